@@ -0,0 +1,377 @@
+// ltimock là một LTI 1.3 platform tối giản để thử tool ở môi trường local:
+// OIDC login, id_token ký bằng key của mock, deep linking và nhận điểm qua AGS.
+//
+//	go run ./cmd/ltimock -tool http://localhost:8080
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const mockKid = "ltimock"
+
+type mockPlatform struct {
+	issuer     string
+	tool       string
+	clientId   string
+	deployment string
+	key        *rsa.PrivateKey
+
+	mu     sync.Mutex
+	tokens map[string]time.Time
+	scores []map[string]interface{}
+}
+
+func main() {
+	addr := flag.String("addr", ":9001", "listen address")
+	issuer := flag.String("issuer", "http://localhost:9001", "platform issuer (public URL of the mock)")
+	tool := flag.String("tool", "http://localhost:8080", "tool base URL (BASE_URL of the API)")
+	clientId := flag.String("client-id", "lms-tool", "client_id assigned to the tool")
+	deployment := flag.String("deployment", "deployment-1", "deployment id")
+	flag.Parse()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mp := &mockPlatform{
+		issuer:     strings.TrimRight(*issuer, "/"),
+		tool:       strings.TrimRight(*tool, "/"),
+		clientId:   *clientId,
+		deployment: *deployment,
+		key:        key,
+		tokens:     make(map[string]time.Time),
+	}
+
+	http.HandleFunc("/", mp.index)
+	http.HandleFunc("/jwks", mp.jwks)
+	http.HandleFunc("/launch", mp.launch)
+	http.HandleFunc("/auth", mp.auth)
+	http.HandleFunc("/token", mp.token)
+	http.HandleFunc("/lineitems/", mp.score)
+	http.HandleFunc("/scores", mp.listScores)
+	http.HandleFunc("/deep-link-return", mp.deepLinkReturn)
+
+	registration, _ := json.MarshalIndent(map[string]interface{}{
+		"name":           "LTI mock platform",
+		"issuer":         mp.issuer,
+		"client_id":      mp.clientId,
+		"deployment_ids": []string{mp.deployment},
+		"auth_login_url": mp.issuer + "/auth",
+		"auth_token_url": mp.issuer + "/token",
+		"jwks_url":       mp.issuer + "/jwks",
+	}, "", "  ")
+	log.Printf("register the mock as an admin: POST %s/api/v1/admin/lti/platforms\n%s", mp.tool, registration)
+	log.Printf("mock platform listening on %s (open %s)", *addr, mp.issuer)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
+
+var indexPage = template.Must(template.New("index").Parse(`<!doctype html>
+<html><body>
+<h1>LTI mock platform</h1>
+<form action="/launch">
+  <h2>Resource link launch (student)</h2>
+  <input type="hidden" name="type" value="resource">
+  User <input name="user" value="student-1">
+  Resource link <input name="resource_link" value="link-1">
+  Course id <input name="course_id" placeholder="empty = use stored link">
+  Lesson id <input name="lesson_id">
+  <button>Launch</button>
+</form>
+<form action="/launch">
+  <h2>Deep linking (instructor)</h2>
+  <input type="hidden" name="type" value="deep-link">
+  User <input name="user" value="instructor-1">
+  <button>Add content</button>
+</form>
+<p><a href="/scores">Received scores</a></p>
+</body></html>`))
+
+var autoSubmitPage = template.Must(template.New("post").Parse(`<!doctype html>
+<html><body onload="document.forms[0].submit()">
+<form method="post" action="{{.Action}}">
+{{range $name, $value := .Fields}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{end}}<noscript><button>Continue</button></noscript>
+</form>
+</body></html>`))
+
+func (mp *mockPlatform) index(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	_ = indexPage.Execute(w, nil)
+}
+
+func (mp *mockPlatform) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": mockKid,
+			"n":   base64.RawURLEncoding.EncodeToString(mp.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(mp.key.E)).Bytes()),
+		}},
+	})
+}
+
+// launch bắt đầu OIDC login initiation tới tool (bước third-party initiated login)
+func (mp *mockPlatform) launch(w http.ResponseWriter, r *http.Request) {
+	target := mp.tool + "/api/v1/lti/launch"
+	if courseId := r.URL.Query().Get("course_id"); courseId != "" {
+		target += "?course_id=" + url.QueryEscape(courseId)
+	}
+
+	query := url.Values{
+		"iss":               {mp.issuer},
+		"login_hint":        {r.URL.Query().Get("user")},
+		"target_link_uri":   {target},
+		"lti_message_hint":  {r.URL.RawQuery},
+		"client_id":         {mp.clientId},
+		"lti_deployment_id": {mp.deployment},
+	}
+	http.Redirect(w, r, mp.tool+"/api/v1/lti/login?"+query.Encode(), http.StatusFound)
+}
+
+// auth là authorization endpoint: ký id_token và form POST về redirect_uri của tool
+func (mp *mockPlatform) auth(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	if r.Form.Get("client_id") != mp.clientId || r.Form.Get("response_type") != "id_token" {
+		http.Error(w, "invalid authentication request", http.StatusBadRequest)
+		return
+	}
+	redirectURI := r.Form.Get("redirect_uri")
+	if !strings.HasPrefix(redirectURI, mp.tool+"/") {
+		http.Error(w, "redirect_uri is not registered", http.StatusBadRequest)
+		return
+	}
+
+	hint, _ := url.ParseQuery(r.Form.Get("lti_message_hint"))
+	user := r.Form.Get("login_hint")
+	if user == "" {
+		user = "student-1"
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   mp.issuer,
+		"aud":   mp.clientId,
+		"sub":   user,
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": r.Form.Get("nonce"),
+		"name":  "Mock " + user,
+		"email": user + "@ltimock.test",
+		"https://purl.imsglobal.org/spec/lti/claim/version":       "1.3.0",
+		"https://purl.imsglobal.org/spec/lti/claim/deployment_id": mp.deployment,
+		"https://purl.imsglobal.org/spec/lti/claim/context":       map[string]string{"id": "course-101", "title": "Mock course 101"},
+		"https://purl.imsglobal.org/spec/lti-ags/claim/endpoint": map[string]interface{}{
+			"scope":     []string{"https://purl.imsglobal.org/spec/lti-ags/scope/lineitem", "https://purl.imsglobal.org/spec/lti-ags/scope/score"},
+			"lineitems": mp.issuer + "/lineitems",
+		},
+	}
+
+	if hint.Get("type") == "deep-link" {
+		claims["https://purl.imsglobal.org/spec/lti/claim/message_type"] = "LtiDeepLinkingRequest"
+		claims["https://purl.imsglobal.org/spec/lti/claim/roles"] = []string{"http://purl.imsglobal.org/vocab/lis/v2/membership#Instructor"}
+		claims["https://purl.imsglobal.org/spec/lti/claim/target_link_uri"] = mp.tool + "/api/v1/lti/launch"
+		claims["https://purl.imsglobal.org/spec/lti-dl/claim/deep_linking_settings"] = map[string]interface{}{
+			"deep_link_return_url": mp.issuer + "/deep-link-return",
+			"accept_types":         []string{"ltiResourceLink"},
+			"accept_multiple":      false,
+			"data":                 "mock-data-" + user,
+		}
+	} else {
+		resourceLink := hint.Get("resource_link")
+		if resourceLink == "" {
+			resourceLink = "link-1"
+		}
+		custom := map[string]string{}
+		for _, name := range []string{"course_id", "lesson_id"} {
+			if value := hint.Get(name); value != "" {
+				custom[name] = value
+			}
+		}
+
+		claims["https://purl.imsglobal.org/spec/lti/claim/message_type"] = "LtiResourceLinkRequest"
+		claims["https://purl.imsglobal.org/spec/lti/claim/roles"] = []string{"http://purl.imsglobal.org/vocab/lis/v2/membership#Learner"}
+		claims["https://purl.imsglobal.org/spec/lti/claim/target_link_uri"] = mp.tool + "/api/v1/lti/launch"
+		claims["https://purl.imsglobal.org/spec/lti/claim/resource_link"] = map[string]string{"id": resourceLink, "title": "Mock activity " + resourceLink}
+		claims["https://purl.imsglobal.org/spec/lti/claim/custom"] = custom
+		claims["https://purl.imsglobal.org/spec/lti-ags/claim/endpoint"].(map[string]interface{})["lineitem"] = mp.issuer + "/lineitems/" + url.PathEscape(resourceLink)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = mockKid
+	idToken, err := token.SignedString(mp.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	_ = autoSubmitPage.Execute(w, map[string]interface{}{
+		"Action": redirectURI,
+		"Fields": map[string]string{"id_token": idToken, "state": r.Form.Get("state")},
+	})
+}
+
+// token cấp access token cho AGS sau khi xác thực client assertion bằng JWKS của tool
+func (mp *mockPlatform) token(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	if r.Form.Get("grant_type") != "client_credentials" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	_, err := jwt.Parse(r.Form.Get("client_assertion"), mp.toolKey,
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(mp.clientId),
+		jwt.WithSubject(mp.clientId),
+		jwt.WithAudience(mp.issuer+"/token"),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		log.Printf("token: invalid client assertion: %v", err)
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	accessToken := randomString()
+	mp.mu.Lock()
+	mp.tokens[accessToken] = time.Now().Add(time.Hour)
+	mp.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"scope":        r.Form.Get("scope"),
+	})
+}
+
+// score nhận POST /lineitems/{id}/scores
+func (mp *mockPlatform) score(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/scores") {
+		http.NotFound(w, r)
+		return
+	}
+
+	accessToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	mp.mu.Lock()
+	expiresAt, ok := mp.tokens[accessToken]
+	mp.mu.Unlock()
+	if !ok || time.Now().After(expiresAt) {
+		http.Error(w, "invalid access token", http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get("Content-Type") != "application/vnd.ims.lis.v1.score+json" {
+		http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var score map[string]interface{}
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&score); err != nil {
+		http.Error(w, "invalid score", http.StatusBadRequest)
+		return
+	}
+	score["lineitem"] = strings.TrimSuffix(r.URL.Path, "/scores")
+
+	mp.mu.Lock()
+	mp.scores = append(mp.scores, score)
+	mp.mu.Unlock()
+
+	log.Printf("score received: %v", score)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (mp *mockPlatform) listScores(w http.ResponseWriter, r *http.Request) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]interface{}{"scores": mp.scores})
+}
+
+// deepLinkReturn xác thực LtiDeepLinkingResponse và hiển thị content item tool trả về
+func (mp *mockPlatform) deepLinkReturn(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(r.Form.Get("JWT"), claims, mp.toolKey,
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(mp.clientId),
+		jwt.WithAudience(mp.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		http.Error(w, "invalid deep linking response: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("deep linking response: %v", claims)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"content_items": claims["https://purl.imsglobal.org/spec/lti-dl/claim/content_items"],
+		"data":          claims["https://purl.imsglobal.org/spec/lti-dl/claim/data"],
+	})
+}
+
+// toolKey tải public key của tool theo kid từ JWKS của tool
+func (mp *mockPlatform) toolKey(token *jwt.Token) (interface{}, error) {
+	resp, err := http.Get(mp.tool + "/api/v1/lti/jwks")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var set struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, err
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	for _, item := range set.Keys {
+		if item.Kid != kid {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(item.N)
+		e, errE := base64.RawURLEncoding.DecodeString(item.E)
+		if errN != nil || errE != nil {
+			return nil, fmt.Errorf("invalid key %q", kid)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
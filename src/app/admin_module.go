@@ -1,29 +1,31 @@
 package app
 
 import (
-	"lms/src/db"
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
 	"lms/src/service"
+	"lms/src/storage"
 )
 
 type AdminModule struct {
 	routes routes.Route
 }
 
-func NewAdminModule() *AdminModule {
+func NewAdminModule(scope *TenantScope) *AdminModule {
 	// Tạo repository để tương tác với database
-	userRepo := repository.NewDBUserRepository(db.DB)
-	courseRepo := repository.NewDBCourseRepository(db.DB)
-	orderRepo := repository.NewDBOrderRepository(db.DB)
-	couponRepo := repository.NewDBCouponRepository(db.DB)
-	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
-	adminAnalyticsRepo := repository.NewDBAdminAnalyticsRepository(db.DB)
+	userRepo := repository.NewDBUserRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	orderRepo := repository.NewDBOrderRepository(scope.DB)
+	couponRepo := repository.NewDBCouponRepository(scope.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(scope.DB)
+	adminAnalyticsRepo := repository.NewDBAdminAnalyticsRepository(scope.DB)
+	approvalRepo := repository.NewDBCourseApprovalRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
 
 	// Tạo service chứa business logic
-	adminService := service.NewAdminService(userRepo, courseRepo)
-	orderService := service.NewOrderService(orderRepo, courseRepo, couponRepo, enrollmentRepo)
+	adminService := service.NewAdminService(userRepo, courseRepo, approvalRepo, storage.Store)
+	orderService := service.NewOrderService(orderRepo, courseRepo, couponRepo, enrollmentRepo, transactor)
 	couponService := service.NewCouponService(couponRepo, courseRepo)
 	adminAnalyticsService := service.NewAdminAnalyticsService(adminAnalyticsRepo)
 
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type AnnouncementModule struct {
+	routes routes.Route
+}
+
+func NewAnnouncementModule(scope *TenantScope) *AnnouncementModule {
+	announcementRepo := repository.NewDBAnnouncementRepository(scope.DB)
+	instructorRepo := repository.NewDBInstructorRepository(scope.DB)
+	lessonRepo := repository.NewDBLessonRepository(scope.DB)
+
+	// Dispatcher gửi theo lịch dùng chung cho mọi tenant
+	announcementService := service.NewAnnouncementService(announcementRepo, instructorRepo, lessonRepo, scope.Platform.AnnouncementDispatcher)
+
+	announcementHandler := handler.NewAnnouncementHandler(announcementService)
+
+	announcementRoutes := routes.NewAnnouncementRoutes(announcementHandler)
+
+	return &AnnouncementModule{routes: announcementRoutes}
+}
+
+func (am *AnnouncementModule) Routes() routes.Route {
+	return am.routes
+}
@@ -4,6 +4,7 @@ import (
 	"lms/src/config"
 	"lms/src/db"
 	"lms/src/routes"
+	"lms/src/storage"
 	"lms/src/validation"
 	"log"
 
@@ -14,6 +15,11 @@ type Module interface {
 	Routes() routes.Route
 }
 
+// WorkerModule là module có background job cần chạy khi khởi động
+type WorkerModule interface {
+	StartWorkers()
+}
+
 type Application struct {
 	config  *config.ServerConfig
 	router  *gin.Engine
@@ -35,32 +41,32 @@ func NewApplication(cfg *config.ServerConfig) *Application {
 		log.Fatal("unable to connect to db")
 	}
 
-	// Tạo Gin router
-	r := gin.Default()
+	// Khởi tạo storage (local hoặc S3-compatible)
+	if err := storage.InitStorage(); err != nil {
+		log.Fatalf("Storage init failed %v", err)
+	}
 
-	// Định nghĩa các module
-	modules := []Module{
-		NewAuthModule(),
-		NewUserModule(),
-		NewAdminModule(),
-		NewCategoryModule(),
-		NewCourseModule(),
-		NewLessonModule(),
-		NewEnrollmentModule(),
-		NewInstructorModule(),
-		NewProgressModule(),
-		NewOrderModule(),
-		NewCouponModule(),
+	// Thành phần dùng chung cho mọi tenant (kết nối gốc, workers)
+	platform := NewPlatform(db.DB)
+
+	// Module cấp platform: dựng một lần, chỉ đăng ký ở tenant mặc định
+	platformModules := []Module{
+		NewEventModule(platform),
+		NewSuperAdminModule(platform),
 	}
 
-	// Đăng ký routes cho tất cả modules
-	routes.RegisterRoutes(r, getModuleRoutes(modules)...)
+	// Router phân request theo tenant (hostname hoặc header X-Tenant)
+	r := NewTenantRouter(platform, platformModules)
+
+	// Khởi động background workers
+	platform.StartWorkers()
+	startModuleWorkers(platformModules)
 
 	// Trả về Application instance
 	return &Application{
 		config:  cfg,
 		router:  r,
-		modules: modules,
+		modules: platformModules,
 	}
 }
 
@@ -68,6 +74,48 @@ func (a *Application) Run() error { // a chính là &Application{config: cfg, ro
 	return a.router.Run(a.config.ServerAddress) // Hàm Run này là của Gin
 }
 
+// newTenantModules định nghĩa các module của một tenant; repository của chúng chỉ thấy dữ liệu của tenant đó
+func newTenantModules(scope *TenantScope) []Module {
+	modules := []Module{
+		NewAuthModule(scope),
+		NewUserModule(scope),
+		NewAdminModule(scope),
+		NewCategoryModule(scope),
+		NewCourseModule(scope),
+		NewLessonModule(scope),
+		NewEnrollmentModule(scope),
+		NewInstructorModule(scope),
+		NewProgressModule(scope),
+		NewOrderModule(scope),
+		NewCouponModule(scope),
+		NewQuizModule(scope),
+		NewLearningPathModule(scope),
+		NewVideoModule(scope),
+		NewStorageModule(),
+		NewAttachmentModule(scope),
+		NewSubtitleModule(scope),
+		NewDiscussionModule(scope),
+		NewNoteModule(scope),
+		NewAnnouncementModule(scope),
+		NewNotificationModule(scope),
+		NewReviewModule(scope),
+		NewCourseApprovalModule(scope),
+		NewCourseRevisionModule(scope),
+		NewCourseTemplateModule(scope),
+		NewCoursePackageModule(scope),
+		NewScormModule(scope),
+		NewOrganizationModule(scope),
+		NewSubscriptionModule(scope),
+		NewBundleModule(scope),
+		NewTenantModule(scope),
+		NewLtiModule(scope),
+		NewXapiModule(scope),
+		NewWebhookModule(scope),
+	}
+
+	return modules
+}
+
 func getModuleRoutes(modules []Module) []routes.Route {
 	routeList := make([]routes.Route, len(modules))
 	for i, module := range modules {
@@ -76,3 +124,11 @@ func getModuleRoutes(modules []Module) []routes.Route {
 
 	return routeList
 }
+
+func startModuleWorkers(modules []Module) {
+	for _, module := range modules {
+		if worker, ok := module.(WorkerModule); ok {
+			worker.StartWorkers()
+		}
+	}
+}
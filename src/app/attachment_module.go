@@ -0,0 +1,31 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+	"lms/src/storage"
+)
+
+type AttachmentModule struct {
+	routes routes.Route
+}
+
+func NewAttachmentModule(scope *TenantScope) *AttachmentModule {
+	attachmentRepo := repository.NewDBAttachmentRepository(scope.DB)
+	instructorRepo := repository.NewDBInstructorRepository(scope.DB)
+	lessonRepo := repository.NewDBLessonRepository(scope.DB)
+
+	attachmentService := service.NewAttachmentService(attachmentRepo, instructorRepo, lessonRepo, storage.Store)
+
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService)
+
+	attachmentRoutes := routes.NewAttachmentRoutes(attachmentHandler)
+
+	return &AttachmentModule{routes: attachmentRoutes}
+}
+
+func (am *AttachmentModule) Routes() routes.Route {
+	return am.routes
+}
@@ -1,7 +1,6 @@
 package app
 
 import (
-	"lms/src/db"
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
@@ -12,14 +11,14 @@ type AuthModule struct {
 	routes routes.Route
 }
 
-func NewAuthModule() *AuthModule {
+func NewAuthModule(scope *TenantScope) *AuthModule {
 	// Tạo repository để tương tác với database
-	userRepo := repository.NewDBUserRepository(db.DB)
-	passwordResetRepo := repository.NewDBPasswordResetRepository(db.DB)
+	userRepo := repository.NewDBUserRepository(scope.DB)
+	passwordResetRepo := repository.NewDBPasswordResetRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
 
 	// Tạo service chứa business logic
-	emailService := service.NewEmailService()
-	authService := service.NewAuthService(userRepo, passwordResetRepo, emailService)
+	authService := service.NewAuthService(userRepo, passwordResetRepo, scope.Platform.EmailService, transactor)
 
 	// Tạo handler xử lý HTTP requests
 	authHandler := handler.NewAuthHandler(authService)
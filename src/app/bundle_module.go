@@ -0,0 +1,31 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type BundleModule struct {
+	routes routes.Route
+}
+
+func NewBundleModule(scope *TenantScope) *BundleModule {
+	bundleRepo := repository.NewDBBundleRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
+
+	bundleService := service.NewBundleService(bundleRepo, courseRepo, enrollmentRepo, transactor, scope.Platform.PaymentGateway)
+
+	bundleHandler := handler.NewBundleHandler(bundleService)
+
+	bundleRoutes := routes.NewBundleRoutes(bundleHandler)
+
+	return &BundleModule{routes: bundleRoutes}
+}
+
+func (bm *BundleModule) Routes() routes.Route {
+	return bm.routes
+}
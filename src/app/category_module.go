@@ -1,7 +1,6 @@
 package app
 
 import (
-	"lms/src/db"
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
@@ -12,8 +11,8 @@ type CategoryModule struct {
 	routes routes.Route
 }
 
-func NewCategoryModule() *CategoryModule {
-	categoryRepo := repository.NewDBCategoryRepository(db.DB)
+func NewCategoryModule(scope *TenantScope) *CategoryModule {
+	categoryRepo := repository.NewDBCategoryRepository(scope.DB)
 
 	categoryService := service.NewCategoryService(categoryRepo)
 
@@ -1,7 +1,6 @@
 package app
 
 import (
-	"lms/src/db"
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
@@ -12,9 +11,9 @@ type CouponModule struct {
 	handler *handler.CouponHandler
 }
 
-func NewCouponModule() *CouponModule {
-	couponRepo := repository.NewDBCouponRepository(db.DB)
-	courseRepo := repository.NewDBCourseRepository(db.DB)
+func NewCouponModule(scope *TenantScope) *CouponModule {
+	couponRepo := repository.NewDBCouponRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
 	couponService := service.NewCouponService(couponRepo, courseRepo)
 	couponHandler := handler.NewCouponHandler(couponService)
 
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type CourseApprovalModule struct {
+	routes routes.Route
+}
+
+func NewCourseApprovalModule(scope *TenantScope) *CourseApprovalModule {
+	approvalRepo := repository.NewDBCourseApprovalRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	notificationRepo := repository.NewDBNotificationRepository(scope.DB)
+
+	notifier := service.NewNotifier(notificationRepo)
+	approvalService := service.NewCourseApprovalService(approvalRepo, courseRepo, notifier)
+
+	approvalHandler := handler.NewCourseApprovalHandler(approvalService)
+
+	approvalRoutes := routes.NewCourseApprovalRoutes(approvalHandler)
+
+	return &CourseApprovalModule{routes: approvalRoutes}
+}
+
+func (cm *CourseApprovalModule) Routes() routes.Route {
+	return cm.routes
+}
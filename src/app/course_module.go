@@ -1,24 +1,27 @@
 package app
 
 import (
-	"lms/src/db"
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
 	"lms/src/service"
+	"lms/src/utils"
 )
 
 type CourseModule struct {
 	routes routes.Route
 }
 
-func NewCourseModule() *CourseModule {
-	courseRepo := repository.NewDBCourseRepository(db.DB)
-	reviewRepo := repository.NewDBReviewRepository(db.DB)
-	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
+func NewCourseModule(scope *TenantScope) *CourseModule {
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	reviewRepo := repository.NewDBReviewRepository(scope.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(scope.DB)
+	learningPathRepo := repository.NewDBLearningPathRepository(scope.DB)
+	bundleRepo := repository.NewDBBundleRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
 
-	courseService := service.NewCourseService(courseRepo)
-	reviewService := service.NewReviewService(reviewRepo, courseRepo, enrollmentRepo)
+	courseService := service.NewCourseService(courseRepo, learningPathRepo, bundleRepo)
+	reviewService := service.NewReviewService(reviewRepo, courseRepo, enrollmentRepo, transactor, utils.NewContentFilter())
 
 	courseHandler := handler.NewCourseHandler(courseService, reviewService)
 
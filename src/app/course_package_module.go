@@ -0,0 +1,32 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+	"lms/src/storage"
+)
+
+type CoursePackageModule struct {
+	routes routes.Route
+}
+
+func NewCoursePackageModule(scope *TenantScope) *CoursePackageModule {
+	templateRepo := repository.NewDBCourseTemplateRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	categoryRepo := repository.NewDBCategoryRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
+
+	packageService := service.NewCoursePackageService(templateRepo, courseRepo, categoryRepo, transactor, storage.Store)
+
+	packageHandler := handler.NewCoursePackageHandler(packageService)
+
+	packageRoutes := routes.NewCoursePackageRoutes(packageHandler)
+
+	return &CoursePackageModule{routes: packageRoutes}
+}
+
+func (pm *CoursePackageModule) Routes() routes.Route {
+	return pm.routes
+}
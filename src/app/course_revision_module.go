@@ -0,0 +1,35 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+	"lms/src/storage"
+)
+
+type CourseRevisionModule struct {
+	routes routes.Route
+}
+
+func NewCourseRevisionModule(scope *TenantScope) *CourseRevisionModule {
+	revisionRepo := repository.NewDBCourseRevisionRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	categoryRepo := repository.NewDBCategoryRepository(scope.DB)
+	instructorRepo := repository.NewDBInstructorRepository(scope.DB)
+	notificationRepo := repository.NewDBNotificationRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
+
+	notifier := service.NewNotifier(notificationRepo)
+	revisionService := service.NewCourseRevisionService(revisionRepo, courseRepo, categoryRepo, instructorRepo, transactor, notifier, storage.Store)
+
+	revisionHandler := handler.NewCourseRevisionHandler(revisionService)
+
+	revisionRoutes := routes.NewCourseRevisionRoutes(revisionHandler)
+
+	return &CourseRevisionModule{routes: revisionRoutes}
+}
+
+func (cm *CourseRevisionModule) Routes() routes.Route {
+	return cm.routes
+}
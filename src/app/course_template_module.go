@@ -0,0 +1,32 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+	"lms/src/storage"
+)
+
+type CourseTemplateModule struct {
+	routes routes.Route
+}
+
+func NewCourseTemplateModule(scope *TenantScope) *CourseTemplateModule {
+	templateRepo := repository.NewDBCourseTemplateRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	instructorRepo := repository.NewDBInstructorRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
+
+	templateService := service.NewCourseTemplateService(templateRepo, courseRepo, instructorRepo, transactor, storage.Store)
+
+	templateHandler := handler.NewCourseTemplateHandler(templateService)
+
+	templateRoutes := routes.NewCourseTemplateRoutes(templateHandler)
+
+	return &CourseTemplateModule{routes: templateRoutes}
+}
+
+func (tm *CourseTemplateModule) Routes() routes.Route {
+	return tm.routes
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type DiscussionModule struct {
+	routes routes.Route
+}
+
+func NewDiscussionModule(scope *TenantScope) *DiscussionModule {
+	discussionRepo := repository.NewDBDiscussionRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	lessonRepo := repository.NewDBLessonRepository(scope.DB)
+	userRepo := repository.NewDBUserRepository(scope.DB)
+	notificationRepo := repository.NewDBNotificationRepository(scope.DB)
+
+	notifier := service.NewNotifier(notificationRepo)
+	discussionService := service.NewDiscussionService(discussionRepo, courseRepo, lessonRepo, userRepo, notifier)
+
+	discussionHandler := handler.NewDiscussionHandler(discussionService)
+
+	discussionRoutes := routes.NewDiscussionRoutes(discussionHandler)
+
+	return &DiscussionModule{routes: discussionRoutes}
+}
+
+func (dm *DiscussionModule) Routes() routes.Route {
+	return dm.routes
+}
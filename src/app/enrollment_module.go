@@ -1,7 +1,6 @@
 package app
 
 import (
-	"lms/src/db"
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
@@ -12,14 +11,16 @@ type EnrollmentModule struct {
 	routes routes.Route
 }
 
-func NewEnrollmentModule() *EnrollmentModule {
-	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
-	orderRepo := repository.NewDBOrderRepository(db.DB)
-	courseRepo := repository.NewDBCourseRepository(db.DB)
-	couponRepo := repository.NewDBCouponRepository(db.DB)
-	progressRepo := repository.NewDBProgressRepository(db.DB)
+func NewEnrollmentModule(scope *TenantScope) *EnrollmentModule {
+	enrollmentRepo := repository.NewDBEnrollmentRepository(scope.DB)
+	orderRepo := repository.NewDBOrderRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	couponRepo := repository.NewDBCouponRepository(scope.DB)
+	progressRepo := repository.NewDBProgressRepository(scope.DB)
+	subscriptionRepo := repository.NewDBSubscriptionRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
 
-	enrollmentService := service.NewEnrollmentService(enrollmentRepo, orderRepo, courseRepo, couponRepo, progressRepo)
+	enrollmentService := service.NewEnrollmentService(enrollmentRepo, orderRepo, courseRepo, couponRepo, progressRepo, subscriptionRepo, transactor)
 
 	enrollmentHandler := handler.NewEnrollmentHandler(enrollmentService)
 
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"lms/src/config"
+	"lms/src/db"
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type EventModule struct {
+	routes     routes.Route
+	dispatcher *service.EventDispatcher
+}
+
+func NewEventModule(platform *Platform) *EventModule {
+	outboxRepo := repository.NewDBOutboxRepository(platform.DB)
+	courseRepo := repository.NewDBCourseRepository(platform.DB)
+	couponRepo := repository.NewDBCouponRepository(platform.DB)
+	notificationRepo := repository.NewDBNotificationRepository(platform.DB)
+	webhookRepo := repository.NewDBWebhookRepository(platform.DB)
+	userRepo := repository.NewDBUserRepository(platform.DB)
+	lessonRepo := repository.NewDBLessonRepository(platform.DB)
+	quizRepo := repository.NewDBQuizRepository(platform.DB)
+	ltiRepo := repository.NewDBLtiRepository(platform.DB)
+	progressRepo := repository.NewDBProgressRepository(platform.DB)
+
+	// Dispatcher và các subscriber mặc định của domain event
+	dispatcher := service.NewEventDispatcher(outboxRepo, config.NewDBConfig().DNS())
+	notifier := service.NewNotifier(notificationRepo)
+	service.RegisterEventSubscribers(dispatcher, courseRepo, couponRepo, notifier, platform.EmailService)
+	service.RegisterWebhookSubscribers(dispatcher, webhookRepo)
+	// LRS tích hợp tách theo tenant: statement lưu vào tenant của event
+	xapiServiceFor := func(tenantId uint) service.XapiService {
+		return service.NewXapiService(repository.NewDBXapiRepository(db.ForTenant(tenantId)))
+	}
+	service.RegisterXapiSubscribers(dispatcher, xapiServiceFor, userRepo, courseRepo, lessonRepo, quizRepo)
+	service.RegisterLtiSubscribers(dispatcher, ltiRepo, progressRepo, lessonRepo, quizRepo)
+
+	eventService := service.NewEventService(outboxRepo)
+
+	eventHandler := handler.NewEventHandler(eventService)
+
+	eventRoutes := routes.NewEventRoutes(eventHandler)
+
+	return &EventModule{routes: eventRoutes, dispatcher: dispatcher}
+}
+
+func (em *EventModule) Routes() routes.Route {
+	return em.routes
+}
+
+// StartWorkers chạy dispatcher gửi domain event từ outbox tới các subscriber
+func (em *EventModule) StartWorkers() {
+	em.dispatcher.StartWorkers()
+}
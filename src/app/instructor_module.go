@@ -1,23 +1,24 @@
 package app
 
 import (
-	"lms/src/db"
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
 	"lms/src/service"
+	"lms/src/storage"
 )
 
 type InstructorModule struct {
 	routes routes.Route
 }
 
-func NewInstructorModule() *InstructorModule {
-	instructorRepo := repository.NewDBInstructorRepository(db.DB)
-	categoryRepo := repository.NewDBCategoryRepository(db.DB)
-	analyticsRepo := repository.NewDBAnalyticsRepository(db.DB)
+func NewInstructorModule(scope *TenantScope) *InstructorModule {
+	instructorRepo := repository.NewDBInstructorRepository(scope.DB)
+	categoryRepo := repository.NewDBCategoryRepository(scope.DB)
+	analyticsRepo := repository.NewDBAnalyticsRepository(scope.DB)
+	approvalRepo := repository.NewDBCourseApprovalRepository(scope.DB)
 
-	instructorService := service.NewInstructorService(instructorRepo, categoryRepo)
+	instructorService := service.NewInstructorService(instructorRepo, categoryRepo, approvalRepo, storage.Store)
 	analyticsService := service.NewAnalyticsService(analyticsRepo)
 
 	instructorHandler := handler.NewInstructorHandler(instructorService)
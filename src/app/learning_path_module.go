@@ -0,0 +1,32 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type LearningPathModule struct {
+	routes routes.Route
+}
+
+func NewLearningPathModule(scope *TenantScope) *LearningPathModule {
+	learningPathRepo := repository.NewDBLearningPathRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(scope.DB)
+	notificationRepo := repository.NewDBNotificationRepository(scope.DB)
+
+	notifier := service.NewNotifier(notificationRepo)
+	learningPathService := service.NewLearningPathService(learningPathRepo, courseRepo, enrollmentRepo, notifier)
+
+	learningPathHandler := handler.NewLearningPathHandler(learningPathService)
+
+	learningPathRoutes := routes.NewLearningPathRoutes(learningPathHandler)
+
+	return &LearningPathModule{routes: learningPathRoutes}
+}
+
+func (lpm *LearningPathModule) Routes() routes.Route {
+	return lpm.routes
+}
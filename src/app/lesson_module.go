@@ -1,22 +1,25 @@
 package app
 
 import (
-	"lms/src/db"
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
 	"lms/src/service"
+	"lms/src/storage"
 )
 
 type LessonModule struct {
 	routes routes.Route
 }
 
-func NewLessonModule() *LessonModule {
-	lessonRepo := repository.NewDBLessonRepository(db.DB)
-	courseRepo := repository.NewDBCourseRepository(db.DB)
+func NewLessonModule(scope *TenantScope) *LessonModule {
+	lessonRepo := repository.NewDBLessonRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	attachmentRepo := repository.NewDBAttachmentRepository(scope.DB)
+	subtitleRepo := repository.NewDBSubtitleRepository(scope.DB)
+	noteRepo := repository.NewDBNoteRepository(scope.DB)
 
-	lessonService := service.NewLessonService(lessonRepo, courseRepo)
+	lessonService := service.NewLessonService(lessonRepo, courseRepo, attachmentRepo, subtitleRepo, noteRepo, storage.Store)
 
 	lessonHandler := handler.NewLessonHandler(lessonService)
 
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type LtiModule struct {
+	routes routes.Route
+}
+
+func NewLtiModule(scope *TenantScope) *LtiModule {
+	ltiRepo := repository.NewDBLtiRepository(scope.DB)
+	userRepo := repository.NewDBUserRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	lessonRepo := repository.NewDBLessonRepository(scope.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
+
+	ltiService := service.NewLtiService(ltiRepo, userRepo, courseRepo, lessonRepo, enrollmentRepo, transactor, scope.BaseURL(), scope.FrontendURL())
+
+	ltiHandler := handler.NewLtiHandler(ltiService)
+
+	ltiRoutes := routes.NewLtiRoutes(ltiHandler)
+
+	return &LtiModule{routes: ltiRoutes}
+}
+
+func (lm *LtiModule) Routes() routes.Route {
+	return lm.routes
+}
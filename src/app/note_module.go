@@ -0,0 +1,30 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type NoteModule struct {
+	routes routes.Route
+}
+
+func NewNoteModule(scope *TenantScope) *NoteModule {
+	noteRepo := repository.NewDBNoteRepository(scope.DB)
+	lessonRepo := repository.NewDBLessonRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+
+	noteService := service.NewNoteService(noteRepo, lessonRepo, courseRepo)
+
+	noteHandler := handler.NewNoteHandler(noteService)
+
+	noteRoutes := routes.NewNoteRoutes(noteHandler)
+
+	return &NoteModule{routes: noteRoutes}
+}
+
+func (nm *NoteModule) Routes() routes.Route {
+	return nm.routes
+}
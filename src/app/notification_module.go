@@ -0,0 +1,29 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type NotificationModule struct {
+	routes routes.Route
+}
+
+func NewNotificationModule(scope *TenantScope) *NotificationModule {
+	notificationRepo := repository.NewDBNotificationRepository(scope.DB)
+
+	// Hub (listener + SSE) dùng chung cho mọi tenant
+	notificationService := service.NewNotificationService(notificationRepo, scope.Platform.NotificationHub)
+
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+
+	notificationRoutes := routes.NewNotificationRoutes(notificationHandler)
+
+	return &NotificationModule{routes: notificationRoutes}
+}
+
+func (nm *NotificationModule) Routes() routes.Route {
+	return nm.routes
+}
@@ -1,7 +1,6 @@
 package app
 
 import (
-	"lms/src/db"
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
@@ -12,13 +11,14 @@ type OrderModule struct {
 	routes routes.Route
 }
 
-func NewOrderModule() *OrderModule {
-	orderRepo := repository.NewDBOrderRepository(db.DB)
-	courseRepo := repository.NewDBCourseRepository(db.DB)
-	couponRepo := repository.NewDBCouponRepository(db.DB)
-	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
+func NewOrderModule(scope *TenantScope) *OrderModule {
+	orderRepo := repository.NewDBOrderRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	couponRepo := repository.NewDBCouponRepository(scope.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
 
-	orderService := service.NewOrderService(orderRepo, courseRepo, couponRepo, enrollmentRepo)
+	orderService := service.NewOrderService(orderRepo, courseRepo, couponRepo, enrollmentRepo, transactor)
 	couponService := service.NewCouponService(couponRepo, courseRepo)
 
 	orderHandler := handler.NewOrderHandler(orderService, couponService)
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type OrganizationModule struct {
+	routes routes.Route
+}
+
+func NewOrganizationModule(scope *TenantScope) *OrganizationModule {
+	organizationRepo := repository.NewDBOrganizationRepository(scope.DB)
+	userRepo := repository.NewDBUserRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	lessonRepo := repository.NewDBLessonRepository(scope.DB)
+	progressRepo := repository.NewDBProgressRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
+
+	organizationService := service.NewOrganizationService(organizationRepo, userRepo, courseRepo, lessonRepo, progressRepo, transactor)
+
+	organizationHandler := handler.NewOrganizationHandler(organizationService)
+
+	organizationRoutes := routes.NewOrganizationRoutes(organizationHandler)
+
+	return &OrganizationModule{routes: organizationRoutes}
+}
+
+func (om *OrganizationModule) Routes() routes.Route {
+	return om.routes
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"lms/src/config"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/service"
+	"lms/src/storage"
+	"lms/src/utils"
+	"net/url"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Platform chứa các thành phần dùng chung cho mọi tenant: chạy trên kết nối gốc và chỉ khởi động một lần
+type Platform struct {
+	DB                     *gorm.DB
+	TenantResolver         *service.TenantResolver
+	EmailService           service.EmailService
+	NotificationHub        *service.NotificationHub
+	VideoTranscoder        *service.VideoTranscoder
+	AnnouncementDispatcher *service.AnnouncementDispatcher
+	PaymentGateway         service.PaymentGateway
+	SubscriptionBiller     *service.SubscriptionBiller
+	WebhookDispatcher      *service.WebhookDispatcher
+}
+
+func NewPlatform(database *gorm.DB) *Platform {
+	tenantResolver := service.NewTenantResolver(repository.NewDBTenantRepository(database))
+	emailService := service.NewEmailService(tenantResolver)
+	paymentGateway := service.NewSimulatedPaymentGateway()
+
+	return &Platform{
+		DB:                     database,
+		TenantResolver:         tenantResolver,
+		EmailService:           emailService,
+		NotificationHub:        service.NewNotificationHub(repository.NewDBNotificationRepository(database), config.NewDBConfig().DNS()),
+		VideoTranscoder:        service.NewVideoTranscoder(repository.NewDBVideoRepository(database), storage.Store),
+		AnnouncementDispatcher: service.NewAnnouncementDispatcher(repository.NewDBAnnouncementRepository(database), emailService),
+		PaymentGateway:         paymentGateway,
+		SubscriptionBiller:     service.NewSubscriptionBiller(repository.NewDBSubscriptionRepository(database), repository.NewDBTransactor(database), paymentGateway),
+		WebhookDispatcher:      service.NewWebhookDispatcher(repository.NewDBWebhookRepository(database), config.NewDBConfig().DNS()),
+	}
+}
+
+// StartWorkers chạy các background job dùng chung (mỗi job xử lý dữ liệu của mọi tenant)
+func (p *Platform) StartWorkers() {
+	p.NotificationHub.StartWorkers()
+	p.VideoTranscoder.StartWorkers()
+	p.AnnouncementDispatcher.StartWorkers()
+	p.SubscriptionBiller.StartWorkers()
+	p.WebhookDispatcher.StartWorkers()
+}
+
+// TenantScope là những gì module cần để dựng route cho một tenant
+type TenantScope struct {
+	DB       *gorm.DB // Kết nối chỉ thấy dữ liệu của tenant (xem db.ForTenant)
+	Tenant   *models.Tenant
+	Platform *Platform
+}
+
+// IsDefault cho biết đây là tenant mặc định, nơi đăng ký các route cấp platform
+func (ts *TenantScope) IsDefault() bool {
+	return ts.Tenant.Id == models.DefaultTenantId
+}
+
+// BaseURL là URL gốc của API cho tenant (dùng cho URL gửi ra hệ thống bên ngoài như LTI tool config):
+// tenant mặc định dùng BASE_URL, tenant khác dùng domain đầu tiên của nó với cùng scheme
+func (ts *TenantScope) BaseURL() string {
+	baseURL := strings.TrimRight(utils.GetEnv("BASE_URL", "http://localhost:8080"), "/")
+	if ts.IsDefault() || len(ts.Tenant.Domains) == 0 {
+		return baseURL
+	}
+
+	scheme := "https"
+	if u, err := url.Parse(baseURL); err == nil && u.Scheme != "" {
+		scheme = u.Scheme
+	}
+	return scheme + "://" + ts.Tenant.Domains[0].Hostname
+}
+
+// FrontendURL là URL frontend của tenant (rỗng thì dùng FRONTEND_URL)
+func (ts *TenantScope) FrontendURL() string {
+	if ts.Tenant.FrontendURL != "" {
+		return strings.TrimRight(ts.Tenant.FrontendURL, "/")
+	}
+	return strings.TrimRight(utils.GetEnv("FRONTEND_URL", "http://localhost:3000"), "/")
+}
@@ -1,7 +1,6 @@
 package app
 
 import (
-	"lms/src/db"
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
@@ -12,13 +11,14 @@ type ProgressModule struct {
 	routes routes.Route
 }
 
-func NewProgressModule() *ProgressModule {
-	progressRepo := repository.NewDBProgressRepository(db.DB)
-	enrollmentRepo := repository.NewDBEnrollmentRepository(db.DB)
-	courseRepo := repository.NewDBCourseRepository(db.DB)
-	lessonRepo := repository.NewDBLessonRepository(db.DB)
+func NewProgressModule(scope *TenantScope) *ProgressModule {
+	progressRepo := repository.NewDBProgressRepository(scope.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	lessonRepo := repository.NewDBLessonRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
 
-	progressService := service.NewProgressService(progressRepo, enrollmentRepo, courseRepo, lessonRepo)
+	progressService := service.NewProgressService(progressRepo, enrollmentRepo, courseRepo, lessonRepo, transactor)
 	progressHandler := handler.NewProgressHandler(progressService)
 	progressRoutes := routes.NewProgressRoutes(progressHandler)
 
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type QuizModule struct {
+	routes routes.Route
+}
+
+func NewQuizModule(scope *TenantScope) *QuizModule {
+	quizRepo := repository.NewDBQuizRepository(scope.DB)
+	instructorRepo := repository.NewDBInstructorRepository(scope.DB)
+	lessonRepo := repository.NewDBLessonRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
+
+	quizService := service.NewQuizService(quizRepo, instructorRepo, lessonRepo, transactor)
+
+	quizHandler := handler.NewQuizHandler(quizService)
+
+	quizRoutes := routes.NewQuizRoutes(quizHandler)
+
+	return &QuizModule{routes: quizRoutes}
+}
+
+func (qm *QuizModule) Routes() routes.Route {
+	return qm.routes
+}
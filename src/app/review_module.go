@@ -0,0 +1,32 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+	"lms/src/utils"
+)
+
+type ReviewModule struct {
+	routes routes.Route
+}
+
+func NewReviewModule(scope *TenantScope) *ReviewModule {
+	reviewRepo := repository.NewDBReviewRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
+
+	reviewService := service.NewReviewService(reviewRepo, courseRepo, enrollmentRepo, transactor, utils.NewContentFilter())
+
+	reviewHandler := handler.NewReviewHandler(reviewService)
+
+	reviewRoutes := routes.NewReviewRoutes(reviewHandler)
+
+	return &ReviewModule{routes: reviewRoutes}
+}
+
+func (rm *ReviewModule) Routes() routes.Route {
+	return rm.routes
+}
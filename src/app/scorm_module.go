@@ -0,0 +1,38 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+	"lms/src/storage"
+)
+
+type ScormModule struct {
+	routes routes.Route
+}
+
+func NewScormModule(scope *TenantScope) *ScormModule {
+	scormRepo := repository.NewDBScormRepository(scope.DB)
+	instructorRepo := repository.NewDBInstructorRepository(scope.DB)
+	lessonRepo := repository.NewDBLessonRepository(scope.DB)
+	userRepo := repository.NewDBUserRepository(scope.DB)
+	progressRepo := repository.NewDBProgressRepository(scope.DB)
+	enrollmentRepo := repository.NewDBEnrollmentRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
+
+	// Hoàn thành lesson SCORM đi qua ProgressService như lesson thường
+	progressService := service.NewProgressService(progressRepo, enrollmentRepo, courseRepo, lessonRepo, transactor)
+	scormService := service.NewScormService(scormRepo, instructorRepo, lessonRepo, userRepo, progressService, storage.Store)
+
+	scormHandler := handler.NewScormHandler(scormService)
+
+	scormRoutes := routes.NewScormRoutes(scormHandler)
+
+	return &ScormModule{routes: scormRoutes}
+}
+
+func (sm *ScormModule) Routes() routes.Route {
+	return sm.routes
+}
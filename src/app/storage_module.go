@@ -0,0 +1,26 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/routes"
+	"lms/src/service"
+	"lms/src/storage"
+)
+
+type StorageModule struct {
+	routes routes.Route
+}
+
+func NewStorageModule() *StorageModule {
+	storageService := service.NewStorageService(storage.Store)
+
+	storageHandler := handler.NewStorageHandler(storageService)
+
+	storageRoutes := routes.NewStorageRoutes(storageHandler)
+
+	return &StorageModule{routes: storageRoutes}
+}
+
+func (sm *StorageModule) Routes() routes.Route {
+	return sm.routes
+}
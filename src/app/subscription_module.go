@@ -0,0 +1,30 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type SubscriptionModule struct {
+	routes routes.Route
+}
+
+func NewSubscriptionModule(scope *TenantScope) *SubscriptionModule {
+	subscriptionRepo := repository.NewDBSubscriptionRepository(scope.DB)
+	courseRepo := repository.NewDBCourseRepository(scope.DB)
+	transactor := repository.NewDBTransactor(scope.DB)
+
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo, courseRepo, transactor, scope.Platform.PaymentGateway)
+
+	subscriptionHandler := handler.NewSubscriptionHandler(subscriptionService)
+
+	subscriptionRoutes := routes.NewSubscriptionRoutes(subscriptionHandler)
+
+	return &SubscriptionModule{routes: subscriptionRoutes}
+}
+
+func (sm *SubscriptionModule) Routes() routes.Route {
+	return sm.routes
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+	"lms/src/storage"
+)
+
+type SubtitleModule struct {
+	routes routes.Route
+}
+
+func NewSubtitleModule(scope *TenantScope) *SubtitleModule {
+	subtitleRepo := repository.NewDBSubtitleRepository(scope.DB)
+	instructorRepo := repository.NewDBInstructorRepository(scope.DB)
+
+	subtitleService := service.NewSubtitleService(subtitleRepo, instructorRepo, storage.Store)
+
+	subtitleHandler := handler.NewSubtitleHandler(subtitleService)
+
+	subtitleRoutes := routes.NewSubtitleRoutes(subtitleHandler)
+
+	return &SubtitleModule{routes: subtitleRoutes}
+}
+
+func (sm *SubtitleModule) Routes() routes.Route {
+	return sm.routes
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type TenantModule struct {
+	routes routes.Route
+}
+
+// NewTenantModule dùng kết nối gốc vì bảng tenants không thuộc tenant nào
+func NewTenantModule(scope *TenantScope) *TenantModule {
+	tenantRepo := repository.NewDBTenantRepository(scope.Platform.DB)
+
+	tenantService := service.NewTenantService(tenantRepo, scope.Platform.TenantResolver)
+
+	tenantHandler := handler.NewTenantHandler(tenantService)
+
+	tenantRoutes := routes.NewTenantRoutes(tenantHandler)
+
+	return &TenantModule{routes: tenantRoutes}
+}
+
+func (tm *TenantModule) Routes() routes.Route {
+	return tm.routes
+}
+
+type SuperAdminModule struct {
+	routes routes.Route
+}
+
+func NewSuperAdminModule(platform *Platform) *SuperAdminModule {
+	tenantRepo := repository.NewDBTenantRepository(platform.DB)
+
+	tenantService := service.NewTenantService(tenantRepo, platform.TenantResolver)
+
+	tenantHandler := handler.NewTenantHandler(tenantService)
+
+	superAdminRoutes := routes.NewSuperAdminRoutes(tenantHandler)
+
+	return &SuperAdminModule{routes: superAdminRoutes}
+}
+
+func (sm *SuperAdminModule) Routes() routes.Route {
+	return sm.routes
+}
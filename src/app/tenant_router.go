@@ -0,0 +1,77 @@
+package app
+
+import (
+	"lms/src/db"
+	"lms/src/middleware"
+	"lms/src/models"
+	"lms/src/routes"
+	"lms/src/utils"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TenantRouter xác định tenant của request rồi chuyển cho router riêng của tenant đó.
+// Router của mỗi tenant được dựng lần đầu có request, với repository gắn kết nối đã giới hạn theo tenant.
+type TenantRouter struct {
+	platform        *Platform
+	platformModules []Module // Route cấp platform, chỉ đăng ký ở tenant mặc định
+	mu              sync.Mutex
+	engines         map[uint]*gin.Engine
+}
+
+func NewTenantRouter(platform *Platform, platformModules []Module) *gin.Engine {
+	tr := &TenantRouter{
+		platform:        platform,
+		platformModules: platformModules,
+		engines:         make(map[uint]*gin.Engine),
+	}
+
+	// Dựng sẵn router của tenant mặc định để lỗi đăng ký route lộ ra ngay khi khởi động
+	if tenant, err := platform.TenantResolver.Get(models.DefaultTenantId); err == nil {
+		tr.engineFor(tenant)
+	}
+
+	// Engine ngoài chỉ log và phân tenant, mọi request rơi vào NoRoute.
+	// Access log ẩn ?access_token= của route stream.
+	r := gin.New()
+	r.Use(gin.LoggerWithFormatter(middleware.AccessLogFormatter), gin.Recovery())
+	r.NoRoute(tr.dispatch)
+	return r
+}
+
+func (tr *TenantRouter) dispatch(ctx *gin.Context) {
+	tenant, err := tr.platform.TenantResolver.Resolve(ctx.GetHeader("X-Tenant"), ctx.Request.Host)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	tr.engineFor(tenant).ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+func (tr *TenantRouter) engineFor(tenant *models.Tenant) *gin.Engine {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if engine, ok := tr.engines[tenant.Id]; ok {
+		return engine
+	}
+
+	scope := &TenantScope{
+		DB:       db.ForTenant(tenant.Id),
+		Tenant:   tenant,
+		Platform: tr.platform,
+	}
+	modules := newTenantModules(scope)
+	if scope.IsDefault() {
+		modules = append(modules, tr.platformModules...)
+	}
+
+	engine := gin.New()
+	engine.Use(gin.Recovery(), middleware.TenantMiddleware(tenant.Id))
+	routes.RegisterRoutes(engine, getModuleRoutes(modules)...)
+
+	tr.engines[tenant.Id] = engine
+	return engine
+}
@@ -1,21 +1,21 @@
 package app
 
 import (
-	"lms/src/db"
 	"lms/src/handler"
 	"lms/src/repository"
 	"lms/src/routes"
 	"lms/src/service"
+	"lms/src/storage"
 )
 
 type UserModule struct {
 	routes routes.Route
 }
 
-func NewUserModule() *UserModule {
-	userRepo := repository.NewDBUserRepository(db.DB)
+func NewUserModule(scope *TenantScope) *UserModule {
+	userRepo := repository.NewDBUserRepository(scope.DB)
 
-	userService := service.NewUserService(userRepo)
+	userService := service.NewUserService(userRepo, storage.Store)
 
 	userHandler := handler.NewUserHandler(userService)
 
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+	"lms/src/storage"
+)
+
+type VideoModule struct {
+	routes routes.Route
+}
+
+func NewVideoModule(scope *TenantScope) *VideoModule {
+	videoRepo := repository.NewDBVideoRepository(scope.DB)
+	instructorRepo := repository.NewDBInstructorRepository(scope.DB)
+	lessonRepo := repository.NewDBLessonRepository(scope.DB)
+
+	// Transcoder (hàng đợi + workers) dùng chung cho mọi tenant
+	videoService := service.NewVideoService(videoRepo, instructorRepo, lessonRepo, scope.Platform.VideoTranscoder, storage.Store)
+
+	videoHandler := handler.NewVideoHandler(videoService)
+
+	videoRoutes := routes.NewVideoRoutes(videoHandler)
+
+	return &VideoModule{routes: videoRoutes}
+}
+
+func (vm *VideoModule) Routes() routes.Route {
+	return vm.routes
+}
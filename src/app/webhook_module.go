@@ -0,0 +1,29 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+)
+
+type WebhookModule struct {
+	routes routes.Route
+}
+
+// NewWebhookModule: admin của tenant quản lý endpoint của tenant mình, việc gửi do Platform.WebhookDispatcher đảm nhận
+func NewWebhookModule(scope *TenantScope) *WebhookModule {
+	webhookRepo := repository.NewDBWebhookRepository(scope.DB)
+
+	webhookService := service.NewWebhookService(webhookRepo)
+
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+
+	webhookRoutes := routes.NewWebhookRoutes(webhookHandler)
+
+	return &WebhookModule{routes: webhookRoutes}
+}
+
+func (wm *WebhookModule) Routes() routes.Route {
+	return wm.routes
+}
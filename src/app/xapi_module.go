@@ -0,0 +1,50 @@
+package app
+
+import (
+	"lms/src/handler"
+	"lms/src/repository"
+	"lms/src/routes"
+	"lms/src/service"
+	"lms/src/utils"
+)
+
+type XapiModule struct {
+	routes routes.Route
+}
+
+func NewXapiModule(scope *TenantScope) *XapiModule {
+	xapiRepo := repository.NewDBXapiRepository(scope.DB)
+
+	xapiService := service.NewXapiService(xapiRepo)
+
+	xapiHandler := handler.NewXapiHandler(xapiService)
+
+	xapiRoutes := routes.NewXapiRoutes(xapiHandler, lrsCredentials(scope))
+
+	return &XapiModule{routes: xapiRoutes}
+}
+
+func (xm *XapiModule) Routes() routes.Route {
+	return xm.routes
+}
+
+// lrsCredentials đọc credentials LRS của tenant từ cache (đổi trong tenant settings có hiệu lực ngay).
+// Tenant mặc định chưa cấu hình thì dùng XAPI_BUILTIN_LRS_KEY/SECRET như trước khi có multi-tenant.
+func lrsCredentials(scope *TenantScope) func() (string, string) {
+	tenantId := scope.Tenant.Id
+	return func() (string, string) {
+		if tenant, err := scope.Platform.TenantResolver.Get(tenantId); err == nil && tenant != nil && tenant.XapiLrsKey != "" {
+			return tenant.XapiLrsKey, tenant.XapiLrsSecretHash
+		}
+		if !scope.IsDefault() {
+			return "", ""
+		}
+
+		key := utils.GetEnv("XAPI_BUILTIN_LRS_KEY", "")
+		secret := utils.GetEnv("XAPI_BUILTIN_LRS_SECRET", "")
+		if key == "" || secret == "" {
+			return "", ""
+		}
+		return key, utils.HashToken(secret)
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import "lms/src/utils"
+
+type S3Config struct {
+	Endpoint     string
+	Region       string
+	Bucket       string
+	AccessKey    string
+	SecretKey    string
+	PublicURL    string
+	UsePathStyle bool
+}
+
+type LocalStorageConfig struct {
+	PublicDir  string
+	PrivateDir string
+	BaseURL    string
+}
+
+type StorageConfig struct {
+	Driver string // local hoặc s3
+	Local  LocalStorageConfig
+	S3     S3Config
+}
+
+func NewStorageConfig() *StorageConfig {
+	return &StorageConfig{
+		Driver: utils.GetEnv("STORAGE_DRIVER", "local"),
+		Local: LocalStorageConfig{
+			PublicDir:  utils.GetEnv("STORAGE_LOCAL_DIR", "./uploads"),
+			PrivateDir: utils.GetEnv("STORAGE_LOCAL_PRIVATE_DIR", "./storage/private"),
+			BaseURL:    utils.GetEnv("BASE_URL", "http://localhost:8080"),
+		},
+		S3: S3Config{
+			Endpoint:     utils.GetEnv("S3_ENDPOINT", "http://localhost:9000"),
+			Region:       utils.GetEnv("S3_REGION", "us-east-1"),
+			Bucket:       utils.GetEnv("S3_BUCKET", "lms"),
+			AccessKey:    utils.GetEnv("S3_ACCESS_KEY", ""),
+			SecretKey:    utils.GetEnv("S3_SECRET_KEY", ""),
+			PublicURL:    utils.GetEnv("S3_PUBLIC_URL", ""),
+			UsePathStyle: utils.GetEnv("S3_USE_PATH_STYLE", "true") == "true",
+		},
+	}
+}
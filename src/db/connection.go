@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"lms/src/config"
 	"lms/src/models"
+	"lms/src/utils"
 	"log"
+	"strings"
 	"time"
 
 	"gorm.io/driver/postgres"
@@ -54,7 +56,15 @@ func InitDB() error {
 		return fmt.Errorf("DB ping error: %w", err)
 	}
 
+	// Mọi query qua model có TenantId được giới hạn theo tenant của context (xem ForTenant)
+	if err := registerTenantScope(DB); err != nil {
+		sqlDB.Close()
+		return fmt.Errorf("error registering tenant scope: %w", err)
+	}
+
 	err = DB.AutoMigrate( // Tự động tạo/cập nhật bảng dựa trên struct
+		&models.Tenant{},
+		&models.TenantDomain{},
 		&models.User{},
 		&models.PasswordReset{},
 		&models.Category{},
@@ -65,6 +75,60 @@ func InitDB() error {
 		&models.Review{},
 		&models.Coupon{},
 		&models.Order{},
+		&models.Quiz{},
+		&models.QuizQuestion{},
+		&models.QuizAttempt{},
+		&models.CoursePrerequisite{},
+		&models.LearningPath{},
+		&models.LearningPathCourse{},
+		&models.LearningPathCertificate{},
+		&models.VideoUpload{},
+		&models.VideoUploadChunk{},
+		&models.LessonAttachment{},
+		&models.LessonSubtitle{},
+		&models.TranscriptCue{},
+		&models.DiscussionThread{},
+		&models.DiscussionReply{},
+		&models.DiscussionUpvote{},
+		&models.CourseAssistant{},
+		&models.LessonNote{},
+		&models.CourseAnnouncement{},
+		&models.AnnouncementReceipt{},
+		&models.AnnouncementUnsubscribe{},
+		&models.Notification{},
+		&models.NotificationPreference{},
+		&models.OutboxEvent{},
+		&models.OutboxDelivery{},
+		&models.WebhookEndpoint{},
+		&models.WebhookDelivery{},
+		&models.ReviewReport{},
+		&models.ReviewReply{},
+		&models.ReviewVote{},
+		&models.CourseStatusHistory{},
+		&models.CourseRevision{},
+		&models.ScormPackage{},
+		&models.ScormAttempt{},
+		&models.XapiStatement{},
+		&models.LtiPlatform{},
+		&models.LtiToolKey{},
+		&models.LtiLaunchState{},
+		&models.LtiUserLink{},
+		&models.LtiResourceLink{},
+		&models.LtiResourceLinkUser{},
+		&models.LtiDeepLinkSession{},
+		&models.Organization{},
+		&models.OrganizationMember{},
+		&models.OrganizationLicense{},
+		&models.OrganizationSeatAssignment{},
+		&models.OrganizationInvoice{},
+		&models.SubscriptionPlan{},
+		&models.SubscriptionPlanTag{},
+		&models.CourseTag{},
+		&models.Subscription{},
+		&models.SubscriptionPayment{},
+		&models.Bundle{},
+		&models.BundleCourse{},
+		&models.BundlePurchase{},
 	)
 
 	if err != nil {
@@ -72,7 +136,101 @@ func InitDB() error {
 		return fmt.Errorf("error running migration: %w", err)
 	}
 
+	// Index full-text cho transcript (config 'simple' để dùng được cho cả tiếng Việt và tiếng Anh)
+	err = DB.Exec("CREATE INDEX IF NOT EXISTS idx_transcript_cues_text_search ON transcript_cues USING GIN (to_tsvector('simple', text))").Error
+	if err != nil {
+		sqlDB.Close()
+		return fmt.Errorf("error creating transcript search index: %w", err)
+	}
+
+	// Index full-text cho ghi chú của học viên
+	err = DB.Exec("CREATE INDEX IF NOT EXISTS idx_lesson_notes_content_search ON lesson_notes USING GIN (to_tsvector('simple', content))").Error
+	if err != nil {
+		sqlDB.Close()
+		return fmt.Errorf("error creating note search index: %w", err)
+	}
+
+	// Mỗi outbox event chỉ tạo một delivery cho mỗi endpoint (replay tạo delivery mới nên không tính)
+	err = DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_event ON webhook_deliveries (endpoint_id, event_id) WHERE replay_of_id IS NULL AND event_id IS NOT NULL").Error
+	if err != nil {
+		sqlDB.Close()
+		return fmt.Errorf("error creating webhook delivery index: %w", err)
+	}
+
+	// Mỗi course chỉ có một bản draft và mỗi version published là duy nhất
+	err = DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_course_revisions_draft ON course_revisions (course_id) WHERE status = 'draft'").Error
+	if err != nil {
+		sqlDB.Close()
+		return fmt.Errorf("error creating course revision draft index: %w", err)
+	}
+
+	err = DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_course_revisions_version ON course_revisions (course_id, version) WHERE status = 'published'").Error
+	if err != nil {
+		sqlDB.Close()
+		return fmt.Errorf("error creating course revision version index: %w", err)
+	}
+
+	// Mỗi user chỉ có một subscription đang hiệu lực
+	err = DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_current ON subscriptions (user_id) WHERE status IN ('trialing', 'active', 'past_due')").Error
+	if err != nil {
+		sqlDB.Close()
+		return fmt.Errorf("error creating current subscription index: %w", err)
+	}
+
+	// Mỗi user chỉ có một lần mua đang xử lý cho mỗi bundle (chặn double submit bị thu tiền hai lần)
+	err = DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_bundle_purchases_pending ON bundle_purchases (user_id, bundle_id) WHERE status = 'pending'").Error
+	if err != nil {
+		sqlDB.Close()
+		return fmt.Errorf("error creating pending bundle purchase index: %w", err)
+	}
+
+	// Username, email, slug, mã coupon, LTI platform và xAPI statement id giờ chỉ unique trong một tenant: bỏ các unique index toàn cục cũ
+	err = DB.Exec("DROP INDEX IF EXISTS idx_users_username, idx_users_email, idx_courses_slug, idx_categories_slug, idx_coupons_code, idx_organizations_slug, idx_learning_paths_slug, idx_lti_platform_client, idx_xapi_statements_statement_id").Error
+	if err != nil {
+		sqlDB.Close()
+		return fmt.Errorf("error dropping global unique indexes: %w", err)
+	}
+
+	// Tenant mặc định giữ toàn bộ dữ liệu có từ trước
+	if err := seedDefaultTenant(); err != nil {
+		sqlDB.Close()
+		return fmt.Errorf("error seeding default tenant: %w", err)
+	}
+
 	log.Println("Connected and migrated successfully")
 
 	return nil
 }
+
+// seedDefaultTenant tạo tenant mặc định (id = 1) nếu chưa có và cấp quyền super-admin
+// cho các user của tenant này có email nằm trong SUPER_ADMIN_EMAILS
+func seedDefaultTenant() error {
+	defaultTenant := models.Tenant{
+		Id:          models.DefaultTenantId,
+		Slug:        "default",
+		Name:        utils.GetEnv("DEFAULT_TENANT_NAME", "LMS"),
+		Status:      "active",
+		FrontendURL: utils.GetEnv("FRONTEND_URL", "http://localhost:3000"),
+	}
+	if err := DB.Where("id = ?", models.DefaultTenantId).FirstOrCreate(&defaultTenant).Error; err != nil {
+		return err
+	}
+
+	// Id được gán tay nên phải đẩy sequence lên để tenant tạo sau không bị trùng id
+	if err := DB.Exec("SELECT setval(pg_get_serial_sequence('tenants', 'id'), (SELECT MAX(id) FROM tenants))").Error; err != nil {
+		return err
+	}
+
+	var emails []string
+	for _, email := range strings.Split(utils.GetEnv("SUPER_ADMIN_EMAILS", ""), ",") {
+		if email = strings.TrimSpace(email); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	if len(emails) == 0 {
+		return nil
+	}
+	return DB.Model(&models.User{}).
+		Where("tenant_id = ? AND email IN ?", models.DefaultTenantId, emails).
+		Update("is_super_admin", true).Error
+}
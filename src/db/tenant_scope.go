@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"lms/src/utils"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ForTenant trả về kết nối chỉ thấy dữ liệu của một tenant.
+// Repository tạo từ kết nối này tự động lọc/gán tenant_id cho mọi model có field TenantId.
+func ForTenant(tenantId uint) *gorm.DB {
+	return DB.WithContext(utils.WithTenant(context.Background(), tenantId))
+}
+
+// registerTenantScope đăng ký callback giới hạn query theo tenant của context.
+// Raw SQL không đi qua các callback này nên phải tự lọc tenant_id.
+func registerTenantScope(database *gorm.DB) error {
+	callbacks := database.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:scope_query", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:scope_row", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:scope_update", scopeTenant); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:scope_delete", scopeTenant); err != nil {
+		return err
+	}
+	return callbacks.Create().Before("gorm:create").Register("tenant:assign", assignTenant)
+}
+
+// scopeTenant thêm điều kiện tenant_id = ? khi model có field TenantId
+func scopeTenant(tx *gorm.DB) {
+	tenantId, ok := utils.TenantFromContext(tx.Statement.Context)
+	if !ok || tx.Statement.Schema == nil {
+		return
+	}
+	field := tx.Statement.Schema.LookUpField("TenantId")
+	if field == nil {
+		return
+	}
+
+	tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: tenantId},
+	}})
+}
+
+// assignTenant gán tenant của context cho bản ghi mới (ghi đè giá trị từ request nếu có)
+func assignTenant(tx *gorm.DB) {
+	tenantId, ok := utils.TenantFromContext(tx.Statement.Context)
+	if !ok || tx.Statement.Schema == nil {
+		return
+	}
+	field := tx.Statement.Schema.LookUpField("TenantId")
+	if field == nil {
+		return
+	}
+
+	ctx := tx.Statement.Context
+	switch tx.Statement.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < tx.Statement.ReflectValue.Len(); i++ {
+			if err := field.Set(ctx, reflect.Indirect(tx.Statement.ReflectValue.Index(i)), tenantId); err != nil {
+				tx.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := field.Set(ctx, tx.Statement.ReflectValue, tenantId); err != nil {
+			tx.AddError(err)
+		}
+	}
+}
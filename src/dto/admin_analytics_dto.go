@@ -39,6 +39,36 @@ type AdminRevenueAnalyticsResponse struct {
 	TopCourses          []TopCourseRevenue      `json:"top_courses"`
 	RevenueGrowth       float64                 `json:"revenue_growth"`
 	PaymentMethodStats  []PaymentMethodStat     `json:"payment_method_stats"`
+
+	// Doanh thu subscription báo cáo riêng, không cộng vào các số liệu order ở trên
+	SubscriptionRevenue SubscriptionRevenueStats `json:"subscription_revenue"`
+}
+
+type SubscriptionRevenueStats struct {
+	TotalRevenue        float64                       `json:"total_revenue"`
+	Payments            int                           `json:"payments"`
+	FailedPayments      int                           `json:"failed_payments"`
+	NewSubscriptions    int                           `json:"new_subscriptions"`
+	EndedSubscriptions  int                           `json:"ended_subscriptions"`
+	ActiveSubscriptions int                           `json:"active_subscriptions"` // Hiện tại, không phụ thuộc khoảng thời gian
+	RevenueByPeriod     []SubscriptionRevenuePeriod   `json:"revenue_by_period"`
+	RevenueByPlan       []SubscriptionPlanRevenueItem `json:"revenue_by_plan"`
+}
+
+type SubscriptionRevenuePeriod struct {
+	Period      string  `json:"period"`
+	Revenue     float64 `json:"revenue"`
+	Payments    int     `json:"payments"`
+	Subscribers int     `json:"subscribers"`
+}
+
+type SubscriptionPlanRevenueItem struct {
+	PlanId              uint    `json:"plan_id"`
+	PlanName            string  `json:"plan_name"`
+	Interval            string  `json:"interval"`
+	Revenue             float64 `json:"revenue"`
+	Payments            int     `json:"payments"`
+	ActiveSubscriptions int     `json:"active_subscriptions"`
 }
 
 type InstructorRevenueItem struct {
@@ -129,6 +129,7 @@ type AdminCourseItem struct {
 	RatingAvg      float32   `json:"rating_avg"`
 	RatingCount    int       `json:"rating_count"`
 	IsFeatured     bool      `json:"is_featured"`
+	IsTemplate     bool      `json:"is_template"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 }
@@ -32,6 +32,11 @@ type RevenueAnalyticsResponse struct {
 	RevenueByPeriod   []RevenuePeriodItem `json:"revenue_by_period"`
 	TopCourses        []TopCourseRevenue  `json:"top_courses"`
 	RevenueGrowth     float64             `json:"revenue_growth"`
+
+	// Phần doanh thu đến từ bundle (đã gồm trong TotalRevenue/TotalOrders):
+	// giá bundle được phân bổ cho từng course theo tỷ lệ giá lẻ
+	BundleRevenue float64 `json:"bundle_revenue"`
+	BundleOrders  int     `json:"bundle_orders"`
 }
 
 type RevenuePeriodItem struct {
@@ -42,11 +47,12 @@ type RevenuePeriodItem struct {
 }
 
 type TopCourseRevenue struct {
-	CourseId    uint    `json:"course_id"`
-	CourseTitle string  `json:"course_title"`
-	Revenue     float64 `json:"revenue"`
-	Orders      int     `json:"orders"`
-	Students    int     `json:"students"`
+	CourseId      uint    `json:"course_id"`
+	CourseTitle   string  `json:"course_title"`
+	Revenue       float64 `json:"revenue"`
+	BundleRevenue float64 `json:"bundle_revenue"` // Phần Revenue đến từ bundle
+	Orders        int     `json:"orders"`
+	Students      int     `json:"students"`
 }
 
 // Student Analytics Request
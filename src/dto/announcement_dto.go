@@ -0,0 +1,93 @@
+package dto
+
+import "time"
+
+type CreateAnnouncementRequest struct {
+	Title     string     `json:"title" binding:"required,min=3,max=200"`
+	Body      string     `json:"body" binding:"required,max=50000"` // Rich-text HTML
+	PublishAt *time.Time `json:"publish_at" binding:"omitempty"`    // Bỏ trống để gửi ngay
+}
+
+// UpdateAnnouncementRequest chỉ áp dụng cho announcement chưa gửi
+type UpdateAnnouncementRequest struct {
+	Title     *string    `json:"title" binding:"omitempty,min=3,max=200"`
+	Body      *string    `json:"body" binding:"omitempty,max=50000"`
+	PublishAt *time.Time `json:"publish_at" binding:"omitempty"`
+}
+
+type GetCourseAnnouncementsQueryRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// AnnouncementStats là số liệu gửi/đọc của announcement cho instructor
+type AnnouncementStats struct {
+	RecipientCount int `json:"recipient_count"`
+	ReadCount      int `json:"read_count"`
+	EmailSentCount int `json:"email_sent_count"`
+	EmailOpenCount int `json:"email_open_count"`
+}
+
+type InstructorAnnouncementItem struct {
+	Id          uint              `json:"id"`
+	CourseId    uint              `json:"course_id"`
+	Title       string            `json:"title"`
+	Body        string            `json:"body"`
+	Status      string            `json:"status"`
+	PublishAt   time.Time         `json:"publish_at"`
+	PublishedAt *time.Time        `json:"published_at"`
+	Stats       AnnouncementStats `json:"stats"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+type GetCourseAnnouncementsResponse struct {
+	Announcements []InstructorAnnouncementItem `json:"announcements"`
+	Pagination    PaginationInfo               `json:"pagination"`
+}
+
+type DeleteAnnouncementResponse struct {
+	Message string `json:"message"`
+	Id      uint   `json:"id"`
+}
+
+type GetAnnouncementFeedQueryRequest struct {
+	Page       int   `form:"page" binding:"omitempty,min=1"`
+	Limit      int   `form:"limit" binding:"omitempty,min=1,max=50"`
+	CourseId   *uint `form:"course_id" binding:"omitempty,min=1"`
+	UnreadOnly bool  `form:"unread_only" binding:"omitempty"`
+}
+
+type AnnouncementFeedItem struct {
+	Id             uint       `json:"id"`
+	CourseId       uint       `json:"course_id"`
+	CourseTitle    string     `json:"course_title"`
+	CourseSlug     string     `json:"course_slug"`
+	InstructorName string     `json:"instructor_name"`
+	Title          string     `json:"title"`
+	Body           string     `json:"body"`
+	PublishedAt    *time.Time `json:"published_at"`
+	IsRead         bool       `json:"is_read"`
+	ReadAt         *time.Time `json:"read_at"`
+}
+
+type GetAnnouncementFeedResponse struct {
+	Announcements []AnnouncementFeedItem `json:"announcements"`
+	UnreadCount   int                    `json:"unread_count"`
+	Pagination    PaginationInfo         `json:"pagination"`
+}
+
+type AnnouncementSubscriptionRequest struct {
+	Subscribed *bool `json:"subscribed" binding:"required"`
+}
+
+type AnnouncementSubscriptionResponse struct {
+	CourseId   uint `json:"course_id"`
+	Subscribed bool `json:"subscribed"`
+}
+
+type UnsubscribeAnnouncementResponse struct {
+	Message     string `json:"message"`
+	CourseId    uint   `json:"course_id"`
+	CourseTitle string `json:"course_title"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+type LessonAttachmentItem struct {
+	Id            uint      `json:"id"`
+	LessonId      uint      `json:"lesson_id"`
+	Title         string    `json:"title"`
+	FileName      string    `json:"file_name"`
+	FileSize      int64     `json:"file_size"`
+	MimeType      string    `json:"mime_type"`
+	DownloadCount int       `json:"download_count"`
+	DownloadURL   string    `json:"download_url"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/lessons/:id/attachments/:attachment_id
+type DeleteAttachmentResponse struct {
+	Message string `json:"message"`
+	Id      uint   `json:"id"`
+}
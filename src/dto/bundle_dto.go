@@ -0,0 +1,189 @@
+package dto
+
+import "time"
+
+// GET /api/v1/bundles - Query parameters
+type GetBundlesQueryRequest struct {
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=50"`
+	Search  string `form:"search" binding:"omitempty,search"`
+	OrderBy string `form:"order_by" binding:"omitempty,oneof=created_at title price"`
+	SortBy  string `form:"sort_by" binding:"omitempty,oneof=asc desc"`
+}
+
+// GET /api/v1/admin/bundles - Query parameters
+type AdminGetBundlesQueryRequest struct {
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Search  string `form:"search" binding:"omitempty,search"`
+	Status  string `form:"status" binding:"omitempty,oneof=draft published archived"`
+	OrderBy string `form:"order_by" binding:"omitempty,oneof=created_at title price"`
+	SortBy  string `form:"sort_by" binding:"omitempty,oneof=asc desc"`
+}
+
+type BundleItem struct {
+	Id            uint              `json:"id"`
+	Title         string            `json:"title"`
+	Slug          string            `json:"slug"`
+	Description   string            `json:"description"`
+	ThumbnailURL  string            `json:"thumbnail_url"`
+	Price         float64           `json:"price"`
+	DiscountPrice *float64          `json:"discount_price"`
+	OriginalPrice float64           `json:"original_price"` // Tổng giá lẻ các course trong bundle
+	TotalCourses  int               `json:"total_courses"`
+	Status        string            `json:"status"`
+	Sales         *BundleSalesStats `json:"sales,omitempty"` // Chỉ trả về cho admin
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+type BundleSalesStats struct {
+	Purchases int     `json:"purchases"`
+	Revenue   float64 `json:"revenue"`
+}
+
+type GetBundlesResponse struct {
+	Bundles    []BundleItem   `json:"bundles"`
+	Pagination PaginationInfo `json:"pagination"`
+}
+
+type BundleCourseItem struct {
+	CourseId       uint     `json:"course_id"`
+	Position       int      `json:"position"`
+	Title          string   `json:"title"`
+	Slug           string   `json:"slug"`
+	ThumbnailURL   string   `json:"thumbnail_url"`
+	InstructorId   uint     `json:"instructor_id"`
+	InstructorName string   `json:"instructor_name"`
+	Level          string   `json:"level"`
+	Price          float64  `json:"price"`
+	DiscountPrice  *float64 `json:"discount_price"`
+	DurationHours  int      `json:"duration_hours"`
+}
+
+type BundleDetail struct {
+	Id            uint               `json:"id"`
+	Title         string             `json:"title"`
+	Slug          string             `json:"slug"`
+	Description   string             `json:"description"`
+	ThumbnailURL  string             `json:"thumbnail_url"`
+	Price         float64            `json:"price"`
+	DiscountPrice *float64           `json:"discount_price"`
+	OriginalPrice float64            `json:"original_price"`
+	Savings       float64            `json:"savings"` // Số tiền tiết kiệm so với mua lẻ
+	Status        string             `json:"status"`
+	CreatedBy     uint               `json:"created_by"`
+	Courses       []BundleCourseItem `json:"courses"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// POST /api/v1/admin/bundles
+type CreateBundleRequest struct {
+	Title         string   `json:"title" binding:"required,min=5,max=200"`
+	Description   string   `json:"description" binding:"omitempty,max=5000"`
+	ThumbnailURL  string   `json:"thumbnail_url" binding:"omitempty,url"`
+	Price         float64  `json:"price" binding:"gte=0"`
+	DiscountPrice *float64 `json:"discount_price" binding:"omitempty,gte=0"`
+	Status        string   `json:"status" binding:"omitempty,oneof=draft published"`
+	CourseIds     []uint   `json:"course_ids" binding:"required,min=2,max=50"`
+}
+
+// PUT /api/v1/admin/bundles/:bundle_id
+type UpdateBundleRequest struct {
+	Title         *string  `json:"title" binding:"omitempty,min=5,max=200"`
+	Description   *string  `json:"description" binding:"omitempty,max=5000"`
+	ThumbnailURL  *string  `json:"thumbnail_url" binding:"omitempty,url"`
+	Price         *float64 `json:"price" binding:"omitempty,gte=0"`
+	DiscountPrice *float64 `json:"discount_price" binding:"omitempty,gte=0"`
+	Status        *string  `json:"status" binding:"omitempty,oneof=draft published archived"`
+}
+
+// PUT /api/v1/admin/bundles/:bundle_id/courses
+type SetBundleCoursesRequest struct {
+	CourseIds []uint `json:"course_ids" binding:"required,min=2,max=50"`
+}
+
+type DeleteBundleResponse struct {
+	Message string `json:"message"`
+}
+
+// ---------------- Purchase ----------------
+// Giá bundle phân bổ cho từng course theo tỷ lệ giá lẻ; course đã sở hữu không tính tiền
+type BundleQuoteCourse struct {
+	CourseId       uint    `json:"course_id"`
+	Title          string  `json:"title"`
+	ListPrice      float64 `json:"list_price"`
+	AllocatedPrice float64 `json:"allocated_price"`
+	Owned          bool    `json:"owned"`
+}
+
+// GET /api/v1/bundles/:slug/quote
+type BundleQuoteResponse struct {
+	BundleId       uint                `json:"bundle_id"`
+	BundleTitle    string              `json:"bundle_title"`
+	BundlePrice    float64             `json:"bundle_price"`
+	OriginalPrice  float64             `json:"original_price"` // Tổng giá lẻ các course sẽ được ghi danh
+	FinalPrice     float64             `json:"final_price"`
+	CoursesCount   int                 `json:"courses_count"`
+	SkippedCourses int                 `json:"skipped_courses"`
+	Courses        []BundleQuoteCourse `json:"courses"`
+}
+
+// POST /api/v1/bundles/:slug/purchase
+type PurchaseBundleRequest struct {
+	PaymentMethod string `json:"payment_method" binding:"required,oneof=credit_card paypal momo zalopay bank_transfer"`
+}
+
+type BundlePurchaseCourseItem struct {
+	CourseId      uint    `json:"course_id"`
+	CourseTitle   string  `json:"course_title"`
+	OrderId       uint    `json:"order_id"`
+	OrderCode     string  `json:"order_code"`
+	EnrollmentId  uint    `json:"enrollment_id"`
+	OriginalPrice float64 `json:"original_price"`
+	FinalPrice    float64 `json:"final_price"`
+}
+
+type PurchaseBundleResponse struct {
+	PurchaseId       uint                       `json:"purchase_id"`
+	PurchaseCode     string                     `json:"purchase_code"`
+	BundleId         uint                       `json:"bundle_id"`
+	BundleTitle      string                     `json:"bundle_title"`
+	BundlePrice      float64                    `json:"bundle_price"`
+	OriginalPrice    float64                    `json:"original_price"`
+	FinalPrice       float64                    `json:"final_price"`
+	PaymentMethod    string                     `json:"payment_method"`
+	TransactionId    string                     `json:"transaction_id"`
+	PaidAt           time.Time                  `json:"paid_at"`
+	Courses          []BundlePurchaseCourseItem `json:"courses"`
+	SkippedCourseIds []uint                     `json:"skipped_course_ids"`
+	Message          string                     `json:"message"`
+}
+
+type BundlePurchaseItem struct {
+	Id             uint       `json:"id"`
+	PurchaseCode   string     `json:"purchase_code"`
+	BundleId       uint       `json:"bundle_id"`
+	BundleTitle    string     `json:"bundle_title"`
+	BundleSlug     string     `json:"bundle_slug"`
+	BundlePrice    float64    `json:"bundle_price"`
+	OriginalPrice  float64    `json:"original_price"`
+	FinalPrice     float64    `json:"final_price"`
+	CoursesCount   int        `json:"courses_count"`
+	SkippedCourses int        `json:"skipped_courses"`
+	PaymentMethod  string     `json:"payment_method"`
+	Status         string     `json:"status"`
+	PaidAt         *time.Time `json:"paid_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// GET /api/v1/bundle-purchases
+type GetBundlePurchasesQueryRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type GetBundlePurchasesResponse struct {
+	Purchases  []BundlePurchaseItem `json:"purchases"`
+	Pagination PaginationInfo       `json:"pagination"`
+}
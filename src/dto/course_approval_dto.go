@@ -0,0 +1,90 @@
+package dto
+
+import "time"
+
+// ---------------- Checklist ----------------
+type CourseChecklistItem struct {
+	Key    string `json:"key"`
+	Label  string `json:"label"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail"`
+}
+
+type CourseReviewChecklist struct {
+	Passed bool                  `json:"passed"`
+	Items  []CourseChecklistItem `json:"items"`
+}
+
+// ---------------- Instructor ----------------
+type CourseReviewStatusResponse struct {
+	CourseId       uint                  `json:"course_id"`
+	Title          string                `json:"title"`
+	Status         string                `json:"status"`
+	SubmittedAt    *time.Time            `json:"submitted_at"`
+	ReviewedAt     *time.Time            `json:"reviewed_at"`
+	ReviewFeedback string                `json:"review_feedback"`
+	Checklist      CourseReviewChecklist `json:"checklist"`
+}
+
+type SubmitCourseReviewRequest struct {
+	Note string `json:"note" binding:"omitempty,max=1000"`
+}
+
+type CourseReviewActionResponse struct {
+	CourseId uint   `json:"course_id"`
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+}
+
+// ---------------- Admin ----------------
+type GetCourseReviewQueueQueryRequest struct {
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Search     string `form:"search" binding:"omitempty,search"`
+	CategoryId uint   `form:"category_id" binding:"omitempty,min=1"`
+}
+
+type CourseReviewQueueItem struct {
+	Id             uint                  `json:"id"`
+	Title          string                `json:"title"`
+	Slug           string                `json:"slug"`
+	ThumbnailURL   string                `json:"thumbnail_url"`
+	InstructorId   uint                  `json:"instructor_id"`
+	InstructorName string                `json:"instructor_name"`
+	CategoryId     uint                  `json:"category_id"`
+	CategoryName   string                `json:"category_name"`
+	Price          float64               `json:"price"`
+	LessonCount    int                   `json:"lesson_count"`
+	SubmittedAt    *time.Time            `json:"submitted_at"`
+	Checklist      CourseReviewChecklist `json:"checklist"`
+}
+
+type GetCourseReviewQueueResponse struct {
+	Courses    []CourseReviewQueueItem `json:"courses"`
+	Pagination PaginationInfo          `json:"pagination"`
+}
+
+type ApproveCourseRequest struct {
+	Comment string `json:"comment" binding:"omitempty,max=2000"`
+}
+
+type RejectCourseRequest struct {
+	Comment string `json:"comment" binding:"required,min=10,max=2000"`
+}
+
+// ---------------- Status history ----------------
+type CourseStatusHistoryItem struct {
+	Id            uint      `json:"id"`
+	FromStatus    string    `json:"from_status"`
+	ToStatus      string    `json:"to_status"`
+	Action        string    `json:"action"`
+	Comment       string    `json:"comment"`
+	ChangedBy     uint      `json:"changed_by"`
+	ChangedByName string    `json:"changed_by_name"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type GetCourseStatusHistoryResponse struct {
+	CourseId uint                      `json:"course_id"`
+	History  []CourseStatusHistoryItem `json:"history"`
+}
@@ -24,6 +24,9 @@ type CourseItem struct {
 	RatingCount    int       `json:"rating_count"`
 	EnrolledCount  int       `json:"enrolled_count"`
 	CreatedAt      time.Time `json:"created_at"`
+
+	// Đoạn transcript khớp từ khóa (chỉ có khi search)
+	TranscriptMatches []TranscriptMatch `json:"transcript_matches,omitempty"`
 }
 
 type GetCoursesQueryRequest struct {
@@ -45,6 +48,9 @@ type GetCoursesQueryRequest struct {
 type GetCoursesResponse struct {
 	Courses    []CourseItem   `json:"courses"`
 	Pagination PaginationInfo `json:"pagination"`
+
+	// Bundle đang bán hiển thị cùng danh sách course (chỉ ở trang đầu)
+	Bundles []BundleItem `json:"bundles,omitempty"`
 }
 
 type SearchCoursesQueryRequest struct {
@@ -90,6 +96,8 @@ type GetFeaturedCoursesQueryRequest struct {
 type GetFeaturedCoursesResponse struct {
 	Courses []CourseItem `json:"courses"`
 	Total   int          `json:"total"`
+
+	FeaturedPaths []LearningPathItem `json:"featured_paths"`
 }
 
 type CourseDetail struct {
@@ -120,6 +128,12 @@ type CourseDetail struct {
 	EnrolledCount   int       `json:"enrolled_count"`
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
+
+	PrerequisiteMode string                   `json:"prerequisite_mode"`
+	Prerequisites    []CoursePrerequisiteItem `json:"prerequisites"`
+
+	// Các bundle đang bán có chứa course
+	Bundles []BundleItem `json:"bundles"`
 }
 
 type ReviewItem struct {
@@ -131,15 +145,23 @@ type ReviewItem struct {
 	Comment     string    `json:"comment"`
 	IsPublished bool      `json:"is_published"`
 	CreatedAt   time.Time `json:"created_at"`
+
+	HelpfulCount       int  `json:"helpful_count"`
+	NotHelpfulCount    int  `json:"not_helpful_count"`
+	IsVerifiedPurchase bool `json:"is_verified_purchase"`
+	HasCompletedCourse bool `json:"has_completed_course"`
+
+	Reply *ReviewReplyItem `json:"reply"`
 }
 
 type GetCourseReviewsQueryRequest struct {
-	Page      int    `form:"page" binding:"omitempty,min=1"`
-	Limit     int    `form:"limit" binding:"omitempty,min=1,max=50"`
-	Rating    *int   `form:"rating" binding:"omitempty,min=1,max=5"`
-	Published *bool  `form:"published" binding:"omitempty"`
-	OrderBy   string `form:"order_by" binding:"omitempty,oneof=created_at rating"`
-	SortBy    string `form:"sort_by" binding:"omitempty,oneof=asc desc"`
+	Page             int    `form:"page" binding:"omitempty,min=1"`
+	Limit            int    `form:"limit" binding:"omitempty,min=1,max=50"`
+	Rating           *int   `form:"rating" binding:"omitempty,min=1,max=5"`
+	VerifiedPurchase *bool  `form:"verified_purchase" binding:"omitempty"`
+	CompletedCourse  *bool  `form:"completed_course" binding:"omitempty"`
+	OrderBy          string `form:"order_by" binding:"omitempty,oneof=created_at rating helpful"`
+	SortBy           string `form:"sort_by" binding:"omitempty,oneof=asc desc"`
 }
 
 type GetCourseReviewsResponse struct {
@@ -152,4 +174,14 @@ type ReviewStats struct {
 	TotalReviews       int         `json:"total_reviews"`
 	AverageRating      float64     `json:"average_rating"`
 	RatingDistribution map[int]int `json:"rating_distribution"`
+
+	VerifiedPurchase ReviewFlagStats `json:"verified_purchase" gorm:"-"`
+	CompletedCourse  ReviewFlagStats `json:"completed_course" gorm:"-"`
+}
+
+// ReviewFlagStats là thống kê của nhóm review có cờ verified purchase / completed course
+type ReviewFlagStats struct {
+	TotalReviews       int         `json:"total_reviews"`
+	AverageRating      float64     `json:"average_rating"`
+	RatingDistribution map[int]int `json:"rating_distribution"`
 }
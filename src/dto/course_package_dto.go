@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"io"
+	"time"
+)
+
+// ---------------- Manifest ----------------
+// CoursePackageManifest là manifest.json trong gói course (zip).
+// Lesson và quiz được tham chiếu qua Ref (không dùng id của DB) để import sang hệ thống khác.
+type CoursePackageManifest struct {
+	FormatVersion int                   `json:"format_version"`
+	ExportedAt    time.Time             `json:"exported_at"`
+	Course        CoursePackageCourse   `json:"course"`
+	Lessons       []CoursePackageLesson `json:"lessons"`
+	Quizzes       []CoursePackageQuiz   `json:"quizzes"`
+}
+
+type CoursePackageCourse struct {
+	Title            string                `json:"title"`
+	Slug             string                `json:"slug"`
+	ShortDesc        string                `json:"short_description"`
+	Description      string                `json:"description"`
+	Category         CoursePackageCategory `json:"category"`
+	Level            string                `json:"level"`
+	Language         string                `json:"language"`
+	Price            float64               `json:"price"`
+	DiscountPrice    *float64              `json:"discount_price"`
+	Requirements     string                `json:"requirements"`
+	WhatYouLearn     string                `json:"what_you_learn"`
+	DurationHours    int                   `json:"duration_hours"`
+	PrerequisiteMode string                `json:"prerequisite_mode"`
+	VideoPreviewURL  string                `json:"video_preview_url"`
+	Thumbnail        *CoursePackageAsset   `json:"thumbnail"`
+}
+
+// CoursePackageCategory được ghép với category của hệ thống đích theo slug
+type CoursePackageCategory struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// CoursePackageAsset là một file trong gói (Path là đường dẫn trong zip)
+type CoursePackageAsset struct {
+	Path     string `json:"path"`
+	FileName string `json:"file_name"`
+	MimeType string `json:"mime_type"`
+	Size     int64  `json:"size"`
+}
+
+type CoursePackageLesson struct {
+	Ref                       string                    `json:"ref"`
+	Title                     string                    `json:"title"`
+	Slug                      string                    `json:"slug"`
+	Description               string                    `json:"description"`
+	ContentPath               string                    `json:"content_path"` // file chứa nội dung lesson
+	VideoURL                  string                    `json:"video_url"`
+	VideoDuration             int                       `json:"video_duration"`
+	IsPreview                 bool                      `json:"is_preview"`
+	IsPublished               bool                      `json:"is_published"`
+	UnlockAfterDays           *int                      `json:"unlock_after_days"`
+	UnlockAt                  *time.Time                `json:"unlock_at"`
+	RequirePreviousCompletion bool                      `json:"require_previous_completion"`
+	PrerequisiteQuizRef       string                    `json:"prerequisite_quiz_ref"`
+	Attachments               []CoursePackageAttachment `json:"attachments"`
+}
+
+type CoursePackageAttachment struct {
+	Title string `json:"title"`
+	CoursePackageAsset
+}
+
+type CoursePackageQuiz struct {
+	Ref          string                  `json:"ref"`
+	LessonRef    string                  `json:"lesson_ref"`
+	Title        string                  `json:"title"`
+	Description  string                  `json:"description"`
+	PassingScore int                     `json:"passing_score"`
+	Questions    []CoursePackageQuestion `json:"questions"`
+}
+
+type CoursePackageQuestion struct {
+	Question      string   `json:"question"`
+	Options       []string `json:"options"`
+	CorrectOption int      `json:"correct_option"`
+}
+
+// ---------------- Export ----------------
+// CoursePackageExport là file zip đã tạo; Body phải được Close để dọn file tạm
+type CoursePackageExport struct {
+	FileName string
+	FileSize int64
+	Body     io.ReadCloser
+}
+
+// ---------------- Import ----------------
+type ImportCourseRequest struct {
+	CategoryId uint `form:"category_id" binding:"omitempty,min=1"` // bỏ trống = ghép theo slug category trong manifest
+}
+
+// CoursePackageProblem là một lỗi cụ thể của gói import (Path: vị trí trong manifest hoặc file trong zip)
+type CoursePackageProblem struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+type ImportCourseResponse struct {
+	Message         string `json:"message"`
+	CourseId        uint   `json:"course_id"`
+	Title           string `json:"title"`
+	Slug            string `json:"slug"`
+	Status          string `json:"status"`
+	CategoryId      uint   `json:"category_id"`
+	FormatVersion   int    `json:"format_version"`
+	LessonsImported int    `json:"lessons_imported"`
+	QuizzesImported int    `json:"quizzes_imported"`
+	AssetsImported  int    `json:"assets_imported"`
+}
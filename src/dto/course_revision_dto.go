@@ -0,0 +1,206 @@
+package dto
+
+import "time"
+
+// ---------------- Snapshot ----------------
+// CourseSnapshot là nội dung được version hóa của course (giá bán và trạng thái không thuộc revision).
+// JSON key trùng tên cột để dùng trực tiếp khi áp dụng thay đổi.
+type CourseSnapshot struct {
+	Title         string           `json:"title"`
+	ShortDesc     string           `json:"short_desc"`
+	Description   string           `json:"description"`
+	CategoryId    uint             `json:"category_id"`
+	Level         string           `json:"level"`
+	Language      string           `json:"language"`
+	Requirements  string           `json:"requirements"`
+	WhatYouLearn  string           `json:"what_you_learn"`
+	DurationHours int              `json:"duration_hours"`
+	ThumbnailURL  string           `json:"thumbnail_url"`
+	Lessons       []LessonSnapshot `json:"lessons"`
+}
+
+// LessonSnapshot là một lesson trong revision; thứ tự trong mảng là thứ tự lesson.
+// Key = "lesson-<id>" với lesson đã tồn tại, "new-..." với lesson thêm trong draft.
+type LessonSnapshot struct {
+	Key                       string     `json:"key"`
+	LessonId                  uint       `json:"lesson_id"`
+	Title                     string     `json:"title"`
+	Description               string     `json:"description"`
+	Content                   string     `json:"content"`
+	VideoURL                  string     `json:"video_url"`
+	VideoDuration             int        `json:"video_duration"`
+	IsPreview                 bool       `json:"is_preview"`
+	IsPublished               bool       `json:"is_published"`
+	UnlockAfterDays           *int       `json:"unlock_after_days"`
+	UnlockAt                  *time.Time `json:"unlock_at"`
+	RequirePreviousCompletion bool       `json:"require_previous_completion"`
+	PrerequisiteQuizId        *uint      `json:"prerequisite_quiz_id"`
+}
+
+// ---------------- Diff ----------------
+type RevisionFieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type RevisionLessonRef struct {
+	Key      string `json:"key"`
+	LessonId uint   `json:"lesson_id"`
+	Title    string `json:"title"`
+}
+
+type RevisionLessonChange struct {
+	RevisionLessonRef
+	Changes []RevisionFieldChange `json:"changes"`
+}
+
+type CourseRevisionDiff struct {
+	FromVersion     int                    `json:"from_version"`
+	ToVersion       int                    `json:"to_version"` // 0 = draft
+	HasChanges      bool                   `json:"has_changes"`
+	Course          []RevisionFieldChange  `json:"course"`
+	AddedLessons    []RevisionLessonRef    `json:"added_lessons"`
+	RemovedLessons  []RevisionLessonRef    `json:"removed_lessons"`
+	ModifiedLessons []RevisionLessonChange `json:"modified_lessons"`
+	Reordered       bool                   `json:"reordered"`
+}
+
+// ---------------- Revision ----------------
+type CourseRevisionItem struct {
+	Id                  uint       `json:"id"`
+	CourseId            uint       `json:"course_id"`
+	Version             int        `json:"version"`
+	Status              string     `json:"status"`
+	BasedOnVersion      int        `json:"based_on_version"`
+	RestoredFromVersion *int       `json:"restored_from_version"`
+	Changelog           string     `json:"changelog"`
+	Summary             string     `json:"summary"`
+	CreatedBy           uint       `json:"created_by"`
+	CreatedByName       string     `json:"created_by_name"`
+	PublishedBy         *uint      `json:"published_by"`
+	PublishedAt         *time.Time `json:"published_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type CourseRevisionDetailResponse struct {
+	Revision CourseRevisionItem `json:"revision"`
+	Snapshot CourseSnapshot     `json:"snapshot"`
+}
+
+// Draft kèm các thay đổi so với version gốc của draft.
+// IsStale = true khi đã có version mới hơn được publish (vd. admin rollback) - draft không thể publish nữa.
+type CourseDraftResponse struct {
+	Revision    CourseRevisionItem `json:"revision"`
+	Snapshot    CourseSnapshot     `json:"snapshot"`
+	Diff        CourseRevisionDiff `json:"diff"`
+	LiveVersion int                `json:"live_version"`
+	IsStale     bool               `json:"is_stale"`
+}
+
+type GetCourseRevisionsQueryRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type GetCourseRevisionsResponse struct {
+	Revisions  []CourseRevisionItem `json:"revisions"`
+	Pagination PaginationInfo       `json:"pagination"`
+}
+
+// ---------------- Draft editing ----------------
+type UpdateCourseDraftRequest struct {
+	Title         *string `json:"title" binding:"omitempty,min=5,max=200"`
+	ShortDesc     *string `json:"short_description" binding:"omitempty,min=10,max=500"`
+	Description   *string `json:"description" binding:"omitempty,min=20"`
+	CategoryId    *uint   `json:"category_id" binding:"omitempty,min=1"`
+	Level         *string `json:"level" binding:"omitempty,course_level"`
+	Language      *string `json:"language" binding:"omitempty,language_code"`
+	Requirements  *string `json:"requirements"`
+	WhatYouLearn  *string `json:"what_you_learn"`
+	DurationHours *int    `json:"duration_hours" binding:"omitempty,min_int=0"`
+	ThumbnailURL  *string `json:"thumbnail_url" binding:"omitempty,url"`
+}
+
+type CreateDraftLessonRequest struct {
+	Title         string `json:"title" binding:"required,min=3,max=200"`
+	Description   string `json:"description" binding:"required,min=10"`
+	Content       string `json:"content" binding:"omitempty"`
+	VideoURL      string `json:"video_url" binding:"omitempty,url"`
+	VideoDuration int    `json:"video_duration" binding:"omitempty,min=0"`
+	IsPreview     bool   `json:"is_preview" binding:"omitempty"`
+	IsPublished   bool   `json:"is_published" binding:"omitempty"`
+	Position      int    `json:"position" binding:"omitempty,min=1"` // vị trí chèn, mặc định cuối danh sách
+
+	UnlockAfterDays           *int       `json:"unlock_after_days" binding:"omitempty,min=0"`
+	UnlockAt                  *time.Time `json:"unlock_at" binding:"omitempty"`
+	RequirePreviousCompletion bool       `json:"require_previous_completion" binding:"omitempty"`
+	PrerequisiteQuizId        *uint      `json:"prerequisite_quiz_id" binding:"omitempty"`
+}
+
+type UpdateDraftLessonRequest struct {
+	Title         *string `json:"title" binding:"omitempty,min=3,max=200"`
+	Description   *string `json:"description" binding:"omitempty,min=10"`
+	Content       *string `json:"content" binding:"omitempty"`
+	VideoURL      *string `json:"video_url" binding:"omitempty,url"`
+	VideoDuration *int    `json:"video_duration" binding:"omitempty,min=0"`
+	IsPreview     *bool   `json:"is_preview" binding:"omitempty"`
+	IsPublished   *bool   `json:"is_published" binding:"omitempty"`
+
+	UnlockAfterDays           *int       `json:"unlock_after_days" binding:"omitempty,min=0"` // 0 = bỏ lịch mở khóa
+	UnlockAt                  *time.Time `json:"unlock_at" binding:"omitempty"`
+	ClearUnlockAt             bool       `json:"clear_unlock_at" binding:"omitempty"`
+	RequirePreviousCompletion *bool      `json:"require_previous_completion" binding:"omitempty"`
+	PrerequisiteQuizId        *uint      `json:"prerequisite_quiz_id" binding:"omitempty"` // 0 = bỏ quiz bắt buộc
+}
+
+type ReorderDraftLessonsRequest struct {
+	Keys []string `json:"keys" binding:"required,min=1"`
+}
+
+type PublishCourseDraftRequest struct {
+	Changelog string `json:"changelog" binding:"required,min=5,max=5000"`
+}
+
+type RollbackCourseRevisionRequest struct {
+	Changelog string `json:"changelog" binding:"omitempty,max=5000"`
+}
+
+type DiscardCourseDraftResponse struct {
+	Message string `json:"message"`
+}
+
+// ---------------- Preview ----------------
+// CoursePreviewResponse là nội dung học viên sẽ thấy sau khi publish draft
+type CoursePreviewResponse struct {
+	CourseId      uint                  `json:"course_id"`
+	Title         string                `json:"title"`
+	ShortDesc     string                `json:"short_description"`
+	Description   string                `json:"description"`
+	CategoryId    uint                  `json:"category_id"`
+	CategoryName  string                `json:"category_name"`
+	Level         string                `json:"level"`
+	Language      string                `json:"language"`
+	Requirements  string                `json:"requirements"`
+	WhatYouLearn  string                `json:"what_you_learn"`
+	DurationHours int                   `json:"duration_hours"`
+	ThumbnailURL  string                `json:"thumbnail_url"`
+	TotalLessons  int                   `json:"total_lessons"`
+	Lessons       []CoursePreviewLesson `json:"lessons"`
+	Diff          CourseRevisionDiff    `json:"diff"`
+}
+
+type CoursePreviewLesson struct {
+	Key           string `json:"key"`
+	LessonOrder   int    `json:"lesson_order"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	VideoDuration int    `json:"video_duration"`
+	IsPreview     bool   `json:"is_preview"`
+}
+
+type DiffCourseRevisionsQueryRequest struct {
+	FromId uint `form:"from_id" binding:"required,min=1"`
+	ToId   uint `form:"to_id" binding:"required,min=1"`
+}
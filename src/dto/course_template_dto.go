@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+// ---------------- Duplicate ----------------
+type DuplicateCourseRequest struct {
+	Title string `json:"title" binding:"omitempty,min=5,max=200"` // mặc định "<title gốc> (Copy)"
+}
+
+type DuplicateCourseResponse struct {
+	Message           string `json:"message"`
+	CourseId          uint   `json:"course_id"`
+	Title             string `json:"title"`
+	Slug              string `json:"slug"`
+	Status            string `json:"status"`
+	CopiedFromId      uint   `json:"copied_from_id"`
+	LessonsCopied     int    `json:"lessons_copied"`
+	QuizzesCopied     int    `json:"quizzes_copied"`
+	AttachmentsCopied int    `json:"attachments_copied"`
+}
+
+// ---------------- Templates ----------------
+type GetCourseTemplatesQueryRequest struct {
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Search     string `form:"search" binding:"omitempty,max=200"`
+	CategoryId uint   `form:"category_id" binding:"omitempty,min=1"`
+}
+
+type CourseTemplateItem struct {
+	Id             uint      `json:"id"`
+	Title          string    `json:"title"`
+	Slug           string    `json:"slug"`
+	ShortDesc      string    `json:"short_description"`
+	ThumbnailURL   string    `json:"thumbnail_url"`
+	CategoryId     uint      `json:"category_id"`
+	CategoryName   string    `json:"category_name"`
+	Level          string    `json:"level"`
+	Language       string    `json:"language"`
+	InstructorId   uint      `json:"instructor_id"`
+	InstructorName string    `json:"instructor_name"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type GetCourseTemplatesResponse struct {
+	Templates  []CourseTemplateItem `json:"templates"`
+	Pagination PaginationInfo       `json:"pagination"`
+}
+
+type SetCourseTemplateRequest struct {
+	IsTemplate *bool `json:"is_template" binding:"required"`
+}
+
+type SetCourseTemplateResponse struct {
+	Message    string `json:"message"`
+	CourseId   uint   `json:"course_id"`
+	IsTemplate bool   `json:"is_template"`
+}
@@ -0,0 +1,121 @@
+package dto
+
+import "time"
+
+// POST /api/v1/courses/course_id/:course_id/discussions
+type CreateThreadRequest struct {
+	LessonId         *uint  `json:"lesson_id" binding:"omitempty,min=1"`
+	Title            string `json:"title" binding:"required,min=5,max=200"`
+	Body             string `json:"body" binding:"required,max=10000"`
+	IsInstructorOnly bool   `json:"is_instructor_only"`
+}
+
+// GET /api/v1/courses/course_id/:course_id/discussions
+type GetThreadsQueryRequest struct {
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=50"`
+	LessonId   *uint  `form:"lesson_id" binding:"omitempty,min=1"`
+	Unanswered *bool  `form:"unanswered" binding:"omitempty"`
+	Search     string `form:"search" binding:"omitempty,search"`
+	SortBy     string `form:"sort_by" binding:"omitempty,oneof=recent top oldest"`
+}
+
+type DiscussionAuthor struct {
+	Id        uint   `json:"id"`
+	FullName  string `json:"full_name"`
+	AvatarURL string `json:"avatar_url"`
+	Role      string `json:"role"` // student, instructor, assistant
+}
+
+type DiscussionThreadItem struct {
+	Id               uint             `json:"id"`
+	CourseId         uint             `json:"course_id"`
+	CourseTitle      string           `json:"course_title,omitempty"`
+	LessonId         *uint            `json:"lesson_id"`
+	LessonTitle      string           `json:"lesson_title,omitempty"`
+	Title            string           `json:"title"`
+	Body             string           `json:"body"`
+	Author           DiscussionAuthor `json:"author"`
+	IsPinned         bool             `json:"is_pinned"`
+	IsInstructorOnly bool             `json:"is_instructor_only"`
+	IsAnswered       bool             `json:"is_answered"`
+	AnswerReplyId    *uint            `json:"answer_reply_id"`
+	UpvoteCount      int              `json:"upvote_count"`
+	ReplyCount       int              `json:"reply_count"`
+	HasUpvoted       bool             `json:"has_upvoted"`
+	LastActivityAt   time.Time        `json:"last_activity_at"`
+	CreatedAt        time.Time        `json:"created_at"`
+}
+
+type GetThreadsResponse struct {
+	Threads    []DiscussionThreadItem `json:"threads"`
+	Pagination PaginationInfo         `json:"pagination"`
+}
+
+// GET /api/v1/discussions/threads/:thread_id
+type DiscussionThreadDetail struct {
+	Thread  DiscussionThreadItem  `json:"thread"`
+	Replies []DiscussionReplyItem `json:"replies"`
+}
+
+// POST /api/v1/discussions/threads/:thread_id/replies
+type CreateReplyRequest struct {
+	Body string `json:"body" binding:"required,max=10000"`
+}
+
+type DiscussionReplyItem struct {
+	Id          uint             `json:"id"`
+	ThreadId    uint             `json:"thread_id"`
+	Body        string           `json:"body"`
+	Author      DiscussionAuthor `json:"author"`
+	IsAnswer    bool             `json:"is_answer"`
+	UpvoteCount int              `json:"upvote_count"`
+	HasUpvoted  bool             `json:"has_upvoted"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+// POST /api/v1/discussions/threads/:thread_id/upvote, /api/v1/discussions/replies/:reply_id/upvote
+type UpvoteResponse struct {
+	Upvoted     bool `json:"upvoted"`
+	UpvoteCount int  `json:"upvote_count"`
+}
+
+// PUT /api/v1/discussions/threads/:thread_id/pin
+type PinThreadRequest struct {
+	IsPinned *bool `json:"is_pinned" binding:"required"`
+}
+
+// PUT /api/v1/discussions/threads/:thread_id/visibility
+type ThreadVisibilityRequest struct {
+	IsInstructorOnly *bool `json:"is_instructor_only" binding:"required"`
+}
+
+type DeleteDiscussionResponse struct {
+	Message string `json:"message"`
+	Id      uint   `json:"id"`
+}
+
+// GET /api/v1/instructor/discussions/unanswered
+type GetUnansweredQueryRequest struct {
+	Page     int   `form:"page" binding:"omitempty,min=1"`
+	Limit    int   `form:"limit" binding:"omitempty,min=1,max=50"`
+	CourseId *uint `form:"course_id" binding:"omitempty,min=1"`
+}
+
+// POST /api/v1/instructor/courses/:course_id/assistants
+type AddCourseAssistantRequest struct {
+	UserId uint `json:"user_id" binding:"required,min=1"`
+}
+
+type CourseAssistantItem struct {
+	UserId    uint      `json:"user_id"`
+	FullName  string    `json:"full_name"`
+	Email     string    `json:"email"`
+	AvatarURL string    `json:"avatar_url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GetCourseAssistantsResponse struct {
+	CourseId   uint                  `json:"course_id"`
+	Assistants []CourseAssistantItem `json:"assistants"`
+}
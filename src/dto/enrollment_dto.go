@@ -3,8 +3,9 @@ package dto
 import "time"
 
 // EnrollCourseRequest - Request để enroll course
+// PaymentMethod bắt buộc khi mua course, không cần khi course nằm trong subscription của user
 type EnrollCourseRequest struct {
-	PaymentMethod string `json:"payment_method" binding:"required,oneof=credit_card paypal momo zalopay bank_transfer"`
+	PaymentMethod string `json:"payment_method" binding:"omitempty,oneof=credit_card paypal momo zalopay bank_transfer"`
 	CouponCode    string `json:"coupon_code" binding:"omitempty"`
 }
 
@@ -22,6 +23,12 @@ type EnrollCourseResponse struct {
 	PaymentStatus  string    `json:"payment_status"`
 	EnrolledAt     time.Time `json:"enrolled_at"`
 	Message        string    `json:"message"`
+
+	// Subscription bao phủ course (ghi danh không qua order)
+	SubscriptionId *uint `json:"subscription_id,omitempty"`
+
+	// Prerequisites chưa hoàn thành (chỉ có khi course ở chế độ warn)
+	MissingPrerequisites []CoursePrerequisiteItem `json:"missing_prerequisites,omitempty"`
 }
 
 // CheckEnrollmentResponse - Kiểm tra user đã enroll chưa
@@ -58,3 +65,20 @@ type GetMyEnrollmentsResponse struct {
 	Enrollments []EnrollmentItem `json:"enrollments"`
 	Pagination  PaginationInfo   `json:"pagination"`
 }
+
+// CoursePrerequisiteItem - Thông tin course prerequisite
+type CoursePrerequisiteItem struct {
+	CourseId    uint   `json:"course_id"`
+	Title       string `json:"title"`
+	Slug        string `json:"slug"`
+	IsCompleted bool   `json:"is_completed"`
+}
+
+// CheckPrerequisitesResponse - Kết quả kiểm tra prerequisites của user cho course
+type CheckPrerequisitesResponse struct {
+	CourseId             uint                     `json:"course_id"`
+	PrerequisiteMode     string                   `json:"prerequisite_mode"`
+	Prerequisites        []CoursePrerequisiteItem `json:"prerequisites"`
+	MissingPrerequisites []CoursePrerequisiteItem `json:"missing_prerequisites"`
+	CanEnroll            bool                     `json:"can_enroll"`
+}
@@ -0,0 +1,277 @@
+package dto
+
+import "time"
+
+// Tên các domain event (lưu ở cột event_type của outbox)
+const (
+	EventUserRegistered      = "user.registered"
+	EventOrderPaid           = "order.paid"
+	EventOrderRefunded       = "order.refunded"
+	EventEnrollmentCreated   = "enrollment.created"
+	EventEnrollmentCompleted = "enrollment.completed"
+	EventLessonCompleted     = "lesson.completed"
+	EventLessonLaunched      = "lesson.launched"
+	EventLessonProgressed    = "lesson.progressed"
+	EventQuizSubmitted       = "quiz.submitted"
+	EventReviewCreated       = "review.created"
+
+	EventOrganizationInvoicePaid = "organization.invoice_paid"
+
+	EventSubscriptionPaid          = "subscription.paid"
+	EventSubscriptionPaymentFailed = "subscription.payment_failed"
+	EventSubscriptionCancelled     = "subscription.cancelled"
+	EventSubscriptionExpired       = "subscription.expired"
+
+	EventBundlePurchased = "bundle.purchased"
+)
+
+// DomainEvent là event được ghi vào outbox cùng transaction với thay đổi dữ liệu
+type DomainEvent interface {
+	EventType() string
+	AggregateId() uint
+}
+
+type UserRegisteredEvent struct {
+	TenantId uint   `json:"tenant_id"`
+	UserId   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	FullName string `json:"full_name"`
+}
+
+func (e UserRegisteredEvent) EventType() string { return EventUserRegistered }
+func (e UserRegisteredEvent) AggregateId() uint { return e.UserId }
+
+type OrderPaidEvent struct {
+	OrderId       uint      `json:"order_id"`
+	OrderCode     string    `json:"order_code"`
+	UserId        uint      `json:"user_id"`
+	CourseId      uint      `json:"course_id"`
+	CouponId      *uint     `json:"coupon_id,omitempty"`
+	FinalPrice    float64   `json:"final_price"`
+	PaymentMethod string    `json:"payment_method"`
+	PaidAt        time.Time `json:"paid_at"`
+
+	// Order thuộc một lần mua bundle (xem BundlePurchasedEvent)
+	BundlePurchaseId *uint `json:"bundle_purchase_id,omitempty"`
+}
+
+func (e OrderPaidEvent) EventType() string { return EventOrderPaid }
+func (e OrderPaidEvent) AggregateId() uint { return e.OrderId }
+
+type OrderRefundedEvent struct {
+	OrderId    uint      `json:"order_id"`
+	OrderCode  string    `json:"order_code"`
+	UserId     uint      `json:"user_id"`
+	CourseId   uint      `json:"course_id"`
+	FinalPrice float64   `json:"final_price"`
+	Reason     string    `json:"reason,omitempty"`
+	RefundedAt time.Time `json:"refunded_at"`
+}
+
+func (e OrderRefundedEvent) EventType() string { return EventOrderRefunded }
+func (e OrderRefundedEvent) AggregateId() uint { return e.OrderId }
+
+// OrganizationInvoicePaidEvent phát khi hóa đơn mua seat của organization được thanh toán
+type OrganizationInvoicePaidEvent struct {
+	InvoiceId      uint      `json:"invoice_id"`
+	InvoiceNumber  string    `json:"invoice_number"`
+	OrganizationId uint      `json:"organization_id"`
+	LicenseId      uint      `json:"license_id"`
+	Scope          string    `json:"scope"`
+	CourseId       *uint     `json:"course_id,omitempty"`
+	Seats          int       `json:"seats"`
+	Total          float64   `json:"total"`
+	PaymentMethod  string    `json:"payment_method"`
+	PaidAt         time.Time `json:"paid_at"`
+}
+
+func (e OrganizationInvoicePaidEvent) EventType() string { return EventOrganizationInvoicePaid }
+func (e OrganizationInvoicePaidEvent) AggregateId() uint { return e.InvoiceId }
+
+// SubscriptionPaidEvent phát khi thu tiền một kỳ subscription thành công (đăng ký mới hoặc gia hạn)
+type SubscriptionPaidEvent struct {
+	SubscriptionId uint      `json:"subscription_id"`
+	PaymentId      uint      `json:"payment_id"`
+	UserId         uint      `json:"user_id"`
+	PlanId         uint      `json:"plan_id"`
+	Amount         float64   `json:"amount"`
+	PaymentMethod  string    `json:"payment_method"`
+	PeriodStart    time.Time `json:"period_start"`
+	PeriodEnd      time.Time `json:"period_end"`
+	PaidAt         time.Time `json:"paid_at"`
+}
+
+func (e SubscriptionPaidEvent) EventType() string { return EventSubscriptionPaid }
+func (e SubscriptionPaidEvent) AggregateId() uint { return e.SubscriptionId }
+
+// SubscriptionPaymentFailedEvent phát khi gia hạn thất bại, subscription vẫn truy cập được đến GraceEndsAt
+type SubscriptionPaymentFailedEvent struct {
+	SubscriptionId uint      `json:"subscription_id"`
+	PaymentId      uint      `json:"payment_id"`
+	UserId         uint      `json:"user_id"`
+	PlanId         uint      `json:"plan_id"`
+	Amount         float64   `json:"amount"`
+	Reason         string    `json:"reason"`
+	GraceEndsAt    time.Time `json:"grace_ends_at"`
+}
+
+func (e SubscriptionPaymentFailedEvent) EventType() string { return EventSubscriptionPaymentFailed }
+func (e SubscriptionPaymentFailedEvent) AggregateId() uint { return e.SubscriptionId }
+
+// SubscriptionCancelledEvent phát khi user hủy subscription (AccessEndsAt là lúc hết quyền truy cập)
+type SubscriptionCancelledEvent struct {
+	SubscriptionId uint      `json:"subscription_id"`
+	UserId         uint      `json:"user_id"`
+	PlanId         uint      `json:"plan_id"`
+	CancelledAt    time.Time `json:"cancelled_at"`
+	AccessEndsAt   time.Time `json:"access_ends_at"`
+}
+
+func (e SubscriptionCancelledEvent) EventType() string { return EventSubscriptionCancelled }
+func (e SubscriptionCancelledEvent) AggregateId() uint { return e.SubscriptionId }
+
+// SubscriptionExpiredEvent phát khi subscription hết hiệu lực (hết kỳ sau khi hủy hoặc hết thời gian gia hạn)
+type SubscriptionExpiredEvent struct {
+	SubscriptionId uint      `json:"subscription_id"`
+	UserId         uint      `json:"user_id"`
+	PlanId         uint      `json:"plan_id"`
+	Reason         string    `json:"reason"` // cancelled, payment_failed
+	EndedAt        time.Time `json:"ended_at"`
+}
+
+func (e SubscriptionExpiredEvent) EventType() string { return EventSubscriptionExpired }
+func (e SubscriptionExpiredEvent) AggregateId() uint { return e.SubscriptionId }
+
+// BundlePurchasedEvent phát khi mua bundle thành công. Mỗi course được ghi danh còn có
+// OrderPaidEvent riêng (cùng BundlePurchaseId) mang phần giá đã phân bổ cho course đó.
+type BundlePurchasedEvent struct {
+	PurchaseId       uint      `json:"purchase_id"`
+	PurchaseCode     string    `json:"purchase_code"`
+	BundleId         uint      `json:"bundle_id"`
+	BundleTitle      string    `json:"bundle_title"`
+	BundleSlug       string    `json:"bundle_slug"`
+	UserId           uint      `json:"user_id"`
+	CourseIds        []uint    `json:"course_ids"`
+	SkippedCourseIds []uint    `json:"skipped_course_ids"`
+	FinalPrice       float64   `json:"final_price"`
+	PaymentMethod    string    `json:"payment_method"`
+	PaidAt           time.Time `json:"paid_at"`
+}
+
+func (e BundlePurchasedEvent) EventType() string { return EventBundlePurchased }
+func (e BundlePurchasedEvent) AggregateId() uint { return e.PurchaseId }
+
+type EnrollmentCreatedEvent struct {
+	EnrollmentId uint      `json:"enrollment_id"`
+	UserId       uint      `json:"user_id"`
+	CourseId     uint      `json:"course_id"`
+	OrderId      uint      `json:"order_id,omitempty"`
+	EnrolledAt   time.Time `json:"enrolled_at"`
+}
+
+func (e EnrollmentCreatedEvent) EventType() string { return EventEnrollmentCreated }
+func (e EnrollmentCreatedEvent) AggregateId() uint { return e.EnrollmentId }
+
+type EnrollmentCompletedEvent struct {
+	EnrollmentId uint      `json:"enrollment_id"`
+	UserId       uint      `json:"user_id"`
+	CourseId     uint      `json:"course_id"`
+	CompletedAt  time.Time `json:"completed_at"`
+}
+
+func (e EnrollmentCompletedEvent) EventType() string { return EventEnrollmentCompleted }
+func (e EnrollmentCompletedEvent) AggregateId() uint { return e.EnrollmentId }
+
+type LessonCompletedEvent struct {
+	UserId      uint      `json:"user_id"`
+	CourseId    uint      `json:"course_id"`
+	LessonId    uint      `json:"lesson_id"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+func (e LessonCompletedEvent) EventType() string { return EventLessonCompleted }
+func (e LessonCompletedEvent) AggregateId() uint { return e.LessonId }
+
+// LessonLaunchedEvent phát khi học viên bắt đầu xem lesson lần đầu
+type LessonLaunchedEvent struct {
+	UserId     uint      `json:"user_id"`
+	CourseId   uint      `json:"course_id"`
+	LessonId   uint      `json:"lesson_id"`
+	LaunchedAt time.Time `json:"launched_at"`
+}
+
+func (e LessonLaunchedEvent) EventType() string { return EventLessonLaunched }
+func (e LessonLaunchedEvent) AggregateId() uint { return e.LessonId }
+
+// LessonProgressedEvent phát khi thời lượng đã xem vượt qua một mốc (25%, 50%, 75%)
+type LessonProgressedEvent struct {
+	UserId        uint      `json:"user_id"`
+	CourseId      uint      `json:"course_id"`
+	LessonId      uint      `json:"lesson_id"`
+	Progress      int       `json:"progress"` // Phần trăm mốc đã đạt
+	WatchDuration int       `json:"watch_duration"`
+	ProgressedAt  time.Time `json:"progressed_at"`
+}
+
+func (e LessonProgressedEvent) EventType() string { return EventLessonProgressed }
+func (e LessonProgressedEvent) AggregateId() uint { return e.LessonId }
+
+type QuizSubmittedEvent struct {
+	AttemptId    uint      `json:"attempt_id"`
+	UserId       uint      `json:"user_id"`
+	QuizId       uint      `json:"quiz_id"`
+	LessonId     uint      `json:"lesson_id"`
+	CourseId     uint      `json:"course_id"`
+	Score        float64   `json:"score"`
+	PassingScore int       `json:"passing_score"`
+	IsPassed     bool      `json:"is_passed"`
+	SubmittedAt  time.Time `json:"submitted_at"`
+}
+
+func (e QuizSubmittedEvent) EventType() string { return EventQuizSubmitted }
+func (e QuizSubmittedEvent) AggregateId() uint { return e.AttemptId }
+
+type ReviewCreatedEvent struct {
+	ReviewId    uint   `json:"review_id"`
+	UserId      uint   `json:"user_id"`
+	CourseId    uint   `json:"course_id"`
+	Rating      int    `json:"rating"`
+	Comment     string `json:"comment"`
+	IsPublished bool   `json:"is_published"`
+}
+
+func (e ReviewCreatedEvent) EventType() string { return EventReviewCreated }
+func (e ReviewCreatedEvent) AggregateId() uint { return e.ReviewId }
+
+// ---------------- Admin: theo dõi outbox ----------------
+type GetOutboxEventsQueryRequest struct {
+	Page      int    `form:"page" binding:"omitempty,min=1"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status    string `form:"status" binding:"omitempty,oneof=pending processing processed failed"`
+	EventType string `form:"event_type" binding:"omitempty,max=100"`
+}
+
+type OutboxEventItem struct {
+	Id            uint       `json:"id"`
+	EventType     string     `json:"event_type"`
+	AggregateId   uint       `json:"aggregate_id"`
+	Payload       string     `json:"payload"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	ProcessedAt   *time.Time `json:"processed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredTo   []string   `json:"delivered_to"`
+}
+
+type GetOutboxEventsResponse struct {
+	Events     []OutboxEventItem `json:"events"`
+	Pagination PaginationInfo    `json:"pagination"`
+}
+
+type RetryOutboxEventResponse struct {
+	Message string `json:"message"`
+	Id      uint   `json:"id"`
+}
@@ -7,7 +7,7 @@ import "time"
 type GetInstructorCoursesQueryRequest struct {
 	Page    int    `form:"page" binding:"omitempty,min=1"`
 	Limit   int    `form:"limit" binding:"omitempty,min=1,max=100"`
-	Status  string `form:"status" binding:"omitempty,oneof=draft published archived"`
+	Status  string `form:"status" binding:"omitempty,oneof=draft pending_review published archived"`
 	Search  string `form:"search" binding:"omitempty,search"`
 	OrderBy string `form:"order_by" binding:"omitempty,oneof=created_at updated_at title enrolled_count rating_avg"`
 	SortBy  string `form:"sort_by" binding:"omitempty,oneof=asc desc"`
@@ -97,6 +97,9 @@ type UpdateCourseRequest struct {
 	DurationHours int      `json:"duration_hours" binding:"omitempty,min_int=0"`
 	Status        string   `json:"status" binding:"omitempty,course_status"`
 	IsFeatured    *bool    `json:"is_featured"`
+
+	// URL thumbnail đã upload qua presigned URL
+	ThumbnailURL string `json:"thumbnail_url" binding:"omitempty,url"`
 }
 
 type UpdateCourseResponse struct {
@@ -179,6 +182,12 @@ type CreateLessonRequest struct {
 	LessonOrder   int    `json:"lesson_order" binding:"required,min=1"`
 	IsPreview     bool   `json:"is_preview" binding:"omitempty"`
 	IsPublished   bool   `json:"is_published" binding:"omitempty"`
+
+	// Drip-feed & prerequisites
+	UnlockAfterDays           *int       `json:"unlock_after_days" binding:"omitempty,min=0"`
+	UnlockAt                  *time.Time `json:"unlock_at" binding:"omitempty"`
+	RequirePreviousCompletion bool       `json:"require_previous_completion" binding:"omitempty"`
+	PrerequisiteQuizId        *uint      `json:"prerequisite_quiz_id" binding:"omitempty"`
 }
 
 type CreateLessonResponse struct {
@@ -194,6 +203,11 @@ type CreateLessonResponse struct {
 	IsPreview     bool   `json:"is_preview"`
 	IsPublished   bool   `json:"is_published"`
 	CreatedAt     string `json:"created_at"`
+
+	UnlockAfterDays           *int       `json:"unlock_after_days"`
+	UnlockAt                  *time.Time `json:"unlock_at"`
+	RequirePreviousCompletion bool       `json:"require_previous_completion"`
+	PrerequisiteQuizId        *uint      `json:"prerequisite_quiz_id"`
 }
 
 type UpdateLessonRequest struct {
@@ -205,6 +219,13 @@ type UpdateLessonRequest struct {
 	LessonOrder   *int    `json:"lesson_order" binding:"omitempty,min=1"`
 	IsPreview     *bool   `json:"is_preview" binding:"omitempty"`
 	IsPublished   *bool   `json:"is_published" binding:"omitempty"`
+
+	// Drip-feed & prerequisites
+	UnlockAfterDays           *int       `json:"unlock_after_days" binding:"omitempty,min=0"` // 0 = bỏ lịch mở khóa
+	UnlockAt                  *time.Time `json:"unlock_at" binding:"omitempty"`
+	ClearUnlockAt             bool       `json:"clear_unlock_at" binding:"omitempty"`
+	RequirePreviousCompletion *bool      `json:"require_previous_completion" binding:"omitempty"`
+	PrerequisiteQuizId        *uint      `json:"prerequisite_quiz_id" binding:"omitempty"` // 0 = bỏ quiz bắt buộc
 }
 
 type UpdateLessonResponse struct {
@@ -220,6 +241,11 @@ type UpdateLessonResponse struct {
 	IsPreview     bool   `json:"is_preview"`
 	IsPublished   bool   `json:"is_published"`
 	UpdatedAt     string `json:"updated_at"`
+
+	UnlockAfterDays           *int       `json:"unlock_after_days"`
+	UnlockAt                  *time.Time `json:"unlock_at"`
+	RequirePreviousCompletion bool       `json:"require_previous_completion"`
+	PrerequisiteQuizId        *uint      `json:"prerequisite_quiz_id"`
 }
 
 type DeleteLessonResponse struct {
@@ -241,3 +267,22 @@ type ReorderLessonsResponse struct {
 	UpdatedCount int    `json:"updated_count"`
 	CourseId     uint   `json:"course_id"`
 }
+
+// PUT /api/v1/instructor/courses/:course_id/prerequisites
+type SetCoursePrerequisitesRequest struct {
+	CourseIds []uint `json:"course_ids" binding:"omitempty,max=20"`
+	Mode      string `json:"mode" binding:"required,oneof=warn block"`
+}
+
+type SetCoursePrerequisitesResponse struct {
+	CourseId         uint                     `json:"course_id"`
+	PrerequisiteMode string                   `json:"prerequisite_mode"`
+	Prerequisites    []CoursePrerequisiteItem `json:"prerequisites"`
+}
+
+// POST /api/v1/instructor/courses/:course_id/thumbnail
+type UploadThumbnailResponse struct {
+	Message      string `json:"message"`
+	CourseId     uint   `json:"course_id"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
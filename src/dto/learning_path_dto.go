@@ -0,0 +1,114 @@
+package dto
+
+import "time"
+
+// GET /api/v1/learning-paths - Query parameters
+type GetLearningPathsQueryRequest struct {
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=50"`
+	Search  string `form:"search" binding:"omitempty,search"`
+	OrderBy string `form:"order_by" binding:"omitempty,oneof=created_at title"`
+	SortBy  string `form:"sort_by" binding:"omitempty,oneof=asc desc"`
+}
+
+type LearningPathItem struct {
+	Id           uint      `json:"id"`
+	Title        string    `json:"title"`
+	Slug         string    `json:"slug"`
+	Description  string    `json:"description"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	CreatorName  string    `json:"creator_name"`
+	IsFeatured   bool      `json:"is_featured"`
+	TotalCourses int       `json:"total_courses"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type GetLearningPathsResponse struct {
+	LearningPaths []LearningPathItem `json:"learning_paths"`
+	Pagination    PaginationInfo     `json:"pagination"`
+}
+
+type LearningPathCourseItem struct {
+	CourseId      uint     `json:"course_id"`
+	Position      int      `json:"position"`
+	Title         string   `json:"title"`
+	Slug          string   `json:"slug"`
+	ThumbnailURL  string   `json:"thumbnail_url"`
+	Level         string   `json:"level"`
+	Price         float64  `json:"price"`
+	DiscountPrice *float64 `json:"discount_price"`
+	DurationHours int      `json:"duration_hours"`
+}
+
+type LearningPathDetail struct {
+	Id           uint                     `json:"id"`
+	Title        string                   `json:"title"`
+	Slug         string                   `json:"slug"`
+	Description  string                   `json:"description"`
+	ThumbnailURL string                   `json:"thumbnail_url"`
+	CreatedBy    uint                     `json:"created_by"`
+	CreatorName  string                   `json:"creator_name"`
+	Status       string                   `json:"status"`
+	IsFeatured   bool                     `json:"is_featured"`
+	Courses      []LearningPathCourseItem `json:"courses"`
+	CreatedAt    time.Time                `json:"created_at"`
+	UpdatedAt    time.Time                `json:"updated_at"`
+}
+
+// POST /api/v1/learning-paths
+type CreateLearningPathRequest struct {
+	Title        string `json:"title" binding:"required,min=5,max=200"`
+	Description  string `json:"description" binding:"omitempty,max=5000"`
+	ThumbnailURL string `json:"thumbnail_url" binding:"omitempty,url"`
+	Status       string `json:"status" binding:"omitempty,oneof=draft published"`
+	IsFeatured   bool   `json:"is_featured"`
+	CourseIds    []uint `json:"course_ids" binding:"omitempty,max=50"`
+}
+
+// PUT /api/v1/learning-paths/:id
+type UpdateLearningPathRequest struct {
+	Title        *string `json:"title" binding:"omitempty,min=5,max=200"`
+	Description  *string `json:"description" binding:"omitempty,max=5000"`
+	ThumbnailURL *string `json:"thumbnail_url" binding:"omitempty,url"`
+	Status       *string `json:"status" binding:"omitempty,oneof=draft published"`
+	IsFeatured   *bool   `json:"is_featured"`
+}
+
+// PUT /api/v1/learning-paths/:id/courses
+type SetLearningPathCoursesRequest struct {
+	CourseIds []uint `json:"course_ids" binding:"required,min=1,max=50"`
+}
+
+type DeleteLearningPathResponse struct {
+	Message string `json:"message"`
+}
+
+type LearningPathCourseProgress struct {
+	CourseId           uint    `json:"course_id"`
+	Title              string  `json:"title"`
+	Slug               string  `json:"slug"`
+	Position           int     `json:"position"`
+	IsEnrolled         bool    `json:"is_enrolled"`
+	IsCompleted        bool    `json:"is_completed"`
+	ProgressPercentage float64 `json:"progress_percentage"`
+}
+
+// GET /api/v1/learning-paths/:slug/progress
+type LearningPathProgressResponse struct {
+	LearningPathId     uint                         `json:"learning_path_id"`
+	Title              string                       `json:"title"`
+	TotalCourses       int                          `json:"total_courses"`
+	CompletedCourses   int                          `json:"completed_courses"`
+	ProgressPercentage float64                      `json:"progress_percentage"`
+	IsCompleted        bool                         `json:"is_completed"`
+	Courses            []LearningPathCourseProgress `json:"courses"`
+	Certificate        *LearningPathCertificateItem `json:"certificate,omitempty"`
+}
+
+type LearningPathCertificateItem struct {
+	Id               uint      `json:"id"`
+	LearningPathId   uint      `json:"learning_path_id"`
+	LearningPathName string    `json:"learning_path_name"`
+	CertificateCode  string    `json:"certificate_code"`
+	IssuedAt         time.Time `json:"issued_at"`
+}
@@ -12,8 +12,14 @@ type LessonItem struct {
 	VideoDuration int       `json:"video_duration"`
 	LessonOrder   int       `json:"lesson_order"`
 	IsPreview     bool      `json:"is_preview"`
+	LessonType    string    `json:"lesson_type"`  // standard, scorm
 	IsCompleted   bool      `json:"is_completed"` // Trạng thái hoàn thành của student
 	CreatedAt     time.Time `json:"created_at"`
+
+	// Drip-feed & prerequisites
+	IsLocked   bool       `json:"is_locked"`
+	UnlockAt   *time.Time `json:"unlock_at,omitempty"`   // Thời điểm lesson được mở (nếu bị khóa theo lịch)
+	LockReason string     `json:"lock_reason,omitempty"` // schedule, previous_lesson, quiz
 }
 
 type GetCourseLessonsResponse struct {
@@ -36,12 +42,28 @@ type LessonDetail struct {
 	VideoDuration int       `json:"video_duration"`
 	LessonOrder   int       `json:"lesson_order"`
 	IsPreview     bool      `json:"is_preview"`
+	LessonType    string    `json:"lesson_type"` // scorm: chạy qua GET /lessons/:lesson_id/scorm/launch
 	IsCompleted   bool      `json:"is_completed"`
 	LastPosition  int       `json:"last_position"`
 	WatchDuration int       `json:"watch_duration"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 
+	// Drip-feed & prerequisites
+	RequirePreviousCompletion bool  `json:"require_previous_completion"`
+	PrerequisiteQuizId        *uint `json:"prerequisite_quiz_id,omitempty"`
+
+	// Video HLS: video_url là signed URL khi video_status = ready
+	VideoStatus string `json:"video_status,omitempty"`
+	PosterURL   string `json:"poster_url,omitempty"`
+
+	// Tài liệu đính kèm và phụ đề
+	Attachments []LessonAttachmentItem `json:"attachments"`
+	Subtitles   []LessonSubtitleItem   `json:"subtitles"`
+
+	// Số ghi chú riêng của học viên trên lesson
+	NoteCount int `json:"note_count"`
+
 	// Navigation
 	PreviousLesson *LessonNavigation `json:"previous_lesson,omitempty"`
 	NextLesson     *LessonNavigation `json:"next_lesson,omitempty"`
@@ -0,0 +1,109 @@
+package dto
+
+import "time"
+
+// ---------------- Admin: đăng ký platform LTI ----------------
+type CreateLtiPlatformRequest struct {
+	Name          string   `json:"name" binding:"required,max=200"`
+	Issuer        string   `json:"issuer" binding:"required,url,max=500"`
+	ClientId      string   `json:"client_id" binding:"required,max=255"`
+	DeploymentIds []string `json:"deployment_ids" binding:"omitempty,max=50,dive,required,max=255"` // Bỏ trống = chấp nhận mọi deployment
+	AuthLoginURL  string   `json:"auth_login_url" binding:"required,url,max=500"`
+	AuthTokenURL  string   `json:"auth_token_url" binding:"omitempty,url,max=500"`
+	JwksURL       string   `json:"jwks_url" binding:"required,url,max=500"`
+	IsActive      *bool    `json:"is_active" binding:"omitempty"`
+}
+
+type UpdateLtiPlatformRequest struct {
+	Name          *string  `json:"name" binding:"omitempty,max=200"`
+	DeploymentIds []string `json:"deployment_ids" binding:"omitempty,max=50,dive,required,max=255"`
+	AuthLoginURL  *string  `json:"auth_login_url" binding:"omitempty,url,max=500"`
+	AuthTokenURL  *string  `json:"auth_token_url" binding:"omitempty,max=500"`
+	JwksURL       *string  `json:"jwks_url" binding:"omitempty,url,max=500"`
+	IsActive      *bool    `json:"is_active" binding:"omitempty"`
+}
+
+type GetLtiPlatformsQueryRequest struct {
+	Page     int   `form:"page" binding:"omitempty,min=1"`
+	Limit    int   `form:"limit" binding:"omitempty,min=1,max=100"`
+	IsActive *bool `form:"is_active" binding:"omitempty"`
+}
+
+type LtiPlatformItem struct {
+	Id            uint      `json:"id"`
+	Name          string    `json:"name"`
+	Issuer        string    `json:"issuer"`
+	ClientId      string    `json:"client_id"`
+	DeploymentIds []string  `json:"deployment_ids"`
+	AuthLoginURL  string    `json:"auth_login_url"`
+	AuthTokenURL  string    `json:"auth_token_url"`
+	JwksURL       string    `json:"jwks_url"`
+	IsActive      bool      `json:"is_active"`
+	CreatedBy     uint      `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type GetLtiPlatformsResponse struct {
+	Platforms  []LtiPlatformItem `json:"platforms"`
+	Pagination PaginationInfo    `json:"pagination"`
+}
+
+type DeleteLtiPlatformResponse struct {
+	Message string `json:"message"`
+	Id      uint   `json:"id"`
+}
+
+// LtiToolConfigResponse là các URL cần khai báo khi đăng ký tool trong LMS
+type LtiToolConfigResponse struct {
+	LoginURL       string   `json:"login_url"`
+	RedirectURIs   []string `json:"redirect_uris"`
+	LaunchURL      string   `json:"launch_url"`
+	DeepLinkingURL string   `json:"deep_linking_url"`
+	JwksURL        string   `json:"jwks_url"`
+	Scopes         []string `json:"scopes"`
+}
+
+// ---------------- OIDC login initiation & launch ----------------
+// Tham số có thể đến qua query (GET) hoặc form (POST)
+type LtiLoginRequest struct {
+	Issuer          string `form:"iss" binding:"required"`
+	LoginHint       string `form:"login_hint" binding:"required"`
+	TargetLinkURI   string `form:"target_link_uri" binding:"required"`
+	LtiMessageHint  string `form:"lti_message_hint"`
+	ClientId        string `form:"client_id"`
+	LtiDeploymentId string `form:"lti_deployment_id"`
+}
+
+type LtiLaunchRequest struct {
+	IdToken      string `form:"id_token" binding:"required"`
+	State        string `form:"state" binding:"required"`
+	BrowserState string `form:"-"` // State lưu trong cookie của trình duyệt đã bắt đầu login (handler điền)
+}
+
+// LtiLaunchResult cho handler biết chuyển hướng người dùng tới đâu sau launch.
+// Với login, State được handler lưu vào cookie đến StateExpiresAt để launch đối chiếu.
+type LtiLaunchResult struct {
+	RedirectURL    string
+	State          string
+	StateExpiresAt time.Time
+}
+
+// ---------------- Deep linking ----------------
+type LtiDeepLinkSessionResponse struct {
+	PlatformName   string    `json:"platform_name"`
+	ContextTitle   string    `json:"context_title"`
+	AcceptLineItem bool      `json:"accept_line_item"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+type LtiDeepLinkRequest struct {
+	CourseId uint  `json:"course_id" binding:"required"`
+	LessonId *uint `json:"lesson_id" binding:"omitempty"`
+}
+
+// LtiDeepLinkResponse: frontend POST form với field JWT tới ReturnURL
+type LtiDeepLinkResponse struct {
+	ReturnURL string `json:"return_url"`
+	JWT       string `json:"jwt"`
+}
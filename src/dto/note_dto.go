@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+type CreateNoteRequest struct {
+	Content  string `json:"content" binding:"required,max=5000"`
+	Position *int   `json:"position" binding:"omitempty,min=0"`
+}
+
+// UpdateNoteRequest thay thế nội dung và timestamp của ghi chú (position = null để bỏ bookmark)
+type UpdateNoteRequest struct {
+	Content  string `json:"content" binding:"required,max=5000"`
+	Position *int   `json:"position" binding:"omitempty,min=0"`
+}
+
+type LessonNoteItem struct {
+	Id            uint      `json:"id"`
+	CourseId      uint      `json:"course_id"`
+	CourseTitle   string    `json:"course_title,omitempty"`
+	LessonId      uint      `json:"lesson_id"`
+	LessonTitle   string    `json:"lesson_title"`
+	LessonSlug    string    `json:"lesson_slug"`
+	Content       string    `json:"content"`
+	Position      *int      `json:"position"`
+	PositionLabel string    `json:"position_label,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type GetLessonNotesResponse struct {
+	LessonId uint             `json:"lesson_id"`
+	Notes    []LessonNoteItem `json:"notes"`
+}
+
+type SearchNotesQueryRequest struct {
+	Q             string `form:"q" binding:"required,min=2,max=100"`
+	Page          int    `form:"page" binding:"omitempty,min=1"`
+	Limit         int    `form:"limit" binding:"omitempty,min=1,max=50"`
+	CourseId      *uint  `form:"course_id" binding:"omitempty,min=1"`
+	BookmarksOnly bool   `form:"bookmarks_only" binding:"omitempty"`
+}
+
+type SearchNotesResponse struct {
+	Notes      []LessonNoteItem `json:"notes"`
+	Pagination PaginationInfo   `json:"pagination"`
+}
+
+type DeleteNoteResponse struct {
+	Message string `json:"message"`
+	Id      uint   `json:"id"`
+}
+
+// NotesExport là file Markdown chứa toàn bộ ghi chú của học viên trong một course
+type NotesExport struct {
+	FileName string
+	Content  []byte
+}
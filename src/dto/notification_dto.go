@@ -0,0 +1,61 @@
+package dto
+
+import "time"
+
+type NotificationItem struct {
+	Id        uint       `json:"id"`
+	Type      string     `json:"type"`
+	Title     string     `json:"title"`
+	Message   string     `json:"message"`
+	Link      string     `json:"link,omitempty"`
+	IsRead    bool       `json:"is_read"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type GetNotificationsQueryRequest struct {
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=50"`
+	UnreadOnly bool   `form:"unread_only" binding:"omitempty"`
+	Type       string `form:"type" binding:"omitempty,max=50"`
+}
+
+type GetNotificationsResponse struct {
+	Notifications []NotificationItem `json:"notifications"`
+	UnreadCount   int                `json:"unread_count"`
+	Pagination    PaginationInfo     `json:"pagination"`
+}
+
+// MarkNotificationsReadRequest: bỏ trống ids để đánh dấu tất cả là đã đọc
+type MarkNotificationsReadRequest struct {
+	Ids []uint `json:"ids" binding:"omitempty,max=100,dive,min=1"`
+}
+
+type MarkNotificationsReadResponse struct {
+	Updated     int `json:"updated"`
+	UnreadCount int `json:"unread_count"`
+}
+
+type DeleteNotificationResponse struct {
+	Message string `json:"message"`
+	Id      uint   `json:"id"`
+}
+
+type NotificationPreferenceItem struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+}
+
+type GetNotificationPreferencesResponse struct {
+	Preferences []NotificationPreferenceItem `json:"preferences"`
+}
+
+type NotificationPreferenceUpdate struct {
+	Type    string `json:"type" binding:"required,max=50"`
+	Enabled *bool  `json:"enabled" binding:"required"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceUpdate `json:"preferences" binding:"required,min=1,dive"`
+}
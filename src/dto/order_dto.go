@@ -22,6 +22,9 @@ type CreateOrderResponse struct {
 	PaymentStatus  string    `json:"payment_status"`
 	CreatedAt      time.Time `json:"created_at"`
 	Message        string    `json:"message"`
+
+	// Prerequisites chưa hoàn thành (chỉ có khi course ở chế độ warn)
+	MissingPrerequisites []CoursePrerequisiteItem `json:"missing_prerequisites,omitempty"`
 }
 
 // Query request cho order history
@@ -44,6 +47,8 @@ type OrderHistoryItem struct {
 	PaymentStatus   string     `json:"payment_status"`
 	PaidAt          *time.Time `json:"paid_at"`
 	CreatedAt       time.Time  `json:"created_at"`
+
+	BundlePurchaseId *uint `json:"bundle_purchase_id,omitempty"` // Order thuộc một lần mua bundle
 }
 
 type GetOrderHistoryResponse struct {
@@ -68,6 +73,8 @@ type OrderDetailResponse struct {
 	PaidAt          *time.Time `json:"paid_at"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
+
+	BundlePurchaseId *uint `json:"bundle_purchase_id,omitempty"` // Order thuộc một lần mua bundle
 }
 
 // Request thanh toán order
@@ -139,6 +146,8 @@ type AdminOrderItem struct {
 	PaidAt          *time.Time `json:"paid_at"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
+
+	BundlePurchaseId *uint `json:"bundle_purchase_id,omitempty"` // Order thuộc một lần mua bundle
 }
 
 type GetAdminOrdersResponse struct {
@@ -0,0 +1,244 @@
+package dto
+
+import "time"
+
+// ---------------- Organization ----------------
+type CreateOrganizationRequest struct {
+	Name           string `json:"name" binding:"required,min=2,max=200"`
+	BillingName    string `json:"billing_name" binding:"omitempty,max=200"`
+	BillingEmail   string `json:"billing_email" binding:"omitempty,email,max=100"`
+	BillingAddress string `json:"billing_address" binding:"omitempty,max=1000"`
+	TaxId          string `json:"tax_id" binding:"omitempty,max=50"`
+}
+
+type UpdateOrganizationRequest struct {
+	Name           *string `json:"name" binding:"omitempty,min=2,max=200"`
+	BillingName    *string `json:"billing_name" binding:"omitempty,max=200"`
+	BillingEmail   *string `json:"billing_email" binding:"omitempty,email,max=100"`
+	BillingAddress *string `json:"billing_address" binding:"omitempty,max=1000"`
+	TaxId          *string `json:"tax_id" binding:"omitempty,max=50"`
+}
+
+type OrganizationItem struct {
+	Id             uint      `json:"id"`
+	Name           string    `json:"name"`
+	Slug           string    `json:"slug"`
+	BillingName    string    `json:"billing_name"`
+	BillingEmail   string    `json:"billing_email"`
+	BillingAddress string    `json:"billing_address"`
+	TaxId          string    `json:"tax_id"`
+	Status         string    `json:"status"`
+	MyRole         string    `json:"my_role,omitempty"`
+	MemberCount    int       `json:"member_count"`
+	CreatedBy      uint      `json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type GetMyOrganizationsResponse struct {
+	Organizations []OrganizationItem `json:"organizations"`
+}
+
+// ---------------- Members ----------------
+type AddOrganizationMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"omitempty,oneof=admin member"`
+}
+
+type UpdateOrganizationMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin member"`
+}
+
+type GetOrganizationMembersQueryRequest struct {
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Role   string `form:"role" binding:"omitempty,oneof=admin member"`
+	Search string `form:"search" binding:"omitempty,search"`
+}
+
+type OrganizationMemberItem struct {
+	UserId   uint      `json:"user_id"`
+	Username string    `json:"username"`
+	FullName string    `json:"full_name"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type GetOrganizationMembersResponse struct {
+	Members    []OrganizationMemberItem `json:"members"`
+	Pagination PaginationInfo           `json:"pagination"`
+}
+
+type RemoveOrganizationMemberResponse struct {
+	Message        string `json:"message"`
+	UserId         uint   `json:"user_id"`
+	ReclaimedSeats int    `json:"reclaimed_seats"`
+}
+
+// ---------------- Seat licenses ----------------
+type PurchaseSeatsRequest struct {
+	Scope    string `json:"scope" binding:"required,oneof=course catalog"`
+	CourseId *uint  `json:"course_id" binding:"omitempty"` // Bắt buộc khi scope = course
+	Seats    int    `json:"seats" binding:"required,min=1,max=10000"`
+}
+
+type OrganizationLicenseItem struct {
+	Id             uint       `json:"id"`
+	Scope          string     `json:"scope"`
+	CourseId       *uint      `json:"course_id"`
+	CourseTitle    string     `json:"course_title,omitempty"`
+	Seats          int        `json:"seats"`
+	SeatsUsed      int        `json:"seats_used"`
+	SeatsAvailable int        `json:"seats_available"`
+	UnitPrice      float64    `json:"unit_price"`
+	TotalPrice     float64    `json:"total_price"`
+	Status         string     `json:"status"`
+	ActivatedAt    *time.Time `json:"activated_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type GetOrganizationLicensesResponse struct {
+	Licenses []OrganizationLicenseItem `json:"licenses"`
+}
+
+type PurchaseSeatsResponse struct {
+	License OrganizationLicenseItem `json:"license"`
+	Invoice OrganizationInvoiceItem `json:"invoice"`
+	Message string                  `json:"message"`
+}
+
+type CancelLicenseResponse struct {
+	Message   string `json:"message"`
+	LicenseId uint   `json:"license_id"`
+}
+
+// ---------------- Seat assignments ----------------
+type AssignSeatRequest struct {
+	UserId   uint  `json:"user_id" binding:"required"`
+	CourseId *uint `json:"course_id" binding:"omitempty"` // Bắt buộc với license catalog
+}
+
+type SeatAssignmentItem struct {
+	Id           uint      `json:"id"`
+	LicenseId    uint      `json:"license_id"`
+	UserId       uint      `json:"user_id"`
+	FullName     string    `json:"full_name"`
+	CourseId     uint      `json:"course_id"`
+	CourseTitle  string    `json:"course_title"`
+	EnrollmentId uint      `json:"enrollment_id"`
+	AssignedAt   time.Time `json:"assigned_at"`
+}
+
+type GetSeatAssignmentsResponse struct {
+	Assignments []SeatAssignmentItem `json:"assignments"`
+}
+
+type ReclaimSeatResponse struct {
+	Message          string `json:"message"`
+	LicenseId        uint   `json:"license_id"`
+	UserId           uint   `json:"user_id"`
+	ReclaimedCourses int    `json:"reclaimed_courses"`
+}
+
+// ---------------- Invoices ----------------
+type GetOrganizationInvoicesQueryRequest struct {
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status string `form:"status" binding:"omitempty,oneof=open paid void"`
+}
+
+type OrganizationInvoiceItem struct {
+	Id               uint       `json:"id"`
+	OrganizationId   uint       `json:"organization_id"`
+	OrganizationName string     `json:"organization_name,omitempty"`
+	LicenseId        uint       `json:"license_id"`
+	InvoiceNumber    string     `json:"invoice_number"`
+	Description      string     `json:"description"`
+	Quantity         int        `json:"quantity"`
+	UnitPrice        float64    `json:"unit_price"`
+	Total            float64    `json:"total"`
+	Status           string     `json:"status"`
+	BillingName      string     `json:"billing_name"`
+	BillingEmail     string     `json:"billing_email"`
+	BillingAddress   string     `json:"billing_address"`
+	TaxId            string     `json:"tax_id"`
+	PaymentMethod    string     `json:"payment_method"`
+	IssuedAt         time.Time  `json:"issued_at"`
+	DueAt            time.Time  `json:"due_at"`
+	PaidAt           *time.Time `json:"paid_at"`
+}
+
+type GetOrganizationInvoicesResponse struct {
+	Invoices   []OrganizationInvoiceItem `json:"invoices"`
+	Pagination PaginationInfo            `json:"pagination"`
+}
+
+type PayInvoiceRequest struct {
+	PaymentMethod string `json:"payment_method" binding:"required,oneof=credit_card paypal momo zalopay bank_transfer"`
+}
+
+type PayInvoiceResponse struct {
+	Invoice OrganizationInvoiceItem `json:"invoice"`
+	Message string                  `json:"message"`
+}
+
+// ---------------- Team progress ----------------
+type GetTeamProgressQueryRequest struct {
+	Page     int  `form:"page" binding:"omitempty,min=1"`
+	Limit    int  `form:"limit" binding:"omitempty,min=1,max=100"`
+	CourseId uint `form:"course_id" binding:"omitempty"`
+	UserId   uint `form:"user_id" binding:"omitempty"`
+}
+
+type TeamProgressItem struct {
+	UserId             uint       `json:"user_id"`
+	FullName           string     `json:"full_name"`
+	Email              string     `json:"email"`
+	CourseId           uint       `json:"course_id"`
+	CourseTitle        string     `json:"course_title"`
+	TotalLessons       int        `json:"total_lessons"`
+	CompletedLessons   int        `json:"completed_lessons"`
+	ProgressPercentage float64    `json:"progress_percentage"`
+	Status             string     `json:"status"` // not_started, in_progress, completed
+	LastActivityAt     *time.Time `json:"last_activity_at"`
+	AssignedAt         time.Time  `json:"assigned_at"`
+}
+
+type TeamProgressSummary struct {
+	Members         int     `json:"members"`
+	SeatsPurchased  int     `json:"seats_purchased"`
+	SeatsUsed       int     `json:"seats_used"`
+	Assignments     int     `json:"assignments"`
+	NotStarted      int     `json:"not_started"`
+	InProgress      int     `json:"in_progress"`
+	Completed       int     `json:"completed"`
+	AverageProgress float64 `json:"average_progress"`
+}
+
+type GetTeamProgressResponse struct {
+	Summary    TeamProgressSummary `json:"summary"`
+	Progress   []TeamProgressItem  `json:"progress"`
+	Pagination PaginationInfo      `json:"pagination"`
+}
+
+// ---------------- Admin ----------------
+type GetOrganizationsQueryRequest struct {
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status string `form:"status" binding:"omitempty,oneof=active suspended"`
+	Search string `form:"search" binding:"omitempty,search"`
+}
+
+type GetOrganizationsResponse struct {
+	Organizations []OrganizationItem `json:"organizations"`
+	Pagination    PaginationInfo     `json:"pagination"`
+}
+
+type UpdateOrganizationStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active suspended"`
+}
+
+// MarkInvoicePaidRequest: admin ghi nhận thanh toán ngoài hệ thống (chuyển khoản theo hóa đơn)
+type MarkInvoicePaidRequest struct {
+	PaymentMethod string `json:"payment_method" binding:"required,oneof=credit_card paypal momo zalopay bank_transfer"`
+}
@@ -0,0 +1,70 @@
+package dto
+
+import "time"
+
+// ============ INSTRUCTOR QUIZ DTOs ============
+
+type CreateQuizRequest struct {
+	Title        string                      `json:"title" binding:"required,min=3,max=200"`
+	Description  string                      `json:"description" binding:"omitempty"`
+	PassingScore int                         `json:"passing_score" binding:"required,min=1,max=100"`
+	Questions    []CreateQuizQuestionRequest `json:"questions" binding:"required,min=1,dive"`
+}
+
+type CreateQuizQuestionRequest struct {
+	Question      string   `json:"question" binding:"required,min=3"`
+	Options       []string `json:"options" binding:"required,min=2,dive,required"`
+	CorrectOption int      `json:"correct_option" binding:"min=0"` // Index của đáp án đúng trong Options
+}
+
+type DeleteQuizResponse struct {
+	Message string `json:"message"`
+	Id      uint   `json:"id"`
+}
+
+// ============ STUDENT QUIZ DTOs ============
+
+type QuizQuestionItem struct {
+	Id            uint     `json:"id"`
+	Question      string   `json:"question"`
+	Options       []string `json:"options"`
+	QuestionOrder int      `json:"question_order"`
+}
+
+type QuizDetail struct {
+	Id             uint               `json:"id"`
+	CourseId       uint               `json:"course_id"`
+	LessonId       uint               `json:"lesson_id"`
+	Title          string             `json:"title"`
+	Description    string             `json:"description"`
+	PassingScore   int                `json:"passing_score"`
+	TotalQuestions int                `json:"total_questions"`
+	Questions      []QuizQuestionItem `json:"questions"`
+	CreatedAt      time.Time          `json:"created_at"`
+
+	// Kết quả của user hiện tại
+	AttemptCount int      `json:"attempt_count"`
+	BestScore    *float64 `json:"best_score,omitempty"`
+	IsPassed     bool     `json:"is_passed"`
+}
+
+type QuizAnswer struct {
+	QuestionId     uint `json:"question_id" binding:"required"`
+	SelectedOption int  `json:"selected_option" binding:"min=0"`
+}
+
+type SubmitQuizRequest struct {
+	Answers []QuizAnswer `json:"answers" binding:"required,min=1,dive"`
+}
+
+type SubmitQuizResponse struct {
+	AttemptId      uint      `json:"attempt_id"`
+	QuizId         uint      `json:"quiz_id"`
+	Score          float64   `json:"score"`
+	CorrectCount   int       `json:"correct_count"`
+	TotalQuestions int       `json:"total_questions"`
+	PassingScore   int       `json:"passing_score"`
+	IsPassed       bool      `json:"is_passed"`
+	SubmittedAt    time.Time `json:"submitted_at"`
+	Message        string    `json:"message"`
+}
@@ -1,16 +1,20 @@
 package dto
 
+import "time"
+
 type CreateReviewRequest struct {
 	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
 	Comment string `json:"comment" binding:"required,min=10,max=1000"`
 }
 
 type CreateReviewResponse struct {
-	Id       uint   `json:"id"`
-	CourseId uint   `json:"course_id"`
-	Rating   int    `json:"rating"`
-	Comment  string `json:"comment"`
-	Message  string `json:"message"`
+	Id               uint   `json:"id"`
+	CourseId         uint   `json:"course_id"`
+	Rating           int    `json:"rating"`
+	Comment          string `json:"comment"`
+	IsPublished      bool   `json:"is_published"`
+	ModerationStatus string `json:"moderation_status"`
+	Message          string `json:"message"`
 }
 
 type UpdateReviewRequest struct {
@@ -19,12 +23,115 @@ type UpdateReviewRequest struct {
 }
 
 type UpdateReviewResponse struct {
-	Id      uint   `json:"id"`
-	Rating  int    `json:"rating"`
-	Comment string `json:"comment"`
-	Message string `json:"message"`
+	Id               uint   `json:"id"`
+	Rating           int    `json:"rating"`
+	Comment          string `json:"comment"`
+	IsPublished      bool   `json:"is_published"`
+	ModerationStatus string `json:"moderation_status"`
+	Message          string `json:"message"`
 }
 
 type DeleteReviewResponse struct {
 	Message string `json:"message"`
 }
+
+// ---------------- Report ----------------
+type ReportReviewRequest struct {
+	Reason  string `json:"reason" binding:"required,oneof=spam offensive off_topic fake other"`
+	Details string `json:"details" binding:"omitempty,max=1000"`
+}
+
+type ReportReviewResponse struct {
+	ReviewId uint   `json:"review_id"`
+	Message  string `json:"message"`
+}
+
+// ---------------- Instructor reply ----------------
+type ReviewReplyRequest struct {
+	Content string `json:"content" binding:"required,min=2,max=2000"`
+}
+
+type ReviewReplyItem struct {
+	Id               uint      `json:"id"`
+	ReviewId         uint      `json:"review_id"`
+	InstructorId     uint      `json:"instructor_id"`
+	InstructorName   string    `json:"instructor_name"`
+	InstructorAvatar string    `json:"instructor_avatar"`
+	Content          string    `json:"content"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type DeleteReviewReplyResponse struct {
+	Message string `json:"message"`
+}
+
+// ---------------- Admin moderation ----------------
+type GetReviewModerationQueryRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status   string `form:"status" binding:"omitempty,oneof=pending hidden reported"`
+	CourseId uint   `form:"course_id" binding:"omitempty,min=1"`
+}
+
+type ModerationReviewItem struct {
+	Id               uint       `json:"id"`
+	CourseId         uint       `json:"course_id"`
+	CourseTitle      string     `json:"course_title"`
+	UserId           uint       `json:"user_id"`
+	UserName         string     `json:"user_name"`
+	Rating           int        `json:"rating"`
+	Comment          string     `json:"comment"`
+	IsPublished      bool       `json:"is_published"`
+	ModerationStatus string     `json:"moderation_status"`
+	ModerationReason string     `json:"moderation_reason"`
+	ModeratedBy      *uint      `json:"moderated_by"`
+	ModeratedAt      *time.Time `json:"moderated_at"`
+	OpenReports      int        `json:"open_reports"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type GetReviewModerationQueueResponse struct {
+	Reviews    []ModerationReviewItem `json:"reviews"`
+	Pagination PaginationInfo         `json:"pagination"`
+}
+
+type ModerateReviewRequest struct {
+	Action string `json:"action" binding:"required,oneof=hide restore"`
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
+
+type ModerateReviewResponse struct {
+	Review  ModerationReviewItem `json:"review"`
+	Message string               `json:"message"`
+}
+
+type ReviewReportItem struct {
+	Id         uint       `json:"id"`
+	UserId     uint       `json:"user_id"`
+	UserName   string     `json:"user_name"`
+	Reason     string     `json:"reason"`
+	Details    string     `json:"details"`
+	Status     string     `json:"status"`
+	ResolvedBy *uint      `json:"resolved_by"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type GetReviewReportsResponse struct {
+	ReviewId uint               `json:"review_id"`
+	Reports  []ReviewReportItem `json:"reports"`
+}
+
+// ---------------- Helpfulness vote ----------------
+type VoteReviewRequest struct {
+	Helpful *bool `json:"helpful" binding:"required"`
+}
+
+type VoteReviewResponse struct {
+	ReviewId        uint   `json:"review_id"`
+	MyVote          *bool  `json:"my_vote"`
+	HelpfulCount    int    `json:"helpful_count"`
+	NotHelpfulCount int    `json:"not_helpful_count"`
+	Message         string `json:"message"`
+}
@@ -0,0 +1,86 @@
+package dto
+
+import "time"
+
+// ---------------- Instructor ----------------
+type ScormPackageResponse struct {
+	Id           uint      `json:"id"`
+	LessonId     uint      `json:"lesson_id"`
+	CourseId     uint      `json:"course_id"`
+	Version      string    `json:"version"` // 1.2, 2004
+	Identifier   string    `json:"identifier"`
+	Title        string    `json:"title"`
+	LaunchPath   string    `json:"launch_path"`
+	MasteryScore *float64  `json:"mastery_score,omitempty"`
+	FileName     string    `json:"file_name"`
+	FileCount    int       `json:"file_count"`
+	TotalSize    int64     `json:"total_size"`
+	ScoCount     int       `json:"sco_count,omitempty"` // Chỉ có khi upload; package nhiều SCO chỉ chạy SCO đầu tiên
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/lessons/:id/scorm
+type DeleteScormPackageResponse struct {
+	Message  string `json:"message"`
+	LessonId uint   `json:"lesson_id"`
+}
+
+type GetScormAttemptsQueryRequest struct {
+	Page             int    `form:"page" binding:"omitempty,min=1"`
+	Limit            int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	CompletionStatus string `form:"completion_status" binding:"omitempty,oneof=completed incomplete"`
+}
+
+type ScormAttemptItem struct {
+	UserId           uint       `json:"user_id"`
+	FullName         string     `json:"full_name"`
+	Email            string     `json:"email"`
+	CompletionStatus string     `json:"completion_status"`
+	SuccessStatus    string     `json:"success_status"`
+	ScoreRaw         *float64   `json:"score_raw"`
+	ScoreScaled      *float64   `json:"score_scaled"`
+	TotalTime        float64    `json:"total_time"` // Giây
+	CommitCount      int        `json:"commit_count"`
+	LastCommittedAt  *time.Time `json:"last_committed_at"`
+	CompletedAt      *time.Time `json:"completed_at"`
+}
+
+type GetScormAttemptsResponse struct {
+	Package    ScormPackageResponse `json:"package"`
+	Attempts   []ScormAttemptItem   `json:"attempts"`
+	Pagination PaginationInfo       `json:"pagination"`
+}
+
+// ---------------- Runtime ----------------
+// ScormLaunchResponse chứa URL launch (signed) và dữ liệu cmi để API adapter phía client khởi tạo
+type ScormLaunchResponse struct {
+	LessonId  uint              `json:"lesson_id"`
+	PackageId uint              `json:"package_id"`
+	Version   string            `json:"version"`
+	APIName   string            `json:"api_name"` // API (1.2) hoặc API_1484_11 (2004)
+	LaunchURL string            `json:"launch_url"`
+	ExpiresAt time.Time         `json:"expires_at"`
+	CanCommit bool              `json:"can_commit"` // false khi xem thử lesson preview mà chưa enroll
+	Values    map[string]string `json:"values"`
+}
+
+// POST /api/v1/lessons/:lesson_id/scorm/commit - LMSCommit/Commit, finish = LMSFinish/Terminate
+type ScormCommitRequest struct {
+	Values map[string]string `json:"values"`
+	Finish bool              `json:"finish"`
+}
+
+type ScormRuntimeResponse struct {
+	LessonId         uint              `json:"lesson_id"`
+	PackageId        uint              `json:"package_id"`
+	Version          string            `json:"version"`
+	CompletionStatus string            `json:"completion_status"`
+	SuccessStatus    string            `json:"success_status"`
+	ScoreRaw         *float64          `json:"score_raw"`
+	ScoreScaled      *float64          `json:"score_scaled"`
+	TotalTime        float64           `json:"total_time"` // Giây
+	LessonCompleted  bool              `json:"lesson_completed"`
+	CommittedAt      time.Time         `json:"committed_at"`
+	Values           map[string]string `json:"values"`
+}
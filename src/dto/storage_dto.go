@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+// POST /api/v1/storage/presign
+type PresignUploadRequest struct {
+	Purpose     string `json:"purpose" binding:"required,oneof=avatar thumbnail"`
+	FileName    string `json:"file_name" binding:"required,max=255"`
+	ContentType string `json:"content_type" binding:"required,oneof=image/jpeg image/png"`
+	FileSize    int64  `json:"file_size" binding:"required,min=1"`
+}
+
+type PresignUploadResponse struct {
+	Key       string            `json:"key"`
+	Method    string            `json:"method"`
+	UploadURL string            `json:"upload_url"`
+	Headers   map[string]string `json:"headers"`
+	PublicURL string            `json:"public_url"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
@@ -0,0 +1,140 @@
+package dto
+
+import "time"
+
+// ---------------- Subscription plans ----------------
+type CreateSubscriptionPlanRequest struct {
+	Name            string   `json:"name" binding:"required,min=2,max=100"`
+	Description     string   `json:"description" binding:"omitempty,max=2000"`
+	Interval        string   `json:"interval" binding:"required,oneof=month year"`
+	Price           float64  `json:"price" binding:"gte=0"`
+	TrialDays       int      `json:"trial_days" binding:"omitempty,min=0,max=90"`
+	GracePeriodDays int      `json:"grace_period_days" binding:"omitempty,min=0,max=30"`
+	Scope           string   `json:"scope" binding:"required,oneof=all tagged"`
+	Tags            []string `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
+}
+
+// Đổi giá/chu kỳ chỉ áp dụng cho subscriber mới, subscription hiện có giữ giá lúc đăng ký
+type UpdateSubscriptionPlanRequest struct {
+	Name            *string  `json:"name" binding:"omitempty,min=2,max=100"`
+	Description     *string  `json:"description" binding:"omitempty,max=2000"`
+	Price           *float64 `json:"price" binding:"omitempty,gte=0"`
+	TrialDays       *int     `json:"trial_days" binding:"omitempty,min=0,max=90"`
+	GracePeriodDays *int     `json:"grace_period_days" binding:"omitempty,min=0,max=30"`
+	Scope           *string  `json:"scope" binding:"omitempty,oneof=all tagged"`
+	Tags            []string `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
+	Status          *string  `json:"status" binding:"omitempty,oneof=active archived"`
+}
+
+type GetSubscriptionPlansQueryRequest struct {
+	Interval string `form:"interval" binding:"omitempty,oneof=month year"`
+	Status   string `form:"status" binding:"omitempty,oneof=active archived"`
+}
+
+type SubscriptionPlanItem struct {
+	Id              uint      `json:"id"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	Interval        string    `json:"interval"`
+	Price           float64   `json:"price"`
+	TrialDays       int       `json:"trial_days"`
+	GracePeriodDays int       `json:"grace_period_days"`
+	Scope           string    `json:"scope"`
+	Tags            []string  `json:"tags"`
+	Status          string    `json:"status"`
+	Subscribers     *int      `json:"subscribers,omitempty"` // Chỉ trả về cho admin
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type GetSubscriptionPlansResponse struct {
+	Plans []SubscriptionPlanItem `json:"plans"`
+}
+
+// ---------------- Course tags ----------------
+// PUT /api/v1/admin/courses/:course_id/tags
+type SetCourseTagsRequest struct {
+	Tags []string `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
+}
+
+type CourseTagsResponse struct {
+	CourseId uint     `json:"course_id"`
+	Tags     []string `json:"tags"`
+}
+
+// ---------------- Subscriptions ----------------
+type SubscribeRequest struct {
+	PlanId        uint   `json:"plan_id" binding:"required"`
+	PaymentMethod string `json:"payment_method" binding:"required,oneof=credit_card paypal momo zalopay bank_transfer"`
+}
+
+type RetrySubscriptionPaymentRequest struct {
+	PaymentMethod string `json:"payment_method" binding:"omitempty,oneof=credit_card paypal momo zalopay bank_transfer"`
+}
+
+type SubscriptionItem struct {
+	Id                 uint                 `json:"id"`
+	UserId             uint                 `json:"user_id"`
+	Username           string               `json:"username,omitempty"`
+	Email              string               `json:"email,omitempty"`
+	Plan               SubscriptionPlanItem `json:"plan"`
+	Status             string               `json:"status"`
+	Price              float64              `json:"price"`
+	Interval           string               `json:"interval"`
+	PaymentMethod      string               `json:"payment_method"`
+	CurrentPeriodStart time.Time            `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time            `json:"current_period_end"`
+	TrialEndsAt        *time.Time           `json:"trial_ends_at"`
+	GraceEndsAt        *time.Time           `json:"grace_ends_at"`
+	CancelAtPeriodEnd  bool                 `json:"cancel_at_period_end"`
+	CancelledAt        *time.Time           `json:"cancelled_at"`
+	EndedAt            *time.Time           `json:"ended_at"`
+	CreatedAt          time.Time            `json:"created_at"`
+}
+
+type SubscriptionResponse struct {
+	Subscription SubscriptionItem `json:"subscription"`
+	Message      string           `json:"message"`
+}
+
+type GetMySubscriptionResponse struct {
+	Subscription *SubscriptionItem `json:"subscription"` // null nếu user chưa có subscription hiệu lực
+}
+
+type SubscriptionPaymentItem struct {
+	Id             uint       `json:"id"`
+	SubscriptionId uint       `json:"subscription_id"`
+	PlanId         uint       `json:"plan_id"`
+	Amount         float64    `json:"amount"`
+	PeriodStart    time.Time  `json:"period_start"`
+	PeriodEnd      time.Time  `json:"period_end"`
+	Status         string     `json:"status"`
+	PaymentMethod  string     `json:"payment_method"`
+	TransactionId  string     `json:"transaction_id"`
+	FailureReason  string     `json:"failure_reason,omitempty"`
+	PaidAt         *time.Time `json:"paid_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type GetSubscriptionPaymentsQueryRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type GetSubscriptionPaymentsResponse struct {
+	Payments   []SubscriptionPaymentItem `json:"payments"`
+	Pagination PaginationInfo            `json:"pagination"`
+}
+
+// ---------------- Admin ----------------
+type GetSubscriptionsQueryRequest struct {
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status string `form:"status" binding:"omitempty,oneof=trialing active past_due cancelled expired"`
+	PlanId uint   `form:"plan_id" binding:"omitempty"`
+	UserId uint   `form:"user_id" binding:"omitempty"`
+}
+
+type GetSubscriptionsResponse struct {
+	Subscriptions []SubscriptionItem `json:"subscriptions"`
+	Pagination    PaginationInfo     `json:"pagination"`
+}
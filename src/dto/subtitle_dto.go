@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+type LessonSubtitleItem struct {
+	Id           uint      `json:"id"`
+	Language     string    `json:"language"`
+	Label        string    `json:"label"`
+	SourceFormat string    `json:"source_format"`
+	CueCount     int       `json:"cue_count"`
+	URL          string    `json:"url,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/lessons/:id/subtitles/:subtitle_id
+type DeleteSubtitleResponse struct {
+	Message string `json:"message"`
+	Id      uint   `json:"id"`
+}
+
+// TranscriptMatch là một đoạn transcript khớp từ khóa tìm kiếm, client dùng start_time để tua tới
+type TranscriptMatch struct {
+	CourseId    uint    `json:"course_id"`
+	LessonId    uint    `json:"lesson_id"`
+	LessonTitle string  `json:"lesson_title"`
+	LessonSlug  string  `json:"lesson_slug"`
+	Language    string  `json:"language"`
+	StartTime   float64 `json:"start_time"`
+	EndTime     float64 `json:"end_time"`
+	Text        string  `json:"text"`
+}
@@ -0,0 +1,123 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ---------------- Tenant branding & settings ----------------
+// TenantBrandingResponse là cấu hình public để frontend white-label tự dựng giao diện
+type TenantBrandingResponse struct {
+	Slug           string          `json:"slug"`
+	Name           string          `json:"name"`
+	FrontendURL    string          `json:"frontend_url"`
+	SupportEmail   string          `json:"support_email"`
+	LogoURL        string          `json:"logo_url"`
+	FaviconURL     string          `json:"favicon_url"`
+	PrimaryColor   string          `json:"primary_color"`
+	SecondaryColor string          `json:"secondary_color"`
+	Settings       json.RawMessage `json:"settings"`
+}
+
+type UpdateTenantSettingsRequest struct {
+	Name             *string          `json:"name" binding:"omitempty,min=2,max=200"`
+	FrontendURL      *string          `json:"frontend_url" binding:"omitempty,url,max=255"`
+	EmailFromName    *string          `json:"email_from_name" binding:"omitempty,max=100"`
+	EmailFromAddress *string          `json:"email_from_address" binding:"omitempty,email,max=100"`
+	SupportEmail     *string          `json:"support_email" binding:"omitempty,email,max=100"`
+	LogoURL          *string          `json:"logo_url" binding:"omitempty,url,max=255"`
+	FaviconURL       *string          `json:"favicon_url" binding:"omitempty,url,max=255"`
+	PrimaryColor     *string          `json:"primary_color" binding:"omitempty,hexcolor"`
+	SecondaryColor   *string          `json:"secondary_color" binding:"omitempty,hexcolor"`
+	Settings         *json.RawMessage `json:"settings"`
+	XapiLrsKey       *string          `json:"xapi_lrs_key" binding:"omitempty,min=8,max=100"`     // Rỗng để tắt LRS tích hợp
+	XapiLrsSecret    *string          `json:"xapi_lrs_secret" binding:"omitempty,min=16,max=200"` // Chỉ lưu hash
+}
+
+type TenantSettingsResponse struct {
+	TenantBrandingResponse
+	EmailFromName    string `json:"email_from_name"`
+	EmailFromAddress string `json:"email_from_address"`
+	XapiLrsKey       string `json:"xapi_lrs_key"`
+	XapiLrsEnabled   bool   `json:"xapi_lrs_enabled"`
+}
+
+// ---------------- Super-admin: tenants ----------------
+type CreateTenantRequest struct {
+	Name          string   `json:"name" binding:"required,min=2,max=200"`
+	Slug          string   `json:"slug" binding:"required,slug,max=100"`
+	FrontendURL   string   `json:"frontend_url" binding:"omitempty,url,max=255"`
+	Domains       []string `json:"domains" binding:"omitempty,max=10,dive,hostname"`
+	AdminEmail    string   `json:"admin_email" binding:"required,email,max=100"`
+	AdminUsername string   `json:"admin_username" binding:"required,min=3,max=50"`
+	AdminPassword string   `json:"admin_password" binding:"required,min=8,max=100"`
+	AdminFullName string   `json:"admin_full_name" binding:"omitempty,max=100"`
+}
+
+type UpdateTenantRequest struct {
+	Name   *string `json:"name" binding:"omitempty,min=2,max=200"`
+	Status *string `json:"status" binding:"omitempty,oneof=active suspended"`
+}
+
+type GetTenantsQueryRequest struct {
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status string `form:"status" binding:"omitempty,oneof=active suspended"`
+	Search string `form:"search" binding:"omitempty,search"`
+}
+
+type TenantDomainItem struct {
+	Id        uint      `json:"id"`
+	Hostname  string    `json:"hostname"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TenantItem struct {
+	Id          uint               `json:"id"`
+	Slug        string             `json:"slug"`
+	Name        string             `json:"name"`
+	Status      string             `json:"status"`
+	FrontendURL string             `json:"frontend_url"`
+	Domains     []TenantDomainItem `json:"domains"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+type GetTenantsResponse struct {
+	Tenants    []TenantItem   `json:"tenants"`
+	Pagination PaginationInfo `json:"pagination"`
+}
+
+type AddTenantDomainRequest struct {
+	Hostname string `json:"hostname" binding:"required,hostname,max=255"`
+}
+
+// ---------------- Super-admin: cross-tenant analytics ----------------
+type CrossTenantAnalyticsRequest struct {
+	StartDate string `form:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate   string `form:"end_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+type TenantAnalyticsItem struct {
+	TenantId         uint    `json:"tenant_id"`
+	TenantSlug       string  `json:"tenant_slug"`
+	TenantName       string  `json:"tenant_name"`
+	Status           string  `json:"status"`
+	TotalUsers       int     `json:"total_users"`
+	NewUsers         int     `json:"new_users"`
+	Instructors      int     `json:"instructors"`
+	Courses          int     `json:"courses"`
+	PublishedCourses int     `json:"published_courses"`
+	Enrollments      int     `json:"enrollments"`
+	Orders           int     `json:"orders"`
+	Revenue          float64 `json:"revenue"`
+}
+
+type CrossTenantAnalyticsResponse struct {
+	StartDate    string                `json:"start_date"`
+	EndDate      string                `json:"end_date"`
+	Tenants      []TenantAnalyticsItem `json:"tenants"`
+	TotalUsers   int                   `json:"total_users"`
+	TotalCourses int                   `json:"total_courses"`
+	TotalOrders  int                   `json:"total_orders"`
+	TotalRevenue float64               `json:"total_revenue"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// POST /api/v1/instructor/courses/:course_id/lessons/:id/video/uploads
+type InitVideoUploadRequest struct {
+	FileName  string `json:"file_name" binding:"required,max=255"`
+	FileSize  int64  `json:"file_size" binding:"required,min=1"`
+	ChunkSize int64  `json:"chunk_size" binding:"omitempty,min=1"`
+}
+
+type VideoUploadResponse struct {
+	UploadId       string    `json:"upload_id"`
+	LessonId       uint      `json:"lesson_id"`
+	FileName       string    `json:"file_name"`
+	FileSize       int64     `json:"file_size"`
+	ChunkSize      int64     `json:"chunk_size"`
+	TotalChunks    int       `json:"total_chunks"`
+	ReceivedChunks []int     `json:"received_chunks"`
+	Status         string    `json:"status"`
+	ErrorMessage   string    `json:"error_message,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// GET /api/v1/lessons/:lesson_id/playback
+type VideoPlaybackResponse struct {
+	LessonId      uint      `json:"lesson_id"`
+	Format        string    `json:"format"`
+	PlaybackURL   string    `json:"playback_url"`
+	PosterURL     string    `json:"poster_url"`
+	VideoDuration int       `json:"video_duration"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
@@ -0,0 +1,101 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookPayload là body JSON gửi tới endpoint. Id giữ nguyên khi retry/replay để bên nhận chống trùng.
+type WebhookPayload struct {
+	Id        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+type WebhookEventTypeItem struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+type GetWebhookEventTypesResponse struct {
+	EventTypes []WebhookEventTypeItem `json:"event_types"`
+}
+
+type CreateWebhookRequest struct {
+	Url         string   `json:"url" binding:"required,url,max=500"`
+	Description string   `json:"description" binding:"omitempty,max=255"`
+	EventTypes  []string `json:"event_types" binding:"omitempty,max=20,dive,required,max=100"` // Bỏ trống = nhận tất cả event
+	IsActive    *bool    `json:"is_active" binding:"omitempty"`
+}
+
+type UpdateWebhookRequest struct {
+	Url         *string  `json:"url" binding:"omitempty,url,max=500"`
+	Description *string  `json:"description" binding:"omitempty,max=255"`
+	EventTypes  []string `json:"event_types" binding:"omitempty,max=20,dive,required,max=100"` // Gửi [] để nhận tất cả event
+	IsActive    *bool    `json:"is_active" binding:"omitempty"`
+}
+
+type GetWebhooksQueryRequest struct {
+	Page     int   `form:"page" binding:"omitempty,min=1"`
+	Limit    int   `form:"limit" binding:"omitempty,min=1,max=100"`
+	IsActive *bool `form:"is_active" binding:"omitempty"`
+}
+
+type WebhookItem struct {
+	Id          uint      `json:"id"`
+	Url         string    `json:"url"`
+	Description string    `json:"description"`
+	EventTypes  []string  `json:"event_types"`
+	IsActive    bool      `json:"is_active"`
+	SecretHint  string    `json:"secret_hint"` // 4 ký tự cuối của secret
+	CreatedBy   uint      `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookSecretResponse trả về secret đầy đủ - chỉ khi tạo mới hoặc rotate
+type WebhookSecretResponse struct {
+	WebhookItem
+	Secret string `json:"secret"`
+}
+
+type GetWebhooksResponse struct {
+	Webhooks   []WebhookItem  `json:"webhooks"`
+	Pagination PaginationInfo `json:"pagination"`
+}
+
+type DeleteWebhookResponse struct {
+	Message string `json:"message"`
+	Id      uint   `json:"id"`
+}
+
+type GetWebhookDeliveriesQueryRequest struct {
+	Page      int    `form:"page" binding:"omitempty,min=1"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status    string `form:"status" binding:"omitempty,oneof=pending processing succeeded failed"`
+	EventType string `form:"event_type" binding:"omitempty,max=100"`
+}
+
+type WebhookDeliveryItem struct {
+	Id             uint            `json:"id"`
+	EndpointId     uint            `json:"endpoint_id"`
+	EventId        *uint           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	ResponseStatus int             `json:"response_status"`
+	ResponseBody   string          `json:"response_body,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	DurationMs     int             `json:"duration_ms"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	ReplayOfId     *uint           `json:"replay_of_id"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type GetWebhookDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryItem `json:"deliveries"`
+	Pagination PaginationInfo        `json:"pagination"`
+}
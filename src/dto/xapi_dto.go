@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ---------------- xAPI statement (phát từ domain event) ----------------
+type XapiAccount struct {
+	HomePage string `json:"homePage"`
+	Name     string `json:"name"`
+}
+
+type XapiAgent struct {
+	ObjectType string       `json:"objectType"`
+	Name       string       `json:"name,omitempty"`
+	Account    *XapiAccount `json:"account,omitempty"`
+}
+
+type XapiVerb struct {
+	Id      string            `json:"id"`
+	Display map[string]string `json:"display"`
+}
+
+type XapiActivityDefinition struct {
+	Name map[string]string `json:"name,omitempty"`
+	Type string            `json:"type"`
+}
+
+type XapiActivity struct {
+	ObjectType string                  `json:"objectType"`
+	Id         string                  `json:"id"`
+	Definition *XapiActivityDefinition `json:"definition,omitempty"`
+}
+
+type XapiScore struct {
+	Scaled *float64 `json:"scaled,omitempty"`
+	Raw    *float64 `json:"raw,omitempty"`
+	Min    *float64 `json:"min,omitempty"`
+	Max    *float64 `json:"max,omitempty"`
+}
+
+type XapiResult struct {
+	Score      *XapiScore             `json:"score,omitempty"`
+	Success    *bool                  `json:"success,omitempty"`
+	Completion *bool                  `json:"completion,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+type XapiContextActivities struct {
+	Parent   []XapiActivity `json:"parent,omitempty"`
+	Grouping []XapiActivity `json:"grouping,omitempty"`
+}
+
+type XapiContext struct {
+	Platform          string                 `json:"platform,omitempty"`
+	ContextActivities *XapiContextActivities `json:"contextActivities,omitempty"`
+}
+
+type XapiStatement struct {
+	Id        string       `json:"id"`
+	Actor     XapiAgent    `json:"actor"`
+	Verb      XapiVerb     `json:"verb"`
+	Object    XapiActivity `json:"object"`
+	Result    *XapiResult  `json:"result,omitempty"`
+	Context   *XapiContext `json:"context,omitempty"`
+	Timestamp time.Time    `json:"timestamp"`
+}
+
+// ---------------- Built-in LRS: /xapi ----------------
+// XapiVersion là version xAPI mà platform gửi và LRS tích hợp hỗ trợ (header X-Experience-API-Version)
+const XapiVersion = "1.0.3"
+
+// Tên tham số theo spec xAPI (camelCase)
+type GetXapiStatementsQueryRequest struct {
+	StatementId       string `form:"statementId" binding:"omitempty,uuid"`
+	VoidedStatementId string `form:"voidedStatementId" binding:"omitempty,uuid"`
+	Agent             string `form:"agent" binding:"omitempty,max=2000"` // JSON của Agent/Group
+	Verb              string `form:"verb" binding:"omitempty,max=500"`
+	Activity          string `form:"activity" binding:"omitempty,max=2000"`
+	Registration      string `form:"registration" binding:"omitempty,uuid"`
+	Since             string `form:"since"`
+	Until             string `form:"until"`
+	Limit             int    `form:"limit" binding:"omitempty,min=0"`
+	Ascending         bool   `form:"ascending"`
+	Cursor            uint   `form:"cursor"` // Dùng trong URL "more", không thuộc spec
+}
+
+type XapiStatementResult struct {
+	Statements []json.RawMessage `json:"statements"`
+	More       string            `json:"more"`
+}
+
+type XapiAboutResponse struct {
+	Version []string `json:"version"`
+}
@@ -207,6 +207,12 @@ func (ah *AdminHandler) GetCourses(ctx *gin.Context) {
 
 // PUT /api/v1/admin/courses/:course_id/status - Thay đổi trạng thái course (Admin)
 func (ah *AdminHandler) ChangeCourseStatus(ctx *gin.Context) {
+	adminId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
 	// Lấy course ID từ URL parameter
 	courseIdParam := ctx.Param("course_id")
 	if courseIdParam == "" {
@@ -229,7 +235,7 @@ func (ah *AdminHandler) ChangeCourseStatus(ctx *gin.Context) {
 	}
 
 	// Gọi service để thay đổi status
-	response, err := ah.service.ChangeCourseStatus(uint(courseId), &req)
+	response, err := ah.service.ChangeCourseStatus(adminId.(uint), uint(courseId), &req)
 	if err != nil {
 		utils.ResponseError(ctx, err)
 		return
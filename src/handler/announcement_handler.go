@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Ảnh GIF 1x1 trong suốt cho tracking pixel của email
+var transparentPixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+type AnnouncementHandler struct {
+	service service.AnnouncementService
+}
+
+func NewAnnouncementHandler(service service.AnnouncementService) *AnnouncementHandler {
+	return &AnnouncementHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/instructor/courses/:course_id/announcements - Danh sách announcement của course kèm số liệu đọc/mở
+func (ah *AnnouncementHandler) GetCourseAnnouncements(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.GetCourseAnnouncementsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ah.service.GetCourseAnnouncements(userId.(uint), uint(courseId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/announcements - Tạo announcement (gửi ngay hoặc theo lịch)
+func (ah *AnnouncementHandler) CreateAnnouncement(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.CreateAnnouncementRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ah.service.CreateAnnouncement(userId.(uint), uint(courseId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// PUT /api/v1/instructor/courses/:course_id/announcements/:announcement_id - Sửa announcement chưa gửi
+func (ah *AnnouncementHandler) UpdateAnnouncement(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	announcementId, err := strconv.ParseUint(ctx.Param("announcement_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid announcement Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.UpdateAnnouncementRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ah.service.UpdateAnnouncement(userId.(uint), uint(courseId), uint(announcementId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/announcements/:announcement_id - Xóa/hủy announcement
+func (ah *AnnouncementHandler) DeleteAnnouncement(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	announcementId, err := strconv.ParseUint(ctx.Param("announcement_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid announcement Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := ah.service.DeleteAnnouncement(userId.(uint), uint(courseId), uint(announcementId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/announcements - Feed announcement của học viên
+func (ah *AnnouncementHandler) GetFeed(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.GetAnnouncementFeedQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ah.service.GetFeed(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/announcements/:announcement_id - Xem announcement (đánh dấu đã đọc)
+func (ah *AnnouncementHandler) GetAnnouncement(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	announcementId, err := strconv.ParseUint(ctx.Param("announcement_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid announcement Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := ah.service.GetAnnouncement(userId.(uint), uint(announcementId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/courses/course_id/:course_id/announcements/subscription - Bật/tắt email announcement của course
+func (ah *AnnouncementHandler) UpdateSubscription(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.AnnouncementSubscriptionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ah.service.UpdateSubscription(userId.(uint), uint(courseId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/announcements/email/:token/unsubscribe - Unsubscribe từ link trong email
+func (ah *AnnouncementHandler) UnsubscribeByToken(ctx *gin.Context) {
+
+	response, err := ah.service.UnsubscribeByToken(ctx.Param("token"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/announcements/email/:token/open - Tracking pixel đếm số lượt mở email
+func (ah *AnnouncementHandler) TrackEmailOpen(ctx *gin.Context) {
+	// Luôn trả về pixel, lỗi tracking không ảnh hưởng người đọc
+	ah.service.TrackEmailOpen(ctx.Param("token"))
+
+	ctx.Header("Cache-Control", "no-store, no-cache, must-revalidate")
+	ctx.Data(http.StatusOK, "image/gif", transparentPixel)
+}
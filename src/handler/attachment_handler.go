@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"fmt"
+	"lms/src/service"
+	"lms/src/utils"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttachmentHandler struct {
+	service service.AttachmentService
+}
+
+func NewAttachmentHandler(service service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		service: service,
+	}
+}
+
+// POST /api/v1/instructor/courses/:course_id/lessons/:id/attachments - Upload tài liệu đính kèm cho lesson
+func (ah *AttachmentHandler) UploadAttachment(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy file từ form data
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Attachment file is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := ah.service.UploadAttachment(userId.(uint), uint(courseId), uint(lessonId), ctx.PostForm("title"), file)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/lessons/:id/attachments/:attachment_id - Xóa tài liệu đính kèm
+func (ah *AttachmentHandler) DeleteAttachment(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	attachmentId, err := strconv.ParseUint(ctx.Param("attachment_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid attachment Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := ah.service.DeleteAttachment(userId.(uint), uint(courseId), uint(lessonId), uint(attachmentId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/lessons/:lesson_id/attachments/:attachment_id/download - Tải tài liệu đính kèm
+func (ah *AttachmentHandler) DownloadAttachment(ctx *gin.Context) {
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	attachmentId, err := strconv.ParseUint(ctx.Param("attachment_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid attachment Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Khách (chưa login) chỉ tải được attachment của lesson preview
+	var userId uint
+	if value, exists := ctx.Get("user_id"); exists {
+		userId = value.(uint)
+	}
+
+	download, err := ah.service.DownloadAttachment(userId, uint(lessonId), uint(attachmentId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+	defer download.Body.Close()
+
+	ctx.DataFromReader(http.StatusOK, download.FileSize, download.MimeType, download.Body, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename*=UTF-8''%s", url.PathEscape(download.FileName)),
+	})
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BundleHandler struct {
+	service service.BundleService
+}
+
+func NewBundleHandler(service service.BundleService) *BundleHandler {
+	return &BundleHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/bundles - Danh sách bundle đang bán
+func (bh *BundleHandler) GetBundles(ctx *gin.Context) {
+	var req dto.GetBundlesQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := bh.service.GetBundles(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/bundles/:slug - Chi tiết bundle
+func (bh *BundleHandler) GetBundleBySlug(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+	if slug == "" {
+		utils.ResponseError(ctx, utils.NewError("Slug is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := bh.service.GetBundleBySlug(slug)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/bundles/:slug/quote - Giá user phải trả (đã trừ course đã sở hữu)
+func (bh *BundleHandler) GetQuote(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := bh.service.GetQuote(userId.(uint), ctx.Param("slug"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/bundles/:slug/purchase - Mua bundle và ghi danh mọi course chưa sở hữu
+func (bh *BundleHandler) PurchaseBundle(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.PurchaseBundleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := bh.service.PurchaseBundle(userId.(uint), ctx.Param("slug"), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// GET /api/v1/bundle-purchases - Lịch sử mua bundle của user
+func (bh *BundleHandler) GetMyPurchases(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.GetBundlePurchasesQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := bh.service.GetMyPurchases(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// ---------------- Admin ----------------
+// GET /api/v1/admin/bundles - Mọi bundle kèm số lượt mua và doanh thu
+func (bh *BundleHandler) AdminGetBundles(ctx *gin.Context) {
+	var req dto.AdminGetBundlesQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := bh.service.AdminGetBundles(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/bundles - Tạo bundle
+func (bh *BundleHandler) CreateBundle(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.CreateBundleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := bh.service.CreateBundle(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// PUT /api/v1/admin/bundles/:bundle_id - Cập nhật bundle
+func (bh *BundleHandler) UpdateBundle(ctx *gin.Context) {
+	bundleId, ok := parseBundleId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateBundleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := bh.service.UpdateBundle(bundleId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/admin/bundles/:bundle_id/courses - Thay danh sách course của bundle
+func (bh *BundleHandler) SetBundleCourses(ctx *gin.Context) {
+	bundleId, ok := parseBundleId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.SetBundleCoursesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := bh.service.SetBundleCourses(bundleId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/admin/bundles/:bundle_id - Xóa bundle (purchase cũ giữ nguyên)
+func (bh *BundleHandler) DeleteBundle(ctx *gin.Context) {
+	bundleId, ok := parseBundleId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := bh.service.DeleteBundle(bundleId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+func parseBundleId(ctx *gin.Context) (uint, bool) {
+	bundleId, err := strconv.ParseUint(ctx.Param("bundle_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid bundle Id format", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+	return uint(bundleId), true
+}
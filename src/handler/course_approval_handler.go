@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CourseApprovalHandler struct {
+	service service.CourseApprovalService
+}
+
+func NewCourseApprovalHandler(service service.CourseApprovalService) *CourseApprovalHandler {
+	return &CourseApprovalHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/instructor/courses/:course_id/review - Trạng thái duyệt và checklist của course
+func (ch *CourseApprovalHandler) GetReviewStatus(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := ch.service.GetReviewStatus(userId.(uint), courseId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/review - Gửi course để admin duyệt
+func (ch *CourseApprovalHandler) SubmitForReview(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.SubmitCourseReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ch.service.SubmitForReview(userId.(uint), courseId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/review - Rút lại yêu cầu duyệt
+func (ch *CourseApprovalHandler) WithdrawSubmission(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := ch.service.WithdrawSubmission(userId.(uint), courseId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/instructor/courses/:course_id/status-history - Lịch sử trạng thái course
+func (ch *CourseApprovalHandler) GetInstructorStatusHistory(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := ch.service.GetInstructorStatusHistory(userId.(uint), courseId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/course-reviews - Hàng đợi course chờ duyệt
+func (ch *CourseApprovalHandler) GetReviewQueue(ctx *gin.Context) {
+	var req dto.GetCourseReviewQueueQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ch.service.GetReviewQueue(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/course-reviews/:course_id/approve - Duyệt và publish course
+func (ch *CourseApprovalHandler) ApproveCourse(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.ApproveCourseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ch.service.ApproveCourse(userId.(uint), courseId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/course-reviews/:course_id/reject - Từ chối course kèm phản hồi
+func (ch *CourseApprovalHandler) RejectCourse(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.RejectCourseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ch.service.RejectCourse(userId.(uint), courseId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/course-reviews/:course_id/history - Lịch sử trạng thái course (Admin)
+func (ch *CourseApprovalHandler) GetStatusHistory(ctx *gin.Context) {
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := ch.service.GetStatusHistory(courseId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+func parseCourseId(ctx *gin.Context) (uint, bool) {
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+	return uint(courseId), true
+}
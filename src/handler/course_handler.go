@@ -159,6 +159,7 @@ func (ch *CourseHandler) CreateCourseReview(ctx *gin.Context) {
 	var req dto.CreateReviewRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
 	}
 
 	// Call service
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CoursePackageHandler struct {
+	service service.CoursePackageService
+}
+
+func NewCoursePackageHandler(service service.CoursePackageService) *CoursePackageHandler {
+	return &CoursePackageHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/instructor/courses/:course_id/export - Tải gói zip của course
+func (ph *CoursePackageHandler) ExportCourse(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	export, err := ph.service.ExportCourse(userId.(uint), courseId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	sendCoursePackage(ctx, export)
+}
+
+// GET /api/v1/admin/courses/:course_id/export - Admin tải gói zip của bất kỳ course nào
+func (ph *CoursePackageHandler) AdminExportCourse(ctx *gin.Context) {
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	export, err := ph.service.AdminExportCourse(courseId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	sendCoursePackage(ctx, export)
+}
+
+// POST /api/v1/instructor/courses/import - Tạo course nháp từ gói zip
+func (ph *CoursePackageHandler) ImportCourse(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.ImportCourseRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	file, err := ctx.FormFile("package")
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Package file is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := ph.service.ImportCourse(userId.(uint), file, &req)
+	if err != nil {
+		// Gói không hợp lệ: trả danh sách lỗi kèm vị trí trong manifest
+		var packageErr *service.CoursePackageError
+		if errors.As(err, &packageErr) {
+			utils.ResponseValidator(ctx, gin.H{
+				"error":    "Invalid course package",
+				"code":     utils.ErrCodeValidation,
+				"problems": packageErr.Problems,
+			})
+			return
+		}
+
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+func sendCoursePackage(ctx *gin.Context, export *dto.CoursePackageExport) {
+	defer export.Body.Close()
+
+	ctx.DataFromReader(http.StatusOK, export.FileSize, "application/zip", export.Body, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename*=UTF-8''%s", url.PathEscape(export.FileName)),
+	})
+}
@@ -0,0 +1,435 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CourseRevisionHandler struct {
+	service service.CourseRevisionService
+}
+
+func NewCourseRevisionHandler(service service.CourseRevisionService) *CourseRevisionHandler {
+	return &CourseRevisionHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/instructor/courses/:course_id/revisions - Danh sách version và draft của course
+func (ch *CourseRevisionHandler) GetRevisions(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.GetCourseRevisionsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ch.service.GetRevisions(userId.(uint), courseId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/revisions/draft - Tạo draft từ version đang live
+func (ch *CourseRevisionHandler) CreateDraft(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := ch.service.CreateDraft(userId.(uint), courseId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// GET /api/v1/instructor/courses/:course_id/revisions/draft - Xem draft và các thay đổi
+func (ch *CourseRevisionHandler) GetDraft(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := ch.service.GetDraft(userId.(uint), courseId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/instructor/courses/:course_id/revisions/draft - Sửa thông tin course trong draft
+func (ch *CourseRevisionHandler) UpdateDraft(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCourseDraftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ch.service.UpdateDraft(userId.(uint), courseId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/revisions/draft/thumbnail - Upload thumbnail mới cho draft
+func (ch *CourseRevisionHandler) UploadDraftThumbnail(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	file, err := ctx.FormFile("thumbnail")
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Thumbnail file is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := ch.service.UploadDraftThumbnail(userId.(uint), courseId, file)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/revisions/draft - Hủy draft
+func (ch *CourseRevisionHandler) DiscardDraft(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := ch.service.DiscardDraft(userId.(uint), courseId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/instructor/courses/:course_id/revisions/draft/preview - Xem trước nội dung học viên sẽ thấy sau khi publish
+func (ch *CourseRevisionHandler) PreviewDraft(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := ch.service.PreviewDraft(userId.(uint), courseId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/revisions/draft/lessons - Thêm lesson vào draft
+func (ch *CourseRevisionHandler) AddDraftLesson(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateDraftLessonRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ch.service.AddDraftLesson(userId.(uint), courseId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/instructor/courses/:course_id/revisions/draft/lessons/order - Sắp xếp lại lessons trong draft
+func (ch *CourseRevisionHandler) ReorderDraftLessons(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.ReorderDraftLessonsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ch.service.ReorderDraftLessons(userId.(uint), courseId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/instructor/courses/:course_id/revisions/draft/lessons/:lesson_key - Sửa lesson trong draft
+func (ch *CourseRevisionHandler) UpdateDraftLesson(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateDraftLessonRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ch.service.UpdateDraftLesson(userId.(uint), courseId, ctx.Param("lesson_key"), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/revisions/draft/lessons/:lesson_key - Xóa lesson khỏi draft
+func (ch *CourseRevisionHandler) RemoveDraftLesson(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := ch.service.RemoveDraftLesson(userId.(uint), courseId, ctx.Param("lesson_key"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/revisions/draft/publish - Publish draft thành version mới
+func (ch *CourseRevisionHandler) PublishDraft(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.PublishCourseDraftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ch.service.PublishDraft(userId.(uint), courseId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/courses/:course_id/revisions - Danh sách version của course
+func (ch *CourseRevisionHandler) GetCourseRevisions(ctx *gin.Context) {
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.GetCourseRevisionsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ch.service.GetCourseRevisions(courseId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/courses/:course_id/revisions/diff - So sánh hai revision
+func (ch *CourseRevisionHandler) DiffRevisions(ctx *gin.Context) {
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.DiffCourseRevisionsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ch.service.DiffRevisions(courseId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/courses/:course_id/revisions/:revision_id - Chi tiết revision kèm snapshot
+func (ch *CourseRevisionHandler) GetRevision(ctx *gin.Context) {
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	revisionId, ok := parseRevisionId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := ch.service.GetRevision(courseId, revisionId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/courses/:course_id/revisions/:revision_id/rollback - Khôi phục course về một version cũ
+func (ch *CourseRevisionHandler) RollbackRevision(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	revisionId, ok := parseRevisionId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.RollbackCourseRevisionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ch.service.RollbackRevision(userId.(uint), courseId, revisionId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+func parseRevisionId(ctx *gin.Context) (uint, bool) {
+	revisionId, err := strconv.ParseUint(ctx.Param("revision_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid revision Id format", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+	return uint(revisionId), true
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CourseTemplateHandler struct {
+	service service.CourseTemplateService
+}
+
+func NewCourseTemplateHandler(service service.CourseTemplateService) *CourseTemplateHandler {
+	return &CourseTemplateHandler{
+		service: service,
+	}
+}
+
+// POST /api/v1/instructor/courses/:course_id/duplicate - Tạo course nháp mới từ course của mình hoặc template
+func (th *CourseTemplateHandler) DuplicateCourse(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.DuplicateCourseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := th.service.DuplicateCourse(userId.(uint), courseId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// GET /api/v1/instructor/course-templates - Danh sách course template
+func (th *CourseTemplateHandler) GetTemplates(ctx *gin.Context) {
+	var req dto.GetCourseTemplatesQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := th.service.GetTemplates(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/admin/courses/:course_id/template - Đánh dấu / bỏ đánh dấu course là template
+func (th *CourseTemplateHandler) SetTemplate(ctx *gin.Context) {
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.SetCourseTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := th.service.SetTemplate(courseId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
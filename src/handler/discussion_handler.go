@@ -0,0 +1,417 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DiscussionHandler struct {
+	service service.DiscussionService
+}
+
+func NewDiscussionHandler(service service.DiscussionService) *DiscussionHandler {
+	return &DiscussionHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/courses/course_id/:course_id/discussions - Lấy danh sách câu hỏi của course
+func (dh *DiscussionHandler) GetThreads(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.GetThreadsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := dh.service.GetThreads(userId, role, uint(courseId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/courses/course_id/:course_id/discussions - Đặt câu hỏi trong course/lesson
+func (dh *DiscussionHandler) CreateThread(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.CreateThreadRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := dh.service.CreateThread(userId, role, uint(courseId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// GET /api/v1/discussions/threads/:thread_id - Xem chi tiết câu hỏi và các trả lời
+func (dh *DiscussionHandler) GetThreadDetail(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	threadId, err := strconv.ParseUint(ctx.Param("thread_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid thread Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := dh.service.GetThreadDetail(userId, role, uint(threadId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/discussions/threads/:thread_id - Xóa câu hỏi
+func (dh *DiscussionHandler) DeleteThread(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	threadId, err := strconv.ParseUint(ctx.Param("thread_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid thread Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := dh.service.DeleteThread(userId, role, uint(threadId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/discussions/threads/:thread_id/replies - Trả lời câu hỏi
+func (dh *DiscussionHandler) CreateReply(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	threadId, err := strconv.ParseUint(ctx.Param("thread_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid thread Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.CreateReplyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := dh.service.CreateReply(userId, role, uint(threadId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// DELETE /api/v1/discussions/replies/:reply_id - Xóa câu trả lời
+func (dh *DiscussionHandler) DeleteReply(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	replyId, err := strconv.ParseUint(ctx.Param("reply_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid reply Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := dh.service.DeleteReply(userId, role, uint(replyId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/discussions/threads/:thread_id/upvote - Upvote/bỏ upvote câu hỏi
+func (dh *DiscussionHandler) UpvoteThread(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	threadId, err := strconv.ParseUint(ctx.Param("thread_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid thread Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := dh.service.UpvoteThread(userId, role, uint(threadId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/discussions/replies/:reply_id/upvote - Upvote/bỏ upvote câu trả lời
+func (dh *DiscussionHandler) UpvoteReply(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	replyId, err := strconv.ParseUint(ctx.Param("reply_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid reply Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := dh.service.UpvoteReply(userId, role, uint(replyId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/discussions/replies/:reply_id/answer - Đánh dấu câu trả lời đúng (instructor/TA)
+func (dh *DiscussionHandler) MarkAnswer(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	replyId, err := strconv.ParseUint(ctx.Param("reply_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid reply Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := dh.service.MarkAnswer(userId, role, uint(replyId), true)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/discussions/replies/:reply_id/answer - Bỏ đánh dấu câu trả lời đúng (instructor/TA)
+func (dh *DiscussionHandler) UnmarkAnswer(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	replyId, err := strconv.ParseUint(ctx.Param("reply_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid reply Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := dh.service.MarkAnswer(userId, role, uint(replyId), false)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/discussions/threads/:thread_id/pin - Ghim/bỏ ghim câu hỏi (instructor/TA)
+func (dh *DiscussionHandler) PinThread(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	threadId, err := strconv.ParseUint(ctx.Param("thread_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid thread Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.PinThreadRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := dh.service.PinThread(userId, role, uint(threadId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/discussions/threads/:thread_id/visibility - Đặt câu hỏi chỉ instructor xem được (instructor/TA)
+func (dh *DiscussionHandler) SetThreadVisibility(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	threadId, err := strconv.ParseUint(ctx.Param("thread_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid thread Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.ThreadVisibilityRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := dh.service.SetThreadVisibility(userId, role, uint(threadId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/instructor/discussions/unanswered - Câu hỏi chưa được trả lời trong các course của instructor/TA
+func (dh *DiscussionHandler) GetUnansweredThreads(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.GetUnansweredQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := dh.service.GetUnansweredThreads(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/instructor/courses/:course_id/assistants - Danh sách trợ giảng (TA) của course
+func (dh *DiscussionHandler) GetCourseAssistants(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := dh.service.GetCourseAssistants(userId, role, uint(courseId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/courses/:course_id/assistants - Thêm trợ giảng (TA) cho course
+func (dh *DiscussionHandler) AddCourseAssistant(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.AddCourseAssistantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := dh.service.AddCourseAssistant(userId, role, uint(courseId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/assistants/:user_id - Xóa trợ giảng (TA) khỏi course
+func (dh *DiscussionHandler) RemoveCourseAssistant(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	assistantId, err := strconv.ParseUint(ctx.Param("user_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid user Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := dh.service.RemoveCourseAssistant(userId, role, uint(courseId), uint(assistantId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
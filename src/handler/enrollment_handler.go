@@ -94,6 +94,38 @@ func (eh *EnrollmentHandler) CheckEnrollment(ctx *gin.Context) {
 	utils.ResponseSuccess(ctx, http.StatusOK, response)
 }
 
+// GET /api/v1/courses/course_id/:course_id/check-prerequisites - Kiểm tra prerequisites trước khi enroll
+func (eh *EnrollmentHandler) CheckPrerequisites(ctx *gin.Context) {
+	// Lấy course ID từ URL parameter
+	courseIdParam := ctx.Param("course_id")
+	if courseIdParam == "" {
+		utils.ResponseError(ctx, utils.NewError("Course Id is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Convert string to uint
+	courseId, err := strconv.ParseUint(courseIdParam, 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy user ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := eh.service.CheckPrerequisites(userId.(uint), uint(courseId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
 // GET /api/v1/enrollments/my - Lấy danh sách enrollments của user
 func (eh *EnrollmentHandler) GetMyEnrollments(ctx *gin.Context) {
 	// Lấy user ID từ context
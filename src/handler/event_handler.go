@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventHandler struct {
+	service service.EventService
+}
+
+func NewEventHandler(service service.EventService) *EventHandler {
+	return &EventHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/admin/events - Danh sách domain event trong outbox (lọc theo status, event_type)
+func (eh *EventHandler) GetEvents(ctx *gin.Context) {
+	var req dto.GetOutboxEventsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := eh.service.GetEvents(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/events/:event_id/retry - Gửi lại event đã failed
+func (eh *EventHandler) RetryEvent(ctx *gin.Context) {
+	eventId, err := strconv.ParseUint(ctx.Param("event_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid event Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := eh.service.RetryEvent(uint(eventId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
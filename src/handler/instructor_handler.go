@@ -146,6 +146,38 @@ func (ih *InstructorHandler) DeleteCourse(ctx *gin.Context) {
 	utils.ResponseSuccess(ctx, http.StatusOK, response)
 }
 
+// POST /api/v1/instructor/courses/:course_id/thumbnail - Upload thumbnail cho course
+func (ih *InstructorHandler) UploadCourseThumbnail(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy file từ form data
+	file, err := ctx.FormFile("thumbnail")
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Thumbnail file is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := ih.service.UploadCourseThumbnail(userId.(uint), uint(courseId), file)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
 // GET /api/v1/instructor/courses/:user_id/students - Lấy danh sách students của course
 func (ih *InstructorHandler) GetCourseStudents(ctx *gin.Context) {
 	// Lấy instructor ID từ context
@@ -361,3 +393,35 @@ func (ih *InstructorHandler) ReorderLessons(ctx *gin.Context) {
 
 	utils.ResponseSuccess(ctx, http.StatusOK, response)
 }
+
+// PUT /api/v1/instructor/courses/:course_id/prerequisites - Thiết lập prerequisites cho course
+func (ih *InstructorHandler) SetCoursePrerequisites(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Lấy course ID từ URL parameter
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Bind JSON request
+	var req dto.SetCoursePrerequisitesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := ih.service.SetCoursePrerequisites(userId.(uint), uint(courseId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
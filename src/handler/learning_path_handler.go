@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LearningPathHandler struct {
+	service service.LearningPathService
+}
+
+func NewLearningPathHandler(service service.LearningPathService) *LearningPathHandler {
+	return &LearningPathHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/learning-paths - Lấy danh sách learning paths
+func (lph *LearningPathHandler) GetLearningPaths(ctx *gin.Context) {
+	var req dto.GetLearningPathsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := lph.service.GetLearningPaths(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/learning-paths/:slug - Lấy chi tiết learning path
+func (lph *LearningPathHandler) GetLearningPathBySlug(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+	if slug == "" {
+		utils.ResponseError(ctx, utils.NewError("Slug is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := lph.service.GetLearningPathBySlug(slug)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/learning-paths/:slug/progress - Lấy progress của user trong learning path
+func (lph *LearningPathHandler) GetLearningPathProgress(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := lph.service.GetLearningPathProgress(userId.(uint), ctx.Param("slug"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/learning-paths/:slug/certificate - Cấp certificate khi hoàn thành learning path
+func (lph *LearningPathHandler) IssueCertificate(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := lph.service.IssueCertificate(userId.(uint), ctx.Param("slug"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// POST /api/v1/instructor/learning-paths - Tạo learning path (instructor/admin)
+func (lph *LearningPathHandler) CreateLearningPath(ctx *gin.Context) {
+	userId, userRole, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateLearningPathRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := lph.service.CreateLearningPath(userId, userRole, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// PUT /api/v1/instructor/learning-paths/:id - Cập nhật learning path
+func (lph *LearningPathHandler) UpdateLearningPath(ctx *gin.Context) {
+	userId, userRole, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	pathId, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid learning path Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.UpdateLearningPathRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := lph.service.UpdateLearningPath(userId, userRole, uint(pathId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/instructor/learning-paths/:id - Xóa learning path
+func (lph *LearningPathHandler) DeleteLearningPath(ctx *gin.Context) {
+	userId, userRole, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	pathId, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid learning path Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := lph.service.DeleteLearningPath(userId, userRole, uint(pathId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/instructor/learning-paths/:id/courses - Sắp xếp courses trong learning path
+func (lph *LearningPathHandler) SetLearningPathCourses(ctx *gin.Context) {
+	userId, userRole, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	pathId, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid learning path Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.SetLearningPathCoursesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := lph.service.SetLearningPathCourses(userId, userRole, uint(pathId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// getUserIdentity lấy user ID và role đã được AuthMiddleware set vào context
+func getUserIdentity(ctx *gin.Context) (uint, string, bool) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return 0, "", false
+	}
+
+	userRole, exists := ctx.Get("user_role")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User role not found", utils.ErrCodeUnauthorized))
+		return 0, "", false
+	}
+
+	return userId.(uint), userRole.(string), true
+}
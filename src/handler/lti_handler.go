@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cookie giữ state của OIDC login (mỗi login một cookie để nhiều launch song song không ghi đè nhau).
+// SameSite=None vì launch là form POST cross-site từ platform.
+const (
+	ltiStateCookiePrefix = "lti_state_"
+	ltiStateCookiePath   = "/api/v1/lti"
+)
+
+type LtiHandler struct {
+	service service.LtiService
+}
+
+func NewLtiHandler(service service.LtiService) *LtiHandler {
+	return &LtiHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/lti/jwks - Public key của tool để platform xác thực JWT do tool ký
+func (lh *LtiHandler) GetJWKS(ctx *gin.Context) {
+	jwks, err := lh.service.GetJWKS()
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, jwks)
+}
+
+// GET /api/v1/lti/config - Các URL cần khai báo khi đăng ký tool trong LMS
+func (lh *LtiHandler) GetToolConfig(ctx *gin.Context) {
+	utils.ResponseSuccess(ctx, http.StatusOK, lh.service.GetToolConfig())
+}
+
+// GET|POST /api/v1/lti/login - OIDC login initiation từ platform
+func (lh *LtiHandler) Login(ctx *gin.Context) {
+	var req dto.LtiLoginRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	result, err := lh.service.Login(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	setLtiStateCookie(ctx, result.State, int(time.Until(result.StateExpiresAt).Seconds()))
+	ctx.Redirect(http.StatusFound, result.RedirectURL)
+}
+
+// POST /api/v1/lti/launch - Nhận id_token (resource link hoặc deep linking) từ platform
+func (lh *LtiHandler) Launch(ctx *gin.Context) {
+	var req dto.LtiLaunchRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	// State phải khớp cookie do login đặt trên chính trình duyệt này; cookie chỉ dùng một lần
+	req.BrowserState, _ = ctx.Cookie(ltiStateCookiePrefix + req.State)
+	setLtiStateCookie(ctx, req.State, -1)
+
+	result, err := lh.service.Launch(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	// 303: trình duyệt chuyển form POST thành GET tới frontend
+	ctx.Redirect(http.StatusSeeOther, result.RedirectURL)
+}
+
+// GET /api/v1/lti/deep-linking/:token - Thông tin phiên deep linking
+func (lh *LtiHandler) GetDeepLinkSession(ctx *gin.Context) {
+	response, err := lh.service.GetDeepLinkSession(ctx.Param("token"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/lti/deep-linking/:token - Chọn course/lesson, trả về JWT để gửi lại platform
+func (lh *LtiHandler) CreateDeepLink(ctx *gin.Context) {
+	var req dto.LtiDeepLinkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := lh.service.CreateDeepLink(ctx.Param("token"), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/lti/platforms - Danh sách platform
+func (lh *LtiHandler) GetPlatforms(ctx *gin.Context) {
+	var req dto.GetLtiPlatformsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := lh.service.GetPlatforms(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/lti/platforms - Đăng ký platform
+func (lh *LtiHandler) CreatePlatform(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.CreateLtiPlatformRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := lh.service.CreatePlatform(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// GET /api/v1/admin/lti/platforms/:platform_id - Chi tiết platform
+func (lh *LtiHandler) GetPlatform(ctx *gin.Context) {
+	platformId, ok := parseLtiPlatformId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := lh.service.GetPlatform(platformId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/admin/lti/platforms/:platform_id - Cập nhật platform
+func (lh *LtiHandler) UpdatePlatform(ctx *gin.Context) {
+	platformId, ok := parseLtiPlatformId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateLtiPlatformRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := lh.service.UpdatePlatform(platformId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/admin/lti/platforms/:platform_id - Xóa platform
+func (lh *LtiHandler) DeletePlatform(ctx *gin.Context) {
+	platformId, ok := parseLtiPlatformId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := lh.service.DeletePlatform(platformId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+func parseLtiPlatformId(ctx *gin.Context) (uint, bool) {
+	platformId, err := strconv.ParseUint(ctx.Param("platform_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid platform Id format", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+	return uint(platformId), true
+}
+
+// setLtiStateCookie đặt (maxAge > 0) hoặc xóa (maxAge < 0) cookie state của một lần login
+func setLtiStateCookie(ctx *gin.Context, state string, maxAge int) {
+	ctx.SetSameSite(http.SameSiteNoneMode)
+	ctx.SetCookie(ltiStateCookiePrefix+state, state, maxAge, ltiStateCookiePath, "", true, true)
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"fmt"
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NoteHandler struct {
+	service service.NoteService
+}
+
+func NewNoteHandler(service service.NoteService) *NoteHandler {
+	return &NoteHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/lessons/:lesson_id/notes - Lấy ghi chú của học viên trên lesson
+func (nh *NoteHandler) GetLessonNotes(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := nh.service.GetLessonNotes(userId.(uint), uint(lessonId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/lessons/:lesson_id/notes - Tạo ghi chú (có thể gắn timestamp video)
+func (nh *NoteHandler) CreateNote(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.CreateNoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := nh.service.CreateNote(userId.(uint), uint(lessonId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// PUT /api/v1/notes/:note_id - Cập nhật ghi chú
+func (nh *NoteHandler) UpdateNote(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	noteId, err := strconv.ParseUint(ctx.Param("note_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid note Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	var req dto.UpdateNoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := nh.service.UpdateNote(userId.(uint), uint(noteId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/notes/:note_id - Xóa ghi chú
+func (nh *NoteHandler) DeleteNote(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	noteId, err := strconv.ParseUint(ctx.Param("note_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid note Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := nh.service.DeleteNote(userId.(uint), uint(noteId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/notes/search - Tìm kiếm full-text trong ghi chú/bookmark ở tất cả course
+func (nh *NoteHandler) SearchNotes(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.SearchNotesQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := nh.service.SearchNotes(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/courses/course_id/:course_id/notes/export - Xuất ghi chú của course ra file Markdown
+func (nh *NoteHandler) ExportCourseNotes(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	export, err := nh.service.ExportCourseNotes(userId.(uint), uint(courseId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename*=UTF-8''%s", url.PathEscape(export.FileName)))
+	ctx.Data(http.StatusOK, "text/markdown; charset=utf-8", export.Content)
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Gửi comment định kỳ để proxy/load balancer không đóng kết nối stream
+const notificationHeartbeatInterval = 25 * time.Second
+
+type NotificationHandler struct {
+	service service.NotificationService
+}
+
+func NewNotificationHandler(service service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/notifications - Lấy danh sách thông báo
+func (nh *NotificationHandler) GetNotifications(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.GetNotificationsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := nh.service.GetNotifications(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/notifications/read - Đánh dấu thông báo đã đọc (bỏ trống ids để đánh dấu tất cả)
+func (nh *NotificationHandler) MarkRead(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.MarkNotificationsReadRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := nh.service.MarkRead(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/notifications/:notification_id - Xóa thông báo
+func (nh *NotificationHandler) DeleteNotification(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	notificationId, err := strconv.ParseUint(ctx.Param("notification_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid notification Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := nh.service.DeleteNotification(userId.(uint), uint(notificationId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/notifications/preferences - Lấy lựa chọn bật/tắt theo loại thông báo
+func (nh *NotificationHandler) GetPreferences(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := nh.service.GetPreferences(userId.(uint))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/notifications/preferences - Cập nhật lựa chọn bật/tắt theo loại thông báo
+func (nh *NotificationHandler) UpdatePreferences(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.UpdateNotificationPreferencesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := nh.service.UpdatePreferences(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/notifications/stream - Nhận thông báo real-time qua Server-Sent Events
+func (nh *NotificationHandler) Stream(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Trình duyệt tự gửi Last-Event-ID khi kết nối lại
+	lastEventId, _ := strconv.ParseUint(ctx.GetHeader("Last-Event-ID"), 10, 32)
+
+	missed, stream, unsubscribe, err := nh.service.Subscribe(userId.(uint), uint(lastEventId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+	defer unsubscribe()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	for _, item := range missed {
+		writeNotificationEvent(ctx.Writer, item)
+	}
+	fmt.Fprint(ctx.Writer, ": connected\n\n")
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(notificationHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case item := <-stream:
+			writeNotificationEvent(w, item)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		return true
+	})
+}
+
+func writeNotificationEvent(w io.Writer, item dto.NotificationItem) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: notification\ndata: %s\n\n", item.Id, data)
+}
@@ -0,0 +1,485 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationHandler struct {
+	service service.OrganizationService
+}
+
+func NewOrganizationHandler(service service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		service: service,
+	}
+}
+
+// ---------------- Organizations ----------------
+// POST /api/v1/organizations - Tạo organization (người tạo là admin)
+func (oh *OrganizationHandler) CreateOrganization(ctx *gin.Context) {
+	userId, _, ok := parseOrganizationParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateOrganizationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := oh.service.CreateOrganization(userId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// GET /api/v1/organizations - Organization mà user là thành viên
+func (oh *OrganizationHandler) GetMyOrganizations(ctx *gin.Context) {
+	userId, _, ok := parseOrganizationParams(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := oh.service.GetMyOrganizations(userId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/organizations/:org_id - Chi tiết organization
+func (oh *OrganizationHandler) GetOrganization(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id")
+	if !ok {
+		return
+	}
+
+	response, err := oh.service.GetOrganization(userId, ids[0])
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/organizations/:org_id - Cập nhật tên và thông tin thanh toán
+func (oh *OrganizationHandler) UpdateOrganization(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateOrganizationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := oh.service.UpdateOrganization(userId, ids[0], &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// ---------------- Members ----------------
+// GET /api/v1/organizations/:org_id/members - Danh sách thành viên
+func (oh *OrganizationHandler) GetMembers(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id")
+	if !ok {
+		return
+	}
+
+	var req dto.GetOrganizationMembersQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := oh.service.GetMembers(userId, ids[0], &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/organizations/:org_id/members - Thêm thành viên theo email
+func (oh *OrganizationHandler) AddMember(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id")
+	if !ok {
+		return
+	}
+
+	var req dto.AddOrganizationMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := oh.service.AddMember(userId, ids[0], &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// PUT /api/v1/organizations/:org_id/members/:user_id - Đổi role thành viên
+func (oh *OrganizationHandler) UpdateMember(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id", "user_id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateOrganizationMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := oh.service.UpdateMember(userId, ids[0], ids[1], &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/organizations/:org_id/members/:user_id - Xóa thành viên và thu hồi seat
+func (oh *OrganizationHandler) RemoveMember(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id", "user_id")
+	if !ok {
+		return
+	}
+
+	response, err := oh.service.RemoveMember(userId, ids[0], ids[1])
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// ---------------- Licenses & seats ----------------
+// GET /api/v1/organizations/:org_id/licenses - Danh sách license và seat đã dùng
+func (oh *OrganizationHandler) GetLicenses(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id")
+	if !ok {
+		return
+	}
+
+	response, err := oh.service.GetLicenses(userId, ids[0])
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/organizations/:org_id/licenses - Mua seat (xuất hóa đơn)
+func (oh *OrganizationHandler) PurchaseSeats(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id")
+	if !ok {
+		return
+	}
+
+	var req dto.PurchaseSeatsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := oh.service.PurchaseSeats(userId, ids[0], &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// POST /api/v1/organizations/:org_id/licenses/:license_id/cancel - Hủy license chưa thanh toán
+func (oh *OrganizationHandler) CancelLicense(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id", "license_id")
+	if !ok {
+		return
+	}
+
+	response, err := oh.service.CancelLicense(userId, ids[0], ids[1])
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/organizations/:org_id/licenses/:license_id/seats - Seat đang được dùng
+func (oh *OrganizationHandler) GetSeatAssignments(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id", "license_id")
+	if !ok {
+		return
+	}
+
+	response, err := oh.service.GetSeatAssignments(userId, ids[0], ids[1])
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/organizations/:org_id/licenses/:license_id/seats - Gán seat và ghi danh thành viên
+func (oh *OrganizationHandler) AssignSeat(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id", "license_id")
+	if !ok {
+		return
+	}
+
+	var req dto.AssignSeatRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := oh.service.AssignSeat(userId, ids[0], ids[1], &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// DELETE /api/v1/organizations/:org_id/licenses/:license_id/seats/:user_id - Thu hồi seat
+func (oh *OrganizationHandler) ReclaimSeat(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id", "license_id", "user_id")
+	if !ok {
+		return
+	}
+
+	response, err := oh.service.ReclaimSeat(userId, ids[0], ids[1], ids[2])
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// ---------------- Invoices ----------------
+// GET /api/v1/organizations/:org_id/invoices - Danh sách hóa đơn
+func (oh *OrganizationHandler) GetInvoices(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id")
+	if !ok {
+		return
+	}
+
+	var req dto.GetOrganizationInvoicesQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := oh.service.GetInvoices(userId, ids[0], &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/organizations/:org_id/invoices/:invoice_id - Chi tiết hóa đơn
+func (oh *OrganizationHandler) GetInvoice(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id", "invoice_id")
+	if !ok {
+		return
+	}
+
+	response, err := oh.service.GetInvoice(userId, ids[0], ids[1])
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/organizations/:org_id/invoices/:invoice_id/pay - Thanh toán hóa đơn
+func (oh *OrganizationHandler) PayInvoice(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id", "invoice_id")
+	if !ok {
+		return
+	}
+
+	var req dto.PayInvoiceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := oh.service.PayInvoice(userId, ids[0], ids[1], &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// ---------------- Team progress ----------------
+// GET /api/v1/organizations/:org_id/progress - Dashboard tiến độ của team
+func (oh *OrganizationHandler) GetTeamProgress(ctx *gin.Context) {
+	userId, ids, ok := parseOrganizationParams(ctx, "org_id")
+	if !ok {
+		return
+	}
+
+	var req dto.GetTeamProgressQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := oh.service.GetTeamProgress(userId, ids[0], &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// ---------------- Admin ----------------
+// GET /api/v1/admin/organizations - Danh sách organization
+func (oh *OrganizationHandler) AdminGetOrganizations(ctx *gin.Context) {
+	var req dto.GetOrganizationsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := oh.service.AdminGetOrganizations(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/admin/organizations/:org_id/status - Kích hoạt / tạm khóa organization
+func (oh *OrganizationHandler) AdminUpdateOrganizationStatus(ctx *gin.Context) {
+	_, ids, ok := parseOrganizationParams(ctx, "org_id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateOrganizationStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := oh.service.AdminUpdateOrganizationStatus(ids[0], &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/organizations/:org_id/invoices - Hóa đơn của organization
+func (oh *OrganizationHandler) AdminGetInvoices(ctx *gin.Context) {
+	_, ids, ok := parseOrganizationParams(ctx, "org_id")
+	if !ok {
+		return
+	}
+
+	var req dto.GetOrganizationInvoicesQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := oh.service.AdminGetInvoices(ids[0], &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/organizations/:org_id/invoices/:invoice_id/mark-paid - Ghi nhận thanh toán chuyển khoản
+func (oh *OrganizationHandler) AdminMarkInvoicePaid(ctx *gin.Context) {
+	_, ids, ok := parseOrganizationParams(ctx, "org_id", "invoice_id")
+	if !ok {
+		return
+	}
+
+	var req dto.MarkInvoicePaidRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := oh.service.AdminMarkInvoicePaid(ids[0], ids[1], &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// parseOrganizationParams lấy user ID từ context và đọc các ID trên path theo thứ tự truyền vào
+func parseOrganizationParams(ctx *gin.Context, params ...string) (uint, []uint, bool) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return 0, nil, false
+	}
+
+	ids := make([]uint, len(params))
+	for i, param := range params {
+		id, err := strconv.ParseUint(ctx.Param(param), 10, 32)
+		if err != nil {
+			name := strings.TrimSuffix(param, "_id")
+			if name == "org" {
+				name = "organization"
+			}
+			utils.ResponseError(ctx, utils.NewError("Invalid "+name+" Id format", utils.ErrCodeBadRequest))
+			return 0, nil, false
+		}
+		ids[i] = uint(id)
+	}
+
+	return userId.(uint), ids, true
+}
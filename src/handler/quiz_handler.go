@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuizHandler struct {
+	service service.QuizService
+}
+
+func NewQuizHandler(service service.QuizService) *QuizHandler {
+	return &QuizHandler{
+		service: service,
+	}
+}
+
+// POST /api/v1/instructor/courses/:course_id/lessons/:id/quiz - Tạo quiz cho lesson
+func (qh *QuizHandler) CreateQuiz(ctx *gin.Context) {
+	// Lấy course ID từ URL parameter
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy lesson ID từ URL parameter
+	lessonId, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Bind JSON request
+	var req dto.CreateQuizRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	// Gọi service để tạo quiz
+	response, err := qh.service.CreateQuiz(userId.(uint), uint(courseId), uint(lessonId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/lessons/:id/quiz - Xóa quiz của lesson
+func (qh *QuizHandler) DeleteQuiz(ctx *gin.Context) {
+	// Lấy course ID từ URL parameter
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy lesson ID từ URL parameter
+	lessonId, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Gọi service để xóa quiz
+	response, err := qh.service.DeleteQuiz(userId.(uint), uint(courseId), uint(lessonId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/lessons/:lesson_id/quiz - Lấy quiz của lesson (enrolled only)
+func (qh *QuizHandler) GetLessonQuiz(ctx *gin.Context) {
+	// Lấy lesson ID từ URL parameter
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy user ID từ context (đã được set bởi AuthMiddleware)
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Gọi service để lấy quiz
+	response, err := qh.service.GetLessonQuiz(userId.(uint), uint(lessonId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/quizzes/:quiz_id/submit - Nộp bài quiz
+func (qh *QuizHandler) SubmitQuiz(ctx *gin.Context) {
+	// Lấy quiz ID từ URL parameter
+	quizId, err := strconv.ParseUint(ctx.Param("quiz_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid quiz Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy user ID từ context (đã được set bởi AuthMiddleware)
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Bind JSON request
+	var req dto.SubmitQuizRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	// Gọi service để chấm bài
+	response, err := qh.service.SubmitQuiz(userId.(uint), uint(quizId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
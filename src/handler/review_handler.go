@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	service service.ReviewService
+}
+
+func NewReviewHandler(service service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		service: service,
+	}
+}
+
+// POST /api/v1/reviews/:review_id/report - Báo cáo review vi phạm
+func (rh *ReviewHandler) ReportReview(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	reviewId, ok := parseReviewId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.ReportReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := rh.service.ReportReview(userId.(uint), reviewId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// PUT /api/v1/reviews/:review_id/vote - Vote review hữu ích / không hữu ích
+func (rh *ReviewHandler) VoteReview(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	reviewId, ok := parseReviewId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.VoteReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := rh.service.VoteReview(userId.(uint), reviewId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/reviews/:review_id/vote - Bỏ vote
+func (rh *ReviewHandler) RemoveVote(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	reviewId, ok := parseReviewId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := rh.service.RemoveVote(userId.(uint), reviewId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/instructor/reviews/:review_id/reply - Tạo hoặc sửa phản hồi của instructor
+func (rh *ReviewHandler) UpsertReply(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	reviewId, ok := parseReviewId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.ReviewReplyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := rh.service.UpsertReply(userId.(uint), reviewId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/instructor/reviews/:review_id/reply - Xóa phản hồi của instructor
+func (rh *ReviewHandler) DeleteReply(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	reviewId, ok := parseReviewId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := rh.service.DeleteReply(userId.(uint), reviewId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/reviews/moderation - Hàng đợi kiểm duyệt review
+func (rh *ReviewHandler) GetModerationQueue(ctx *gin.Context) {
+	var req dto.GetReviewModerationQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := rh.service.GetModerationQueue(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/admin/reviews/:review_id/moderation - Ẩn hoặc khôi phục review
+func (rh *ReviewHandler) ModerateReview(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User not authenticated", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	reviewId, ok := parseReviewId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.ModerateReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := rh.service.ModerateReview(userId.(uint), reviewId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/reviews/:review_id/reports - Danh sách report của review
+func (rh *ReviewHandler) GetReviewReports(ctx *gin.Context) {
+	reviewId, ok := parseReviewId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := rh.service.GetReviewReports(reviewId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+func parseReviewId(ctx *gin.Context) (uint, bool) {
+	reviewId, err := strconv.ParseUint(ctx.Param("review_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid review Id format", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+	return uint(reviewId), true
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScormHandler struct {
+	service service.ScormService
+}
+
+func NewScormHandler(service service.ScormService) *ScormHandler {
+	return &ScormHandler{
+		service: service,
+	}
+}
+
+// POST /api/v1/instructor/courses/:course_id/lessons/:id/scorm - Upload package SCORM (zip) cho lesson
+func (sh *ScormHandler) UploadPackage(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseInstructorLessonParams(ctx)
+	if !ok {
+		return
+	}
+
+	file, err := ctx.FormFile("package")
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("SCORM package file is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := sh.service.UploadPackage(userId.(uint), courseId, lessonId, file)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// GET /api/v1/instructor/courses/:course_id/lessons/:id/scorm - Thông tin package SCORM của lesson
+func (sh *ScormHandler) GetPackage(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseInstructorLessonParams(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := sh.service.GetPackage(userId.(uint), courseId, lessonId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/lessons/:id/scorm - Xóa package, lesson trở về loại thường
+func (sh *ScormHandler) DeletePackage(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseInstructorLessonParams(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := sh.service.DeletePackage(userId.(uint), courseId, lessonId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/instructor/courses/:course_id/lessons/:id/scorm/attempts - Kết quả SCORM của học viên
+func (sh *ScormHandler) GetAttempts(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, lessonId, ok := parseInstructorLessonParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.GetScormAttemptsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := sh.service.GetAttempts(userId.(uint), courseId, lessonId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/lessons/:lesson_id/scorm/launch - URL launch và dữ liệu cmi để khởi tạo SCORM API
+func (sh *ScormHandler) Launch(ctx *gin.Context) {
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := sh.service.Launch(userId.(uint), uint(lessonId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/lessons/:lesson_id/scorm/commit - Lưu dữ liệu cmi (LMSCommit/Commit, LMSFinish/Terminate)
+func (sh *ScormHandler) Commit(ctx *gin.Context) {
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.ScormCommitRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := sh.service.Commit(userId.(uint), uint(lessonId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/scorm/content/:token/*filepath - Nội dung package SCORM bằng signed token
+func (sh *ScormHandler) Content(ctx *gin.Context) {
+	content, err := sh.service.ResolveContent(ctx.Param("token"), ctx.Param("filepath"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	if content.RedirectURL != "" {
+		ctx.Redirect(http.StatusFound, content.RedirectURL)
+		return
+	}
+
+	defer content.Body.Close()
+	// HTML/JS của instructor chạy trong sandbox với origin riêng (không allow-same-origin),
+	// không đọc được cookie/storage hay gọi API với tư cách origin của API
+	ctx.Header("Content-Security-Policy", "sandbox allow-scripts allow-forms allow-popups allow-modals")
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.Header("Cache-Control", "private, max-age=300")
+	ctx.DataFromReader(http.StatusOK, -1, content.ContentType, content.Body, nil)
+}
+
+// parseInstructorLessonParams đọc course_id và id (lesson) của route instructor
+func parseInstructorLessonParams(ctx *gin.Context) (uint, uint, bool) {
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return 0, 0, false
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return 0, 0, false
+	}
+
+	return uint(courseId), uint(lessonId), true
+}
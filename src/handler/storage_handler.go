@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StorageHandler struct {
+	service service.StorageService
+}
+
+func NewStorageHandler(service service.StorageService) *StorageHandler {
+	return &StorageHandler{
+		service: service,
+	}
+}
+
+// POST /api/v1/storage/presign - Tạo presigned URL để client upload ảnh trực tiếp lên storage
+func (sh *StorageHandler) PresignUpload(ctx *gin.Context) {
+	userId, role, ok := getUserIdentity(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.PresignUploadRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := sh.service.PresignUpload(userId, role, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/storage/local/*key - Nhận file upload qua presigned URL (local driver)
+func (sh *StorageHandler) PutLocalObject(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+
+	err := sh.service.PutLocalObject(
+		key,
+		ctx.Query("expires"),
+		ctx.Query("signature"),
+		ctx.Request.Body,
+		ctx.Request.ContentLength,
+		ctx.GetHeader("Content-Type"),
+	)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// GET /api/v1/storage/local/*key - Đọc file private qua presigned URL (local driver)
+func (sh *StorageHandler) GetLocalObject(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+
+	body, contentType, err := sh.service.GetLocalObject(key, ctx.Query("expires"), ctx.Query("signature"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+	defer body.Close()
+
+	ctx.DataFromReader(http.StatusOK, -1, contentType, body, nil)
+}
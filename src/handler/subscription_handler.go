@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubscriptionHandler struct {
+	service service.SubscriptionService
+}
+
+func NewSubscriptionHandler(service service.SubscriptionService) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		service: service,
+	}
+}
+
+// ---------------- Plans ----------------
+// GET /api/v1/subscription-plans - Các plan đang mở bán
+func (sh *SubscriptionHandler) GetPlans(ctx *gin.Context) {
+	var req dto.GetSubscriptionPlansQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := sh.service.GetPlans(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/subscription-plans/:plan_id - Chi tiết plan
+func (sh *SubscriptionHandler) GetPlan(ctx *gin.Context) {
+	planId, ok := parsePlanId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := sh.service.GetPlan(planId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// ---------------- Subscriptions ----------------
+// GET /api/v1/subscriptions/me - Subscription hiện tại của user
+func (sh *SubscriptionHandler) GetMySubscription(ctx *gin.Context) {
+	userId, ok := parseSubscriptionUser(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := sh.service.GetMySubscription(userId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/subscriptions - Đăng ký plan (dùng thử hoặc thu tiền kỳ đầu)
+func (sh *SubscriptionHandler) Subscribe(ctx *gin.Context) {
+	userId, ok := parseSubscriptionUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.SubscribeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := sh.service.Subscribe(userId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// POST /api/v1/subscriptions/me/cancel - Hủy, vẫn truy cập được đến hết kỳ
+func (sh *SubscriptionHandler) CancelSubscription(ctx *gin.Context) {
+	userId, ok := parseSubscriptionUser(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := sh.service.CancelSubscription(userId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/subscriptions/me/resume - Bỏ hủy trước khi hết kỳ
+func (sh *SubscriptionHandler) ResumeSubscription(ctx *gin.Context) {
+	userId, ok := parseSubscriptionUser(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := sh.service.ResumeSubscription(userId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/subscriptions/me/pay - Thu lại tiền khi gia hạn thất bại (past_due)
+func (sh *SubscriptionHandler) RetryPayment(ctx *gin.Context) {
+	userId, ok := parseSubscriptionUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.RetrySubscriptionPaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := sh.service.RetryPayment(userId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/subscriptions/me/payments - Lịch sử thanh toán
+func (sh *SubscriptionHandler) GetMyPayments(ctx *gin.Context) {
+	userId, ok := parseSubscriptionUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.GetSubscriptionPaymentsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := sh.service.GetMyPayments(userId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// ---------------- Admin ----------------
+// GET /api/v1/admin/subscription-plans - Mọi plan kèm số subscriber
+func (sh *SubscriptionHandler) AdminGetPlans(ctx *gin.Context) {
+	var req dto.GetSubscriptionPlansQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := sh.service.AdminGetPlans(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/subscription-plans - Tạo plan
+func (sh *SubscriptionHandler) CreatePlan(ctx *gin.Context) {
+	var req dto.CreateSubscriptionPlanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := sh.service.CreatePlan(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// PUT /api/v1/admin/subscription-plans/:plan_id - Cập nhật plan (giá mới chỉ áp dụng cho subscriber mới)
+func (sh *SubscriptionHandler) UpdatePlan(ctx *gin.Context) {
+	planId, ok := parsePlanId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateSubscriptionPlanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := sh.service.UpdatePlan(planId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/subscriptions - Danh sách subscription
+func (sh *SubscriptionHandler) AdminGetSubscriptions(ctx *gin.Context) {
+	var req dto.GetSubscriptionsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := sh.service.AdminGetSubscriptions(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/courses/:course_id/tags - Tag của course
+func (sh *SubscriptionHandler) GetCourseTags(ctx *gin.Context) {
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := sh.service.GetCourseTags(courseId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/admin/courses/:course_id/tags - Gắn tag cho course (xác định course thuộc plan tagged nào)
+func (sh *SubscriptionHandler) SetCourseTags(ctx *gin.Context) {
+	courseId, ok := parseCourseId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.SetCourseTagsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := sh.service.SetCourseTags(courseId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+func parseSubscriptionUser(ctx *gin.Context) (uint, bool) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return 0, false
+	}
+	return userId.(uint), true
+}
+
+func parsePlanId(ctx *gin.Context) (uint, bool) {
+	planId, err := strconv.ParseUint(ctx.Param("plan_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid plan Id format", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+	return uint(planId), true
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"lms/src/service"
+	"lms/src/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubtitleHandler struct {
+	service service.SubtitleService
+}
+
+func NewSubtitleHandler(service service.SubtitleService) *SubtitleHandler {
+	return &SubtitleHandler{
+		service: service,
+	}
+}
+
+// POST /api/v1/instructor/courses/:course_id/lessons/:id/subtitles - Upload phụ đề WebVTT/SRT cho lesson
+func (sh *SubtitleHandler) UploadSubtitle(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy file từ form data
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Subtitle file is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := sh.service.UploadSubtitle(userId.(uint), uint(courseId), uint(lessonId), ctx.PostForm("language"), ctx.PostForm("label"), file)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// DELETE /api/v1/instructor/courses/:course_id/lessons/:id/subtitles/:subtitle_id - Xóa phụ đề
+func (sh *SubtitleHandler) DeleteSubtitle(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	lessonId, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	subtitleId, err := strconv.ParseUint(ctx.Param("subtitle_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid subtitle Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := sh.service.DeleteSubtitle(userId.(uint), uint(courseId), uint(lessonId), uint(subtitleId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TenantHandler struct {
+	service service.TenantService
+}
+
+func NewTenantHandler(service service.TenantService) *TenantHandler {
+	return &TenantHandler{
+		service: service,
+	}
+}
+
+// ---------------- Tenant hiện tại ----------------
+// GET /api/v1/tenant - Branding public của tenant (theo hostname hoặc header X-Tenant)
+func (th *TenantHandler) GetBranding(ctx *gin.Context) {
+	response, err := th.service.GetBranding(ctx.GetUint("tenant_id"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/admin/tenant/settings - Cấu hình đầy đủ của tenant (admin của tenant)
+func (th *TenantHandler) GetSettings(ctx *gin.Context) {
+	response, err := th.service.GetSettings(ctx.GetUint("tenant_id"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/admin/tenant/settings - Cập nhật branding, email gửi đi và frontend URL
+func (th *TenantHandler) UpdateSettings(ctx *gin.Context) {
+	var req dto.UpdateTenantSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := th.service.UpdateSettings(ctx.GetUint("tenant_id"), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// ---------------- Super-admin ----------------
+// POST /api/v1/super-admin/tenants - Tạo tenant cùng domain và tài khoản admin đầu tiên
+func (th *TenantHandler) CreateTenant(ctx *gin.Context) {
+	var req dto.CreateTenantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := th.service.CreateTenant(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// GET /api/v1/super-admin/tenants - Danh sách tenant
+func (th *TenantHandler) GetTenants(ctx *gin.Context) {
+	var req dto.GetTenantsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := th.service.GetTenants(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/super-admin/tenants/:tenant_id - Chi tiết tenant
+func (th *TenantHandler) GetTenant(ctx *gin.Context) {
+	ids, ok := parseTenantParams(ctx, "tenant_id")
+	if !ok {
+		return
+	}
+
+	response, err := th.service.GetTenant(ids[0])
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/super-admin/tenants/:tenant_id - Đổi tên hoặc tạm ngưng tenant
+func (th *TenantHandler) UpdateTenant(ctx *gin.Context) {
+	ids, ok := parseTenantParams(ctx, "tenant_id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateTenantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := th.service.UpdateTenant(ids[0], &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/super-admin/tenants/:tenant_id/domains - Gắn hostname cho tenant
+func (th *TenantHandler) AddDomain(ctx *gin.Context) {
+	ids, ok := parseTenantParams(ctx, "tenant_id")
+	if !ok {
+		return
+	}
+
+	var req dto.AddTenantDomainRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := th.service.AddDomain(ids[0], &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// DELETE /api/v1/super-admin/tenants/:tenant_id/domains/:domain_id - Gỡ hostname
+func (th *TenantHandler) RemoveDomain(ctx *gin.Context) {
+	ids, ok := parseTenantParams(ctx, "tenant_id", "domain_id")
+	if !ok {
+		return
+	}
+
+	if err := th.service.RemoveDomain(ids[0], ids[1]); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, gin.H{"message": "Domain removed successfully"})
+}
+
+// GET /api/v1/super-admin/analytics - Số liệu so sánh giữa các tenant
+func (th *TenantHandler) GetCrossTenantAnalytics(ctx *gin.Context) {
+	var req dto.CrossTenantAnalyticsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := th.service.GetCrossTenantAnalytics(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+func parseTenantParams(ctx *gin.Context, params ...string) ([]uint, bool) {
+	ids := make([]uint, len(params))
+	for i, param := range params {
+		id, err := strconv.ParseUint(ctx.Param(param), 10, 32)
+		if err != nil {
+			utils.ResponseError(ctx, utils.NewError("Invalid "+strings.TrimSuffix(param, "_id")+" Id format", utils.ErrCodeBadRequest))
+			return nil, false
+		}
+		ids[i] = uint(id)
+	}
+	return ids, true
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VideoHandler struct {
+	service service.VideoService
+}
+
+func NewVideoHandler(service service.VideoService) *VideoHandler {
+	return &VideoHandler{
+		service: service,
+	}
+}
+
+// POST /api/v1/instructor/courses/:course_id/lessons/:id/video/uploads - Bắt đầu upload video theo chunk
+func (vh *VideoHandler) InitUpload(ctx *gin.Context) {
+	// Lấy course ID từ URL parameter
+	courseId, err := strconv.ParseUint(ctx.Param("course_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid course Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy lesson ID từ URL parameter
+	lessonId, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	// Bind JSON request
+	var req dto.InitVideoUploadRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := vh.service.InitUpload(userId.(uint), uint(courseId), uint(lessonId), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// PUT /api/v1/instructor/videos/uploads/:upload_id/chunks/:index - Upload một chunk (raw body)
+func (vh *VideoHandler) UploadChunk(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	index, err := strconv.Atoi(ctx.Param("index"))
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid chunk index", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := vh.service.UploadChunk(userId.(uint), ctx.Param("upload_id"), index, ctx.Request.Body)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/instructor/videos/uploads/:upload_id - Trạng thái upload (dùng để resume)
+func (vh *VideoHandler) GetUploadStatus(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := vh.service.GetUploadStatus(userId.(uint), ctx.Param("upload_id"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/instructor/videos/uploads/:upload_id/complete - Hoàn tất upload và bắt đầu transcode
+func (vh *VideoHandler) CompleteUpload(ctx *gin.Context) {
+	// Lấy instructor ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := vh.service.CompleteUpload(userId.(uint), ctx.Param("upload_id"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusAccepted, response)
+}
+
+// GET /api/v1/lessons/:lesson_id/playback - Lấy signed URL để stream video
+func (vh *VideoHandler) GetPlayback(ctx *gin.Context) {
+	lessonId, err := strconv.ParseUint(ctx.Param("lesson_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid lesson Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	// Lấy user ID từ context
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	response, err := vh.service.GetPlayback(userId.(uint), uint(lessonId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// GET /api/v1/videos/stream/:token/*filepath - Stream playlist/segment HLS bằng signed token
+func (vh *VideoHandler) Stream(ctx *gin.Context) {
+	stream, err := vh.service.ResolveStream(ctx.Param("token"), ctx.Param("filepath"))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	if stream.RedirectURL != "" {
+		ctx.Redirect(http.StatusFound, stream.RedirectURL)
+		return
+	}
+
+	defer stream.Body.Close()
+	ctx.Header("Cache-Control", "no-store")
+	ctx.DataFromReader(http.StatusOK, -1, stream.ContentType, stream.Body, nil)
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	service service.WebhookService
+}
+
+func NewWebhookHandler(service service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/admin/webhooks/event-types - Danh sách event có thể subscribe
+func (wh *WebhookHandler) GetEventTypes(ctx *gin.Context) {
+	utils.ResponseSuccess(ctx, http.StatusOK, wh.service.GetEventTypes())
+}
+
+// GET /api/v1/admin/webhooks - Danh sách webhook
+func (wh *WebhookHandler) GetWebhooks(ctx *gin.Context) {
+	var req dto.GetWebhooksQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := wh.service.GetWebhooks(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/webhooks - Tạo webhook (secret chỉ trả về một lần)
+func (wh *WebhookHandler) CreateWebhook(ctx *gin.Context) {
+	userId, exists := ctx.Get("user_id")
+	if !exists {
+		utils.ResponseError(ctx, utils.NewError("User information not found in context", utils.ErrCodeUnauthorized))
+		return
+	}
+
+	var req dto.CreateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := wh.service.CreateWebhook(userId.(uint), &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusCreated, response)
+}
+
+// GET /api/v1/admin/webhooks/:webhook_id - Chi tiết webhook
+func (wh *WebhookHandler) GetWebhook(ctx *gin.Context) {
+	webhookId, ok := parseWebhookId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := wh.service.GetWebhook(webhookId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// PUT /api/v1/admin/webhooks/:webhook_id - Cập nhật URL, event types, trạng thái
+func (wh *WebhookHandler) UpdateWebhook(ctx *gin.Context) {
+	webhookId, ok := parseWebhookId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := wh.service.UpdateWebhook(webhookId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// DELETE /api/v1/admin/webhooks/:webhook_id - Xóa webhook
+func (wh *WebhookHandler) DeleteWebhook(ctx *gin.Context) {
+	webhookId, ok := parseWebhookId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := wh.service.DeleteWebhook(webhookId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/webhooks/:webhook_id/rotate-secret - Đổi secret ký payload
+func (wh *WebhookHandler) RotateSecret(ctx *gin.Context) {
+	webhookId, ok := parseWebhookId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := wh.service.RotateSecret(webhookId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/webhooks/:webhook_id/test - Gửi event test tới endpoint
+func (wh *WebhookHandler) SendTestEvent(ctx *gin.Context) {
+	webhookId, ok := parseWebhookId(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := wh.service.SendTestEvent(webhookId)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusAccepted, response)
+}
+
+// GET /api/v1/admin/webhooks/:webhook_id/deliveries - Log gửi webhook
+func (wh *WebhookHandler) GetDeliveries(ctx *gin.Context) {
+	webhookId, ok := parseWebhookId(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.GetWebhookDeliveriesQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	response, err := wh.service.GetDeliveries(webhookId, &req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusOK, response)
+}
+
+// POST /api/v1/admin/webhooks/deliveries/:delivery_id/replay - Gửi lại payload của một delivery
+func (wh *WebhookHandler) ReplayDelivery(ctx *gin.Context) {
+	deliveryId, err := strconv.ParseUint(ctx.Param("delivery_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid delivery Id format", utils.ErrCodeBadRequest))
+		return
+	}
+
+	response, err := wh.service.ReplayDelivery(uint(deliveryId))
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseSuccess(ctx, http.StatusAccepted, response)
+}
+
+func parseWebhookId(ctx *gin.Context) (uint, bool) {
+	webhookId, err := strconv.ParseUint(ctx.Param("webhook_id"), 10, 32)
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Invalid webhook Id format", utils.ErrCodeBadRequest))
+		return 0, false
+	}
+	return uint(webhookId), true
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"io"
+	"lms/src/dto"
+	"lms/src/service"
+	"lms/src/utils"
+	"lms/src/validation"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Giới hạn kích thước request gửi statement
+const xapiMaxBodySize = 5 << 20
+
+// XapiHandler là LRS tích hợp. Response theo định dạng của spec xAPI (không bọc trong "data").
+type XapiHandler struct {
+	service service.XapiService
+}
+
+func NewXapiHandler(service service.XapiService) *XapiHandler {
+	return &XapiHandler{
+		service: service,
+	}
+}
+
+// GET /api/v1/xapi/about - Version xAPI mà LRS hỗ trợ
+func (xh *XapiHandler) About(ctx *gin.Context) {
+	ctx.Header("X-Experience-API-Version", dto.XapiVersion)
+	ctx.JSON(http.StatusOK, xh.service.About())
+}
+
+// PUT /api/v1/xapi/statements?statementId=... - Lưu statement với id cho trước
+func (xh *XapiHandler) PutStatement(ctx *gin.Context) {
+	statementId := ctx.Query("statementId")
+	if statementId == "" {
+		utils.ResponseError(ctx, utils.NewError("statementId parameter is required", utils.ErrCodeBadRequest))
+		return
+	}
+
+	body, ok := readXapiBody(ctx)
+	if !ok {
+		return
+	}
+
+	if err := xh.service.PutStatement(ctx.GetString("xapi_client"), statementId, body); err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	utils.ResponseStatusCode(ctx, http.StatusNoContent)
+}
+
+// POST /api/v1/xapi/statements - Lưu một statement hoặc một batch, trả về mảng id
+func (xh *XapiHandler) PostStatements(ctx *gin.Context) {
+	body, ok := readXapiBody(ctx)
+	if !ok {
+		return
+	}
+
+	ids, err := xh.service.PostStatements(ctx.GetString("xapi_client"), body)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ids)
+}
+
+// GET /api/v1/xapi/statements - Lấy một statement (statementId/voidedStatementId) hoặc query theo filter
+func (xh *XapiHandler) GetStatements(ctx *gin.Context) {
+	var req dto.GetXapiStatementsQueryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		utils.ResponseValidator(ctx, validation.HandlerValidationErrors(err))
+		return
+	}
+
+	ctx.Header("X-Experience-API-Consistent-Through", time.Now().UTC().Format(time.RFC3339Nano))
+
+	if req.StatementId != "" || req.VoidedStatementId != "" {
+		// Lấy theo id không được kết hợp với filter khác
+		if (req.StatementId != "" && req.VoidedStatementId != "") || req.Agent != "" || req.Verb != "" ||
+			req.Activity != "" || req.Registration != "" || req.Since != "" || req.Until != "" || req.Limit > 0 || req.Ascending {
+			utils.ResponseError(ctx, utils.NewError("statementId and voidedStatementId cannot be combined with other parameters", utils.ErrCodeBadRequest))
+			return
+		}
+
+		statementId, voided := req.StatementId, false
+		if req.VoidedStatementId != "" {
+			statementId, voided = req.VoidedStatementId, true
+		}
+
+		statement, err := xh.service.GetStatement(statementId, voided)
+		if err != nil {
+			utils.ResponseError(ctx, err)
+			return
+		}
+
+		ctx.Data(http.StatusOK, "application/json; charset=utf-8", statement)
+		return
+	}
+
+	response, err := xh.service.GetStatements(&req)
+	if err != nil {
+		utils.ResponseError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// readXapiBody đọc body JSON của request gửi statement (không hỗ trợ multipart/mixed cho attachment)
+func readXapiBody(ctx *gin.Context) ([]byte, bool) {
+	if strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		utils.ResponseError(ctx, utils.NewError("Statements with attachment content are not supported, reference attachments by fileUrl", utils.ErrCodeBadRequest))
+		return nil, false
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, xapiMaxBodySize))
+	if err != nil {
+		utils.ResponseError(ctx, utils.NewError("Request body is too large or unreadable", utils.ErrCodeBadRequest))
+		return nil, false
+	}
+
+	return body, true
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"lms/src/models"
 	"lms/src/utils"
 	"net/http"
 	"strings"
@@ -56,12 +57,72 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Token của tenant khác không dùng được ở tenant này
+		if !tokenMatchesTenant(ctx, claims) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token was issued for another tenant",
+				"code":  utils.ErrCodeUnauthorized,
+			})
+			ctx.Abort()
+			return
+		}
+
 		// Lưu thông tin User vào Context
-		ctx.Set("user_id", claims.UserId)
-		ctx.Set("username", claims.Username)
-		ctx.Set("user_email", claims.Email)
-		ctx.Set("user_role", claims.Role)
+		setClaims(ctx, claims)
+
+		ctx.Next()
+	}
+}
+
+// OptionalAuthMiddleware set thông tin user vào context nếu có access token hợp lệ,
+// không có token thì request vẫn được đi tiếp như khách (dùng cho nội dung preview)
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tokenParts := strings.Split(ctx.GetHeader("Authorization"), " ")
+		if len(tokenParts) == 2 && tokenParts[0] == "Bearer" {
+			claims, err := utils.ValidateToken(tokenParts[1])
+			if err == nil && claims.Subject == "access" && tokenMatchesTenant(ctx, claims) {
+				setClaims(ctx, claims)
+			}
+		}
 
 		ctx.Next()
 	}
 }
+
+// QueryTokenMiddleware lấy access token từ query ?access_token= khi không có header Authorization.
+// Dùng trước AuthMiddleware cho route stream (EventSource của trình duyệt không gửi được header).
+// Access log ẩn giá trị của param này (xem RedactQuery).
+func QueryTokenMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("Authorization") == "" {
+			if token := ctx.Query("access_token"); token != "" {
+				ctx.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+
+		ctx.Next()
+	}
+}
+
+func setClaims(ctx *gin.Context, claims *utils.JWTClaims) {
+	ctx.Set("user_id", claims.UserId)
+	ctx.Set("username", claims.Username)
+	ctx.Set("user_email", claims.Email)
+	ctx.Set("user_role", claims.Role)
+	ctx.Set("is_super_admin", claims.SuperAdmin)
+}
+
+// tokenMatchesTenant so tenant trong token với tenant của request (token cũ không có tenant thuộc tenant mặc định)
+func tokenMatchesTenant(ctx *gin.Context, claims *utils.JWTClaims) bool {
+	tokenTenantId := claims.TenantId
+	if tokenTenantId == 0 {
+		tokenTenantId = models.DefaultTenantId
+	}
+
+	requestTenantId := ctx.GetUint("tenant_id")
+	if requestTenantId == 0 {
+		requestTenantId = models.DefaultTenantId
+	}
+	return tokenTenantId == requestTenantId
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"strings"
 	"time"
@@ -134,16 +135,16 @@ func LoggerMiddleware() gin.HandlerFunc {
 		logEvent.
 			Str("method", ctx.Request.Method).
 			Str("path", ctx.Request.URL.Path).
-			Str("query", ctx.Request.URL.RawQuery).
+			Str("query", RedactQuery(ctx.Request.URL.RawQuery)).
 			Str("client_ip", ctx.ClientIP()).
 			Str("user_agent", ctx.Request.UserAgent()).
 			Str("referer", ctx.Request.Referer()).
 			Str("protocol", ctx.Request.Proto).
 			Str("host", ctx.Request.Host).
 			Str("remote_addr", ctx.Request.RemoteAddr).
-			Str("request_uri", ctx.Request.RequestURI).
+			Str("request_uri", redactPath(ctx.Request.RequestURI)).
 			Int64("content_length", ctx.Request.ContentLength).
-			Interface("headers", ctx.Request.Header).
+			Interface("headers", redactHeaders(ctx.Request.Header)).
 			Interface("request_body", requestBody).
 			Int("status_code", statusCode).
 			Interface("response_body", responseBodyParsed).
@@ -152,6 +153,66 @@ func LoggerMiddleware() gin.HandlerFunc {
 	}
 }
 
+// redactedQueryParams là query param chứa credential (access token của route stream), không được ghi ra log
+var redactedQueryParams = []string{"access_token"}
+
+// RedactQuery thay giá trị của các query param chứa credential bằng [REDACTED]
+func RedactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "[UNPARSEABLE]"
+	}
+
+	redacted := false
+	for _, param := range redactedQueryParams {
+		if _, ok := values[param]; ok {
+			values.Set(param, "[REDACTED]")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return rawQuery
+	}
+	return values.Encode()
+}
+
+// redactPath áp dụng RedactQuery cho phần query của path/request URI
+func redactPath(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		return path[:i+1] + RedactQuery(path[i+1:])
+	}
+	return path
+}
+
+// redactHeaders ẩn header Authorization (QueryTokenMiddleware chép token từ query vào đây)
+func redactHeaders(headers http.Header) http.Header {
+	if headers.Get("Authorization") == "" {
+		return headers
+	}
+	redacted := headers.Clone()
+	redacted.Set("Authorization", "[REDACTED]")
+	return redacted
+}
+
+// AccessLogFormatter là format access log của gin (không màu) với query chứa credential đã được ẩn
+func AccessLogFormatter(param gin.LogFormatterParams) string {
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+	return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		param.StatusCode,
+		param.Latency,
+		param.ClientIP,
+		param.Method,
+		redactPath(param.Path),
+		param.ErrorMessage,
+	)
+}
+
 func formatFileSize(size int64) string {
 	switch {
 	case size >= 1<<20:
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"lms/src/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TenantMiddleware gắn tenant của router vào context (handler đọc qua ctx.GetUint("tenant_id"))
+func TenantMiddleware(tenantId uint) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set("tenant_id", tenantId)
+		ctx.Request = ctx.Request.WithContext(utils.WithTenant(ctx.Request.Context(), tenantId))
+
+		ctx.Next()
+	}
+}
+
+// SuperAdminMiddleware chỉ cho super-admin (quản trị mọi tenant) đi tiếp, dùng sau AuthMiddleware
+func SuperAdminMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !ctx.GetBool("is_super_admin") {
+			ctx.JSON(http.StatusForbidden, gin.H{
+				"error": "Access denied. Super-admin required",
+				"code":  utils.ErrCodeForbidden,
+			})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
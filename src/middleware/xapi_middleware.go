@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"lms/src/dto"
+	"lms/src/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// XapiVersionMiddleware gắn header version vào response và yêu cầu client gửi X-Experience-API-Version 1.0.x
+func XapiVersionMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("X-Experience-API-Version", dto.XapiVersion)
+
+		if !strings.HasPrefix(ctx.GetHeader("X-Experience-API-Version"), "1.0") {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "X-Experience-API-Version header with a 1.0.x version is required",
+				"code":  utils.ErrCodeBadRequest,
+			})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// XapiAuthMiddleware xác thực client của LRS tích hợp bằng HTTP Basic.
+// credentials trả về key và hash (SHA-256) secret của tenant, đọc mỗi request để đổi secret có hiệu lực ngay.
+// Không cấu hình credentials thì LRS tích hợp của tenant bị tắt.
+func XapiAuthMiddleware(credentials func() (key, secretHash string)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		expectedKey, expectedSecretHash := credentials()
+		if expectedKey == "" || expectedSecretHash == "" {
+			ctx.JSON(http.StatusForbidden, gin.H{
+				"error": "Built-in LRS is disabled",
+				"code":  utils.ErrCodeForbidden,
+			})
+			ctx.Abort()
+			return
+		}
+
+		key, secret, ok := ctx.Request.BasicAuth()
+		if !ok ||
+			subtle.ConstantTimeCompare([]byte(key), []byte(expectedKey)) != 1 ||
+			subtle.ConstantTimeCompare([]byte(utils.HashToken(secret)), []byte(expectedSecretHash)) != 1 {
+			ctx.Header("WWW-Authenticate", `Basic realm="xAPI"`)
+			ctx.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid LRS credentials",
+				"code":  utils.ErrCodeUnauthorized,
+			})
+			ctx.Abort()
+			return
+		}
+
+		// Client được ghi vào authority của statement
+		ctx.Set("xapi_client", key)
+		ctx.Next()
+	}
+}
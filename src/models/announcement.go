@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Course Announcements ----------------
+// CourseAnnouncement là thông báo của instructor gửi tới học viên đang học course.
+// Status: scheduled (chờ đến PublishAt) -> publishing (đang gửi) -> published
+type CourseAnnouncement struct {
+	Id           uint           `gorm:"primaryKey" json:"id"`
+	CourseId     uint           `gorm:"index;not null" json:"course_id"`
+	Course       Course         `gorm:"foreignKey:CourseId" json:"course,omitempty"`
+	InstructorId uint           `gorm:"index;not null" json:"instructor_id"`
+	Instructor   User           `gorm:"foreignKey:InstructorId" json:"instructor,omitempty"`
+	Title        string         `gorm:"size:200;not null" json:"title"`
+	Body         string         `gorm:"type:text;not null" json:"body"` // HTML đã sanitize
+	Status       string         `gorm:"size:20;default:scheduled;index" json:"status"`
+	PublishAt    time.Time      `gorm:"index;not null" json:"publish_at"`
+	PublishedAt  *time.Time     `json:"published_at"`
+	LockedAt     *time.Time     `json:"-"` // Instance đang gửi nhận announcement lúc này (hết hạn thì instance khác nhận lại)
+	RetryAt      *time.Time     `json:"-"` // Lần gửi lại tiếp theo khi lượt trước lỗi hoặc còn email chưa gửi được
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// AnnouncementReceipt là bản gửi announcement tới từng học viên (feed in-app + email).
+// Token dùng cho tracking pixel và link unsubscribe trong email.
+type AnnouncementReceipt struct {
+	Id             uint               `gorm:"primaryKey" json:"id"`
+	AnnouncementId uint               `gorm:"uniqueIndex:idx_announcement_receipt;not null" json:"announcement_id"`
+	Announcement   CourseAnnouncement `gorm:"foreignKey:AnnouncementId" json:"announcement,omitempty"`
+	UserId         uint               `gorm:"uniqueIndex:idx_announcement_receipt;index;not null" json:"user_id"`
+	User           User               `gorm:"foreignKey:UserId" json:"user,omitempty"`
+	CourseId       uint               `gorm:"index;not null" json:"course_id"`
+	Token          string             `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	ReadAt         *time.Time         `json:"read_at"`
+	EmailSentAt    *time.Time         `json:"email_sent_at"`
+	EmailAttempts  int                `gorm:"default:0" json:"-"`
+	EmailError     string             `gorm:"type:text" json:"-"`
+	EmailOpenedAt  *time.Time         `json:"email_opened_at"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+// AnnouncementUnsubscribe đánh dấu học viên không nhận email announcement của course
+type AnnouncementUnsubscribe struct {
+	Id        uint      `gorm:"primaryKey" json:"id"`
+	UserId    uint      `gorm:"uniqueIndex:idx_announcement_unsubscribe;not null" json:"user_id"`
+	CourseId  uint      `gorm:"uniqueIndex:idx_announcement_unsubscribe;not null" json:"course_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
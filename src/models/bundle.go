@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Course bundles ----------------
+// Bundle bán nhiều course với một giá, giá từng course được phân bổ lại khi mua (xem BundlePurchase)
+type Bundle struct {
+	Id            uint           `gorm:"primaryKey" json:"id"`
+	TenantId      uint           `gorm:"not null;default:1;uniqueIndex:idx_bundles_tenant_slug" json:"-"`
+	Title         string         `gorm:"size:200;not null" json:"title"`
+	Slug          string         `gorm:"uniqueIndex:idx_bundles_tenant_slug;size:200;not null" json:"slug"`
+	Description   string         `json:"description"`
+	ThumbnailURL  string         `gorm:"size:255" json:"thumbnail_url"`
+	Price         float64        `gorm:"not null;default:0" json:"price"`
+	DiscountPrice *float64       `json:"discount_price"`
+	CreatedBy     uint           `json:"created_by"`
+	Status        string         `gorm:"size:20;default:draft" json:"status"` // draft, published, archived
+	Courses       []BundleCourse `gorm:"foreignKey:BundleId" json:"courses"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type BundleCourse struct {
+	Id        uint      `gorm:"primaryKey" json:"id"`
+	BundleId  uint      `gorm:"uniqueIndex:idx_bundle_course;not null" json:"bundle_id"`
+	CourseId  uint      `gorm:"uniqueIndex:idx_bundle_course;not null" json:"course_id"`
+	Course    Course    `gorm:"foreignKey:CourseId" json:"course"`
+	Position  int       `gorm:"not null" json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BundlePurchase là một lần mua bundle. Mỗi course được ghi danh có một Order riêng (BundlePurchaseId)
+// với FinalPrice là phần giá bundle phân bổ cho course đó, nên doanh thu vẫn tính theo course và instructor.
+// Purchase được tạo ở trạng thái pending trước khi thu tiền, chuyển sang paid khi đã ghi danh;
+// thu tiền xong mà ghi danh lỗi thì giao dịch được hoàn (refunded) hoặc chờ hoàn thủ công (refund_pending).
+type BundlePurchase struct {
+	Id             uint       `gorm:"primaryKey" json:"id"`
+	TenantId       uint       `gorm:"not null;default:1;index" json:"-"`
+	BundleId       uint       `gorm:"index;not null" json:"bundle_id"`
+	Bundle         Bundle     `gorm:"foreignKey:BundleId" json:"bundle"`
+	UserId         uint       `gorm:"index;not null" json:"user_id"`
+	PurchaseCode   string     `gorm:"uniqueIndex;size:50;not null" json:"purchase_code"`
+	BundlePrice    float64    `gorm:"not null" json:"bundle_price"`     // Giá bundle lúc mua
+	OriginalPrice  float64    `gorm:"not null" json:"original_price"`   // Tổng giá lẻ của các course được ghi danh
+	FinalPrice     float64    `gorm:"not null" json:"final_price"`      // Số tiền thực thu
+	CoursesCount   int        `gorm:"not null" json:"courses_count"`    // Số course được ghi danh
+	SkippedCourses int        `gorm:"default:0" json:"skipped_courses"` // Course user đã sở hữu từ trước
+	PaymentMethod  string     `gorm:"size:50" json:"payment_method"`
+	TransactionId  string     `gorm:"size:100" json:"transaction_id"`
+	Status         string     `gorm:"size:20;not null;default:'paid';index" json:"status"` // pending, paid, failed, refunded, refund_pending, expired
+	PaidAt         *time.Time `json:"paid_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -9,8 +9,9 @@ import (
 // ---------------- Categories ----------------
 type Category struct {
 	Id          uint           `gorm:"primaryKey" json:"id"`
+	TenantId    uint           `gorm:"not null;default:1;uniqueIndex:idx_categories_tenant_slug" json:"-"`
 	Name        string         `gorm:"size:100;not null" json:"name"`
-	Slug        string         `gorm:"uniqueIndex;size:100;not null" json:"slug"`
+	Slug        string         `gorm:"uniqueIndex:idx_categories_tenant_slug;size:100;not null" json:"slug"`
 	Description string         `json:"description"`
 	ImageURL    string         `gorm:"size:255" json:"image_url"`
 	ParentId    *uint          `json:"parent_id"`
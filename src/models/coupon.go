@@ -9,7 +9,8 @@ import (
 // ---------------- Coupons ----------------
 type Coupon struct {
 	Id                uint           `gorm:"primaryKey" json:"id"`
-	Code              string         `gorm:"uniqueIndex;size:50;not null" json:"code"`
+	TenantId          uint           `gorm:"not null;default:1;uniqueIndex:idx_coupons_tenant_code" json:"-"`
+	Code              string         `gorm:"uniqueIndex:idx_coupons_tenant_code;size:50;not null" json:"code"`
 	Description       string         `gorm:"size:200" json:"description"`
 	DiscountType      string         `gorm:"size:20" json:"discount_type"` // percentage, fixed
 	DiscountValue     float64        `gorm:"not null" json:"discount_value"`
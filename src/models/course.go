@@ -8,8 +8,9 @@ import (
 
 type Course struct {
 	Id              uint           `gorm:"primaryKey" json:"id"`
+	TenantId        uint           `gorm:"not null;default:1;uniqueIndex:idx_courses_tenant_slug" json:"-"`
 	Title           string         `gorm:"size:200;not null" json:"title"`
-	Slug            string         `gorm:"uniqueIndex;size:200;not null" json:"slug"`
+	Slug            string         `gorm:"uniqueIndex:idx_courses_tenant_slug;size:200;not null" json:"slug"`
 	Description     string         `json:"description"`
 	ShortDesc       string         `gorm:"size:500" json:"short_description"`
 	ThumbnailURL    string         `gorm:"size:255" json:"thumbnail_url"`
@@ -26,7 +27,7 @@ type Course struct {
 	Language        string         `gorm:"size:10;default:vi" json:"language"`
 	Requirements    string         `json:"requirements"`
 	WhatYouLearn    string         `json:"what_you_learn"`
-	Status          string         `gorm:"size:20;default:draft" json:"status"` // draft, published, archived
+	Status          string         `gorm:"size:20;default:draft" json:"status"` // draft, pending_review, published, archived
 	IsFeatured      bool           `gorm:"default:false" json:"is_featured"`
 	RatingAvg       float32        `gorm:"default:0" json:"rating_avg"`
 	RatingCount     int            `gorm:"default:0" json:"rating_count"`
@@ -34,4 +35,17 @@ type Course struct {
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Prerequisites: warn = chỉ cảnh báo, block = chặn enroll/mua khi chưa hoàn thành
+	PrerequisiteMode string `gorm:"size:10;default:warn" json:"prerequisite_mode"`
+
+	// Duyệt course: thời điểm gửi duyệt, admin duyệt gần nhất và phản hồi khi bị từ chối
+	SubmittedAt    *time.Time `json:"submitted_at"`
+	ReviewedBy     *uint      `json:"reviewed_by"`
+	ReviewedAt     *time.Time `json:"reviewed_at"`
+	ReviewFeedback string     `gorm:"type:text" json:"review_feedback"`
+
+	// Template: admin đánh dấu để mọi instructor có thể tạo course mới từ course này
+	IsTemplate   bool  `gorm:"default:false;index" json:"is_template"`
+	CopiedFromId *uint `json:"copied_from_id"` // course gốc khi được tạo bằng duplicate
 }
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ---------------- Course approval ----------------
+// CourseStatusHistory ghi lại mọi lần đổi trạng thái course (ai đổi, hành động và nhận xét)
+type CourseStatusHistory struct {
+	Id            uint      `gorm:"primaryKey" json:"id"`
+	CourseId      uint      `gorm:"index;not null" json:"course_id"`
+	FromStatus    string    `gorm:"size:20" json:"from_status"`
+	ToStatus      string    `gorm:"size:20;not null" json:"to_status"`
+	Action        string    `gorm:"size:30;not null" json:"action"` // submitted, withdrawn, approved, rejected, status_changed
+	Comment       string    `gorm:"type:text" json:"comment"`
+	ChangedBy     uint      `gorm:"not null" json:"changed_by"`
+	ChangedByUser User      `gorm:"foreignKey:ChangedBy" json:"changed_by_user"`
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+}
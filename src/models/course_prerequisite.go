@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// ---------------- Course Prerequisites ----------------
+type CoursePrerequisite struct {
+	Id                   uint      `gorm:"primaryKey" json:"id"`
+	CourseId             uint      `gorm:"uniqueIndex:idx_course_prerequisite;not null" json:"course_id"`
+	PrerequisiteCourseId uint      `gorm:"uniqueIndex:idx_course_prerequisite;not null" json:"prerequisite_course_id"`
+	PrerequisiteCourse   Course    `gorm:"foreignKey:PrerequisiteCourseId" json:"prerequisite_course"`
+	CreatedAt            time.Time `json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// ---------------- Course revisions ----------------
+// CourseRevision là một phiên bản nội dung (metadata + curriculum) của course đã publish.
+// Mỗi course có tối đa một bản draft; bản published mới nhất là nội dung đang hiển thị.
+type CourseRevision struct {
+	Id                  uint       `gorm:"primaryKey" json:"id"`
+	CourseId            uint       `gorm:"index;not null" json:"course_id"`
+	Version             int        `gorm:"not null;default:0" json:"version"`          // 0 khi còn là draft
+	Status              string     `gorm:"size:20;default:draft;index" json:"status"`  // draft, published, discarded
+	Snapshot            string     `gorm:"type:text;not null" json:"-"`                // JSON dto.CourseSnapshot
+	BasedOnVersion      int        `gorm:"not null;default:0" json:"based_on_version"` // version đang live khi tạo draft
+	RestoredFromVersion *int       `json:"restored_from_version"`                      // rollback: version được khôi phục
+	Changelog           string     `gorm:"type:text" json:"changelog"`
+	Summary             string     `gorm:"size:500" json:"summary"` // tóm tắt thay đổi tự động
+	CreatedBy           uint       `gorm:"not null" json:"created_by"`
+	Creator             User       `gorm:"foreignKey:CreatedBy" json:"creator"`
+	PublishedBy         *uint      `json:"published_by"`
+	PublishedAt         *time.Time `json:"published_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
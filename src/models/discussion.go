@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Discussions (Q&A) ----------------
+// DiscussionThread là câu hỏi/thảo luận của course, gắn với một lesson nếu LessonId != nil
+type DiscussionThread struct {
+	Id               uint           `gorm:"primaryKey" json:"id"`
+	CourseId         uint           `gorm:"index;not null" json:"course_id"`
+	Course           Course         `gorm:"foreignKey:CourseId" json:"course"`
+	LessonId         *uint          `gorm:"index" json:"lesson_id"`
+	Lesson           *Lesson        `gorm:"foreignKey:LessonId" json:"lesson,omitempty"`
+	UserId           uint           `gorm:"index;not null" json:"user_id"`
+	User             User           `gorm:"foreignKey:UserId" json:"user"`
+	Title            string         `gorm:"size:200;not null" json:"title"`
+	Body             string         `gorm:"type:text;not null" json:"body"`
+	IsPinned         bool           `gorm:"default:false" json:"is_pinned"`
+	IsInstructorOnly bool           `gorm:"default:false" json:"is_instructor_only"` // chỉ người hỏi và instructor/TA thấy
+	IsAnswered       bool           `gorm:"default:false;index" json:"is_answered"`
+	AnswerReplyId    *uint          `json:"answer_reply_id"`
+	UpvoteCount      int            `gorm:"default:0" json:"upvote_count"`
+	ReplyCount       int            `gorm:"default:0" json:"reply_count"`
+	LastActivityAt   time.Time      `json:"last_activity_at"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type DiscussionReply struct {
+	Id          uint           `gorm:"primaryKey" json:"id"`
+	ThreadId    uint           `gorm:"index;not null" json:"thread_id"`
+	UserId      uint           `gorm:"not null" json:"user_id"`
+	User        User           `gorm:"foreignKey:UserId" json:"user"`
+	Body        string         `gorm:"type:text;not null" json:"body"`
+	IsAnswer    bool           `gorm:"default:false" json:"is_answer"`
+	UpvoteCount int            `gorm:"default:0" json:"upvote_count"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// DiscussionUpvote lưu upvote của user cho thread hoặc reply (mỗi user một lần)
+type DiscussionUpvote struct {
+	Id         uint      `gorm:"primaryKey" json:"id"`
+	UserId     uint      `gorm:"uniqueIndex:idx_discussion_upvote;not null" json:"user_id"`
+	TargetType string    `gorm:"size:10;uniqueIndex:idx_discussion_upvote;not null" json:"target_type"` // thread, reply
+	TargetId   uint      `gorm:"uniqueIndex:idx_discussion_upvote;not null" json:"target_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CourseAssistant là trợ giảng (TA) của course, có quyền trả lời và quản lý Q&A như instructor
+type CourseAssistant struct {
+	Id        uint      `gorm:"primaryKey" json:"id"`
+	CourseId  uint      `gorm:"uniqueIndex:idx_course_assistant;not null" json:"course_id"`
+	UserId    uint      `gorm:"uniqueIndex:idx_course_assistant;not null" json:"user_id"`
+	User      User      `gorm:"foreignKey:UserId" json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
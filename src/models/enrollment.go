@@ -18,6 +18,7 @@ type Enrollment struct {
 	ProgressPercentage float64        `gorm:"default:0" json:"progress_percentage"`
 	LastAccessedAt     *time.Time     `json:"last_accessed_at"`
 	Status             string         `gorm:"size:20;default:active" json:"status"` // active, completed, dropped
+	SubscriptionId     *uint          `gorm:"index" json:"subscription_id"`         // Ghi danh qua subscription: chỉ học được khi còn subscription bao phủ course
 	CreatedAt          time.Time      `json:"created_at"`
 	UpdatedAt          time.Time      `json:"updated_at"`
 	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Learning Paths ----------------
+type LearningPath struct {
+	Id           uint                 `gorm:"primaryKey" json:"id"`
+	TenantId     uint                 `gorm:"not null;default:1;uniqueIndex:idx_learning_paths_tenant_slug" json:"-"`
+	Title        string               `gorm:"size:200;not null" json:"title"`
+	Slug         string               `gorm:"uniqueIndex:idx_learning_paths_tenant_slug;size:200;not null" json:"slug"`
+	Description  string               `json:"description"`
+	ThumbnailURL string               `gorm:"size:255" json:"thumbnail_url"`
+	CreatedBy    uint                 `json:"created_by"`
+	Creator      User                 `gorm:"foreignKey:CreatedBy" json:"creator"`
+	Status       string               `gorm:"size:20;default:draft" json:"status"` // draft, published, archived
+	IsFeatured   bool                 `gorm:"default:false" json:"is_featured"`
+	Courses      []LearningPathCourse `gorm:"foreignKey:LearningPathId" json:"courses"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt       `gorm:"index" json:"-"`
+}
+
+type LearningPathCourse struct {
+	Id             uint      `gorm:"primaryKey" json:"id"`
+	LearningPathId uint      `gorm:"index;not null" json:"learning_path_id"`
+	CourseId       uint      `gorm:"not null" json:"course_id"`
+	Course         Course    `gorm:"foreignKey:CourseId" json:"course"`
+	Position       int       `gorm:"not null" json:"position"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type LearningPathCertificate struct {
+	Id              uint         `gorm:"primaryKey" json:"id"`
+	UserId          uint         `gorm:"uniqueIndex:idx_path_certificate_user;not null" json:"user_id"`
+	LearningPathId  uint         `gorm:"uniqueIndex:idx_path_certificate_user;not null" json:"learning_path_id"`
+	LearningPath    LearningPath `gorm:"foreignKey:LearningPathId" json:"learning_path"`
+	CertificateCode string       `gorm:"uniqueIndex;size:50;not null" json:"certificate_code"`
+	IssuedAt        time.Time    `json:"issued_at"`
+	CreatedAt       time.Time    `json:"created_at"`
+}
@@ -8,18 +8,33 @@ import (
 
 // ---------------- Lessons ----------------
 type Lesson struct {
-	Id            uint           `gorm:"primaryKey" json:"id"`
-	CourseId      uint           `json:"course_id"`
-	Title         string         `gorm:"size:200;not null" json:"title"`
-	Slug          string         `gorm:"size:200;not null" json:"slug"`
-	Description   string         `json:"description"`
-	Content       string         `json:"content"`
-	VideoURL      string         `gorm:"size:255" json:"video_url"`
-	VideoDuration int            `json:"video_duration"`
-	LessonOrder   int            `gorm:"not null" json:"lesson_order"`
-	IsPreview     bool           `gorm:"default:false" json:"is_preview"`
-	IsPublished   bool           `gorm:"default:true" json:"is_published"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	Id            uint   `gorm:"primaryKey" json:"id"`
+	CourseId      uint   `json:"course_id"`
+	Title         string `gorm:"size:200;not null" json:"title"`
+	Slug          string `gorm:"size:200;not null" json:"slug"`
+	Description   string `json:"description"`
+	Content       string `json:"content"`
+	VideoURL      string `gorm:"size:255" json:"video_url"`
+	VideoDuration int    `json:"video_duration"`
+	LessonOrder   int    `gorm:"not null" json:"lesson_order"`
+	IsPreview     bool   `gorm:"default:false" json:"is_preview"`
+	IsPublished   bool   `gorm:"default:true" json:"is_published"`
+	LessonType    string `gorm:"size:20;default:standard" json:"lesson_type"` // standard, scorm
+
+	// Drip-feed: mở khóa sau N ngày kể từ khi enroll hoặc vào một ngày cố định
+	UnlockAfterDays *int       `json:"unlock_after_days"`
+	UnlockAt        *time.Time `json:"unlock_at"`
+
+	// Prerequisites: yêu cầu hoàn thành lesson trước đó hoặc pass một quiz
+	RequirePreviousCompletion bool  `gorm:"default:false" json:"require_previous_completion"`
+	PrerequisiteQuizId        *uint `json:"prerequisite_quiz_id"`
+
+	// Video pipeline: trạng thái transcode HLS (processing, ready, failed)
+	VideoStatus string `gorm:"size:20" json:"video_status"`
+	HLSPath     string `gorm:"size:255" json:"-"`
+	PosterURL   string `gorm:"size:255" json:"poster_url"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
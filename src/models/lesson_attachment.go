@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Lesson Attachments ----------------
+// LessonAttachment là tài liệu đính kèm lesson (slides, source code, worksheet), lưu private trên storage
+type LessonAttachment struct {
+	Id            uint           `gorm:"primaryKey" json:"id"`
+	LessonId      uint           `gorm:"index;not null" json:"lesson_id"`
+	Title         string         `gorm:"size:200;not null" json:"title"`
+	FileName      string         `gorm:"size:255;not null" json:"file_name"`
+	FileSize      int64          `gorm:"not null" json:"file_size"`
+	MimeType      string         `gorm:"size:150;not null" json:"mime_type"`
+	StorageKey    string         `gorm:"size:255;not null" json:"-"`
+	DownloadCount int            `gorm:"default:0" json:"download_count"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Lesson Notes ----------------
+// LessonNote là ghi chú riêng của học viên trên một lesson.
+// Position (giây) gắn ghi chú với một thời điểm trong video - dùng như bookmark.
+type LessonNote struct {
+	Id        uint           `gorm:"primaryKey" json:"id"`
+	UserId    uint           `gorm:"index:idx_lesson_note_user_lesson;not null" json:"user_id"`
+	CourseId  uint           `gorm:"index;not null" json:"course_id"`
+	Course    Course         `gorm:"foreignKey:CourseId" json:"course,omitempty"`
+	LessonId  uint           `gorm:"index:idx_lesson_note_user_lesson;not null" json:"lesson_id"`
+	Lesson    Lesson         `gorm:"foreignKey:LessonId" json:"lesson,omitempty"`
+	Content   string         `gorm:"type:text;not null" json:"content"`
+	Position  *int           `json:"position"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ---------------- Subtitles & Transcripts ----------------
+// LessonSubtitle là track phụ đề WebVTT của lesson theo ngôn ngữ (file lưu private trên storage)
+type LessonSubtitle struct {
+	Id           uint      `gorm:"primaryKey" json:"id"`
+	LessonId     uint      `gorm:"uniqueIndex:idx_lesson_subtitle_language;not null" json:"lesson_id"`
+	Language     string    `gorm:"size:10;uniqueIndex:idx_lesson_subtitle_language;not null" json:"language"`
+	Label        string    `gorm:"size:50" json:"label"`
+	SourceFormat string    `gorm:"size:10;not null" json:"source_format"` // vtt, srt
+	StorageKey   string    `gorm:"size:255;not null" json:"-"`
+	CueCount     int       `gorm:"not null" json:"cue_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TranscriptCue là một đoạn transcript (từ subtitle) được index full-text để tìm kiếm theo nội dung bài giảng
+type TranscriptCue struct {
+	Id         uint   `gorm:"primaryKey" json:"id"`
+	SubtitleId uint   `gorm:"index;not null" json:"subtitle_id"`
+	LessonId   uint   `gorm:"index;not null" json:"lesson_id"`
+	Language   string `gorm:"size:10;not null" json:"language"`
+	StartMs    int    `gorm:"not null" json:"start_ms"`
+	EndMs      int    `gorm:"not null" json:"end_ms"`
+	Text       string `gorm:"type:text;not null" json:"text"`
+}
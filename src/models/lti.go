@@ -0,0 +1,92 @@
+package models
+
+import "time"
+
+// ---------------- LTI 1.3 (tool provider) ----------------
+// LtiPlatform là một LMS bên ngoài (Moodle, Canvas, ...) đã đăng ký dùng một tenant như một LTI tool
+type LtiPlatform struct {
+	Id            uint      `gorm:"primaryKey" json:"id"`
+	TenantId      uint      `gorm:"not null;default:1;uniqueIndex:idx_lti_platforms_tenant_client" json:"-"`
+	Name          string    `gorm:"size:200;not null" json:"name"`
+	Issuer        string    `gorm:"size:500;not null;uniqueIndex:idx_lti_platforms_tenant_client" json:"issuer"`
+	ClientId      string    `gorm:"size:255;not null;uniqueIndex:idx_lti_platforms_tenant_client" json:"client_id"`
+	DeploymentIds string    `gorm:"type:text" json:"deployment_ids"`         // Danh sách phân tách bằng dấu phẩy, rỗng = chấp nhận mọi deployment
+	AuthLoginURL  string    `gorm:"size:500;not null" json:"auth_login_url"` // OIDC authorization endpoint
+	AuthTokenURL  string    `gorm:"size:500" json:"auth_token_url"`          // OAuth2 token endpoint (dùng cho Assignment and Grade Services)
+	JwksURL       string    `gorm:"size:500;not null" json:"jwks_url"`
+	IsActive      bool      `gorm:"default:true" json:"is_active"`
+	CreatedBy     uint      `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// LtiToolKey là RSA key của tool, dùng ký client assertion và deep linking response (public key công bố qua JWKS)
+type LtiToolKey struct {
+	Id         uint      `gorm:"primaryKey" json:"id"`
+	Kid        string    `gorm:"uniqueIndex;size:64;not null" json:"kid"`
+	PrivateKey string    `gorm:"type:text;not null" json:"-"` // PEM (PKCS#8)
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// LtiLaunchState lưu state/nonce của bước OIDC login initiation, dùng một lần khi launch
+type LtiLaunchState struct {
+	Id         uint      `gorm:"primaryKey" json:"id"`
+	State      string    `gorm:"uniqueIndex;size:100;not null" json:"state"`
+	Nonce      string    `gorm:"size:100;not null" json:"nonce"`
+	PlatformId uint      `gorm:"index" json:"platform_id"`
+	ExpiresAt  time.Time `gorm:"index" json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// LtiUserLink liên kết user của platform (sub) với tài khoản được tự động tạo
+type LtiUserLink struct {
+	Id         uint      `gorm:"primaryKey" json:"id"`
+	PlatformId uint      `gorm:"not null;uniqueIndex:idx_lti_user_subject" json:"platform_id"`
+	Subject    string    `gorm:"size:255;not null;uniqueIndex:idx_lti_user_subject" json:"subject"`
+	UserId     uint      `gorm:"index;not null" json:"user_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// LtiResourceLink là một liên kết trong LMS bên ngoài trỏ tới course (hoặc một lesson)
+type LtiResourceLink struct {
+	Id             uint      `gorm:"primaryKey" json:"id"`
+	PlatformId     uint      `gorm:"not null;uniqueIndex:idx_lti_resource_link" json:"platform_id"`
+	ResourceLinkId string    `gorm:"size:255;not null;uniqueIndex:idx_lti_resource_link" json:"resource_link_id"`
+	DeploymentId   string    `gorm:"size:255" json:"deployment_id"`
+	ContextId      string    `gorm:"size:255" json:"context_id"`
+	ContextTitle   string    `gorm:"size:255" json:"context_title"`
+	Title          string    `gorm:"size:255" json:"title"`
+	CourseId       uint      `gorm:"index;not null" json:"course_id"`
+	LessonId       *uint     `gorm:"index" json:"lesson_id"`
+	LineItemURL    string    `gorm:"size:500" json:"line_item_url"` // Cột điểm trong gradebook của LMS (AGS)
+	AgsScopes      string    `gorm:"type:text" json:"ags_scopes"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// LtiResourceLinkUser ghi nhận user đã launch resource link, dùng để gửi điểm về LMS
+type LtiResourceLinkUser struct {
+	Id             uint            `gorm:"primaryKey" json:"id"`
+	ResourceLinkId uint            `gorm:"not null;uniqueIndex:idx_lti_resource_link_user" json:"resource_link_id"`
+	ResourceLink   LtiResourceLink `gorm:"foreignKey:ResourceLinkId" json:"resource_link"`
+	UserId         uint            `gorm:"not null;uniqueIndex:idx_lti_resource_link_user" json:"user_id"`
+	Subject        string          `gorm:"size:255;not null" json:"subject"`
+	LastScore      *float64        `json:"last_score"`
+	LastSyncedAt   *time.Time      `json:"last_synced_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// LtiDeepLinkSession giữ deep linking request trong lúc người dùng chọn course trên frontend
+type LtiDeepLinkSession struct {
+	Id             uint       `gorm:"primaryKey" json:"id"`
+	TokenHash      string     `gorm:"uniqueIndex;size:64;not null" json:"-"`
+	PlatformId     uint       `gorm:"index" json:"platform_id"`
+	DeploymentId   string     `gorm:"size:255" json:"deployment_id"`
+	ReturnURL      string     `gorm:"size:1000;not null" json:"return_url"`
+	Data           string     `gorm:"type:text" json:"data"` // Giá trị "data" phải gửi lại nguyên vẹn cho platform
+	AcceptLineItem bool       `gorm:"default:false" json:"accept_line_item"`
+	ContextTitle   string     `gorm:"size:255" json:"context_title"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	UsedAt         *time.Time `json:"used_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// ---------------- Notifications ----------------
+// Notification là thông báo in-app của user (đơn hàng, enrollment, review, certificate, Q&A)
+type Notification struct {
+	Id        uint       `gorm:"primaryKey" json:"id"`
+	UserId    uint       `gorm:"index:idx_notification_user_created;not null" json:"user_id"`
+	Type      string     `gorm:"size:50;not null" json:"type"`
+	Title     string     `gorm:"size:200;not null" json:"title"`
+	Message   string     `gorm:"type:text" json:"message"`
+	Link      string     `gorm:"size:500" json:"link"` // Đường dẫn frontend khi click
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `gorm:"index:idx_notification_user_created" json:"created_at"`
+}
+
+// NotificationPreference lưu lựa chọn bật/tắt theo loại thông báo (không có record = bật)
+type NotificationPreference struct {
+	Id        uint      `gorm:"primaryKey" json:"id"`
+	UserId    uint      `gorm:"uniqueIndex:idx_notification_preference;not null" json:"user_id"`
+	Type      string    `gorm:"uniqueIndex:idx_notification_preference;size:50;not null" json:"type"`
+	Enabled   bool      `gorm:"not null" json:"enabled"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -9,6 +9,7 @@ import (
 // ---------------- Orders ----------------
 type Order struct {
 	Id             uint           `gorm:"primaryKey" json:"id"`
+	TenantId       uint           `gorm:"not null;default:1;index" json:"-"`
 	UserId         uint           `json:"user_id"`
 	User           User           `gorm:"foreignKey:UserId" json:"user"` // ✅ THÊM NẾU CHƯA CÓ
 	CourseId       uint           `json:"course_id"`
@@ -24,4 +25,7 @@ type Order struct {
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Order sinh ra khi mua bundle: FinalPrice là phần giá bundle phân bổ cho course này
+	BundlePurchaseId *uint `gorm:"index" json:"bundle_purchase_id"`
 }
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Organizations (B2B) ----------------
+// Organization là công ty mua seat cho nhân viên
+type Organization struct {
+	Id             uint           `gorm:"primaryKey" json:"id"`
+	TenantId       uint           `gorm:"not null;default:1;uniqueIndex:idx_organizations_tenant_slug" json:"-"`
+	Name           string         `gorm:"size:200;not null" json:"name"`
+	Slug           string         `gorm:"uniqueIndex:idx_organizations_tenant_slug;size:200;not null" json:"slug"`
+	BillingName    string         `gorm:"size:200" json:"billing_name"`
+	BillingEmail   string         `gorm:"size:100" json:"billing_email"`
+	BillingAddress string         `gorm:"type:text" json:"billing_address"`
+	TaxId          string         `gorm:"size:50" json:"tax_id"`
+	Status         string         `gorm:"size:20;default:active;index" json:"status"` // active, suspended
+	CreatedBy      uint           `json:"created_by"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// OrganizationMember: thành viên của organization, admin quản lý thành viên, seat và hóa đơn
+type OrganizationMember struct {
+	Id             uint         `gorm:"primaryKey" json:"id"`
+	OrganizationId uint         `gorm:"not null;uniqueIndex:idx_organization_member" json:"organization_id"`
+	Organization   Organization `gorm:"foreignKey:OrganizationId" json:"organization"`
+	UserId         uint         `gorm:"not null;uniqueIndex:idx_organization_member;index" json:"user_id"`
+	User           User         `gorm:"foreignKey:UserId" json:"user"`
+	Role           string       `gorm:"size:20;default:member" json:"role"` // admin, member
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// OrganizationLicense là một lần mua seat: cho một course hoặc cho toàn bộ catalog.
+// Seat của license catalog cho phép gán người đó vào bất kỳ course đã publish nào.
+type OrganizationLicense struct {
+	Id             uint       `gorm:"primaryKey" json:"id"`
+	OrganizationId uint       `gorm:"not null;index" json:"organization_id"`
+	Scope          string     `gorm:"size:20;not null" json:"scope"` // course, catalog
+	CourseId       *uint      `gorm:"index" json:"course_id"`
+	Course         *Course    `gorm:"foreignKey:CourseId" json:"course,omitempty"`
+	Seats          int        `gorm:"not null" json:"seats"`
+	UnitPrice      float64    `gorm:"not null" json:"unit_price"`
+	TotalPrice     float64    `gorm:"not null" json:"total_price"`
+	Status         string     `gorm:"size:20;default:pending;index" json:"status"` // pending (chờ thanh toán), active, cancelled
+	PurchasedBy    uint       `json:"purchased_by"`
+	ActivatedAt    *time.Time `json:"activated_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// OrganizationSeatAssignment gán một seat của license cho thành viên trong một course.
+// Seat được tính theo số người đang giữ (một người giữ seat catalog có thể được gán nhiều course).
+type OrganizationSeatAssignment struct {
+	Id             uint       `gorm:"primaryKey" json:"id"`
+	OrganizationId uint       `gorm:"not null;index" json:"organization_id"`
+	LicenseId      uint       `gorm:"not null;index" json:"license_id"`
+	UserId         uint       `gorm:"not null;index" json:"user_id"`
+	User           User       `gorm:"foreignKey:UserId" json:"user"`
+	CourseId       uint       `gorm:"not null;index" json:"course_id"`
+	Course         Course     `gorm:"foreignKey:CourseId" json:"course"`
+	EnrollmentId   uint       `gorm:"not null" json:"enrollment_id"`
+	AssignedBy     uint       `json:"assigned_by"`
+	AssignedAt     time.Time  `json:"assigned_at"`
+	ReclaimedAt    *time.Time `gorm:"index" json:"reclaimed_at"` // NULL = đang dùng seat
+}
+
+// OrganizationInvoice là hóa đơn của một lần mua seat, thông tin thanh toán được chụp lại lúc xuất
+type OrganizationInvoice struct {
+	Id             uint       `gorm:"primaryKey" json:"id"`
+	OrganizationId uint       `gorm:"not null;index" json:"organization_id"`
+	LicenseId      uint       `gorm:"not null;uniqueIndex" json:"license_id"`
+	InvoiceNumber  string     `gorm:"uniqueIndex;size:50;not null" json:"invoice_number"`
+	Description    string     `gorm:"size:500" json:"description"`
+	Quantity       int        `gorm:"not null" json:"quantity"`
+	UnitPrice      float64    `gorm:"not null" json:"unit_price"`
+	Total          float64    `gorm:"not null" json:"total"`
+	Status         string     `gorm:"size:20;default:open;index" json:"status"` // open, paid, void
+	BillingName    string     `gorm:"size:200" json:"billing_name"`
+	BillingEmail   string     `gorm:"size:100" json:"billing_email"`
+	BillingAddress string     `gorm:"type:text" json:"billing_address"`
+	TaxId          string     `gorm:"size:50" json:"tax_id"`
+	PaymentMethod  string     `gorm:"size:50" json:"payment_method"`
+	IssuedAt       time.Time  `json:"issued_at"`
+	DueAt          time.Time  `json:"due_at"`
+	PaidAt         *time.Time `json:"paid_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// ---------------- Domain events (transactional outbox) ----------------
+// OutboxEvent là domain event được ghi cùng transaction với thay đổi dữ liệu,
+// dispatcher đọc bảng này để gửi tới các subscriber (có retry)
+type OutboxEvent struct {
+	Id            uint       `gorm:"primaryKey" json:"id"`
+	TenantId      uint       `gorm:"not null;default:1;index" json:"tenant_id"` // Tenant của transaction ghi event, subscriber dùng để xử lý đúng tenant
+	EventType     string     `gorm:"size:100;not null;index" json:"event_type"`
+	AggregateId   uint       `gorm:"index" json:"aggregate_id"`
+	Payload       string     `gorm:"type:text;not null" json:"payload"`                                 // JSON của event
+	Status        string     `gorm:"size:20;default:pending;index:idx_outbox_events_due" json:"status"` // pending, processing, processed, failed
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index:idx_outbox_events_due" json:"next_attempt_at"`
+	LockedAt      *time.Time `json:"locked_at"`
+	LastError     string     `gorm:"type:text" json:"last_error"`
+	ProcessedAt   *time.Time `json:"processed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// OutboxDelivery đánh dấu subscriber đã xử lý xong event, khi retry sẽ bỏ qua subscriber này
+type OutboxDelivery struct {
+	Id          uint      `gorm:"primaryKey" json:"id"`
+	EventId     uint      `gorm:"uniqueIndex:idx_outbox_delivery_subscriber;not null" json:"event_id"`
+	Subscriber  string    `gorm:"uniqueIndex:idx_outbox_delivery_subscriber;size:100;not null" json:"subscriber"`
+	DeliveredAt time.Time `json:"delivered_at"`
+}
@@ -8,6 +8,7 @@ import (
 
 type PasswordReset struct {
 	Id        uint           `gorm:"primaryKey" json:"id"`
+	TenantId  uint           `gorm:"not null;default:1;index" json:"-"` // Token chỉ dùng được trên tenant đã cấp
 	Email     string         `gorm:"index;size:100;not null" json:"email"`
 	Token     string         `gorm:"index;size:255;not null" json:"token"`
 	ExpiresAt time.Time      `gorm:"not null" json:"expires_at"`
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Quizzes ----------------
+type Quiz struct {
+	Id           uint           `gorm:"primaryKey" json:"id"`
+	CourseId     uint           `gorm:"index" json:"course_id"`
+	LessonId     uint           `gorm:"index" json:"lesson_id"`
+	Title        string         `gorm:"size:200;not null" json:"title"`
+	Description  string         `json:"description"`
+	PassingScore int            `gorm:"not null;default:70" json:"passing_score"` // Phần trăm điểm tối thiểu để pass
+	Questions    []QuizQuestion `gorm:"foreignKey:QuizId" json:"questions"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type QuizQuestion struct {
+	Id            uint           `gorm:"primaryKey" json:"id"`
+	QuizId        uint           `gorm:"index" json:"quiz_id"`
+	Question      string         `gorm:"not null" json:"question"`
+	Options       string         `gorm:"type:text;not null" json:"options"` // JSON array các lựa chọn
+	CorrectOption int            `gorm:"not null" json:"-"`                 // Index của đáp án đúng
+	QuestionOrder int            `gorm:"not null" json:"question_order"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type QuizAttempt struct {
+	Id          uint           `gorm:"primaryKey" json:"id"`
+	UserId      uint           `gorm:"index" json:"user_id"`
+	QuizId      uint           `gorm:"index" json:"quiz_id"`
+	Score       float64        `gorm:"default:0" json:"score"` // Phần trăm câu trả lời đúng
+	IsPassed    bool           `gorm:"default:false" json:"is_passed"`
+	SubmittedAt time.Time      `json:"submitted_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
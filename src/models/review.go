@@ -18,4 +18,20 @@ type Review struct {
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Kiểm duyệt: approved (hiển thị), pending (bị filter/report giữ lại chờ duyệt), hidden (admin ẩn)
+	ModerationStatus string       `gorm:"size:20;default:approved;index" json:"moderation_status"`
+	ModerationReason string       `gorm:"size:500" json:"moderation_reason"`
+	ModeratedBy      *uint        `json:"moderated_by"`
+	ModeratedAt      *time.Time   `json:"moderated_at"`
+	Reply            *ReviewReply `gorm:"foreignKey:ReviewId" json:"reply,omitempty"`
+
+	// Vote hữu ích: helpful_score là cận dưới Wilson, dùng để sắp xếp "most helpful"
+	HelpfulCount    int     `gorm:"default:0" json:"helpful_count"`
+	NotHelpfulCount int     `gorm:"default:0" json:"not_helpful_count"`
+	HelpfulScore    float64 `gorm:"default:0;index" json:"helpful_score"`
+
+	// Tính khi truy vấn (không lưu): đã mua qua order trả phí, đã hoàn thành course
+	IsVerifiedPurchase bool `gorm:"->;-:migration" json:"is_verified_purchase"`
+	HasCompletedCourse bool `gorm:"->;-:migration" json:"has_completed_course"`
 }
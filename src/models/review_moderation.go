@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// ---------------- Review moderation ----------------
+// ReviewReport là báo cáo vi phạm của user với một review (mỗi user báo cáo một lần)
+type ReviewReport struct {
+	Id         uint       `gorm:"primaryKey" json:"id"`
+	ReviewId   uint       `gorm:"uniqueIndex:idx_review_report_user;not null" json:"review_id"`
+	UserId     uint       `gorm:"uniqueIndex:idx_review_report_user;not null" json:"user_id"`
+	User       User       `gorm:"foreignKey:UserId" json:"user"`
+	Reason     string     `gorm:"size:30;not null" json:"reason"` // spam, offensive, off_topic, fake, other
+	Details    string     `gorm:"type:text" json:"details"`
+	Status     string     `gorm:"size:20;default:open;index" json:"status"` // open, resolved, dismissed
+	ResolvedBy *uint      `json:"resolved_by"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ReviewReply là phản hồi công khai của instructor cho review (tối đa một phản hồi mỗi review)
+type ReviewReply struct {
+	Id           uint      `gorm:"primaryKey" json:"id"`
+	ReviewId     uint      `gorm:"uniqueIndex;not null" json:"review_id"`
+	InstructorId uint      `gorm:"not null" json:"instructor_id"`
+	Instructor   User      `gorm:"foreignKey:InstructorId" json:"instructor"`
+	Content      string    `gorm:"type:text;not null" json:"content"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
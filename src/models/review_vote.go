@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// ReviewVote là đánh giá "review này có hữu ích không" của user (mỗi user một vote cho mỗi review)
+type ReviewVote struct {
+	Id        uint      `gorm:"primaryKey" json:"id"`
+	ReviewId  uint      `gorm:"uniqueIndex:idx_review_vote_user;not null" json:"review_id"`
+	UserId    uint      `gorm:"uniqueIndex:idx_review_vote_user;not null;index" json:"user_id"`
+	IsHelpful bool      `gorm:"not null" json:"is_helpful"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
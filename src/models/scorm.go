@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// ---------------- SCORM ----------------
+// ScormPackage là package SCORM đã giải nén lên storage của một lesson (mỗi lesson một package)
+type ScormPackage struct {
+	Id            uint      `gorm:"primaryKey" json:"id"`
+	LessonId      uint      `gorm:"uniqueIndex" json:"lesson_id"`
+	CourseId      uint      `gorm:"index" json:"course_id"`
+	Version       string    `gorm:"size:10;not null" json:"version"` // 1.2, 2004
+	Identifier    string    `gorm:"size:255" json:"identifier"`
+	Title         string    `gorm:"size:255" json:"title"`
+	LaunchPath    string    `gorm:"size:500;not null" json:"launch_path"` // file launch trong package
+	LaunchQuery   string    `gorm:"size:500" json:"launch_query"`
+	LaunchData    string    `gorm:"type:text" json:"launch_data"`
+	MasteryScore  *float64  `json:"mastery_score"`
+	StoragePrefix string    `gorm:"size:255;not null" json:"-"`
+	FileName      string    `gorm:"size:255" json:"file_name"`
+	FileCount     int       `json:"file_count"`
+	TotalSize     int64     `json:"total_size"`
+	UploadedBy    uint      `json:"uploaded_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ScormAttempt lưu dữ liệu cmi của một học viên với một package
+type ScormAttempt struct {
+	Id               uint       `gorm:"primaryKey" json:"id"`
+	UserId           uint       `gorm:"uniqueIndex:idx_scorm_attempt_user_package" json:"user_id"`
+	User             User       `gorm:"foreignKey:UserId" json:"-"`
+	PackageId        uint       `gorm:"uniqueIndex:idx_scorm_attempt_user_package;index" json:"package_id"`
+	LessonId         uint       `gorm:"index" json:"lesson_id"`
+	CourseId         uint       `gorm:"index" json:"course_id"`
+	CmiData          string     `gorm:"type:text;not null;default:'{}'" json:"-"`                 // JSON object: element -> value
+	CompletionStatus string     `gorm:"size:20;default:'not attempted'" json:"completion_status"` // not attempted, incomplete, completed, unknown
+	SuccessStatus    string     `gorm:"size:20;default:unknown" json:"success_status"`            // passed, failed, unknown
+	ScoreRaw         *float64   `json:"score_raw"`
+	ScoreScaled      *float64   `json:"score_scaled"`
+	TotalTime        float64    `gorm:"default:0" json:"total_time"` // Giây, cộng dồn từ session_time
+	Suspended        bool       `gorm:"default:false" json:"suspended"`
+	CommitCount      int        `gorm:"default:0" json:"commit_count"`
+	LastCommittedAt  *time.Time `json:"last_committed_at"`
+	CompletedAt      *time.Time `json:"completed_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
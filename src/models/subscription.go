@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Subscriptions ----------------
+// SubscriptionPlan là gói thuê bao theo tháng/năm: truy cập mọi course (scope all)
+// hoặc các course có tag trùng với tag của plan (scope tagged)
+type SubscriptionPlan struct {
+	Id              uint                  `gorm:"primaryKey" json:"id"`
+	TenantId        uint                  `gorm:"not null;default:1;index" json:"-"`
+	Name            string                `gorm:"size:100;not null" json:"name"`
+	Description     string                `gorm:"type:text" json:"description"`
+	Interval        string                `gorm:"size:10;not null" json:"interval"` // month, year
+	Price           float64               `gorm:"not null" json:"price"`
+	TrialDays       int                   `gorm:"default:0" json:"trial_days"`
+	GracePeriodDays int                   `gorm:"default:0" json:"grace_period_days"`         // Số ngày giữ quyền truy cập khi gia hạn thất bại
+	Scope           string                `gorm:"size:10;not null;default:all" json:"scope"`  // all, tagged
+	Status          string                `gorm:"size:20;default:active;index" json:"status"` // active, archived (không nhận subscriber mới)
+	Tags            []SubscriptionPlanTag `gorm:"foreignKey:PlanId" json:"tags,omitempty"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt        `gorm:"index" json:"-"`
+}
+
+// SubscriptionPlanTag: plan scope tagged bao phủ các course mang một trong các tag này
+type SubscriptionPlanTag struct {
+	Id     uint   `gorm:"primaryKey" json:"id"`
+	PlanId uint   `gorm:"not null;uniqueIndex:idx_subscription_plan_tag" json:"plan_id"`
+	Tag    string `gorm:"size:50;not null;uniqueIndex:idx_subscription_plan_tag;index" json:"tag"`
+}
+
+// CourseTag gắn tag cho course, dùng để xác định course thuộc plan nào
+type CourseTag struct {
+	Id       uint   `gorm:"primaryKey" json:"id"`
+	CourseId uint   `gorm:"not null;uniqueIndex:idx_course_tag" json:"course_id"`
+	Tag      string `gorm:"size:50;not null;uniqueIndex:idx_course_tag;index" json:"tag"`
+}
+
+// Subscription của một user. Giá và chu kỳ được chụp lại từ plan lúc đăng ký
+// nên đổi giá plan chỉ áp dụng cho subscriber mới.
+// Mỗi user chỉ có một subscription đang hiệu lực (trialing, active, past_due).
+type Subscription struct {
+	Id                 uint             `gorm:"primaryKey" json:"id"`
+	TenantId           uint             `gorm:"not null;default:1;index" json:"-"`
+	UserId             uint             `gorm:"not null;index" json:"user_id"`
+	User               User             `gorm:"foreignKey:UserId" json:"user"`
+	PlanId             uint             `gorm:"not null;index" json:"plan_id"`
+	Plan               SubscriptionPlan `gorm:"foreignKey:PlanId" json:"plan"`
+	Status             string           `gorm:"size:20;not null;index" json:"status"` // trialing, active, past_due, cancelled, expired
+	Price              float64          `gorm:"not null" json:"price"`
+	Interval           string           `gorm:"size:10;not null" json:"interval"`
+	PaymentMethod      string           `gorm:"size:50" json:"payment_method"`
+	CurrentPeriodStart time.Time        `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time        `gorm:"index" json:"current_period_end"` // Hết trial hoặc hết kỳ: đến hạn gia hạn
+	TrialEndsAt        *time.Time       `json:"trial_ends_at"`
+	GraceEndsAt        *time.Time       `json:"grace_ends_at"` // Chỉ có khi past_due: quá hạn này thì hết hiệu lực
+	NextRetryAt        *time.Time       `json:"next_retry_at"` // Lần thử thu tiền lại tiếp theo khi past_due
+	CancelAtPeriodEnd  bool             `gorm:"default:false" json:"cancel_at_period_end"`
+	CancelledAt        *time.Time       `json:"cancelled_at"`
+	EndedAt            *time.Time       `json:"ended_at"`
+	CreatedAt          time.Time        `json:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at"`
+}
+
+// SubscriptionPayment là một lần thu tiền cho một kỳ của subscription (kể cả lần thất bại)
+type SubscriptionPayment struct {
+	Id             uint       `gorm:"primaryKey" json:"id"`
+	TenantId       uint       `gorm:"not null;default:1;index" json:"-"`
+	SubscriptionId uint       `gorm:"not null;index" json:"subscription_id"`
+	UserId         uint       `gorm:"not null;index" json:"user_id"`
+	PlanId         uint       `gorm:"not null;index" json:"plan_id"`
+	Amount         float64    `gorm:"not null" json:"amount"`
+	PeriodStart    time.Time  `json:"period_start"`
+	PeriodEnd      time.Time  `json:"period_end"`
+	Status         string     `gorm:"size:20;not null;index" json:"status"` // paid, failed
+	PaymentMethod  string     `gorm:"size:50" json:"payment_method"`
+	TransactionId  string     `gorm:"size:100" json:"transaction_id"`
+	FailureReason  string     `gorm:"size:500" json:"failure_reason"`
+	PaidAt         *time.Time `gorm:"index" json:"paid_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
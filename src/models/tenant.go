@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultTenantId là tenant được tạo sẵn khi migrate, chứa toàn bộ dữ liệu có từ trước khi bật multi-tenant
+const DefaultTenantId uint = 1
+
+// ---------------- Tenants (white-label) ----------------
+// Tenant là một deployment white-label: có user, course, category, order và coupon riêng
+type Tenant struct {
+	Id                uint           `gorm:"primaryKey" json:"id"`
+	Slug              string         `gorm:"uniqueIndex;size:100;not null" json:"slug"` // Dùng cho header X-Tenant
+	Name              string         `gorm:"size:200;not null" json:"name"`
+	Status            string         `gorm:"size:20;default:active;index" json:"status"` // active, suspended
+	FrontendURL       string         `gorm:"size:255" json:"frontend_url"`               // Rỗng thì dùng FRONTEND_URL
+	EmailFromName     string         `gorm:"size:100" json:"email_from_name"`
+	EmailFromAddress  string         `gorm:"size:100" json:"email_from_address"`
+	SupportEmail      string         `gorm:"size:100" json:"support_email"`
+	LogoURL           string         `gorm:"size:255" json:"logo_url"`
+	FaviconURL        string         `gorm:"size:255" json:"favicon_url"`
+	PrimaryColor      string         `gorm:"size:20" json:"primary_color"`
+	SecondaryColor    string         `gorm:"size:20" json:"secondary_color"`
+	Settings          string         `gorm:"type:text" json:"settings"` // JSON cấu hình tự do cho frontend
+	XapiLrsKey        string         `gorm:"size:100" json:"-"`         // HTTP Basic key của LRS tích hợp, rỗng = tắt LRS
+	XapiLrsSecretHash string         `gorm:"size:64" json:"-"`          // SHA-256 của secret
+	Domains           []TenantDomain `gorm:"foreignKey:TenantId" json:"domains,omitempty"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TenantDomain: hostname trỏ về tenant (mỗi hostname chỉ thuộc một tenant)
+type TenantDomain struct {
+	Id        uint      `gorm:"primaryKey" json:"id"`
+	TenantId  uint      `gorm:"not null;index" json:"tenant_id"`
+	Hostname  string    `gorm:"uniqueIndex;size:255;not null" json:"hostname"`
+	CreatedAt time.Time `json:"created_at"`
+}
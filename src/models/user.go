@@ -9,8 +9,9 @@ import (
 // ---------------- Users ----------------
 type User struct {
 	Id            uint           `gorm:"primaryKey" json:"id"`
-	Username      string         `gorm:"uniqueIndex;size:50;not null" json:"username"`
-	Email         string         `gorm:"uniqueIndex;size:100;not null" json:"email"`
+	TenantId      uint           `gorm:"not null;default:1;index;uniqueIndex:idx_users_tenant_username;uniqueIndex:idx_users_tenant_email" json:"-"`
+	Username      string         `gorm:"uniqueIndex:idx_users_tenant_username;size:50;not null" json:"username"`
+	Email         string         `gorm:"uniqueIndex:idx_users_tenant_email;size:100;not null" json:"email"`
 	Password      string         `gorm:"size:255;not null" json:"-"`
 	FullName      string         `gorm:"size:100;not null" json:"full_name"`
 	AvatarURL     string         `gorm:"size:255" json:"avatar_url"`
@@ -19,6 +20,7 @@ type User struct {
 	Role          string         `gorm:"size:20;default:student" json:"role"` // admin,
 	Status        string         `gorm:"size:20;default:active" json:"status"`
 	EmailVerified bool           `gorm:"default:false" json:"email_verified"`
+	IsSuperAdmin  bool           `gorm:"default:false" json:"-"` // Quản trị toàn bộ deployment (mọi tenant)
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// ---------------- Video Uploads ----------------
+// VideoUpload lưu trạng thái upload theo chunk và transcode HLS của video lesson
+type VideoUpload struct {
+	Id           uint       `gorm:"primaryKey" json:"id"`
+	UploadId     string     `gorm:"size:36;uniqueIndex;not null" json:"upload_id"`
+	LessonId     uint       `gorm:"index;not null" json:"lesson_id"`
+	CourseId     uint       `gorm:"not null" json:"course_id"`
+	InstructorId uint       `gorm:"not null" json:"instructor_id"`
+	FileName     string     `gorm:"size:255;not null" json:"file_name"`
+	FileSize     int64      `gorm:"not null" json:"file_size"`
+	ChunkSize    int64      `gorm:"not null" json:"chunk_size"`
+	TotalChunks  int        `gorm:"not null" json:"total_chunks"`
+	Status       string     `gorm:"size:20;default:uploading" json:"status"` // uploading, queued, processing, ready, failed
+	ErrorMessage string     `json:"error_message"`
+	LockedAt     *time.Time `json:"-"` // Worker transcode nhận job lúc này (quá hạn thì worker khác nhận lại)
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// VideoUploadChunk đánh dấu chunk đã lưu lên storage dùng chung, mọi instance thấy cùng tiến độ upload
+type VideoUploadChunk struct {
+	Id         uint      `gorm:"primaryKey" json:"id"`
+	UploadId   uint      `gorm:"uniqueIndex:idx_video_upload_chunk;not null" json:"upload_id"`
+	ChunkIndex int       `gorm:"uniqueIndex:idx_video_upload_chunk;not null" json:"chunk_index"`
+	CreatedAt  time.Time `json:"created_at"`
+}
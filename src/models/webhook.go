@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ---------------- Webhooks ----------------
+// WebhookEndpoint là subscription do admin của tenant quản lý để gửi domain event tới hệ thống ngoài (HR, CRM, ...).
+// Endpoint chỉ nhận event phát sinh trong tenant của nó.
+type WebhookEndpoint struct {
+	Id          uint           `gorm:"primaryKey" json:"id"`
+	TenantId    uint           `gorm:"not null;default:1;index" json:"-"`
+	Url         string         `gorm:"size:500;not null" json:"url"`
+	Description string         `gorm:"size:255" json:"description"`
+	EventTypes  string         `gorm:"type:text" json:"event_types"` // Danh sách event cách nhau bởi dấu phẩy, rỗng = tất cả
+	Secret      string         `gorm:"size:100;not null" json:"-"`   // Dùng ký HMAC payload
+	IsActive    bool           `gorm:"not null" json:"is_active"`
+	CreatedBy   uint           `json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// WebhookDelivery là một lần gửi event tới endpoint (log gửi, retry, replay)
+type WebhookDelivery struct {
+	Id             uint            `gorm:"primaryKey" json:"id"`
+	EndpointId     uint            `gorm:"index;not null" json:"endpoint_id"`
+	Endpoint       WebhookEndpoint `gorm:"foreignKey:EndpointId" json:"-"`
+	EventId        *uint           `json:"event_id"` // Outbox event, nil với event test
+	EventType      string          `gorm:"size:100;not null" json:"event_type"`
+	Payload        string          `gorm:"type:text;not null" json:"payload"`
+	Status         string          `gorm:"size:20;default:pending;index:idx_webhook_deliveries_due" json:"status"` // pending, processing, succeeded, failed
+	Attempts       int             `gorm:"default:0" json:"attempts"`
+	NextAttemptAt  time.Time       `gorm:"index:idx_webhook_deliveries_due" json:"next_attempt_at"`
+	LockedAt       *time.Time      `json:"locked_at"`
+	ResponseStatus int             `json:"response_status"`
+	ResponseBody   string          `gorm:"type:text" json:"response_body"`
+	LastError      string          `gorm:"type:text" json:"last_error"`
+	DurationMs     int             `json:"duration_ms"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	ReplayOfId     *uint           `json:"replay_of_id"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// ---------------- xAPI (built-in LRS) ----------------
+// XapiStatement là statement đã lưu trong LRS tích hợp. Statement gốc được giữ nguyên trong cột Statement,
+// các cột còn lại phục vụ query theo spec (agent, verb, activity, registration, since/until).
+type XapiStatement struct {
+	Id              uint      `gorm:"primaryKey" json:"id"`
+	TenantId        uint      `gorm:"not null;default:1;uniqueIndex:idx_xapi_statements_tenant_statement" json:"-"` // Mỗi tenant có LRS riêng
+	StatementId     string    `gorm:"uniqueIndex:idx_xapi_statements_tenant_statement;size:36;not null" json:"statement_id"`
+	ActorKey        string    `gorm:"size:500;index" json:"actor_key"`        // Inverse functional identifier của actor
+	ObjectAgentKey  string    `gorm:"size:500;index" json:"object_agent_key"` // Khi object là Agent/Group
+	VerbId          string    `gorm:"size:500;index;not null" json:"verb_id"`
+	ActivityId      string    `gorm:"size:2000;index" json:"activity_id"` // Khi object là Activity
+	Registration    string    `gorm:"size:36;index" json:"registration"`
+	VoidedStatement string    `gorm:"size:36;index" json:"voided_statement"` // Statement bị void (chỉ có ở voiding statement)
+	Voided          bool      `gorm:"default:false;index" json:"voided"`
+	ContentHash     string    `gorm:"size:64;not null" json:"content_hash"` // Dùng để phát hiện statement id trùng nhưng khác nội dung
+	Statement       string    `gorm:"type:text;not null" json:"statement"`
+	Timestamp       time.Time `json:"timestamp"`
+	Stored          time.Time `gorm:"index" json:"stored"`
+}
@@ -124,14 +124,14 @@ func (r *DBAdminAnalyticsRepository) GetAdminDashboard() (*dto.AdminDashboardRes
 
 	// Total enrollments
 	var totalEnrollments int64
-	if err := r.db.Model(&models.Enrollment{}).Count(&totalEnrollments).Error; err != nil {
+	if err := r.db.Model(&models.Enrollment{}).Where("course_id IN (?)", tenantCourseIds(r.db)).Count(&totalEnrollments).Error; err != nil {
 		return nil, err
 	}
 	dashboard.TotalEnrollments = int(totalEnrollments)
 
 	// Month enrollments
 	var monthEnrollments int64
-	if err := r.db.Model(&models.Enrollment{}).Where("enrolled_at >= ?", startOfMonth).Count(&monthEnrollments).Error; err != nil {
+	if err := r.db.Model(&models.Enrollment{}).Where("course_id IN (?) AND enrolled_at >= ?", tenantCourseIds(r.db), startOfMonth).Count(&monthEnrollments).Error; err != nil {
 		return nil, err
 	}
 	dashboard.MonthEnrollments = int(monthEnrollments)
@@ -149,7 +149,7 @@ func (r *DBAdminAnalyticsRepository) GetAdminDashboard() (*dto.AdminDashboardRes
 
 	// Total reviews
 	var totalReviews int64
-	if err := r.db.Model(&models.Review{}).Count(&totalReviews).Error; err != nil {
+	if err := r.db.Model(&models.Review{}).Where("course_id IN (?)", tenantCourseIds(r.db)).Count(&totalReviews).Error; err != nil {
 		return nil, err
 	}
 	dashboard.TotalReviews = int(totalReviews)
@@ -237,7 +237,7 @@ func (r *DBAdminAnalyticsRepository) GetAdminRevenueAnalytics(req *dto.AdminReve
 			COUNT(id) as orders,
 			COUNT(DISTINCT user_id) as students
 		FROM orders
-		WHERE payment_status = ? AND paid_at BETWEEN ? AND ?
+		WHERE payment_status = ? AND paid_at BETWEEN ? AND ? AND `+tenantCondition(r.db, "orders")+`
 		GROUP BY period
 		ORDER BY period
 	`, periodFormat, "paid", startDate, endDate).Scan(&revenueByPeriod).Error; err != nil {
@@ -274,7 +274,7 @@ func (r *DBAdminAnalyticsRepository) GetAdminRevenueAnalytics(req *dto.AdminReve
 		LEFT JOIN orders ON orders.course_id = courses.id 
 			AND orders.payment_status = ?
 			AND orders.paid_at BETWEEN ? AND ?
-		WHERE users.role = ?
+		WHERE users.role = ? AND `+tenantCondition(r.db, "users")+`
 		GROUP BY users.id, users.full_name
 		ORDER BY revenue DESC
 		LIMIT 10
@@ -313,6 +313,7 @@ func (r *DBAdminAnalyticsRepository) GetAdminRevenueAnalytics(req *dto.AdminReve
 		LEFT JOIN orders ON orders.course_id = courses.id 
 			AND orders.payment_status = ?
 			AND orders.paid_at BETWEEN ? AND ?
+		WHERE `+tenantCondition(r.db, "categories")+`
 		GROUP BY categories.id, categories.name
 		ORDER BY revenue DESC
 	`, "paid", startDate, endDate).Scan(&revenueByCategory).Error; err != nil {
@@ -331,11 +332,12 @@ func (r *DBAdminAnalyticsRepository) GetAdminRevenueAnalytics(req *dto.AdminReve
 
 	// Top courses
 	var topCourses []struct {
-		CourseId    uint
-		CourseTitle string
-		Revenue     float64
-		Orders      int
-		Students    int
+		CourseId      uint
+		CourseTitle   string
+		Revenue       float64
+		BundleRevenue float64
+		Orders        int
+		Students      int
 	}
 
 	if err := r.db.Raw(`
@@ -343,12 +345,14 @@ func (r *DBAdminAnalyticsRepository) GetAdminRevenueAnalytics(req *dto.AdminReve
 			courses.id as course_id,
 			courses.title as course_title,
 			COALESCE(SUM(orders.final_price), 0) as revenue,
+			COALESCE(SUM(orders.final_price) FILTER (WHERE orders.bundle_purchase_id IS NOT NULL), 0) as bundle_revenue,
 			COUNT(orders.id) as orders,
 			COUNT(DISTINCT orders.user_id) as students
 		FROM courses
 		LEFT JOIN orders ON orders.course_id = courses.id 
 			AND orders.payment_status = ?
 			AND orders.paid_at BETWEEN ? AND ?
+		WHERE `+tenantCondition(r.db, "courses")+`
 		GROUP BY courses.id, courses.title
 		ORDER BY revenue DESC
 		LIMIT 10
@@ -358,11 +362,12 @@ func (r *DBAdminAnalyticsRepository) GetAdminRevenueAnalytics(req *dto.AdminReve
 
 	for _, item := range topCourses {
 		response.TopCourses = append(response.TopCourses, dto.TopCourseRevenue{
-			CourseId:    item.CourseId,
-			CourseTitle: item.CourseTitle,
-			Revenue:     item.Revenue,
-			Orders:      item.Orders,
-			Students:    item.Students,
+			CourseId:      item.CourseId,
+			CourseTitle:   item.CourseTitle,
+			Revenue:       item.Revenue,
+			BundleRevenue: item.BundleRevenue,
+			Orders:        item.Orders,
+			Students:      item.Students,
 		})
 	}
 
@@ -379,7 +384,7 @@ func (r *DBAdminAnalyticsRepository) GetAdminRevenueAnalytics(req *dto.AdminReve
 			COUNT(*) as count,
 			COALESCE(SUM(final_price), 0) as amount
 		FROM orders
-		WHERE payment_status = ? AND paid_at BETWEEN ? AND ?
+		WHERE payment_status = ? AND paid_at BETWEEN ? AND ? AND `+tenantCondition(r.db, "orders")+`
 		GROUP BY payment_method
 		ORDER BY amount DESC
 	`, "paid", startDate, endDate).Scan(&paymentStats).Error; err != nil {
@@ -394,6 +399,13 @@ func (r *DBAdminAnalyticsRepository) GetAdminRevenueAnalytics(req *dto.AdminReve
 		})
 	}
 
+	// Subscription revenue (báo cáo riêng)
+	subscriptionRevenue, err := r.getSubscriptionRevenue(periodFormat, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	response.SubscriptionRevenue = *subscriptionRevenue
+
 	// Revenue growth
 	periodDiff := endDate.Sub(startDate)
 	previousStartDate := startDate.Add(-periodDiff)
@@ -416,6 +428,131 @@ func (r *DBAdminAnalyticsRepository) GetAdminRevenueAnalytics(req *dto.AdminReve
 	return &response, nil
 }
 
+// getSubscriptionRevenue tổng hợp các lần thu tiền subscription trong khoảng thời gian
+func (r *DBAdminAnalyticsRepository) getSubscriptionRevenue(periodFormat string, startDate, endDate time.Time) (*dto.SubscriptionRevenueStats, error) {
+	var stats dto.SubscriptionRevenueStats
+
+	// Total revenue, payments và lần thu thất bại
+	var totals struct {
+		Total    float64
+		Payments int
+		Failed   int
+	}
+	if err := r.db.Raw(`
+		SELECT
+			COALESCE(SUM(amount) FILTER (WHERE status = 'paid' AND paid_at BETWEEN ? AND ?), 0) as total,
+			COUNT(*) FILTER (WHERE status = 'paid' AND paid_at BETWEEN ? AND ?) as payments,
+			COUNT(*) FILTER (WHERE status = 'failed' AND created_at BETWEEN ? AND ?) as failed
+		FROM subscription_payments
+		WHERE `+tenantCondition(r.db, "subscription_payments")+`
+	`, startDate, endDate, startDate, endDate, startDate, endDate).Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	stats.TotalRevenue = totals.Total
+	stats.Payments = totals.Payments
+	stats.FailedPayments = totals.Failed
+
+	// Subscription mới, đã kết thúc trong kỳ và đang hiệu lực
+	var counts struct {
+		New    int
+		Ended  int
+		Active int
+	}
+	if err := r.db.Raw(`
+		SELECT
+			COUNT(*) FILTER (WHERE created_at BETWEEN ? AND ?) as new,
+			COUNT(*) FILTER (WHERE ended_at BETWEEN ? AND ?) as ended,
+			COUNT(*) FILTER (WHERE status IN ?) as active
+		FROM subscriptions
+		WHERE `+tenantCondition(r.db, "subscriptions")+`
+	`, startDate, endDate, startDate, endDate, currentSubscriptionStatuses).Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	stats.NewSubscriptions = counts.New
+	stats.EndedSubscriptions = counts.Ended
+	stats.ActiveSubscriptions = counts.Active
+
+	// Revenue by period
+	var revenueByPeriod []struct {
+		Period      string
+		Revenue     float64
+		Payments    int
+		Subscribers int
+	}
+	if err := r.db.Raw(`
+		SELECT
+			TO_CHAR(paid_at, ?) as period,
+			COALESCE(SUM(amount), 0) as revenue,
+			COUNT(id) as payments,
+			COUNT(DISTINCT subscription_id) as subscribers
+		FROM subscription_payments
+		WHERE status = ? AND paid_at BETWEEN ? AND ? AND `+tenantCondition(r.db, "subscription_payments")+`
+		GROUP BY period
+		ORDER BY period
+	`, periodFormat, "paid", startDate, endDate).Scan(&revenueByPeriod).Error; err != nil {
+		return nil, err
+	}
+
+	stats.RevenueByPeriod = make([]dto.SubscriptionRevenuePeriod, len(revenueByPeriod))
+	for i, item := range revenueByPeriod {
+		stats.RevenueByPeriod[i] = dto.SubscriptionRevenuePeriod{
+			Period:      item.Period,
+			Revenue:     item.Revenue,
+			Payments:    item.Payments,
+			Subscribers: item.Subscribers,
+		}
+	}
+
+	// Revenue by plan
+	var revenueByPlan []struct {
+		PlanId              uint
+		PlanName            string
+		Interval            string
+		Revenue             float64
+		Payments            int
+		ActiveSubscriptions int
+	}
+	if err := r.db.Raw(`
+		SELECT
+			subscription_plans.id as plan_id,
+			subscription_plans.name as plan_name,
+			subscription_plans.interval as interval,
+			COALESCE((
+				SELECT SUM(amount) FROM subscription_payments
+				WHERE subscription_payments.plan_id = subscription_plans.id
+					AND subscription_payments.status = ? AND subscription_payments.paid_at BETWEEN ? AND ?
+			), 0) as revenue,
+			(
+				SELECT COUNT(*) FROM subscription_payments
+				WHERE subscription_payments.plan_id = subscription_plans.id
+					AND subscription_payments.status = ? AND subscription_payments.paid_at BETWEEN ? AND ?
+			) as payments,
+			(
+				SELECT COUNT(*) FROM subscriptions
+				WHERE subscriptions.plan_id = subscription_plans.id AND subscriptions.status IN ?
+			) as active_subscriptions
+		FROM subscription_plans
+		WHERE subscription_plans.deleted_at IS NULL AND `+tenantCondition(r.db, "subscription_plans")+`
+		ORDER BY revenue DESC
+	`, "paid", startDate, endDate, "paid", startDate, endDate, currentSubscriptionStatuses).Scan(&revenueByPlan).Error; err != nil {
+		return nil, err
+	}
+
+	stats.RevenueByPlan = make([]dto.SubscriptionPlanRevenueItem, len(revenueByPlan))
+	for i, item := range revenueByPlan {
+		stats.RevenueByPlan[i] = dto.SubscriptionPlanRevenueItem{
+			PlanId:              item.PlanId,
+			PlanName:            item.PlanName,
+			Interval:            item.Interval,
+			Revenue:             item.Revenue,
+			Payments:            item.Payments,
+			ActiveSubscriptions: item.ActiveSubscriptions,
+		}
+	}
+
+	return &stats, nil
+}
+
 func (r *DBAdminAnalyticsRepository) GetAdminUsersAnalytics(req *dto.AdminUsersAnalyticsRequest) (*dto.AdminUsersAnalyticsResponse, error) {
 	var response dto.AdminUsersAnalyticsResponse
 
@@ -504,7 +641,7 @@ func (r *DBAdminAnalyticsRepository) GetAdminUsersAnalytics(req *dto.AdminUsersA
 			COUNT(*) as new_users,
 			COUNT(CASE WHEN status = 'active' THEN 1 END) as active_users
 		FROM users
-		WHERE created_at BETWEEN ? AND ?`+roleFilter+`
+		WHERE created_at BETWEEN ? AND ? AND `+tenantCondition(r.db, "users")+roleFilter+`
 		GROUP BY period
 		ORDER BY period
 	`, startDate, endDate).Scan(&usersByPeriod).Error; err != nil {
@@ -528,6 +665,7 @@ func (r *DBAdminAnalyticsRepository) GetAdminUsersAnalytics(req *dto.AdminUsersA
 	if err := r.db.Raw(`
 		SELECT role, COUNT(*) as count
 		FROM users
+		WHERE ` + tenantCondition(r.db, "users") + `
 		GROUP BY role
 		ORDER BY count DESC
 	`).Scan(&usersByRole).Error; err != nil {
@@ -689,7 +827,7 @@ func (r *DBAdminAnalyticsRepository) GetAdminCoursesAnalytics(req *dto.AdminCour
 			COUNT(CASE WHEN status = 'published' THEN 1 END) as published,
 			COALESCE(SUM(enrolled_count), 0) as enrollments
 		FROM courses
-		WHERE created_at BETWEEN ? AND ?`+categoryFilter+statusFilter+`
+		WHERE created_at BETWEEN ? AND ? AND `+tenantCondition(r.db, "courses")+categoryFilter+statusFilter+`
 		GROUP BY period
 		ORDER BY period
 	`, startDate, endDate).Scan(&coursesByPeriod).Error; err != nil {
@@ -723,6 +861,7 @@ func (r *DBAdminAnalyticsRepository) GetAdminCoursesAnalytics(req *dto.AdminCour
 			COALESCE(AVG(courses.rating_avg), 0) as avg_rating
 		FROM categories
 		LEFT JOIN courses ON courses.category_id = categories.id
+		WHERE ` + tenantCondition(r.db, "categories") + `
 		GROUP BY categories.id, categories.name
 		ORDER BY courses DESC
 	`).Scan(&coursesByCategory).Error; err != nil {
@@ -757,7 +896,7 @@ func (r *DBAdminAnalyticsRepository) GetAdminCoursesAnalytics(req *dto.AdminCour
 			COALESCE(AVG(courses.rating_avg), 0) as avg_rating
 		FROM users
 		LEFT JOIN courses ON courses.instructor_id = users.id
-		WHERE users.role = ?
+		WHERE users.role = ? AND `+tenantCondition(r.db, "users")+`
 		GROUP BY users.id, users.full_name
 		ORDER BY courses DESC
 		LIMIT 10
@@ -794,7 +933,7 @@ func (r *DBAdminAnalyticsRepository) GetAdminCoursesAnalytics(req *dto.AdminCour
 
 	// Total enrollments
 	var totalEnrollments int64
-	enrollQuery := r.db.Model(&models.Enrollment{})
+	enrollQuery := r.db.Model(&models.Enrollment{}).Where("enrollments.course_id IN (?)", tenantCourseIds(r.db))
 	if req.CategoryId != 0 {
 		enrollQuery = enrollQuery.Joins("JOIN courses ON courses.id = enrollments.course_id").
 			Where("courses.category_id = ?", req.CategoryId)
@@ -194,16 +194,24 @@ func (ar *DBAnalyticsRepository) GetRevenueAnalytics(instructorId uint, req *dto
 
 	// Total revenue and orders
 	var stats struct {
-		Total  float64
-		Orders int64
-	}
-	if err := query.Select("COALESCE(SUM(final_price), 0) as total, COUNT(*) as orders").
+		Total        float64
+		Orders       int64
+		BundleTotal  float64
+		BundleOrders int64
+	}
+	if err := query.Select(`COALESCE(SUM(final_price), 0) as total, COUNT(*) as orders,
+			COALESCE(SUM(final_price) FILTER (WHERE orders.bundle_purchase_id IS NOT NULL), 0) as bundle_total,
+			COUNT(*) FILTER (WHERE orders.bundle_purchase_id IS NOT NULL) as bundle_orders`).
 		Scan(&stats).Error; err != nil {
 		return nil, err
 	}
 	response.TotalRevenue = stats.Total
 	response.TotalOrders = int(stats.Orders)
 
+	// Order của bundle mang phần giá đã phân bổ cho course nên doanh thu được tính cho đúng course/instructor
+	response.BundleRevenue = stats.BundleTotal
+	response.BundleOrders = int(stats.BundleOrders)
+
 	// Average order value
 	if response.TotalOrders > 0 {
 		response.AverageOrderValue = response.TotalRevenue / float64(response.TotalOrders)
@@ -266,11 +274,12 @@ func (ar *DBAnalyticsRepository) GetRevenueAnalytics(instructorId uint, req *dto
 
 	// Top courses by revenue
 	var topCourses []struct {
-		CourseId    uint
-		CourseTitle string
-		Revenue     float64
-		Orders      int
-		Students    int
+		CourseId      uint
+		CourseTitle   string
+		Revenue       float64
+		BundleRevenue float64
+		Orders        int
+		Students      int
 	}
 
 	if err := ar.db.Raw(`
@@ -278,6 +287,7 @@ func (ar *DBAnalyticsRepository) GetRevenueAnalytics(instructorId uint, req *dto
 			courses.id as course_id,
 			courses.title as course_title,
 			COALESCE(SUM(orders.final_price), 0) as revenue,
+			COALESCE(SUM(orders.final_price) FILTER (WHERE orders.bundle_purchase_id IS NOT NULL), 0) as bundle_revenue,
 			COUNT(orders.id) as orders,
 			COUNT(DISTINCT orders.user_id) as students
 		FROM courses
@@ -296,11 +306,12 @@ func (ar *DBAnalyticsRepository) GetRevenueAnalytics(instructorId uint, req *dto
 
 	for _, item := range topCourses {
 		response.TopCourses = append(response.TopCourses, dto.TopCourseRevenue{
-			CourseId:    item.CourseId,
-			CourseTitle: item.CourseTitle,
-			Revenue:     item.Revenue,
-			Orders:      item.Orders,
-			Students:    item.Students,
+			CourseId:      item.CourseId,
+			CourseTitle:   item.CourseTitle,
+			Revenue:       item.Revenue,
+			BundleRevenue: item.BundleRevenue,
+			Orders:        item.Orders,
+			Students:      item.Students,
 		})
 	}
 
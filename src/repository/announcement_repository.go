@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"lms/src/dto"
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DBAnnouncementRepository struct {
+	db *gorm.DB
+}
+
+func NewDBAnnouncementRepository(db *gorm.DB) AnnouncementRepository {
+	return &DBAnnouncementRepository{
+		db: db,
+	}
+}
+
+func (ar *DBAnnouncementRepository) Create(announcement *models.CourseAnnouncement) error {
+	return ar.db.Create(announcement).Error
+}
+
+func (ar *DBAnnouncementRepository) FindById(announcementId uint) (*models.CourseAnnouncement, error) {
+	var announcement models.CourseAnnouncement
+	err := ar.db.Preload("Course").Preload("Instructor").
+		Where("id = ?", announcementId).
+		First(&announcement).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+func (ar *DBAnnouncementRepository) GetCourseAnnouncements(courseId uint, offset, limit int) ([]models.CourseAnnouncement, int, error) {
+	var announcements []models.CourseAnnouncement
+	var total int64
+
+	query := ar.db.Model(&models.CourseAnnouncement{}).Where("course_id = ?", courseId)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("publish_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&announcements).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return announcements, int(total), nil
+}
+
+func (ar *DBAnnouncementRepository) GetAnnouncementStats(announcementIds []uint) (map[uint]dto.AnnouncementStats, error) {
+	stats := make(map[uint]dto.AnnouncementStats)
+	if len(announcementIds) == 0 {
+		return stats, nil
+	}
+
+	var rows []struct {
+		AnnouncementId uint
+		dto.AnnouncementStats
+	}
+
+	err := ar.db.Model(&models.AnnouncementReceipt{}).
+		Select(`announcement_id,
+			COUNT(*) AS recipient_count,
+			COUNT(read_at) AS read_count,
+			COUNT(email_sent_at) AS email_sent_count,
+			COUNT(email_opened_at) AS email_open_count`).
+		Where("announcement_id IN ?", announcementIds).
+		Group("announcement_id").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		stats[row.AnnouncementId] = row.AnnouncementStats
+	}
+	return stats, nil
+}
+
+func (ar *DBAnnouncementRepository) Update(announcementId uint, updates map[string]interface{}) error {
+	return ar.db.Model(&models.CourseAnnouncement{}).Where("id = ?", announcementId).Updates(updates).Error
+}
+
+func (ar *DBAnnouncementRepository) Delete(announcementId uint) error {
+	return ar.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("announcement_id = ?", announcementId).Delete(&models.AnnouncementReceipt{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.CourseAnnouncement{}, announcementId).Error
+	})
+}
+
+// ClaimDueAnnouncements nhận các announcement cần gửi: đến giờ publish, hoặc đang "publishing" mà đã đến lượt gửi lại
+// hoặc instance đang gửi đã chết (locked_at quá hạn). UPDATE ... RETURNING đảm bảo mỗi announcement chỉ được một instance xử lý.
+func (ar *DBAnnouncementRepository) ClaimDueAnnouncements(now, staleBefore time.Time) ([]models.CourseAnnouncement, error) {
+	var announcements []models.CourseAnnouncement
+	err := ar.db.Model(&announcements).
+		Clauses(clause.Returning{}).
+		Where("(status = ? AND publish_at <= ?) OR (status = ? AND (locked_at IS NULL OR locked_at < ?) AND (retry_at IS NULL OR retry_at <= ?))",
+			"scheduled", now, "publishing", staleBefore, now).
+		Updates(map[string]interface{}{"status": "publishing", "locked_at": now}).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return announcements, nil
+}
+
+func (ar *DBAnnouncementRepository) GetActiveEnrollmentUserIds(courseId uint) ([]uint, error) {
+	var userIds []uint
+	err := ar.db.Model(&models.Enrollment{}).
+		Where("course_id = ? AND status = ?", courseId, "active").
+		Pluck("user_id", &userIds).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return userIds, nil
+}
+
+// CreateReceipts bỏ qua receipt đã tồn tại để có thể chạy lại khi gửi dở
+func (ar *DBAnnouncementRepository) CreateReceipts(receipts []models.AnnouncementReceipt) error {
+	if len(receipts) == 0 {
+		return nil
+	}
+	return ar.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(receipts, 500).Error
+}
+
+// GetPendingEmailReceipts lấy receipt chưa gửi email và chưa hết lượt thử, bỏ qua học viên đã unsubscribe course
+func (ar *DBAnnouncementRepository) GetPendingEmailReceipts(announcementId uint, maxAttempts int) ([]models.AnnouncementReceipt, error) {
+	var receipts []models.AnnouncementReceipt
+	err := ar.db.Preload("User").
+		Where("announcement_id = ? AND email_sent_at IS NULL AND email_attempts < ?", announcementId, maxAttempts).
+		Where("NOT EXISTS (?)", ar.db.Model(&models.AnnouncementUnsubscribe{}).
+			Select("1").
+			Where("announcement_unsubscribes.user_id = announcement_receipts.user_id AND announcement_unsubscribes.course_id = announcement_receipts.course_id")).
+		Find(&receipts).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+func (ar *DBAnnouncementRepository) GetUserFeed(userId uint, offset, limit int, filters map[string]interface{}) ([]models.AnnouncementReceipt, int, error) {
+	var receipts []models.AnnouncementReceipt
+	var total int64
+
+	query := ar.db.Model(&models.AnnouncementReceipt{}).
+		Joins("JOIN course_announcements ON course_announcements.id = announcement_receipts.announcement_id AND course_announcements.deleted_at IS NULL").
+		Where("announcement_receipts.user_id = ?", userId)
+
+	// Apply filters
+	for field, value := range filters {
+		switch field {
+		case "course_id":
+			query = query.Where("announcement_receipts.course_id = ?", value)
+		case "unread_only":
+			query = query.Where("announcement_receipts.read_at IS NULL")
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Announcement.Course").Preload("Announcement.Instructor").
+		Order("course_announcements.published_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&receipts).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return receipts, int(total), nil
+}
+
+func (ar *DBAnnouncementRepository) CountUnread(userId uint) (int, error) {
+	var count int64
+	err := ar.db.Model(&models.AnnouncementReceipt{}).
+		Joins("JOIN course_announcements ON course_announcements.id = announcement_receipts.announcement_id AND course_announcements.deleted_at IS NULL").
+		Where("announcement_receipts.user_id = ? AND announcement_receipts.read_at IS NULL", userId).
+		Count(&count).Error
+
+	return int(count), err
+}
+
+func (ar *DBAnnouncementRepository) FindReceipt(userId, announcementId uint) (*models.AnnouncementReceipt, error) {
+	var receipt models.AnnouncementReceipt
+	err := ar.db.Preload("Announcement.Course").Preload("Announcement.Instructor").
+		Where("user_id = ? AND announcement_id = ?", userId, announcementId).
+		First(&receipt).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+func (ar *DBAnnouncementRepository) FindReceiptByToken(token string) (*models.AnnouncementReceipt, error) {
+	var receipt models.AnnouncementReceipt
+	if err := ar.db.Where("token = ?", token).First(&receipt).Error; err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// MarkReceipt set thời điểm (read_at, email_sent_at, email_opened_at) nếu chưa có
+func (ar *DBAnnouncementRepository) MarkReceipt(receiptId uint, column string, at time.Time) error {
+	return ar.db.Model(&models.AnnouncementReceipt{}).
+		Where("id = ?", receiptId).
+		Where(clause.Eq{Column: clause.Column{Name: column}, Value: nil}).
+		Update(column, at).Error
+}
+
+// RecordEmailFailure ghi lại lần gửi email lỗi của receipt để lượt sau gửi lại
+func (ar *DBAnnouncementRepository) RecordEmailFailure(receiptId uint, message string) error {
+	return ar.db.Model(&models.AnnouncementReceipt{}).
+		Where("id = ?", receiptId).
+		Updates(map[string]interface{}{
+			"email_attempts": gorm.Expr("email_attempts + 1"),
+			"email_error":    message,
+		}).Error
+}
+
+func (ar *DBAnnouncementRepository) IsUnsubscribed(userId, courseId uint) (bool, error) {
+	var count int64
+	err := ar.db.Model(&models.AnnouncementUnsubscribe{}).
+		Where("user_id = ? AND course_id = ?", userId, courseId).
+		Count(&count).Error
+
+	return count > 0, err
+}
+
+func (ar *DBAnnouncementRepository) Unsubscribe(userId, courseId uint) error {
+	unsubscribe := models.AnnouncementUnsubscribe{UserId: userId, CourseId: courseId}
+	return ar.db.Where("user_id = ? AND course_id = ?", userId, courseId).
+		FirstOrCreate(&unsubscribe).Error
+}
+
+func (ar *DBAnnouncementRepository) Subscribe(userId, courseId uint) error {
+	return ar.db.Where("user_id = ? AND course_id = ?", userId, courseId).
+		Delete(&models.AnnouncementUnsubscribe{}).Error
+}
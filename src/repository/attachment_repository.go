@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"lms/src/models"
+
+	"gorm.io/gorm"
+)
+
+type DBAttachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewDBAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &DBAttachmentRepository{
+		db: db,
+	}
+}
+
+func (ar *DBAttachmentRepository) Create(attachment *models.LessonAttachment) error {
+	return ar.db.Create(attachment).Error
+}
+
+func (ar *DBAttachmentRepository) FindById(attachmentId uint) (*models.LessonAttachment, error) {
+	var attachment models.LessonAttachment
+	if err := ar.db.Where("id = ?", attachmentId).First(&attachment).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (ar *DBAttachmentRepository) FindByLesson(lessonId uint) ([]models.LessonAttachment, error) {
+	var attachments []models.LessonAttachment
+	err := ar.db.Where("lesson_id = ?", lessonId).
+		Order("created_at ASC").
+		Find(&attachments).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (ar *DBAttachmentRepository) Delete(attachmentId uint) error {
+	return ar.db.Delete(&models.LessonAttachment{}, attachmentId).Error
+}
+
+func (ar *DBAttachmentRepository) IncrementDownloadCount(attachmentId uint) error {
+	return ar.db.Model(&models.LessonAttachment{}).
+		Where("id = ?", attachmentId).
+		UpdateColumn("download_count", gorm.Expr("download_count + 1")).Error
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DBBundleRepository struct {
+	db *gorm.DB
+}
+
+func NewDBBundleRepository(db *gorm.DB) BundleRepository {
+	return &DBBundleRepository{
+		db: db,
+	}
+}
+
+// preloadBundleCourses load các course của bundle theo thứ tự position
+func preloadBundleCourses(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+func (br *DBBundleRepository) GetBundles(offset, limit int, filters map[string]interface{}, orderBy, sortBy string) ([]models.Bundle, int, error) {
+	var bundles []models.Bundle
+	var total int64
+
+	query := br.db.Model(&models.Bundle{}).Where("deleted_at IS NULL")
+
+	// Apply filters
+	for field, value := range filters {
+		if field == "search" {
+			searchTerm := fmt.Sprintf("%%%s%%", value)
+			query = query.Where("title ILIKE ? OR description ILIKE ?", searchTerm, searchTerm)
+		} else {
+			query = query.Where(fmt.Sprintf("%s = ?", field), value)
+		}
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Validate orderBy
+	allowedOrderBy := map[string]bool{
+		"created_at": true,
+		"title":      true,
+		"price":      true,
+	}
+	if !allowedOrderBy[orderBy] {
+		orderBy = "created_at"
+	}
+	if sortBy != "asc" && sortBy != "desc" {
+		sortBy = "desc"
+	}
+
+	if err := query.
+		Preload("Courses", preloadBundleCourses).
+		Preload("Courses.Course").
+		Order(fmt.Sprintf("%s %s", orderBy, sortBy)).
+		Offset(offset).
+		Limit(limit).
+		Find(&bundles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return bundles, int(total), nil
+}
+
+// GetPublishedBundlesByCourse trả về các bundle đang bán có chứa course
+func (br *DBBundleRepository) GetPublishedBundlesByCourse(courseId uint) ([]models.Bundle, error) {
+	var bundles []models.Bundle
+
+	err := br.db.
+		Preload("Courses", preloadBundleCourses).
+		Preload("Courses.Course").
+		Where("deleted_at IS NULL AND status = ?", "published").
+		Where("id IN (?)", br.db.Model(&models.BundleCourse{}).Select("bundle_id").Where("course_id = ?", courseId)).
+		Order("created_at DESC").
+		Find(&bundles).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bundles, nil
+}
+
+func (br *DBBundleRepository) FindById(bundleId uint) (*models.Bundle, error) {
+	var bundle models.Bundle
+
+	err := br.db.
+		Preload("Courses", preloadBundleCourses).
+		Preload("Courses.Course").
+		Preload("Courses.Course.Instructor").
+		Where("id = ? AND deleted_at IS NULL", bundleId).
+		First(&bundle).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &bundle, nil
+}
+
+func (br *DBBundleRepository) FindBySlug(slug string) (*models.Bundle, error) {
+	var bundle models.Bundle
+
+	err := br.db.
+		Preload("Courses", preloadBundleCourses).
+		Preload("Courses.Course").
+		Preload("Courses.Course.Instructor").
+		Where("slug = ? AND deleted_at IS NULL", slug).
+		First(&bundle).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &bundle, nil
+}
+
+func (br *DBBundleRepository) ExistsBySlug(slug string) bool {
+	var count int64
+	br.db.Unscoped().Model(&models.Bundle{}).Where("slug = ?", slug).Count(&count)
+	return count > 0
+}
+
+func (br *DBBundleRepository) Create(bundle *models.Bundle) error {
+	return br.db.Create(bundle).Error
+}
+
+func (br *DBBundleRepository) Update(bundleId uint, updates map[string]interface{}) error {
+	return br.db.Model(&models.Bundle{}).
+		Where("id = ?", bundleId).
+		Updates(updates).Error
+}
+
+func (br *DBBundleRepository) Delete(bundleId uint) error {
+	return br.db.Where("id = ?", bundleId).Delete(&models.Bundle{}).Error
+}
+
+// ReplaceCourses thay thế toàn bộ danh sách course của bundle theo thứ tự truyền vào
+func (br *DBBundleRepository) ReplaceCourses(bundleId uint, courseIds []uint) error {
+	return br.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("bundle_id = ?", bundleId).Delete(&models.BundleCourse{}).Error; err != nil {
+			return err
+		}
+
+		for i, courseId := range courseIds {
+			item := &models.BundleCourse{
+				BundleId: bundleId,
+				CourseId: courseId,
+				Position: i + 1,
+			}
+			if err := tx.Create(item).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetBundleSales thống kê số lượt mua và doanh thu của từng bundle
+func (br *DBBundleRepository) GetBundleSales(bundleIds []uint) (map[uint]dto.BundleSalesStats, error) {
+	sales := make(map[uint]dto.BundleSalesStats)
+	if len(bundleIds) == 0 {
+		return sales, nil
+	}
+
+	var rows []struct {
+		BundleId  uint
+		Purchases int
+		Revenue   float64
+	}
+	if err := br.db.Model(&models.BundlePurchase{}).
+		Select("bundle_id, COUNT(*) as purchases, COALESCE(SUM(final_price), 0) as revenue").
+		Where("bundle_id IN ? AND status = ?", bundleIds, "paid").
+		Group("bundle_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		sales[row.BundleId] = dto.BundleSalesStats{
+			Purchases: row.Purchases,
+			Revenue:   row.Revenue,
+		}
+	}
+	return sales, nil
+}
+
+func (br *DBBundleRepository) CreatePurchase(purchase *models.BundlePurchase) error {
+	return br.db.Omit("Bundle").Create(purchase).Error
+}
+
+func (br *DBBundleRepository) UpdatePurchase(purchaseId uint, updates map[string]interface{}) error {
+	return br.db.Model(&models.BundlePurchase{}).
+		Where("id = ?", purchaseId).
+		Updates(updates).Error
+}
+
+func (br *DBBundleRepository) DeletePurchase(purchaseId uint) error {
+	return br.db.Where("id = ?", purchaseId).Delete(&models.BundlePurchase{}).Error
+}
+
+func (br *DBBundleRepository) HasPendingPurchase(userId, bundleId uint) (bool, error) {
+	var count int64
+	err := br.db.Model(&models.BundlePurchase{}).
+		Where("user_id = ? AND bundle_id = ? AND status = ?", userId, bundleId, "pending").
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ExpirePendingPurchases đóng purchase pending bị treo (instance chết giữa chừng) để user mua lại được
+func (br *DBBundleRepository) ExpirePendingPurchases(userId, bundleId uint, createdBefore time.Time) error {
+	return br.db.Model(&models.BundlePurchase{}).
+		Where("user_id = ? AND bundle_id = ? AND status = ? AND created_at < ?", userId, bundleId, "pending", createdBefore).
+		Update("status", "expired").Error
+}
+
+func (br *DBBundleRepository) GetUserPurchases(userId uint, offset, limit int) ([]models.BundlePurchase, int, error) {
+	var purchases []models.BundlePurchase
+	var total int64
+
+	// Lần mua đang xử lý chưa hiện trong lịch sử
+	query := br.db.Model(&models.BundlePurchase{}).Where("user_id = ? AND status <> ?", userId, "pending")
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Bundle đã xóa vẫn hiện trong lịch sử mua
+	if err := query.
+		Preload("Bundle", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&purchases).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return purchases, int(total), nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"lms/src/models"
+
+	"gorm.io/gorm"
+)
+
+type DBCourseApprovalRepository struct {
+	db *gorm.DB
+}
+
+func NewDBCourseApprovalRepository(db *gorm.DB) CourseApprovalRepository {
+	return &DBCourseApprovalRepository{
+		db: db,
+	}
+}
+
+// ChangeStatus cập nhật course (bao gồm status) và ghi lịch sử trong cùng transaction
+func (car *DBCourseApprovalRepository) ChangeStatus(courseId uint, updates map[string]interface{}, history *models.CourseStatusHistory) error {
+	return car.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Course{}).Where("id = ?", courseId).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		history.CourseId = courseId
+		return tx.Create(history).Error
+	})
+}
+
+func (car *DBCourseApprovalRepository) GetStatusHistory(courseId uint) ([]models.CourseStatusHistory, error) {
+	var history []models.CourseStatusHistory
+	err := car.db.Preload("ChangedByUser").
+		Where("course_id = ?", courseId).
+		Order("created_at DESC, id DESC").
+		Find(&history).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// GetReviewQueue lấy các course đang chờ duyệt, gửi sớm nhất trước
+func (car *DBCourseApprovalRepository) GetReviewQueue(offset, limit int, filters map[string]interface{}) ([]models.Course, int, error) {
+	var courses []models.Course
+	var total int64
+
+	query := car.db.Model(&models.Course{}).
+		Where("status = ? AND deleted_at IS NULL", "pending_review")
+
+	if search, ok := filters["search"]; ok {
+		query = query.Where("title ILIKE ?", "%"+search.(string)+"%")
+	}
+
+	if categoryId, ok := filters["category_id"]; ok {
+		query = query.Where("category_id = ?", categoryId)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Instructor").Preload("Category").
+		Order("submitted_at ASC NULLS LAST, id ASC").
+		Offset(offset).Limit(limit).
+		Find(&courses).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return courses, int(total), nil
+}
+
+// CountLessons đếm số lesson đã publish của từng course
+func (car *DBCourseApprovalRepository) CountLessons(courseIds []uint) (map[uint]int, error) {
+	counts := make(map[uint]int)
+	if len(courseIds) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		CourseId uint
+		Count    int
+	}
+
+	err := car.db.Model(&models.Lesson{}).
+		Select("course_id, COUNT(*) AS count").
+		Where("course_id IN ? AND is_published = ? AND deleted_at IS NULL", courseIds, true).
+		Group("course_id").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.CourseId] = row.Count
+	}
+	return counts, nil
+}
@@ -74,11 +74,16 @@ func (cr *DBCourseRepository) SearchCourses(query string, offset, limit int, fil
 		Preload("Category").
 		Where("deleted_at IS NULL AND status = ?", "published")
 
-	// Full text search
+	// Full text search (bao gồm cả transcript của lessons)
 	searchTerm := fmt.Sprintf("%%%s%%", query)
 	dbQuery = dbQuery.Where(
-		"title ILIKE ? OR description ILIKE ? OR short_desc ILIKE ? OR requirements ILIKE ? OR what_you_learn ILIKE ?",
+		"title ILIKE ? OR description ILIKE ? OR short_desc ILIKE ? OR requirements ILIKE ? OR what_you_learn ILIKE ? OR id IN (?)",
 		searchTerm, searchTerm, searchTerm, searchTerm, searchTerm,
+		cr.db.Table("transcript_cues").
+			Select("lessons.course_id").
+			Joins("JOIN lessons ON lessons.id = transcript_cues.lesson_id").
+			Where("lessons.deleted_at IS NULL AND lessons.is_published = ?", true).
+			Where(transcriptMatchCondition, query),
 	)
 
 	// Apply filters
@@ -122,6 +127,60 @@ func (cr *DBCourseRepository) SearchCourses(query string, offset, limit int, fil
 	return courses, int(total), nil
 }
 
+// transcriptMatchCondition dùng index GIN idx_transcript_cues_text_search
+const transcriptMatchCondition = "to_tsvector('simple', transcript_cues.text) @@ plainto_tsquery('simple', ?)"
+
+func (cr *DBCourseRepository) SearchTranscripts(courseIds []uint, query string, limitPerCourse int) ([]dto.TranscriptMatch, error) {
+	var rows []struct {
+		CourseId    uint
+		LessonId    uint
+		LessonTitle string
+		LessonSlug  string
+		Language    string
+		StartMs     int
+		EndMs       int
+		Text        string
+	}
+
+	if len(courseIds) == 0 {
+		return []dto.TranscriptMatch{}, nil
+	}
+
+	// Lấy tối đa limitPerCourse đoạn khớp cho mỗi course, theo thứ tự lesson và thời gian
+	matches := cr.db.Table("transcript_cues").
+		Select(`lessons.course_id, lessons.id AS lesson_id, lessons.title AS lesson_title, lessons.slug AS lesson_slug,
+			transcript_cues.language, transcript_cues.start_ms, transcript_cues.end_ms, transcript_cues.text,
+			ROW_NUMBER() OVER (PARTITION BY lessons.course_id ORDER BY lessons.lesson_order, transcript_cues.start_ms) AS row_num`).
+		Joins("JOIN lessons ON lessons.id = transcript_cues.lesson_id").
+		Where("lessons.course_id IN ? AND lessons.deleted_at IS NULL AND lessons.is_published = ?", courseIds, true).
+		Where(transcriptMatchCondition, query)
+
+	err := cr.db.Table("(?) AS matches", matches).
+		Where("row_num <= ?", limitPerCourse).
+		Order("course_id, row_num").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]dto.TranscriptMatch, len(rows))
+	for i, row := range rows {
+		results[i] = dto.TranscriptMatch{
+			CourseId:    row.CourseId,
+			LessonId:    row.LessonId,
+			LessonTitle: row.LessonTitle,
+			LessonSlug:  row.LessonSlug,
+			Language:    row.Language,
+			StartTime:   float64(row.StartMs) / 1000,
+			EndTime:     float64(row.EndMs) / 1000,
+			Text:        row.Text,
+		}
+	}
+
+	return results, nil
+}
+
 func (cr *DBCourseRepository) GetSearchFilters(query string) (*dto.SearchFilters, error) {
 	searchTerm := fmt.Sprintf("%%%s%%", query)
 
@@ -132,7 +191,8 @@ func (cr *DBCourseRepository) GetSearchFilters(query string) (*dto.SearchFilters
 		Count int    `json:"count"`
 	}
 
-	err := cr.db.Table("course").
+	// Model (không dùng Table) để query được giới hạn theo tenant
+	err := cr.db.Model(&models.Course{}).
 		Select("categories.id, categories.name, COUNT(courses.id) as count").
 		Joins("JOIN categories ON courses.category_id = categories.id").
 		Where("courses.deleted_at IS NULL AND courses.status = ? AND (courses.title ILIKE ? OR courses.description ILIKE ?)",
@@ -237,3 +297,44 @@ func (cr *DBCourseRepository) UpdateCourseStatus(courseId uint, status string) e
 		Where("id = ?", courseId).
 		Update("status", status).Error
 }
+
+func (cr *DBCourseRepository) IncrementEnrolledCount(courseId uint) error {
+	return cr.db.Model(&models.Course{}).
+		Where("id = ?", courseId).
+		Update("enrolled_count", gorm.Expr("enrolled_count + 1")).Error
+}
+
+func (cr *DBCourseRepository) FindByIds(courseIds []uint) ([]models.Course, error) {
+	var courses []models.Course
+
+	if len(courseIds) == 0 {
+		return courses, nil
+	}
+
+	err := cr.db.Preload("Instructor").Preload("Category").
+		Where("id IN ? AND deleted_at IS NULL", courseIds).
+		Find(&courses).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return courses, nil
+}
+
+// GetCoursePrerequisites lấy danh sách course cần hoàn thành trước khi học course này
+func (cr *DBCourseRepository) GetCoursePrerequisites(courseId uint) ([]models.Course, error) {
+	var courses []models.Course
+
+	err := cr.db.Model(&models.Course{}).
+		Joins("JOIN course_prerequisites cp ON cp.prerequisite_course_id = courses.id").
+		Where("cp.course_id = ? AND courses.deleted_at IS NULL", courseId).
+		Order("cp.id ASC").
+		Find(&courses).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return courses, nil
+}
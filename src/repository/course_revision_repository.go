@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"lms/src/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DBCourseRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewDBCourseRevisionRepository(db *gorm.DB) CourseRevisionRepository {
+	return &DBCourseRevisionRepository{
+		db: db,
+	}
+}
+
+func (crr *DBCourseRevisionRepository) Create(revision *models.CourseRevision) error {
+	return crr.db.Create(revision).Error
+}
+
+func (crr *DBCourseRevisionRepository) FindById(revisionId uint) (*models.CourseRevision, error) {
+	var revision models.CourseRevision
+	if err := crr.db.Preload("Creator").Where("id = ?", revisionId).First(&revision).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (crr *DBCourseRevisionRepository) FindDraft(courseId uint) (*models.CourseRevision, error) {
+	var revision models.CourseRevision
+	err := crr.db.Preload("Creator").
+		Where("course_id = ? AND status = ?", courseId, "draft").
+		First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (crr *DBCourseRevisionRepository) FindLatestPublished(courseId uint) (*models.CourseRevision, error) {
+	var revision models.CourseRevision
+	err := crr.db.Where("course_id = ? AND status = ?", courseId, "published").
+		Order("version DESC").
+		First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (crr *DBCourseRevisionRepository) FindPublishedVersion(courseId uint, version int) (*models.CourseRevision, error) {
+	var revision models.CourseRevision
+	err := crr.db.Where("course_id = ? AND status = ? AND version = ?", courseId, "published", version).
+		First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// GetRevisions lấy draft (nếu có) và các version đã publish, mới nhất trước
+func (crr *DBCourseRevisionRepository) GetRevisions(courseId uint, offset, limit int) ([]models.CourseRevision, int, error) {
+	var revisions []models.CourseRevision
+	var total int64
+
+	query := crr.db.Model(&models.CourseRevision{}).
+		Where("course_id = ? AND status IN ?", courseId, []string{"draft", "published"})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Creator").
+		Order("CASE WHEN status = 'draft' THEN 0 ELSE 1 END, version DESC").
+		Offset(offset).Limit(limit).
+		Find(&revisions).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return revisions, int(total), nil
+}
+
+func (crr *DBCourseRevisionRepository) Update(revisionId uint, updates map[string]interface{}) error {
+	return crr.db.Model(&models.CourseRevision{}).
+		Where("id = ?", revisionId).
+		Updates(updates).Error
+}
+
+// LockCourse khóa dòng course (SELECT ... FOR UPDATE) để các lần publish/rollback chạy tuần tự
+func (crr *DBCourseRevisionRepository) LockCourse(courseId uint) (*models.Course, error) {
+	var course models.Course
+	err := crr.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", courseId).
+		First(&course).Error
+	if err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+func (crr *DBCourseRevisionRepository) NextVersion(courseId uint) (int, error) {
+	var version int
+	err := crr.db.Model(&models.CourseRevision{}).
+		Select("COALESCE(MAX(version), 0) + 1").
+		Where("course_id = ? AND status = ?", courseId, "published").
+		Scan(&version).Error
+
+	return version, err
+}
+
+// FindLessons lấy lessons của course theo thứ tự; withDeleted lấy cả lesson đã xóa mềm (để rollback khôi phục)
+func (crr *DBCourseRevisionRepository) FindLessons(courseId uint, withDeleted bool) ([]models.Lesson, error) {
+	var lessons []models.Lesson
+
+	query := crr.db
+	if withDeleted {
+		query = query.Unscoped()
+	}
+
+	err := query.Where("course_id = ?", courseId).
+		Order("lesson_order ASC, id ASC").
+		Find(&lessons).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return lessons, nil
+}
+
+func (crr *DBCourseRevisionRepository) UpdateCourse(courseId uint, updates map[string]interface{}) error {
+	return crr.db.Model(&models.Course{}).
+		Where("id = ?", courseId).
+		Updates(updates).Error
+}
+
+// UpdateLesson cập nhật lesson; restore = true khôi phục lesson đã xóa mềm (giữ nguyên id nên progress cũ vẫn còn)
+func (crr *DBCourseRevisionRepository) UpdateLesson(lessonId uint, updates map[string]interface{}, restore bool) error {
+	query := crr.db.Model(&models.Lesson{}).Where("id = ?", lessonId)
+	if restore {
+		query = query.Unscoped()
+		updates["deleted_at"] = nil
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+	return query.Updates(updates).Error
+}
+
+func (crr *DBCourseRevisionRepository) CreateLesson(lesson *models.Lesson) error {
+	if err := crr.db.Create(lesson).Error; err != nil {
+		return err
+	}
+
+	// is_published có default:true nên giá trị false bị bỏ qua khi insert
+	if !lesson.IsPublished {
+		return crr.db.Model(lesson).Update("is_published", false).Error
+	}
+	return nil
+}
+
+func (crr *DBCourseRevisionRepository) DeleteLessons(courseId uint, lessonIds []uint) error {
+	if len(lessonIds) == 0 {
+		return nil
+	}
+
+	return crr.db.Where("course_id = ? AND id IN ?", courseId, lessonIds).
+		Delete(&models.Lesson{}).Error
+}
+
+// RecalculateProgress tính lại % tiến độ của học viên đang học theo các lesson còn lại.
+// Enrollment đã hoàn thành giữ nguyên trạng thái (chứng chỉ đã cấp).
+func (crr *DBCourseRevisionRepository) RecalculateProgress(courseId uint) error {
+	var totalLessons int64
+	err := crr.db.Model(&models.Lesson{}).
+		Where("course_id = ? AND is_published = ?", courseId, true).
+		Count(&totalLessons).Error
+	if err != nil || totalLessons == 0 {
+		return err
+	}
+
+	completed := crr.db.Model(&models.Progress{}).
+		Select("COUNT(*)").
+		Joins("JOIN lessons ON lessons.id = progresses.lesson_id AND lessons.deleted_at IS NULL AND lessons.is_published = ?", true).
+		Where("progresses.user_id = enrollments.user_id AND progresses.course_id = enrollments.course_id AND progresses.is_completed = ?", true)
+
+	return crr.db.Model(&models.Enrollment{}).
+		Where("course_id = ? AND status = ?", courseId, "active").
+		Update("progress_percentage", gorm.Expr("LEAST(100, (?) * 100.0 / ?)", completed, totalLessons)).Error
+}
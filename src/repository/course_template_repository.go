@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"lms/src/models"
+
+	"gorm.io/gorm"
+)
+
+type DBCourseTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewDBCourseTemplateRepository(db *gorm.DB) CourseTemplateRepository {
+	return &DBCourseTemplateRepository{
+		db: db,
+	}
+}
+
+// GetTemplates lấy các course được admin đánh dấu là template
+func (ctr *DBCourseTemplateRepository) GetTemplates(offset, limit int, filters map[string]interface{}) ([]models.Course, int, error) {
+	var courses []models.Course
+	var total int64
+
+	query := ctr.db.Model(&models.Course{}).
+		Where("is_template = ? AND deleted_at IS NULL", true)
+
+	if search, ok := filters["search"]; ok {
+		query = query.Where("title ILIKE ?", "%"+search.(string)+"%")
+	}
+
+	if categoryId, ok := filters["category_id"]; ok {
+		query = query.Where("category_id = ?", categoryId)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Instructor").Preload("Category").
+		Order("title ASC, id ASC").
+		Offset(offset).Limit(limit).
+		Find(&courses).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return courses, int(total), nil
+}
+
+func (ctr *DBCourseTemplateRepository) SetTemplate(courseId uint, isTemplate bool) error {
+	return ctr.db.Model(&models.Course{}).
+		Where("id = ?", courseId).
+		Update("is_template", isTemplate).Error
+}
+
+// CourseSlugExists kiểm tra cả course đã xóa mềm vì unique index của slug vẫn áp dụng cho chúng
+func (ctr *DBCourseTemplateRepository) CourseSlugExists(slug string) bool {
+	var count int64
+	ctr.db.Unscoped().Model(&models.Course{}).Where("slug = ?", slug).Count(&count)
+	return count > 0
+}
+
+func (ctr *DBCourseTemplateRepository) FindLessons(courseId uint) ([]models.Lesson, error) {
+	var lessons []models.Lesson
+	err := ctr.db.Where("course_id = ?", courseId).
+		Order("lesson_order ASC, id ASC").
+		Find(&lessons).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return lessons, nil
+}
+
+func (ctr *DBCourseTemplateRepository) FindQuizzes(courseId uint) ([]models.Quiz, error) {
+	var quizzes []models.Quiz
+	err := ctr.db.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("question_order ASC")
+	}).
+		Where("course_id = ?", courseId).
+		Order("id ASC").
+		Find(&quizzes).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return quizzes, nil
+}
+
+func (ctr *DBCourseTemplateRepository) FindAttachments(lessonIds []uint) ([]models.LessonAttachment, error) {
+	var attachments []models.LessonAttachment
+	if len(lessonIds) == 0 {
+		return attachments, nil
+	}
+
+	err := ctr.db.Where("lesson_id IN ?", lessonIds).
+		Order("id ASC").
+		Find(&attachments).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (ctr *DBCourseTemplateRepository) CreateCourse(course *models.Course) error {
+	return ctr.db.Create(course).Error
+}
+
+func (ctr *DBCourseTemplateRepository) CreateLesson(lesson *models.Lesson) error {
+	if err := ctr.db.Create(lesson).Error; err != nil {
+		return err
+	}
+
+	// is_published có default:true nên giá trị false bị bỏ qua khi insert
+	if !lesson.IsPublished {
+		return ctr.db.Model(lesson).Update("is_published", false).Error
+	}
+	return nil
+}
+
+// CreateQuiz tạo quiz kèm các câu hỏi
+func (ctr *DBCourseTemplateRepository) CreateQuiz(quiz *models.Quiz) error {
+	return ctr.db.Create(quiz).Error
+}
+
+func (ctr *DBCourseTemplateRepository) SetLessonPrerequisiteQuiz(lessonId, quizId uint) error {
+	return ctr.db.Model(&models.Lesson{}).
+		Where("id = ?", lessonId).
+		Update("prerequisite_quiz_id", quizId).Error
+}
+
+func (ctr *DBCourseTemplateRepository) CreateAttachment(attachment *models.LessonAttachment) error {
+	return ctr.db.Create(attachment).Error
+}
+
+func (ctr *DBCourseTemplateRepository) CreatePrerequisites(courseId uint, prerequisiteIds []uint) error {
+	if len(prerequisiteIds) == 0 {
+		return nil
+	}
+
+	prerequisites := make([]models.CoursePrerequisite, len(prerequisiteIds))
+	for i, prerequisiteId := range prerequisiteIds {
+		prerequisites[i] = models.CoursePrerequisite{
+			CourseId:             courseId,
+			PrerequisiteCourseId: prerequisiteId,
+		}
+	}
+	return ctr.db.Create(&prerequisites).Error
+}
@@ -0,0 +1,312 @@
+package repository
+
+import (
+	"fmt"
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DBDiscussionRepository struct {
+	db *gorm.DB
+}
+
+func NewDBDiscussionRepository(db *gorm.DB) DiscussionRepository {
+	return &DBDiscussionRepository{
+		db: db,
+	}
+}
+
+func (dr *DBDiscussionRepository) CreateThread(thread *models.DiscussionThread) error {
+	return dr.db.Create(thread).Error
+}
+
+func (dr *DBDiscussionRepository) FindThreadById(threadId uint) (*models.DiscussionThread, error) {
+	var thread models.DiscussionThread
+	err := dr.db.Preload("User").Preload("Lesson").
+		Where("id = ?", threadId).
+		First(&thread).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &thread, nil
+}
+
+func (dr *DBDiscussionRepository) GetThreads(offset, limit int, filters map[string]interface{}, sortBy string) ([]models.DiscussionThread, int, error) {
+	var threads []models.DiscussionThread
+	var total int64
+
+	query := dr.db.Model(&models.DiscussionThread{}).Preload("User").Preload("Lesson")
+
+	// Apply filters
+	for field, value := range filters {
+		switch field {
+		case "visible_to":
+			// Thread chỉ dành cho instructor thì chỉ người hỏi thấy được
+			query = query.Where("(is_instructor_only = ? OR user_id = ?)", false, value)
+		case "search":
+			searchTerm := fmt.Sprintf("%%%s%%", value)
+			query = query.Where("(title ILIKE ? OR body ILIKE ?)", searchTerm, searchTerm)
+		default:
+			query = query.Where(fmt.Sprintf("%s = ?", field), value)
+		}
+	}
+
+	// Count total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Thread được ghim luôn ở đầu
+	orderClause := "is_pinned DESC, last_activity_at DESC"
+	switch sortBy {
+	case "top":
+		orderClause = "is_pinned DESC, upvote_count DESC, created_at DESC"
+	case "oldest":
+		orderClause = "is_pinned DESC, created_at ASC"
+	}
+
+	if err := query.Order(orderClause).Offset(offset).Limit(limit).Find(&threads).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return threads, int(total), nil
+}
+
+// GetUnansweredThreads lấy câu hỏi chưa có câu trả lời trong các course mà user là instructor hoặc TA
+func (dr *DBDiscussionRepository) GetUnansweredThreads(staffId uint, offset, limit int, filters map[string]interface{}) ([]models.DiscussionThread, int, error) {
+	var threads []models.DiscussionThread
+	var total int64
+
+	staffCourses := dr.db.Table("courses").Select("id").
+		Where("deleted_at IS NULL AND (instructor_id = ? OR id IN (?))",
+			staffId, dr.db.Table("course_assistants").Select("course_id").Where("user_id = ?", staffId))
+
+	query := dr.db.Model(&models.DiscussionThread{}).
+		Preload("User").Preload("Course").Preload("Lesson").
+		Where("is_answered = ? AND course_id IN (?)", false, staffCourses)
+
+	for field, value := range filters {
+		query = query.Where(fmt.Sprintf("%s = ?", field), value)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Câu hỏi chờ lâu nhất lên trước
+	if err := query.Order("created_at ASC").Offset(offset).Limit(limit).Find(&threads).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return threads, int(total), nil
+}
+
+func (dr *DBDiscussionRepository) UpdateThread(threadId uint, updates map[string]interface{}) error {
+	return dr.db.Model(&models.DiscussionThread{}).
+		Where("id = ?", threadId).
+		Updates(updates).Error
+}
+
+func (dr *DBDiscussionRepository) DeleteThread(threadId uint) error {
+	return dr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("thread_id = ?", threadId).Delete(&models.DiscussionReply{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.DiscussionThread{}, threadId).Error
+	})
+}
+
+// CreateReply tạo reply và cập nhật reply_count, last_activity_at của thread
+func (dr *DBDiscussionRepository) CreateReply(reply *models.DiscussionReply) error {
+	return dr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(reply).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.DiscussionThread{}).
+			Where("id = ?", reply.ThreadId).
+			Updates(map[string]interface{}{
+				"reply_count":      gorm.Expr("reply_count + 1"),
+				"last_activity_at": time.Now(),
+			}).Error
+	})
+}
+
+func (dr *DBDiscussionRepository) FindReplyById(replyId uint) (*models.DiscussionReply, error) {
+	var reply models.DiscussionReply
+	if err := dr.db.Where("id = ?", replyId).First(&reply).Error; err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+func (dr *DBDiscussionRepository) GetReplies(threadId uint) ([]models.DiscussionReply, error) {
+	var replies []models.DiscussionReply
+	err := dr.db.Preload("User").
+		Where("thread_id = ?", threadId).
+		Order("is_answer DESC, created_at ASC").
+		Find(&replies).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return replies, nil
+}
+
+// DeleteReply xóa reply, nếu reply đang là câu trả lời thì thread trở về chưa trả lời
+func (dr *DBDiscussionRepository) DeleteReply(reply *models.DiscussionReply) error {
+	return dr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.DiscussionReply{}, reply.Id).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{"reply_count": gorm.Expr("GREATEST(reply_count - 1, 0)")}
+		if reply.IsAnswer {
+			updates["is_answered"] = false
+			updates["answer_reply_id"] = nil
+		}
+
+		return tx.Model(&models.DiscussionThread{}).
+			Where("id = ?", reply.ThreadId).
+			Updates(updates).Error
+	})
+}
+
+// MarkAnswer đánh dấu reply là câu trả lời của thread (thay câu trả lời cũ nếu có)
+func (dr *DBDiscussionRepository) MarkAnswer(threadId, replyId uint) error {
+	return dr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.DiscussionReply{}).
+			Where("thread_id = ? AND is_answer = ?", threadId, true).
+			Update("is_answer", false).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.DiscussionReply{}).
+			Where("id = ?", replyId).
+			Update("is_answer", true).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.DiscussionThread{}).
+			Where("id = ?", threadId).
+			Updates(map[string]interface{}{
+				"is_answered":     true,
+				"answer_reply_id": replyId,
+			}).Error
+	})
+}
+
+func (dr *DBDiscussionRepository) UnmarkAnswer(threadId uint) error {
+	return dr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.DiscussionReply{}).
+			Where("thread_id = ? AND is_answer = ?", threadId, true).
+			Update("is_answer", false).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.DiscussionThread{}).
+			Where("id = ?", threadId).
+			Updates(map[string]interface{}{
+				"is_answered":     false,
+				"answer_reply_id": nil,
+			}).Error
+	})
+}
+
+// ToggleUpvote thêm hoặc bỏ upvote của user, trả về trạng thái mới và số upvote hiện tại
+func (dr *DBDiscussionRepository) ToggleUpvote(userId uint, targetType string, targetId uint) (bool, int, error) {
+	var target interface{} = &models.DiscussionThread{}
+	if targetType == "reply" {
+		target = &models.DiscussionReply{}
+	}
+
+	upvoted := false
+	count := 0
+
+	err := dr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND target_type = ? AND target_id = ?", userId, targetType, targetId).
+			Delete(&models.DiscussionUpvote{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		delta := -1
+		if result.RowsAffected == 0 {
+			upvote := &models.DiscussionUpvote{UserId: userId, TargetType: targetType, TargetId: targetId}
+			if err := tx.Create(upvote).Error; err != nil {
+				return err
+			}
+			upvoted = true
+			delta = 1
+		}
+
+		if err := tx.Model(target).
+			Where("id = ?", targetId).
+			UpdateColumn("upvote_count", gorm.Expr("GREATEST(upvote_count + ?, 0)", delta)).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(target).
+			Select("upvote_count").
+			Where("id = ?", targetId).
+			Scan(&count).Error
+	})
+
+	return upvoted, count, err
+}
+
+func (dr *DBDiscussionRepository) GetUserUpvotes(userId uint, targetType string, targetIds []uint) (map[uint]bool, error) {
+	upvoted := make(map[uint]bool)
+	if len(targetIds) == 0 {
+		return upvoted, nil
+	}
+
+	var ids []uint
+	err := dr.db.Model(&models.DiscussionUpvote{}).
+		Where("user_id = ? AND target_type = ? AND target_id IN ?", userId, targetType, targetIds).
+		Pluck("target_id", &ids).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		upvoted[id] = true
+	}
+	return upvoted, nil
+}
+
+func (dr *DBDiscussionRepository) IsCourseAssistant(courseId, userId uint) (bool, error) {
+	var count int64
+	err := dr.db.Model(&models.CourseAssistant{}).
+		Where("course_id = ? AND user_id = ?", courseId, userId).
+		Count(&count).Error
+
+	return count > 0, err
+}
+
+func (dr *DBDiscussionRepository) GetCourseAssistants(courseId uint) ([]models.CourseAssistant, error) {
+	var assistants []models.CourseAssistant
+	err := dr.db.Preload("User").
+		Where("course_id = ?", courseId).
+		Order("created_at ASC").
+		Find(&assistants).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return assistants, nil
+}
+
+func (dr *DBDiscussionRepository) AddCourseAssistant(assistant *models.CourseAssistant) error {
+	return dr.db.Where("course_id = ? AND user_id = ?", assistant.CourseId, assistant.UserId).
+		FirstOrCreate(assistant).Error
+}
+
+func (dr *DBDiscussionRepository) RemoveCourseAssistant(courseId, userId uint) error {
+	return dr.db.Where("course_id = ? AND user_id = ?", courseId, userId).
+		Delete(&models.CourseAssistant{}).Error
+}
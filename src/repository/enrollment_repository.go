@@ -85,9 +85,11 @@ func (er *DBEnrollmentRepository) UpdateEnrollmentProgress(enrollmentId uint, up
 
 func (er *DBEnrollmentRepository) CheckUserEnrollment(userId, courseId uint) (bool, error) {
 	var count int64
+	// Enrollment qua subscription chỉ còn hiệu lực khi user vẫn có subscription bao phủ course
 	err := er.db.Model(&models.Enrollment{}).
 		Where("user_id = ? AND course_id = ? AND status = ? AND deleted_at IS NULL",
 			userId, courseId, "active").
+		Where("subscription_id IS NULL OR EXISTS (?)", entitledSubscriptions(er.db, userId, courseId)).
 		Count(&count).Error
 
 	if err != nil {
@@ -96,3 +98,21 @@ func (er *DBEnrollmentRepository) CheckUserEnrollment(userId, courseId uint) (bo
 
 	return count > 0, nil
 }
+
+// GetEnrollmentsByCourses lấy enrollments của user trong danh sách courses
+func (er *DBEnrollmentRepository) GetEnrollmentsByCourses(userId uint, courseIds []uint) ([]models.Enrollment, error) {
+	var enrollments []models.Enrollment
+
+	if len(courseIds) == 0 {
+		return enrollments, nil
+	}
+
+	err := er.db.Where("user_id = ? AND course_id IN ? AND deleted_at IS NULL", userId, courseIds).
+		Find(&enrollments).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return enrollments, nil
+}
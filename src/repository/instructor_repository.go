@@ -272,12 +272,64 @@ func (ir *DBInstructorRepository) FindLessonsByIds(lessonIds []uint) ([]models.L
 	return lessons, nil
 }
 
+func (ir *DBInstructorRepository) FindLessonsByCourse(courseId uint) ([]models.Lesson, error) {
+	var lessons []models.Lesson
+	err := ir.db.Where("course_id = ?", courseId).
+		Order("lesson_order ASC").
+		Find(&lessons).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return lessons, nil
+}
+
 func (ir *DBInstructorRepository) UpdateLessonOrder(lessonId uint, newOrder int) error {
 	return ir.db.Model(&models.Lesson{}).
 		Where("id = ?", lessonId).
 		Update("lesson_order", newOrder).Error
 }
 
+func (ir *DBInstructorRepository) FindQuizByIdAndCourse(quizId, courseId uint) (*models.Quiz, error) {
+	var quiz models.Quiz
+	err := ir.db.Where("id = ? AND course_id = ? AND deleted_at IS NULL", quizId, courseId).
+		First(&quiz).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &quiz, nil
+}
+
+func (ir *DBInstructorRepository) GetPrerequisiteIds(courseId uint) ([]uint, error) {
+	var ids []uint
+	err := ir.db.Model(&models.CoursePrerequisite{}).
+		Where("course_id = ?", courseId).
+		Pluck("prerequisite_course_id", &ids).Error
+	return ids, err
+}
+
+// ReplaceCoursePrerequisites thay thế toàn bộ prerequisites của course
+func (ir *DBInstructorRepository) ReplaceCoursePrerequisites(courseId uint, prerequisiteIds []uint) error {
+	return ir.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("course_id = ?", courseId).Delete(&models.CoursePrerequisite{}).Error; err != nil {
+			return err
+		}
+
+		for _, prerequisiteId := range prerequisiteIds {
+			prerequisite := &models.CoursePrerequisite{
+				CourseId:             courseId,
+				PrerequisiteCourseId: prerequisiteId,
+			}
+			if err := tx.Create(prerequisite).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 func (ir *DBInstructorRepository) BeginTransaction() *gorm.DB {
 	return ir.db.Begin()
 }
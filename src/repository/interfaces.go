@@ -3,6 +3,7 @@ package repository
 import (
 	"lms/src/dto"
 	"lms/src/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -47,6 +48,10 @@ type CourseRepository interface {
 	FindBySlug(slug string) (*models.Course, error)
 	FindById(courseId uint) (*models.Course, error)
 	UpdateCourseStatus(courseId uint, status string) error
+	IncrementEnrolledCount(courseId uint) error
+	FindByIds(courseIds []uint) ([]models.Course, error)
+	GetCoursePrerequisites(courseId uint) ([]models.Course, error)
+	SearchTranscripts(courseIds []uint, query string, limitPerCourse int) ([]dto.TranscriptMatch, error)
 }
 
 type ReviewRepository interface {
@@ -58,6 +63,20 @@ type ReviewRepository interface {
 	UpdateCourseRatingStats(courseId uint) error
 	Delete(reviewId uint) error
 	Update(reviewId uint, updates map[string]interface{}) error
+	CreateReport(report *models.ReviewReport) error
+	HasReported(userId, reviewId uint) (bool, error)
+	CountOpenReports(reviewId uint) (int, error)
+	GetOpenReportCounts(reviewIds []uint) (map[uint]int, error)
+	GetReviewReports(reviewId uint) ([]models.ReviewReport, error)
+	GetModerationQueue(offset, limit int, filters map[string]interface{}) ([]models.Review, int, error)
+	Moderate(reviewId uint, updates map[string]interface{}, reportStatus string, moderatorId uint) error
+	FindReply(reviewId uint) (*models.ReviewReply, error)
+	SaveReply(reply *models.ReviewReply) error
+	DeleteReply(reviewId uint) error
+	SaveVote(vote *models.ReviewVote) error
+	DeleteVote(userId, reviewId uint) (bool, error)
+	CountVotes(reviewId uint) (int, int, error)
+	UpdateHelpfulStats(reviewId uint, helpful, notHelpful int, score float64) error
 }
 
 type LessonRepository interface {
@@ -69,6 +88,18 @@ type LessonRepository interface {
 	GetNextLesson(courseId uint, currentOrder int) (*models.Lesson, error)
 	FindLessonBySlugAndCourse(slug string, courseId uint) (*models.Lesson, error)
 	FindLessonByIds(lessonIds []uint) ([]models.Lesson, error)
+	GetUserEnrollment(userId, courseId uint) (*models.Enrollment, error)
+	GetPassedQuizzes(userId uint, quizIds []uint) (map[uint]bool, error)
+}
+
+type QuizRepository interface {
+	Create(quiz *models.Quiz) error
+	FindById(quizId uint) (*models.Quiz, error)
+	FindByLesson(lessonId uint) (*models.Quiz, error)
+	Delete(quizId uint) error
+	CreateAttempt(attempt *models.QuizAttempt) error
+	GetBestAttempt(userId, quizId uint) (*models.QuizAttempt, error)
+	CountAttempts(userId, quizId uint) (int, error)
 }
 
 type CouponRepository interface {
@@ -103,6 +134,7 @@ type EnrollmentRepository interface {
 	GetUserEnrollments(userId uint, offset, limit int, filters map[string]interface{}) ([]models.Enrollment, int, error)
 	CompleteEnrollment(enrollmentId uint) error
 	UpdateEnrollmentProgress(enrollmentId uint, updates map[string]interface{}) error
+	GetEnrollmentsByCourses(userId uint, courseIds []uint) ([]models.Enrollment, error)
 }
 
 type InstructorRepository interface {
@@ -124,10 +156,123 @@ type InstructorRepository interface {
 	DeleteLesson(lessonId uint) error
 	CheckLessonOrderExistsExcept(courseId uint, lessonOrder int, excludeId uint) (bool, error)
 	FindLessonsByIds(lessonIds []uint) ([]models.Lesson, error)
+	FindLessonsByCourse(courseId uint) ([]models.Lesson, error)
 	UpdateLessonOrder(lessonId uint, newOrder int) error
+	FindQuizByIdAndCourse(quizId, courseId uint) (*models.Quiz, error)
+	GetPrerequisiteIds(courseId uint) ([]uint, error)
+	ReplaceCoursePrerequisites(courseId uint, prerequisiteIds []uint) error
 	BeginTransaction() *gorm.DB
 }
 
+type LearningPathRepository interface {
+	GetLearningPaths(offset, limit int, filters map[string]interface{}, orderBy, sortBy string) ([]models.LearningPath, int, error)
+	GetFeaturedPaths(limit int) ([]models.LearningPath, error)
+	FindById(pathId uint) (*models.LearningPath, error)
+	FindBySlug(slug string) (*models.LearningPath, error)
+	ExistsBySlug(slug string) bool
+	Create(path *models.LearningPath) error
+	Update(pathId uint, updates map[string]interface{}) error
+	Delete(pathId uint) error
+	ReplaceCourses(pathId uint, courseIds []uint) error
+	FindCertificate(userId, pathId uint) (*models.LearningPathCertificate, error)
+	CreateCertificate(certificate *models.LearningPathCertificate) error
+}
+
+type VideoRepository interface {
+	CreateUpload(upload *models.VideoUpload) error
+	FindUploadByUploadId(uploadId string) (*models.VideoUpload, error)
+	FindUploadById(id uint) (*models.VideoUpload, error)
+	UpdateUpload(id uint, updates map[string]interface{}) error
+	SaveChunk(uploadId uint, index int) error
+	GetReceivedChunks(uploadId uint) ([]int, error)
+	ClaimQueuedUpload(now, staleBefore time.Time) (*models.VideoUpload, error)
+	FindLessonById(lessonId uint) (*models.Lesson, error)
+	UpdateLessonVideo(lessonId uint, updates map[string]interface{}) error
+}
+
+type SubtitleRepository interface {
+	FindById(subtitleId uint) (*models.LessonSubtitle, error)
+	FindByLessonAndLanguage(lessonId uint, language string) (*models.LessonSubtitle, error)
+	FindByLesson(lessonId uint) ([]models.LessonSubtitle, error)
+	ReplaceSubtitle(subtitle *models.LessonSubtitle, cues []models.TranscriptCue) error
+	Delete(subtitleId uint) error
+}
+
+type DiscussionRepository interface {
+	CreateThread(thread *models.DiscussionThread) error
+	FindThreadById(threadId uint) (*models.DiscussionThread, error)
+	GetThreads(offset, limit int, filters map[string]interface{}, sortBy string) ([]models.DiscussionThread, int, error)
+	GetUnansweredThreads(staffId uint, offset, limit int, filters map[string]interface{}) ([]models.DiscussionThread, int, error)
+	UpdateThread(threadId uint, updates map[string]interface{}) error
+	DeleteThread(threadId uint) error
+	CreateReply(reply *models.DiscussionReply) error
+	FindReplyById(replyId uint) (*models.DiscussionReply, error)
+	GetReplies(threadId uint) ([]models.DiscussionReply, error)
+	DeleteReply(reply *models.DiscussionReply) error
+	MarkAnswer(threadId, replyId uint) error
+	UnmarkAnswer(threadId uint) error
+	ToggleUpvote(userId uint, targetType string, targetId uint) (bool, int, error)
+	GetUserUpvotes(userId uint, targetType string, targetIds []uint) (map[uint]bool, error)
+	IsCourseAssistant(courseId, userId uint) (bool, error)
+	GetCourseAssistants(courseId uint) ([]models.CourseAssistant, error)
+	AddCourseAssistant(assistant *models.CourseAssistant) error
+	RemoveCourseAssistant(courseId, userId uint) error
+}
+
+type NoteRepository interface {
+	Create(note *models.LessonNote) error
+	FindById(noteId uint) (*models.LessonNote, error)
+	FindByUserAndLesson(userId, lessonId uint) ([]models.LessonNote, error)
+	FindByUserAndCourse(userId, courseId uint) ([]models.LessonNote, error)
+	CountByUserAndLesson(userId, lessonId uint) (int, error)
+	Search(userId uint, query string, offset, limit int, filters map[string]interface{}) ([]models.LessonNote, int, error)
+	Update(noteId uint, updates map[string]interface{}) error
+	Delete(noteId uint) error
+}
+
+type AnnouncementRepository interface {
+	Create(announcement *models.CourseAnnouncement) error
+	FindById(announcementId uint) (*models.CourseAnnouncement, error)
+	GetCourseAnnouncements(courseId uint, offset, limit int) ([]models.CourseAnnouncement, int, error)
+	GetAnnouncementStats(announcementIds []uint) (map[uint]dto.AnnouncementStats, error)
+	Update(announcementId uint, updates map[string]interface{}) error
+	Delete(announcementId uint) error
+	ClaimDueAnnouncements(now, staleBefore time.Time) ([]models.CourseAnnouncement, error)
+	GetActiveEnrollmentUserIds(courseId uint) ([]uint, error)
+	CreateReceipts(receipts []models.AnnouncementReceipt) error
+	GetPendingEmailReceipts(announcementId uint, maxAttempts int) ([]models.AnnouncementReceipt, error)
+	RecordEmailFailure(receiptId uint, message string) error
+	GetUserFeed(userId uint, offset, limit int, filters map[string]interface{}) ([]models.AnnouncementReceipt, int, error)
+	CountUnread(userId uint) (int, error)
+	FindReceipt(userId, announcementId uint) (*models.AnnouncementReceipt, error)
+	FindReceiptByToken(token string) (*models.AnnouncementReceipt, error)
+	MarkReceipt(receiptId uint, column string, at time.Time) error
+	IsUnsubscribed(userId, courseId uint) (bool, error)
+	Unsubscribe(userId, courseId uint) error
+	Subscribe(userId, courseId uint) error
+}
+
+type NotificationRepository interface {
+	Create(notification *models.Notification) error
+	FindById(notificationId uint) (*models.Notification, error)
+	GetUserNotifications(userId uint, offset, limit int, filters map[string]interface{}) ([]models.Notification, int, error)
+	GetNotificationsAfter(userId, afterId uint, limit int) ([]models.Notification, error)
+	CountUnread(userId uint) (int, error)
+	MarkRead(userId uint, notificationIds []uint) (int, error)
+	Delete(userId, notificationId uint) error
+	GetPreferences(userId uint) ([]models.NotificationPreference, error)
+	IsEnabled(userId uint, notificationType string) (bool, error)
+	UpsertPreferences(preferences []models.NotificationPreference) error
+}
+
+type AttachmentRepository interface {
+	Create(attachment *models.LessonAttachment) error
+	FindById(attachmentId uint) (*models.LessonAttachment, error)
+	FindByLesson(lessonId uint) ([]models.LessonAttachment, error)
+	Delete(attachmentId uint) error
+	IncrementDownloadCount(attachmentId uint) error
+}
+
 type ProgressRepository interface {
 	CountCompletedLessons(userId, courseId uint) (int, error)
 	GetCourseProgress(userId, courseId uint) ([]models.Progress, error)
@@ -148,3 +293,203 @@ type AdminAnalyticsRepository interface {
 	GetAdminUsersAnalytics(req *dto.AdminUsersAnalyticsRequest) (*dto.AdminUsersAnalyticsResponse, error)
 	GetAdminCoursesAnalytics(req *dto.AdminCoursesAnalyticsRequest) (*dto.AdminCoursesAnalyticsResponse, error)
 }
+
+type OutboxRepository interface {
+	Append(events ...dto.DomainEvent) error
+	ClaimDueEvents(now, staleBefore time.Time, limit int) ([]models.OutboxEvent, error)
+	FindById(eventId uint) (*models.OutboxEvent, error)
+	GetEvents(offset, limit int, filters map[string]interface{}) ([]models.OutboxEvent, int, error)
+	GetDeliveredSubscribers(eventIds []uint) (map[uint][]string, error)
+	MarkDelivered(eventId uint, subscriber string) error
+	MarkProcessed(eventId uint) error
+	ScheduleRetry(eventId uint, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(eventId uint, lastError string) error
+	Requeue(eventId uint) error
+}
+
+type Transactor interface {
+	WithinTransaction(fn func(repos *TxRepositories) error) error
+	ForTenant(tenantId uint) Transactor
+}
+
+type WebhookRepository interface {
+	CreateEndpoint(endpoint *models.WebhookEndpoint) error
+	FindEndpointById(endpointId uint) (*models.WebhookEndpoint, error)
+	GetEndpoints(offset, limit int, filters map[string]interface{}) ([]models.WebhookEndpoint, int, error)
+	GetActiveEndpoints(tenantId uint) ([]models.WebhookEndpoint, error)
+	UpdateEndpoint(endpointId uint, updates map[string]interface{}) error
+	DeleteEndpoint(endpointId uint) error
+	CreateDeliveries(deliveries []models.WebhookDelivery) error
+	ClaimDueDeliveries(now, staleBefore time.Time, limit int) ([]models.WebhookDelivery, error)
+	FindDeliveryById(deliveryId uint) (*models.WebhookDelivery, error)
+	GetEndpointDeliveries(endpointId uint, offset, limit int, filters map[string]interface{}) ([]models.WebhookDelivery, int, error)
+	UpdateDelivery(deliveryId uint, updates map[string]interface{}) error
+}
+
+type CourseApprovalRepository interface {
+	ChangeStatus(courseId uint, updates map[string]interface{}, history *models.CourseStatusHistory) error
+	GetStatusHistory(courseId uint) ([]models.CourseStatusHistory, error)
+	GetReviewQueue(offset, limit int, filters map[string]interface{}) ([]models.Course, int, error)
+	CountLessons(courseIds []uint) (map[uint]int, error)
+}
+
+type CourseRevisionRepository interface {
+	Create(revision *models.CourseRevision) error
+	FindById(revisionId uint) (*models.CourseRevision, error)
+	FindDraft(courseId uint) (*models.CourseRevision, error)
+	FindLatestPublished(courseId uint) (*models.CourseRevision, error)
+	FindPublishedVersion(courseId uint, version int) (*models.CourseRevision, error)
+	GetRevisions(courseId uint, offset, limit int) ([]models.CourseRevision, int, error)
+	Update(revisionId uint, updates map[string]interface{}) error
+	LockCourse(courseId uint) (*models.Course, error)
+	NextVersion(courseId uint) (int, error)
+	FindLessons(courseId uint, withDeleted bool) ([]models.Lesson, error)
+	UpdateCourse(courseId uint, updates map[string]interface{}) error
+	UpdateLesson(lessonId uint, updates map[string]interface{}, restore bool) error
+	CreateLesson(lesson *models.Lesson) error
+	DeleteLessons(courseId uint, lessonIds []uint) error
+	RecalculateProgress(courseId uint) error
+}
+
+type CourseTemplateRepository interface {
+	GetTemplates(offset, limit int, filters map[string]interface{}) ([]models.Course, int, error)
+	SetTemplate(courseId uint, isTemplate bool) error
+	CourseSlugExists(slug string) bool
+	FindLessons(courseId uint) ([]models.Lesson, error)
+	FindQuizzes(courseId uint) ([]models.Quiz, error)
+	FindAttachments(lessonIds []uint) ([]models.LessonAttachment, error)
+	CreateCourse(course *models.Course) error
+	CreateLesson(lesson *models.Lesson) error
+	CreateQuiz(quiz *models.Quiz) error
+	SetLessonPrerequisiteQuiz(lessonId, quizId uint) error
+	CreateAttachment(attachment *models.LessonAttachment) error
+	CreatePrerequisites(courseId uint, prerequisiteIds []uint) error
+}
+
+type ScormRepository interface {
+	FindLessonById(lessonId uint) (*models.Lesson, error)
+	FindPackageById(packageId uint) (*models.ScormPackage, error)
+	FindPackageByLesson(lessonId uint) (*models.ScormPackage, error)
+	ReplacePackage(pkg *models.ScormPackage) (*models.ScormPackage, error)
+	DeletePackage(pkg *models.ScormPackage) error
+	FindAttempt(userId, packageId uint) (*models.ScormAttempt, error)
+	SaveAttempt(attempt *models.ScormAttempt) error
+	GetAttempts(packageId uint, offset, limit int, filters map[string]interface{}) ([]models.ScormAttempt, int, error)
+}
+
+type XapiRepository interface {
+	FindByStatementIds(statementIds []string) ([]models.XapiStatement, error)
+	CreateStatements(statements []models.XapiStatement) error
+	FindStatement(statementId string, voided bool) (*models.XapiStatement, error)
+	QueryStatements(filters map[string]interface{}, cursor uint, ascending bool, limit int) ([]models.XapiStatement, error)
+}
+
+type LtiRepository interface {
+	CreatePlatform(platform *models.LtiPlatform) error
+	FindPlatformById(platformId uint) (*models.LtiPlatform, error)
+	FindPlatform(issuer, clientId string) (*models.LtiPlatform, error)
+	FindActivePlatforms(issuer, clientId string) ([]models.LtiPlatform, error)
+	GetPlatforms(offset, limit int, filters map[string]interface{}) ([]models.LtiPlatform, int, error)
+	UpdatePlatform(platformId uint, updates map[string]interface{}) error
+	DeletePlatform(platformId uint) error
+	GetToolKey() (*models.LtiToolKey, error)
+	CreateToolKey(key *models.LtiToolKey) error
+	CreateLaunchState(state *models.LtiLaunchState) error
+	ConsumeLaunchState(state string) (*models.LtiLaunchState, error)
+	FindUserLink(platformId uint, subject string) (*models.LtiUserLink, error)
+	CreateUserLink(link *models.LtiUserLink) error
+	FindResourceLink(platformId uint, resourceLinkId string) (*models.LtiResourceLink, error)
+	SaveResourceLink(link *models.LtiResourceLink) error
+	AddResourceLinkUser(linkUser *models.LtiResourceLinkUser) error
+	GetGradeTargets(userId, courseId uint) ([]models.LtiResourceLinkUser, error)
+	UpdateGradeSync(linkUserId uint, score float64, syncedAt time.Time) error
+	CreateDeepLinkSession(session *models.LtiDeepLinkSession) error
+	FindDeepLinkSession(tokenHash string) (*models.LtiDeepLinkSession, error)
+	MarkDeepLinkSessionUsed(sessionId uint) (bool, error)
+}
+
+type OrganizationRepository interface {
+	CreateOrganization(organization *models.Organization, ownerId uint) error
+	FindById(organizationId uint) (*models.Organization, error)
+	SlugExists(slug string) bool
+	GetOrganizations(offset, limit int, filters map[string]interface{}) ([]models.Organization, int, error)
+	UpdateOrganization(organizationId uint, updates map[string]interface{}) error
+	GetUserMemberships(userId uint) ([]models.OrganizationMember, error)
+	CountMembers(organizationIds []uint) (map[uint]int, error)
+	FindMember(organizationId, userId uint) (*models.OrganizationMember, error)
+	GetMembers(organizationId uint, offset, limit int, filters map[string]interface{}) ([]models.OrganizationMember, int, error)
+	CountAdmins(organizationId uint) (int, error)
+	AddMember(member *models.OrganizationMember) error
+	UpdateMemberRole(organizationId, userId uint, role string) error
+	RemoveMember(organizationId, userId uint) error
+	CreateLicense(license *models.OrganizationLicense, invoice *models.OrganizationInvoice) error
+	FindLicense(organizationId, licenseId uint) (*models.OrganizationLicense, error)
+	LockLicense(licenseId uint) (*models.OrganizationLicense, error)
+	GetLicenses(organizationId uint) ([]models.OrganizationLicense, error)
+	CountSeatsUsed(licenseIds []uint) (map[uint]int, error)
+	UpdateLicense(licenseId uint, updates map[string]interface{}) error
+	FindInvoice(invoiceId uint) (*models.OrganizationInvoice, error)
+	FindInvoiceByLicense(licenseId uint) (*models.OrganizationInvoice, error)
+	GetInvoices(organizationId uint, offset, limit int, filters map[string]interface{}) ([]models.OrganizationInvoice, int, error)
+	UpdateInvoiceStatus(invoiceId uint, fromStatus string, updates map[string]interface{}) (bool, error)
+	IsSeatHolder(licenseId, userId uint) (bool, error)
+	CreateAssignment(assignment *models.OrganizationSeatAssignment) error
+	GetActiveAssignments(filters map[string]interface{}) ([]models.OrganizationSeatAssignment, error)
+	ReclaimAssignments(assignmentIds []uint, reclaimedAt time.Time) error
+}
+
+type TenantRepository interface {
+	CreateTenant(tenant *models.Tenant, admin *models.User) error
+	FindById(tenantId uint) (*models.Tenant, error)
+	FindBySlug(slug string) (*models.Tenant, error)
+	FindByHostname(hostname string) (*models.Tenant, error)
+	SlugExists(slug string) bool
+	GetTenants(offset, limit int, filters map[string]interface{}) ([]models.Tenant, int, error)
+	UpdateTenant(tenantId uint, updates map[string]interface{}) error
+	HostnameExists(hostname string) bool
+	AddDomain(domain *models.TenantDomain) error
+	FindDomain(tenantId, domainId uint) (*models.TenantDomain, error)
+	RemoveDomain(domainId uint) error
+	GetTenantAnalytics(startDate, endDate time.Time) ([]dto.TenantAnalyticsItem, error)
+}
+
+type SubscriptionRepository interface {
+	CreatePlan(plan *models.SubscriptionPlan) error
+	FindPlanById(planId uint) (*models.SubscriptionPlan, error)
+	GetPlans(filters map[string]interface{}) ([]models.SubscriptionPlan, error)
+	UpdatePlan(planId uint, updates map[string]interface{}) error
+	ReplacePlanTags(planId uint, tags []string) error
+	CountSubscribers(planIds []uint) (map[uint]int, error)
+	GetCourseTags(courseId uint) ([]string, error)
+	ReplaceCourseTags(courseId uint, tags []string) error
+	Create(subscription *models.Subscription) error
+	FindById(subscriptionId uint) (*models.Subscription, error)
+	FindCurrentByUser(userId uint) (*models.Subscription, error)
+	HasSubscribed(userId uint) (bool, error)
+	FindEntitlingSubscription(userId, courseId uint) (*models.Subscription, error)
+	LockSubscription(subscriptionId uint) (*models.Subscription, error)
+	UpdateSubscription(subscriptionId uint, updates map[string]interface{}) error
+	GetSubscriptions(offset, limit int, filters map[string]interface{}) ([]models.Subscription, int, error)
+	GetDueSubscriptions(now time.Time, limit int) ([]models.Subscription, error)
+	CreatePayment(payment *models.SubscriptionPayment) error
+	GetPayments(userId uint, offset, limit int) ([]models.SubscriptionPayment, int, error)
+}
+
+type BundleRepository interface {
+	GetBundles(offset, limit int, filters map[string]interface{}, orderBy, sortBy string) ([]models.Bundle, int, error)
+	GetPublishedBundlesByCourse(courseId uint) ([]models.Bundle, error)
+	FindById(bundleId uint) (*models.Bundle, error)
+	FindBySlug(slug string) (*models.Bundle, error)
+	ExistsBySlug(slug string) bool
+	Create(bundle *models.Bundle) error
+	Update(bundleId uint, updates map[string]interface{}) error
+	Delete(bundleId uint) error
+	ReplaceCourses(bundleId uint, courseIds []uint) error
+	GetBundleSales(bundleIds []uint) (map[uint]dto.BundleSalesStats, error)
+	CreatePurchase(purchase *models.BundlePurchase) error
+	UpdatePurchase(purchaseId uint, updates map[string]interface{}) error
+	DeletePurchase(purchaseId uint) error
+	HasPendingPurchase(userId, bundleId uint) (bool, error)
+	ExpirePendingPurchases(userId, bundleId uint, createdBefore time.Time) error
+	GetUserPurchases(userId uint, offset, limit int) ([]models.BundlePurchase, int, error)
+}
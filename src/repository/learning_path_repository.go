@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"fmt"
+	"lms/src/models"
+
+	"gorm.io/gorm"
+)
+
+type DBLearningPathRepository struct {
+	db *gorm.DB
+}
+
+func NewDBLearningPathRepository(db *gorm.DB) LearningPathRepository {
+	return &DBLearningPathRepository{
+		db: db,
+	}
+}
+
+// preloadPathCourses load các course của path theo thứ tự position
+func preloadPathCourses(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+func (lpr *DBLearningPathRepository) GetLearningPaths(offset, limit int, filters map[string]interface{}, orderBy, sortBy string) ([]models.LearningPath, int, error) {
+	var paths []models.LearningPath
+	var total int64
+
+	query := lpr.db.Model(&models.LearningPath{}).Where("deleted_at IS NULL")
+
+	// Apply filters
+	for field, value := range filters {
+		if field == "search" {
+			searchTerm := fmt.Sprintf("%%%s%%", value)
+			query = query.Where("title ILIKE ? OR description ILIKE ?", searchTerm, searchTerm)
+		} else {
+			query = query.Where(fmt.Sprintf("%s = ?", field), value)
+		}
+	}
+
+	// Count total
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Validate orderBy
+	allowedOrderBy := map[string]bool{
+		"created_at": true,
+		"title":      true,
+	}
+	if !allowedOrderBy[orderBy] {
+		orderBy = "created_at"
+	}
+	if sortBy != "asc" && sortBy != "desc" {
+		sortBy = "desc"
+	}
+
+	if err := query.
+		Preload("Creator").
+		Preload("Courses", preloadPathCourses).
+		Preload("Courses.Course").
+		Order(fmt.Sprintf("%s %s", orderBy, sortBy)).
+		Offset(offset).
+		Limit(limit).
+		Find(&paths).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return paths, int(total), nil
+}
+
+func (lpr *DBLearningPathRepository) GetFeaturedPaths(limit int) ([]models.LearningPath, error) {
+	var paths []models.LearningPath
+
+	err := lpr.db.
+		Preload("Creator").
+		Preload("Courses", preloadPathCourses).
+		Preload("Courses.Course").
+		Where("deleted_at IS NULL AND status = ? AND is_featured = ?", "published", true).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&paths).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+func (lpr *DBLearningPathRepository) FindById(pathId uint) (*models.LearningPath, error) {
+	var path models.LearningPath
+
+	err := lpr.db.
+		Preload("Creator").
+		Preload("Courses", preloadPathCourses).
+		Preload("Courses.Course").
+		Where("id = ? AND deleted_at IS NULL", pathId).
+		First(&path).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &path, nil
+}
+
+func (lpr *DBLearningPathRepository) FindBySlug(slug string) (*models.LearningPath, error) {
+	var path models.LearningPath
+
+	err := lpr.db.
+		Preload("Creator").
+		Preload("Courses", preloadPathCourses).
+		Preload("Courses.Course").
+		Where("slug = ? AND deleted_at IS NULL", slug).
+		First(&path).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &path, nil
+}
+
+func (lpr *DBLearningPathRepository) ExistsBySlug(slug string) bool {
+	var count int64
+	lpr.db.Unscoped().Model(&models.LearningPath{}).Where("slug = ?", slug).Count(&count)
+	return count > 0
+}
+
+func (lpr *DBLearningPathRepository) Create(path *models.LearningPath) error {
+	return lpr.db.Create(path).Error
+}
+
+func (lpr *DBLearningPathRepository) Update(pathId uint, updates map[string]interface{}) error {
+	return lpr.db.Model(&models.LearningPath{}).
+		Where("id = ?", pathId).
+		Updates(updates).Error
+}
+
+func (lpr *DBLearningPathRepository) Delete(pathId uint) error {
+	return lpr.db.Where("id = ?", pathId).Delete(&models.LearningPath{}).Error
+}
+
+// ReplaceCourses thay thế toàn bộ danh sách course của path theo thứ tự truyền vào
+func (lpr *DBLearningPathRepository) ReplaceCourses(pathId uint, courseIds []uint) error {
+	return lpr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("learning_path_id = ?", pathId).Delete(&models.LearningPathCourse{}).Error; err != nil {
+			return err
+		}
+
+		for i, courseId := range courseIds {
+			item := &models.LearningPathCourse{
+				LearningPathId: pathId,
+				CourseId:       courseId,
+				Position:       i + 1,
+			}
+			if err := tx.Create(item).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (lpr *DBLearningPathRepository) FindCertificate(userId, pathId uint) (*models.LearningPathCertificate, error) {
+	var certificate models.LearningPathCertificate
+
+	err := lpr.db.Where("user_id = ? AND learning_path_id = ?", userId, pathId).
+		First(&certificate).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &certificate, nil
+}
+
+func (lpr *DBLearningPathRepository) CreateCertificate(certificate *models.LearningPathCertificate) error {
+	return lpr.db.Create(certificate).Error
+}
@@ -33,9 +33,11 @@ func (lr *DBLessonRepository) GetCourseLessons(courseId uint) ([]models.Lesson,
 func (lr *DBLessonRepository) CheckUserEnrollment(userId, courseId uint) (bool, error) {
 	var count int64
 
+	// Enrollment qua subscription chỉ còn hiệu lực khi user vẫn có subscription bao phủ course
 	err := lr.db.Model(&models.Enrollment{}).
 		Where("user_id = ? AND course_id = ? AND status = ? AND deleted_at IS NULL",
 			userId, courseId, "active").
+		Where("subscription_id IS NULL OR EXISTS (?)", entitledSubscriptions(lr.db, userId, courseId)).
 		Count(&count).Error
 
 	if err != nil {
@@ -157,3 +159,59 @@ func (lr *DBLessonRepository) FindLessonByIds(lessonIds []uint) ([]models.Lesson
 
 	return lessons, nil
 }
+
+func (lr *DBLessonRepository) GetUserEnrollment(userId, courseId uint) (*models.Enrollment, error) {
+	var enrollment models.Enrollment
+
+	err := lr.db.Where("user_id = ? AND course_id = ? AND deleted_at IS NULL", userId, courseId).
+		First(&enrollment).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &enrollment, nil
+}
+
+// GetPassedQuizzes trả về map quizId -> true cho các quiz user đã pass.
+// Quiz không còn tồn tại (soft delete hoặc đã xóa hẳn) được xem như đã pass để lesson trỏ tới nó
+// (vd. từ revision cũ) không bị khóa vĩnh viễn.
+func (lr *DBLessonRepository) GetPassedQuizzes(userId uint, quizIds []uint) (map[uint]bool, error) {
+	passedMap := make(map[uint]bool)
+	if len(quizIds) == 0 {
+		return passedMap, nil
+	}
+
+	var existingIds []uint
+	if err := lr.db.Model(&models.Quiz{}).
+		Where("id IN ?", quizIds).
+		Pluck("id", &existingIds).Error; err != nil {
+		return nil, err
+	}
+	existing := make(map[uint]bool, len(existingIds))
+	for _, id := range existingIds {
+		existing[id] = true
+	}
+	for _, id := range quizIds {
+		if !existing[id] {
+			passedMap[id] = true
+		}
+	}
+
+	var passedIds []uint
+
+	err := lr.db.Model(&models.QuizAttempt{}).
+		Where("user_id = ? AND quiz_id IN ? AND is_passed = ? AND deleted_at IS NULL", userId, quizIds, true).
+		Distinct("quiz_id").
+		Pluck("quiz_id", &passedIds).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range passedIds {
+		passedMap[id] = true
+	}
+
+	return passedMap, nil
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DBLtiRepository struct {
+	db *gorm.DB
+}
+
+func NewDBLtiRepository(db *gorm.DB) LtiRepository {
+	return &DBLtiRepository{
+		db: db,
+	}
+}
+
+// ---------------- Platforms ----------------
+func (lr *DBLtiRepository) CreatePlatform(platform *models.LtiPlatform) error {
+	return lr.db.Create(platform).Error
+}
+
+func (lr *DBLtiRepository) FindPlatformById(platformId uint) (*models.LtiPlatform, error) {
+	var platform models.LtiPlatform
+	if err := lr.db.Where("id = ?", platformId).First(&platform).Error; err != nil {
+		return nil, err
+	}
+	return &platform, nil
+}
+
+// FindPlatform tìm platform theo cặp issuer + client_id (kể cả đã tắt); nil nếu chưa đăng ký
+func (lr *DBLtiRepository) FindPlatform(issuer, clientId string) (*models.LtiPlatform, error) {
+	var platform models.LtiPlatform
+	err := lr.db.Where("issuer = ? AND client_id = ?", issuer, clientId).First(&platform).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &platform, nil
+}
+
+// FindActivePlatforms tìm platform đang hoạt động theo issuer (và client_id nếu có)
+func (lr *DBLtiRepository) FindActivePlatforms(issuer, clientId string) ([]models.LtiPlatform, error) {
+	query := lr.db.Where("issuer = ? AND is_active = ?", issuer, true)
+	if clientId != "" {
+		query = query.Where("client_id = ?", clientId)
+	}
+
+	var platforms []models.LtiPlatform
+	err := query.Find(&platforms).Error
+	return platforms, err
+}
+
+func (lr *DBLtiRepository) GetPlatforms(offset, limit int, filters map[string]interface{}) ([]models.LtiPlatform, int, error) {
+	query := lr.db.Model(&models.LtiPlatform{})
+	if isActive, ok := filters["is_active"].(bool); ok {
+		query = query.Where("is_active = ?", isActive)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var platforms []models.LtiPlatform
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&platforms).Error
+	return platforms, int(total), err
+}
+
+func (lr *DBLtiRepository) UpdatePlatform(platformId uint, updates map[string]interface{}) error {
+	return lr.db.Model(&models.LtiPlatform{}).Where("id = ?", platformId).Updates(updates).Error
+}
+
+// DeletePlatform xóa platform cùng các liên kết user/resource link, state và deep linking session của nó.
+// Tài khoản và enrollment đã tạo được giữ lại.
+func (lr *DBLtiRepository) DeletePlatform(platformId uint) error {
+	return lr.db.Transaction(func(tx *gorm.DB) error {
+		linkIds := tx.Model(&models.LtiResourceLink{}).Select("id").Where("platform_id = ?", platformId)
+		if err := tx.Where("resource_link_id IN (?)", linkIds).Delete(&models.LtiResourceLinkUser{}).Error; err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{&models.LtiResourceLink{}, &models.LtiUserLink{}, &models.LtiLaunchState{}, &models.LtiDeepLinkSession{}} {
+			if err := tx.Where("platform_id = ?", platformId).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(&models.LtiPlatform{}, platformId).Error
+	})
+}
+
+// ---------------- Tool key ----------------
+// GetToolKey trả về key đầu tiên (nil nếu chưa có) để mọi instance dùng chung một key
+func (lr *DBLtiRepository) GetToolKey() (*models.LtiToolKey, error) {
+	var key models.LtiToolKey
+	err := lr.db.Order("id ASC").First(&key).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (lr *DBLtiRepository) CreateToolKey(key *models.LtiToolKey) error {
+	return lr.db.Create(key).Error
+}
+
+// ---------------- OIDC state ----------------
+// CreateLaunchState lưu state mới và dọn các state đã hết hạn
+func (lr *DBLtiRepository) CreateLaunchState(state *models.LtiLaunchState) error {
+	if err := lr.db.Where("expires_at < ?", time.Now()).Delete(&models.LtiLaunchState{}).Error; err != nil {
+		return err
+	}
+	return lr.db.Create(state).Error
+}
+
+// ConsumeLaunchState xóa và trả về state (nil nếu không tồn tại) - mỗi state chỉ dùng được một lần
+func (lr *DBLtiRepository) ConsumeLaunchState(state string) (*models.LtiLaunchState, error) {
+	var states []models.LtiLaunchState
+	err := lr.db.Clauses(clause.Returning{}).Where("state = ?", state).Delete(&states).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, nil
+	}
+	return &states[0], nil
+}
+
+// ---------------- Users & resource links ----------------
+func (lr *DBLtiRepository) FindUserLink(platformId uint, subject string) (*models.LtiUserLink, error) {
+	var link models.LtiUserLink
+	err := lr.db.Where("platform_id = ? AND subject = ?", platformId, subject).First(&link).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (lr *DBLtiRepository) CreateUserLink(link *models.LtiUserLink) error {
+	return lr.db.Create(link).Error
+}
+
+func (lr *DBLtiRepository) FindResourceLink(platformId uint, resourceLinkId string) (*models.LtiResourceLink, error) {
+	var link models.LtiResourceLink
+	err := lr.db.Where("platform_id = ? AND resource_link_id = ?", platformId, resourceLinkId).First(&link).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (lr *DBLtiRepository) SaveResourceLink(link *models.LtiResourceLink) error {
+	return lr.db.Save(link).Error
+}
+
+// AddResourceLinkUser ghi nhận user đã launch resource link (bỏ qua nếu đã có)
+func (lr *DBLtiRepository) AddResourceLinkUser(linkUser *models.LtiResourceLinkUser) error {
+	return lr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "resource_link_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"subject"}),
+	}).Omit("ResourceLink").Create(linkUser).Error
+}
+
+// GetGradeTargets trả về các resource link có cột điểm mà user đã launch trong course
+func (lr *DBLtiRepository) GetGradeTargets(userId, courseId uint) ([]models.LtiResourceLinkUser, error) {
+	var targets []models.LtiResourceLinkUser
+	err := lr.db.Joins("ResourceLink").
+		Where("lti_resource_link_users.user_id = ?", userId).
+		Where(`"ResourceLink".course_id = ? AND "ResourceLink".line_item_url <> ''`, courseId).
+		Find(&targets).Error
+	return targets, err
+}
+
+func (lr *DBLtiRepository) UpdateGradeSync(linkUserId uint, score float64, syncedAt time.Time) error {
+	return lr.db.Model(&models.LtiResourceLinkUser{}).
+		Where("id = ?", linkUserId).
+		Updates(map[string]interface{}{"last_score": score, "last_synced_at": syncedAt}).Error
+}
+
+// ---------------- Deep linking ----------------
+func (lr *DBLtiRepository) CreateDeepLinkSession(session *models.LtiDeepLinkSession) error {
+	return lr.db.Create(session).Error
+}
+
+func (lr *DBLtiRepository) FindDeepLinkSession(tokenHash string) (*models.LtiDeepLinkSession, error) {
+	var session models.LtiDeepLinkSession
+	err := lr.db.Where("token_hash = ?", tokenHash).First(&session).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// MarkDeepLinkSessionUsed đánh dấu session đã dùng; false nếu session đã được dùng trước đó
+func (lr *DBLtiRepository) MarkDeepLinkSessionUsed(sessionId uint) (bool, error) {
+	result := lr.db.Model(&models.LtiDeepLinkSession{}).
+		Where("id = ? AND used_at IS NULL", sessionId).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"fmt"
+	"lms/src/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// noteMatchCondition dùng index GIN idx_lesson_notes_content_search
+const noteMatchCondition = "to_tsvector('simple', lesson_notes.content) @@ plainto_tsquery('simple', ?)"
+
+type DBNoteRepository struct {
+	db *gorm.DB
+}
+
+func NewDBNoteRepository(db *gorm.DB) NoteRepository {
+	return &DBNoteRepository{
+		db: db,
+	}
+}
+
+func (nr *DBNoteRepository) Create(note *models.LessonNote) error {
+	return nr.db.Create(note).Error
+}
+
+func (nr *DBNoteRepository) FindById(noteId uint) (*models.LessonNote, error) {
+	var note models.LessonNote
+	if err := nr.db.Preload("Lesson").Where("id = ?", noteId).First(&note).Error; err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+func (nr *DBNoteRepository) FindByUserAndLesson(userId, lessonId uint) ([]models.LessonNote, error) {
+	var notes []models.LessonNote
+	err := nr.db.Preload("Lesson").
+		Where("user_id = ? AND lesson_id = ?", userId, lessonId).
+		Order("position ASC NULLS LAST, created_at ASC").
+		Find(&notes).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (nr *DBNoteRepository) FindByUserAndCourse(userId, courseId uint) ([]models.LessonNote, error) {
+	var notes []models.LessonNote
+	err := nr.db.Preload("Lesson").
+		Joins("JOIN lessons ON lessons.id = lesson_notes.lesson_id").
+		Where("lesson_notes.user_id = ? AND lesson_notes.course_id = ?", userId, courseId).
+		Order("lessons.lesson_order ASC, lesson_notes.position ASC NULLS LAST, lesson_notes.created_at ASC").
+		Find(&notes).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (nr *DBNoteRepository) CountByUserAndLesson(userId, lessonId uint) (int, error) {
+	var count int64
+	err := nr.db.Model(&models.LessonNote{}).
+		Where("user_id = ? AND lesson_id = ?", userId, lessonId).
+		Count(&count).Error
+
+	return int(count), err
+}
+
+func (nr *DBNoteRepository) Search(userId uint, query string, offset, limit int, filters map[string]interface{}) ([]models.LessonNote, int, error) {
+	var notes []models.LessonNote
+	var total int64
+
+	dbQuery := nr.db.Model(&models.LessonNote{}).
+		Where("lesson_notes.user_id = ?", userId).
+		Where(noteMatchCondition, query)
+
+	// Apply filters
+	for field, value := range filters {
+		switch field {
+		case "bookmarks_only":
+			dbQuery = dbQuery.Where("lesson_notes.position IS NOT NULL")
+		default:
+			dbQuery = dbQuery.Where(fmt.Sprintf("lesson_notes.%s = ?", field), value)
+		}
+	}
+
+	// Count total
+	if err := dbQuery.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Ghi chú khớp nhiều nhất lên đầu
+	err := dbQuery.Preload("Lesson").Preload("Course").
+		Order(clause.Expr{SQL: "ts_rank(to_tsvector('simple', lesson_notes.content), plainto_tsquery('simple', ?)) DESC", Vars: []interface{}{query}}).
+		Order("lesson_notes.updated_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&notes).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return notes, int(total), nil
+}
+
+func (nr *DBNoteRepository) Update(noteId uint, updates map[string]interface{}) error {
+	return nr.db.Model(&models.LessonNote{}).Where("id = ?", noteId).Updates(updates).Error
+}
+
+func (nr *DBNoteRepository) Delete(noteId uint) error {
+	return nr.db.Delete(&models.LessonNote{}, noteId).Error
+}
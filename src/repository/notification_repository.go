@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"encoding/json"
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationChannel là kênh Postgres LISTEN/NOTIFY dùng để đẩy thông báo mới tới mọi API instance
+const NotificationChannel = "lms_notifications"
+
+// NotificationSignal là payload NOTIFY - chỉ chứa id để không vượt giới hạn 8000 bytes
+type NotificationSignal struct {
+	Id     uint `json:"id"`
+	UserId uint `json:"user_id"`
+}
+
+type DBNotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewDBNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &DBNotificationRepository{
+		db: db,
+	}
+}
+
+// Create lưu thông báo và NOTIFY trong cùng transaction (signal chỉ gửi khi commit)
+func (nr *DBNotificationRepository) Create(notification *models.Notification) error {
+	return nr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(notification).Error; err != nil {
+			return err
+		}
+
+		payload, err := json.Marshal(NotificationSignal{Id: notification.Id, UserId: notification.UserId})
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec("SELECT pg_notify(?, ?)", NotificationChannel, string(payload)).Error
+	})
+}
+
+func (nr *DBNotificationRepository) FindById(notificationId uint) (*models.Notification, error) {
+	var notification models.Notification
+	if err := nr.db.Where("id = ?", notificationId).First(&notification).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (nr *DBNotificationRepository) GetUserNotifications(userId uint, offset, limit int, filters map[string]interface{}) ([]models.Notification, int, error) {
+	var notifications []models.Notification
+	var total int64
+
+	query := nr.db.Model(&models.Notification{}).Where("user_id = ?", userId)
+
+	// Apply filters
+	for field, value := range filters {
+		switch field {
+		case "unread_only":
+			query = query.Where("read_at IS NULL")
+		case "type":
+			query = query.Where("type = ?", value)
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&notifications).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, int(total), nil
+}
+
+func (nr *DBNotificationRepository) CountUnread(userId uint) (int, error) {
+	var count int64
+	err := nr.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userId).
+		Count(&count).Error
+
+	return int(count), err
+}
+
+// MarkRead đánh dấu đã đọc; ids rỗng = tất cả thông báo của user
+func (nr *DBNotificationRepository) MarkRead(userId uint, notificationIds []uint) (int, error) {
+	query := nr.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userId)
+
+	if len(notificationIds) > 0 {
+		query = query.Where("id IN ?", notificationIds)
+	}
+
+	result := query.Update("read_at", time.Now())
+	return int(result.RowsAffected), result.Error
+}
+
+func (nr *DBNotificationRepository) Delete(userId, notificationId uint) error {
+	result := nr.db.Where("id = ? AND user_id = ?", notificationId, userId).Delete(&models.Notification{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (nr *DBNotificationRepository) GetPreferences(userId uint) ([]models.NotificationPreference, error) {
+	var preferences []models.NotificationPreference
+	if err := nr.db.Where("user_id = ?", userId).Find(&preferences).Error; err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+func (nr *DBNotificationRepository) IsEnabled(userId uint, notificationType string) (bool, error) {
+	var preference models.NotificationPreference
+	err := nr.db.Where("user_id = ? AND type = ?", userId, notificationType).Limit(1).Find(&preference).Error
+	if err != nil {
+		return false, err
+	}
+
+	// Chưa có record: mặc định bật
+	return preference.Id == 0 || preference.Enabled, nil
+}
+
+func (nr *DBNotificationRepository) UpsertPreferences(preferences []models.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+	return nr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(&preferences).Error
+}
+
+// GetNotificationsAfter lấy thông báo mới hơn afterId (client stream kết nối lại với Last-Event-ID)
+func (nr *DBNotificationRepository) GetNotificationsAfter(userId, afterId uint, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := nr.db.Where("user_id = ? AND id > ?", userId, afterId).
+		Order("id ASC").
+		Limit(limit).
+		Find(&notifications).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
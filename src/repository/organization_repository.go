@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"fmt"
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DBOrganizationRepository struct {
+	db *gorm.DB
+}
+
+func NewDBOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &DBOrganizationRepository{
+		db: db,
+	}
+}
+
+// ---------------- Organizations ----------------
+// CreateOrganization tạo organization và đặt người tạo làm admin
+func (or *DBOrganizationRepository) CreateOrganization(organization *models.Organization, ownerId uint) error {
+	return or.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(organization).Error; err != nil {
+			return err
+		}
+		return tx.Omit("Organization", "User").Create(&models.OrganizationMember{
+			OrganizationId: organization.Id,
+			UserId:         ownerId,
+			Role:           "admin",
+		}).Error
+	})
+}
+
+func (or *DBOrganizationRepository) FindById(organizationId uint) (*models.Organization, error) {
+	var organization models.Organization
+	if err := or.db.Where("id = ?", organizationId).First(&organization).Error; err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+// SlugExists kiểm tra cả organization đã xóa mềm vì unique index của slug vẫn áp dụng cho chúng
+func (or *DBOrganizationRepository) SlugExists(slug string) bool {
+	var count int64
+	or.db.Unscoped().Model(&models.Organization{}).Where("slug = ?", slug).Count(&count)
+	return count > 0
+}
+
+func (or *DBOrganizationRepository) GetOrganizations(offset, limit int, filters map[string]interface{}) ([]models.Organization, int, error) {
+	query := or.db.Model(&models.Organization{})
+	if status, ok := filters["status"].(string); ok {
+		query = query.Where("status = ?", status)
+	}
+	if search, ok := filters["search"].(string); ok {
+		searchTerm := fmt.Sprintf("%%%s%%", search)
+		query = query.Where("name ILIKE ? OR slug ILIKE ? OR billing_email ILIKE ?", searchTerm, searchTerm, searchTerm)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var organizations []models.Organization
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&organizations).Error
+	return organizations, int(total), err
+}
+
+func (or *DBOrganizationRepository) UpdateOrganization(organizationId uint, updates map[string]interface{}) error {
+	return or.db.Model(&models.Organization{}).Where("id = ?", organizationId).Updates(updates).Error
+}
+
+// ---------------- Members ----------------
+func (or *DBOrganizationRepository) GetUserMemberships(userId uint) ([]models.OrganizationMember, error) {
+	var memberships []models.OrganizationMember
+	err := or.db.Joins("Organization").
+		Where("organization_members.user_id = ?", userId).
+		Order(`"Organization".name ASC`).
+		Find(&memberships).Error
+	return memberships, err
+}
+
+func (or *DBOrganizationRepository) CountMembers(organizationIds []uint) (map[uint]int, error) {
+	counts := make(map[uint]int, len(organizationIds))
+	if len(organizationIds) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		OrganizationId uint
+		Count          int
+	}
+	err := or.db.Model(&models.OrganizationMember{}).
+		Select("organization_id, COUNT(*) AS count").
+		Where("organization_id IN ?", organizationIds).
+		Group("organization_id").
+		Scan(&rows).Error
+	for _, row := range rows {
+		counts[row.OrganizationId] = row.Count
+	}
+	return counts, err
+}
+
+func (or *DBOrganizationRepository) FindMember(organizationId, userId uint) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	err := or.db.Where("organization_id = ? AND user_id = ?", organizationId, userId).First(&member).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (or *DBOrganizationRepository) GetMembers(organizationId uint, offset, limit int, filters map[string]interface{}) ([]models.OrganizationMember, int, error) {
+	query := or.db.Model(&models.OrganizationMember{}).
+		Joins("User").
+		Where("organization_members.organization_id = ?", organizationId)
+	if role, ok := filters["role"].(string); ok {
+		query = query.Where("organization_members.role = ?", role)
+	}
+	if search, ok := filters["search"].(string); ok {
+		searchTerm := fmt.Sprintf("%%%s%%", search)
+		query = query.Where(`"User".username ILIKE ? OR "User".email ILIKE ? OR "User".full_name ILIKE ?`, searchTerm, searchTerm, searchTerm)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var members []models.OrganizationMember
+	err := query.Order("organization_members.created_at ASC").Offset(offset).Limit(limit).Find(&members).Error
+	return members, int(total), err
+}
+
+func (or *DBOrganizationRepository) CountAdmins(organizationId uint) (int, error) {
+	var count int64
+	err := or.db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", organizationId, "admin").
+		Count(&count).Error
+	return int(count), err
+}
+
+func (or *DBOrganizationRepository) AddMember(member *models.OrganizationMember) error {
+	return or.db.Omit("Organization", "User").Create(member).Error
+}
+
+func (or *DBOrganizationRepository) UpdateMemberRole(organizationId, userId uint, role string) error {
+	return or.db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", organizationId, userId).
+		Update("role", role).Error
+}
+
+func (or *DBOrganizationRepository) RemoveMember(organizationId, userId uint) error {
+	return or.db.Where("organization_id = ? AND user_id = ?", organizationId, userId).
+		Delete(&models.OrganizationMember{}).Error
+}
+
+// ---------------- Licenses & invoices ----------------
+// CreateLicense lưu license cùng hóa đơn của nó
+func (or *DBOrganizationRepository) CreateLicense(license *models.OrganizationLicense, invoice *models.OrganizationInvoice) error {
+	return or.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Course").Create(license).Error; err != nil {
+			return err
+		}
+		invoice.LicenseId = license.Id
+		return tx.Create(invoice).Error
+	})
+}
+
+func (or *DBOrganizationRepository) FindLicense(organizationId, licenseId uint) (*models.OrganizationLicense, error) {
+	var license models.OrganizationLicense
+	err := or.db.Preload("Course").
+		Where("id = ? AND organization_id = ?", licenseId, organizationId).
+		First(&license).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &license, nil
+}
+
+// LockLicense khóa dòng license (SELECT ... FOR UPDATE) để việc gán seat chạy tuần tự, không vượt số seat
+func (or *DBOrganizationRepository) LockLicense(licenseId uint) (*models.OrganizationLicense, error) {
+	var license models.OrganizationLicense
+	err := or.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", licenseId).
+		First(&license).Error
+	if err != nil {
+		return nil, err
+	}
+	return &license, nil
+}
+
+func (or *DBOrganizationRepository) GetLicenses(organizationId uint) ([]models.OrganizationLicense, error) {
+	var licenses []models.OrganizationLicense
+	err := or.db.Preload("Course").
+		Where("organization_id = ?", organizationId).
+		Order("created_at DESC").
+		Find(&licenses).Error
+	return licenses, err
+}
+
+// CountSeatsUsed đếm số người đang giữ seat của từng license
+func (or *DBOrganizationRepository) CountSeatsUsed(licenseIds []uint) (map[uint]int, error) {
+	counts := make(map[uint]int, len(licenseIds))
+	if len(licenseIds) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		LicenseId uint
+		Count     int
+	}
+	err := or.db.Model(&models.OrganizationSeatAssignment{}).
+		Select("license_id, COUNT(DISTINCT user_id) AS count").
+		Where("license_id IN ? AND reclaimed_at IS NULL", licenseIds).
+		Group("license_id").
+		Scan(&rows).Error
+	for _, row := range rows {
+		counts[row.LicenseId] = row.Count
+	}
+	return counts, err
+}
+
+func (or *DBOrganizationRepository) UpdateLicense(licenseId uint, updates map[string]interface{}) error {
+	return or.db.Model(&models.OrganizationLicense{}).Where("id = ?", licenseId).Updates(updates).Error
+}
+
+func (or *DBOrganizationRepository) FindInvoice(invoiceId uint) (*models.OrganizationInvoice, error) {
+	var invoice models.OrganizationInvoice
+	err := or.db.Where("id = ?", invoiceId).First(&invoice).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (or *DBOrganizationRepository) FindInvoiceByLicense(licenseId uint) (*models.OrganizationInvoice, error) {
+	var invoice models.OrganizationInvoice
+	err := or.db.Where("license_id = ?", licenseId).First(&invoice).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (or *DBOrganizationRepository) GetInvoices(organizationId uint, offset, limit int, filters map[string]interface{}) ([]models.OrganizationInvoice, int, error) {
+	query := or.db.Model(&models.OrganizationInvoice{}).Where("organization_id = ?", organizationId)
+	if status, ok := filters["status"].(string); ok {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var invoices []models.OrganizationInvoice
+	err := query.Order("issued_at DESC").Offset(offset).Limit(limit).Find(&invoices).Error
+	return invoices, int(total), err
+}
+
+// UpdateInvoiceStatus chuyển trạng thái hóa đơn nếu đang ở fromStatus; false nếu hóa đơn đã được xử lý trước đó
+func (or *DBOrganizationRepository) UpdateInvoiceStatus(invoiceId uint, fromStatus string, updates map[string]interface{}) (bool, error) {
+	result := or.db.Model(&models.OrganizationInvoice{}).
+		Where("id = ? AND status = ?", invoiceId, fromStatus).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ---------------- Seat assignments ----------------
+func (or *DBOrganizationRepository) IsSeatHolder(licenseId, userId uint) (bool, error) {
+	var count int64
+	err := or.db.Model(&models.OrganizationSeatAssignment{}).
+		Where("license_id = ? AND user_id = ? AND reclaimed_at IS NULL", licenseId, userId).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (or *DBOrganizationRepository) CreateAssignment(assignment *models.OrganizationSeatAssignment) error {
+	return or.db.Omit("User", "Course").Create(assignment).Error
+}
+
+// GetActiveAssignments trả về các seat đang được dùng (lọc theo organization_id, license_id, user_id, course_id)
+func (or *DBOrganizationRepository) GetActiveAssignments(filters map[string]interface{}) ([]models.OrganizationSeatAssignment, error) {
+	query := or.db.Preload("User").Preload("Course").Where("reclaimed_at IS NULL")
+	for _, field := range []string{"organization_id", "license_id", "user_id", "course_id"} {
+		if value, ok := filters[field].(uint); ok {
+			query = query.Where(field+" = ?", value)
+		}
+	}
+
+	var assignments []models.OrganizationSeatAssignment
+	err := query.Order("assigned_at ASC").Find(&assignments).Error
+	return assignments, err
+}
+
+func (or *DBOrganizationRepository) ReclaimAssignments(assignmentIds []uint, reclaimedAt time.Time) error {
+	if len(assignmentIds) == 0 {
+		return nil
+	}
+	return or.db.Model(&models.OrganizationSeatAssignment{}).
+		Where("id IN ? AND reclaimed_at IS NULL", assignmentIds).
+		Update("reclaimed_at", reclaimedAt).Error
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"encoding/json"
+	"lms/src/dto"
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OutboxChannel là kênh Postgres LISTEN/NOTIFY để đánh thức dispatcher khi có event mới.
+// NOTIFY nằm trong transaction nên chỉ được gửi khi transaction commit.
+const OutboxChannel = "lms_outbox"
+
+type DBOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewDBOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &DBOutboxRepository{
+		db: db,
+	}
+}
+
+func (or *DBOutboxRepository) Append(events ...dto.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	records := make([]models.OutboxEvent, 0, len(events))
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		records = append(records, models.OutboxEvent{
+			EventType:     event.EventType(),
+			AggregateId:   event.AggregateId(),
+			Payload:       string(payload),
+			Status:        "pending",
+			NextAttemptAt: now,
+		})
+	}
+
+	if err := or.db.Create(&records).Error; err != nil {
+		return err
+	}
+	return or.db.Exec("SELECT pg_notify(?, '')", OutboxChannel).Error
+}
+
+// ClaimDueEvents nhận các event đến hạn xử lý (kể cả event "processing" bị treo do instance chết).
+// FOR UPDATE SKIP LOCKED để nhiều instance không nhận trùng event.
+func (or *DBOutboxRepository) ClaimDueEvents(now, staleBefore time.Time, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := or.db.Raw(`
+		UPDATE outbox_events SET status = 'processing', locked_at = ?, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE (status = 'pending' AND next_attempt_at <= ?) OR (status = 'processing' AND locked_at < ?)
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now, now, staleBefore, limit).
+		Scan(&events).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (or *DBOutboxRepository) FindById(eventId uint) (*models.OutboxEvent, error) {
+	var event models.OutboxEvent
+	if err := or.db.Where("id = ?", eventId).First(&event).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (or *DBOutboxRepository) GetEvents(offset, limit int, filters map[string]interface{}) ([]models.OutboxEvent, int, error) {
+	var events []models.OutboxEvent
+	var total int64
+
+	query := or.db.Model(&models.OutboxEvent{})
+
+	// Apply filters
+	for field, value := range filters {
+		switch field {
+		case "status":
+			query = query.Where("status = ?", value)
+		case "event_type":
+			query = query.Where("event_type = ?", value)
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("id DESC").
+		Offset(offset).Limit(limit).
+		Find(&events).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, int(total), nil
+}
+
+func (or *DBOutboxRepository) GetDeliveredSubscribers(eventIds []uint) (map[uint][]string, error) {
+	delivered := make(map[uint][]string)
+	if len(eventIds) == 0 {
+		return delivered, nil
+	}
+
+	var deliveries []models.OutboxDelivery
+	if err := or.db.Where("event_id IN ?", eventIds).Order("id").Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	for _, delivery := range deliveries {
+		delivered[delivery.EventId] = append(delivered[delivery.EventId], delivery.Subscriber)
+	}
+	return delivered, nil
+}
+
+func (or *DBOutboxRepository) MarkDelivered(eventId uint, subscriber string) error {
+	delivery := models.OutboxDelivery{EventId: eventId, Subscriber: subscriber, DeliveredAt: time.Now()}
+	return or.db.Where("event_id = ? AND subscriber = ?", eventId, subscriber).
+		FirstOrCreate(&delivery).Error
+}
+
+func (or *DBOutboxRepository) MarkProcessed(eventId uint) error {
+	return or.db.Model(&models.OutboxEvent{}).Where("id = ?", eventId).Updates(map[string]interface{}{
+		"status":       "processed",
+		"processed_at": time.Now(),
+		"locked_at":    nil,
+		"last_error":   "",
+	}).Error
+}
+
+func (or *DBOutboxRepository) ScheduleRetry(eventId uint, nextAttemptAt time.Time, lastError string) error {
+	return or.db.Model(&models.OutboxEvent{}).Where("id = ?", eventId).Updates(map[string]interface{}{
+		"status":          "pending",
+		"next_attempt_at": nextAttemptAt,
+		"locked_at":       nil,
+		"last_error":      lastError,
+	}).Error
+}
+
+func (or *DBOutboxRepository) MarkFailed(eventId uint, lastError string) error {
+	return or.db.Model(&models.OutboxEvent{}).Where("id = ?", eventId).Updates(map[string]interface{}{
+		"status":     "failed",
+		"locked_at":  nil,
+		"last_error": lastError,
+	}).Error
+}
+
+// Requeue đưa event failed về hàng đợi với số lần thử mới (subscriber đã xử lý xong vẫn được bỏ qua)
+func (or *DBOutboxRepository) Requeue(eventId uint) error {
+	err := or.db.Model(&models.OutboxEvent{}).Where("id = ?", eventId).Updates(map[string]interface{}{
+		"status":          "pending",
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+		"locked_at":       nil,
+	}).Error
+	if err != nil {
+		return err
+	}
+	return or.db.Exec("SELECT pg_notify(?, '')", OutboxChannel).Error
+}
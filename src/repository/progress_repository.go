@@ -19,8 +19,10 @@ func NewDBProgressRepository(db *gorm.DB) ProgressRepository {
 // CountCompletedLessons đếm số bài học đã hoàn thành của user trong course
 func (pr *DBProgressRepository) CountCompletedLessons(userId, courseId uint) (int, error) {
 	var count int64
+	// Chỉ tính lesson còn trong curriculum (lesson bị gỡ khi publish revision mới không được tính)
 	err := pr.db.Model(&models.Progress{}).
-		Where("user_id = ? AND course_id = ? AND is_completed = ? AND deleted_at IS NULL", userId, courseId, true).
+		Joins("JOIN lessons ON lessons.id = progresses.lesson_id AND lessons.deleted_at IS NULL AND lessons.is_published = ?", true).
+		Where("progresses.user_id = ? AND progresses.course_id = ? AND progresses.is_completed = ?", userId, courseId, true).
 		Count(&count).Error
 
 	if err != nil {
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"lms/src/models"
+
+	"gorm.io/gorm"
+)
+
+type DBQuizRepository struct {
+	db *gorm.DB
+}
+
+func NewDBQuizRepository(db *gorm.DB) QuizRepository {
+	return &DBQuizRepository{
+		db: db,
+	}
+}
+
+// Create tạo quiz cùng với danh sách câu hỏi
+func (qr *DBQuizRepository) Create(quiz *models.Quiz) error {
+	return qr.db.Create(quiz).Error
+}
+
+func (qr *DBQuizRepository) FindById(quizId uint) (*models.Quiz, error) {
+	var quiz models.Quiz
+
+	err := qr.db.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("question_order ASC")
+	}).
+		Where("id = ? AND deleted_at IS NULL", quizId).
+		First(&quiz).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &quiz, nil
+}
+
+func (qr *DBQuizRepository) FindByLesson(lessonId uint) (*models.Quiz, error) {
+	var quiz models.Quiz
+
+	err := qr.db.Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("question_order ASC")
+	}).
+		Where("lesson_id = ? AND deleted_at IS NULL", lessonId).
+		First(&quiz).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &quiz, nil
+}
+
+func (qr *DBQuizRepository) Delete(quizId uint) error {
+	return qr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("quiz_id = ?", quizId).Delete(&models.QuizQuestion{}).Error; err != nil {
+			return err
+		}
+
+		// Lesson yêu cầu pass quiz này không còn điều kiện mở khóa
+		if err := tx.Model(&models.Lesson{}).
+			Where("prerequisite_quiz_id = ?", quizId).
+			Update("prerequisite_quiz_id", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", quizId).Delete(&models.Quiz{}).Error
+	})
+}
+
+func (qr *DBQuizRepository) CreateAttempt(attempt *models.QuizAttempt) error {
+	return qr.db.Create(attempt).Error
+}
+
+// GetBestAttempt lấy lần làm bài có điểm cao nhất của user
+func (qr *DBQuizRepository) GetBestAttempt(userId, quizId uint) (*models.QuizAttempt, error) {
+	var attempt models.QuizAttempt
+
+	err := qr.db.Where("user_id = ? AND quiz_id = ? AND deleted_at IS NULL", userId, quizId).
+		Order("score DESC, submitted_at DESC").
+		First(&attempt).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+func (qr *DBQuizRepository) CountAttempts(userId, quizId uint) (int, error) {
+	var count int64
+
+	err := qr.db.Model(&models.QuizAttempt{}).
+		Where("user_id = ? AND quiz_id = ? AND deleted_at IS NULL", userId, quizId).
+		Count(&count).Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
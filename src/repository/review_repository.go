@@ -5,8 +5,16 @@ import (
 	"lms/src/dto"
 	"lms/src/models"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Cờ của review tính theo dữ liệu hiện tại: mua qua order trả phí, enrollment đã hoàn thành
+const (
+	reviewVerifiedPurchaseSQL = "EXISTS (SELECT 1 FROM orders WHERE orders.user_id = reviews.user_id AND orders.course_id = reviews.course_id AND orders.payment_status = 'paid' AND orders.final_price > 0 AND orders.deleted_at IS NULL)"
+	reviewCompletedCourseSQL  = "EXISTS (SELECT 1 FROM enrollments WHERE enrollments.user_id = reviews.user_id AND enrollments.course_id = reviews.course_id AND enrollments.status = 'completed' AND enrollments.deleted_at IS NULL)"
 )
 
 type DBReviewRepository struct {
@@ -25,11 +33,19 @@ func (rr *DBReviewRepository) GetCourseReviews(courseId uint, offset, limit int,
 
 	query := rr.db.Model(&models.Review{}).
 		Preload("User").
+		Preload("Reply.Instructor").
 		Where("course_id = ? AND deleted_at IS NULL", courseId)
 
 	// Apply filters
 	for field, value := range filters {
-		query = query.Where(fmt.Sprintf("%s = ?", field), value)
+		switch field {
+		case "verified_purchase":
+			query = query.Where(flagCondition(reviewVerifiedPurchaseSQL, value.(bool)))
+		case "completed_course":
+			query = query.Where(flagCondition(reviewCompletedCourseSQL, value.(bool)))
+		default:
+			query = query.Where(fmt.Sprintf("%s = ?", field), value)
+		}
 	}
 
 	// Count total records
@@ -37,14 +53,19 @@ func (rr *DBReviewRepository) GetCourseReviews(courseId uint, offset, limit int,
 		return nil, 0, err
 	}
 
-	// Apply ordering
-	if orderBy != "" && sortBy != "" {
-		query = query.Order(fmt.Sprintf("%s %s", orderBy, strings.ToUpper(sortBy)))
-	} else {
+	// Apply ordering - "helpful" sắp theo Wilson score, hòa thì review nhiều vote hơn và mới hơn lên trước
+	sortBy = strings.ToUpper(sortBy)
+	switch {
+	case orderBy == "helpful" && sortBy != "":
+		query = query.Order(fmt.Sprintf("helpful_score %s, helpful_count %s, created_at DESC", sortBy, sortBy))
+	case orderBy != "" && sortBy != "":
+		query = query.Order(fmt.Sprintf("%s %s", orderBy, sortBy))
+	default:
 		query = query.Order("created_at DESC")
 	}
 
 	// Apply pagination
+	query = query.Select(fmt.Sprintf("reviews.*, %s AS is_verified_purchase, %s AS has_completed_course", reviewVerifiedPurchaseSQL, reviewCompletedCourseSQL))
 	if err := query.Offset(offset).Limit(limit).Find(&reviews).Error; err != nil {
 		return nil, 0, err
 	}
@@ -94,9 +115,64 @@ func (rr *DBReviewRepository) GetCourseReviewStats(courseId uint) (*dto.ReviewSt
 		}
 	}
 
+	// Thống kê theo cờ verified purchase / completed course
+	verified, err := rr.getFlagStats(courseId, reviewVerifiedPurchaseSQL)
+	if err != nil {
+		return nil, err
+	}
+	completed, err := rr.getFlagStats(courseId, reviewCompletedCourseSQL)
+	if err != nil {
+		return nil, err
+	}
+	stats.VerifiedPurchase = *verified
+	stats.CompletedCourse = *completed
+
 	return &stats, nil
 }
 
+// getFlagStats tính tổng, điểm trung bình và phân bố sao của các review thỏa điều kiện cờ
+func (rr *DBReviewRepository) getFlagStats(courseId uint, flagSQL string) (*dto.ReviewFlagStats, error) {
+	var rows []struct {
+		Rating int
+		Count  int
+	}
+
+	err := rr.db.Model(&models.Review{}).
+		Select("rating, COUNT(*) as count").
+		Where("course_id = ? AND is_published = true AND deleted_at IS NULL", courseId).
+		Where(flagSQL).
+		Group("rating").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &dto.ReviewFlagStats{RatingDistribution: make(map[int]int)}
+	for i := 1; i <= 5; i++ {
+		stats.RatingDistribution[i] = 0
+	}
+
+	sum := 0
+	for _, row := range rows {
+		stats.RatingDistribution[row.Rating] = row.Count
+		stats.TotalReviews += row.Count
+		sum += row.Rating * row.Count
+	}
+	if stats.TotalReviews > 0 {
+		stats.AverageRating = float64(sum) / float64(stats.TotalReviews)
+	}
+
+	return stats, nil
+}
+
+func flagCondition(flagSQL string, value bool) string {
+	if value {
+		return flagSQL
+	}
+	return "NOT " + flagSQL
+}
+
 func (rr *DBReviewRepository) FindByUserAndCourse(userId, courseId uint) (*models.Review, error) {
 	var review models.Review
 	err := rr.db.Where("user_id = ? AND course_id = ? AND deleted_at IS NULL", userId, courseId).
@@ -108,13 +184,25 @@ func (rr *DBReviewRepository) FindByUserAndCourse(userId, courseId uint) (*model
 }
 
 func (rr *DBReviewRepository) Create(review *models.Review) error {
-	return rr.db.Create(review).Error
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+
+		// is_published có default:true nên giá trị false bị bỏ qua khi insert
+		if !review.IsPublished {
+			return tx.Model(review).Update("is_published", false).Error
+		}
+		return nil
+	})
 }
 
 func (rr *DBReviewRepository) FindById(reviewId uint) (*models.Review, error) {
 	var review models.Review
-	err := rr.db.Preload("User").Preload("Course").
+	// Review không có tenant_id: giới hạn theo course của tenant để không đọc/moderate review của tenant khác
+	err := rr.db.Preload("User").Preload("Course").Preload("Reply.Instructor").
 		Where("id = ? AND deleted_at IS NULL", reviewId).
+		Where("course_id IN (?)", tenantCourseIds(rr.db)).
 		First(&review).Error
 	if err != nil {
 		return nil, err
@@ -156,3 +244,181 @@ func (rr *DBReviewRepository) UpdateCourseRatingStats(courseId uint) error {
 			"rating_count": stats.Count,
 		}).Error
 }
+
+func (rr *DBReviewRepository) CreateReport(report *models.ReviewReport) error {
+	return rr.db.Create(report).Error
+}
+
+func (rr *DBReviewRepository) HasReported(userId, reviewId uint) (bool, error) {
+	var count int64
+	err := rr.db.Model(&models.ReviewReport{}).
+		Where("user_id = ? AND review_id = ?", userId, reviewId).
+		Count(&count).Error
+
+	return count > 0, err
+}
+
+func (rr *DBReviewRepository) CountOpenReports(reviewId uint) (int, error) {
+	var count int64
+	err := rr.db.Model(&models.ReviewReport{}).
+		Where("review_id = ? AND status = ?", reviewId, "open").
+		Count(&count).Error
+
+	return int(count), err
+}
+
+func (rr *DBReviewRepository) GetOpenReportCounts(reviewIds []uint) (map[uint]int, error) {
+	counts := make(map[uint]int)
+	if len(reviewIds) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ReviewId uint
+		Count    int
+	}
+
+	err := rr.db.Model(&models.ReviewReport{}).
+		Select("review_id, COUNT(*) AS count").
+		Where("review_id IN ? AND status = ?", reviewIds, "open").
+		Group("review_id").
+		Scan(&rows).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ReviewId] = row.Count
+	}
+	return counts, nil
+}
+
+func (rr *DBReviewRepository) GetReviewReports(reviewId uint) ([]models.ReviewReport, error) {
+	var reports []models.ReviewReport
+	err := rr.db.Preload("User").
+		Where("review_id = ?", reviewId).
+		Order("created_at DESC").
+		Find(&reports).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// GetModerationQueue mặc định lấy review đang chờ duyệt hoặc có report chưa xử lý, cũ nhất trước
+func (rr *DBReviewRepository) GetModerationQueue(offset, limit int, filters map[string]interface{}) ([]models.Review, int, error) {
+	var reviews []models.Review
+	var total int64
+
+	openReports := rr.db.Model(&models.ReviewReport{}).
+		Select("1").
+		Where("review_reports.review_id = reviews.id AND review_reports.status = ?", "open")
+
+	query := rr.db.Model(&models.Review{}).Where("reviews.deleted_at IS NULL AND reviews.course_id IN (?)", tenantCourseIds(rr.db))
+
+	switch filters["status"] {
+	case "pending", "hidden":
+		query = query.Where("reviews.moderation_status = ?", filters["status"])
+	case "reported":
+		query = query.Where("EXISTS (?)", openReports)
+	default:
+		query = query.Where("reviews.moderation_status = ? OR EXISTS (?)", "pending", openReports)
+	}
+
+	if courseId, ok := filters["course_id"]; ok {
+		query = query.Where("reviews.course_id = ?", courseId)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").Preload("Course").
+		Order("reviews.created_at ASC").
+		Offset(offset).Limit(limit).
+		Find(&reviews).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reviews, int(total), nil
+}
+
+// Moderate cập nhật trạng thái kiểm duyệt và đóng các report đang mở trong cùng transaction
+func (rr *DBReviewRepository) Moderate(reviewId uint, updates map[string]interface{}, reportStatus string, moderatorId uint) error {
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Review{}).Where("id = ?", reviewId).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.ReviewReport{}).
+			Where("review_id = ? AND status = ?", reviewId, "open").
+			Updates(map[string]interface{}{
+				"status":      reportStatus,
+				"resolved_by": moderatorId,
+				"resolved_at": time.Now(),
+			}).Error
+	})
+}
+
+func (rr *DBReviewRepository) FindReply(reviewId uint) (*models.ReviewReply, error) {
+	var reply models.ReviewReply
+	if err := rr.db.Preload("Instructor").Where("review_id = ?", reviewId).First(&reply).Error; err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// SaveReply tạo hoặc cập nhật phản hồi duy nhất của review
+func (rr *DBReviewRepository) SaveReply(reply *models.ReviewReply) error {
+	return rr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "review_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"instructor_id", "content", "updated_at"}),
+	}).Create(reply).Error
+}
+
+func (rr *DBReviewRepository) DeleteReply(reviewId uint) error {
+	return rr.db.Where("review_id = ?", reviewId).Delete(&models.ReviewReply{}).Error
+}
+
+// SaveVote tạo hoặc đổi vote của user cho review
+func (rr *DBReviewRepository) SaveVote(vote *models.ReviewVote) error {
+	return rr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "review_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"is_helpful", "updated_at"}),
+	}).Create(vote).Error
+}
+
+func (rr *DBReviewRepository) DeleteVote(userId, reviewId uint) (bool, error) {
+	result := rr.db.Where("user_id = ? AND review_id = ?", userId, reviewId).Delete(&models.ReviewVote{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountVotes đếm số vote hữu ích / không hữu ích hiện tại của review
+func (rr *DBReviewRepository) CountVotes(reviewId uint) (int, int, error) {
+	var counts struct {
+		Helpful    int
+		NotHelpful int
+	}
+
+	err := rr.db.Model(&models.ReviewVote{}).
+		Select("COUNT(*) FILTER (WHERE is_helpful) AS helpful, COUNT(*) FILTER (WHERE NOT is_helpful) AS not_helpful").
+		Where("review_id = ?", reviewId).
+		Scan(&counts).Error
+
+	return counts.Helpful, counts.NotHelpful, err
+}
+
+// UpdateHelpfulStats ghi số vote và score, không đổi updated_at của review
+func (rr *DBReviewRepository) UpdateHelpfulStats(reviewId uint, helpful, notHelpful int, score float64) error {
+	return rr.db.Model(&models.Review{}).
+		Where("id = ?", reviewId).
+		UpdateColumns(map[string]interface{}{
+			"helpful_count":     helpful,
+			"not_helpful_count": notHelpful,
+			"helpful_score":     score,
+		}).Error
+}
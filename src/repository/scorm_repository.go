@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"lms/src/models"
+
+	"gorm.io/gorm"
+)
+
+type DBScormRepository struct {
+	db *gorm.DB
+}
+
+func NewDBScormRepository(db *gorm.DB) ScormRepository {
+	return &DBScormRepository{
+		db: db,
+	}
+}
+
+func (sr *DBScormRepository) FindLessonById(lessonId uint) (*models.Lesson, error) {
+	var lesson models.Lesson
+	if err := sr.db.Where("id = ?", lessonId).First(&lesson).Error; err != nil {
+		return nil, err
+	}
+	return &lesson, nil
+}
+
+func (sr *DBScormRepository) FindPackageById(packageId uint) (*models.ScormPackage, error) {
+	var pkg models.ScormPackage
+	if err := sr.db.Where("id = ?", packageId).First(&pkg).Error; err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+func (sr *DBScormRepository) FindPackageByLesson(lessonId uint) (*models.ScormPackage, error) {
+	var pkg models.ScormPackage
+	if err := sr.db.Where("lesson_id = ?", lessonId).First(&pkg).Error; err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
+
+// ReplacePackage thay package của lesson (xóa package cũ cùng dữ liệu cmi của nó) và chuyển lesson sang loại scorm.
+// Trả về package cũ để service dọn file trên storage.
+func (sr *DBScormRepository) ReplacePackage(pkg *models.ScormPackage) (*models.ScormPackage, error) {
+	var previous *models.ScormPackage
+	err := sr.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.ScormPackage
+		err := tx.Where("lesson_id = ?", pkg.LessonId).First(&existing).Error
+		if err == nil {
+			previous = &existing
+			if err := deleteScormPackage(tx, &existing); err != nil {
+				return err
+			}
+		} else if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		if err := tx.Create(pkg).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Lesson{}).
+			Where("id = ?", pkg.LessonId).
+			Update("lesson_type", "scorm").Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
+
+// DeletePackage xóa package cùng dữ liệu cmi, lesson trở về loại standard
+func (sr *DBScormRepository) DeletePackage(pkg *models.ScormPackage) error {
+	return sr.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteScormPackage(tx, pkg); err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Lesson{}).
+			Where("id = ?", pkg.LessonId).
+			Update("lesson_type", "standard").Error
+	})
+}
+
+func deleteScormPackage(tx *gorm.DB, pkg *models.ScormPackage) error {
+	if err := tx.Where("package_id = ?", pkg.Id).Delete(&models.ScormAttempt{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(pkg).Error
+}
+
+// FindAttempt trả về nil nếu học viên chưa chạy package
+func (sr *DBScormRepository) FindAttempt(userId, packageId uint) (*models.ScormAttempt, error) {
+	var attempt models.ScormAttempt
+	err := sr.db.Where("user_id = ? AND package_id = ?", userId, packageId).First(&attempt).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &attempt, nil
+}
+
+func (sr *DBScormRepository) SaveAttempt(attempt *models.ScormAttempt) error {
+	return sr.db.Save(attempt).Error
+}
+
+func (sr *DBScormRepository) GetAttempts(packageId uint, offset, limit int, filters map[string]interface{}) ([]models.ScormAttempt, int, error) {
+	var attempts []models.ScormAttempt
+	var total int64
+
+	query := sr.db.Model(&models.ScormAttempt{}).Where("package_id = ?", packageId)
+
+	if completionStatus, ok := filters["completion_status"]; ok {
+		query = query.Where("completion_status = ?", completionStatus)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").
+		Order("last_committed_at DESC NULLS LAST, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&attempts).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return attempts, int(total), nil
+}
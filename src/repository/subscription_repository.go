@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Trạng thái subscription còn quyền truy cập (past_due vẫn học được trong thời gian gia hạn)
+var currentSubscriptionStatuses = []string{"trialing", "active", "past_due"}
+
+type DBSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewDBSubscriptionRepository(db *gorm.DB) SubscriptionRepository {
+	return &DBSubscriptionRepository{
+		db: db,
+	}
+}
+
+// entitledSubscriptions là subquery các subscription còn hiệu lực của user có plan bao phủ course:
+// plan scope all, hoặc plan scope tagged có ít nhất một tag trùng với tag của course.
+// Enrollment ghi danh qua subscription chỉ học được khi subquery này còn kết quả.
+func entitledSubscriptions(db *gorm.DB, userId, courseId uint) *gorm.DB {
+	return db.Table("subscriptions").
+		Select("subscriptions.id").
+		Joins("JOIN subscription_plans ON subscription_plans.id = subscriptions.plan_id").
+		Where("subscriptions.user_id = ? AND subscriptions.status IN ?", userId, currentSubscriptionStatuses).
+		Where("subscription_plans.scope = ? OR EXISTS (?)", "all",
+			db.Table("subscription_plan_tags").
+				Select("1").
+				Joins("JOIN course_tags ON course_tags.tag = subscription_plan_tags.tag").
+				Where("subscription_plan_tags.plan_id = subscription_plans.id AND course_tags.course_id = ?", courseId),
+		)
+}
+
+// ---------------- Plans ----------------
+func (sr *DBSubscriptionRepository) CreatePlan(plan *models.SubscriptionPlan) error {
+	return sr.db.Create(plan).Error
+}
+
+func (sr *DBSubscriptionRepository) FindPlanById(planId uint) (*models.SubscriptionPlan, error) {
+	var plan models.SubscriptionPlan
+	err := sr.db.Preload("Tags").Where("id = ?", planId).First(&plan).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// GetPlans lấy danh sách plan (lọc theo status, interval), plan rẻ nhất trước
+func (sr *DBSubscriptionRepository) GetPlans(filters map[string]interface{}) ([]models.SubscriptionPlan, error) {
+	query := sr.db.Preload("Tags")
+	for _, field := range []string{"status", "interval"} {
+		if value, ok := filters[field].(string); ok {
+			query = query.Where("subscription_plans."+field+" = ?", value)
+		}
+	}
+
+	var plans []models.SubscriptionPlan
+	err := query.Order("price ASC, id ASC").Find(&plans).Error
+	return plans, err
+}
+
+func (sr *DBSubscriptionRepository) UpdatePlan(planId uint, updates map[string]interface{}) error {
+	return sr.db.Model(&models.SubscriptionPlan{}).
+		Where("id = ?", planId).
+		Updates(updates).Error
+}
+
+func (sr *DBSubscriptionRepository) ReplacePlanTags(planId uint, tags []string) error {
+	return sr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("plan_id = ?", planId).Delete(&models.SubscriptionPlanTag{}).Error; err != nil {
+			return err
+		}
+
+		for _, tag := range tags {
+			if err := tx.Create(&models.SubscriptionPlanTag{PlanId: planId, Tag: tag}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CountSubscribers đếm subscription còn hiệu lực của từng plan
+func (sr *DBSubscriptionRepository) CountSubscribers(planIds []uint) (map[uint]int, error) {
+	counts := make(map[uint]int, len(planIds))
+	if len(planIds) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		PlanId uint
+		Count  int
+	}
+	err := sr.db.Model(&models.Subscription{}).
+		Select("plan_id, COUNT(*) AS count").
+		Where("plan_id IN ? AND status IN ?", planIds, currentSubscriptionStatuses).
+		Group("plan_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.PlanId] = row.Count
+	}
+	return counts, nil
+}
+
+// ---------------- Course tags ----------------
+func (sr *DBSubscriptionRepository) GetCourseTags(courseId uint) ([]string, error) {
+	var tags []string
+	err := sr.db.Model(&models.CourseTag{}).
+		Where("course_id = ?", courseId).
+		Order("tag ASC").
+		Pluck("tag", &tags).Error
+	return tags, err
+}
+
+func (sr *DBSubscriptionRepository) ReplaceCourseTags(courseId uint, tags []string) error {
+	return sr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("course_id = ?", courseId).Delete(&models.CourseTag{}).Error; err != nil {
+			return err
+		}
+
+		for _, tag := range tags {
+			if err := tx.Create(&models.CourseTag{CourseId: courseId, Tag: tag}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ---------------- Subscriptions ----------------
+func (sr *DBSubscriptionRepository) Create(subscription *models.Subscription) error {
+	return sr.db.Omit("User", "Plan").Create(subscription).Error
+}
+
+func (sr *DBSubscriptionRepository) FindById(subscriptionId uint) (*models.Subscription, error) {
+	var subscription models.Subscription
+	if err := sr.db.Preload("Plan").Preload("Plan.Tags").
+		Where("id = ?", subscriptionId).
+		First(&subscription).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// FindCurrentByUser lấy subscription đang hiệu lực của user, nil nếu không có
+func (sr *DBSubscriptionRepository) FindCurrentByUser(userId uint) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := sr.db.Preload("Plan").Preload("Plan.Tags").
+		Where("user_id = ? AND status IN ?", userId, currentSubscriptionStatuses).
+		First(&subscription).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// HasSubscribed cho biết user đã từng có subscription (dùng để chỉ cho dùng thử một lần)
+func (sr *DBSubscriptionRepository) HasSubscribed(userId uint) (bool, error) {
+	var count int64
+	err := sr.db.Model(&models.Subscription{}).Where("user_id = ?", userId).Count(&count).Error
+	return count > 0, err
+}
+
+// FindEntitlingSubscription lấy subscription còn hiệu lực của user bao phủ course, nil nếu không có
+func (sr *DBSubscriptionRepository) FindEntitlingSubscription(userId, courseId uint) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := sr.db.Preload("Plan").
+		Where("id IN (?)", entitledSubscriptions(sr.db, userId, courseId)).
+		First(&subscription).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// LockSubscription khóa dòng subscription (SELECT ... FOR UPDATE) để gia hạn/hủy chạy tuần tự, không thu tiền hai lần
+func (sr *DBSubscriptionRepository) LockSubscription(subscriptionId uint) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := sr.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", subscriptionId).
+		First(&subscription).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (sr *DBSubscriptionRepository) UpdateSubscription(subscriptionId uint, updates map[string]interface{}) error {
+	return sr.db.Model(&models.Subscription{}).
+		Where("id = ?", subscriptionId).
+		Updates(updates).Error
+}
+
+// GetSubscriptions lấy danh sách subscription (lọc theo status, plan_id, user_id), mới nhất trước
+func (sr *DBSubscriptionRepository) GetSubscriptions(offset, limit int, filters map[string]interface{}) ([]models.Subscription, int, error) {
+	query := sr.db.Model(&models.Subscription{})
+	if status, ok := filters["status"].(string); ok {
+		query = query.Where("status = ?", status)
+	}
+	for _, field := range []string{"plan_id", "user_id"} {
+		if value, ok := filters[field].(uint); ok {
+			query = query.Where(field+" = ?", value)
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var subscriptions []models.Subscription
+	err := query.Preload("User").Preload("Plan").
+		Order("created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&subscriptions).Error
+	return subscriptions, int(total), err
+}
+
+// GetDueSubscriptions lấy các subscription cần xử lý: hết trial/hết kỳ, hoặc past_due đến lượt thu lại/hết gia hạn.
+// Chỉ lấy id và tenant_id, biller khóa lại từng subscription trong transaction của tenant.
+func (sr *DBSubscriptionRepository) GetDueSubscriptions(now time.Time, limit int) ([]models.Subscription, error) {
+	var subscriptions []models.Subscription
+	err := sr.db.Model(&models.Subscription{}).
+		Select("id", "tenant_id").
+		Where("(status IN ? AND current_period_end <= ?) OR (status = ? AND (next_retry_at <= ? OR grace_ends_at <= ?))",
+			[]string{"trialing", "active"}, now, "past_due", now, now).
+		Order("current_period_end ASC").
+		Limit(limit).
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// ---------------- Payments ----------------
+func (sr *DBSubscriptionRepository) CreatePayment(payment *models.SubscriptionPayment) error {
+	return sr.db.Create(payment).Error
+}
+
+// GetPayments lấy lịch sử thanh toán của user, mới nhất trước
+func (sr *DBSubscriptionRepository) GetPayments(userId uint, offset, limit int) ([]models.SubscriptionPayment, int, error) {
+	query := sr.db.Model(&models.SubscriptionPayment{}).Where("user_id = ?", userId)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var payments []models.SubscriptionPayment
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&payments).Error
+	return payments, int(total), err
+}
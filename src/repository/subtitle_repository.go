@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"lms/src/models"
+
+	"gorm.io/gorm"
+)
+
+type DBSubtitleRepository struct {
+	db *gorm.DB
+}
+
+func NewDBSubtitleRepository(db *gorm.DB) SubtitleRepository {
+	return &DBSubtitleRepository{
+		db: db,
+	}
+}
+
+func (sr *DBSubtitleRepository) FindById(subtitleId uint) (*models.LessonSubtitle, error) {
+	var subtitle models.LessonSubtitle
+	if err := sr.db.Where("id = ?", subtitleId).First(&subtitle).Error; err != nil {
+		return nil, err
+	}
+	return &subtitle, nil
+}
+
+func (sr *DBSubtitleRepository) FindByLessonAndLanguage(lessonId uint, language string) (*models.LessonSubtitle, error) {
+	var subtitle models.LessonSubtitle
+	if err := sr.db.Where("lesson_id = ? AND language = ?", lessonId, language).First(&subtitle).Error; err != nil {
+		return nil, err
+	}
+	return &subtitle, nil
+}
+
+func (sr *DBSubtitleRepository) FindByLesson(lessonId uint) ([]models.LessonSubtitle, error) {
+	var subtitles []models.LessonSubtitle
+	err := sr.db.Where("lesson_id = ?", lessonId).
+		Order("language ASC").
+		Find(&subtitles).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return subtitles, nil
+}
+
+// ReplaceSubtitle thay subtitle (và transcript) cùng ngôn ngữ của lesson trong một transaction
+func (sr *DBSubtitleRepository) ReplaceSubtitle(subtitle *models.LessonSubtitle, cues []models.TranscriptCue) error {
+	return sr.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.LessonSubtitle
+		err := tx.Where("lesson_id = ? AND language = ?", subtitle.LessonId, subtitle.Language).First(&existing).Error
+		if err == nil {
+			if err := deleteSubtitle(tx, existing.Id); err != nil {
+				return err
+			}
+		} else if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		if err := tx.Create(subtitle).Error; err != nil {
+			return err
+		}
+
+		for i := range cues {
+			cues[i].SubtitleId = subtitle.Id
+		}
+		if len(cues) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(cues, 500).Error
+	})
+}
+
+func (sr *DBSubtitleRepository) Delete(subtitleId uint) error {
+	return sr.db.Transaction(func(tx *gorm.DB) error {
+		return deleteSubtitle(tx, subtitleId)
+	})
+}
+
+func deleteSubtitle(tx *gorm.DB, subtitleId uint) error {
+	if err := tx.Where("subtitle_id = ?", subtitleId).Delete(&models.TranscriptCue{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&models.LessonSubtitle{}, subtitleId).Error
+}
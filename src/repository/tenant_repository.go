@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DBTenantRepository làm việc trên kết nối gốc (không giới hạn tenant) vì tenant là dữ liệu cấp platform
+type DBTenantRepository struct {
+	db *gorm.DB
+}
+
+func NewDBTenantRepository(db *gorm.DB) TenantRepository {
+	return &DBTenantRepository{
+		db: db,
+	}
+}
+
+// ---------------- Tenants ----------------
+// CreateTenant tạo tenant, các domain và tài khoản admin đầu tiên của tenant
+func (tr *DBTenantRepository) CreateTenant(tenant *models.Tenant, admin *models.User) error {
+	return tr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(tenant).Error; err != nil {
+			return err
+		}
+		admin.TenantId = tenant.Id
+		return tx.Create(admin).Error
+	})
+}
+
+func (tr *DBTenantRepository) FindById(tenantId uint) (*models.Tenant, error) {
+	var tenant models.Tenant
+	if err := tr.db.Preload("Domains").Where("id = ?", tenantId).First(&tenant).Error; err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+func (tr *DBTenantRepository) FindBySlug(slug string) (*models.Tenant, error) {
+	var tenant models.Tenant
+	err := tr.db.Where("slug = ?", slug).First(&tenant).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+func (tr *DBTenantRepository) FindByHostname(hostname string) (*models.Tenant, error) {
+	var tenant models.Tenant
+	err := tr.db.Joins("JOIN tenant_domains ON tenant_domains.tenant_id = tenants.id").
+		Where("tenant_domains.hostname = ?", hostname).
+		First(&tenant).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// SlugExists kiểm tra cả tenant đã xóa mềm vì unique index của slug vẫn áp dụng cho chúng
+func (tr *DBTenantRepository) SlugExists(slug string) bool {
+	var count int64
+	tr.db.Unscoped().Model(&models.Tenant{}).Where("slug = ?", slug).Count(&count)
+	return count > 0
+}
+
+func (tr *DBTenantRepository) GetTenants(offset, limit int, filters map[string]interface{}) ([]models.Tenant, int, error) {
+	query := tr.db.Model(&models.Tenant{})
+	if status, ok := filters["status"].(string); ok {
+		query = query.Where("status = ?", status)
+	}
+	if search, ok := filters["search"].(string); ok {
+		searchTerm := fmt.Sprintf("%%%s%%", search)
+		query = query.Where("name ILIKE ? OR slug ILIKE ?", searchTerm, searchTerm)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var tenants []models.Tenant
+	err := query.Preload("Domains").Order("id ASC").Offset(offset).Limit(limit).Find(&tenants).Error
+	return tenants, int(total), err
+}
+
+func (tr *DBTenantRepository) UpdateTenant(tenantId uint, updates map[string]interface{}) error {
+	return tr.db.Model(&models.Tenant{}).Where("id = ?", tenantId).Updates(updates).Error
+}
+
+// ---------------- Domains ----------------
+func (tr *DBTenantRepository) HostnameExists(hostname string) bool {
+	var count int64
+	tr.db.Model(&models.TenantDomain{}).Where("hostname = ?", hostname).Count(&count)
+	return count > 0
+}
+
+func (tr *DBTenantRepository) AddDomain(domain *models.TenantDomain) error {
+	return tr.db.Create(domain).Error
+}
+
+func (tr *DBTenantRepository) FindDomain(tenantId, domainId uint) (*models.TenantDomain, error) {
+	var domain models.TenantDomain
+	err := tr.db.Where("id = ? AND tenant_id = ?", domainId, tenantId).First(&domain).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &domain, nil
+}
+
+func (tr *DBTenantRepository) RemoveDomain(domainId uint) error {
+	return tr.db.Where("id = ?", domainId).Delete(&models.TenantDomain{}).Error
+}
+
+// ---------------- Cross-tenant analytics ----------------
+// GetTenantAnalytics tổng hợp số liệu của từng tenant; số mới/đơn hàng/doanh thu tính trong khoảng thời gian
+func (tr *DBTenantRepository) GetTenantAnalytics(startDate, endDate time.Time) ([]dto.TenantAnalyticsItem, error) {
+	var items []dto.TenantAnalyticsItem
+	err := tr.db.Raw(`
+		SELECT
+			tenants.id AS tenant_id,
+			tenants.slug AS tenant_slug,
+			tenants.name AS tenant_name,
+			tenants.status AS status,
+			COALESCE(user_stats.total_users, 0) AS total_users,
+			COALESCE(user_stats.new_users, 0) AS new_users,
+			COALESCE(user_stats.instructors, 0) AS instructors,
+			COALESCE(course_stats.courses, 0) AS courses,
+			COALESCE(course_stats.published_courses, 0) AS published_courses,
+			COALESCE(enrollment_stats.enrollments, 0) AS enrollments,
+			COALESCE(order_stats.orders, 0) AS orders,
+			COALESCE(order_stats.revenue, 0) AS revenue
+		FROM tenants
+		LEFT JOIN (
+			SELECT tenant_id,
+				COUNT(*) AS total_users,
+				COUNT(CASE WHEN created_at BETWEEN @start AND @end THEN 1 END) AS new_users,
+				COUNT(CASE WHEN role = 'instructor' THEN 1 END) AS instructors
+			FROM users
+			WHERE deleted_at IS NULL
+			GROUP BY tenant_id
+		) user_stats ON user_stats.tenant_id = tenants.id
+		LEFT JOIN (
+			SELECT tenant_id,
+				COUNT(*) AS courses,
+				COUNT(CASE WHEN status = 'published' THEN 1 END) AS published_courses
+			FROM courses
+			WHERE deleted_at IS NULL
+			GROUP BY tenant_id
+		) course_stats ON course_stats.tenant_id = tenants.id
+		LEFT JOIN (
+			SELECT courses.tenant_id, COUNT(enrollments.id) AS enrollments
+			FROM enrollments
+			JOIN courses ON courses.id = enrollments.course_id
+			WHERE enrollments.enrolled_at BETWEEN @start AND @end
+			GROUP BY courses.tenant_id
+		) enrollment_stats ON enrollment_stats.tenant_id = tenants.id
+		LEFT JOIN (
+			SELECT tenant_id, COUNT(*) AS orders, SUM(final_price) AS revenue
+			FROM orders
+			WHERE payment_status = 'paid' AND paid_at BETWEEN @start AND @end
+			GROUP BY tenant_id
+		) order_stats ON order_stats.tenant_id = tenants.id
+		WHERE tenants.deleted_at IS NULL
+		ORDER BY revenue DESC, tenants.id ASC
+	`, map[string]interface{}{"start": startDate, "end": endDate}).Scan(&items).Error
+	return items, err
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"fmt"
+	"lms/src/models"
+	"lms/src/utils"
+
+	"gorm.io/gorm"
+)
+
+// tenantCondition trả về điều kiện giới hạn bảng theo tenant của kết nối.
+// Chỉ cần cho Raw SQL; query qua model đã được lọc tự động (xem db.ForTenant).
+func tenantCondition(db *gorm.DB, table string) string {
+	if tenantId, ok := utils.TenantFromContext(db.Statement.Context); ok {
+		return fmt.Sprintf("%s.tenant_id = %d", table, tenantId)
+	}
+	return "TRUE"
+}
+
+// tenantCourseIds là subquery id các course của tenant, dùng cho bảng không có tenant_id (enrollments, reviews...)
+func tenantCourseIds(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Model(&models.Course{}).Select("id")
+}
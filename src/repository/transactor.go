@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"lms/src/utils"
+
+	"gorm.io/gorm"
+)
+
+// TxRepositories là các repository dùng chung một transaction.
+// Ghi domain event vào Outbox cùng transaction để event chỉ tồn tại khi dữ liệu đã commit.
+type TxRepositories struct {
+	Users           UserRepository
+	Orders          OrderRepository
+	Enrollments     EnrollmentRepository
+	Progress        ProgressRepository
+	Quizzes         QuizRepository
+	Reviews         ReviewRepository
+	Outbox          OutboxRepository
+	Lti             LtiRepository
+	Organizations   OrganizationRepository
+	CourseRevisions CourseRevisionRepository
+	CourseTemplates CourseTemplateRepository
+	Subscriptions   SubscriptionRepository
+	Bundles         BundleRepository
+}
+
+type DBTransactor struct {
+	db *gorm.DB
+}
+
+func NewDBTransactor(db *gorm.DB) Transactor {
+	return &DBTransactor{
+		db: db,
+	}
+}
+
+// WithinTransaction chạy fn trong một transaction, rollback nếu fn trả về lỗi
+func (t *DBTransactor) WithinTransaction(fn func(repos *TxRepositories) error) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&TxRepositories{
+			Users:           NewDBUserRepository(tx),
+			Orders:          NewDBOrderRepository(tx),
+			Enrollments:     NewDBEnrollmentRepository(tx),
+			Progress:        NewDBProgressRepository(tx),
+			Quizzes:         NewDBQuizRepository(tx),
+			Reviews:         NewDBReviewRepository(tx),
+			Outbox:          NewDBOutboxRepository(tx),
+			Lti:             NewDBLtiRepository(tx),
+			Organizations:   NewDBOrganizationRepository(tx),
+			CourseRevisions: NewDBCourseRevisionRepository(tx),
+			CourseTemplates: NewDBCourseTemplateRepository(tx),
+			Subscriptions:   NewDBSubscriptionRepository(tx),
+			Bundles:         NewDBBundleRepository(tx),
+		})
+	})
+}
+
+// ForTenant trả về transactor chạy trong tenant (dùng cho background job chạy trên kết nối gốc),
+// dữ liệu và domain event tạo trong transaction được gán tenant này
+func (t *DBTransactor) ForTenant(tenantId uint) Transactor {
+	return &DBTransactor{
+		db: t.db.WithContext(utils.WithTenant(context.Background(), tenantId)),
+	}
+}
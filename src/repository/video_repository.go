@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DBVideoRepository struct {
+	db *gorm.DB
+}
+
+func NewDBVideoRepository(db *gorm.DB) VideoRepository {
+	return &DBVideoRepository{
+		db: db,
+	}
+}
+
+func (vr *DBVideoRepository) CreateUpload(upload *models.VideoUpload) error {
+	return vr.db.Create(upload).Error
+}
+
+func (vr *DBVideoRepository) FindUploadByUploadId(uploadId string) (*models.VideoUpload, error) {
+	var upload models.VideoUpload
+	if err := vr.db.Where("upload_id = ?", uploadId).First(&upload).Error; err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+func (vr *DBVideoRepository) FindUploadById(id uint) (*models.VideoUpload, error) {
+	var upload models.VideoUpload
+	if err := vr.db.Where("id = ?", id).First(&upload).Error; err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+func (vr *DBVideoRepository) UpdateUpload(id uint, updates map[string]interface{}) error {
+	return vr.db.Model(&models.VideoUpload{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// SaveChunk đánh dấu chunk đã lưu (upload lại cùng chunk không tạo bản ghi mới)
+func (vr *DBVideoRepository) SaveChunk(uploadId uint, index int) error {
+	return vr.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.VideoUploadChunk{UploadId: uploadId, ChunkIndex: index}).Error
+}
+
+// GetReceivedChunks liệt kê các chunk đã nhận, dùng để client resume upload
+func (vr *DBVideoRepository) GetReceivedChunks(uploadId uint) ([]int, error) {
+	chunks := make([]int, 0)
+	err := vr.db.Model(&models.VideoUploadChunk{}).
+		Where("upload_id = ?", uploadId).
+		Order("chunk_index ASC").
+		Pluck("chunk_index", &chunks).Error
+	return chunks, err
+}
+
+// ClaimQueuedUpload chuyển một upload "queued" (hoặc "processing" bị treo do worker chết) sang "processing".
+// FOR UPDATE SKIP LOCKED để nhiều instance không transcode trùng; trả về nil khi hàng đợi trống.
+func (vr *DBVideoRepository) ClaimQueuedUpload(now, staleBefore time.Time) (*models.VideoUpload, error) {
+	var uploads []models.VideoUpload
+	err := vr.db.Raw(`
+		UPDATE video_uploads SET status = 'processing', locked_at = ?
+		WHERE id IN (
+			SELECT id FROM video_uploads
+			WHERE status = 'queued' OR (status = 'processing' AND (locked_at IS NULL OR locked_at < ?))
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now, staleBefore).
+		Scan(&uploads).Error
+
+	if err != nil || len(uploads) == 0 {
+		return nil, err
+	}
+	return &uploads[0], nil
+}
+
+func (vr *DBVideoRepository) FindLessonById(lessonId uint) (*models.Lesson, error) {
+	var lesson models.Lesson
+	if err := vr.db.Where("id = ? AND deleted_at IS NULL", lessonId).First(&lesson).Error; err != nil {
+		return nil, err
+	}
+	return &lesson, nil
+}
+
+func (vr *DBVideoRepository) UpdateLessonVideo(lessonId uint, updates map[string]interface{}) error {
+	return vr.db.Model(&models.Lesson{}).
+		Where("id = ?", lessonId).
+		Updates(updates).Error
+}
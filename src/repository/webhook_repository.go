@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookChannel là kênh Postgres LISTEN/NOTIFY để đánh thức webhook dispatcher khi có delivery mới
+const WebhookChannel = "lms_webhooks"
+
+type DBWebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewDBWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &DBWebhookRepository{
+		db: db,
+	}
+}
+
+func (wr *DBWebhookRepository) CreateEndpoint(endpoint *models.WebhookEndpoint) error {
+	return wr.db.Create(endpoint).Error
+}
+
+func (wr *DBWebhookRepository) FindEndpointById(endpointId uint) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	if err := wr.db.Where("id = ?", endpointId).First(&endpoint).Error; err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+func (wr *DBWebhookRepository) GetEndpoints(offset, limit int, filters map[string]interface{}) ([]models.WebhookEndpoint, int, error) {
+	var endpoints []models.WebhookEndpoint
+	var total int64
+
+	query := wr.db.Model(&models.WebhookEndpoint{})
+
+	// Apply filters
+	for field, value := range filters {
+		switch field {
+		case "is_active":
+			query = query.Where("is_active = ?", value)
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("id DESC").
+		Offset(offset).Limit(limit).
+		Find(&endpoints).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return endpoints, int(total), nil
+}
+
+// GetActiveEndpoints lấy endpoint đang bật của tenant (subscriber chạy trên kết nối gốc nên lọc tenant tường minh)
+func (wr *DBWebhookRepository) GetActiveEndpoints(tenantId uint) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	if err := wr.db.Where("tenant_id = ? AND is_active = ?", tenantId, true).Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func (wr *DBWebhookRepository) UpdateEndpoint(endpointId uint, updates map[string]interface{}) error {
+	return wr.db.Model(&models.WebhookEndpoint{}).Where("id = ?", endpointId).Updates(updates).Error
+}
+
+// DeleteEndpoint xóa mềm endpoint và hủy các delivery chưa gửi
+func (wr *DBWebhookRepository) DeleteEndpoint(endpointId uint) error {
+	return wr.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.WebhookDelivery{}).
+			Where("endpoint_id = ? AND status IN ?", endpointId, []string{"pending", "processing"}).
+			Updates(map[string]interface{}{"status": "failed", "last_error": "endpoint deleted", "locked_at": nil}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&models.WebhookEndpoint{}, endpointId).Error
+	})
+}
+
+// CreateDeliveries bỏ qua delivery đã tạo cho cùng endpoint và event (khi outbox retry)
+func (wr *DBWebhookRepository) CreateDeliveries(deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return wr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error; err != nil {
+			return err
+		}
+		return tx.Exec("SELECT pg_notify(?, '')", WebhookChannel).Error
+	})
+}
+
+// ClaimDueDeliveries nhận các delivery đến hạn gửi (kể cả delivery "processing" bị treo).
+// FOR UPDATE SKIP LOCKED để nhiều instance không gửi trùng.
+func (wr *DBWebhookRepository) ClaimDueDeliveries(now, staleBefore time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := wr.db.Raw(`
+		UPDATE webhook_deliveries SET status = 'processing', locked_at = ?, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE (status = 'pending' AND next_attempt_at <= ?) OR (status = 'processing' AND locked_at < ?)
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now, now, staleBefore, limit).
+		Scan(&deliveries).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// FindDeliveryById: delivery không có tenant_id, giới hạn theo endpoint của tenant
+func (wr *DBWebhookRepository) FindDeliveryById(deliveryId uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := wr.db.Preload("Endpoint").
+		Where("id = ? AND endpoint_id IN (?)", deliveryId, wr.db.Unscoped().Model(&models.WebhookEndpoint{}).Select("id")).
+		First(&delivery).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (wr *DBWebhookRepository) GetEndpointDeliveries(endpointId uint, offset, limit int, filters map[string]interface{}) ([]models.WebhookDelivery, int, error) {
+	var deliveries []models.WebhookDelivery
+	var total int64
+
+	query := wr.db.Model(&models.WebhookDelivery{}).Where("endpoint_id = ?", endpointId)
+
+	// Apply filters
+	for field, value := range filters {
+		switch field {
+		case "status":
+			query = query.Where("status = ?", value)
+		case "event_type":
+			query = query.Where("event_type = ?", value)
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("id DESC").
+		Offset(offset).Limit(limit).
+		Find(&deliveries).Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, int(total), nil
+}
+
+func (wr *DBWebhookRepository) UpdateDelivery(deliveryId uint, updates map[string]interface{}) error {
+	return wr.db.Model(&models.WebhookDelivery{}).Where("id = ?", deliveryId).Updates(updates).Error
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"lms/src/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// XapiVoidedVerb là verb của voiding statement theo spec xAPI
+const XapiVoidedVerb = "http://adlnet.gov/expapi/verbs/voided"
+
+type DBXapiRepository struct {
+	db *gorm.DB
+}
+
+func NewDBXapiRepository(db *gorm.DB) XapiRepository {
+	return &DBXapiRepository{
+		db: db,
+	}
+}
+
+func (xr *DBXapiRepository) FindByStatementIds(statementIds []string) ([]models.XapiStatement, error) {
+	var statements []models.XapiStatement
+	if len(statementIds) == 0 {
+		return statements, nil
+	}
+	err := xr.db.Where("statement_id IN ?", statementIds).Find(&statements).Error
+	return statements, err
+}
+
+// CreateStatements lưu statement và cập nhật trạng thái voided.
+// Statement bị void khi có voiding statement trỏ tới nó (kể cả khi voiding statement đến trước),
+// trừ khi chính nó là voiding statement. Voiding statement chỉ có hiệu lực trong cùng tenant.
+func (xr *DBXapiRepository) CreateStatements(statements []models.XapiStatement) error {
+	if len(statements) == 0 {
+		return nil
+	}
+
+	return xr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&statements).Error; err != nil {
+			return err
+		}
+
+		affected := make([]string, 0, len(statements))
+		for _, statement := range statements {
+			affected = append(affected, statement.StatementId)
+			if statement.VoidedStatement != "" {
+				affected = append(affected, statement.VoidedStatement)
+			}
+		}
+
+		return tx.Exec(`
+			UPDATE xapi_statements s SET voided = true
+			WHERE s.statement_id IN ? AND s.verb_id <> ? AND s.voided = false AND s.tenant_id = ?
+			AND EXISTS (SELECT 1 FROM xapi_statements v WHERE v.voided_statement = s.statement_id AND v.tenant_id = s.tenant_id)
+		`, affected, XapiVoidedVerb, statements[0].TenantId).Error
+	})
+}
+
+// FindStatement tìm statement theo id; voided chọn giữa statement còn hiệu lực và statement đã bị void
+func (xr *DBXapiRepository) FindStatement(statementId string, voided bool) (*models.XapiStatement, error) {
+	var statement models.XapiStatement
+	err := xr.db.Where("statement_id = ? AND voided = ?", statementId, voided).First(&statement).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &statement, nil
+}
+
+// QueryStatements trả về statement chưa bị void theo filter, phân trang bằng cursor (id của bản ghi cuối trang trước)
+func (xr *DBXapiRepository) QueryStatements(filters map[string]interface{}, cursor uint, ascending bool, limit int) ([]models.XapiStatement, error) {
+	query := xr.db.Model(&models.XapiStatement{}).Where("voided = ?", false)
+
+	if agent, ok := filters["agent"].(string); ok && agent != "" {
+		query = query.Where("actor_key = ? OR object_agent_key = ?", agent, agent)
+	}
+	if verbId, ok := filters["verb_id"].(string); ok && verbId != "" {
+		query = query.Where("verb_id = ?", verbId)
+	}
+	if activityId, ok := filters["activity_id"].(string); ok && activityId != "" {
+		query = query.Where("activity_id = ?", activityId)
+	}
+	if registration, ok := filters["registration"].(string); ok && registration != "" {
+		query = query.Where("registration = ?", registration)
+	}
+	if since, ok := filters["since"].(time.Time); ok {
+		query = query.Where("stored > ?", since)
+	}
+	if until, ok := filters["until"].(time.Time); ok {
+		query = query.Where("stored <= ?", until)
+	}
+
+	// id tăng cùng thời điểm lưu nên dùng làm thứ tự của "stored"
+	order := "id DESC"
+	if ascending {
+		order = "id ASC"
+		if cursor > 0 {
+			query = query.Where("id > ?", cursor)
+		}
+	} else if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+
+	var statements []models.XapiStatement
+	err := query.Order(order).Limit(limit).Find(&statements).Error
+	return statements, err
+}
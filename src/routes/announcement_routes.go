@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnnouncementRoutes struct {
+	handler *handler.AnnouncementHandler
+}
+
+func NewAnnouncementRoutes(handler *handler.AnnouncementHandler) *AnnouncementRoutes {
+	return &AnnouncementRoutes{
+		handler: handler,
+	}
+}
+
+func (ar *AnnouncementRoutes) Register(r *gin.RouterGroup) {
+	announcements := r.Group("/announcements")
+	{
+		// Link trong email - xác thực bằng token của receipt
+		announcements.GET("/email/:token/open", ar.handler.TrackEmailOpen)
+		announcements.GET("/email/:token/unsubscribe", ar.handler.UnsubscribeByToken)
+
+		// Student routes - cần authentication
+		student := announcements.Group("")
+		student.Use(middleware.AuthMiddleware())
+		{
+			student.GET("", ar.handler.GetFeed)
+			student.GET("/:announcement_id", ar.handler.GetAnnouncement)
+		}
+	}
+
+	courses := r.Group("/courses")
+	{
+		courses.Use(middleware.AuthMiddleware())
+		{
+			courses.PUT("/course_id/:course_id/announcements/subscription", ar.handler.UpdateSubscription)
+		}
+	}
+
+	// Instructor routes - quản lý announcement của course
+	instructor := r.Group("/instructor/courses/:course_id/announcements")
+	{
+		instructor.Use(middleware.AuthMiddleware())
+		instructor.Use(middleware.InstructorMiddleware())
+		{
+			instructor.GET("", ar.handler.GetCourseAnnouncements)
+			instructor.POST("", ar.handler.CreateAnnouncement)
+			instructor.PUT("/:announcement_id", ar.handler.UpdateAnnouncement)
+			instructor.DELETE("/:announcement_id", ar.handler.DeleteAnnouncement)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttachmentRoutes struct {
+	handler *handler.AttachmentHandler
+}
+
+func NewAttachmentRoutes(handler *handler.AttachmentHandler) *AttachmentRoutes {
+	return &AttachmentRoutes{
+		handler: handler,
+	}
+}
+
+func (ar *AttachmentRoutes) Register(r *gin.RouterGroup) {
+	// Download - lesson preview không cần login
+	lessons := r.Group("/lessons")
+	{
+		lessons.Use(middleware.OptionalAuthMiddleware())
+		{
+			lessons.GET("/:lesson_id/attachments/:attachment_id/download", ar.handler.DownloadAttachment)
+		}
+	}
+
+	// Instructor routes - quản lý tài liệu đính kèm
+	instructorLessons := r.Group("/instructor/courses/:course_id/lessons/:id")
+	{
+		instructorLessons.Use(middleware.AuthMiddleware())
+		instructorLessons.Use(middleware.InstructorMiddleware())
+		{
+			instructorLessons.POST("/attachments", ar.handler.UploadAttachment)
+			instructorLessons.DELETE("/attachments/:attachment_id", ar.handler.DeleteAttachment)
+		}
+	}
+}
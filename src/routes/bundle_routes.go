@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BundleRoutes struct {
+	handler *handler.BundleHandler
+}
+
+func NewBundleRoutes(handler *handler.BundleHandler) *BundleRoutes {
+	return &BundleRoutes{
+		handler: handler,
+	}
+}
+
+func (br *BundleRoutes) Register(r *gin.RouterGroup) {
+	bundles := r.Group("/bundles")
+	{
+		// Public routes
+		bundles.GET("", br.handler.GetBundles)
+		bundles.GET("/:slug", br.handler.GetBundleBySlug)
+
+		// Student routes - cần authentication
+		student := bundles.Group("")
+		student.Use(middleware.AuthMiddleware())
+		{
+			student.GET("/:slug/quote", br.handler.GetQuote)
+			student.POST("/:slug/purchase", br.handler.PurchaseBundle)
+		}
+	}
+
+	purchases := r.Group("/bundle-purchases")
+	{
+		purchases.Use(middleware.AuthMiddleware())
+		{
+			purchases.GET("", br.handler.GetMyPurchases)
+		}
+	}
+
+	// Management routes - admin (marketing)
+	admin := r.Group("/admin/bundles")
+	{
+		admin.Use(middleware.AuthMiddleware())
+		admin.Use(middleware.AdminMiddleware())
+		{
+			admin.GET("", br.handler.AdminGetBundles)
+			admin.POST("", br.handler.CreateBundle)
+			admin.PUT("/:bundle_id", br.handler.UpdateBundle)
+			admin.DELETE("/:bundle_id", br.handler.DeleteBundle)
+			admin.PUT("/:bundle_id/courses", br.handler.SetBundleCourses)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CourseApprovalRoutes struct {
+	handler *handler.CourseApprovalHandler
+}
+
+func NewCourseApprovalRoutes(handler *handler.CourseApprovalHandler) *CourseApprovalRoutes {
+	return &CourseApprovalRoutes{
+		handler: handler,
+	}
+}
+
+func (cr *CourseApprovalRoutes) Register(r *gin.RouterGroup) {
+	// Instructor gửi / rút yêu cầu duyệt course
+	instructorCourses := r.Group("/instructor/courses")
+	{
+		instructorCourses.Use(middleware.AuthMiddleware())
+		instructorCourses.Use(middleware.InstructorMiddleware())
+		{
+			instructorCourses.GET("/:course_id/review", cr.handler.GetReviewStatus)
+			instructorCourses.POST("/:course_id/review", cr.handler.SubmitForReview)
+			instructorCourses.DELETE("/:course_id/review", cr.handler.WithdrawSubmission)
+			instructorCourses.GET("/:course_id/status-history", cr.handler.GetInstructorStatusHistory)
+		}
+	}
+
+	// Admin duyệt course
+	courseReviews := r.Group("/admin/course-reviews")
+	{
+		courseReviews.Use(middleware.AuthMiddleware())
+		courseReviews.Use(middleware.AdminMiddleware())
+		{
+			courseReviews.GET("", cr.handler.GetReviewQueue)
+			courseReviews.POST("/:course_id/approve", cr.handler.ApproveCourse)
+			courseReviews.POST("/:course_id/reject", cr.handler.RejectCourse)
+			courseReviews.GET("/:course_id/history", cr.handler.GetStatusHistory)
+		}
+	}
+}
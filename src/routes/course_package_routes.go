@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CoursePackageRoutes struct {
+	handler *handler.CoursePackageHandler
+}
+
+func NewCoursePackageRoutes(handler *handler.CoursePackageHandler) *CoursePackageRoutes {
+	return &CoursePackageRoutes{
+		handler: handler,
+	}
+}
+
+func (pr *CoursePackageRoutes) Register(r *gin.RouterGroup) {
+	// Instructor export / import course dạng gói zip
+	instructor := r.Group("/instructor")
+	{
+		instructor.Use(middleware.AuthMiddleware())
+		instructor.Use(middleware.InstructorMiddleware())
+		{
+			instructor.POST("/courses/import", pr.handler.ImportCourse)
+			instructor.GET("/courses/:course_id/export", pr.handler.ExportCourse)
+		}
+	}
+
+	// Admin export bất kỳ course nào
+	admin := r.Group("/admin")
+	{
+		admin.Use(middleware.AuthMiddleware())
+		admin.Use(middleware.AdminMiddleware())
+		{
+			admin.GET("/courses/:course_id/export", pr.handler.AdminExportCourse)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CourseRevisionRoutes struct {
+	handler *handler.CourseRevisionHandler
+}
+
+func NewCourseRevisionRoutes(handler *handler.CourseRevisionHandler) *CourseRevisionRoutes {
+	return &CourseRevisionRoutes{
+		handler: handler,
+	}
+}
+
+func (cr *CourseRevisionRoutes) Register(r *gin.RouterGroup) {
+	// Instructor sửa course đã publish qua draft revision
+	instructorRevisions := r.Group("/instructor/courses/:course_id/revisions")
+	{
+		instructorRevisions.Use(middleware.AuthMiddleware())
+		instructorRevisions.Use(middleware.InstructorMiddleware())
+		{
+			instructorRevisions.GET("", cr.handler.GetRevisions)
+			instructorRevisions.POST("/draft", cr.handler.CreateDraft)
+			instructorRevisions.GET("/draft", cr.handler.GetDraft)
+			instructorRevisions.PUT("/draft", cr.handler.UpdateDraft)
+			instructorRevisions.DELETE("/draft", cr.handler.DiscardDraft)
+			instructorRevisions.POST("/draft/thumbnail", cr.handler.UploadDraftThumbnail)
+			instructorRevisions.GET("/draft/preview", cr.handler.PreviewDraft)
+			instructorRevisions.POST("/draft/lessons", cr.handler.AddDraftLesson)
+			instructorRevisions.PUT("/draft/lessons/order", cr.handler.ReorderDraftLessons)
+			instructorRevisions.PUT("/draft/lessons/:lesson_key", cr.handler.UpdateDraftLesson)
+			instructorRevisions.DELETE("/draft/lessons/:lesson_key", cr.handler.RemoveDraftLesson)
+			instructorRevisions.POST("/draft/publish", cr.handler.PublishDraft)
+		}
+	}
+
+	// Admin xem, so sánh và rollback revision
+	adminRevisions := r.Group("/admin/courses/:course_id/revisions")
+	{
+		adminRevisions.Use(middleware.AuthMiddleware())
+		adminRevisions.Use(middleware.AdminMiddleware())
+		{
+			adminRevisions.GET("", cr.handler.GetCourseRevisions)
+			adminRevisions.GET("/diff", cr.handler.DiffRevisions)
+			adminRevisions.GET("/:revision_id", cr.handler.GetRevision)
+			adminRevisions.POST("/:revision_id/rollback", cr.handler.RollbackRevision)
+		}
+	}
+}
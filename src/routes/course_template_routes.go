@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CourseTemplateRoutes struct {
+	handler *handler.CourseTemplateHandler
+}
+
+func NewCourseTemplateRoutes(handler *handler.CourseTemplateHandler) *CourseTemplateRoutes {
+	return &CourseTemplateRoutes{
+		handler: handler,
+	}
+}
+
+func (tr *CourseTemplateRoutes) Register(r *gin.RouterGroup) {
+	// Instructor duplicate course và tạo course từ template
+	instructor := r.Group("/instructor")
+	{
+		instructor.Use(middleware.AuthMiddleware())
+		instructor.Use(middleware.InstructorMiddleware())
+		{
+			instructor.POST("/courses/:course_id/duplicate", tr.handler.DuplicateCourse)
+			instructor.GET("/course-templates", tr.handler.GetTemplates)
+		}
+	}
+
+	// Admin quản lý template
+	admin := r.Group("/admin")
+	{
+		admin.Use(middleware.AuthMiddleware())
+		admin.Use(middleware.AdminMiddleware())
+		{
+			admin.PUT("/courses/:course_id/template", tr.handler.SetTemplate)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DiscussionRoutes struct {
+	handler *handler.DiscussionHandler
+}
+
+func NewDiscussionRoutes(handler *handler.DiscussionHandler) *DiscussionRoutes {
+	return &DiscussionRoutes{
+		handler: handler,
+	}
+}
+
+func (dr *DiscussionRoutes) Register(r *gin.RouterGroup) {
+	// Q&A của course - cần enroll (hoặc là instructor/TA)
+	courses := r.Group("/courses")
+	{
+		courses.Use(middleware.AuthMiddleware())
+		{
+			courses.GET("/course_id/:course_id/discussions", dr.handler.GetThreads)
+			courses.POST("/course_id/:course_id/discussions", dr.handler.CreateThread)
+		}
+	}
+
+	discussions := r.Group("/discussions")
+	{
+		discussions.Use(middleware.AuthMiddleware())
+		{
+			discussions.GET("/threads/:thread_id", dr.handler.GetThreadDetail)
+			discussions.DELETE("/threads/:thread_id", dr.handler.DeleteThread)
+			discussions.POST("/threads/:thread_id/replies", dr.handler.CreateReply)
+			discussions.POST("/threads/:thread_id/upvote", dr.handler.UpvoteThread)
+			discussions.DELETE("/replies/:reply_id", dr.handler.DeleteReply)
+			discussions.POST("/replies/:reply_id/upvote", dr.handler.UpvoteReply)
+
+			// Moderation - instructor/TA (kiểm tra trong service)
+			discussions.POST("/replies/:reply_id/answer", dr.handler.MarkAnswer)
+			discussions.DELETE("/replies/:reply_id/answer", dr.handler.UnmarkAnswer)
+			discussions.PUT("/threads/:thread_id/pin", dr.handler.PinThread)
+			discussions.PUT("/threads/:thread_id/visibility", dr.handler.SetThreadVisibility)
+		}
+	}
+
+	// Instructor routes - hộp thư câu hỏi chưa trả lời và quản lý TA
+	instructor := r.Group("/instructor")
+	{
+		instructor.Use(middleware.AuthMiddleware())
+		{
+			// TA không cần role instructor
+			instructor.GET("/discussions/unanswered", dr.handler.GetUnansweredThreads)
+		}
+
+		assistants := instructor.Group("/courses/:course_id/assistants")
+		assistants.Use(middleware.InstructorMiddleware())
+		{
+			assistants.GET("", dr.handler.GetCourseAssistants)
+			assistants.POST("", dr.handler.AddCourseAssistant)
+			assistants.DELETE("/:user_id", dr.handler.RemoveCourseAssistant)
+		}
+	}
+}
@@ -29,6 +29,9 @@ func (er *EnrollmentRoutes) Register(r *gin.RouterGroup) {
 
 			// Kiểm tra enrollment status
 			courses.GET("/course_id/:course_id/check-enrollment", er.handler.CheckEnrollment)
+
+			// Kiểm tra prerequisites của course
+			courses.GET("/course_id/:course_id/check-prerequisites", er.handler.CheckPrerequisites)
 		}
 	}
 
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventRoutes struct {
+	handler *handler.EventHandler
+}
+
+func NewEventRoutes(handler *handler.EventHandler) *EventRoutes {
+	return &EventRoutes{
+		handler: handler,
+	}
+}
+
+func (er *EventRoutes) Register(r *gin.RouterGroup) {
+	events := r.Group("/admin/events")
+	{
+		events.Use(middleware.AuthMiddleware())
+		events.Use(middleware.AdminMiddleware())
+		{
+			events.GET("", er.handler.GetEvents)
+			events.POST("/:event_id/retry", er.handler.RetryEvent)
+		}
+	}
+}
@@ -31,7 +31,9 @@ func (ir *InstructorRoutes) Register(r *gin.RouterGroup) {
 			instructor.POST("/courses", ir.handler.CreateCourse)
 			instructor.PUT("/courses/:course_id", ir.handler.UpdateCourse)
 			instructor.DELETE("/courses/:course_id", ir.handler.DeleteCourse)
+			instructor.POST("/courses/:course_id/thumbnail", ir.handler.UploadCourseThumbnail)
 			instructor.GET("/courses/:course_id/students", ir.handler.GetCourseStudents)
+			instructor.PUT("/courses/:course_id/prerequisites", ir.handler.SetCoursePrerequisites)
 
 			// Lesson management
 			instructor.POST("/courses/:course_id/lessons", ir.handler.CreateLesson)
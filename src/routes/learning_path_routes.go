@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LearningPathRoutes struct {
+	handler *handler.LearningPathHandler
+}
+
+func NewLearningPathRoutes(handler *handler.LearningPathHandler) *LearningPathRoutes {
+	return &LearningPathRoutes{
+		handler: handler,
+	}
+}
+
+func (lpr *LearningPathRoutes) Register(r *gin.RouterGroup) {
+	paths := r.Group("/learning-paths")
+	{
+		// Public routes
+		paths.GET("", lpr.handler.GetLearningPaths)
+		paths.GET("/:slug", lpr.handler.GetLearningPathBySlug)
+
+		// Student routes - cần authentication
+		student := paths.Group("")
+		student.Use(middleware.AuthMiddleware())
+		{
+			student.GET("/:slug/progress", lpr.handler.GetLearningPathProgress)
+			student.POST("/:slug/certificate", lpr.handler.IssueCertificate)
+		}
+
+	}
+
+	// Management routes - instructor hoặc admin
+	manage := r.Group("/instructor/learning-paths")
+	{
+		manage.Use(middleware.AuthMiddleware())
+		manage.Use(middleware.InstructorMiddleware())
+		{
+			manage.POST("", lpr.handler.CreateLearningPath)
+			manage.PUT("/:id", lpr.handler.UpdateLearningPath)
+			manage.DELETE("/:id", lpr.handler.DeleteLearningPath)
+			manage.PUT("/:id/courses", lpr.handler.SetLearningPathCourses)
+		}
+	}
+}
@@ -27,7 +27,7 @@ func (lr *LessonRoutes) Register(r *gin.RouterGroup) {
 			courses.GET("/course_id/:course_id/lessons", lr.handler.GetCourseLessons)
 
 			// Lấy lesson detail
-			courses.GET("/course_id/:course_id/lessons/:slug", lr.handler.GetCourseLessons)
+			courses.GET("/course_id/:course_id/lessons/:slug", lr.handler.GetLessonDetail)
 		}
 	}
 }
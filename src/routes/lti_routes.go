@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LtiRoutes struct {
+	handler *handler.LtiHandler
+}
+
+func NewLtiRoutes(handler *handler.LtiHandler) *LtiRoutes {
+	return &LtiRoutes{
+		handler: handler,
+	}
+}
+
+func (lr *LtiRoutes) Register(r *gin.RouterGroup) {
+	// Endpoint cho platform (LMS bên ngoài) - xác thực bằng state/JWT thay vì access token
+	lti := r.Group("/lti")
+	{
+		lti.GET("/jwks", lr.handler.GetJWKS)
+		lti.GET("/config", lr.handler.GetToolConfig)
+		lti.GET("/login", lr.handler.Login)
+		lti.POST("/login", lr.handler.Login)
+		lti.POST("/launch", lr.handler.Launch)
+
+		// Deep linking - token của phiên là credential
+		lti.GET("/deep-linking/:token", lr.handler.GetDeepLinkSession)
+		lti.POST("/deep-linking/:token", lr.handler.CreateDeepLink)
+	}
+
+	// Admin đăng ký platform
+	platforms := r.Group("/admin/lti/platforms")
+	{
+		platforms.Use(middleware.AuthMiddleware())
+		platforms.Use(middleware.AdminMiddleware())
+		{
+			platforms.GET("", lr.handler.GetPlatforms)
+			platforms.POST("", lr.handler.CreatePlatform)
+			platforms.GET("/:platform_id", lr.handler.GetPlatform)
+			platforms.PUT("/:platform_id", lr.handler.UpdatePlatform)
+			platforms.DELETE("/:platform_id", lr.handler.DeletePlatform)
+		}
+	}
+}
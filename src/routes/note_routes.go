@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NoteRoutes struct {
+	handler *handler.NoteHandler
+}
+
+func NewNoteRoutes(handler *handler.NoteHandler) *NoteRoutes {
+	return &NoteRoutes{
+		handler: handler,
+	}
+}
+
+func (nr *NoteRoutes) Register(r *gin.RouterGroup) {
+	// Ghi chú là riêng tư - tất cả routes cần authentication
+	lessons := r.Group("/lessons")
+	{
+		lessons.Use(middleware.AuthMiddleware())
+		{
+			lessons.GET("/:lesson_id/notes", nr.handler.GetLessonNotes)
+			lessons.POST("/:lesson_id/notes", nr.handler.CreateNote)
+		}
+	}
+
+	notes := r.Group("/notes")
+	{
+		notes.Use(middleware.AuthMiddleware())
+		{
+			notes.GET("/search", nr.handler.SearchNotes)
+			notes.PUT("/:note_id", nr.handler.UpdateNote)
+			notes.DELETE("/:note_id", nr.handler.DeleteNote)
+		}
+	}
+
+	courses := r.Group("/courses")
+	{
+		courses.Use(middleware.AuthMiddleware())
+		{
+			courses.GET("/course_id/:course_id/notes/export", nr.handler.ExportCourseNotes)
+		}
+	}
+}
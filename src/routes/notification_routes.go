@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationRoutes struct {
+	handler *handler.NotificationHandler
+}
+
+func NewNotificationRoutes(handler *handler.NotificationHandler) *NotificationRoutes {
+	return &NotificationRoutes{
+		handler: handler,
+	}
+}
+
+func (nr *NotificationRoutes) Register(r *gin.RouterGroup) {
+	notifications := r.Group("/notifications")
+	{
+		// Stream SSE - cho phép truyền token qua query vì EventSource không gửi được header
+		notifications.GET("/stream", middleware.QueryTokenMiddleware(), middleware.AuthMiddleware(), nr.handler.Stream)
+
+		authenticated := notifications.Group("")
+		authenticated.Use(middleware.AuthMiddleware())
+		{
+			authenticated.GET("", nr.handler.GetNotifications)
+			authenticated.PUT("/read", nr.handler.MarkRead)
+			authenticated.DELETE("/:notification_id", nr.handler.DeleteNotification)
+			authenticated.GET("/preferences", nr.handler.GetPreferences)
+			authenticated.PUT("/preferences", nr.handler.UpdatePreferences)
+		}
+	}
+}
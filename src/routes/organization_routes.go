@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationRoutes struct {
+	handler *handler.OrganizationHandler
+}
+
+func NewOrganizationRoutes(handler *handler.OrganizationHandler) *OrganizationRoutes {
+	return &OrganizationRoutes{
+		handler: handler,
+	}
+}
+
+func (or *OrganizationRoutes) Register(r *gin.RouterGroup) {
+	organizations := r.Group("/organizations")
+	{
+		organizations.Use(middleware.AuthMiddleware())
+		{
+			organizations.GET("", or.handler.GetMyOrganizations)
+			organizations.POST("", or.handler.CreateOrganization)
+			organizations.GET("/:org_id", or.handler.GetOrganization)
+			organizations.PUT("/:org_id", or.handler.UpdateOrganization)
+
+			// Thành viên
+			organizations.GET("/:org_id/members", or.handler.GetMembers)
+			organizations.POST("/:org_id/members", or.handler.AddMember)
+			organizations.PUT("/:org_id/members/:user_id", or.handler.UpdateMember)
+			organizations.DELETE("/:org_id/members/:user_id", or.handler.RemoveMember)
+
+			// License và seat
+			organizations.GET("/:org_id/licenses", or.handler.GetLicenses)
+			organizations.POST("/:org_id/licenses", or.handler.PurchaseSeats)
+			organizations.POST("/:org_id/licenses/:license_id/cancel", or.handler.CancelLicense)
+			organizations.GET("/:org_id/licenses/:license_id/seats", or.handler.GetSeatAssignments)
+			organizations.POST("/:org_id/licenses/:license_id/seats", or.handler.AssignSeat)
+			organizations.DELETE("/:org_id/licenses/:license_id/seats/:user_id", or.handler.ReclaimSeat)
+
+			// Hóa đơn
+			organizations.GET("/:org_id/invoices", or.handler.GetInvoices)
+			organizations.GET("/:org_id/invoices/:invoice_id", or.handler.GetInvoice)
+			organizations.POST("/:org_id/invoices/:invoice_id/pay", or.handler.PayInvoice)
+
+			// Dashboard tiến độ
+			organizations.GET("/:org_id/progress", or.handler.GetTeamProgress)
+		}
+	}
+
+	admin := r.Group("/admin/organizations")
+	{
+		admin.Use(middleware.AuthMiddleware())
+		admin.Use(middleware.AdminMiddleware())
+		{
+			admin.GET("", or.handler.AdminGetOrganizations)
+			admin.PUT("/:org_id/status", or.handler.AdminUpdateOrganizationStatus)
+			admin.GET("/:org_id/invoices", or.handler.AdminGetInvoices)
+			admin.POST("/:org_id/invoices/:invoice_id/mark-paid", or.handler.AdminMarkInvoicePaid)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QuizRoutes struct {
+	handler *handler.QuizHandler
+}
+
+func NewQuizRoutes(handler *handler.QuizHandler) *QuizRoutes {
+	return &QuizRoutes{
+		handler: handler,
+	}
+}
+
+func (qr *QuizRoutes) Register(r *gin.RouterGroup) {
+	// Student routes - cần authentication
+	lessons := r.Group("/lessons")
+	{
+		lessons.Use(middleware.AuthMiddleware())
+		{
+			// Lấy quiz của lesson
+			lessons.GET("/:lesson_id/quiz", qr.handler.GetLessonQuiz)
+		}
+	}
+
+	quizzes := r.Group("/quizzes")
+	{
+		quizzes.Use(middleware.AuthMiddleware())
+		{
+			// Nộp bài quiz
+			quizzes.POST("/:quiz_id/submit", qr.handler.SubmitQuiz)
+		}
+	}
+
+	// Instructor routes
+	instructorLessons := r.Group("/instructor/courses/:course_id/lessons/:id")
+	{
+		instructorLessons.Use(middleware.AuthMiddleware())
+		instructorLessons.Use(middleware.InstructorMiddleware())
+		{
+			instructorLessons.POST("/quiz", qr.handler.CreateQuiz)
+			instructorLessons.DELETE("/quiz", qr.handler.DeleteQuiz)
+		}
+	}
+}
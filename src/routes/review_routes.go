@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewRoutes struct {
+	handler *handler.ReviewHandler
+}
+
+func NewReviewRoutes(handler *handler.ReviewHandler) *ReviewRoutes {
+	return &ReviewRoutes{
+		handler: handler,
+	}
+}
+
+func (rr *ReviewRoutes) Register(r *gin.RouterGroup) {
+	// Student report / vote review
+	reviews := r.Group("/reviews")
+	{
+		reviews.Use(middleware.AuthMiddleware())
+		{
+			reviews.POST("/:review_id/report", rr.handler.ReportReview)
+			reviews.PUT("/:review_id/vote", rr.handler.VoteReview)
+			reviews.DELETE("/:review_id/vote", rr.handler.RemoveVote)
+		}
+	}
+
+	// Instructor reply
+	instructorReviews := r.Group("/instructor/reviews")
+	{
+		instructorReviews.Use(middleware.AuthMiddleware())
+		instructorReviews.Use(middleware.InstructorMiddleware())
+		{
+			instructorReviews.PUT("/:review_id/reply", rr.handler.UpsertReply)
+			instructorReviews.DELETE("/:review_id/reply", rr.handler.DeleteReply)
+		}
+	}
+
+	// Admin moderation
+	adminReviews := r.Group("/admin/reviews")
+	{
+		adminReviews.Use(middleware.AuthMiddleware())
+		adminReviews.Use(middleware.AdminMiddleware())
+		{
+			adminReviews.GET("/moderation", rr.handler.GetModerationQueue)
+			adminReviews.PUT("/:review_id/moderation", rr.handler.ModerateReview)
+			adminReviews.GET("/:review_id/reports", rr.handler.GetReviewReports)
+		}
+	}
+}
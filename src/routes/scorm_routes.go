@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScormRoutes struct {
+	handler *handler.ScormHandler
+}
+
+func NewScormRoutes(handler *handler.ScormHandler) *ScormRoutes {
+	return &ScormRoutes{
+		handler: handler,
+	}
+}
+
+func (sr *ScormRoutes) Register(r *gin.RouterGroup) {
+	// Nội dung package - xác thực bằng signed token trong URL
+	r.GET("/scorm/content/:token/*filepath", sr.handler.Content)
+
+	// Student routes - SCORM runtime
+	lessons := r.Group("/lessons")
+	{
+		lessons.Use(middleware.AuthMiddleware())
+		{
+			lessons.GET("/:lesson_id/scorm/launch", sr.handler.Launch)
+			lessons.POST("/:lesson_id/scorm/commit", sr.handler.Commit)
+		}
+	}
+
+	// Instructor routes - quản lý package SCORM của lesson
+	instructorLessons := r.Group("/instructor/courses/:course_id/lessons/:id")
+	{
+		instructorLessons.Use(middleware.AuthMiddleware())
+		instructorLessons.Use(middleware.InstructorMiddleware())
+		{
+			instructorLessons.POST("/scorm", sr.handler.UploadPackage)
+			instructorLessons.GET("/scorm", sr.handler.GetPackage)
+			instructorLessons.DELETE("/scorm", sr.handler.DeletePackage)
+			instructorLessons.GET("/scorm/attempts", sr.handler.GetAttempts)
+		}
+	}
+}
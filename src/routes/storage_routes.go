@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StorageRoutes struct {
+	handler *handler.StorageHandler
+}
+
+func NewStorageRoutes(handler *handler.StorageHandler) *StorageRoutes {
+	return &StorageRoutes{
+		handler: handler,
+	}
+}
+
+func (sr *StorageRoutes) Register(r *gin.RouterGroup) {
+	storage := r.Group("/storage")
+	{
+		// Presigned URL cho local driver - xác thực bằng chữ ký trong query string
+		storage.GET("/local/*key", sr.handler.GetLocalObject)
+		storage.PUT("/local/*key", sr.handler.PutLocalObject)
+
+		// Protected routes - cần authentication
+		protected := storage.Group("")
+		protected.Use(middleware.AuthMiddleware())
+		{
+			protected.POST("/presign", sr.handler.PresignUpload)
+		}
+	}
+}
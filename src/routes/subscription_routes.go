@@ -0,0 +1,55 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubscriptionRoutes struct {
+	handler *handler.SubscriptionHandler
+}
+
+func NewSubscriptionRoutes(handler *handler.SubscriptionHandler) *SubscriptionRoutes {
+	return &SubscriptionRoutes{
+		handler: handler,
+	}
+}
+
+func (sr *SubscriptionRoutes) Register(r *gin.RouterGroup) {
+	plans := r.Group("/subscription-plans")
+	{
+		plans.GET("", sr.handler.GetPlans)
+		plans.GET("/:plan_id", sr.handler.GetPlan)
+	}
+
+	subscriptions := r.Group("/subscriptions")
+	{
+		subscriptions.Use(middleware.AuthMiddleware())
+		{
+			subscriptions.POST("", sr.handler.Subscribe)
+			subscriptions.GET("/me", sr.handler.GetMySubscription)
+			subscriptions.POST("/me/cancel", sr.handler.CancelSubscription)
+			subscriptions.POST("/me/resume", sr.handler.ResumeSubscription)
+			subscriptions.POST("/me/pay", sr.handler.RetryPayment)
+			subscriptions.GET("/me/payments", sr.handler.GetMyPayments)
+		}
+	}
+
+	admin := r.Group("/admin")
+	{
+		admin.Use(middleware.AuthMiddleware())
+		admin.Use(middleware.AdminMiddleware())
+		{
+			admin.GET("/subscription-plans", sr.handler.AdminGetPlans)
+			admin.POST("/subscription-plans", sr.handler.CreatePlan)
+			admin.PUT("/subscription-plans/:plan_id", sr.handler.UpdatePlan)
+			admin.GET("/subscriptions", sr.handler.AdminGetSubscriptions)
+
+			// Tag của course quyết định course thuộc plan tagged nào
+			admin.GET("/courses/:course_id/tags", sr.handler.GetCourseTags)
+			admin.PUT("/courses/:course_id/tags", sr.handler.SetCourseTags)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubtitleRoutes struct {
+	handler *handler.SubtitleHandler
+}
+
+func NewSubtitleRoutes(handler *handler.SubtitleHandler) *SubtitleRoutes {
+	return &SubtitleRoutes{
+		handler: handler,
+	}
+}
+
+func (sr *SubtitleRoutes) Register(r *gin.RouterGroup) {
+	// Instructor routes - quản lý phụ đề
+	instructorLessons := r.Group("/instructor/courses/:course_id/lessons/:id")
+	{
+		instructorLessons.Use(middleware.AuthMiddleware())
+		instructorLessons.Use(middleware.InstructorMiddleware())
+		{
+			instructorLessons.POST("/subtitles", sr.handler.UploadSubtitle)
+			instructorLessons.DELETE("/subtitles/:subtitle_id", sr.handler.DeleteSubtitle)
+		}
+	}
+}
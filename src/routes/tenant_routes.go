@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TenantRoutes: branding và cấu hình của tenant đang truy cập (đăng ký ở mọi tenant)
+type TenantRoutes struct {
+	handler *handler.TenantHandler
+}
+
+func NewTenantRoutes(handler *handler.TenantHandler) *TenantRoutes {
+	return &TenantRoutes{
+		handler: handler,
+	}
+}
+
+func (tr *TenantRoutes) Register(r *gin.RouterGroup) {
+	// Public: frontend white-label lấy branding khi khởi động
+	r.GET("/tenant", tr.handler.GetBranding)
+
+	settings := r.Group("/admin/tenant")
+	{
+		settings.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+		{
+			settings.GET("/settings", tr.handler.GetSettings)
+			settings.PUT("/settings", tr.handler.UpdateSettings)
+		}
+	}
+}
+
+// SuperAdminRoutes: quản lý tenant và analytics toàn platform (chỉ đăng ký ở tenant mặc định)
+type SuperAdminRoutes struct {
+	handler *handler.TenantHandler
+}
+
+func NewSuperAdminRoutes(handler *handler.TenantHandler) *SuperAdminRoutes {
+	return &SuperAdminRoutes{
+		handler: handler,
+	}
+}
+
+func (sr *SuperAdminRoutes) Register(r *gin.RouterGroup) {
+	superAdmin := r.Group("/super-admin")
+	{
+		superAdmin.Use(middleware.AuthMiddleware(), middleware.SuperAdminMiddleware())
+		{
+			superAdmin.GET("/tenants", sr.handler.GetTenants)
+			superAdmin.POST("/tenants", sr.handler.CreateTenant)
+			superAdmin.GET("/tenants/:tenant_id", sr.handler.GetTenant)
+			superAdmin.PUT("/tenants/:tenant_id", sr.handler.UpdateTenant)
+			superAdmin.POST("/tenants/:tenant_id/domains", sr.handler.AddDomain)
+			superAdmin.DELETE("/tenants/:tenant_id/domains/:domain_id", sr.handler.RemoveDomain)
+
+			superAdmin.GET("/analytics", sr.handler.GetCrossTenantAnalytics)
+		}
+	}
+}
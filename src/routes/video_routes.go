@@ -0,0 +1,53 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VideoRoutes struct {
+	handler *handler.VideoHandler
+}
+
+func NewVideoRoutes(handler *handler.VideoHandler) *VideoRoutes {
+	return &VideoRoutes{
+		handler: handler,
+	}
+}
+
+func (vr *VideoRoutes) Register(r *gin.RouterGroup) {
+	// Stream HLS - xác thực bằng signed token trong URL
+	r.GET("/videos/stream/:token/*filepath", vr.handler.Stream)
+
+	// Student routes - cần authentication
+	lessons := r.Group("/lessons")
+	{
+		lessons.Use(middleware.AuthMiddleware())
+		{
+			lessons.GET("/:lesson_id/playback", vr.handler.GetPlayback)
+		}
+	}
+
+	// Instructor routes - upload video
+	instructorLessons := r.Group("/instructor/courses/:course_id/lessons/:id")
+	{
+		instructorLessons.Use(middleware.AuthMiddleware())
+		instructorLessons.Use(middleware.InstructorMiddleware())
+		{
+			instructorLessons.POST("/video/uploads", vr.handler.InitUpload)
+		}
+	}
+
+	uploads := r.Group("/instructor/videos/uploads")
+	{
+		uploads.Use(middleware.AuthMiddleware())
+		uploads.Use(middleware.InstructorMiddleware())
+		{
+			uploads.GET("/:upload_id", vr.handler.GetUploadStatus)
+			uploads.PUT("/:upload_id/chunks/:index", vr.handler.UploadChunk)
+			uploads.POST("/:upload_id/complete", vr.handler.CompleteUpload)
+		}
+	}
+}
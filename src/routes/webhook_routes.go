@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookRoutes struct {
+	handler *handler.WebhookHandler
+}
+
+func NewWebhookRoutes(handler *handler.WebhookHandler) *WebhookRoutes {
+	return &WebhookRoutes{
+		handler: handler,
+	}
+}
+
+func (wr *WebhookRoutes) Register(r *gin.RouterGroup) {
+	webhooks := r.Group("/admin/webhooks")
+	{
+		webhooks.Use(middleware.AuthMiddleware())
+		webhooks.Use(middleware.AdminMiddleware())
+		{
+			webhooks.GET("/event-types", wr.handler.GetEventTypes)
+			webhooks.GET("", wr.handler.GetWebhooks)
+			webhooks.POST("", wr.handler.CreateWebhook)
+			webhooks.GET("/:webhook_id", wr.handler.GetWebhook)
+			webhooks.PUT("/:webhook_id", wr.handler.UpdateWebhook)
+			webhooks.DELETE("/:webhook_id", wr.handler.DeleteWebhook)
+			webhooks.POST("/:webhook_id/rotate-secret", wr.handler.RotateSecret)
+			webhooks.POST("/:webhook_id/test", wr.handler.SendTestEvent)
+			webhooks.GET("/:webhook_id/deliveries", wr.handler.GetDeliveries)
+			webhooks.POST("/deliveries/:delivery_id/replay", wr.handler.ReplayDelivery)
+		}
+	}
+}
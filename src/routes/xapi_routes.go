@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"lms/src/handler"
+	"lms/src/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type XapiRoutes struct {
+	handler     *handler.XapiHandler
+	credentials func() (key, secretHash string)
+}
+
+func NewXapiRoutes(handler *handler.XapiHandler, credentials func() (key, secretHash string)) *XapiRoutes {
+	return &XapiRoutes{
+		handler:     handler,
+		credentials: credentials,
+	}
+}
+
+func (xr *XapiRoutes) Register(r *gin.RouterGroup) {
+	xapi := r.Group("/xapi")
+	{
+		xapi.GET("/about", xr.handler.About)
+
+		// Statement API - client của LRS xác thực bằng HTTP Basic (credentials của tenant)
+		statements := xapi.Group("/statements")
+		statements.Use(middleware.XapiVersionMiddleware())
+		statements.Use(middleware.XapiAuthMiddleware(xr.credentials))
+		{
+			statements.GET("", xr.handler.GetStatements)
+			statements.PUT("", xr.handler.PutStatement)
+			statements.POST("", xr.handler.PostStatements)
+		}
+	}
+}
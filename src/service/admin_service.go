@@ -3,7 +3,9 @@ package service
 import (
 	"fmt"
 	"lms/src/dto"
+	"lms/src/models"
 	"lms/src/repository"
+	"lms/src/storage"
 	"lms/src/utils"
 	"math"
 	"strings"
@@ -11,14 +13,18 @@ import (
 )
 
 type adminService struct {
-	userRepo   repository.UserRepository
-	courseRepo repository.CourseRepository
+	userRepo     repository.UserRepository
+	courseRepo   repository.CourseRepository
+	approvalRepo repository.CourseApprovalRepository
+	store        storage.Storage
 }
 
-func NewAdminService(userRepo repository.UserRepository, courseRepo repository.CourseRepository) AdminService {
+func NewAdminService(userRepo repository.UserRepository, courseRepo repository.CourseRepository, approvalRepo repository.CourseApprovalRepository, store storage.Storage) AdminService {
 	return &adminService{
-		userRepo:   userRepo,
-		courseRepo: courseRepo,
+		userRepo:     userRepo,
+		courseRepo:   courseRepo,
+		approvalRepo: approvalRepo,
+		store:        store,
 	}
 }
 
@@ -209,6 +215,9 @@ func (as *adminService) DeleteUser(userId uint) (*dto.DeleteUserResponse, error)
 		return nil, utils.WrapError(err, "Failed to delete user", utils.ErrCodeInternal)
 	}
 
+	// 4. Dọn avatar trên storage
+	storage.DeleteByURL(as.store, existingUser.AvatarURL, "avatars/")
+
 	return &dto.DeleteUserResponse{
 		Message: "User deleted successfully",
 		UserId:  userId,
@@ -343,6 +352,7 @@ func (as *adminService) GetCourses(req *dto.GetAdminCoursesQueryRequest) (*dto.G
 			RatingAvg:      course.RatingAvg,
 			RatingCount:    course.RatingCount,
 			IsFeatured:     course.IsFeatured,
+			IsTemplate:     course.IsTemplate,
 			CreatedAt:      course.CreatedAt,
 			UpdatedAt:      course.UpdatedAt,
 		}
@@ -364,7 +374,7 @@ func (as *adminService) GetCourses(req *dto.GetAdminCoursesQueryRequest) (*dto.G
 	}, nil
 }
 
-func (as *adminService) ChangeCourseStatus(courseId uint, req *dto.ChangeCourseStatusRequest) (*dto.ChangeCourseStatusResponse, error) {
+func (as *adminService) ChangeCourseStatus(adminId, courseId uint, req *dto.ChangeCourseStatusRequest) (*dto.ChangeCourseStatusResponse, error) {
 	// 1. Kiểm tra course có tồn tại không
 	course, err := as.courseRepo.FindById(courseId)
 	if err != nil {
@@ -377,13 +387,31 @@ func (as *adminService) ChangeCourseStatus(courseId uint, req *dto.ChangeCourseS
 	}
 
 	// 3. Validate business rules
+	// Course chỉ vào hàng đợi duyệt khi instructor gửi
+	if req.Status == "pending_review" {
+		return nil, utils.NewError("courses enter the review queue only when submitted by the instructor", utils.ErrCodeBadRequest)
+	}
+
 	// Không cho phép publish course chưa có lesson
-	if req.Status == "published" && course.TotalLessons == 0 {
-		return nil, utils.NewError("cannot publish course without lessons", utils.ErrCodeBadRequest)
+	if req.Status == "published" {
+		lessonCounts, err := as.approvalRepo.CountLessons([]uint{courseId})
+		if err != nil {
+			return nil, utils.WrapError(err, "failed to count lessons", utils.ErrCodeInternal)
+		}
+		if lessonCounts[courseId] == 0 {
+			return nil, utils.NewError("cannot publish course without lessons", utils.ErrCodeBadRequest)
+		}
 	}
 
-	// 4. Update status
-	if err := as.courseRepo.UpdateCourseStatus(courseId, req.Status); err != nil {
+	// 4. Update status và ghi lịch sử
+	history := &models.CourseStatusHistory{
+		FromStatus: course.Status,
+		ToStatus:   req.Status,
+		Action:     courseActionStatusChanged,
+		Comment:    req.Reason,
+		ChangedBy:  adminId,
+	}
+	if err := as.approvalRepo.ChangeStatus(courseId, map[string]interface{}{"status": req.Status}, history); err != nil {
 		return nil, utils.WrapError(err, "failed to update course status", utils.ErrCodeInternal)
 	}
 
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"fmt"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"log"
+	"strconv"
+	"time"
+)
+
+const (
+	// Announcement "publishing" giữ lâu hơn thời gian này xem như instance đang gửi đã chết
+	announcementLockTimeout = 10 * time.Minute
+	// Khoảng chờ trước khi gửi lại announcement lỗi hoặc còn email chưa gửi được
+	announcementRetryDelay = 5 * time.Minute
+	// Số lần gửi email tối đa cho một học viên
+	announcementMaxEmailAttempts = 5
+)
+
+// AnnouncementDispatcher chạy background job publish announcement đến giờ:
+// tạo receipt cho mọi enrollment active (feed in-app) rồi gửi email.
+type AnnouncementDispatcher struct {
+	announcementRepo repository.AnnouncementRepository
+	emailService     EmailService
+	interval         time.Duration
+	wake             chan struct{}
+}
+
+func NewAnnouncementDispatcher(announcementRepo repository.AnnouncementRepository, emailService EmailService) *AnnouncementDispatcher {
+	seconds, err := strconv.Atoi(utils.GetEnv("ANNOUNCEMENT_POLL_INTERVAL_SECONDS", "30"))
+	if err != nil || seconds < 1 {
+		seconds = 30
+	}
+
+	return &AnnouncementDispatcher{
+		announcementRepo: announcementRepo,
+		emailService:     emailService,
+		interval:         time.Duration(seconds) * time.Second,
+		wake:             make(chan struct{}, 1),
+	}
+}
+
+// Trigger đánh thức dispatcher ngay (vd. announcement gửi ngay không cần chờ lịch)
+func (ad *AnnouncementDispatcher) Trigger() {
+	select {
+	case ad.wake <- struct{}{}:
+	default:
+	}
+}
+
+// StartWorkers chạy vòng lặp theo lịch. Announcement gửi dở (lỗi hoặc instance chết) được nhận lại
+// trong vòng lặp qua ClaimDueAnnouncements nên không instance nào phải gửi lại lúc khởi động.
+func (ad *AnnouncementDispatcher) StartWorkers() {
+	go func() {
+		ticker := time.NewTicker(ad.interval)
+		defer ticker.Stop()
+
+		for {
+			ad.dispatchDue()
+
+			select {
+			case <-ticker.C:
+			case <-ad.wake:
+			}
+		}
+	}()
+}
+
+func (ad *AnnouncementDispatcher) dispatchDue() {
+	now := time.Now()
+	announcements, err := ad.announcementRepo.ClaimDueAnnouncements(now, now.Add(-announcementLockTimeout))
+	if err != nil {
+		log.Printf("Failed to claim due announcements: %v", err)
+		return
+	}
+
+	for i := range announcements {
+		ad.publish(&announcements[i])
+	}
+}
+
+func (ad *AnnouncementDispatcher) publish(announcement *models.CourseAnnouncement) {
+	if err := ad.deliver(announcement); err != nil {
+		// Giữ status "publishing" và trả claim để lượt sau gửi tiếp
+		log.Printf("Announcement delivery failed (announcement %d): %v", announcement.Id, err)
+		ad.scheduleRetry(announcement.Id)
+	}
+}
+
+// scheduleRetry trả claim của announcement, vòng lặp nhận lại sau announcementRetryDelay
+func (ad *AnnouncementDispatcher) scheduleRetry(announcementId uint) {
+	if err := ad.announcementRepo.Update(announcementId, map[string]interface{}{
+		"locked_at": nil,
+		"retry_at":  time.Now().Add(announcementRetryDelay),
+	}); err != nil {
+		log.Printf("Failed to schedule announcement retry (announcement %d): %v", announcementId, err)
+	}
+}
+
+func (ad *AnnouncementDispatcher) deliver(announcement *models.CourseAnnouncement) error {
+	// 1. Tạo receipt cho học viên đang học (feed in-app)
+	userIds, err := ad.announcementRepo.GetActiveEnrollmentUserIds(announcement.CourseId)
+	if err != nil {
+		return err
+	}
+
+	receipts := make([]models.AnnouncementReceipt, 0, len(userIds))
+	for _, userId := range userIds {
+		token, err := utils.GenerateSecureToken(24)
+		if err != nil {
+			return err
+		}
+		receipts = append(receipts, models.AnnouncementReceipt{
+			AnnouncementId: announcement.Id,
+			UserId:         userId,
+			CourseId:       announcement.CourseId,
+			Token:          token,
+		})
+	}
+
+	if err := ad.announcementRepo.CreateReceipts(receipts); err != nil {
+		return err
+	}
+
+	if announcement.PublishedAt == nil {
+		if err := ad.announcementRepo.Update(announcement.Id, map[string]interface{}{"published_at": time.Now()}); err != nil {
+			return err
+		}
+	}
+
+	// 2. Gửi email cho học viên chưa unsubscribe course
+	full, err := ad.announcementRepo.FindById(announcement.Id)
+	if err != nil {
+		return err
+	}
+
+	pending, err := ad.announcementRepo.GetPendingEmailReceipts(announcement.Id, announcementMaxEmailAttempts)
+	if err != nil {
+		return err
+	}
+
+	retryable := 0
+
+	baseURL := utils.GetEnv("BASE_URL", "http://localhost:8080")
+	for _, receipt := range pending {
+		unsubscribeURL := fmt.Sprintf("%s/api/v1/announcements/email/%s/unsubscribe", baseURL, receipt.Token)
+		openURL := fmt.Sprintf("%s/api/v1/announcements/email/%s/open", baseURL, receipt.Token)
+
+		err := ad.emailService.SendAnnouncementEmail(receipt.User.TenantId, receipt.User.Email, receipt.User.FullName, full.Course.Title, full.Title, full.Body, unsubscribeURL, openURL)
+		if err != nil {
+			// Ghi lại lỗi để lượt sau gửi lại, hết lượt thử thì bỏ qua học viên này
+			log.Printf("Failed to send announcement email (announcement %d, user %d): %v", announcement.Id, receipt.UserId, err)
+			if err := ad.announcementRepo.RecordEmailFailure(receipt.Id, err.Error()); err != nil {
+				return err
+			}
+			if receipt.EmailAttempts+1 < announcementMaxEmailAttempts {
+				retryable++
+			}
+			continue
+		}
+
+		if err := ad.announcementRepo.MarkReceipt(receipt.Id, "email_sent_at", time.Now()); err != nil {
+			return err
+		}
+	}
+
+	// 3. Còn email gửi lại được thì giữ "publishing" chờ lượt sau, ngược lại hoàn tất
+	if retryable > 0 {
+		ad.scheduleRetry(announcement.Id)
+		return nil
+	}
+	return ad.announcementRepo.Update(announcement.Id, map[string]interface{}{
+		"status":    "published",
+		"locked_at": nil,
+		"retry_at":  nil,
+	})
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"math"
+	"strings"
+	"time"
+)
+
+type announcementService struct {
+	announcementRepo repository.AnnouncementRepository
+	instructorRepo   repository.InstructorRepository
+	lessonRepo       repository.LessonRepository
+	dispatcher       *AnnouncementDispatcher
+}
+
+func NewAnnouncementService(
+	announcementRepo repository.AnnouncementRepository,
+	instructorRepo repository.InstructorRepository,
+	lessonRepo repository.LessonRepository,
+	dispatcher *AnnouncementDispatcher,
+) AnnouncementService {
+	return &announcementService{
+		announcementRepo: announcementRepo,
+		instructorRepo:   instructorRepo,
+		lessonRepo:       lessonRepo,
+		dispatcher:       dispatcher,
+	}
+}
+
+func (as *announcementService) GetCourseAnnouncements(instructorId, courseId uint, req *dto.GetCourseAnnouncementsQueryRequest) (*dto.GetCourseAnnouncementsResponse, error) {
+	// 1. Kiểm tra course thuộc instructor
+	if _, err := as.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	// 3. Lấy announcements và số liệu đọc/mở
+	announcements, total, err := as.announcementRepo.GetCourseAnnouncements(courseId, offset, limit)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get announcements", utils.ErrCodeInternal)
+	}
+
+	announcementIds := make([]uint, len(announcements))
+	for i, announcement := range announcements {
+		announcementIds[i] = announcement.Id
+	}
+
+	stats, err := as.announcementRepo.GetAnnouncementStats(announcementIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get announcement stats", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.InstructorAnnouncementItem, len(announcements))
+	for i := range announcements {
+		items[i] = toInstructorAnnouncementItem(&announcements[i], stats[announcements[i].Id])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetCourseAnnouncementsResponse{
+		Announcements: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (as *announcementService) CreateAnnouncement(instructorId, courseId uint, req *dto.CreateAnnouncementRequest) (*dto.InstructorAnnouncementItem, error) {
+	// 1. Kiểm tra course thuộc instructor
+	if _, err := as.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Sanitize nội dung rich-text
+	body := utils.SanitizeHTML(req.Body)
+	if strings.TrimSpace(utils.HTMLToText(body)) == "" {
+		return nil, utils.NewError("Announcement body is required", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Không có lịch (hoặc lịch trong quá khứ) thì gửi ngay
+	publishAt := time.Now()
+	if req.PublishAt != nil && req.PublishAt.After(publishAt) {
+		publishAt = *req.PublishAt
+	}
+
+	announcement := &models.CourseAnnouncement{
+		CourseId:     courseId,
+		InstructorId: instructorId,
+		Title:        strings.TrimSpace(req.Title),
+		Body:         body,
+		Status:       "scheduled",
+		PublishAt:    publishAt,
+	}
+
+	if err := as.announcementRepo.Create(announcement); err != nil {
+		return nil, utils.WrapError(err, "Failed to create announcement", utils.ErrCodeInternal)
+	}
+
+	// 4. Đánh thức dispatcher nếu cần gửi ngay
+	if !publishAt.After(time.Now()) {
+		as.dispatcher.Trigger()
+	}
+
+	item := toInstructorAnnouncementItem(announcement, dto.AnnouncementStats{})
+	return &item, nil
+}
+
+func (as *announcementService) UpdateAnnouncement(instructorId, courseId, announcementId uint, req *dto.UpdateAnnouncementRequest) (*dto.InstructorAnnouncementItem, error) {
+	// 1. Lấy announcement của course
+	announcement, err := as.findCourseAnnouncement(instructorId, courseId, announcementId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Chỉ sửa được announcement chưa gửi
+	if announcement.Status != "scheduled" {
+		return nil, utils.NewError("Only scheduled announcements can be edited", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Chuẩn bị dữ liệu cập nhật
+	updates := make(map[string]interface{})
+
+	if req.Title != nil {
+		updates["title"] = strings.TrimSpace(*req.Title)
+	}
+
+	if req.Body != nil {
+		body := utils.SanitizeHTML(*req.Body)
+		if strings.TrimSpace(utils.HTMLToText(body)) == "" {
+			return nil, utils.NewError("Announcement body is required", utils.ErrCodeBadRequest)
+		}
+		updates["body"] = body
+	}
+
+	publishNow := false
+	if req.PublishAt != nil {
+		publishAt := *req.PublishAt
+		if !publishAt.After(time.Now()) {
+			publishAt = time.Now()
+			publishNow = true
+		}
+		updates["publish_at"] = publishAt
+	}
+
+	if len(updates) == 0 {
+		return nil, utils.NewError("No fields to update", utils.ErrCodeBadRequest)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	// 4. Cập nhật
+	if err := as.announcementRepo.Update(announcement.Id, updates); err != nil {
+		return nil, utils.WrapError(err, "Failed to update announcement", utils.ErrCodeInternal)
+	}
+
+	if publishNow {
+		as.dispatcher.Trigger()
+	}
+
+	updated, err := as.announcementRepo.FindById(announcement.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get updated announcement", utils.ErrCodeInternal)
+	}
+
+	item := toInstructorAnnouncementItem(updated, dto.AnnouncementStats{})
+	return &item, nil
+}
+
+func (as *announcementService) DeleteAnnouncement(instructorId, courseId, announcementId uint) (*dto.DeleteAnnouncementResponse, error) {
+	// 1. Lấy announcement của course
+	announcement, err := as.findCourseAnnouncement(instructorId, courseId, announcementId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Không xóa khi đang gửi
+	if announcement.Status == "publishing" {
+		return nil, utils.NewError("Announcement is being delivered, please try again later", utils.ErrCodeConflict)
+	}
+
+	// 3. Xóa announcement (và feed của học viên)
+	if err := as.announcementRepo.Delete(announcement.Id); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete announcement", utils.ErrCodeInternal)
+	}
+
+	return &dto.DeleteAnnouncementResponse{
+		Message: "Announcement deleted successfully",
+		Id:      announcement.Id,
+	}, nil
+}
+
+func (as *announcementService) GetFeed(userId uint, req *dto.GetAnnouncementFeedQueryRequest) (*dto.GetAnnouncementFeedResponse, error) {
+	// 1. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	// 2. Prepare filters
+	filters := make(map[string]interface{})
+	if req.CourseId != nil {
+		filters["course_id"] = *req.CourseId
+	}
+	if req.UnreadOnly {
+		filters["unread_only"] = true
+	}
+
+	// 3. Lấy feed và số chưa đọc
+	receipts, total, err := as.announcementRepo.GetUserFeed(userId, offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get announcements", utils.ErrCodeInternal)
+	}
+
+	unreadCount, err := as.announcementRepo.CountUnread(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count unread announcements", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.AnnouncementFeedItem, len(receipts))
+	for i := range receipts {
+		items[i] = toAnnouncementFeedItem(&receipts[i])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetAnnouncementFeedResponse{
+		Announcements: items,
+		UnreadCount:   unreadCount,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (as *announcementService) GetAnnouncement(userId, announcementId uint) (*dto.AnnouncementFeedItem, error) {
+	// 1. Học viên chỉ xem được announcement đã gửi tới mình
+	receipt, err := as.announcementRepo.FindReceipt(userId, announcementId)
+	if err != nil || receipt.Announcement.Id == 0 {
+		return nil, utils.NewError("Announcement not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Đánh dấu đã đọc
+	if receipt.ReadAt == nil {
+		now := time.Now()
+		if err := as.announcementRepo.MarkReceipt(receipt.Id, "read_at", now); err != nil {
+			return nil, utils.WrapError(err, "Failed to mark announcement as read", utils.ErrCodeInternal)
+		}
+		receipt.ReadAt = &now
+	}
+
+	item := toAnnouncementFeedItem(receipt)
+	return &item, nil
+}
+
+func (as *announcementService) UpdateSubscription(userId, courseId uint, req *dto.AnnouncementSubscriptionRequest) (*dto.AnnouncementSubscriptionResponse, error) {
+	// 1. Kiểm tra user đã enroll course chưa
+	isEnrolled, err := as.lessonRepo.CheckUserEnrollment(userId, courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check enrollment", utils.ErrCodeInternal)
+	}
+
+	if !isEnrolled {
+		return nil, utils.NewError("You are not enrolled in this course", utils.ErrCodeForbidden)
+	}
+
+	// 2. Bật/tắt email announcement của course
+	if *req.Subscribed {
+		err = as.announcementRepo.Subscribe(userId, courseId)
+	} else {
+		err = as.announcementRepo.Unsubscribe(userId, courseId)
+	}
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to update subscription", utils.ErrCodeInternal)
+	}
+
+	return &dto.AnnouncementSubscriptionResponse{
+		CourseId:   courseId,
+		Subscribed: *req.Subscribed,
+	}, nil
+}
+
+func (as *announcementService) TrackEmailOpen(token string) error {
+	receipt, err := as.announcementRepo.FindReceiptByToken(token)
+	if err != nil {
+		return utils.NewError("Announcement not found", utils.ErrCodeNotFound)
+	}
+
+	if receipt.EmailOpenedAt == nil {
+		if err := as.announcementRepo.MarkReceipt(receipt.Id, "email_opened_at", time.Now()); err != nil {
+			return utils.WrapError(err, "Failed to track email open", utils.ErrCodeInternal)
+		}
+	}
+
+	return nil
+}
+
+func (as *announcementService) UnsubscribeByToken(token string) (*dto.UnsubscribeAnnouncementResponse, error) {
+	// 1. Token trong link email xác định học viên và course
+	receipt, err := as.announcementRepo.FindReceiptByToken(token)
+	if err != nil {
+		return nil, utils.NewError("Invalid unsubscribe link", utils.ErrCodeNotFound)
+	}
+
+	// 2. Unsubscribe email announcement của course
+	if err := as.announcementRepo.Unsubscribe(receipt.UserId, receipt.CourseId); err != nil {
+		return nil, utils.WrapError(err, "Failed to unsubscribe", utils.ErrCodeInternal)
+	}
+
+	courseTitle := ""
+	if course, err := as.instructorRepo.FindCourseById(receipt.CourseId); err == nil {
+		courseTitle = course.Title
+	}
+
+	return &dto.UnsubscribeAnnouncementResponse{
+		Message:     "You will no longer receive announcement emails for this course",
+		CourseId:    receipt.CourseId,
+		CourseTitle: courseTitle,
+	}, nil
+}
+
+func (as *announcementService) findCourseAnnouncement(instructorId, courseId, announcementId uint) (*models.CourseAnnouncement, error) {
+	if _, err := as.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	announcement, err := as.announcementRepo.FindById(announcementId)
+	if err != nil || announcement.CourseId != courseId {
+		return nil, utils.NewError("Announcement not found", utils.ErrCodeNotFound)
+	}
+
+	return announcement, nil
+}
+
+func toInstructorAnnouncementItem(announcement *models.CourseAnnouncement, stats dto.AnnouncementStats) dto.InstructorAnnouncementItem {
+	return dto.InstructorAnnouncementItem{
+		Id:          announcement.Id,
+		CourseId:    announcement.CourseId,
+		Title:       announcement.Title,
+		Body:        announcement.Body,
+		Status:      announcement.Status,
+		PublishAt:   announcement.PublishAt,
+		PublishedAt: announcement.PublishedAt,
+		Stats:       stats,
+		CreatedAt:   announcement.CreatedAt,
+		UpdatedAt:   announcement.UpdatedAt,
+	}
+}
+
+func toAnnouncementFeedItem(receipt *models.AnnouncementReceipt) dto.AnnouncementFeedItem {
+	announcement := receipt.Announcement
+	return dto.AnnouncementFeedItem{
+		Id:             announcement.Id,
+		CourseId:       announcement.CourseId,
+		CourseTitle:    announcement.Course.Title,
+		CourseSlug:     announcement.Course.Slug,
+		InstructorName: announcement.Instructor.FullName,
+		Title:          announcement.Title,
+		Body:           announcement.Body,
+		PublishedAt:    announcement.PublishedAt,
+		IsRead:         receipt.ReadAt != nil,
+		ReadAt:         receipt.ReadAt,
+	}
+}
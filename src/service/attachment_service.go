@@ -0,0 +1,205 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+)
+
+type attachmentService struct {
+	attachmentRepo repository.AttachmentRepository
+	instructorRepo repository.InstructorRepository
+	lessonRepo     repository.LessonRepository
+	store          storage.Storage
+}
+
+// AttachmentDownload là file đính kèm được đọc từ storage để trả về cho client
+type AttachmentDownload struct {
+	Body     io.ReadCloser
+	FileName string
+	MimeType string
+	FileSize int64
+}
+
+func NewAttachmentService(
+	attachmentRepo repository.AttachmentRepository,
+	instructorRepo repository.InstructorRepository,
+	lessonRepo repository.LessonRepository,
+	store storage.Storage,
+) AttachmentService {
+	return &attachmentService{
+		attachmentRepo: attachmentRepo,
+		instructorRepo: instructorRepo,
+		lessonRepo:     lessonRepo,
+		store:          store,
+	}
+}
+
+func (as *attachmentService) UploadAttachment(instructorId, courseId, lessonId uint, title string, file *multipart.FileHeader) (*dto.LessonAttachmentItem, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	if _, err := as.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Kiểm tra lesson có thuộc về course không
+	if _, err := as.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId); err != nil {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	// 3. Validate file theo allow-list của attachment
+	fileName, mimeType, err := utils.ValidateFile(file, utils.AttachmentFileRule())
+	if err != nil {
+		return nil, utils.WrapError(err, "Invalid attachment file", utils.ErrCodeBadRequest)
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(file.Filename), filepath.Ext(file.Filename))
+	}
+	if len(title) > 200 {
+		return nil, utils.NewError("Title must be at most 200 characters", utils.ErrCodeBadRequest)
+	}
+
+	// 4. Lưu file (private) vào storage
+	src, err := file.Open()
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to upload attachment", utils.ErrCodeBadRequest)
+	}
+	defer src.Close()
+
+	key := lessonAttachmentPrefix(lessonId) + fileName
+	if err := as.store.Put(key, src, file.Size, mimeType); err != nil {
+		return nil, utils.WrapError(err, "Failed to upload attachment", utils.ErrCodeInternal)
+	}
+
+	// 5. Lưu thông tin attachment
+	attachment := &models.LessonAttachment{
+		LessonId:   lessonId,
+		Title:      title,
+		FileName:   filepath.Base(file.Filename),
+		FileSize:   file.Size,
+		MimeType:   mimeType,
+		StorageKey: key,
+	}
+	if err := as.attachmentRepo.Create(attachment); err != nil {
+		as.store.Delete(key)
+		return nil, utils.WrapError(err, "Failed to create attachment", utils.ErrCodeInternal)
+	}
+
+	item := toLessonAttachmentItem(attachment)
+	return &item, nil
+}
+
+func (as *attachmentService) DeleteAttachment(instructorId, courseId, lessonId, attachmentId uint) (*dto.DeleteAttachmentResponse, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	if _, err := as.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Kiểm tra attachment có thuộc về lesson của course không
+	if _, err := as.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId); err != nil {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	attachment, err := as.attachmentRepo.FindById(attachmentId)
+	if err != nil || attachment.LessonId != lessonId {
+		return nil, utils.NewError("Attachment not found", utils.ErrCodeNotFound)
+	}
+
+	// 3. Xóa attachment và file trên storage
+	if err := as.attachmentRepo.Delete(attachmentId); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete attachment", utils.ErrCodeInternal)
+	}
+
+	if err := as.store.Delete(attachment.StorageKey); err != nil {
+		log.Printf("Failed to delete attachment file %s: %v", attachment.StorageKey, err)
+	}
+
+	return &dto.DeleteAttachmentResponse{
+		Message: "Attachment deleted successfully",
+		Id:      attachmentId,
+	}, nil
+}
+
+func (as *attachmentService) DownloadAttachment(userId, lessonId, attachmentId uint) (*AttachmentDownload, error) {
+	// 1. Lấy attachment và lesson
+	attachment, err := as.attachmentRepo.FindById(attachmentId)
+	if err != nil || attachment.LessonId != lessonId {
+		return nil, utils.NewError("Attachment not found", utils.ErrCodeNotFound)
+	}
+
+	lessons, err := as.lessonRepo.FindLessonByIds([]uint{lessonId})
+	if err != nil || len(lessons) == 0 || !lessons[0].IsPublished {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+	lesson := lessons[0]
+
+	// 2. Lesson preview ai cũng tải được, còn lại phải enroll và lesson đã mở khóa
+	if !lesson.IsPreview {
+		if userId == 0 {
+			return nil, utils.NewError("You must login to download this attachment", utils.ErrCodeUnauthorized)
+		}
+
+		isEnrolled, err := as.lessonRepo.CheckUserEnrollment(userId, lesson.CourseId)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to check enrollment", utils.ErrCodeInternal)
+		}
+		if !isEnrolled {
+			return nil, utils.NewError("You must enroll in this course to download this attachment", utils.ErrCodeForbidden)
+		}
+
+		lock, err := getLessonLock(as.lessonRepo, userId, lesson.CourseId, lesson.Id)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to resolve lesson schedule", utils.ErrCodeInternal)
+		}
+		if lock.isLocked {
+			return nil, utils.NewError(lockMessage(lock), utils.ErrCodeForbidden)
+		}
+	}
+
+	// 3. Đọc file từ storage
+	body, err := as.store.Get(attachment.StorageKey)
+	if err != nil {
+		return nil, utils.NewError("Attachment file not found", utils.ErrCodeNotFound)
+	}
+
+	// 4. Tăng lượt tải
+	if err := as.attachmentRepo.IncrementDownloadCount(attachment.Id); err != nil {
+		log.Printf("Failed to increment download count (attachment %d): %v", attachment.Id, err)
+	}
+
+	return &AttachmentDownload{
+		Body:     body,
+		FileName: attachment.FileName,
+		MimeType: attachment.MimeType,
+		FileSize: attachment.FileSize,
+	}, nil
+}
+
+// lessonAttachmentPrefix prefix (private) chứa file đính kèm của một lesson trên storage
+func lessonAttachmentPrefix(lessonId uint) string {
+	return fmt.Sprintf("%sattachments/%d/", storage.PrivatePrefix, lessonId)
+}
+
+func toLessonAttachmentItem(attachment *models.LessonAttachment) dto.LessonAttachmentItem {
+	baseURL := utils.GetEnv("BASE_URL", "http://localhost:8080")
+	return dto.LessonAttachmentItem{
+		Id:            attachment.Id,
+		LessonId:      attachment.LessonId,
+		Title:         attachment.Title,
+		FileName:      attachment.FileName,
+		FileSize:      attachment.FileSize,
+		MimeType:      attachment.MimeType,
+		DownloadCount: attachment.DownloadCount,
+		DownloadURL:   fmt.Sprintf("%s/api/v1/lessons/%d/attachments/%d/download", baseURL, attachment.LessonId, attachment.Id),
+		CreatedAt:     attachment.CreatedAt,
+	}
+}
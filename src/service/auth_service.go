@@ -12,13 +12,15 @@ type authService struct {
 	userRepo          repository.UserRepository
 	passwordResetRepo repository.PasswordResetRepository
 	emailService      EmailService
+	transactor        repository.Transactor
 }
 
-func NewAuthService(userRepo repository.UserRepository, passwordResetRepo repository.PasswordResetRepository, emailService EmailService) AuthService {
+func NewAuthService(userRepo repository.UserRepository, passwordResetRepo repository.PasswordResetRepository, emailService EmailService, transactor repository.Transactor) AuthService {
 	return &authService{
 		userRepo:          userRepo,
 		passwordResetRepo: passwordResetRepo,
 		emailService:      emailService,
+		transactor:        transactor,
 	}
 }
 
@@ -55,13 +57,25 @@ func (as *authService) Register(req *dto.RegisterRequest) (*dto.AuthResponse, er
 		EmailVerified: false,
 	}
 
-	// 4. Luu vao db
-	if err := as.userRepo.Create(&user); err != nil {
+	// 4. Luu vao db cung event UserRegistered (email chao mung gui qua event subscriber)
+	err = as.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		if err := repos.Users.Create(&user); err != nil {
+			return err
+		}
+		return repos.Outbox.Append(dto.UserRegisteredEvent{
+			TenantId: user.TenantId,
+			UserId:   user.Id,
+			Username: user.Username,
+			Email:    user.Email,
+			FullName: user.FullName,
+		})
+	})
+	if err != nil {
 		return nil, utils.WrapError(err, "failed to create user", utils.ErrCodeInternal)
 	}
 
 	// 5. Tao jwt tokens
-	accessToken, refreshToken, err := utils.GenerateTokens(user.Id, user.Username, user.Role)
+	accessToken, refreshToken, err := utils.GenerateTokens(user.Id, user.Username, user.Role, user.TenantId, user.IsSuperAdmin)
 	if err != nil {
 		return nil, utils.NewError("failed to create tokens", utils.ErrCodeInternal)
 	}
@@ -104,7 +118,7 @@ func (as *authService) Login(req *dto.LoginRequest) (*dto.AuthResponse, error) {
 	}
 
 	// Generate tokens
-	accessToken, refreshToken, err := utils.GenerateTokens(user.Id, user.Email, user.Role)
+	accessToken, refreshToken, err := utils.GenerateTokens(user.Id, user.Email, user.Role, user.TenantId, user.IsSuperAdmin)
 	if err != nil {
 		return nil, utils.NewError("failed to create tokens", utils.ErrCodeInternal)
 	}
@@ -168,7 +182,7 @@ func (as *authService) RefreshToken(req *dto.RefreshTokenRequest) (*dto.TokenRes
 	}
 
 	// Tạo tokens mới
-	newAccessToken, newRefreshToken, err := utils.GenerateTokens(user.Id, user.Username, user.Role)
+	newAccessToken, newRefreshToken, err := utils.GenerateTokens(user.Id, user.Username, user.Role, user.TenantId, user.IsSuperAdmin)
 	if err != nil {
 		return nil, utils.NewError("failed to create tokens", utils.ErrCodeInternal)
 	}
@@ -223,7 +237,7 @@ func (as *authService) ForgotPassword(req *dto.ForgotPasswordRequest) (*dto.Forg
 	}
 
 	// 8. Gửi email (sử dụng secureToken raw, không phải hash)
-	if err := as.emailService.SendPasswordResetEmail(req.Email, secureToken, readableCode); err != nil {
+	if err := as.emailService.SendPasswordResetEmail(user.TenantId, req.Email, secureToken, readableCode); err != nil {
 		fmt.Printf("Failed to send reset email to %s: %v\n", req.Email, err)
 		// Không trả lỗi cho user để tránh leak thông tin
 	}
@@ -0,0 +1,887 @@
+package service
+
+import (
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// bundleClaimTTL là thời gian tối đa một purchase pending giữ chỗ, quá hạn thì xem như request đã chết
+const bundleClaimTTL = 15 * time.Minute
+
+type bundleService struct {
+	bundleRepo     repository.BundleRepository
+	courseRepo     repository.CourseRepository
+	enrollmentRepo repository.EnrollmentRepository
+	transactor     repository.Transactor
+	paymentGateway PaymentGateway
+}
+
+func NewBundleService(
+	bundleRepo repository.BundleRepository,
+	courseRepo repository.CourseRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	transactor repository.Transactor,
+	paymentGateway PaymentGateway,
+) BundleService {
+	return &bundleService{
+		bundleRepo:     bundleRepo,
+		courseRepo:     courseRepo,
+		enrollmentRepo: enrollmentRepo,
+		transactor:     transactor,
+		paymentGateway: paymentGateway,
+	}
+}
+
+// bundleQuoteLine là một course trong bundle cùng phần giá bundle phân bổ cho course đó
+type bundleQuoteLine struct {
+	course    *models.Course
+	listPrice float64
+	price     float64
+	owned     bool
+	// Enrollment cũ được dùng lại khi mua (đã drop hoặc ghi danh qua subscription)
+	existing *models.Enrollment
+}
+
+type bundleQuote struct {
+	bundlePrice   float64
+	originalPrice float64
+	finalPrice    float64
+	lines         []bundleQuoteLine
+	skippedIds    []uint
+}
+
+func (bs *bundleService) GetBundles(req *dto.GetBundlesQueryRequest) (*dto.GetBundlesResponse, error) {
+	// Set default values
+	page := 1
+	limit := 12
+	orderBy := "created_at"
+	sortBy := "desc"
+
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	if req.OrderBy != "" {
+		orderBy = req.OrderBy
+	}
+	if req.SortBy != "" {
+		sortBy = req.SortBy
+	}
+
+	offset := (page - 1) * limit
+
+	// Chỉ hiển thị bundle đã published
+	filters := map[string]interface{}{
+		"status": "published",
+	}
+	if req.Search != "" {
+		filters["search"] = req.Search
+	}
+
+	bundles, total, err := bs.bundleRepo.GetBundles(offset, limit, filters, orderBy, sortBy)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get bundles", utils.ErrCodeInternal)
+	}
+
+	bundleItems := make([]dto.BundleItem, len(bundles))
+	for i := range bundles {
+		bundleItems[i] = toBundleItem(&bundles[i])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	pagination := dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
+
+	return &dto.GetBundlesResponse{
+		Bundles:    bundleItems,
+		Pagination: pagination,
+	}, nil
+}
+
+func (bs *bundleService) GetBundleBySlug(slug string) (*dto.BundleDetail, error) {
+	bundle, err := bs.bundleRepo.FindBySlug(slug)
+	if err != nil || bundle.Status != "published" {
+		return nil, utils.NewError("Bundle not found", utils.ErrCodeNotFound)
+	}
+
+	return toBundleDetail(bundle), nil
+}
+
+func (bs *bundleService) GetQuote(userId uint, slug string) (*dto.BundleQuoteResponse, error) {
+	bundle, err := bs.bundleRepo.FindBySlug(slug)
+	if err != nil || bundle.Status != "published" {
+		return nil, utils.NewError("Bundle not found", utils.ErrCodeNotFound)
+	}
+
+	quote, err := bs.buildQuote(userId, bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	courses := make([]dto.BundleQuoteCourse, len(quote.lines))
+	for i, line := range quote.lines {
+		courses[i] = dto.BundleQuoteCourse{
+			CourseId:       line.course.Id,
+			Title:          line.course.Title,
+			ListPrice:      line.listPrice,
+			AllocatedPrice: line.price,
+			Owned:          line.owned,
+		}
+	}
+
+	return &dto.BundleQuoteResponse{
+		BundleId:       bundle.Id,
+		BundleTitle:    bundle.Title,
+		BundlePrice:    quote.bundlePrice,
+		OriginalPrice:  quote.originalPrice,
+		FinalPrice:     quote.finalPrice,
+		CoursesCount:   len(quote.lines) - len(quote.skippedIds),
+		SkippedCourses: len(quote.skippedIds),
+		Courses:        courses,
+	}, nil
+}
+
+func (bs *bundleService) PurchaseBundle(userId uint, slug string, req *dto.PurchaseBundleRequest) (*dto.PurchaseBundleResponse, error) {
+	// 1. Kiểm tra bundle đang bán
+	bundle, err := bs.bundleRepo.FindBySlug(slug)
+	if err != nil || bundle.Status != "published" {
+		return nil, utils.NewError("Bundle not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Giữ chỗ bằng purchase pending trước khi tính giá và thu tiền: unique index (user_id, bundle_id)
+	// WHERE status = 'pending' khiến lần submit thứ hai (double submit) bị từ chối thay vì thu tiền hai lần
+	purchase := &models.BundlePurchase{
+		BundleId:      bundle.Id,
+		UserId:        userId,
+		PurchaseCode:  fmt.Sprintf("BDL-%s-%d", strings.ToUpper(uuid.New().String()[:8]), time.Now().Unix()),
+		BundlePrice:   bundle.Price,
+		PaymentMethod: req.PaymentMethod,
+		Status:        "pending",
+	}
+	if err := bs.claimPurchase(purchase); err != nil {
+		return nil, err
+	}
+
+	// 3. Tính giá sau khi giữ chỗ để thấy các course vừa được ghi danh bởi lần mua trước
+	quote, err := bs.validatePurchase(userId, bundle)
+	if err != nil {
+		if delErr := bs.bundleRepo.DeletePurchase(purchase.Id); delErr != nil {
+			log.Printf("Failed to release bundle purchase %s: %v", purchase.PurchaseCode, delErr)
+		}
+		return nil, err
+	}
+
+	// 4. Thu tiền (sau khi phân bổ mà bằng 0 thì không gọi gateway)
+	purchase.BundlePrice = quote.bundlePrice
+	purchase.OriginalPrice = quote.originalPrice
+	purchase.FinalPrice = quote.finalPrice
+	purchase.CoursesCount = len(quote.lines) - len(quote.skippedIds)
+	purchase.SkippedCourses = len(quote.skippedIds)
+	if quote.finalPrice > 0 {
+		transactionId, err := bs.paymentGateway.Charge(purchase.PaymentMethod, quote.finalPrice, purchase.PurchaseCode)
+		if err != nil {
+			bs.finishPurchase(purchase, "failed")
+			return nil, utils.NewError(fmt.Sprintf("Payment was declined: %s", err.Error()), utils.ErrCodeBadRequest)
+		}
+		purchase.TransactionId = transactionId
+	} else {
+		purchase.PaymentMethod = "free"
+	}
+
+	now := time.Now()
+	paymentMethod := purchase.PaymentMethod
+	courseItems := make([]dto.BundlePurchaseCourseItem, 0, purchase.CoursesCount)
+
+	// 5. Chuyển purchase sang paid, mỗi course một order đã thanh toán (để tính doanh thu theo course/instructor),
+	// enrollment và event trong cùng transaction
+	err = bs.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		if err := repos.Bundles.UpdatePurchase(purchase.Id, map[string]interface{}{
+			"bundle_price":    purchase.BundlePrice,
+			"original_price":  purchase.OriginalPrice,
+			"final_price":     purchase.FinalPrice,
+			"courses_count":   purchase.CoursesCount,
+			"skipped_courses": purchase.SkippedCourses,
+			"payment_method":  purchase.PaymentMethod,
+			"transaction_id":  purchase.TransactionId,
+			"status":          "paid",
+			"paid_at":         now,
+		}); err != nil {
+			return utils.WrapError(err, "Failed to update bundle purchase", utils.ErrCodeInternal)
+		}
+
+		var events []dto.DomainEvent
+		courseIds := make([]uint, 0, purchase.CoursesCount)
+
+		for _, line := range quote.lines {
+			if line.owned {
+				continue
+			}
+
+			order := &models.Order{
+				UserId:           userId,
+				CourseId:         line.course.Id,
+				OrderCode:        fmt.Sprintf("ORD-%s-%d", uuid.New().String()[:8], now.Unix()),
+				OriginalPrice:    line.listPrice,
+				DiscountAmount:   math.Round((line.listPrice-line.price)*100) / 100,
+				FinalPrice:       line.price,
+				PaymentMethod:    paymentMethod,
+				PaymentStatus:    "paid",
+				PaidAt:           &now,
+				BundlePurchaseId: &purchase.Id,
+			}
+			if err := repos.Orders.Create(order); err != nil {
+				return utils.WrapError(err, "Failed to create order", utils.ErrCodeInternal)
+			}
+			events = append(events, newOrderPaidEvent(order))
+
+			// Enrollment cũ (đã drop hoặc qua subscription) chuyển thành enrollment đã mua
+			enrollment := line.existing
+			if enrollment != nil {
+				updates := map[string]interface{}{
+					"subscription_id": nil,
+				}
+				if enrollment.Status == "dropped" {
+					updates["status"] = "active"
+				}
+				if err := repos.Enrollments.UpdateEnrollmentProgress(enrollment.Id, updates); err != nil {
+					return utils.WrapError(err, "Failed to update enrollment", utils.ErrCodeInternal)
+				}
+			} else {
+				enrollment = &models.Enrollment{
+					UserId:             userId,
+					CourseId:           line.course.Id,
+					EnrolledAt:         now,
+					ProgressPercentage: 0,
+					Status:             "active",
+				}
+				if err := repos.Enrollments.Create(enrollment); err != nil {
+					return utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
+				}
+				events = append(events, newEnrollmentCreatedEvent(enrollment, order.Id))
+			}
+
+			courseIds = append(courseIds, line.course.Id)
+			courseItems = append(courseItems, dto.BundlePurchaseCourseItem{
+				CourseId:      line.course.Id,
+				CourseTitle:   line.course.Title,
+				OrderId:       order.Id,
+				OrderCode:     order.OrderCode,
+				EnrollmentId:  enrollment.Id,
+				OriginalPrice: order.OriginalPrice,
+				FinalPrice:    order.FinalPrice,
+			})
+		}
+
+		events = append(events, dto.BundlePurchasedEvent{
+			PurchaseId:       purchase.Id,
+			PurchaseCode:     purchase.PurchaseCode,
+			BundleId:         bundle.Id,
+			BundleTitle:      bundle.Title,
+			BundleSlug:       bundle.Slug,
+			UserId:           userId,
+			CourseIds:        courseIds,
+			SkippedCourseIds: quote.skippedIds,
+			FinalPrice:       purchase.FinalPrice,
+			PaymentMethod:    purchase.PaymentMethod,
+			PaidAt:           now,
+		})
+
+		if err := repos.Outbox.Append(events...); err != nil {
+			return utils.WrapError(err, "Failed to record bundle purchase events", utils.ErrCodeInternal)
+		}
+		return nil
+	})
+	if err != nil {
+		// Đã thu tiền mà không ghi danh được thì hoàn tiền ngay
+		bs.refundPurchase(purchase)
+		return nil, err
+	}
+	purchase.Status = "paid"
+	purchase.PaidAt = &now
+
+	message := fmt.Sprintf("Payment successful! You have been enrolled in %d courses", purchase.CoursesCount)
+	if purchase.FinalPrice == 0 {
+		message = fmt.Sprintf("Congratulations! You have been enrolled in %d courses", purchase.CoursesCount)
+	}
+	if purchase.SkippedCourses > 0 {
+		message += fmt.Sprintf(" (%d courses you already own were skipped)", purchase.SkippedCourses)
+	}
+
+	return &dto.PurchaseBundleResponse{
+		PurchaseId:       purchase.Id,
+		PurchaseCode:     purchase.PurchaseCode,
+		BundleId:         bundle.Id,
+		BundleTitle:      bundle.Title,
+		BundlePrice:      purchase.BundlePrice,
+		OriginalPrice:    purchase.OriginalPrice,
+		FinalPrice:       purchase.FinalPrice,
+		PaymentMethod:    purchase.PaymentMethod,
+		TransactionId:    purchase.TransactionId,
+		PaidAt:           now,
+		Courses:          courseItems,
+		SkippedCourseIds: quote.skippedIds,
+		Message:          message,
+	}, nil
+}
+
+func (bs *bundleService) GetMyPurchases(userId uint, req *dto.GetBundlePurchasesQueryRequest) (*dto.GetBundlePurchasesResponse, error) {
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	purchases, total, err := bs.bundleRepo.GetUserPurchases(userId, offset, limit)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get bundle purchases", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.BundlePurchaseItem, len(purchases))
+	for i, purchase := range purchases {
+		items[i] = dto.BundlePurchaseItem{
+			Id:             purchase.Id,
+			PurchaseCode:   purchase.PurchaseCode,
+			BundleId:       purchase.BundleId,
+			BundleTitle:    purchase.Bundle.Title,
+			BundleSlug:     purchase.Bundle.Slug,
+			BundlePrice:    purchase.BundlePrice,
+			OriginalPrice:  purchase.OriginalPrice,
+			FinalPrice:     purchase.FinalPrice,
+			CoursesCount:   purchase.CoursesCount,
+			SkippedCourses: purchase.SkippedCourses,
+			PaymentMethod:  purchase.PaymentMethod,
+			Status:         purchase.Status,
+			PaidAt:         purchase.PaidAt,
+			CreatedAt:      purchase.CreatedAt,
+		}
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetBundlePurchasesResponse{
+		Purchases: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+// ---------------- Admin ----------------
+func (bs *bundleService) AdminGetBundles(req *dto.AdminGetBundlesQueryRequest) (*dto.GetBundlesResponse, error) {
+	page := 1
+	limit := 20
+	orderBy := "created_at"
+	sortBy := "desc"
+
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	if req.OrderBy != "" {
+		orderBy = req.OrderBy
+	}
+	if req.SortBy != "" {
+		sortBy = req.SortBy
+	}
+
+	offset := (page - 1) * limit
+
+	filters := make(map[string]interface{})
+	if req.Status != "" {
+		filters["status"] = req.Status
+	}
+	if req.Search != "" {
+		filters["search"] = req.Search
+	}
+
+	bundles, total, err := bs.bundleRepo.GetBundles(offset, limit, filters, orderBy, sortBy)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get bundles", utils.ErrCodeInternal)
+	}
+
+	bundleIds := make([]uint, len(bundles))
+	for i, bundle := range bundles {
+		bundleIds[i] = bundle.Id
+	}
+	sales, err := bs.bundleRepo.GetBundleSales(bundleIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get bundle sales", utils.ErrCodeInternal)
+	}
+
+	bundleItems := make([]dto.BundleItem, len(bundles))
+	for i := range bundles {
+		bundleItems[i] = toBundleItem(&bundles[i])
+		stats := sales[bundles[i].Id]
+		bundleItems[i].Sales = &stats
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetBundlesResponse{
+		Bundles: bundleItems,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (bs *bundleService) CreateBundle(userId uint, req *dto.CreateBundleRequest) (*dto.BundleDetail, error) {
+	// 1. Validate giá và danh sách courses
+	if req.DiscountPrice != nil && *req.DiscountPrice >= req.Price {
+		return nil, utils.NewError("discount price must be less than regular price", utils.ErrCodeBadRequest)
+	}
+
+	courseIds, err := bs.validateBundleCourses(req.CourseIds)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Generate slug từ title
+	baseSlug := utils.GenerateSlug(req.Title)
+	uniqueSlug := utils.GenerateUniqueSlug(baseSlug, bs.bundleRepo.ExistsBySlug)
+
+	status := "draft"
+	if req.Status != "" {
+		status = req.Status
+	}
+
+	bundle := &models.Bundle{
+		Title:         req.Title,
+		Slug:          uniqueSlug,
+		Description:   req.Description,
+		ThumbnailURL:  req.ThumbnailURL,
+		Price:         math.Round(req.Price*100) / 100,
+		DiscountPrice: req.DiscountPrice,
+		CreatedBy:     userId,
+		Status:        status,
+	}
+
+	if err := bs.bundleRepo.Create(bundle); err != nil {
+		return nil, utils.WrapError(err, "Failed to create bundle", utils.ErrCodeInternal)
+	}
+
+	// 3. Lưu courses theo thứ tự
+	if err := bs.bundleRepo.ReplaceCourses(bundle.Id, courseIds); err != nil {
+		return nil, utils.WrapError(err, "Failed to save bundle courses", utils.ErrCodeInternal)
+	}
+
+	return bs.getBundleDetail(bundle.Id)
+}
+
+func (bs *bundleService) UpdateBundle(bundleId uint, req *dto.UpdateBundleRequest) (*dto.BundleDetail, error) {
+	// 1. Tìm bundle
+	bundle, err := bs.bundleRepo.FindById(bundleId)
+	if err != nil {
+		return nil, utils.NewError("Bundle not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Chuẩn bị updates
+	updates := make(map[string]interface{})
+
+	if req.Title != nil && *req.Title != bundle.Title {
+		updates["title"] = *req.Title
+		baseSlug := utils.GenerateSlug(*req.Title)
+		updates["slug"] = utils.GenerateUniqueSlug(baseSlug, func(slug string) bool {
+			if slug == bundle.Slug {
+				return false
+			}
+			return bs.bundleRepo.ExistsBySlug(slug)
+		})
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.ThumbnailURL != nil {
+		updates["thumbnail_url"] = *req.ThumbnailURL
+	}
+
+	// 3. Validate discount price theo giá mới (nếu có)
+	price := bundle.Price
+	if req.Price != nil {
+		price = math.Round(*req.Price*100) / 100
+		updates["price"] = price
+	}
+	discountPrice := bundle.DiscountPrice
+	if req.DiscountPrice != nil {
+		discountPrice = req.DiscountPrice
+		updates["discount_price"] = req.DiscountPrice
+	}
+	if discountPrice != nil && *discountPrice >= price {
+		return nil, utils.NewError("discount price must be less than regular price", utils.ErrCodeBadRequest)
+	}
+
+	if req.Status != nil {
+		if *req.Status == "published" {
+			if _, err := bs.validateBundleCourses(bundleCourseIds(bundle)); err != nil {
+				return nil, err
+			}
+		}
+		updates["status"] = *req.Status
+	}
+
+	if len(updates) == 0 {
+		return nil, utils.NewError("No fields to update", utils.ErrCodeBadRequest)
+	}
+
+	// 4. Update
+	if err := bs.bundleRepo.Update(bundleId, updates); err != nil {
+		return nil, utils.WrapError(err, "Failed to update bundle", utils.ErrCodeInternal)
+	}
+
+	return bs.getBundleDetail(bundleId)
+}
+
+func (bs *bundleService) SetBundleCourses(bundleId uint, req *dto.SetBundleCoursesRequest) (*dto.BundleDetail, error) {
+	// 1. Kiểm tra bundle tồn tại
+	if _, err := bs.bundleRepo.FindById(bundleId); err != nil {
+		return nil, utils.NewError("Bundle not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Validate courses
+	courseIds, err := bs.validateBundleCourses(req.CourseIds)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Thay thế danh sách courses (purchase cũ giữ nguyên order đã tạo)
+	if err := bs.bundleRepo.ReplaceCourses(bundleId, courseIds); err != nil {
+		return nil, utils.WrapError(err, "Failed to update bundle courses", utils.ErrCodeInternal)
+	}
+
+	return bs.getBundleDetail(bundleId)
+}
+
+func (bs *bundleService) DeleteBundle(bundleId uint) (*dto.DeleteBundleResponse, error) {
+	if _, err := bs.bundleRepo.FindById(bundleId); err != nil {
+		return nil, utils.NewError("Bundle not found", utils.ErrCodeNotFound)
+	}
+
+	if err := bs.bundleRepo.Delete(bundleId); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete bundle", utils.ErrCodeInternal)
+	}
+
+	return &dto.DeleteBundleResponse{
+		Message: "Bundle deleted successfully",
+	}, nil
+}
+
+// claimPurchase tạo purchase pending; đã có lần mua khác đang xử lý cho bundle này thì trả về conflict.
+// Purchase pending quá bundleClaimTTL (request bị ngắt giữa chừng) được chuyển sang expired trước,
+// giao dịch của nó (nếu có) đối soát với gateway theo purchase_code.
+func (bs *bundleService) claimPurchase(purchase *models.BundlePurchase) error {
+	if err := bs.bundleRepo.ExpirePendingPurchases(purchase.UserId, purchase.BundleId, time.Now().Add(-bundleClaimTTL)); err != nil {
+		return utils.WrapError(err, "Failed to check bundle purchase", utils.ErrCodeInternal)
+	}
+
+	pending, err := bs.bundleRepo.HasPendingPurchase(purchase.UserId, purchase.BundleId)
+	if err != nil {
+		return utils.WrapError(err, "Failed to check bundle purchase", utils.ErrCodeInternal)
+	}
+	if !pending {
+		err = bs.bundleRepo.CreatePurchase(purchase)
+		if err == nil {
+			return nil
+		}
+		// Lỗi do unique index pending (hai request chạy đồng thời) thì trả về conflict như trên
+		if pending, _ = bs.bundleRepo.HasPendingPurchase(purchase.UserId, purchase.BundleId); !pending {
+			return utils.WrapError(err, "Failed to create bundle purchase", utils.ErrCodeInternal)
+		}
+	}
+	return utils.NewError("A purchase of this bundle is already in progress", utils.ErrCodeConflict)
+}
+
+// validatePurchase tính giá và kiểm tra prerequisites
+// (course nằm trong bundle xem như đã đáp ứng vì mua cùng lúc)
+func (bs *bundleService) validatePurchase(userId uint, bundle *models.Bundle) (*bundleQuote, error) {
+	quote, err := bs.buildQuote(userId, bundle)
+	if err != nil {
+		return nil, err
+	}
+	if len(quote.skippedIds) == len(quote.lines) {
+		return nil, utils.NewError("You already own every course in this bundle", utils.ErrCodeConflict)
+	}
+
+	inBundle := make(map[uint]bool)
+	for _, line := range quote.lines {
+		inBundle[line.course.Id] = true
+	}
+	for _, line := range quote.lines {
+		if line.owned {
+			continue
+		}
+		_, missing, err := checkCoursePrerequisites(bs.courseRepo, bs.enrollmentRepo, userId, line.course)
+		if err != nil {
+			return nil, err
+		}
+		outside := make([]dto.CoursePrerequisiteItem, 0, len(missing))
+		for _, item := range missing {
+			if !inBundle[item.CourseId] {
+				outside = append(outside, item)
+			}
+		}
+		if err := enforcePrerequisites(line.course, outside); err != nil {
+			return nil, err
+		}
+	}
+
+	return quote, nil
+}
+
+// finishPurchase đóng purchase pending với trạng thái cuối (failed, refunded, refund_pending)
+func (bs *bundleService) finishPurchase(purchase *models.BundlePurchase, status string) {
+	purchase.Status = status
+	if err := bs.bundleRepo.UpdatePurchase(purchase.Id, map[string]interface{}{
+		"final_price":    purchase.FinalPrice,
+		"payment_method": purchase.PaymentMethod,
+		"transaction_id": purchase.TransactionId,
+		"status":         status,
+	}); err != nil {
+		log.Printf("Failed to mark bundle purchase %s as %s: %v", purchase.PurchaseCode, status, err)
+	}
+}
+
+// refundPurchase hoàn giao dịch của purchase không ghi danh được. Gateway lỗi thì giữ lại
+// transaction_id với trạng thái refund_pending để xử lý hoàn tiền thủ công.
+func (bs *bundleService) refundPurchase(purchase *models.BundlePurchase) {
+	if purchase.TransactionId == "" {
+		bs.finishPurchase(purchase, "failed")
+		return
+	}
+
+	if err := bs.paymentGateway.Refund(purchase.TransactionId, purchase.FinalPrice); err != nil {
+		log.Printf("Failed to refund bundle purchase %s (transaction %s): %v", purchase.PurchaseCode, purchase.TransactionId, err)
+		bs.finishPurchase(purchase, "refund_pending")
+		return
+	}
+	bs.finishPurchase(purchase, "refunded")
+}
+
+// buildQuote xác định course user đã sở hữu và phân bổ giá bundle theo tỷ lệ giá lẻ của từng course.
+// User chỉ trả phần giá bundle ứng với các course chưa sở hữu; tỷ lệ không vượt quá 1
+// để mua bundle không bao giờ đắt hơn mua lẻ.
+func (bs *bundleService) buildQuote(userId uint, bundle *models.Bundle) (*bundleQuote, error) {
+	quote := &bundleQuote{
+		bundlePrice: sellingPrice(bundle.Price, bundle.DiscountPrice),
+		lines:       make([]bundleQuoteLine, len(bundle.Courses)),
+		skippedIds:  make([]uint, 0),
+	}
+
+	// 1. Mọi course trong bundle phải còn đang bán
+	listTotal := 0.0
+	for i := range bundle.Courses {
+		course := &bundle.Courses[i].Course
+		if course.Id == 0 || course.Status != "published" {
+			return nil, utils.NewError("This bundle is currently unavailable", utils.ErrCodeBadRequest)
+		}
+
+		line := bundleQuoteLine{
+			course:    course,
+			listPrice: sellingPrice(course.Price, course.DiscountPrice),
+		}
+
+		// 2. Course đã sở hữu (mua lẻ, organization...) thì bỏ qua; enrollment đã drop
+		// hoặc ghi danh qua subscription vẫn được mua và dùng lại
+		if enrollment, exists := bs.enrollmentRepo.CheckEnrollment(userId, course.Id); exists {
+			if enrollment.Status != "dropped" && enrollment.SubscriptionId == nil {
+				line.owned = true
+				quote.skippedIds = append(quote.skippedIds, course.Id)
+			} else {
+				line.existing = enrollment
+			}
+		}
+
+		listTotal += line.listPrice
+		quote.lines[i] = line
+	}
+
+	ratio := 0.0
+	if listTotal > 0 {
+		ratio = math.Min(1, quote.bundlePrice/listTotal)
+	}
+
+	// 3. Phân bổ, phần lẻ do làm tròn dồn vào course cuối để tổng khớp số tiền thu
+	remainingList := 0.0
+	for _, line := range quote.lines {
+		if !line.owned {
+			remainingList += line.listPrice
+		}
+	}
+	quote.originalPrice = math.Round(remainingList*100) / 100
+	quote.finalPrice = math.Round(remainingList*ratio*100) / 100
+
+	allocated := 0.0
+	lastIndex := -1
+	for i := range quote.lines {
+		if quote.lines[i].owned {
+			continue
+		}
+		quote.lines[i].price = math.Round(quote.lines[i].listPrice*ratio*100) / 100
+		allocated += quote.lines[i].price
+		if quote.lines[i].listPrice > 0 {
+			lastIndex = i
+		}
+	}
+	if lastIndex >= 0 {
+		quote.lines[lastIndex].price = math.Round((quote.lines[lastIndex].price+quote.finalPrice-allocated)*100) / 100
+	}
+
+	return quote, nil
+}
+
+// validateBundleCourses kiểm tra courses không trùng và đã published
+func (bs *bundleService) validateBundleCourses(courseIds []uint) ([]uint, error) {
+	uniqueIds := make([]uint, 0, len(courseIds))
+	seen := make(map[uint]bool)
+	for _, courseId := range courseIds {
+		if seen[courseId] {
+			return nil, utils.NewError("Duplicate courses in bundle", utils.ErrCodeBadRequest)
+		}
+		seen[courseId] = true
+		uniqueIds = append(uniqueIds, courseId)
+	}
+	if len(uniqueIds) < 2 {
+		return nil, utils.NewError("A bundle must contain at least 2 courses", utils.ErrCodeBadRequest)
+	}
+
+	courses, err := bs.courseRepo.FindByIds(uniqueIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get courses", utils.ErrCodeInternal)
+	}
+
+	courseMap := make(map[uint]models.Course)
+	for _, course := range courses {
+		courseMap[course.Id] = course
+	}
+
+	for _, courseId := range uniqueIds {
+		course, ok := courseMap[courseId]
+		if !ok || course.Status != "published" {
+			return nil, utils.NewError(fmt.Sprintf("Course %d not found or not published", courseId), utils.ErrCodeBadRequest)
+		}
+	}
+
+	return uniqueIds, nil
+}
+
+func (bs *bundleService) getBundleDetail(bundleId uint) (*dto.BundleDetail, error) {
+	bundle, err := bs.bundleRepo.FindById(bundleId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get bundle", utils.ErrCodeInternal)
+	}
+
+	return toBundleDetail(bundle), nil
+}
+
+// sellingPrice trả về giá bán thực tế (giá giảm nếu thấp hơn giá gốc)
+func sellingPrice(price float64, discountPrice *float64) float64 {
+	if discountPrice != nil && *discountPrice < price {
+		return *discountPrice
+	}
+	return price
+}
+
+func bundleCourseIds(bundle *models.Bundle) []uint {
+	courseIds := make([]uint, len(bundle.Courses))
+	for i, item := range bundle.Courses {
+		courseIds[i] = item.CourseId
+	}
+	return courseIds
+}
+
+// bundleOriginalPrice là tổng giá lẻ các course trong bundle
+func bundleOriginalPrice(bundle *models.Bundle) float64 {
+	total := 0.0
+	for _, item := range bundle.Courses {
+		total += sellingPrice(item.Course.Price, item.Course.DiscountPrice)
+	}
+	return math.Round(total*100) / 100
+}
+
+func toBundleItem(bundle *models.Bundle) dto.BundleItem {
+	return dto.BundleItem{
+		Id:            bundle.Id,
+		Title:         bundle.Title,
+		Slug:          bundle.Slug,
+		Description:   bundle.Description,
+		ThumbnailURL:  bundle.ThumbnailURL,
+		Price:         bundle.Price,
+		DiscountPrice: bundle.DiscountPrice,
+		OriginalPrice: bundleOriginalPrice(bundle),
+		TotalCourses:  len(bundle.Courses),
+		Status:        bundle.Status,
+		CreatedAt:     bundle.CreatedAt,
+	}
+}
+
+func toBundleDetail(bundle *models.Bundle) *dto.BundleDetail {
+	courses := make([]dto.BundleCourseItem, len(bundle.Courses))
+	for i, item := range bundle.Courses {
+		courses[i] = dto.BundleCourseItem{
+			CourseId:       item.CourseId,
+			Position:       item.Position,
+			Title:          item.Course.Title,
+			Slug:           item.Course.Slug,
+			ThumbnailURL:   item.Course.ThumbnailURL,
+			InstructorId:   item.Course.InstructorId,
+			InstructorName: item.Course.Instructor.FullName,
+			Level:          item.Course.Level,
+			Price:          item.Course.Price,
+			DiscountPrice:  item.Course.DiscountPrice,
+			DurationHours:  item.Course.DurationHours,
+		}
+	}
+
+	originalPrice := bundleOriginalPrice(bundle)
+	savings := originalPrice - sellingPrice(bundle.Price, bundle.DiscountPrice)
+	if savings < 0 {
+		savings = 0
+	}
+
+	return &dto.BundleDetail{
+		Id:            bundle.Id,
+		Title:         bundle.Title,
+		Slug:          bundle.Slug,
+		Description:   bundle.Description,
+		ThumbnailURL:  bundle.ThumbnailURL,
+		Price:         bundle.Price,
+		DiscountPrice: bundle.DiscountPrice,
+		OriginalPrice: originalPrice,
+		Savings:       math.Round(savings*100) / 100,
+		Status:        bundle.Status,
+		CreatedBy:     bundle.CreatedBy,
+		Courses:       courses,
+		CreatedAt:     bundle.CreatedAt,
+		UpdatedAt:     bundle.UpdatedAt,
+	}
+}
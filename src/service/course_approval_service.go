@@ -0,0 +1,430 @@
+package service
+
+import (
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Hành động ghi vào lịch sử trạng thái course
+const (
+	courseActionSubmitted     = "submitted"
+	courseActionWithdrawn     = "withdrawn"
+	courseActionApproved      = "approved"
+	courseActionRejected      = "rejected"
+	courseActionStatusChanged = "status_changed"
+)
+
+// courseChecklist là các điều kiện tối thiểu để course được gửi duyệt và publish
+type courseChecklist struct {
+	minLessons           int
+	minDescriptionLength int
+}
+
+// loadCourseChecklist đọc COURSE_REVIEW_MIN_LESSONS (mặc định 3) và COURSE_REVIEW_MIN_DESCRIPTION_LENGTH (mặc định 200)
+func loadCourseChecklist() courseChecklist {
+	minLessons, err := strconv.Atoi(utils.GetEnv("COURSE_REVIEW_MIN_LESSONS", "3"))
+	if err != nil || minLessons < 1 {
+		minLessons = 3
+	}
+
+	minDescriptionLength, err := strconv.Atoi(utils.GetEnv("COURSE_REVIEW_MIN_DESCRIPTION_LENGTH", "200"))
+	if err != nil || minDescriptionLength < 0 {
+		minDescriptionLength = 200
+	}
+
+	return courseChecklist{
+		minLessons:           minLessons,
+		minDescriptionLength: minDescriptionLength,
+	}
+}
+
+func (cc courseChecklist) evaluate(course *models.Course, lessonCount int) dto.CourseReviewChecklist {
+	descriptionLength := utf8.RuneCountInString(strings.TrimSpace(utils.HTMLToText(course.Description)))
+
+	items := []dto.CourseChecklistItem{
+		{
+			Key:    "min_lessons",
+			Label:  fmt.Sprintf("At least %d published lessons", cc.minLessons),
+			Passed: lessonCount >= cc.minLessons,
+			Detail: fmt.Sprintf("%d published lessons", lessonCount),
+		},
+		{
+			Key:    "thumbnail",
+			Label:  "Course thumbnail uploaded",
+			Passed: course.ThumbnailURL != "",
+		},
+		{
+			Key:    "description_length",
+			Label:  fmt.Sprintf("Description has at least %d characters", cc.minDescriptionLength),
+			Passed: descriptionLength >= cc.minDescriptionLength,
+			Detail: fmt.Sprintf("%d characters", descriptionLength),
+		},
+	}
+
+	passed := true
+	for _, item := range items {
+		passed = passed && item.Passed
+	}
+
+	return dto.CourseReviewChecklist{Passed: passed, Items: items}
+}
+
+// failedChecklistItems trả về nhãn các điều kiện chưa đạt để đưa vào thông báo lỗi
+func failedChecklistItems(checklist dto.CourseReviewChecklist) string {
+	var failed []string
+	for _, item := range checklist.Items {
+		if !item.Passed {
+			failed = append(failed, item.Label)
+		}
+	}
+	return strings.Join(failed, "; ")
+}
+
+type courseApprovalService struct {
+	approvalRepo repository.CourseApprovalRepository
+	courseRepo   repository.CourseRepository
+	notifier     Notifier
+	checklist    courseChecklist
+}
+
+func NewCourseApprovalService(approvalRepo repository.CourseApprovalRepository, courseRepo repository.CourseRepository, notifier Notifier) CourseApprovalService {
+	return &courseApprovalService{
+		approvalRepo: approvalRepo,
+		courseRepo:   courseRepo,
+		notifier:     notifier,
+		checklist:    loadCourseChecklist(),
+	}
+}
+
+func (cs *courseApprovalService) GetReviewStatus(instructorId, courseId uint) (*dto.CourseReviewStatusResponse, error) {
+	course, err := cs.findInstructorCourse(instructorId, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	checklist, err := cs.evaluateChecklist(course)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.CourseReviewStatusResponse{
+		CourseId:       course.Id,
+		Title:          course.Title,
+		Status:         course.Status,
+		SubmittedAt:    course.SubmittedAt,
+		ReviewedAt:     course.ReviewedAt,
+		ReviewFeedback: course.ReviewFeedback,
+		Checklist:      *checklist,
+	}, nil
+}
+
+func (cs *courseApprovalService) SubmitForReview(instructorId, courseId uint, req *dto.SubmitCourseReviewRequest) (*dto.CourseReviewActionResponse, error) {
+	// 1. Kiểm tra quyền sở hữu và trạng thái
+	course, err := cs.findInstructorCourse(instructorId, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	if course.Status != "draft" {
+		return nil, utils.NewError(fmt.Sprintf("only draft courses can be submitted for review (current status: %s)", course.Status), utils.ErrCodeBadRequest)
+	}
+
+	// 2. Course phải đạt checklist
+	checklist, err := cs.evaluateChecklist(course)
+	if err != nil {
+		return nil, err
+	}
+	if !checklist.Passed {
+		return nil, utils.NewError("course does not meet the review checklist: "+failedChecklistItems(*checklist), utils.ErrCodeBadRequest)
+	}
+
+	// 3. Chuyển sang pending_review
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":       "pending_review",
+		"submitted_at": now,
+	}
+	history := &models.CourseStatusHistory{
+		FromStatus: course.Status,
+		ToStatus:   "pending_review",
+		Action:     courseActionSubmitted,
+		Comment:    strings.TrimSpace(req.Note),
+		ChangedBy:  instructorId,
+	}
+	if err := cs.approvalRepo.ChangeStatus(courseId, updates, history); err != nil {
+		return nil, utils.WrapError(err, "failed to submit course for review", utils.ErrCodeInternal)
+	}
+
+	return &dto.CourseReviewActionResponse{
+		CourseId: courseId,
+		Status:   "pending_review",
+		Message:  "Course submitted for review",
+	}, nil
+}
+
+func (cs *courseApprovalService) WithdrawSubmission(instructorId, courseId uint) (*dto.CourseReviewActionResponse, error) {
+	course, err := cs.findInstructorCourse(instructorId, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	if course.Status != "pending_review" {
+		return nil, utils.NewError("course is not pending review", utils.ErrCodeBadRequest)
+	}
+
+	updates := map[string]interface{}{
+		"status":       "draft",
+		"submitted_at": nil,
+	}
+	history := &models.CourseStatusHistory{
+		FromStatus: course.Status,
+		ToStatus:   "draft",
+		Action:     courseActionWithdrawn,
+		ChangedBy:  instructorId,
+	}
+	if err := cs.approvalRepo.ChangeStatus(courseId, updates, history); err != nil {
+		return nil, utils.WrapError(err, "failed to withdraw course submission", utils.ErrCodeInternal)
+	}
+
+	return &dto.CourseReviewActionResponse{
+		CourseId: courseId,
+		Status:   "draft",
+		Message:  "Course submission withdrawn",
+	}, nil
+}
+
+func (cs *courseApprovalService) GetInstructorStatusHistory(instructorId, courseId uint) (*dto.GetCourseStatusHistoryResponse, error) {
+	if _, err := cs.findInstructorCourse(instructorId, courseId); err != nil {
+		return nil, err
+	}
+
+	return cs.getStatusHistory(courseId)
+}
+
+func (cs *courseApprovalService) GetReviewQueue(req *dto.GetCourseReviewQueueQueryRequest) (*dto.GetCourseReviewQueueResponse, error) {
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	filters := make(map[string]interface{})
+	if req.Search != "" {
+		filters["search"] = req.Search
+	}
+	if req.CategoryId != 0 {
+		filters["category_id"] = req.CategoryId
+	}
+
+	courses, total, err := cs.approvalRepo.GetReviewQueue(offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to get course review queue", utils.ErrCodeInternal)
+	}
+
+	courseIds := make([]uint, len(courses))
+	for i := range courses {
+		courseIds[i] = courses[i].Id
+	}
+	lessonCounts, err := cs.approvalRepo.CountLessons(courseIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to count lessons", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.CourseReviewQueueItem, len(courses))
+	for i := range courses {
+		course := &courses[i]
+		items[i] = dto.CourseReviewQueueItem{
+			Id:             course.Id,
+			Title:          course.Title,
+			Slug:           course.Slug,
+			ThumbnailURL:   course.ThumbnailURL,
+			InstructorId:   course.InstructorId,
+			InstructorName: course.Instructor.FullName,
+			CategoryId:     course.CategoryId,
+			CategoryName:   course.Category.Name,
+			Price:          course.Price,
+			LessonCount:    lessonCounts[course.Id],
+			SubmittedAt:    course.SubmittedAt,
+			Checklist:      cs.checklist.evaluate(course, lessonCounts[course.Id]),
+		}
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetCourseReviewQueueResponse{
+		Courses: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (cs *courseApprovalService) ApproveCourse(adminId, courseId uint, req *dto.ApproveCourseRequest) (*dto.CourseReviewActionResponse, error) {
+	// 1. Course phải đang chờ duyệt
+	course, err := cs.findPendingCourse(courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Kiểm tra lại checklist (instructor có thể đã sửa course sau khi gửi)
+	checklist, err := cs.evaluateChecklist(course)
+	if err != nil {
+		return nil, err
+	}
+	if !checklist.Passed {
+		return nil, utils.NewError("course does not meet the review checklist: "+failedChecklistItems(*checklist), utils.ErrCodeBadRequest)
+	}
+
+	// 3. Publish course và ghi lịch sử
+	comment := strings.TrimSpace(req.Comment)
+	updates := map[string]interface{}{
+		"status":          "published",
+		"reviewed_by":     adminId,
+		"reviewed_at":     time.Now(),
+		"review_feedback": comment,
+	}
+	history := &models.CourseStatusHistory{
+		FromStatus: course.Status,
+		ToStatus:   "published",
+		Action:     courseActionApproved,
+		Comment:    comment,
+		ChangedBy:  adminId,
+	}
+	if err := cs.approvalRepo.ChangeStatus(courseId, updates, history); err != nil {
+		return nil, utils.WrapError(err, "failed to approve course", utils.ErrCodeInternal)
+	}
+
+	// 4. Báo cho instructor
+	cs.notifier.Notify(course.InstructorId, notificationCourseReviewed,
+		"Course approved",
+		fmt.Sprintf("\"%s\" has been approved and is now published.", course.Title),
+		fmt.Sprintf("/courses/%s", course.Slug),
+	)
+
+	return &dto.CourseReviewActionResponse{
+		CourseId: courseId,
+		Status:   "published",
+		Message:  "Course approved and published",
+	}, nil
+}
+
+func (cs *courseApprovalService) RejectCourse(adminId, courseId uint, req *dto.RejectCourseRequest) (*dto.CourseReviewActionResponse, error) {
+	// 1. Course phải đang chờ duyệt
+	course, err := cs.findPendingCourse(courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Trả course về draft kèm phản hồi cho instructor
+	comment := strings.TrimSpace(req.Comment)
+	updates := map[string]interface{}{
+		"status":          "draft",
+		"submitted_at":    nil,
+		"reviewed_by":     adminId,
+		"reviewed_at":     time.Now(),
+		"review_feedback": comment,
+	}
+	history := &models.CourseStatusHistory{
+		FromStatus: course.Status,
+		ToStatus:   "draft",
+		Action:     courseActionRejected,
+		Comment:    comment,
+		ChangedBy:  adminId,
+	}
+	if err := cs.approvalRepo.ChangeStatus(courseId, updates, history); err != nil {
+		return nil, utils.WrapError(err, "failed to reject course", utils.ErrCodeInternal)
+	}
+
+	// 3. Báo cho instructor
+	cs.notifier.Notify(course.InstructorId, notificationCourseReviewed,
+		"Course changes requested",
+		fmt.Sprintf("\"%s\" was not approved: %s", course.Title, comment),
+		fmt.Sprintf("/instructor/courses/%d/review", course.Id),
+	)
+
+	return &dto.CourseReviewActionResponse{
+		CourseId: courseId,
+		Status:   "draft",
+		Message:  "Course rejected and returned to draft",
+	}, nil
+}
+
+func (cs *courseApprovalService) GetStatusHistory(courseId uint) (*dto.GetCourseStatusHistoryResponse, error) {
+	if _, err := cs.courseRepo.FindById(courseId); err != nil {
+		return nil, utils.NewError("course not found", utils.ErrCodeNotFound)
+	}
+
+	return cs.getStatusHistory(courseId)
+}
+
+func (cs *courseApprovalService) getStatusHistory(courseId uint) (*dto.GetCourseStatusHistoryResponse, error) {
+	history, err := cs.approvalRepo.GetStatusHistory(courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to get course status history", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.CourseStatusHistoryItem, len(history))
+	for i, entry := range history {
+		items[i] = dto.CourseStatusHistoryItem{
+			Id:            entry.Id,
+			FromStatus:    entry.FromStatus,
+			ToStatus:      entry.ToStatus,
+			Action:        entry.Action,
+			Comment:       entry.Comment,
+			ChangedBy:     entry.ChangedBy,
+			ChangedByName: entry.ChangedByUser.FullName,
+			CreatedAt:     entry.CreatedAt,
+		}
+	}
+
+	return &dto.GetCourseStatusHistoryResponse{
+		CourseId: courseId,
+		History:  items,
+	}, nil
+}
+
+func (cs *courseApprovalService) evaluateChecklist(course *models.Course) (*dto.CourseReviewChecklist, error) {
+	lessonCounts, err := cs.approvalRepo.CountLessons([]uint{course.Id})
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to count lessons", utils.ErrCodeInternal)
+	}
+
+	checklist := cs.checklist.evaluate(course, lessonCounts[course.Id])
+	return &checklist, nil
+}
+
+func (cs *courseApprovalService) findInstructorCourse(instructorId, courseId uint) (*models.Course, error) {
+	course, err := cs.courseRepo.FindById(courseId)
+	if err != nil || course.InstructorId != instructorId {
+		return nil, utils.NewError("course not found or you don't have permission to access this course", utils.ErrCodeNotFound)
+	}
+	return course, nil
+}
+
+func (cs *courseApprovalService) findPendingCourse(courseId uint) (*models.Course, error) {
+	course, err := cs.courseRepo.FindById(courseId)
+	if err != nil {
+		return nil, utils.NewError("course not found", utils.ErrCodeNotFound)
+	}
+
+	if course.Status != "pending_review" {
+		return nil, utils.NewError(fmt.Sprintf("course is not pending review (current status: %s)", course.Status), utils.ErrCodeBadRequest)
+	}
+	return course, nil
+}
@@ -0,0 +1,898 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// coursePackageFormatVersion là version manifest mà server export ra
+	coursePackageFormatVersion = 1
+	coursePackageManifestName  = "manifest.json"
+	coursePackageMaxManifest   = 10 << 20
+	coursePackageMaxContent    = 5 << 20
+)
+
+// coursePackageUpgrades nâng manifest (JSON thô) từ version N lên N+1 để gói export cũ vẫn import được.
+// Khi đổi format: tăng coursePackageFormatVersion và thêm hàm nâng cấp cho version cũ vào đây.
+var coursePackageUpgrades = map[int]func(manifest map[string]interface{}) error{}
+
+var (
+	coursePackageLevels            = map[string]bool{"beginner": true, "intermediate": true, "advanced": true}
+	coursePackageLanguages         = map[string]bool{"vi": true, "en": true}
+	coursePackagePrerequisiteModes = map[string]bool{"": true, "warn": true, "block": true}
+)
+
+// CoursePackageError là lỗi của gói import, liệt kê từng vấn đề kèm vị trí trong manifest/zip
+type CoursePackageError struct {
+	Problems []dto.CoursePackageProblem
+}
+
+func (e *CoursePackageError) Error() string {
+	return fmt.Sprintf("invalid course package: %d problem(s)", len(e.Problems))
+}
+
+type packageProblems []dto.CoursePackageProblem
+
+func (pp *packageProblems) add(path, format string, args ...interface{}) {
+	*pp = append(*pp, dto.CoursePackageProblem{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (pp packageProblems) err() error {
+	if len(pp) == 0 {
+		return nil
+	}
+	return &CoursePackageError{Problems: pp}
+}
+
+type coursePackageService struct {
+	templateRepo repository.CourseTemplateRepository
+	courseRepo   repository.CourseRepository
+	categoryRepo repository.CategoryRepository
+	transactor   repository.Transactor
+	store        storage.Storage
+}
+
+func NewCoursePackageService(
+	templateRepo repository.CourseTemplateRepository,
+	courseRepo repository.CourseRepository,
+	categoryRepo repository.CategoryRepository,
+	transactor repository.Transactor,
+	store storage.Storage,
+) CoursePackageService {
+	return &coursePackageService{
+		templateRepo: templateRepo,
+		courseRepo:   courseRepo,
+		categoryRepo: categoryRepo,
+		transactor:   transactor,
+		store:        store,
+	}
+}
+
+// ---------------- Export ----------------
+
+func (ps *coursePackageService) ExportCourse(instructorId, courseId uint) (*dto.CoursePackageExport, error) {
+	course, err := ps.courseRepo.FindById(courseId)
+	if err != nil || course.InstructorId != instructorId {
+		return nil, utils.NewError("course not found or you don't have permission to export this course", utils.ErrCodeNotFound)
+	}
+	return ps.exportCourse(course)
+}
+
+func (ps *coursePackageService) AdminExportCourse(courseId uint) (*dto.CoursePackageExport, error) {
+	course, err := ps.courseRepo.FindById(courseId)
+	if err != nil {
+		return nil, utils.NewError("course not found", utils.ErrCodeNotFound)
+	}
+	return ps.exportCourse(course)
+}
+
+// exportCourse ghi gói zip ra file tạm: manifest.json, nội dung lesson (lessons/) và file từ storage (assets/)
+func (ps *coursePackageService) exportCourse(course *models.Course) (*dto.CoursePackageExport, error) {
+	// 1. Đọc nội dung course
+	lessons, err := ps.templateRepo.FindLessons(course.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to load lessons", utils.ErrCodeInternal)
+	}
+
+	quizzes, err := ps.templateRepo.FindQuizzes(course.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to load quizzes", utils.ErrCodeInternal)
+	}
+
+	lessonIds := make([]uint, len(lessons))
+	for i := range lessons {
+		lessonIds[i] = lessons[i].Id
+	}
+	attachments, err := ps.templateRepo.FindAttachments(lessonIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to load attachments", utils.ErrCodeInternal)
+	}
+
+	attachmentsByLesson := make(map[uint][]models.LessonAttachment)
+	for _, attachment := range attachments {
+		attachmentsByLesson[attachment.LessonId] = append(attachmentsByLesson[attachment.LessonId], attachment)
+	}
+
+	// 2. Ghi zip ra file tạm để không giữ cả gói trong bộ nhớ
+	tmp, err := os.CreateTemp("", "course-package-*.zip")
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to create export file", utils.ErrCodeInternal)
+	}
+	cleanup := func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+
+	zw := zip.NewWriter(tmp)
+	manifest := dto.CoursePackageManifest{
+		FormatVersion: coursePackageFormatVersion,
+		ExportedAt:    time.Now().UTC(),
+		Course: dto.CoursePackageCourse{
+			Title:            course.Title,
+			Slug:             course.Slug,
+			ShortDesc:        course.ShortDesc,
+			Description:      course.Description,
+			Category:         dto.CoursePackageCategory{Slug: course.Category.Slug, Name: course.Category.Name},
+			Level:            course.Level,
+			Language:         course.Language,
+			Price:            course.Price,
+			DiscountPrice:    course.DiscountPrice,
+			Requirements:     course.Requirements,
+			WhatYouLearn:     course.WhatYouLearn,
+			DurationHours:    course.DurationHours,
+			PrerequisiteMode: course.PrerequisiteMode,
+			VideoPreviewURL:  course.VideoPreviewURL,
+		},
+		Lessons: make([]dto.CoursePackageLesson, 0, len(lessons)),
+		Quizzes: make([]dto.CoursePackageQuiz, 0, len(quizzes)),
+	}
+
+	// 2.1 Thumbnail (chỉ ảnh nằm trong thumbnails/ trên storage; URL bên ngoài không được đóng gói)
+	if key, ok := storage.KeyFromURL(ps.store, course.ThumbnailURL); ok && strings.HasPrefix(key, "thumbnails/") {
+		ext := strings.ToLower(path.Ext(key))
+		asset, err := ps.writeStorageAsset(zw, "assets/thumbnail"+ext, key)
+		if err != nil {
+			cleanup()
+			return nil, utils.WrapError(err, "failed to export thumbnail", utils.ErrCodeInternal)
+		}
+		asset.FileName = "thumbnail" + ext
+		manifest.Course.Thumbnail = asset
+	}
+
+	// 2.2 Lessons: id trong DB được thay bằng ref để gói độc lập với hệ thống nguồn (quiz của lesson đã xóa bị bỏ qua)
+	lessonRefs := make(map[uint]string, len(lessons))
+	for i := range lessons {
+		lessonRefs[lessons[i].Id] = fmt.Sprintf("lesson-%d", i+1)
+	}
+	quizRefs := make(map[uint]string, len(quizzes))
+	for i := range quizzes {
+		if _, ok := lessonRefs[quizzes[i].LessonId]; ok {
+			quizRefs[quizzes[i].Id] = fmt.Sprintf("quiz-%d", len(quizRefs)+1)
+		}
+	}
+
+	for i := range lessons {
+		lesson := &lessons[i]
+		item := dto.CoursePackageLesson{
+			Ref:                       lessonRefs[lesson.Id],
+			Title:                     lesson.Title,
+			Slug:                      lesson.Slug,
+			Description:               lesson.Description,
+			VideoURL:                  lesson.VideoURL,
+			VideoDuration:             lesson.VideoDuration,
+			IsPreview:                 lesson.IsPreview,
+			IsPublished:               lesson.IsPublished,
+			UnlockAfterDays:           lesson.UnlockAfterDays,
+			UnlockAt:                  lesson.UnlockAt,
+			RequirePreviousCompletion: lesson.RequirePreviousCompletion,
+			Attachments:               []dto.CoursePackageAttachment{},
+		}
+		if lesson.PrerequisiteQuizId != nil {
+			item.PrerequisiteQuizRef = quizRefs[*lesson.PrerequisiteQuizId]
+		}
+
+		dir := fmt.Sprintf("lessons/%03d", i+1)
+		if lesson.Content != "" {
+			item.ContentPath = dir + "/content.html"
+			if err := writeZipFile(zw, item.ContentPath, []byte(lesson.Content)); err != nil {
+				cleanup()
+				return nil, utils.WrapError(err, "failed to export lesson content", utils.ErrCodeInternal)
+			}
+		}
+
+		for j, attachment := range attachmentsByLesson[lesson.Id] {
+			name := fmt.Sprintf("%s/attachments/%02d%s", dir, j+1, strings.ToLower(path.Ext(attachment.FileName)))
+			asset, err := ps.writeStorageAsset(zw, name, attachment.StorageKey)
+			if err != nil {
+				cleanup()
+				return nil, utils.WrapError(err, fmt.Sprintf("failed to export attachment %q", attachment.FileName), utils.ErrCodeInternal)
+			}
+			asset.FileName = attachment.FileName
+			asset.MimeType = attachment.MimeType
+			item.Attachments = append(item.Attachments, dto.CoursePackageAttachment{Title: attachment.Title, CoursePackageAsset: *asset})
+		}
+
+		manifest.Lessons = append(manifest.Lessons, item)
+	}
+
+	// 2.3 Quizzes
+	for i := range quizzes {
+		quiz := &quizzes[i]
+		quizRef, ok := quizRefs[quiz.Id]
+		if !ok {
+			continue
+		}
+
+		questions := make([]dto.CoursePackageQuestion, len(quiz.Questions))
+		for j, question := range quiz.Questions {
+			var options []string
+			_ = json.Unmarshal([]byte(question.Options), &options)
+			questions[j] = dto.CoursePackageQuestion{
+				Question:      question.Question,
+				Options:       options,
+				CorrectOption: question.CorrectOption,
+			}
+		}
+
+		manifest.Quizzes = append(manifest.Quizzes, dto.CoursePackageQuiz{
+			Ref:          quizRef,
+			LessonRef:    lessonRefs[quiz.LessonId],
+			Title:        quiz.Title,
+			Description:  quiz.Description,
+			PassingScore: quiz.PassingScore,
+			Questions:    questions,
+		})
+	}
+
+	// 3. Manifest
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		cleanup()
+		return nil, utils.WrapError(err, "failed to encode manifest", utils.ErrCodeInternal)
+	}
+	if err := writeZipFile(zw, coursePackageManifestName, data); err != nil {
+		cleanup()
+		return nil, utils.WrapError(err, "failed to write manifest", utils.ErrCodeInternal)
+	}
+	if err := zw.Close(); err != nil {
+		cleanup()
+		return nil, utils.WrapError(err, "failed to write course package", utils.ErrCodeInternal)
+	}
+
+	// 4. Trả file tạm để handler stream về client
+	info, err := tmp.Stat()
+	if err != nil {
+		cleanup()
+		return nil, utils.WrapError(err, "failed to read course package", utils.ErrCodeInternal)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, utils.WrapError(err, "failed to read course package", utils.ErrCodeInternal)
+	}
+
+	return &dto.CoursePackageExport{
+		FileName: fmt.Sprintf("%s-v%d.zip", course.Slug, coursePackageFormatVersion),
+		FileSize: info.Size(),
+		Body:     tempFile{tmp},
+	}, nil
+}
+
+// writeStorageAsset copy một object trên storage vào zip
+func (ps *coursePackageService) writeStorageAsset(zw *zip.Writer, name, key string) (*dto.CoursePackageAsset, error) {
+	body, err := ps.store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	size, err := io.Copy(w, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.CoursePackageAsset{Path: name, Size: size}, nil
+}
+
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// tempFile xóa file tạm khi Close
+type tempFile struct {
+	*os.File
+}
+
+func (tf tempFile) Close() error {
+	err := tf.File.Close()
+	if removeErr := os.Remove(tf.Name()); removeErr != nil {
+		log.Printf("Failed to remove temp file %s: %v", tf.Name(), removeErr)
+	}
+	return err
+}
+
+// ---------------- Import ----------------
+
+// coursePackageMaxSize giới hạn dung lượng gói import (COURSE_PACKAGE_MAX_SIZE_MB, mặc định 500MB)
+func coursePackageMaxSize() int64 {
+	maxSizeMB, err := strconv.Atoi(utils.GetEnv("COURSE_PACKAGE_MAX_SIZE_MB", "500"))
+	if err != nil || maxSizeMB < 1 {
+		maxSizeMB = 500
+	}
+	return int64(maxSizeMB) << 20
+}
+
+func (ps *coursePackageService) ImportCourse(instructorId uint, fileHeader *multipart.FileHeader, req *dto.ImportCourseRequest) (*dto.ImportCourseResponse, error) {
+	// 1. Kiểm tra file zip
+	if strings.ToLower(path.Ext(fileHeader.Filename)) != ".zip" {
+		return nil, utils.NewError("course package must be a .zip file", utils.ErrCodeBadRequest)
+	}
+	if maxSize := coursePackageMaxSize(); fileHeader.Size > maxSize {
+		return nil, utils.NewError(fmt.Sprintf("course package too large (max %dMB)", maxSize>>20), utils.ErrCodeBadRequest)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, utils.WrapError(err, "cannot open course package", utils.ErrCodeBadRequest)
+	}
+	defer file.Close()
+
+	zr, err := zip.NewReader(file, fileHeader.Size)
+	if err != nil {
+		return nil, &CoursePackageError{Problems: []dto.CoursePackageProblem{{Path: fileHeader.Filename, Message: "not a valid zip archive"}}}
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	// 2. Đọc manifest, nâng cấp về version hiện tại rồi validate
+	manifestFile, ok := files[coursePackageManifestName]
+	if !ok {
+		return nil, &CoursePackageError{Problems: []dto.CoursePackageProblem{{Path: coursePackageManifestName, Message: "manifest.json is missing from the package root"}}}
+	}
+	data, err := readZipEntry(manifestFile, coursePackageMaxManifest)
+	if err != nil {
+		return nil, &CoursePackageError{Problems: []dto.CoursePackageProblem{{Path: coursePackageManifestName, Message: err.Error()}}}
+	}
+
+	manifest, sourceVersion, err := decodeCoursePackageManifest(data)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := validateCoursePackage(manifest, files)
+
+	// 3. Category: category_id truyền vào hoặc category cùng slug trên hệ thống này
+	var category *models.Category
+	if req.CategoryId != 0 {
+		category, err = ps.categoryRepo.FindById(req.CategoryId)
+		if err != nil || !category.IsActive {
+			return nil, utils.NewError("category not found", utils.ErrCodeNotFound)
+		}
+	} else if manifest.Course.Category.Slug == "" {
+		problems.add("course.category.slug", "category slug is required when category_id is not provided")
+	} else if found, ok := ps.categoryRepo.FindBySlug(manifest.Course.Category.Slug); ok && found.IsActive {
+		category = found
+	} else {
+		problems.add("course.category.slug", "category %q does not exist on this server, pass category_id to choose one", manifest.Course.Category.Slug)
+	}
+
+	if err := problems.err(); err != nil {
+		return nil, err
+	}
+
+	// 4. Thumbnail được upload trước, các file còn lại upload trong transaction khi đã có id lesson
+	var uploadedKeys []string
+	thumbnailURL := ""
+	if asset := manifest.Course.Thumbnail; asset != nil {
+		ext := strings.ToLower(path.Ext(asset.FileName))
+		key := fmt.Sprintf("%s%s%s", storage.OwnerPrefix("thumbnails", instructorId), uuid.New().String(), ext)
+		if err := ps.putZipEntry(files[asset.Path], key, utils.ImageFileRule.Extensions[ext]); err != nil {
+			return nil, utils.WrapError(err, "failed to import thumbnail", utils.ErrCodeInternal)
+		}
+		uploadedKeys = append(uploadedKeys, key)
+		thumbnailURL = ps.store.URL(key)
+	}
+
+	// 5. Tạo course draft với id, slug và category mới
+	var course *models.Course
+	assetsImported := len(uploadedKeys)
+	attachmentRule := utils.AttachmentFileRule()
+	err = ps.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		source := &manifest.Course
+		baseSlug := utils.GenerateSlug(source.Slug)
+		if baseSlug == "" {
+			baseSlug = utils.GenerateSlug(source.Title)
+		}
+
+		course = &models.Course{
+			Title:            source.Title,
+			Slug:             utils.GenerateUniqueSlug(baseSlug, repos.CourseTemplates.CourseSlugExists),
+			Description:      source.Description,
+			ShortDesc:        source.ShortDesc,
+			ThumbnailURL:     thumbnailURL,
+			VideoPreviewURL:  source.VideoPreviewURL,
+			Price:            source.Price,
+			DiscountPrice:    source.DiscountPrice,
+			InstructorId:     instructorId,
+			CategoryId:       category.Id,
+			Level:            source.Level,
+			DurationHours:    source.DurationHours,
+			Language:         source.Language,
+			Requirements:     source.Requirements,
+			WhatYouLearn:     source.WhatYouLearn,
+			Status:           "draft",
+			PrerequisiteMode: source.PrerequisiteMode,
+		}
+		if course.PrerequisiteMode == "" {
+			course.PrerequisiteMode = "warn"
+		}
+		if err := repos.CourseTemplates.CreateCourse(course); err != nil {
+			return utils.WrapError(err, "failed to create course", utils.ErrCodeInternal)
+		}
+
+		// 5.1 Lessons theo thứ trong manifest; slug được tạo lại để không trùng trong course
+		slugs := make(lessonSlugSet, len(manifest.Lessons))
+		lessonIds := make(map[string]uint, len(manifest.Lessons))
+		for i := range manifest.Lessons {
+			src := &manifest.Lessons[i]
+			slugSource := src.Slug
+			if utils.GenerateSlug(slugSource) == "" {
+				slugSource = src.Title
+			}
+
+			lesson := &models.Lesson{
+				CourseId:                  course.Id,
+				Title:                     src.Title,
+				Slug:                      slugs.claim(slugSource, uint(i+1)),
+				Description:               src.Description,
+				VideoURL:                  src.VideoURL,
+				VideoDuration:             src.VideoDuration,
+				LessonOrder:               i + 1,
+				IsPreview:                 src.IsPreview,
+				IsPublished:               src.IsPublished,
+				UnlockAfterDays:           src.UnlockAfterDays,
+				UnlockAt:                  src.UnlockAt,
+				RequirePreviousCompletion: src.RequirePreviousCompletion,
+			}
+			if src.ContentPath != "" {
+				content, err := readZipEntry(files[src.ContentPath], coursePackageMaxContent)
+				if err != nil {
+					return utils.WrapError(err, fmt.Sprintf("failed to read %s", src.ContentPath), utils.ErrCodeBadRequest)
+				}
+				lesson.Content = string(content)
+			}
+			if err := repos.CourseTemplates.CreateLesson(lesson); err != nil {
+				return utils.WrapError(err, "failed to import lessons", utils.ErrCodeInternal)
+			}
+			lessonIds[src.Ref] = lesson.Id
+
+			// 5.2 Tài liệu đính kèm của lesson
+			for _, item := range src.Attachments {
+				// Content type lấy theo extension (nội dung đã được kiểm tra ở bước validate)
+				ext := strings.ToLower(path.Ext(item.FileName))
+				contentType := attachmentRule.Extensions[ext]
+
+				key := lessonAttachmentPrefix(lesson.Id) + uuid.New().String() + ext
+				if err := ps.putZipEntry(files[item.Path], key, contentType); err != nil {
+					return utils.WrapError(err, fmt.Sprintf("failed to import attachment %q", item.FileName), utils.ErrCodeInternal)
+				}
+				uploadedKeys = append(uploadedKeys, key)
+
+				attachment := &models.LessonAttachment{
+					LessonId:   lesson.Id,
+					Title:      item.Title,
+					FileName:   path.Base(item.FileName),
+					FileSize:   item.Size,
+					MimeType:   contentType,
+					StorageKey: key,
+				}
+				if err := repos.CourseTemplates.CreateAttachment(attachment); err != nil {
+					return utils.WrapError(err, "failed to import attachments", utils.ErrCodeInternal)
+				}
+				assetsImported++
+			}
+		}
+
+		// 5.3 Quizzes
+		quizIds := make(map[string]uint, len(manifest.Quizzes))
+		for i := range manifest.Quizzes {
+			src := &manifest.Quizzes[i]
+
+			questions := make([]models.QuizQuestion, len(src.Questions))
+			for j, question := range src.Questions {
+				options, err := json.Marshal(question.Options)
+				if err != nil {
+					return utils.WrapError(err, "failed to import quiz questions", utils.ErrCodeInternal)
+				}
+				questions[j] = models.QuizQuestion{
+					Question:      question.Question,
+					Options:       string(options),
+					CorrectOption: question.CorrectOption,
+					QuestionOrder: j + 1,
+				}
+			}
+
+			quiz := &models.Quiz{
+				CourseId:     course.Id,
+				LessonId:     lessonIds[src.LessonRef],
+				Title:        src.Title,
+				Description:  src.Description,
+				PassingScore: src.PassingScore,
+				Questions:    questions,
+			}
+			if err := repos.CourseTemplates.CreateQuiz(quiz); err != nil {
+				return utils.WrapError(err, "failed to import quizzes", utils.ErrCodeInternal)
+			}
+			quizIds[src.Ref] = quiz.Id
+		}
+
+		// 5.4 Quiz bắt buộc của lesson trỏ sang id mới
+		for i := range manifest.Lessons {
+			src := &manifest.Lessons[i]
+			if src.PrerequisiteQuizRef == "" {
+				continue
+			}
+			if err := repos.CourseTemplates.SetLessonPrerequisiteQuiz(lessonIds[src.Ref], quizIds[src.PrerequisiteQuizRef]); err != nil {
+				return utils.WrapError(err, "failed to import lesson prerequisites", utils.ErrCodeInternal)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for _, key := range uploadedKeys {
+			if deleteErr := ps.store.Delete(key); deleteErr != nil {
+				log.Printf("Failed to delete imported object %s: %v", key, deleteErr)
+			}
+		}
+		return nil, err
+	}
+
+	return &dto.ImportCourseResponse{
+		Message:         "Course imported as draft",
+		CourseId:        course.Id,
+		Title:           course.Title,
+		Slug:            course.Slug,
+		Status:          course.Status,
+		CategoryId:      course.CategoryId,
+		FormatVersion:   sourceVersion,
+		LessonsImported: len(manifest.Lessons),
+		QuizzesImported: len(manifest.Quizzes),
+		AssetsImported:  assetsImported,
+	}, nil
+}
+
+// putZipEntry upload một file trong zip lên storage
+func (ps *coursePackageService) putZipEntry(f *zip.File, key, contentType string) error {
+	body, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return ps.store.Put(key, body, int64(f.UncompressedSize64), contentType)
+}
+
+// readZipEntry đọc một file trong zip, không tin dung lượng khai báo trong header
+func readZipEntry(f *zip.File, maxSize int64) ([]byte, error) {
+	if f.UncompressedSize64 > uint64(maxSize) {
+		return nil, fmt.Errorf("file too large (max %dMB)", maxSize>>20)
+	}
+
+	body, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("file too large (max %dMB)", maxSize>>20)
+	}
+	return data, nil
+}
+
+// decodeCoursePackageManifest parse manifest và chạy các bước nâng cấp từ version của gói lên version hiện tại
+func decodeCoursePackageManifest(data []byte) (*dto.CoursePackageManifest, int, error) {
+	var problems packageProblems
+
+	// 1. Parse JSON thô để đọc version trước khi áp schema
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line, column := jsonPosition(data, syntaxErr.Offset)
+			problems.add(coursePackageManifestName, "invalid JSON at line %d, column %d: %s", line, column, syntaxErr.Error())
+		} else {
+			problems.add(coursePackageManifestName, "manifest must be a JSON object")
+		}
+		return nil, 0, problems.err()
+	}
+
+	version, ok := raw["format_version"].(float64)
+	if !ok || version != float64(int(version)) || version < 1 {
+		problems.add("format_version", "format_version is required and must be a positive integer")
+		return nil, 0, problems.err()
+	}
+	sourceVersion := int(version)
+	if sourceVersion > coursePackageFormatVersion {
+		problems.add("format_version", "format_version %d is newer than the supported version %d", sourceVersion, coursePackageFormatVersion)
+		return nil, 0, problems.err()
+	}
+
+	// 2. Nâng cấp lần lượt từng version
+	for v := sourceVersion; v < coursePackageFormatVersion; v++ {
+		upgrade, ok := coursePackageUpgrades[v]
+		if !ok {
+			problems.add("format_version", "no upgrade available from format_version %d", v)
+			return nil, 0, problems.err()
+		}
+		if err := upgrade(raw); err != nil {
+			problems.add("format_version", "cannot upgrade from format_version %d: %v", v, err)
+			return nil, 0, problems.err()
+		}
+		raw["format_version"] = v + 1
+	}
+
+	if sourceVersion != coursePackageFormatVersion {
+		upgraded, err := json.Marshal(raw)
+		if err != nil {
+			return nil, 0, utils.WrapError(err, "failed to upgrade manifest", utils.ErrCodeInternal)
+		}
+		data = upgraded
+	}
+
+	// 3. Áp schema, báo field sai kiểu
+	var manifest dto.CoursePackageManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			problems.add(typeErr.Field, "expected %s but got JSON %s", typeErr.Type.String(), typeErr.Value)
+		} else {
+			problems.add(coursePackageManifestName, "%s", err.Error())
+		}
+		return nil, 0, problems.err()
+	}
+
+	return &manifest, sourceVersion, nil
+}
+
+// jsonPosition đổi offset byte thành dòng/cột để dễ sửa manifest
+func jsonPosition(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := utf8.RuneCount(before[bytes.LastIndexByte(before, '\n')+1:]) + 1
+	return line, column
+}
+
+// validateCoursePackage kiểm tra nội dung manifest và các file nó tham chiếu trong zip
+func validateCoursePackage(manifest *dto.CoursePackageManifest, files map[string]*zip.File) packageProblems {
+	var problems packageProblems
+
+	// 1. Course
+	course := &manifest.Course
+	checkLength(&problems, "course.title", course.Title, 5, 200)
+	checkLength(&problems, "course.short_description", course.ShortDesc, 0, 500)
+	if !coursePackageLevels[course.Level] {
+		problems.add("course.level", "level must be one of beginner, intermediate, advanced")
+	}
+	if !coursePackageLanguages[course.Language] {
+		problems.add("course.language", "language must be one of vi, en")
+	}
+	if !coursePackagePrerequisiteModes[course.PrerequisiteMode] {
+		problems.add("course.prerequisite_mode", "prerequisite_mode must be one of warn, block")
+	}
+	if course.Price < 0 {
+		problems.add("course.price", "price must be a positive number")
+	}
+	if course.DiscountPrice != nil && (*course.DiscountPrice < 0 || *course.DiscountPrice > course.Price) {
+		problems.add("course.discount_price", "discount_price must be between 0 and price")
+	}
+	if course.DurationHours < 0 {
+		problems.add("course.duration_hours", "duration_hours must not be negative")
+	}
+	checkURL(&problems, "course.video_preview_url", course.VideoPreviewURL)
+	if course.Thumbnail != nil {
+		checkAsset(&problems, "course.thumbnail", course.Thumbnail, files, utils.ImageFileRule)
+	}
+
+	// 2. Lessons
+	lessonRefs := make(map[string]bool, len(manifest.Lessons))
+	attachmentRule := utils.AttachmentFileRule()
+	for i := range manifest.Lessons {
+		lesson := &manifest.Lessons[i]
+		prefix := fmt.Sprintf("lessons[%d]", i)
+
+		if lesson.Ref == "" {
+			problems.add(prefix+".ref", "ref is required")
+		} else if lessonRefs[lesson.Ref] {
+			problems.add(prefix+".ref", "duplicate lesson ref %q", lesson.Ref)
+		}
+		lessonRefs[lesson.Ref] = true
+
+		checkLength(&problems, prefix+".title", lesson.Title, 3, 200)
+		checkURL(&problems, prefix+".video_url", lesson.VideoURL)
+		if lesson.VideoDuration < 0 {
+			problems.add(prefix+".video_duration", "video_duration must not be negative")
+		}
+		if lesson.UnlockAfterDays != nil && *lesson.UnlockAfterDays < 0 {
+			problems.add(prefix+".unlock_after_days", "unlock_after_days must not be negative")
+		}
+
+		if lesson.ContentPath != "" {
+			if f, ok := files[lesson.ContentPath]; !ok {
+				problems.add(prefix+".content_path", "file %q not found in package", lesson.ContentPath)
+			} else if f.UncompressedSize64 > coursePackageMaxContent {
+				problems.add(prefix+".content_path", "lesson content too large (max %dMB)", coursePackageMaxContent>>20)
+			}
+		}
+
+		for j := range lesson.Attachments {
+			attachment := &lesson.Attachments[j]
+			attachmentPrefix := fmt.Sprintf("%s.attachments[%d]", prefix, j)
+			checkLength(&problems, attachmentPrefix+".title", attachment.Title, 1, 200)
+			checkAsset(&problems, attachmentPrefix, &attachment.CoursePackageAsset, files, attachmentRule)
+		}
+	}
+
+	// 3. Quizzes
+	quizLessons := make(map[string]string, len(manifest.Quizzes))
+	for i := range manifest.Quizzes {
+		quiz := &manifest.Quizzes[i]
+		prefix := fmt.Sprintf("quizzes[%d]", i)
+
+		if quiz.Ref == "" {
+			problems.add(prefix+".ref", "ref is required")
+		} else if _, exists := quizLessons[quiz.Ref]; exists {
+			problems.add(prefix+".ref", "duplicate quiz ref %q", quiz.Ref)
+		}
+		quizLessons[quiz.Ref] = quiz.LessonRef
+
+		if !lessonRefs[quiz.LessonRef] || quiz.LessonRef == "" {
+			problems.add(prefix+".lesson_ref", "lesson ref %q does not match any lesson", quiz.LessonRef)
+		}
+		checkLength(&problems, prefix+".title", quiz.Title, 3, 200)
+		if quiz.PassingScore < 1 || quiz.PassingScore > 100 {
+			problems.add(prefix+".passing_score", "passing_score must be between 1 and 100")
+		}
+		if len(quiz.Questions) == 0 {
+			problems.add(prefix+".questions", "quiz must have at least one question")
+		}
+
+		for j := range quiz.Questions {
+			question := &quiz.Questions[j]
+			questionPrefix := fmt.Sprintf("%s.questions[%d]", prefix, j)
+			checkLength(&problems, questionPrefix+".question", question.Question, 3, 0)
+			if len(question.Options) < 2 {
+				problems.add(questionPrefix+".options", "question must have at least 2 options")
+			}
+			for k, option := range question.Options {
+				if strings.TrimSpace(option) == "" {
+					problems.add(fmt.Sprintf("%s.options[%d]", questionPrefix, k), "option must not be empty")
+				}
+			}
+			if question.CorrectOption < 0 || question.CorrectOption >= len(question.Options) {
+				problems.add(questionPrefix+".correct_option", "correct_option must be an index into options")
+			}
+		}
+	}
+
+	// 4. Quiz bắt buộc phải thuộc course và không nằm ở chính lesson đó
+	for i := range manifest.Lessons {
+		lesson := &manifest.Lessons[i]
+		if lesson.PrerequisiteQuizRef == "" {
+			continue
+		}
+		lessonRef, ok := quizLessons[lesson.PrerequisiteQuizRef]
+		if !ok {
+			problems.add(fmt.Sprintf("lessons[%d].prerequisite_quiz_ref", i), "quiz ref %q does not match any quiz", lesson.PrerequisiteQuizRef)
+		} else if lessonRef == lesson.Ref {
+			problems.add(fmt.Sprintf("lessons[%d].prerequisite_quiz_ref", i), "a lesson cannot require its own quiz")
+		}
+	}
+
+	return problems
+}
+
+// checkLength kiểm tra độ dài theo ký tự (max = 0: không giới hạn)
+func checkLength(problems *packageProblems, field, value string, min, max int) {
+	length := utf8.RuneCountInString(strings.TrimSpace(value))
+	switch {
+	case min > 0 && length == 0:
+		problems.add(field, "%s is required", field[strings.LastIndex(field, ".")+1:])
+	case length < min:
+		problems.add(field, "must be at least %d characters", min)
+	case max > 0 && length > max:
+		problems.add(field, "must be at most %d characters", max)
+	}
+}
+
+func checkURL(problems *packageProblems, field, value string) {
+	if value == "" {
+		return
+	}
+	parsed, err := url.ParseRequestURI(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		problems.add(field, "must be an absolute http(s) URL")
+	}
+}
+
+// checkAsset kiểm tra file được tham chiếu có trong zip, đúng dung lượng khai báo và hợp lệ theo rule
+func checkAsset(problems *packageProblems, field string, asset *dto.CoursePackageAsset, files map[string]*zip.File, rule utils.FileRule) {
+	f, ok := files[asset.Path]
+	if asset.Path == "" || !ok {
+		problems.add(field+".path", "file %q not found in package", asset.Path)
+		return
+	}
+	if asset.Size != int64(f.UncompressedSize64) {
+		problems.add(field+".size", "declared size %d does not match file size %d", asset.Size, f.UncompressedSize64)
+	}
+	if _, err := utils.ValidateFileName(asset.FileName, int64(f.UncompressedSize64), rule); err != nil {
+		problems.add(field+".file_name", "%s", err.Error())
+		return
+	}
+
+	// Nội dung file được kiểm tra như khi upload, không tin mime_type khai báo trong manifest
+	head, err := readZipHead(f)
+	if err != nil {
+		problems.add(field+".path", "cannot read file %q", asset.Path)
+		return
+	}
+	ext := strings.ToLower(path.Ext(asset.FileName))
+	if err := utils.ValidateFileContent(ext, head, rule); err != nil {
+		problems.add(field+".path", "%s", err.Error())
+	}
+}
+
+// readZipHead đọc tối đa 512 byte đầu của file trong zip để nhận diện MIME type
+func readZipHead(f *zip.File) ([]byte, error) {
+	body, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(body, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	return head[:n], nil
+}
@@ -0,0 +1,790 @@
+package service
+
+import (
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/storage"
+	"lms/src/utils"
+	"math"
+	"mime/multipart"
+	"time"
+)
+
+// Trạng thái của course revision
+const (
+	revisionStatusDraft     = "draft"
+	revisionStatusPublished = "published"
+	revisionStatusDiscarded = "discarded"
+)
+
+type courseRevisionService struct {
+	revisionRepo   repository.CourseRevisionRepository
+	courseRepo     repository.CourseRepository
+	categoryRepo   repository.CategoryRepository
+	instructorRepo repository.InstructorRepository
+	transactor     repository.Transactor
+	notifier       Notifier
+	store          storage.Storage
+}
+
+func NewCourseRevisionService(
+	revisionRepo repository.CourseRevisionRepository,
+	courseRepo repository.CourseRepository,
+	categoryRepo repository.CategoryRepository,
+	instructorRepo repository.InstructorRepository,
+	transactor repository.Transactor,
+	notifier Notifier,
+	store storage.Storage,
+) CourseRevisionService {
+	return &courseRevisionService{
+		revisionRepo:   revisionRepo,
+		courseRepo:     courseRepo,
+		categoryRepo:   categoryRepo,
+		instructorRepo: instructorRepo,
+		transactor:     transactor,
+		notifier:       notifier,
+		store:          store,
+	}
+}
+
+// ---------------- Instructor ----------------
+
+func (cs *courseRevisionService) GetRevisions(instructorId, courseId uint, req *dto.GetCourseRevisionsQueryRequest) (*dto.GetCourseRevisionsResponse, error) {
+	if _, err := cs.findInstructorCourse(instructorId, courseId); err != nil {
+		return nil, err
+	}
+	return cs.listRevisions(courseId, req)
+}
+
+func (cs *courseRevisionService) CreateDraft(instructorId, courseId uint) (*dto.CourseDraftResponse, error) {
+	// 1. Chỉ course đã publish mới cần draft (course nháp được sửa trực tiếp)
+	course, err := cs.findInstructorCourse(instructorId, courseId)
+	if err != nil {
+		return nil, err
+	}
+	if course.Status != "published" {
+		return nil, utils.NewError("draft revisions are only used for published courses. Edit this course directly", utils.ErrCodeBadRequest)
+	}
+
+	err = cs.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		if _, err := repos.CourseRevisions.LockCourse(courseId); err != nil {
+			return utils.WrapError(err, "failed to lock course", utils.ErrCodeInternal)
+		}
+
+		// 2. Mỗi course chỉ có một draft
+		if _, err := repos.CourseRevisions.FindDraft(courseId); err == nil {
+			return utils.NewError("course already has a draft revision", utils.ErrCodeConflict)
+		}
+
+		// 3. Đảm bảo nội dung đang live đã được lưu thành một version
+		base, err := ensureBaselineRevision(repos, courseId, instructorId)
+		if err != nil {
+			return utils.WrapError(err, "failed to record the live version", utils.ErrCodeInternal)
+		}
+
+		// 4. Draft bắt đầu từ nội dung của version đó
+		draft := &models.CourseRevision{
+			CourseId:       courseId,
+			Status:         revisionStatusDraft,
+			Snapshot:       base.Snapshot,
+			BasedOnVersion: base.Version,
+			CreatedBy:      instructorId,
+		}
+		if err := repos.CourseRevisions.Create(draft); err != nil {
+			return utils.WrapError(err, "failed to create draft revision", utils.ErrCodeInternal)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cs.draftResponse(courseId)
+}
+
+func (cs *courseRevisionService) GetDraft(instructorId, courseId uint) (*dto.CourseDraftResponse, error) {
+	if _, err := cs.findInstructorCourse(instructorId, courseId); err != nil {
+		return nil, err
+	}
+	return cs.draftResponse(courseId)
+}
+
+func (cs *courseRevisionService) UpdateDraft(instructorId, courseId uint, req *dto.UpdateCourseDraftRequest) (*dto.CourseDraftResponse, error) {
+	// Category mới phải tồn tại và đang hoạt động
+	if req.CategoryId != nil {
+		category, err := cs.categoryRepo.FindById(*req.CategoryId)
+		if err != nil {
+			return nil, utils.NewError("category not found", utils.ErrCodeNotFound)
+		}
+		if !category.IsActive {
+			return nil, utils.NewError("category is not active", utils.ErrCodeBadRequest)
+		}
+	}
+
+	return cs.mutateDraft(instructorId, courseId, func(snapshot *dto.CourseSnapshot) error {
+		if req.Title != nil {
+			snapshot.Title = *req.Title
+		}
+		if req.ShortDesc != nil {
+			snapshot.ShortDesc = *req.ShortDesc
+		}
+		if req.Description != nil {
+			snapshot.Description = *req.Description
+		}
+		if req.CategoryId != nil {
+			snapshot.CategoryId = *req.CategoryId
+		}
+		if req.Level != nil {
+			snapshot.Level = *req.Level
+		}
+		if req.Language != nil {
+			snapshot.Language = *req.Language
+		}
+		if req.Requirements != nil {
+			snapshot.Requirements = *req.Requirements
+		}
+		if req.WhatYouLearn != nil {
+			snapshot.WhatYouLearn = *req.WhatYouLearn
+		}
+		if req.DurationHours != nil {
+			snapshot.DurationHours = *req.DurationHours
+		}
+		if req.ThumbnailURL != nil && *req.ThumbnailURL != snapshot.ThumbnailURL {
+			// URL thuộc storage phải là ảnh instructor tự upload qua presigned URL
+			if err := storage.ValidateClientURL(cs.store, *req.ThumbnailURL, storage.OwnerPrefix("thumbnails", instructorId)); err != nil {
+				return utils.NewError(err.Error(), utils.ErrCodeBadRequest)
+			}
+			snapshot.ThumbnailURL = *req.ThumbnailURL
+		}
+		return nil
+	})
+}
+
+func (cs *courseRevisionService) UploadDraftThumbnail(instructorId, courseId uint, file *multipart.FileHeader) (*dto.CourseDraftResponse, error) {
+	// 1. Kiểm tra quyền và draft trước khi upload
+	if _, err := cs.findInstructorCourse(instructorId, courseId); err != nil {
+		return nil, err
+	}
+	if _, err := cs.revisionRepo.FindDraft(courseId); err != nil {
+		return nil, cs.draftNotFound()
+	}
+
+	// 2. Validate và lưu ảnh (thumbnail cũ được giữ lại vì các version trước vẫn dùng)
+	fileName, contentType, err := utils.ValidateFile(file, utils.ImageFileRule)
+	if err != nil {
+		return nil, utils.WrapError(err, "Invalid thumbnail file", utils.ErrCodeBadRequest)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to upload thumbnail", utils.ErrCodeBadRequest)
+	}
+	defer src.Close()
+
+	key := storage.OwnerPrefix("thumbnails", instructorId) + fileName
+	if err := cs.store.Put(key, src, file.Size, contentType); err != nil {
+		return nil, utils.WrapError(err, "Failed to upload thumbnail", utils.ErrCodeInternal)
+	}
+
+	// 3. Gắn vào draft
+	thumbnailURL := cs.store.URL(key)
+	response, err := cs.mutateDraft(instructorId, courseId, func(snapshot *dto.CourseSnapshot) error {
+		snapshot.ThumbnailURL = thumbnailURL
+		return nil
+	})
+	if err != nil {
+		cs.store.Delete(key)
+		return nil, err
+	}
+	return response, nil
+}
+
+func (cs *courseRevisionService) DiscardDraft(instructorId, courseId uint) (*dto.DiscardCourseDraftResponse, error) {
+	if _, err := cs.findInstructorCourse(instructorId, courseId); err != nil {
+		return nil, err
+	}
+
+	draft, err := cs.revisionRepo.FindDraft(courseId)
+	if err != nil {
+		return nil, cs.draftNotFound()
+	}
+
+	if err := cs.revisionRepo.Update(draft.Id, map[string]interface{}{"status": revisionStatusDiscarded}); err != nil {
+		return nil, utils.WrapError(err, "failed to discard draft revision", utils.ErrCodeInternal)
+	}
+
+	return &dto.DiscardCourseDraftResponse{Message: "Draft revision discarded"}, nil
+}
+
+func (cs *courseRevisionService) PreviewDraft(instructorId, courseId uint) (*dto.CoursePreviewResponse, error) {
+	if _, err := cs.findInstructorCourse(instructorId, courseId); err != nil {
+		return nil, err
+	}
+
+	draft, err := cs.draftResponse(courseId)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &draft.Snapshot
+
+	categoryName := ""
+	if category, err := cs.categoryRepo.FindById(snapshot.CategoryId); err == nil {
+		categoryName = category.Name
+	}
+
+	// Học viên chỉ thấy lesson đã publish
+	lessons := make([]dto.CoursePreviewLesson, 0, len(snapshot.Lessons))
+	for _, lesson := range snapshot.Lessons {
+		if !lesson.IsPublished {
+			continue
+		}
+		lessons = append(lessons, dto.CoursePreviewLesson{
+			Key:           lesson.Key,
+			LessonOrder:   len(lessons) + 1,
+			Title:         lesson.Title,
+			Description:   lesson.Description,
+			VideoDuration: lesson.VideoDuration,
+			IsPreview:     lesson.IsPreview,
+		})
+	}
+
+	return &dto.CoursePreviewResponse{
+		CourseId:      courseId,
+		Title:         snapshot.Title,
+		ShortDesc:     snapshot.ShortDesc,
+		Description:   snapshot.Description,
+		CategoryId:    snapshot.CategoryId,
+		CategoryName:  categoryName,
+		Level:         snapshot.Level,
+		Language:      snapshot.Language,
+		Requirements:  snapshot.Requirements,
+		WhatYouLearn:  snapshot.WhatYouLearn,
+		DurationHours: snapshot.DurationHours,
+		ThumbnailURL:  snapshot.ThumbnailURL,
+		TotalLessons:  len(lessons),
+		Lessons:       lessons,
+		Diff:          draft.Diff,
+	}, nil
+}
+
+func (cs *courseRevisionService) AddDraftLesson(instructorId, courseId uint, req *dto.CreateDraftLessonRequest) (*dto.CourseDraftResponse, error) {
+	// 1. Quiz bắt buộc phải thuộc course
+	if req.PrerequisiteQuizId != nil {
+		if _, err := cs.instructorRepo.FindQuizByIdAndCourse(*req.PrerequisiteQuizId, courseId); err != nil {
+			return nil, utils.NewError("Prerequisite quiz not found in this course", utils.ErrCodeBadRequest)
+		}
+	}
+
+	key, err := newLessonRevisionKey()
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to create lesson key", utils.ErrCodeInternal)
+	}
+
+	var unlockAfterDays *int
+	if req.UnlockAfterDays != nil && *req.UnlockAfterDays > 0 {
+		unlockAfterDays = req.UnlockAfterDays
+	}
+
+	lesson := dto.LessonSnapshot{
+		Key:                       key,
+		Title:                     req.Title,
+		Description:               req.Description,
+		Content:                   req.Content,
+		VideoURL:                  req.VideoURL,
+		VideoDuration:             req.VideoDuration,
+		IsPreview:                 req.IsPreview,
+		IsPublished:               req.IsPublished,
+		UnlockAfterDays:           unlockAfterDays,
+		UnlockAt:                  req.UnlockAt,
+		RequirePreviousCompletion: req.RequirePreviousCompletion,
+		PrerequisiteQuizId:        req.PrerequisiteQuizId,
+	}
+
+	// 2. Chèn vào vị trí yêu cầu (mặc định cuối danh sách)
+	return cs.mutateDraft(instructorId, courseId, func(snapshot *dto.CourseSnapshot) error {
+		position := len(snapshot.Lessons)
+		if req.Position > 0 && req.Position <= len(snapshot.Lessons) {
+			position = req.Position - 1
+		}
+
+		snapshot.Lessons = append(snapshot.Lessons, dto.LessonSnapshot{})
+		copy(snapshot.Lessons[position+1:], snapshot.Lessons[position:])
+		snapshot.Lessons[position] = lesson
+		return nil
+	})
+}
+
+func (cs *courseRevisionService) UpdateDraftLesson(instructorId, courseId uint, key string, req *dto.UpdateDraftLessonRequest) (*dto.CourseDraftResponse, error) {
+	// 1. Quiz bắt buộc phải thuộc course
+	var quiz *models.Quiz
+	if req.PrerequisiteQuizId != nil && *req.PrerequisiteQuizId != 0 {
+		var err error
+		quiz, err = cs.instructorRepo.FindQuizByIdAndCourse(*req.PrerequisiteQuizId, courseId)
+		if err != nil {
+			return nil, utils.NewError("Prerequisite quiz not found in this course", utils.ErrCodeBadRequest)
+		}
+	}
+
+	return cs.mutateDraft(instructorId, courseId, func(snapshot *dto.CourseSnapshot) error {
+		index := findSnapshotLesson(snapshot, key)
+		if index < 0 {
+			return utils.NewError("lesson not found in draft revision", utils.ErrCodeNotFound)
+		}
+		lesson := &snapshot.Lessons[index]
+
+		if req.Title != nil {
+			lesson.Title = *req.Title
+		}
+		if req.Description != nil {
+			lesson.Description = *req.Description
+		}
+		if req.Content != nil {
+			lesson.Content = *req.Content
+		}
+		if req.VideoURL != nil {
+			lesson.VideoURL = *req.VideoURL
+		}
+		if req.VideoDuration != nil {
+			lesson.VideoDuration = *req.VideoDuration
+		}
+		if req.IsPreview != nil {
+			lesson.IsPreview = *req.IsPreview
+		}
+		if req.IsPublished != nil {
+			lesson.IsPublished = *req.IsPublished
+		}
+
+		if req.UnlockAfterDays != nil {
+			if *req.UnlockAfterDays == 0 {
+				lesson.UnlockAfterDays = nil
+			} else {
+				lesson.UnlockAfterDays = req.UnlockAfterDays
+			}
+		}
+
+		if req.ClearUnlockAt {
+			lesson.UnlockAt = nil
+		} else if req.UnlockAt != nil {
+			lesson.UnlockAt = req.UnlockAt
+		}
+
+		if req.RequirePreviousCompletion != nil {
+			lesson.RequirePreviousCompletion = *req.RequirePreviousCompletion
+		}
+
+		if req.PrerequisiteQuizId != nil {
+			if quiz == nil {
+				lesson.PrerequisiteQuizId = nil
+			} else if lesson.LessonId != 0 && quiz.LessonId == lesson.LessonId {
+				return utils.NewError("A lesson cannot require its own quiz", utils.ErrCodeBadRequest)
+			} else {
+				lesson.PrerequisiteQuizId = &quiz.Id
+			}
+		}
+		return nil
+	})
+}
+
+func (cs *courseRevisionService) RemoveDraftLesson(instructorId, courseId uint, key string) (*dto.CourseDraftResponse, error) {
+	return cs.mutateDraft(instructorId, courseId, func(snapshot *dto.CourseSnapshot) error {
+		index := findSnapshotLesson(snapshot, key)
+		if index < 0 {
+			return utils.NewError("lesson not found in draft revision", utils.ErrCodeNotFound)
+		}
+
+		snapshot.Lessons = append(snapshot.Lessons[:index], snapshot.Lessons[index+1:]...)
+		return nil
+	})
+}
+
+func (cs *courseRevisionService) ReorderDraftLessons(instructorId, courseId uint, req *dto.ReorderDraftLessonsRequest) (*dto.CourseDraftResponse, error) {
+	return cs.mutateDraft(instructorId, courseId, func(snapshot *dto.CourseSnapshot) error {
+		// Danh sách key phải gồm đúng tất cả lesson của draft, mỗi lesson một lần
+		if len(req.Keys) != len(snapshot.Lessons) {
+			return utils.NewError(fmt.Sprintf("keys must list all %d lessons of the draft", len(snapshot.Lessons)), utils.ErrCodeBadRequest)
+		}
+
+		seen := make(map[string]bool, len(req.Keys))
+		lessons := make([]dto.LessonSnapshot, 0, len(req.Keys))
+		for _, key := range req.Keys {
+			index := findSnapshotLesson(snapshot, key)
+			if index < 0 {
+				return utils.NewError(fmt.Sprintf("lesson %s not found in draft revision", key), utils.ErrCodeBadRequest)
+			}
+			if seen[key] {
+				return utils.NewError(fmt.Sprintf("lesson %s is listed more than once", key), utils.ErrCodeBadRequest)
+			}
+			seen[key] = true
+			lessons = append(lessons, snapshot.Lessons[index])
+		}
+
+		snapshot.Lessons = lessons
+		return nil
+	})
+}
+
+func (cs *courseRevisionService) PublishDraft(instructorId, courseId uint, req *dto.PublishCourseDraftRequest) (*dto.CourseRevisionDetailResponse, error) {
+	if _, err := cs.findInstructorCourse(instructorId, courseId); err != nil {
+		return nil, err
+	}
+
+	var draftId uint
+	err := cs.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		// 1. Khóa course để publish/rollback không chạy chồng nhau
+		course, err := repos.CourseRevisions.LockCourse(courseId)
+		if err != nil {
+			return utils.WrapError(err, "failed to lock course", utils.ErrCodeInternal)
+		}
+		if course.Status != "published" && course.Status != "archived" {
+			return utils.NewError(fmt.Sprintf("draft revisions can only be published for published courses (current status: %s)", course.Status), utils.ErrCodeBadRequest)
+		}
+
+		draft, err := repos.CourseRevisions.FindDraft(courseId)
+		if err != nil {
+			return cs.draftNotFound()
+		}
+		draftId = draft.Id
+
+		// 2. Draft phải dựa trên version đang live
+		latest, err := repos.CourseRevisions.FindLatestPublished(courseId)
+		if err != nil {
+			return utils.WrapError(err, "failed to load the live version", utils.ErrCodeInternal)
+		}
+		if latest.Version != draft.BasedOnVersion {
+			return utils.NewError(fmt.Sprintf("draft is based on version %d but version %d is now live. Discard the draft and create a new one", draft.BasedOnVersion, latest.Version), utils.ErrCodeConflict)
+		}
+
+		base, err := decodeSnapshot(latest)
+		if err != nil {
+			return utils.WrapError(err, "failed to read the live version", utils.ErrCodeInternal)
+		}
+		target, err := decodeSnapshot(draft)
+		if err != nil {
+			return utils.WrapError(err, "failed to read draft revision", utils.ErrCodeInternal)
+		}
+
+		diff := diffSnapshots(base, target, latest.Version, 0)
+		if !diff.HasChanges {
+			return utils.NewError("draft revision has no changes to publish", utils.ErrCodeBadRequest)
+		}
+
+		// 3. Áp dụng thay đổi lên course và lessons
+		if err := applySnapshot(repos, courseId, base, target, false); err != nil {
+			return utils.WrapError(err, "failed to publish draft revision", utils.ErrCodeInternal)
+		}
+
+		// 4. Lưu snapshot thực tế (lesson mới đã có id) và đánh dấu draft là version mới
+		_, final, err := liveSnapshot(repos, courseId)
+		if err != nil {
+			return utils.WrapError(err, "failed to publish draft revision", utils.ErrCodeInternal)
+		}
+		encoded, err := encodeSnapshot(final)
+		if err != nil {
+			return utils.WrapError(err, "failed to publish draft revision", utils.ErrCodeInternal)
+		}
+		version, err := repos.CourseRevisions.NextVersion(courseId)
+		if err != nil {
+			return utils.WrapError(err, "failed to publish draft revision", utils.ErrCodeInternal)
+		}
+
+		err = repos.CourseRevisions.Update(draft.Id, map[string]interface{}{
+			"version":      version,
+			"status":       revisionStatusPublished,
+			"snapshot":     encoded,
+			"changelog":    req.Changelog,
+			"summary":      diffSummary(&diff),
+			"published_by": instructorId,
+			"published_at": time.Now(),
+		})
+		if err != nil {
+			return utils.WrapError(err, "failed to publish draft revision", utils.ErrCodeInternal)
+		}
+
+		// 5. Tính lại tiến độ học viên theo curriculum mới
+		if err := repos.CourseRevisions.RecalculateProgress(courseId); err != nil {
+			return utils.WrapError(err, "failed to update student progress", utils.ErrCodeInternal)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cs.revisionDetail(courseId, draftId)
+}
+
+// ---------------- Admin ----------------
+
+func (cs *courseRevisionService) GetCourseRevisions(courseId uint, req *dto.GetCourseRevisionsQueryRequest) (*dto.GetCourseRevisionsResponse, error) {
+	return cs.listRevisions(courseId, req)
+}
+
+func (cs *courseRevisionService) GetRevision(courseId, revisionId uint) (*dto.CourseRevisionDetailResponse, error) {
+	return cs.revisionDetail(courseId, revisionId)
+}
+
+func (cs *courseRevisionService) DiffRevisions(courseId uint, req *dto.DiffCourseRevisionsQueryRequest) (*dto.CourseRevisionDiff, error) {
+	from, fromSnapshot, err := cs.findRevision(courseId, req.FromId)
+	if err != nil {
+		return nil, err
+	}
+	to, toSnapshot, err := cs.findRevision(courseId, req.ToId)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := diffSnapshots(fromSnapshot, toSnapshot, from.Version, to.Version)
+	return &diff, nil
+}
+
+func (cs *courseRevisionService) RollbackRevision(adminId, courseId, revisionId uint, req *dto.RollbackCourseRevisionRequest) (*dto.CourseRevisionDetailResponse, error) {
+	// 1. Chỉ rollback về version đã publish
+	target, targetSnapshot, err := cs.findRevision(courseId, revisionId)
+	if err != nil {
+		return nil, err
+	}
+	if target.Status != revisionStatusPublished {
+		return nil, utils.NewError("only published revisions can be restored", utils.ErrCodeBadRequest)
+	}
+
+	var course *models.Course
+	var restored *models.CourseRevision
+	err = cs.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		course, err = repos.CourseRevisions.LockCourse(courseId)
+		if err != nil {
+			return utils.WrapError(err, "failed to lock course", utils.ErrCodeInternal)
+		}
+
+		latest, err := repos.CourseRevisions.FindLatestPublished(courseId)
+		if err != nil {
+			return utils.WrapError(err, "failed to load the live version", utils.ErrCodeInternal)
+		}
+		if latest.Version == target.Version {
+			return utils.NewError("revision is already the live version", utils.ErrCodeBadRequest)
+		}
+
+		// 2. Áp dụng snapshot cũ lên nội dung đang live; lesson đã xóa được khôi phục để giữ progress
+		_, live, err := liveSnapshot(repos, courseId)
+		if err != nil {
+			return utils.WrapError(err, "failed to read live course", utils.ErrCodeInternal)
+		}
+		if err := applySnapshot(repos, courseId, live, targetSnapshot, true); err != nil {
+			return utils.WrapError(err, "failed to roll back course", utils.ErrCodeInternal)
+		}
+
+		// 3. Rollback được ghi thành một version mới
+		_, final, err := liveSnapshot(repos, courseId)
+		if err != nil {
+			return utils.WrapError(err, "failed to roll back course", utils.ErrCodeInternal)
+		}
+
+		changelog := req.Changelog
+		if changelog == "" {
+			changelog = fmt.Sprintf("Rolled back to version %d", target.Version)
+		}
+		diff := diffSnapshots(live, final, latest.Version, 0)
+
+		restored, err = newPublishedRevision(repos, courseId, adminId, final, changelog, diffSummary(&diff), &target.Version)
+		if err != nil {
+			return utils.WrapError(err, "failed to record rollback revision", utils.ErrCodeInternal)
+		}
+
+		if err := repos.CourseRevisions.RecalculateProgress(courseId); err != nil {
+			return utils.WrapError(err, "failed to update student progress", utils.ErrCodeInternal)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Báo cho instructor (draft đang mở, nếu có, không còn publish được)
+	cs.notifier.Notify(course.InstructorId, notificationCourseRolledBack,
+		"Course rolled back",
+		fmt.Sprintf("An admin restored \"%s\" to version %d.", course.Title, target.Version),
+		fmt.Sprintf("/instructor/courses/%d/revisions", course.Id),
+	)
+
+	return cs.revisionDetail(courseId, restored.Id)
+}
+
+// ---------------- Helpers ----------------
+
+func (cs *courseRevisionService) findInstructorCourse(instructorId, courseId uint) (*models.Course, error) {
+	course, err := cs.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	if err != nil {
+		return nil, utils.NewError("course not found or you don't have permission to access this course", utils.ErrCodeNotFound)
+	}
+	return course, nil
+}
+
+func (cs *courseRevisionService) draftNotFound() error {
+	return utils.NewError("course has no draft revision. Please create a draft first", utils.ErrCodeNotFound)
+}
+
+// findRevision lấy revision (draft hoặc published) của course kèm snapshot.
+// Revision không có tenant_id nên course được kiểm tra qua courseRepo (đã lọc theo tenant) trước.
+func (cs *courseRevisionService) findRevision(courseId, revisionId uint) (*models.CourseRevision, *dto.CourseSnapshot, error) {
+	if _, err := cs.courseRepo.FindById(courseId); err != nil {
+		return nil, nil, utils.NewError("course not found", utils.ErrCodeNotFound)
+	}
+
+	revision, err := cs.revisionRepo.FindById(revisionId)
+	if err != nil || revision.CourseId != courseId || revision.Status == revisionStatusDiscarded {
+		return nil, nil, utils.NewError("revision not found", utils.ErrCodeNotFound)
+	}
+
+	snapshot, err := decodeSnapshot(revision)
+	if err != nil {
+		return nil, nil, utils.WrapError(err, "failed to read revision", utils.ErrCodeInternal)
+	}
+	return revision, snapshot, nil
+}
+
+// mutateDraft khóa course, sửa snapshot của draft bằng fn và lưu lại
+func (cs *courseRevisionService) mutateDraft(instructorId, courseId uint, fn func(snapshot *dto.CourseSnapshot) error) (*dto.CourseDraftResponse, error) {
+	if _, err := cs.findInstructorCourse(instructorId, courseId); err != nil {
+		return nil, err
+	}
+
+	err := cs.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		if _, err := repos.CourseRevisions.LockCourse(courseId); err != nil {
+			return utils.WrapError(err, "failed to lock course", utils.ErrCodeInternal)
+		}
+
+		draft, err := repos.CourseRevisions.FindDraft(courseId)
+		if err != nil {
+			return cs.draftNotFound()
+		}
+
+		snapshot, err := decodeSnapshot(draft)
+		if err != nil {
+			return utils.WrapError(err, "failed to read draft revision", utils.ErrCodeInternal)
+		}
+
+		if err := fn(snapshot); err != nil {
+			return err
+		}
+
+		encoded, err := encodeSnapshot(snapshot)
+		if err != nil {
+			return utils.WrapError(err, "failed to update draft revision", utils.ErrCodeInternal)
+		}
+		if err := repos.CourseRevisions.Update(draft.Id, map[string]interface{}{"snapshot": encoded}); err != nil {
+			return utils.WrapError(err, "failed to update draft revision", utils.ErrCodeInternal)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cs.draftResponse(courseId)
+}
+
+// draftResponse trả về draft kèm diff so với version gốc
+func (cs *courseRevisionService) draftResponse(courseId uint) (*dto.CourseDraftResponse, error) {
+	draft, err := cs.revisionRepo.FindDraft(courseId)
+	if err != nil {
+		return nil, cs.draftNotFound()
+	}
+	snapshot, err := decodeSnapshot(draft)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to read draft revision", utils.ErrCodeInternal)
+	}
+
+	base, err := cs.revisionRepo.FindPublishedVersion(courseId, draft.BasedOnVersion)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to load base version", utils.ErrCodeInternal)
+	}
+	baseSnapshot, err := decodeSnapshot(base)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to read base version", utils.ErrCodeInternal)
+	}
+
+	latest, err := cs.revisionRepo.FindLatestPublished(courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to load the live version", utils.ErrCodeInternal)
+	}
+
+	return &dto.CourseDraftResponse{
+		Revision:    toCourseRevisionItem(draft),
+		Snapshot:    *snapshot,
+		Diff:        diffSnapshots(baseSnapshot, snapshot, draft.BasedOnVersion, 0),
+		LiveVersion: latest.Version,
+		IsStale:     latest.Version != draft.BasedOnVersion,
+	}, nil
+}
+
+func (cs *courseRevisionService) revisionDetail(courseId, revisionId uint) (*dto.CourseRevisionDetailResponse, error) {
+	revision, snapshot, err := cs.findRevision(courseId, revisionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.CourseRevisionDetailResponse{
+		Revision: toCourseRevisionItem(revision),
+		Snapshot: *snapshot,
+	}, nil
+}
+
+func (cs *courseRevisionService) listRevisions(courseId uint, req *dto.GetCourseRevisionsQueryRequest) (*dto.GetCourseRevisionsResponse, error) {
+	// Course phải thuộc tenant hiện tại (revision không có tenant_id)
+	if _, err := cs.courseRepo.FindById(courseId); err != nil {
+		return nil, utils.NewError("course not found", utils.ErrCodeNotFound)
+	}
+
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	revisions, total, err := cs.revisionRepo.GetRevisions(courseId, offset, limit)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to get course revisions", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.CourseRevisionItem, len(revisions))
+	for i := range revisions {
+		items[i] = toCourseRevisionItem(&revisions[i])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetCourseRevisionsResponse{
+		Revisions: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func toCourseRevisionItem(revision *models.CourseRevision) dto.CourseRevisionItem {
+	return dto.CourseRevisionItem{
+		Id:                  revision.Id,
+		CourseId:            revision.CourseId,
+		Version:             revision.Version,
+		Status:              revision.Status,
+		BasedOnVersion:      revision.BasedOnVersion,
+		RestoredFromVersion: revision.RestoredFromVersion,
+		Changelog:           revision.Changelog,
+		Summary:             revision.Summary,
+		CreatedBy:           revision.CreatedBy,
+		CreatedByName:       revision.Creator.FullName,
+		PublishedBy:         revision.PublishedBy,
+		PublishedAt:         revision.PublishedAt,
+		CreatedAt:           revision.CreatedAt,
+		UpdatedAt:           revision.UpdatedAt,
+	}
+}
@@ -0,0 +1,435 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"strings"
+	"time"
+)
+
+// lessonRevisionKey là key ổn định của lesson đã tồn tại trong snapshot
+func lessonRevisionKey(lessonId uint) string {
+	return fmt.Sprintf("lesson-%d", lessonId)
+}
+
+func newLessonRevisionKey() (string, error) {
+	token, err := utils.GenerateSecureToken(6)
+	if err != nil {
+		return "", err
+	}
+	return "new-" + token, nil
+}
+
+func toLessonSnapshot(lesson *models.Lesson) dto.LessonSnapshot {
+	return dto.LessonSnapshot{
+		Key:                       lessonRevisionKey(lesson.Id),
+		LessonId:                  lesson.Id,
+		Title:                     lesson.Title,
+		Description:               lesson.Description,
+		Content:                   lesson.Content,
+		VideoURL:                  lesson.VideoURL,
+		VideoDuration:             lesson.VideoDuration,
+		IsPreview:                 lesson.IsPreview,
+		IsPublished:               lesson.IsPublished,
+		UnlockAfterDays:           lesson.UnlockAfterDays,
+		UnlockAt:                  lesson.UnlockAt,
+		RequirePreviousCompletion: lesson.RequirePreviousCompletion,
+		PrerequisiteQuizId:        lesson.PrerequisiteQuizId,
+	}
+}
+
+// buildCourseSnapshot chụp nội dung đang live của course (lessons theo lesson_order)
+func buildCourseSnapshot(course *models.Course, lessons []models.Lesson) dto.CourseSnapshot {
+	snapshot := dto.CourseSnapshot{
+		Title:         course.Title,
+		ShortDesc:     course.ShortDesc,
+		Description:   course.Description,
+		CategoryId:    course.CategoryId,
+		Level:         course.Level,
+		Language:      course.Language,
+		Requirements:  course.Requirements,
+		WhatYouLearn:  course.WhatYouLearn,
+		DurationHours: course.DurationHours,
+		ThumbnailURL:  course.ThumbnailURL,
+		Lessons:       make([]dto.LessonSnapshot, 0, len(lessons)),
+	}
+
+	for i := range lessons {
+		snapshot.Lessons = append(snapshot.Lessons, toLessonSnapshot(&lessons[i]))
+	}
+	return snapshot
+}
+
+func encodeSnapshot(snapshot *dto.CourseSnapshot) (string, error) {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func decodeSnapshot(revision *models.CourseRevision) (*dto.CourseSnapshot, error) {
+	var snapshot dto.CourseSnapshot
+	if err := json.Unmarshal([]byte(revision.Snapshot), &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid snapshot of revision %d: %w", revision.Id, err)
+	}
+	return &snapshot, nil
+}
+
+func findSnapshotLesson(snapshot *dto.CourseSnapshot, key string) int {
+	for i := range snapshot.Lessons {
+		if snapshot.Lessons[i].Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// ---------------- Diff ----------------
+
+func appendChange[T comparable](changes []dto.RevisionFieldChange, field string, from, to T) []dto.RevisionFieldChange {
+	if from == to {
+		return changes
+	}
+	return append(changes, dto.RevisionFieldChange{Field: field, Old: from, New: to})
+}
+
+// appendPtrChange so sánh giá trị của con trỏ; nil được ghi là null
+func appendPtrChange[T comparable](changes []dto.RevisionFieldChange, field string, from, to *T) []dto.RevisionFieldChange {
+	if from == nil && to == nil || from != nil && to != nil && *from == *to {
+		return changes
+	}
+	return append(changes, dto.RevisionFieldChange{Field: field, Old: ptrValue(from), New: ptrValue(to)})
+}
+
+func ptrValue[T any](value *T) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// courseChanges liệt kê các field course khác nhau (Field = tên cột)
+func courseChanges(from, to *dto.CourseSnapshot) []dto.RevisionFieldChange {
+	var changes []dto.RevisionFieldChange
+	changes = appendChange(changes, "title", from.Title, to.Title)
+	changes = appendChange(changes, "short_desc", from.ShortDesc, to.ShortDesc)
+	changes = appendChange(changes, "description", from.Description, to.Description)
+	changes = appendChange(changes, "category_id", from.CategoryId, to.CategoryId)
+	changes = appendChange(changes, "level", from.Level, to.Level)
+	changes = appendChange(changes, "language", from.Language, to.Language)
+	changes = appendChange(changes, "requirements", from.Requirements, to.Requirements)
+	changes = appendChange(changes, "what_you_learn", from.WhatYouLearn, to.WhatYouLearn)
+	changes = appendChange(changes, "duration_hours", from.DurationHours, to.DurationHours)
+	changes = appendChange(changes, "thumbnail_url", from.ThumbnailURL, to.ThumbnailURL)
+	return changes
+}
+
+// lessonChanges liệt kê các field lesson khác nhau (Field = tên cột, không gồm thứ tự)
+func lessonChanges(from, to *dto.LessonSnapshot) []dto.RevisionFieldChange {
+	var changes []dto.RevisionFieldChange
+	changes = appendChange(changes, "title", from.Title, to.Title)
+	changes = appendChange(changes, "description", from.Description, to.Description)
+	changes = appendChange(changes, "content", from.Content, to.Content)
+	changes = appendChange(changes, "video_url", from.VideoURL, to.VideoURL)
+	changes = appendChange(changes, "video_duration", from.VideoDuration, to.VideoDuration)
+	changes = appendChange(changes, "is_preview", from.IsPreview, to.IsPreview)
+	changes = appendChange(changes, "is_published", from.IsPublished, to.IsPublished)
+	changes = appendPtrChange(changes, "unlock_after_days", from.UnlockAfterDays, to.UnlockAfterDays)
+	changes = appendChange(changes, "require_previous_completion", from.RequirePreviousCompletion, to.RequirePreviousCompletion)
+	changes = appendPtrChange(changes, "prerequisite_quiz_id", from.PrerequisiteQuizId, to.PrerequisiteQuizId)
+
+	// time.Time phải so sánh bằng Equal (timezone có thể khác sau khi đọc từ DB)
+	if (from.UnlockAt == nil) != (to.UnlockAt == nil) || from.UnlockAt != nil && !from.UnlockAt.Equal(*to.UnlockAt) {
+		changes = append(changes, dto.RevisionFieldChange{Field: "unlock_at", Old: ptrValue(from.UnlockAt), New: ptrValue(to.UnlockAt)})
+	}
+	return changes
+}
+
+func toRevisionLessonRef(lesson *dto.LessonSnapshot) dto.RevisionLessonRef {
+	return dto.RevisionLessonRef{
+		Key:      lesson.Key,
+		LessonId: lesson.LessonId,
+		Title:    lesson.Title,
+	}
+}
+
+// diffSnapshots so sánh hai snapshot; lesson được ghép theo key
+func diffSnapshots(from, to *dto.CourseSnapshot, fromVersion, toVersion int) dto.CourseRevisionDiff {
+	diff := dto.CourseRevisionDiff{
+		FromVersion:     fromVersion,
+		ToVersion:       toVersion,
+		Course:          courseChanges(from, to),
+		AddedLessons:    []dto.RevisionLessonRef{},
+		RemovedLessons:  []dto.RevisionLessonRef{},
+		ModifiedLessons: []dto.RevisionLessonChange{},
+	}
+	if diff.Course == nil {
+		diff.Course = []dto.RevisionFieldChange{}
+	}
+
+	// 1. Lesson thêm mới / sửa
+	var fromOrder, toOrder []string
+	for i := range to.Lessons {
+		lesson := &to.Lessons[i]
+		index := findSnapshotLesson(from, lesson.Key)
+		if index < 0 {
+			diff.AddedLessons = append(diff.AddedLessons, toRevisionLessonRef(lesson))
+			continue
+		}
+
+		toOrder = append(toOrder, lesson.Key)
+		if changes := lessonChanges(&from.Lessons[index], lesson); len(changes) > 0 {
+			diff.ModifiedLessons = append(diff.ModifiedLessons, dto.RevisionLessonChange{
+				RevisionLessonRef: toRevisionLessonRef(lesson),
+				Changes:           changes,
+			})
+		}
+	}
+
+	// 2. Lesson bị xóa
+	for i := range from.Lessons {
+		lesson := &from.Lessons[i]
+		if findSnapshotLesson(to, lesson.Key) < 0 {
+			diff.RemovedLessons = append(diff.RemovedLessons, toRevisionLessonRef(lesson))
+			continue
+		}
+		fromOrder = append(fromOrder, lesson.Key)
+	}
+
+	// 3. Thứ tự của các lesson còn lại có thay đổi không
+	diff.Reordered = strings.Join(fromOrder, ",") != strings.Join(toOrder, ",")
+
+	diff.HasChanges = len(diff.Course) > 0 || len(diff.AddedLessons) > 0 || len(diff.RemovedLessons) > 0 ||
+		len(diff.ModifiedLessons) > 0 || diff.Reordered
+	return diff
+}
+
+// diffSummary tóm tắt diff thành một câu ngắn để hiển thị trong danh sách revision
+func diffSummary(diff *dto.CourseRevisionDiff) string {
+	var parts []string
+	if len(diff.Course) > 0 {
+		fields := make([]string, 0, len(diff.Course))
+		for _, change := range diff.Course {
+			fields = append(fields, change.Field)
+		}
+		parts = append(parts, "course: "+strings.Join(fields, ", "))
+	}
+	if n := len(diff.AddedLessons); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d lesson(s) added", n))
+	}
+	if n := len(diff.RemovedLessons); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d lesson(s) removed", n))
+	}
+	if n := len(diff.ModifiedLessons); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d lesson(s) modified", n))
+	}
+	if diff.Reordered {
+		parts = append(parts, "lessons reordered")
+	}
+
+	if len(parts) == 0 {
+		return "No changes"
+	}
+	summary := strings.Join(parts, "; ")
+	if len(summary) > 500 {
+		summary = summary[:497] + "..."
+	}
+	return summary
+}
+
+// ---------------- Apply ----------------
+
+// lessonSlugSet giữ slug đang được dùng trong course (slug -> lesson id)
+type lessonSlugSet map[string]uint
+
+func (ls lessonSlugSet) claim(title string, lessonId uint) string {
+	baseSlug := utils.GenerateSlug(title)
+	slug := baseSlug
+	for counter := 1; ; counter++ {
+		if owner, taken := ls[slug]; !taken || owner == lessonId {
+			break
+		}
+		slug = fmt.Sprintf("%s-%d", baseSlug, counter)
+	}
+
+	ls[slug] = lessonId
+	return slug
+}
+
+// applySnapshot đưa nội dung live của course từ base sang target (three-way):
+// chỉ các field thay đổi giữa base và target được ghi, lesson giữ nguyên id nên progress của học viên được giữ lại.
+// restore = true cho phép khôi phục lesson đã xóa mềm (rollback).
+func applySnapshot(repos *repository.TxRepositories, courseId uint, base, target *dto.CourseSnapshot, restore bool) error {
+	// 1. Metadata course (slug giữ nguyên để link của học viên không bị hỏng)
+	courseUpdates := make(map[string]interface{})
+	for _, change := range courseChanges(base, target) {
+		courseUpdates[change.Field] = change.New
+	}
+	if len(courseUpdates) > 0 {
+		if err := repos.CourseRevisions.UpdateCourse(courseId, courseUpdates); err != nil {
+			return err
+		}
+	}
+
+	// 2. Lessons hiện có (kèm lesson đã xóa mềm khi rollback)
+	lessons, err := repos.CourseRevisions.FindLessons(courseId, restore)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[uint]*models.Lesson, len(lessons))
+	slugs := make(lessonSlugSet, len(lessons))
+	for i := range lessons {
+		existing[lessons[i].Id] = &lessons[i]
+		if !lessons[i].DeletedAt.Valid {
+			slugs[lessons[i].Slug] = lessons[i].Id
+		}
+	}
+
+	// 3. Tạo / cập nhật lesson theo thứ tự của target
+	kept := make(map[uint]bool, len(target.Lessons))
+	for i := range target.Lessons {
+		item := &target.Lessons[i]
+		order := i + 1
+
+		lesson, ok := existing[item.LessonId]
+		if item.LessonId == 0 || !ok {
+			newLesson := &models.Lesson{
+				CourseId:                  courseId,
+				Title:                     item.Title,
+				Slug:                      slugs.claim(item.Title, 0),
+				Description:               item.Description,
+				Content:                   item.Content,
+				VideoURL:                  item.VideoURL,
+				VideoDuration:             item.VideoDuration,
+				LessonOrder:               order,
+				IsPreview:                 item.IsPreview,
+				IsPublished:               item.IsPublished,
+				UnlockAfterDays:           item.UnlockAfterDays,
+				UnlockAt:                  item.UnlockAt,
+				RequirePreviousCompletion: item.RequirePreviousCompletion,
+				PrerequisiteQuizId:        item.PrerequisiteQuizId,
+			}
+			if err := repos.CourseRevisions.CreateLesson(newLesson); err != nil {
+				return err
+			}
+			slugs[newLesson.Slug] = newLesson.Id
+			continue
+		}
+		kept[lesson.Id] = true
+
+		// So với base để giữ các field không đổi; lesson đã xóa thì so với dữ liệu trong DB
+		from := toLessonSnapshot(lesson)
+		if index := findSnapshotLesson(base, item.Key); index >= 0 && !lesson.DeletedAt.Valid {
+			from = base.Lessons[index]
+		}
+
+		updates := make(map[string]interface{})
+		for _, change := range lessonChanges(&from, item) {
+			updates[change.Field] = change.New
+		}
+		if _, ok := updates["title"]; ok {
+			updates["slug"] = slugs.claim(item.Title, lesson.Id)
+		} else if owner, taken := slugs[lesson.Slug]; lesson.DeletedAt.Valid && taken && owner != lesson.Id {
+			// Slug của lesson được khôi phục đã bị lesson khác dùng
+			updates["slug"] = slugs.claim(item.Title, lesson.Id)
+		} else {
+			slugs[lesson.Slug] = lesson.Id
+		}
+		if lesson.LessonOrder != order {
+			updates["lesson_order"] = order
+		}
+
+		if len(updates) > 0 || lesson.DeletedAt.Valid {
+			if err := repos.CourseRevisions.UpdateLesson(lesson.Id, updates, lesson.DeletedAt.Valid); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 4. Xóa mềm lesson có trong base nhưng không còn trong target
+	var removed []uint
+	for _, item := range base.Lessons {
+		if lesson, ok := existing[item.LessonId]; ok && !kept[lesson.Id] && !lesson.DeletedAt.Valid {
+			removed = append(removed, lesson.Id)
+		}
+	}
+	return repos.CourseRevisions.DeleteLessons(courseId, removed)
+}
+
+// liveSnapshot chụp lại nội dung course trong transaction (sau khi apply)
+func liveSnapshot(repos *repository.TxRepositories, courseId uint) (*models.Course, *dto.CourseSnapshot, error) {
+	course, err := repos.CourseRevisions.LockCourse(courseId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lessons, err := repos.CourseRevisions.FindLessons(courseId, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snapshot := buildCourseSnapshot(course, lessons)
+	return course, &snapshot, nil
+}
+
+// ensureBaselineRevision đảm bảo nội dung đang live đã được lưu thành version mới nhất.
+// Lần đầu tạo version 1; nếu course bị sửa ngoài quy trình revision (vd. khi đang archived) thì ghi thêm một version.
+func ensureBaselineRevision(repos *repository.TxRepositories, courseId, userId uint) (*models.CourseRevision, error) {
+	_, live, err := liveSnapshot(repos, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := repos.CourseRevisions.FindLatestPublished(courseId)
+	if err != nil {
+		return newPublishedRevision(repos, courseId, userId, live, "Initial version", fmt.Sprintf("%d lesson(s)", len(live.Lessons)), nil)
+	}
+
+	latestSnapshot, err := decodeSnapshot(latest)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := diffSnapshots(latestSnapshot, live, latest.Version, 0)
+	if !diff.HasChanges {
+		return latest, nil
+	}
+	return newPublishedRevision(repos, courseId, userId, live, "Changes made outside the revision workflow", diffSummary(&diff), nil)
+}
+
+// newPublishedRevision tạo revision published với version kế tiếp
+func newPublishedRevision(repos *repository.TxRepositories, courseId, userId uint, snapshot *dto.CourseSnapshot, changelog, summary string, restoredFrom *int) (*models.CourseRevision, error) {
+	encoded, err := encodeSnapshot(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := repos.CourseRevisions.NextVersion(courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	revision := &models.CourseRevision{
+		CourseId:            courseId,
+		Version:             version,
+		Status:              revisionStatusPublished,
+		Snapshot:            encoded,
+		RestoredFromVersion: restoredFrom,
+		Changelog:           changelog,
+		Summary:             summary,
+		CreatedBy:           userId,
+		PublishedBy:         &userId,
+		PublishedAt:         &now,
+	}
+	if err := repos.CourseRevisions.Create(revision); err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
@@ -7,13 +7,23 @@ import (
 	"math"
 )
 
+// Số đoạn transcript khớp tối đa trả về cho mỗi course khi search
+const transcriptMatchesPerCourse = 3
+
+// Số bundle tối đa hiển thị cùng danh sách course
+const listedBundlesLimit = 4
+
 type courseService struct {
-	courseRepo repository.CourseRepository
+	courseRepo       repository.CourseRepository
+	learningPathRepo repository.LearningPathRepository
+	bundleRepo       repository.BundleRepository
 }
 
-func NewCourseService(courseRepo repository.CourseRepository) CourseService {
+func NewCourseService(courseRepo repository.CourseRepository, learningPathRepo repository.LearningPathRepository, bundleRepo repository.BundleRepository) CourseService {
 	return &courseService{
-		courseRepo: courseRepo,
+		courseRepo:       courseRepo,
+		learningPathRepo: learningPathRepo,
+		bundleRepo:       bundleRepo,
 	}
 }
 
@@ -140,9 +150,31 @@ func (cs *courseService) GetCourses(req *dto.GetCoursesQueryRequest) (*dto.GetCo
 		HasPrev:    hasPrev,
 	}
 
+	// Bundle đang bán hiển thị ở trang đầu; bộ lọc theo category/instructor/level không áp dụng cho bundle
+	var bundleItems []dto.BundleItem
+	if page == 1 && req.CategoryId == nil && req.InstructorId == nil && req.Level == "" {
+		bundleFilters := map[string]interface{}{
+			"status": "published",
+		}
+		if req.Search != "" {
+			bundleFilters["search"] = req.Search
+		}
+
+		bundles, _, err := cs.bundleRepo.GetBundles(0, listedBundlesLimit, bundleFilters, "created_at", "desc")
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to get bundles", utils.ErrCodeInternal)
+		}
+
+		bundleItems = make([]dto.BundleItem, len(bundles))
+		for i := range bundles {
+			bundleItems[i] = toBundleItem(&bundles[i])
+		}
+	}
+
 	return &dto.GetCoursesResponse{
 		Courses:    courseItems,
 		Pagination: pagination,
+		Bundles:    bundleItems,
 	}, nil
 
 }
@@ -233,6 +265,25 @@ func (cs *courseService) SearchCourses(req *dto.SearchCoursesQueryRequest) (*dto
 		}
 	}
 
+	// Gắn các đoạn transcript khớp từ khóa để client tua tới đúng thời điểm
+	courseIds := make([]uint, len(courses))
+	for i, course := range courses {
+		courseIds[i] = course.Id
+	}
+
+	transcriptMatches, err := cs.courseRepo.SearchTranscripts(courseIds, req.Q, transcriptMatchesPerCourse)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to search transcripts", utils.ErrCodeInternal)
+	}
+
+	matchesByCourse := make(map[uint][]dto.TranscriptMatch)
+	for _, match := range transcriptMatches {
+		matchesByCourse[match.CourseId] = append(matchesByCourse[match.CourseId], match)
+	}
+	for i := range courseItems {
+		courseItems[i].TranscriptMatches = matchesByCourse[courseItems[i].Id]
+	}
+
 	// Get search filters
 	searchFilters, err := cs.courseRepo.GetSearchFilters(req.Q)
 	if err != nil {
@@ -323,9 +374,21 @@ func (cs *courseService) GetFeaturedCourses(req *dto.GetFeaturedCoursesQueryRequ
 		}
 	}
 
+	// Learning paths nổi bật hiển thị cùng featured courses
+	paths, err := cs.learningPathRepo.GetFeaturedPaths(limit)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get featured learning paths", utils.ErrCodeInternal)
+	}
+
+	pathItems := make([]dto.LearningPathItem, len(paths))
+	for i := range paths {
+		pathItems[i] = toLearningPathItem(&paths[i])
+	}
+
 	return &dto.GetFeaturedCoursesResponse{
-		Courses: courseItems,
-		Total:   total,
+		Courses:       courseItems,
+		Total:         total,
+		FeaturedPaths: pathItems,
 	}, nil
 
 }
@@ -356,6 +419,32 @@ func (cs *courseService) GetCourseBySlug(slug string) (*dto.CourseDetail, error)
 		categoryName = course.Category.Name
 	}
 
+	// Get prerequisites
+	prerequisites, err := cs.courseRepo.GetCoursePrerequisites(course.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get course prerequisites", utils.ErrCodeInternal)
+	}
+
+	prerequisiteItems := make([]dto.CoursePrerequisiteItem, len(prerequisites))
+	for i, prerequisite := range prerequisites {
+		prerequisiteItems[i] = dto.CoursePrerequisiteItem{
+			CourseId: prerequisite.Id,
+			Title:    prerequisite.Title,
+			Slug:     prerequisite.Slug,
+		}
+	}
+
+	// Get bundles có chứa course
+	bundles, err := cs.bundleRepo.GetPublishedBundlesByCourse(course.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get course bundles", utils.ErrCodeInternal)
+	}
+
+	bundleItems := make([]dto.BundleItem, len(bundles))
+	for i := range bundles {
+		bundleItems[i] = toBundleItem(&bundles[i])
+	}
+
 	return &dto.CourseDetail{
 		Id:              course.Id,
 		Title:           course.Title,
@@ -384,5 +473,10 @@ func (cs *courseService) GetCourseBySlug(slug string) (*dto.CourseDetail, error)
 		EnrolledCount:   course.EnrolledCount,
 		CreatedAt:       course.CreatedAt,
 		UpdatedAt:       course.UpdatedAt,
+
+		PrerequisiteMode: course.PrerequisiteMode,
+		Prerequisites:    prerequisiteItems,
+
+		Bundles: bundleItems,
 	}, nil
 }
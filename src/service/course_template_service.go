@@ -0,0 +1,360 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+	"math"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type courseTemplateService struct {
+	templateRepo   repository.CourseTemplateRepository
+	courseRepo     repository.CourseRepository
+	instructorRepo repository.InstructorRepository
+	transactor     repository.Transactor
+	store          storage.Storage
+}
+
+func NewCourseTemplateService(
+	templateRepo repository.CourseTemplateRepository,
+	courseRepo repository.CourseRepository,
+	instructorRepo repository.InstructorRepository,
+	transactor repository.Transactor,
+	store storage.Storage,
+) CourseTemplateService {
+	return &courseTemplateService{
+		templateRepo:   templateRepo,
+		courseRepo:     courseRepo,
+		instructorRepo: instructorRepo,
+		transactor:     transactor,
+		store:          store,
+	}
+}
+
+func (ts *courseTemplateService) DuplicateCourse(instructorId, courseId uint, req *dto.DuplicateCourseRequest) (*dto.DuplicateCourseResponse, error) {
+	// 1. Chỉ duplicate course của chính mình hoặc course template
+	source, err := ts.courseRepo.FindById(courseId)
+	if err != nil || (source.InstructorId != instructorId && !source.IsTemplate) {
+		return nil, utils.NewError("course not found or you don't have permission to duplicate this course", utils.ErrCodeNotFound)
+	}
+
+	// 2. Đọc nội dung cần copy
+	lessons, err := ts.templateRepo.FindLessons(courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to load lessons", utils.ErrCodeInternal)
+	}
+
+	quizzes, err := ts.templateRepo.FindQuizzes(courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to load quizzes", utils.ErrCodeInternal)
+	}
+
+	lessonIds := make([]uint, len(lessons))
+	for i := range lessons {
+		lessonIds[i] = lessons[i].Id
+	}
+	attachments, err := ts.templateRepo.FindAttachments(lessonIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to load attachments", utils.ErrCodeInternal)
+	}
+
+	prerequisiteIds, err := ts.instructorRepo.GetPrerequisiteIds(courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to load prerequisites", utils.ErrCodeInternal)
+	}
+
+	title := req.Title
+	if title == "" {
+		title = source.Title + " (Copy)"
+	}
+
+	// 3. Tạo bản copy trong một transaction; file đã copy lên storage được dọn nếu transaction lỗi
+	var course *models.Course
+	var copiedKeys []string
+	quizzesCopied := 0
+	err = ts.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		// 3.1 Course mới luôn là draft của instructor đang thao tác
+		course = &models.Course{
+			Title:            title,
+			Slug:             utils.GenerateUniqueSlug(utils.GenerateSlug(title), repos.CourseTemplates.CourseSlugExists),
+			Description:      source.Description,
+			ShortDesc:        source.ShortDesc,
+			ThumbnailURL:     ts.copyThumbnail(source.ThumbnailURL, instructorId, &copiedKeys),
+			VideoPreviewURL:  source.VideoPreviewURL,
+			Price:            source.Price,
+			DiscountPrice:    source.DiscountPrice,
+			InstructorId:     instructorId,
+			CategoryId:       source.CategoryId,
+			Level:            source.Level,
+			DurationHours:    source.DurationHours,
+			Language:         source.Language,
+			Requirements:     source.Requirements,
+			WhatYouLearn:     source.WhatYouLearn,
+			Status:           "draft",
+			PrerequisiteMode: source.PrerequisiteMode,
+			CopiedFromId:     &source.Id,
+		}
+		if err := repos.CourseTemplates.CreateCourse(course); err != nil {
+			return utils.WrapError(err, "failed to create course", utils.ErrCodeInternal)
+		}
+
+		if err := repos.CourseTemplates.CreatePrerequisites(course.Id, prerequisiteIds); err != nil {
+			return utils.WrapError(err, "failed to copy prerequisites", utils.ErrCodeInternal)
+		}
+
+		// 3.2 Lessons (video đã transcode không được copy, chỉ giữ video_url)
+		lessonMap := make(map[uint]*models.Lesson, len(lessons))
+		for i := range lessons {
+			src := &lessons[i]
+			lesson := &models.Lesson{
+				CourseId:                  course.Id,
+				Title:                     src.Title,
+				Slug:                      src.Slug,
+				Description:               src.Description,
+				Content:                   src.Content,
+				VideoURL:                  src.VideoURL,
+				VideoDuration:             src.VideoDuration,
+				LessonOrder:               src.LessonOrder,
+				IsPreview:                 src.IsPreview,
+				IsPublished:               src.IsPublished,
+				UnlockAfterDays:           src.UnlockAfterDays,
+				UnlockAt:                  src.UnlockAt,
+				RequirePreviousCompletion: src.RequirePreviousCompletion,
+			}
+			if err := repos.CourseTemplates.CreateLesson(lesson); err != nil {
+				return utils.WrapError(err, "failed to copy lessons", utils.ErrCodeInternal)
+			}
+			lessonMap[src.Id] = lesson
+		}
+
+		// 3.3 Quizzes và câu hỏi của các lesson đã copy
+		quizMap := make(map[uint]uint, len(quizzes))
+		for i := range quizzes {
+			src := &quizzes[i]
+			lesson, ok := lessonMap[src.LessonId]
+			if !ok {
+				continue
+			}
+
+			questions := make([]models.QuizQuestion, len(src.Questions))
+			for j, question := range src.Questions {
+				questions[j] = models.QuizQuestion{
+					Question:      question.Question,
+					Options:       question.Options,
+					CorrectOption: question.CorrectOption,
+					QuestionOrder: question.QuestionOrder,
+				}
+			}
+
+			quiz := &models.Quiz{
+				CourseId:     course.Id,
+				LessonId:     lesson.Id,
+				Title:        src.Title,
+				Description:  src.Description,
+				PassingScore: src.PassingScore,
+				Questions:    questions,
+			}
+			if err := repos.CourseTemplates.CreateQuiz(quiz); err != nil {
+				return utils.WrapError(err, "failed to copy quizzes", utils.ErrCodeInternal)
+			}
+			quizMap[src.Id] = quiz.Id
+			quizzesCopied++
+		}
+
+		// 3.4 Quiz bắt buộc trỏ sang quiz của bản copy
+		for i := range lessons {
+			src := &lessons[i]
+			if src.PrerequisiteQuizId == nil {
+				continue
+			}
+			quizId, ok := quizMap[*src.PrerequisiteQuizId]
+			if !ok {
+				continue
+			}
+			if err := repos.CourseTemplates.SetLessonPrerequisiteQuiz(lessonMap[src.Id].Id, quizId); err != nil {
+				return utils.WrapError(err, "failed to copy lesson prerequisites", utils.ErrCodeInternal)
+			}
+		}
+
+		// 3.5 Tài liệu đính kèm: copy file sang prefix của lesson mới
+		for i := range attachments {
+			src := &attachments[i]
+			lesson := lessonMap[src.LessonId]
+
+			key := lessonAttachmentPrefix(lesson.Id) + uuid.New().String() + strings.ToLower(path.Ext(src.StorageKey))
+			if err := ts.copyObject(src.StorageKey, key, src.FileSize, src.MimeType); err != nil {
+				return utils.WrapError(err, fmt.Sprintf("failed to copy attachment %q", src.FileName), utils.ErrCodeInternal)
+			}
+			copiedKeys = append(copiedKeys, key)
+
+			attachment := &models.LessonAttachment{
+				LessonId:   lesson.Id,
+				Title:      src.Title,
+				FileName:   src.FileName,
+				FileSize:   src.FileSize,
+				MimeType:   src.MimeType,
+				StorageKey: key,
+			}
+			if err := repos.CourseTemplates.CreateAttachment(attachment); err != nil {
+				return utils.WrapError(err, "failed to copy attachments", utils.ErrCodeInternal)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for _, key := range copiedKeys {
+			if deleteErr := ts.store.Delete(key); deleteErr != nil {
+				log.Printf("Failed to delete copied object %s: %v", key, deleteErr)
+			}
+		}
+		return nil, err
+	}
+
+	return &dto.DuplicateCourseResponse{
+		Message:           "Course duplicated successfully",
+		CourseId:          course.Id,
+		Title:             course.Title,
+		Slug:              course.Slug,
+		Status:            course.Status,
+		CopiedFromId:      source.Id,
+		LessonsCopied:     len(lessons),
+		QuizzesCopied:     quizzesCopied,
+		AttachmentsCopied: len(attachments),
+	}, nil
+}
+
+func (ts *courseTemplateService) GetTemplates(req *dto.GetCourseTemplatesQueryRequest) (*dto.GetCourseTemplatesResponse, error) {
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	filters := make(map[string]interface{})
+	if req.Search != "" {
+		filters["search"] = req.Search
+	}
+	if req.CategoryId != 0 {
+		filters["category_id"] = req.CategoryId
+	}
+
+	courses, total, err := ts.templateRepo.GetTemplates(offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to get course templates", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.CourseTemplateItem, len(courses))
+	for i := range courses {
+		course := &courses[i]
+		items[i] = dto.CourseTemplateItem{
+			Id:             course.Id,
+			Title:          course.Title,
+			Slug:           course.Slug,
+			ShortDesc:      course.ShortDesc,
+			ThumbnailURL:   course.ThumbnailURL,
+			CategoryId:     course.CategoryId,
+			CategoryName:   course.Category.Name,
+			Level:          course.Level,
+			Language:       course.Language,
+			InstructorId:   course.InstructorId,
+			InstructorName: course.Instructor.FullName,
+			UpdatedAt:      course.UpdatedAt,
+		}
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetCourseTemplatesResponse{
+		Templates: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (ts *courseTemplateService) SetTemplate(courseId uint, req *dto.SetCourseTemplateRequest) (*dto.SetCourseTemplateResponse, error) {
+	if _, err := ts.courseRepo.FindById(courseId); err != nil {
+		return nil, utils.NewError("course not found", utils.ErrCodeNotFound)
+	}
+
+	if err := ts.templateRepo.SetTemplate(courseId, *req.IsTemplate); err != nil {
+		return nil, utils.WrapError(err, "failed to update course template", utils.ErrCodeInternal)
+	}
+
+	message := "Course marked as template"
+	if !*req.IsTemplate {
+		message = "Course is no longer a template"
+	}
+
+	return &dto.SetCourseTemplateResponse{
+		Message:    message,
+		CourseId:   courseId,
+		IsTemplate: *req.IsTemplate,
+	}, nil
+}
+
+// copyObject copy một object trên storage sang key mới
+func (ts *courseTemplateService) copyObject(srcKey, dstKey string, size int64, contentType string) error {
+	body, err := ts.store.Get(srcKey)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return ts.store.Put(dstKey, body, size, contentType)
+}
+
+// copyThumbnail copy thumbnail vào thư mục của instructor sở hữu bản copy để bản copy không bị mất ảnh khi course gốc bị xóa.
+// URL bên ngoài storage được giữ nguyên; key ngoài thumbnails/ (vd. file private) hoặc copy lỗi thì bỏ trống thumbnail.
+func (ts *courseTemplateService) copyThumbnail(thumbnailURL string, ownerId uint, copiedKeys *[]string) string {
+	srcKey, ok := storage.KeyFromURL(ts.store, thumbnailURL)
+	if !ok {
+		return thumbnailURL
+	}
+	if !strings.HasPrefix(srcKey, "thumbnails/") || strings.Contains(srcKey, "..") {
+		log.Printf("Skipped copying thumbnail %s outside thumbnails/", srcKey)
+		return ""
+	}
+
+	body, err := ts.store.Get(srcKey)
+	if err != nil {
+		log.Printf("Failed to read thumbnail %s: %v", srcKey, err)
+		return ""
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		log.Printf("Failed to read thumbnail %s: %v", srcKey, err)
+		return ""
+	}
+
+	ext := strings.ToLower(path.Ext(srcKey))
+	key := fmt.Sprintf("%s%s%s", storage.OwnerPrefix("thumbnails", ownerId), uuid.New().String(), ext)
+	if err := ts.store.Put(key, bytes.NewReader(data), int64(len(data)), mime.TypeByExtension(ext)); err != nil {
+		log.Printf("Failed to copy thumbnail %s: %v", srcKey, err)
+		return ""
+	}
+
+	*copiedKeys = append(*copiedKeys, key)
+	return ts.store.URL(key)
+}
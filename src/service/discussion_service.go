@@ -0,0 +1,624 @@
+package service
+
+import (
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"math"
+	"strings"
+	"time"
+)
+
+// discussionAccess là quyền của user trong Q&A của một course
+type discussionAccess struct {
+	course     *models.Course
+	isStaff    bool // instructor của course, TA hoặc admin
+	assistants map[uint]bool
+}
+
+type discussionService struct {
+	discussionRepo repository.DiscussionRepository
+	courseRepo     repository.CourseRepository
+	lessonRepo     repository.LessonRepository
+	userRepo       repository.UserRepository
+	notifier       Notifier
+}
+
+func NewDiscussionService(
+	discussionRepo repository.DiscussionRepository,
+	courseRepo repository.CourseRepository,
+	lessonRepo repository.LessonRepository,
+	userRepo repository.UserRepository,
+	notifier Notifier,
+) DiscussionService {
+	return &discussionService{
+		discussionRepo: discussionRepo,
+		courseRepo:     courseRepo,
+		lessonRepo:     lessonRepo,
+		userRepo:       userRepo,
+		notifier:       notifier,
+	}
+}
+
+func (ds *discussionService) GetThreads(userId uint, role string, courseId uint, req *dto.GetThreadsQueryRequest) (*dto.GetThreadsResponse, error) {
+	// 1. Kiểm tra quyền truy cập Q&A của course
+	access, err := ds.getAccess(userId, role, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	// 3. Prepare filters
+	filters := map[string]interface{}{"course_id": courseId}
+	if req.LessonId != nil {
+		filters["lesson_id"] = *req.LessonId
+	}
+	if req.Unanswered != nil {
+		filters["is_answered"] = !*req.Unanswered
+	}
+	if req.Search != "" {
+		filters["search"] = req.Search
+	}
+	if !access.isStaff {
+		filters["visible_to"] = userId
+	}
+
+	threads, total, err := ds.discussionRepo.GetThreads(offset, limit, filters, req.SortBy)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get discussions", utils.ErrCodeInternal)
+	}
+
+	// 4. Đánh dấu các thread user đã upvote
+	threadIds := make([]uint, len(threads))
+	for i, thread := range threads {
+		threadIds[i] = thread.Id
+	}
+
+	upvoted, err := ds.discussionRepo.GetUserUpvotes(userId, "thread", threadIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get upvotes", utils.ErrCodeInternal)
+	}
+
+	threadItems := make([]dto.DiscussionThreadItem, len(threads))
+	for i := range threads {
+		threadItems[i] = toDiscussionThreadItem(&threads[i], access, upvoted[threads[i].Id])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetThreadsResponse{
+		Threads: threadItems,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (ds *discussionService) CreateThread(userId uint, role string, courseId uint, req *dto.CreateThreadRequest) (*dto.DiscussionThreadItem, error) {
+	// 1. Chỉ user đã enroll (hoặc instructor/TA) mới được đặt câu hỏi
+	access, err := ds.getAccess(userId, role, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Lesson (nếu có) phải thuộc course
+	var lesson *models.Lesson
+	if req.LessonId != nil {
+		lessons, err := ds.lessonRepo.FindLessonByIds([]uint{*req.LessonId})
+		if err != nil || len(lessons) == 0 || lessons[0].CourseId != courseId {
+			return nil, utils.NewError("Lesson not found in this course", utils.ErrCodeNotFound)
+		}
+		lesson = &lessons[0]
+	}
+
+	// 3. Tạo thread
+	thread := &models.DiscussionThread{
+		CourseId:         courseId,
+		LessonId:         req.LessonId,
+		UserId:           userId,
+		Title:            strings.TrimSpace(req.Title),
+		Body:             strings.TrimSpace(req.Body),
+		IsInstructorOnly: req.IsInstructorOnly,
+		LastActivityAt:   time.Now(),
+	}
+
+	if err := ds.discussionRepo.CreateThread(thread); err != nil {
+		return nil, utils.WrapError(err, "Failed to create discussion", utils.ErrCodeInternal)
+	}
+
+	author, err := ds.userRepo.FindById(userId)
+	if err == nil {
+		thread.User = *author
+	}
+	thread.Lesson = lesson
+
+	item := toDiscussionThreadItem(thread, access, false)
+	return &item, nil
+}
+
+func (ds *discussionService) GetThreadDetail(userId uint, role string, threadId uint) (*dto.DiscussionThreadDetail, error) {
+	// 1. Lấy thread và kiểm tra quyền xem
+	thread, access, err := ds.getVisibleThread(userId, role, threadId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Lấy replies
+	replies, err := ds.discussionRepo.GetReplies(thread.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get replies", utils.ErrCodeInternal)
+	}
+
+	// 3. Đánh dấu thread/replies user đã upvote
+	threadUpvoted, err := ds.discussionRepo.GetUserUpvotes(userId, "thread", []uint{thread.Id})
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get upvotes", utils.ErrCodeInternal)
+	}
+
+	replyIds := make([]uint, len(replies))
+	for i, reply := range replies {
+		replyIds[i] = reply.Id
+	}
+
+	replyUpvoted, err := ds.discussionRepo.GetUserUpvotes(userId, "reply", replyIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get upvotes", utils.ErrCodeInternal)
+	}
+
+	replyItems := make([]dto.DiscussionReplyItem, len(replies))
+	for i := range replies {
+		replyItems[i] = toDiscussionReplyItem(&replies[i], access, replyUpvoted[replies[i].Id])
+	}
+
+	return &dto.DiscussionThreadDetail{
+		Thread:  toDiscussionThreadItem(thread, access, threadUpvoted[thread.Id]),
+		Replies: replyItems,
+	}, nil
+}
+
+func (ds *discussionService) DeleteThread(userId uint, role string, threadId uint) (*dto.DeleteDiscussionResponse, error) {
+	// 1. Lấy thread và kiểm tra quyền xem
+	thread, access, err := ds.getVisibleThread(userId, role, threadId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Chỉ người tạo hoặc instructor/TA được xóa
+	if thread.UserId != userId && !access.isStaff {
+		return nil, utils.NewError("You don't have permission to delete this discussion", utils.ErrCodeForbidden)
+	}
+
+	if err := ds.discussionRepo.DeleteThread(thread.Id); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete discussion", utils.ErrCodeInternal)
+	}
+
+	return &dto.DeleteDiscussionResponse{
+		Message: "Discussion deleted successfully",
+		Id:      thread.Id,
+	}, nil
+}
+
+func (ds *discussionService) CreateReply(userId uint, role string, threadId uint, req *dto.CreateReplyRequest) (*dto.DiscussionReplyItem, error) {
+	// 1. Lấy thread và kiểm tra quyền (enroll hoặc instructor/TA)
+	thread, access, err := ds.getVisibleThread(userId, role, threadId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Tạo reply
+	reply := &models.DiscussionReply{
+		ThreadId: thread.Id,
+		UserId:   userId,
+		Body:     strings.TrimSpace(req.Body),
+	}
+
+	if err := ds.discussionRepo.CreateReply(reply); err != nil {
+		return nil, utils.WrapError(err, "Failed to create reply", utils.ErrCodeInternal)
+	}
+
+	author, err := ds.userRepo.FindById(userId)
+	if err == nil {
+		reply.User = *author
+	}
+
+	// 3. Thông báo cho người đặt câu hỏi
+	if thread.UserId != userId {
+		ds.notifier.Notify(thread.UserId, notificationDiscussionReply,
+			"New reply to your question",
+			fmt.Sprintf("%s replied to \"%s\".", reply.User.FullName, thread.Title),
+			discussionThreadLink(access.course, thread.Id),
+		)
+	}
+
+	item := toDiscussionReplyItem(reply, access, false)
+	return &item, nil
+}
+
+func (ds *discussionService) DeleteReply(userId uint, role string, replyId uint) (*dto.DeleteDiscussionResponse, error) {
+	// 1. Lấy reply và thread
+	reply, err := ds.discussionRepo.FindReplyById(replyId)
+	if err != nil {
+		return nil, utils.NewError("Reply not found", utils.ErrCodeNotFound)
+	}
+
+	_, access, err := ds.getVisibleThread(userId, role, reply.ThreadId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Chỉ người viết hoặc instructor/TA được xóa
+	if reply.UserId != userId && !access.isStaff {
+		return nil, utils.NewError("You don't have permission to delete this reply", utils.ErrCodeForbidden)
+	}
+
+	if err := ds.discussionRepo.DeleteReply(reply); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete reply", utils.ErrCodeInternal)
+	}
+
+	return &dto.DeleteDiscussionResponse{
+		Message: "Reply deleted successfully",
+		Id:      reply.Id,
+	}, nil
+}
+
+func (ds *discussionService) UpvoteThread(userId uint, role string, threadId uint) (*dto.UpvoteResponse, error) {
+	// 1. Lấy thread và kiểm tra quyền
+	thread, _, err := ds.getVisibleThread(userId, role, threadId)
+	if err != nil {
+		return nil, err
+	}
+
+	if thread.UserId == userId {
+		return nil, utils.NewError("You cannot upvote your own discussion", utils.ErrCodeBadRequest)
+	}
+
+	// 2. Thêm/bỏ upvote
+	upvoted, count, err := ds.discussionRepo.ToggleUpvote(userId, "thread", thread.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to upvote discussion", utils.ErrCodeInternal)
+	}
+
+	return &dto.UpvoteResponse{Upvoted: upvoted, UpvoteCount: count}, nil
+}
+
+func (ds *discussionService) UpvoteReply(userId uint, role string, replyId uint) (*dto.UpvoteResponse, error) {
+	// 1. Lấy reply và kiểm tra quyền
+	reply, err := ds.discussionRepo.FindReplyById(replyId)
+	if err != nil {
+		return nil, utils.NewError("Reply not found", utils.ErrCodeNotFound)
+	}
+
+	if _, _, err := ds.getVisibleThread(userId, role, reply.ThreadId); err != nil {
+		return nil, err
+	}
+
+	if reply.UserId == userId {
+		return nil, utils.NewError("You cannot upvote your own reply", utils.ErrCodeBadRequest)
+	}
+
+	// 2. Thêm/bỏ upvote
+	upvoted, count, err := ds.discussionRepo.ToggleUpvote(userId, "reply", reply.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to upvote reply", utils.ErrCodeInternal)
+	}
+
+	return &dto.UpvoteResponse{Upvoted: upvoted, UpvoteCount: count}, nil
+}
+
+func (ds *discussionService) MarkAnswer(userId uint, role string, replyId uint, isAnswer bool) (*dto.DiscussionThreadDetail, error) {
+	// 1. Lấy reply và thread
+	reply, err := ds.discussionRepo.FindReplyById(replyId)
+	if err != nil {
+		return nil, utils.NewError("Reply not found", utils.ErrCodeNotFound)
+	}
+
+	thread, access, err := ds.getVisibleThread(userId, role, reply.ThreadId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Chỉ instructor hoặc TA được đánh dấu câu trả lời
+	if !access.isStaff {
+		return nil, utils.NewError("Only the instructor or a teaching assistant can mark answers", utils.ErrCodeForbidden)
+	}
+
+	// 3. Đánh dấu/bỏ đánh dấu
+	if isAnswer {
+		err = ds.discussionRepo.MarkAnswer(thread.Id, reply.Id)
+	} else if reply.IsAnswer {
+		err = ds.discussionRepo.UnmarkAnswer(thread.Id)
+	}
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to update answer", utils.ErrCodeInternal)
+	}
+
+	// 4. Thông báo cho người trả lời
+	if isAnswer && !reply.IsAnswer && reply.UserId != userId {
+		ds.notifier.Notify(reply.UserId, notificationDiscussionAnswer,
+			"Your reply was marked as the answer",
+			fmt.Sprintf("Your reply to \"%s\" was marked as the answer.", thread.Title),
+			discussionThreadLink(access.course, thread.Id),
+		)
+	}
+
+	return ds.GetThreadDetail(userId, role, thread.Id)
+}
+
+func (ds *discussionService) PinThread(userId uint, role string, threadId uint, req *dto.PinThreadRequest) (*dto.DiscussionThreadItem, error) {
+	return ds.moderateThread(userId, role, threadId, map[string]interface{}{"is_pinned": *req.IsPinned})
+}
+
+func (ds *discussionService) SetThreadVisibility(userId uint, role string, threadId uint, req *dto.ThreadVisibilityRequest) (*dto.DiscussionThreadItem, error) {
+	return ds.moderateThread(userId, role, threadId, map[string]interface{}{"is_instructor_only": *req.IsInstructorOnly})
+}
+
+func (ds *discussionService) GetUnansweredThreads(userId uint, req *dto.GetUnansweredQueryRequest) (*dto.GetThreadsResponse, error) {
+	// 1. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	filters := make(map[string]interface{})
+	if req.CourseId != nil {
+		filters["course_id"] = *req.CourseId
+	}
+
+	// 2. Lấy câu hỏi chưa trả lời trong các course user là instructor hoặc TA
+	threads, total, err := ds.discussionRepo.GetUnansweredThreads(userId, offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get unanswered questions", utils.ErrCodeInternal)
+	}
+
+	threadItems := make([]dto.DiscussionThreadItem, len(threads))
+	for i := range threads {
+		access := &discussionAccess{course: &threads[i].Course, isStaff: true}
+		threadItems[i] = toDiscussionThreadItem(&threads[i], access, false)
+		threadItems[i].CourseTitle = threads[i].Course.Title
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetThreadsResponse{
+		Threads: threadItems,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (ds *discussionService) GetCourseAssistants(userId uint, role string, courseId uint) (*dto.GetCourseAssistantsResponse, error) {
+	// 1. Chỉ instructor của course (hoặc admin) được quản lý TA
+	if _, err := ds.getOwnedCourse(userId, role, courseId); err != nil {
+		return nil, err
+	}
+
+	// 2. Lấy danh sách TA
+	assistants, err := ds.discussionRepo.GetCourseAssistants(courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get course assistants", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.CourseAssistantItem, len(assistants))
+	for i, assistant := range assistants {
+		items[i] = dto.CourseAssistantItem{
+			UserId:    assistant.UserId,
+			FullName:  assistant.User.FullName,
+			Email:     assistant.User.Email,
+			AvatarURL: assistant.User.AvatarURL,
+			CreatedAt: assistant.CreatedAt,
+		}
+	}
+
+	return &dto.GetCourseAssistantsResponse{
+		CourseId:   courseId,
+		Assistants: items,
+	}, nil
+}
+
+func (ds *discussionService) AddCourseAssistant(userId uint, role string, courseId uint, req *dto.AddCourseAssistantRequest) (*dto.GetCourseAssistantsResponse, error) {
+	// 1. Chỉ instructor của course (hoặc admin) được quản lý TA
+	course, err := ds.getOwnedCourse(userId, role, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Kiểm tra user được thêm
+	assistant, err := ds.userRepo.FindById(req.UserId)
+	if err != nil {
+		return nil, utils.NewError("User not found", utils.ErrCodeNotFound)
+	}
+	if assistant.Status != "active" {
+		return nil, utils.NewError("User account is not active", utils.ErrCodeBadRequest)
+	}
+	if assistant.Id == course.InstructorId {
+		return nil, utils.NewError("The course instructor cannot be added as an assistant", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Thêm TA
+	if err := ds.discussionRepo.AddCourseAssistant(&models.CourseAssistant{CourseId: courseId, UserId: assistant.Id}); err != nil {
+		return nil, utils.WrapError(err, "Failed to add course assistant", utils.ErrCodeInternal)
+	}
+
+	return ds.GetCourseAssistants(userId, role, courseId)
+}
+
+func (ds *discussionService) RemoveCourseAssistant(userId uint, role string, courseId, assistantId uint) (*dto.GetCourseAssistantsResponse, error) {
+	// 1. Chỉ instructor của course (hoặc admin) được quản lý TA
+	if _, err := ds.getOwnedCourse(userId, role, courseId); err != nil {
+		return nil, err
+	}
+
+	// 2. Xóa TA
+	if err := ds.discussionRepo.RemoveCourseAssistant(courseId, assistantId); err != nil {
+		return nil, utils.WrapError(err, "Failed to remove course assistant", utils.ErrCodeInternal)
+	}
+
+	return ds.GetCourseAssistants(userId, role, courseId)
+}
+
+// moderateThread cập nhật cờ của thread (ghim, hiển thị), chỉ dành cho instructor/TA
+func (ds *discussionService) moderateThread(userId uint, role string, threadId uint, updates map[string]interface{}) (*dto.DiscussionThreadItem, error) {
+	thread, access, err := ds.getVisibleThread(userId, role, threadId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !access.isStaff {
+		return nil, utils.NewError("Only the instructor or a teaching assistant can moderate discussions", utils.ErrCodeForbidden)
+	}
+
+	if err := ds.discussionRepo.UpdateThread(thread.Id, updates); err != nil {
+		return nil, utils.WrapError(err, "Failed to update discussion", utils.ErrCodeInternal)
+	}
+
+	thread, err = ds.discussionRepo.FindThreadById(thread.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get discussion", utils.ErrCodeInternal)
+	}
+
+	item := toDiscussionThreadItem(thread, access, false)
+	return &item, nil
+}
+
+// getAccess kiểm tra user có được tham gia Q&A của course: đã enroll, instructor, TA hoặc admin
+func (ds *discussionService) getAccess(userId uint, role string, courseId uint) (*discussionAccess, error) {
+	course, err := ds.courseRepo.FindById(courseId)
+	if err != nil {
+		return nil, utils.NewError("Course not found", utils.ErrCodeNotFound)
+	}
+
+	assistants, err := ds.discussionRepo.GetCourseAssistants(courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get course assistants", utils.ErrCodeInternal)
+	}
+
+	access := &discussionAccess{course: course, assistants: make(map[uint]bool)}
+	for _, assistant := range assistants {
+		access.assistants[assistant.UserId] = true
+	}
+	access.isStaff = role == "admin" || course.InstructorId == userId || access.assistants[userId]
+
+	if !access.isStaff {
+		isEnrolled, err := ds.lessonRepo.CheckUserEnrollment(userId, courseId)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to check enrollment", utils.ErrCodeInternal)
+		}
+		if !isEnrolled {
+			return nil, utils.NewError("You must enroll in this course to join discussions", utils.ErrCodeForbidden)
+		}
+	}
+
+	return access, nil
+}
+
+// getVisibleThread lấy thread mà user có quyền xem
+func (ds *discussionService) getVisibleThread(userId uint, role string, threadId uint) (*models.DiscussionThread, *discussionAccess, error) {
+	thread, err := ds.discussionRepo.FindThreadById(threadId)
+	if err != nil {
+		return nil, nil, utils.NewError("Discussion not found", utils.ErrCodeNotFound)
+	}
+
+	access, err := ds.getAccess(userId, role, thread.CourseId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if thread.IsInstructorOnly && !access.isStaff && thread.UserId != userId {
+		return nil, nil, utils.NewError("Discussion not found", utils.ErrCodeNotFound)
+	}
+
+	return thread, access, nil
+}
+
+func (ds *discussionService) getOwnedCourse(userId uint, role string, courseId uint) (*models.Course, error) {
+	course, err := ds.courseRepo.FindById(courseId)
+	if err != nil || (role != "admin" && course.InstructorId != userId) {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+	return course, nil
+}
+
+func discussionThreadLink(course *models.Course, threadId uint) string {
+	return fmt.Sprintf("/courses/%s/discussions/%d", course.Slug, threadId)
+}
+
+func toDiscussionAuthor(user *models.User, access *discussionAccess) dto.DiscussionAuthor {
+	authorRole := "student"
+	if user.Id == access.course.InstructorId {
+		authorRole = "instructor"
+	} else if access.assistants[user.Id] {
+		authorRole = "assistant"
+	}
+
+	return dto.DiscussionAuthor{
+		Id:        user.Id,
+		FullName:  user.FullName,
+		AvatarURL: user.AvatarURL,
+		Role:      authorRole,
+	}
+}
+
+func toDiscussionThreadItem(thread *models.DiscussionThread, access *discussionAccess, hasUpvoted bool) dto.DiscussionThreadItem {
+	lessonTitle := ""
+	if thread.Lesson != nil {
+		lessonTitle = thread.Lesson.Title
+	}
+
+	return dto.DiscussionThreadItem{
+		Id:               thread.Id,
+		CourseId:         thread.CourseId,
+		LessonId:         thread.LessonId,
+		LessonTitle:      lessonTitle,
+		Title:            thread.Title,
+		Body:             thread.Body,
+		Author:           toDiscussionAuthor(&thread.User, access),
+		IsPinned:         thread.IsPinned,
+		IsInstructorOnly: thread.IsInstructorOnly,
+		IsAnswered:       thread.IsAnswered,
+		AnswerReplyId:    thread.AnswerReplyId,
+		UpvoteCount:      thread.UpvoteCount,
+		ReplyCount:       thread.ReplyCount,
+		HasUpvoted:       hasUpvoted,
+		LastActivityAt:   thread.LastActivityAt,
+		CreatedAt:        thread.CreatedAt,
+	}
+}
+
+func toDiscussionReplyItem(reply *models.DiscussionReply, access *discussionAccess, hasUpvoted bool) dto.DiscussionReplyItem {
+	return dto.DiscussionReplyItem{
+		Id:          reply.Id,
+		ThreadId:    reply.ThreadId,
+		Body:        reply.Body,
+		Author:      toDiscussionAuthor(&reply.User, access),
+		IsAnswer:    reply.IsAnswer,
+		UpvoteCount: reply.UpvoteCount,
+		HasUpvoted:  hasUpvoted,
+		CreatedAt:   reply.CreatedAt,
+	}
+}
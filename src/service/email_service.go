@@ -2,21 +2,57 @@ package service
 
 import (
 	"fmt"
+	"html"
 	"lms/src/utils"
+	"strings"
 )
 
 type emailService struct {
 	// Có thể thêm SMTP config, template engine, etc.
+	tenantResolver *TenantResolver
 }
 
-func NewEmailService() EmailService {
-	return &emailService{}
+func NewEmailService(tenantResolver *TenantResolver) EmailService {
+	return &emailService{tenantResolver: tenantResolver}
 }
 
-func (es *emailService) SendPasswordResetEmail(email, resetToken, resetCode string) error {
+// emailSender là người gửi và frontend của tenant (white-label), mặc định lấy từ biến môi trường
+type emailSender struct {
+	from        string
+	siteName    string
+	frontendURL string
+}
+
+func (es *emailService) senderFor(tenantId uint) emailSender {
+	sender := emailSender{
+		from:        fmt.Sprintf("%s <%s>", utils.GetEnv("EMAIL_FROM_NAME", "LMS Team"), utils.GetEnv("EMAIL_FROM_ADDRESS", "no-reply@lms.local")),
+		siteName:    utils.GetEnv("EMAIL_FROM_NAME", "LMS Team"),
+		frontendURL: strings.TrimRight(utils.GetEnv("FRONTEND_URL", "http://localhost:3000"), "/"),
+	}
+
+	tenant, err := es.tenantResolver.Get(tenantId)
+	if err != nil || tenant == nil {
+		return sender
+	}
+	if tenant.EmailFromName != "" {
+		sender.siteName = tenant.EmailFromName
+	} else {
+		sender.siteName = tenant.Name
+	}
+	if tenant.EmailFromAddress != "" {
+		sender.from = fmt.Sprintf("%s <%s>", sender.siteName, tenant.EmailFromAddress)
+	}
+	if tenant.FrontendURL != "" {
+		sender.frontendURL = strings.TrimRight(tenant.FrontendURL, "/")
+	}
+	return sender
+}
+
+func (es *emailService) SendPasswordResetEmail(tenantId uint, email, resetToken, resetCode string) error {
+	sender := es.senderFor(tenantId)
+
 	// Tạo reset URL
-	baseURL := utils.GetEnv("FRONTEND_URL", "http://localhost:3000")
-	resetURL := fmt.Sprintf("%s/reset-password?token=%s", baseURL, resetToken)
+	resetURL := fmt.Sprintf("%s/reset-password?token=%s", sender.frontendURL, resetToken)
 	// Template email (trong production nên dùng HTML template)
 	subject := "Password Reset Request"
 	body := fmt.Sprintf(`
@@ -34,11 +70,12 @@ func (es *emailService) SendPasswordResetEmail(email, resetToken, resetCode stri
 	If you did not request this, please ignore this email.
 
 	Best regards,
-	LMS Team
-`, resetURL, resetCode)
+	%s
+`, resetURL, resetCode, sender.siteName)
 
 	// Trong development, chỉ log ra console
 	fmt.Printf("=== PASSWORD RESET EMAIL ===\n")
+	fmt.Printf("From: %s\n", sender.from)
 	fmt.Printf("To: %s\n", email)
 	fmt.Printf("Subject: %s\n", subject)
 	fmt.Printf("Body:\n%s\n", body)
@@ -50,10 +87,39 @@ func (es *emailService) SendPasswordResetEmail(email, resetToken, resetCode stri
 	return nil
 }
 
-func (es *emailService) SendWelcomeEmail(email, fullName string) error {
+func (es *emailService) SendWelcomeEmail(tenantId uint, email, fullName string) error {
+	sender := es.senderFor(tenantId)
+
 	fmt.Printf("=== WELCOME EMAIL ===\n")
+	fmt.Printf("From: %s\n", sender.from)
 	fmt.Printf("To: %s\n", email)
-	fmt.Printf("Welcome %s!\n", fullName)
+	fmt.Printf("Welcome to %s, %s!\n", sender.siteName, fullName)
+	fmt.Printf("Start learning: %s\n", sender.frontendURL)
 	fmt.Printf("====================\n")
 	return nil
 }
+
+func (es *emailService) SendAnnouncementEmail(tenantId uint, email, fullName, courseTitle, title, htmlBody, unsubscribeURL, openTrackingURL string) error {
+	sender := es.senderFor(tenantId)
+
+	subject := fmt.Sprintf("[%s] %s", courseTitle, title)
+	body := fmt.Sprintf(`<p>Hi %s,</p>
+<p>Your instructor posted a new announcement in <strong>%s</strong>:</p>
+<h2>%s</h2>
+%s
+<p style="font-size:12px;color:#888">Don't want these emails? <a href="%s">Unsubscribe from this course's announcements</a>.</p>
+<img src="%s" width="1" height="1" alt="" />
+`, html.EscapeString(fullName), html.EscapeString(courseTitle), html.EscapeString(title), htmlBody, unsubscribeURL, openTrackingURL)
+
+	// Trong development, chỉ log ra console
+	fmt.Printf("=== ANNOUNCEMENT EMAIL ===\n")
+	fmt.Printf("From: %s\n", sender.from)
+	fmt.Printf("To: %s\n", email)
+	fmt.Printf("Subject: %s\n", subject)
+	fmt.Printf("List-Unsubscribe: <%s>\n", unsubscribeURL)
+	fmt.Printf("Body:\n%s\n", body)
+	fmt.Printf("==========================\n")
+
+	// TODO: Implement thật sự với SMTP
+	return nil
+}
@@ -7,17 +7,20 @@ import (
 	"lms/src/repository"
 	"lms/src/utils"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type enrollmentService struct {
-	enrollmentRepo repository.EnrollmentRepository
-	orderRepo      repository.OrderRepository
-	courseRepo     repository.CourseRepository
-	couponRepo     repository.CouponRepository
-	progressRepo   repository.ProgressRepository // Thêm để đếm completed lessons
+	enrollmentRepo   repository.EnrollmentRepository
+	orderRepo        repository.OrderRepository
+	courseRepo       repository.CourseRepository
+	couponRepo       repository.CouponRepository
+	progressRepo     repository.ProgressRepository // Thêm để đếm completed lessons
+	subscriptionRepo repository.SubscriptionRepository
+	transactor       repository.Transactor
 }
 
 func NewEnrollmentService(
@@ -26,13 +29,17 @@ func NewEnrollmentService(
 	courseRepo repository.CourseRepository,
 	couponRepo repository.CouponRepository,
 	progressRepo repository.ProgressRepository,
+	subscriptionRepo repository.SubscriptionRepository,
+	transactor repository.Transactor,
 ) EnrollmentService {
 	return &enrollmentService{
-		enrollmentRepo: enrollmentRepo,
-		orderRepo:      orderRepo,
-		courseRepo:     courseRepo,
-		couponRepo:     couponRepo,
-		progressRepo:   progressRepo,
+		enrollmentRepo:   enrollmentRepo,
+		orderRepo:        orderRepo,
+		courseRepo:       courseRepo,
+		couponRepo:       couponRepo,
+		progressRepo:     progressRepo,
+		subscriptionRepo: subscriptionRepo,
+		transactor:       transactor,
 	}
 }
 
@@ -48,12 +55,46 @@ func (es *enrollmentService) EnrollCourse(userId, courseId uint, req *dto.Enroll
 		return nil, utils.NewError("Course is not available for enrollment", utils.ErrCodeBadRequest)
 	}
 
-	// 3. Kiểm tra user đã enroll chưa
-	if existingEnrollment, exists := es.enrollmentRepo.CheckEnrollment(userId, courseId); exists {
-		if existingEnrollment.Status == "active" {
+	// 3. Kiểm tra user đã enroll chưa (enrollment qua subscription đã hết hiệu lực thì được ghi danh lại)
+	existingEnrollment, exists := es.enrollmentRepo.CheckEnrollment(userId, courseId)
+	if exists && existingEnrollment.Status == "active" {
+		hasAccess, err := es.enrollmentRepo.CheckUserEnrollment(userId, courseId)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to check enrollment", utils.ErrCodeInternal)
+		}
+		if hasAccess {
 			return nil, utils.NewError("You are already enrolled in this course", utils.ErrCodeConflict)
 		}
 	}
+	var lapsedEnrollment *models.Enrollment
+	if exists && existingEnrollment.Status == "active" {
+		lapsedEnrollment = existingEnrollment
+	}
+
+	// 3.1 Kiểm tra prerequisites (block => chặn, warn => chỉ cảnh báo)
+	_, missingPrerequisites, err := checkCoursePrerequisites(es.courseRepo, es.enrollmentRepo, userId, course)
+	if err != nil {
+		return nil, err
+	}
+	if err := enforcePrerequisites(course, missingPrerequisites); err != nil {
+		return nil, err
+	}
+
+	// 3.2 Course nằm trong subscription của user: ghi danh luôn, không tạo order
+	subscription, err := es.subscriptionRepo.FindEntitlingSubscription(userId, courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check subscription", utils.ErrCodeInternal)
+	}
+	if subscription != nil {
+		var reusable *models.Enrollment
+		if exists && (existingEnrollment.Status == "active" || existingEnrollment.Status == "dropped") {
+			reusable = existingEnrollment
+		}
+		return es.enrollWithSubscription(course, subscription, reusable, missingPrerequisites)
+	}
+	if req.PaymentMethod == "" {
+		return nil, utils.NewError("payment_method is required to purchase this course", utils.ErrCodeBadRequest)
+	}
 
 	// 4. Tính toán giá
 	originalPrice := course.Price
@@ -107,7 +148,7 @@ func (es *enrollmentService) EnrollCourse(userId, courseId uint, req *dto.Enroll
 	// 7. Tạo order code
 	orderCode := fmt.Sprintf("ORD-%s-%d", uuid.New().String()[:8], time.Now().Unix())
 
-	// 8. Tạo order
+	// 8-10. Tạo order, enrollment và ghi event trong cùng transaction
 	order := &models.Order{
 		UserId:         userId,
 		CourseId:       courseId,
@@ -120,22 +161,6 @@ func (es *enrollmentService) EnrollCourse(userId, courseId uint, req *dto.Enroll
 		PaymentStatus:  "pending",
 	}
 
-	if err := es.orderRepo.Create(order); err != nil {
-		return nil, utils.WrapError(err, "Failed to create order", utils.ErrCodeInternal)
-	}
-
-	// 9. Nếu course free (finalPrice = 0), tự động approve
-	if finalPrice == 0 {
-		order.PaymentStatus = "paid"
-		now := time.Now()
-		order.PaidAt = &now
-
-		if err := es.orderRepo.UpdatePaymentStatus(order.Id, "paid"); err != nil {
-			return nil, utils.WrapError(err, "Failed to update payment status", utils.ErrCodeInternal)
-		}
-	}
-
-	// 10. Tạo enrollment
 	enrollment := &models.Enrollment{
 		UserId:             userId,
 		CourseId:           courseId,
@@ -144,20 +169,51 @@ func (es *enrollmentService) EnrollCourse(userId, courseId uint, req *dto.Enroll
 		Status:             "active",
 	}
 
-	if err := es.enrollmentRepo.Create(enrollment); err != nil {
-		return nil, utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
-	}
+	err = es.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		var events []dto.DomainEvent
 
-	// 11. Update coupon used count nếu có
-	if couponId != nil {
-		if err := es.couponRepo.IncrementUsedCount(*couponId); err != nil {
-			// Log error but don't fail
-			fmt.Printf("Failed to increment coupon used count: %v\n", err)
+		// 8. Tạo order
+		if err := repos.Orders.Create(order); err != nil {
+			return utils.WrapError(err, "Failed to create order", utils.ErrCodeInternal)
+		}
+
+		// 9. Nếu course free (finalPrice = 0), tự động approve
+		if finalPrice == 0 {
+			order.PaymentStatus = "paid"
+			now := time.Now()
+			order.PaidAt = &now
+
+			if err := repos.Orders.UpdatePaymentStatus(order.Id, "paid"); err != nil {
+				return utils.WrapError(err, "Failed to update payment status", utils.ErrCodeInternal)
+			}
+			events = append(events, newOrderPaidEvent(order))
+		}
+
+		// 10. Tạo enrollment; enrollment qua subscription đã hết hiệu lực thì chuyển thành enrollment mua lẻ
+		if lapsedEnrollment != nil {
+			if err := repos.Enrollments.UpdateEnrollmentProgress(lapsedEnrollment.Id, map[string]interface{}{
+				"subscription_id": nil,
+			}); err != nil {
+				return utils.WrapError(err, "Failed to update enrollment", utils.ErrCodeInternal)
+			}
+			enrollment = lapsedEnrollment
+			enrollment.SubscriptionId = nil
+		} else {
+			if err := repos.Enrollments.Create(enrollment); err != nil {
+				return utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
+			}
+			events = append(events, newEnrollmentCreatedEvent(enrollment, order.Id))
 		}
-	}
 
-	// 12. Update course enrolled count
-	// TODO: Implement UpdateEnrolledCount in CourseRepository
+		// 11. Coupon used count, enrolled count và thông báo cho instructor do event subscriber xử lý
+		if err := repos.Outbox.Append(events...); err != nil {
+			return utils.WrapError(err, "Failed to record enrollment events", utils.ErrCodeInternal)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	return &dto.EnrollCourseResponse{
 		EnrollmentId:   enrollment.Id,
@@ -172,6 +228,63 @@ func (es *enrollmentService) EnrollCourse(userId, courseId uint, req *dto.Enroll
 		PaymentStatus:  order.PaymentStatus,
 		EnrolledAt:     enrollment.EnrolledAt,
 		Message:        getEnrollmentMessage(finalPrice, order.PaymentStatus),
+
+		MissingPrerequisites: missingPrerequisites,
+	}, nil
+}
+
+// enrollWithSubscription ghi danh bằng subscription: dùng lại enrollment cũ (đã drop hoặc hết hiệu lực) nếu có
+func (es *enrollmentService) enrollWithSubscription(course *models.Course, subscription *models.Subscription, existing *models.Enrollment, missingPrerequisites []dto.CoursePrerequisiteItem) (*dto.EnrollCourseResponse, error) {
+	enrollment := existing
+	err := es.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		if existing != nil {
+			if err := repos.Enrollments.UpdateEnrollmentProgress(existing.Id, map[string]interface{}{
+				"status":          "active",
+				"subscription_id": subscription.Id,
+			}); err != nil {
+				return utils.WrapError(err, "Failed to reactivate enrollment", utils.ErrCodeInternal)
+			}
+			enrollment.Status = "active"
+			enrollment.SubscriptionId = &subscription.Id
+			return nil
+		}
+
+		enrollment = &models.Enrollment{
+			UserId:         subscription.UserId,
+			CourseId:       course.Id,
+			EnrolledAt:     time.Now(),
+			Status:         "active",
+			SubscriptionId: &subscription.Id,
+		}
+		if err := repos.Enrollments.Create(enrollment); err != nil {
+			return utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
+		}
+		if err := repos.Outbox.Append(newEnrollmentCreatedEvent(enrollment, 0)); err != nil {
+			return utils.WrapError(err, "Failed to record enrollment event", utils.ErrCodeInternal)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	price := course.Price
+	if course.DiscountPrice != nil && *course.DiscountPrice < price {
+		price = *course.DiscountPrice
+	}
+
+	return &dto.EnrollCourseResponse{
+		EnrollmentId:   enrollment.Id,
+		CourseId:       course.Id,
+		CourseTitle:    course.Title,
+		OriginalPrice:  price,
+		DiscountAmount: price,
+		FinalPrice:     0,
+		EnrolledAt:     enrollment.EnrolledAt,
+		Message:        fmt.Sprintf("You have been enrolled with your \"%s\" subscription", subscription.Plan.Name),
+		SubscriptionId: &subscription.Id,
+
+		MissingPrerequisites: missingPrerequisites,
 	}, nil
 }
 
@@ -192,6 +305,26 @@ func (es *enrollmentService) CheckEnrollment(userId, courseId uint) (*dto.CheckE
 	}, nil
 }
 
+func (es *enrollmentService) CheckPrerequisites(userId, courseId uint) (*dto.CheckPrerequisitesResponse, error) {
+	course, err := es.courseRepo.FindById(courseId)
+	if err != nil {
+		return nil, utils.NewError("Course not found", utils.ErrCodeNotFound)
+	}
+
+	prerequisites, missingPrerequisites, err := checkCoursePrerequisites(es.courseRepo, es.enrollmentRepo, userId, course)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.CheckPrerequisitesResponse{
+		CourseId:             course.Id,
+		PrerequisiteMode:     course.PrerequisiteMode,
+		Prerequisites:        prerequisites,
+		MissingPrerequisites: missingPrerequisites,
+		CanEnroll:            enforcePrerequisites(course, missingPrerequisites) == nil,
+	}, nil
+}
+
 func (es *enrollmentService) GetMyEnrollments(userId uint, req *dto.GetMyEnrollmentsQueryRequest) (*dto.GetMyEnrollmentsResponse, error) {
 	// Set defaults
 	page := 1
@@ -277,6 +410,16 @@ func (es *enrollmentService) GetMyEnrollments(userId uint, req *dto.GetMyEnrollm
 	}, nil
 }
 
+func newEnrollmentCreatedEvent(enrollment *models.Enrollment, orderId uint) dto.EnrollmentCreatedEvent {
+	return dto.EnrollmentCreatedEvent{
+		EnrollmentId: enrollment.Id,
+		UserId:       enrollment.UserId,
+		CourseId:     enrollment.CourseId,
+		OrderId:      orderId,
+		EnrolledAt:   enrollment.EnrolledAt,
+	}
+}
+
 func getEnrollmentMessage(finalPrice float64, paymentStatus string) string {
 	if finalPrice == 0 {
 		return "Congratulations! You have successfully enrolled in this free course"
@@ -288,3 +431,71 @@ func getEnrollmentMessage(finalPrice float64, paymentStatus string) string {
 
 	return "Enrollment pending. Please complete your payment to access the course"
 }
+
+// checkCoursePrerequisites trả về toàn bộ prerequisites của course và những course user chưa hoàn thành
+func checkCoursePrerequisites(
+	courseRepo repository.CourseRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	userId uint,
+	course *models.Course,
+) ([]dto.CoursePrerequisiteItem, []dto.CoursePrerequisiteItem, error) {
+	prerequisites, err := courseRepo.GetCoursePrerequisites(course.Id)
+	if err != nil {
+		return nil, nil, utils.WrapError(err, "Failed to get course prerequisites", utils.ErrCodeInternal)
+	}
+
+	if len(prerequisites) == 0 {
+		return []dto.CoursePrerequisiteItem{}, []dto.CoursePrerequisiteItem{}, nil
+	}
+
+	courseIds := make([]uint, len(prerequisites))
+	for i, prerequisite := range prerequisites {
+		courseIds[i] = prerequisite.Id
+	}
+
+	// Prerequisite được xem là hoàn thành khi enrollment ở trạng thái completed
+	enrollments, err := enrollmentRepo.GetEnrollmentsByCourses(userId, courseIds)
+	if err != nil {
+		return nil, nil, utils.WrapError(err, "Failed to get enrollments", utils.ErrCodeInternal)
+	}
+
+	completedMap := make(map[uint]bool)
+	for _, enrollment := range enrollments {
+		if enrollment.Status == "completed" {
+			completedMap[enrollment.CourseId] = true
+		}
+	}
+
+	items := make([]dto.CoursePrerequisiteItem, len(prerequisites))
+	missing := make([]dto.CoursePrerequisiteItem, 0)
+	for i, prerequisite := range prerequisites {
+		items[i] = dto.CoursePrerequisiteItem{
+			CourseId:    prerequisite.Id,
+			Title:       prerequisite.Title,
+			Slug:        prerequisite.Slug,
+			IsCompleted: completedMap[prerequisite.Id],
+		}
+		if !items[i].IsCompleted {
+			missing = append(missing, items[i])
+		}
+	}
+
+	return items, missing, nil
+}
+
+// enforcePrerequisites trả về lỗi nếu course ở chế độ block và còn prerequisite chưa hoàn thành
+func enforcePrerequisites(course *models.Course, missing []dto.CoursePrerequisiteItem) error {
+	if course.PrerequisiteMode != "block" || len(missing) == 0 {
+		return nil
+	}
+
+	titles := make([]string, len(missing))
+	for i, item := range missing {
+		titles[i] = item.Title
+	}
+
+	return utils.NewError(
+		fmt.Sprintf("You must complete the prerequisite courses first: %s", strings.Join(titles, ", ")),
+		utils.ErrCodeBadRequest,
+	)
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Số event nhận mỗi lần claim
+	outboxBatchSize = 50
+	// Event "processing" quá thời gian này được coi là bị treo (instance chết giữa chừng) và được nhận lại
+	outboxLockTimeout = 5 * time.Minute
+	// Thời gian chờ retry: 10s, 20s, 40s, ... tối đa 1 giờ
+	outboxRetryBaseDelay = 10 * time.Second
+	outboxRetryMaxDelay  = time.Hour
+)
+
+// EventHandler xử lý một domain event; trả về lỗi để dispatcher retry.
+// Handler có thể chạy lại nhiều lần (at-least-once) nên cần idempotent khi có thể.
+type EventHandler func(event *models.OutboxEvent) error
+
+type eventSubscriber struct {
+	name    string
+	handler EventHandler
+}
+
+// HandleEvent chuyển handler nhận event đã parse (vd. dto.OrderPaidEvent) thành EventHandler
+func HandleEvent[T dto.DomainEvent](fn func(event T) error) EventHandler {
+	return func(event *models.OutboxEvent) error {
+		var payload T
+		if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", event.EventType, err)
+		}
+		return fn(payload)
+	}
+}
+
+// EventDispatcher đọc domain event từ outbox và gửi tới các subscriber trong process.
+// Mỗi subscriber thành công được ghi nhận riêng nên khi retry chỉ chạy lại subscriber bị lỗi.
+type EventDispatcher struct {
+	outboxRepo  repository.OutboxRepository
+	dsn         string
+	interval    time.Duration
+	maxAttempts int
+	wake        chan struct{}
+
+	mu          sync.RWMutex
+	subscribers map[string][]eventSubscriber
+}
+
+func NewEventDispatcher(outboxRepo repository.OutboxRepository, dsn string) *EventDispatcher {
+	seconds, err := strconv.Atoi(utils.GetEnv("OUTBOX_POLL_INTERVAL_SECONDS", "5"))
+	if err != nil || seconds < 1 {
+		seconds = 5
+	}
+
+	maxAttempts, err := strconv.Atoi(utils.GetEnv("OUTBOX_MAX_ATTEMPTS", "8"))
+	if err != nil || maxAttempts < 1 {
+		maxAttempts = 8
+	}
+
+	return &EventDispatcher{
+		outboxRepo:  outboxRepo,
+		dsn:         dsn,
+		interval:    time.Duration(seconds) * time.Second,
+		maxAttempts: maxAttempts,
+		wake:        make(chan struct{}, 1),
+		subscribers: make(map[string][]eventSubscriber),
+	}
+}
+
+// Subscribe đăng ký handler cho một loại event. Tên subscriber phải cố định và duy nhất
+// trong cùng loại event vì được dùng để đánh dấu đã xử lý.
+func (ed *EventDispatcher) Subscribe(eventType, name string, handler EventHandler) {
+	ed.mu.Lock()
+	defer ed.mu.Unlock()
+
+	ed.subscribers[eventType] = append(ed.subscribers[eventType], eventSubscriber{name: name, handler: handler})
+}
+
+// Trigger đánh thức dispatcher ngay
+func (ed *EventDispatcher) Trigger() {
+	select {
+	case ed.wake <- struct{}{}:
+	default:
+	}
+}
+
+// StartWorkers LISTEN event mới (NOTIFY gửi khi transaction commit) và poll định kỳ để xử lý retry
+func (ed *EventDispatcher) StartWorkers() {
+	startPostgresListener(ed.dsn, repository.OutboxChannel, func(string) {
+		ed.Trigger()
+	})
+
+	go func() {
+		ticker := time.NewTicker(ed.interval)
+		defer ticker.Stop()
+
+		for {
+			ed.dispatchDue()
+
+			select {
+			case <-ticker.C:
+			case <-ed.wake:
+			}
+		}
+	}()
+}
+
+func (ed *EventDispatcher) dispatchDue() {
+	for {
+		now := time.Now()
+		events, err := ed.outboxRepo.ClaimDueEvents(now, now.Add(-outboxLockTimeout), outboxBatchSize)
+		if err != nil {
+			log.Printf("Failed to claim outbox events: %v", err)
+			return
+		}
+
+		for i := range events {
+			ed.process(&events[i])
+		}
+
+		if len(events) < outboxBatchSize {
+			return
+		}
+	}
+}
+
+func (ed *EventDispatcher) process(event *models.OutboxEvent) {
+	ed.mu.RLock()
+	subscribers := ed.subscribers[event.EventType]
+	ed.mu.RUnlock()
+
+	// 1. Bỏ qua subscriber đã xử lý thành công ở lần trước
+	delivered, err := ed.outboxRepo.GetDeliveredSubscribers([]uint{event.Id})
+	if err != nil {
+		ed.retry(event, err.Error())
+		return
+	}
+	done := make(map[string]bool)
+	for _, name := range delivered[event.Id] {
+		done[name] = true
+	}
+
+	// 2. Gửi tới từng subscriber, lỗi của subscriber này không ảnh hưởng subscriber khác
+	var failures []string
+	for _, subscriber := range subscribers {
+		if done[subscriber.name] {
+			continue
+		}
+
+		if err := runEventHandler(subscriber.handler, event); err != nil {
+			log.Printf("Event subscriber %s failed (event %d, attempt %d): %v", subscriber.name, event.Id, event.Attempts, err)
+			failures = append(failures, fmt.Sprintf("%s: %v", subscriber.name, err))
+			continue
+		}
+
+		if err := ed.outboxRepo.MarkDelivered(event.Id, subscriber.name); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", subscriber.name, err))
+		}
+	}
+
+	// 3. Cập nhật trạng thái event
+	if len(failures) > 0 {
+		ed.retry(event, strings.Join(failures, "; "))
+		return
+	}
+
+	if err := ed.outboxRepo.MarkProcessed(event.Id); err != nil {
+		log.Printf("Failed to mark outbox event %d as processed: %v", event.Id, err)
+	}
+}
+
+// retry hẹn lần thử tiếp theo (exponential backoff) hoặc đánh dấu failed khi hết số lần thử
+func (ed *EventDispatcher) retry(event *models.OutboxEvent, lastError string) {
+	var err error
+	if event.Attempts >= ed.maxAttempts {
+		log.Printf("Outbox event %d (%s) failed after %d attempts: %s", event.Id, event.EventType, event.Attempts, lastError)
+		err = ed.outboxRepo.MarkFailed(event.Id, lastError)
+	} else {
+		err = ed.outboxRepo.ScheduleRetry(event.Id, time.Now().Add(outboxRetryDelay(event.Attempts)), lastError)
+	}
+
+	if err != nil {
+		log.Printf("Failed to update outbox event %d: %v", event.Id, err)
+	}
+}
+
+func outboxRetryDelay(attempts int) time.Duration {
+	delay := outboxRetryBaseDelay
+	for i := 1; i < attempts && delay < outboxRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > outboxRetryMaxDelay {
+		delay = outboxRetryMaxDelay
+	}
+	return delay
+}
+
+// runEventHandler chặn panic của subscriber để không làm dừng dispatcher
+func runEventHandler(handler EventHandler, event *models.OutboxEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(event)
+}
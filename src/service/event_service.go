@@ -0,0 +1,118 @@
+package service
+
+import (
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"math"
+)
+
+type eventService struct {
+	outboxRepo repository.OutboxRepository
+}
+
+func NewEventService(outboxRepo repository.OutboxRepository) EventService {
+	return &eventService{
+		outboxRepo: outboxRepo,
+	}
+}
+
+func (es *eventService) GetEvents(req *dto.GetOutboxEventsQueryRequest) (*dto.GetOutboxEventsResponse, error) {
+	// 1. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	// 2. Prepare filters
+	filters := make(map[string]interface{})
+	if req.Status != "" {
+		filters["status"] = req.Status
+	}
+	if req.EventType != "" {
+		filters["event_type"] = req.EventType
+	}
+
+	// 3. Lấy events và các subscriber đã xử lý xong
+	events, total, err := es.outboxRepo.GetEvents(offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get events", utils.ErrCodeInternal)
+	}
+
+	eventIds := make([]uint, len(events))
+	for i, event := range events {
+		eventIds[i] = event.Id
+	}
+
+	delivered, err := es.outboxRepo.GetDeliveredSubscribers(eventIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get event deliveries", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.OutboxEventItem, len(events))
+	for i := range events {
+		items[i] = toOutboxEventItem(&events[i], delivered[events[i].Id])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetOutboxEventsResponse{
+		Events: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (es *eventService) RetryEvent(eventId uint) (*dto.RetryOutboxEventResponse, error) {
+	// 1. Kiểm tra event tồn tại
+	event, err := es.outboxRepo.FindById(eventId)
+	if err != nil {
+		return nil, utils.NewError("Event not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Chỉ cho phép gửi lại event đã failed
+	if event.Status != "failed" {
+		return nil, utils.NewError("Only failed events can be retried", utils.ErrCodeConflict)
+	}
+
+	// 3. Đưa event về hàng đợi
+	if err := es.outboxRepo.Requeue(eventId); err != nil {
+		return nil, utils.WrapError(err, "Failed to requeue event", utils.ErrCodeInternal)
+	}
+
+	return &dto.RetryOutboxEventResponse{
+		Message: "Event queued for retry",
+		Id:      eventId,
+	}, nil
+}
+
+func toOutboxEventItem(event *models.OutboxEvent, deliveredTo []string) dto.OutboxEventItem {
+	if deliveredTo == nil {
+		deliveredTo = []string{}
+	}
+
+	return dto.OutboxEventItem{
+		Id:            event.Id,
+		EventType:     event.EventType,
+		AggregateId:   event.AggregateId,
+		Payload:       event.Payload,
+		Status:        event.Status,
+		Attempts:      event.Attempts,
+		NextAttemptAt: event.NextAttemptAt,
+		LastError:     event.LastError,
+		ProcessedAt:   event.ProcessedAt,
+		CreatedAt:     event.CreatedAt,
+		DeliveredTo:   deliveredTo,
+	}
+}
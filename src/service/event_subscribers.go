@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"lms/src/dto"
+	"lms/src/repository"
+)
+
+// RegisterEventSubscribers đăng ký các side effect mặc định của domain event.
+// Tính năng mới chỉ cần Subscribe thêm, không phải sửa service phát sinh event.
+func RegisterEventSubscribers(
+	dispatcher *EventDispatcher,
+	courseRepo repository.CourseRepository,
+	couponRepo repository.CouponRepository,
+	notifier Notifier,
+	emailService EmailService,
+) {
+	// User mới: gửi email chào mừng
+	dispatcher.Subscribe(dto.EventUserRegistered, "welcome_email", HandleEvent(func(event dto.UserRegisteredEvent) error {
+		return emailService.SendWelcomeEmail(event.TenantId, event.Email, event.FullName)
+	}))
+
+	// Thanh toán thành công: cập nhật lượt dùng coupon và báo cho người mua
+	dispatcher.Subscribe(dto.EventOrderPaid, "coupon_usage", HandleEvent(func(event dto.OrderPaidEvent) error {
+		if event.CouponId == nil {
+			return nil
+		}
+		return couponRepo.IncrementUsedCount(*event.CouponId)
+	}))
+
+	dispatcher.Subscribe(dto.EventOrderPaid, "buyer_notification", HandleEvent(func(event dto.OrderPaidEvent) error {
+		// Order của bundle: báo một lần cho cả bundle (bundle.purchased)
+		if event.BundlePurchaseId != nil {
+			return nil
+		}
+
+		course, err := courseRepo.FindById(event.CourseId)
+		if err != nil {
+			return err
+		}
+
+		notifier.Notify(event.UserId, notificationOrderPaid,
+			"Payment successful",
+			fmt.Sprintf("Your payment for order %s was successful. You can now start learning \"%s\".", event.OrderCode, course.Title),
+			fmt.Sprintf("/courses/%s", course.Slug),
+		)
+		return nil
+	}))
+
+	dispatcher.Subscribe(dto.EventBundlePurchased, "buyer_notification", HandleEvent(func(event dto.BundlePurchasedEvent) error {
+		notifier.Notify(event.UserId, notificationOrderPaid,
+			"Payment successful",
+			fmt.Sprintf("Your payment for bundle \"%s\" was successful. You have been enrolled in %d courses.", event.BundleTitle, len(event.CourseIds)),
+			fmt.Sprintf("/bundles/%s", event.BundleSlug),
+		)
+		return nil
+	}))
+
+	// Enrollment mới: tăng enrolled_count của course và báo cho instructor
+	dispatcher.Subscribe(dto.EventEnrollmentCreated, "course_enrolled_count", HandleEvent(func(event dto.EnrollmentCreatedEvent) error {
+		return courseRepo.IncrementEnrolledCount(event.CourseId)
+	}))
+
+	dispatcher.Subscribe(dto.EventEnrollmentCreated, "instructor_notification", HandleEvent(func(event dto.EnrollmentCreatedEvent) error {
+		course, err := courseRepo.FindById(event.CourseId)
+		if err != nil {
+			return err
+		}
+
+		notifier.Notify(course.InstructorId, notificationEnrollmentCreated,
+			"New student enrolled",
+			fmt.Sprintf("A new student enrolled in \"%s\".", course.Title),
+			fmt.Sprintf("/instructor/courses/%d/students", course.Id),
+		)
+		return nil
+	}))
+
+	// Review mới: báo cho instructor (review đang chờ kiểm duyệt thì chưa báo)
+	dispatcher.Subscribe(dto.EventReviewCreated, "instructor_notification", HandleEvent(func(event dto.ReviewCreatedEvent) error {
+		if !event.IsPublished {
+			return nil
+		}
+
+		course, err := courseRepo.FindById(event.CourseId)
+		if err != nil {
+			return err
+		}
+
+		notifier.Notify(course.InstructorId, notificationReviewCreated,
+			"New review",
+			fmt.Sprintf("\"%s\" received a new %d-star review.", course.Title, event.Rating),
+			fmt.Sprintf("/courses/%s#reviews", course.Slug),
+		)
+		return nil
+	}))
+
+	// Subscription: báo cho subscriber khi gia hạn thất bại và khi hết quyền truy cập catalog
+	dispatcher.Subscribe(dto.EventSubscriptionPaymentFailed, "subscriber_notification", HandleEvent(func(event dto.SubscriptionPaymentFailedEvent) error {
+		notifier.Notify(event.UserId, notificationSubscriptionBilling,
+			"Subscription payment failed",
+			fmt.Sprintf("We could not renew your subscription. Update your payment before %s to keep access to your courses.", event.GraceEndsAt.Format("2006-01-02")),
+			"/account/subscription",
+		)
+		return nil
+	}))
+
+	dispatcher.Subscribe(dto.EventSubscriptionExpired, "subscriber_notification", HandleEvent(func(event dto.SubscriptionExpiredEvent) error {
+		notifier.Notify(event.UserId, notificationSubscriptionBilling,
+			"Subscription ended",
+			"Your subscription has ended. Courses you joined through it are locked until you subscribe again.",
+			"/account/subscription",
+		)
+		return nil
+	}))
+}
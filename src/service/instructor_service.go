@@ -1,23 +1,31 @@
 package service
 
 import (
+	"fmt"
 	"lms/src/dto"
 	"lms/src/models"
 	"lms/src/repository"
+	"lms/src/storage"
 	"lms/src/utils"
+	"log"
 	"math"
+	"mime/multipart"
 	"strconv"
 )
 
 type instructorService struct {
 	instructorRepo repository.InstructorRepository
 	categoryRepo   repository.CategoryRepository
+	approvalRepo   repository.CourseApprovalRepository
+	store          storage.Storage
 }
 
-func NewInstructorService(instructorRepo repository.InstructorRepository, categoryRepo repository.CategoryRepository) InstructorService {
+func NewInstructorService(instructorRepo repository.InstructorRepository, categoryRepo repository.CategoryRepository, approvalRepo repository.CourseApprovalRepository, store storage.Storage) InstructorService {
 	return &instructorService{
 		instructorRepo: instructorRepo,
 		categoryRepo:   categoryRepo,
+		approvalRepo:   approvalRepo,
+		store:          store,
 	}
 }
 
@@ -188,6 +196,11 @@ func (is *instructorService) UpdateCourse(instructorId, courseId uint, req *dto.
 		return nil, utils.NewError("course not found or you don't have permission to update this course", utils.ErrCodeNotFound)
 	}
 
+	// Nội dung course đã publish được sửa qua draft revision để học viên không thấy thay đổi dở dang
+	if course.Status == "published" && updatesCourseContent(req) {
+		return nil, utils.NewError(errEditPublishedCourse, utils.ErrCodeBadRequest)
+	}
+
 	// 2. Validate category nếu được cập nhật
 	var category *models.Category
 	if req.CategoryId != 0 && req.CategoryId != course.CategoryId {
@@ -265,11 +278,19 @@ func (is *instructorService) UpdateCourse(instructorId, courseId uint, req *dto.
 		updates["what_you_learn"] = req.WhatYouLearn
 	}
 
-	if req.DurationHours >= 0 {
+	if req.DurationHours > 0 {
 		updates["duration_hours"] = req.DurationHours
 	}
 
-	if req.Status != "" {
+	if req.Status != "" && req.Status != course.Status {
+		// Publish phải qua quy trình duyệt của admin
+		if req.Status == "published" || req.Status == "pending_review" {
+			return nil, utils.NewError("courses are published after admin approval. Please submit the course for review", utils.ErrCodeBadRequest)
+		}
+		if course.Status == "pending_review" {
+			return nil, utils.NewError("course is pending review. Please withdraw the submission before changing its status", utils.ErrCodeBadRequest)
+		}
+
 		// Không cho phép chuyển từ published về draft nếu đã có học viên
 		if course.Status == "published" && req.Status == "draft" {
 			enrollmentCount, _ := is.instructorRepo.CountEnrollmentsByCourse(courseId)
@@ -284,16 +305,38 @@ func (is *instructorService) UpdateCourse(instructorId, courseId uint, req *dto.
 		updates["is_featured"] = *req.IsFeatured
 	}
 
+	if req.ThumbnailURL != "" && req.ThumbnailURL != course.ThumbnailURL {
+		if err := storage.ValidateClientURL(is.store, req.ThumbnailURL, storage.OwnerPrefix("thumbnails", instructorId)); err != nil {
+			return nil, utils.NewError(err.Error(), utils.ErrCodeBadRequest)
+		}
+		updates["thumbnail_url"] = req.ThumbnailURL
+	}
+
 	// 5. Kiểm tra có gì cần update không
 	if len(updates) == 0 {
 		return nil, utils.NewError("no fields to update", utils.ErrCodeBadRequest)
 	}
 
-	// 6. Update course
-	if err := is.instructorRepo.UpdateCourse(courseId, updates); err != nil {
+	// 6. Update course (đổi status thì ghi lịch sử trong cùng transaction)
+	if newStatus, ok := updates["status"]; ok {
+		history := &models.CourseStatusHistory{
+			FromStatus: course.Status,
+			ToStatus:   newStatus.(string),
+			Action:     courseActionStatusChanged,
+			ChangedBy:  instructorId,
+		}
+		if err := is.approvalRepo.ChangeStatus(courseId, updates, history); err != nil {
+			return nil, utils.WrapError(err, "failed to update course", utils.ErrCodeInternal)
+		}
+	} else if err := is.instructorRepo.UpdateCourse(courseId, updates); err != nil {
 		return nil, utils.WrapError(err, "failed to update course", utils.ErrCodeInternal)
 	}
 
+	// Thumbnail cũ không còn được dùng
+	if _, ok := updates["thumbnail_url"]; ok {
+		storage.DeleteByURL(is.store, course.ThumbnailURL, "thumbnails/")
+	}
+
 	// 7. Lấy lại course đã update
 	updatedCourse, err := is.instructorRepo.FindCourseById(courseId)
 	if err != nil {
@@ -351,12 +394,30 @@ func (is *instructorService) DeleteCourse(instructorId, courseId uint) (*dto.Del
 	if course.Status == "published" {
 		return nil, utils.NewError("cannot delete published course. Please archive it first", utils.ErrCodeBadRequest)
 	}
+	if course.Status == "pending_review" {
+		return nil, utils.NewError("cannot delete course while it is pending review. Please withdraw the submission first", utils.ErrCodeBadRequest)
+	}
 
 	// 4. Xóa course (soft delete)
 	if err := is.instructorRepo.DeleteCourse(courseId); err != nil {
 		return nil, utils.WrapError(err, "failed to delete course", utils.ErrCodeInternal)
 	}
 
+	// 5. Dọn thumbnail, video, tài liệu đính kèm, phụ đề và package SCORM của các lessons trên storage
+	storage.DeleteByURL(is.store, course.ThumbnailURL, "thumbnails/")
+
+	lessons, err := is.instructorRepo.FindLessonsByCourse(courseId)
+	if err != nil {
+		log.Printf("Failed to load lessons for storage cleanup (course %d): %v", courseId, err)
+	}
+	for _, lesson := range lessons {
+		storage.DeleteByURL(is.store, lesson.PosterURL, "posters/")
+		storage.DeletePrefixQuietly(is.store, lessonVideoPrefix(lesson.Id))
+		storage.DeletePrefixQuietly(is.store, lessonAttachmentPrefix(lesson.Id))
+		storage.DeletePrefixQuietly(is.store, lessonSubtitlePrefix(lesson.Id))
+		storage.DeletePrefixQuietly(is.store, lessonScormPrefix(lesson.Id))
+	}
+
 	return &dto.DeleteCourseResponse{
 		Message:  "Course deleted successfully",
 		CourseId: courseId,
@@ -451,10 +512,13 @@ func (is *instructorService) GetCourseStudents(instructorId, courseId uint, req
 
 func (is *instructorService) CreateLesson(instructorId, courseId uint, req *dto.CreateLessonRequest) (*dto.CreateLessonResponse, error) {
 	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
-	_, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	course, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
 	if err != nil {
 		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
 	}
+	if course.Status == "published" {
+		return nil, utils.NewError(errEditPublishedCourse, utils.ErrCodeBadRequest)
+	}
 
 	// 2. Generate slug từ title
 	baseSlug := utils.GenerateSlug(req.Title)
@@ -480,26 +544,42 @@ func (is *instructorService) CreateLesson(instructorId, courseId uint, req *dto.
 		return nil, utils.NewError("Lesson order already exists in this course", utils.ErrCodeConflict)
 	}
 
-	// 5. Tạo lesson mới
-	lesson := &models.Lesson{
-		CourseId:      courseId,
-		Title:         req.Title,
-		Slug:          slug,
-		Description:   req.Description,
-		Content:       req.Content,
-		VideoURL:      req.VideoURL,
-		VideoDuration: req.VideoDuration,
-		LessonOrder:   req.LessonOrder,
-		IsPreview:     req.IsPreview,
-		IsPublished:   req.IsPublished,
+	// 5. Kiểm tra quiz bắt buộc có thuộc course không
+	if req.PrerequisiteQuizId != nil {
+		if _, err := is.instructorRepo.FindQuizByIdAndCourse(*req.PrerequisiteQuizId, courseId); err != nil {
+			return nil, utils.NewError("Prerequisite quiz not found in this course", utils.ErrCodeBadRequest)
+		}
 	}
 
-	// 6. Lưu vào database
+	// 6. Tạo lesson mới
+	var unlockAfterDays *int
+	if req.UnlockAfterDays != nil && *req.UnlockAfterDays > 0 {
+		unlockAfterDays = req.UnlockAfterDays
+	}
+
+	lesson := &models.Lesson{
+		CourseId:                  courseId,
+		Title:                     req.Title,
+		Slug:                      slug,
+		Description:               req.Description,
+		Content:                   req.Content,
+		VideoURL:                  req.VideoURL,
+		VideoDuration:             req.VideoDuration,
+		LessonOrder:               req.LessonOrder,
+		IsPreview:                 req.IsPreview,
+		IsPublished:               req.IsPublished,
+		UnlockAfterDays:           unlockAfterDays,
+		UnlockAt:                  req.UnlockAt,
+		RequirePreviousCompletion: req.RequirePreviousCompletion,
+		PrerequisiteQuizId:        req.PrerequisiteQuizId,
+	}
+
+	// 7. Lưu vào database
 	if err := is.instructorRepo.CreateLesson(lesson); err != nil {
 		return nil, utils.WrapError(err, "Failed to create lesson", utils.ErrCodeInternal)
 	}
 
-	// 7. Trả về response
+	// 8. Trả về response
 	return &dto.CreateLessonResponse{
 		Id:            lesson.Id,
 		CourseId:      lesson.CourseId,
@@ -513,15 +593,23 @@ func (is *instructorService) CreateLesson(instructorId, courseId uint, req *dto.
 		IsPreview:     lesson.IsPreview,
 		IsPublished:   lesson.IsPublished,
 		CreatedAt:     lesson.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+
+		UnlockAfterDays:           lesson.UnlockAfterDays,
+		UnlockAt:                  lesson.UnlockAt,
+		RequirePreviousCompletion: lesson.RequirePreviousCompletion,
+		PrerequisiteQuizId:        lesson.PrerequisiteQuizId,
 	}, nil
 }
 
 func (is *instructorService) UpdateLesson(instructorId, courseId, lessonId uint, req *dto.UpdateLessonRequest) (*dto.UpdateLessonResponse, error) {
 	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
-	_, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	course, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
 	if err != nil {
 		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
 	}
+	if course.Status == "published" {
+		return nil, utils.NewError(errEditPublishedCourse, utils.ErrCodeBadRequest)
+	}
 
 	// 2. Kiểm tra lesson có tồn tại và thuộc về course không
 	_, err = is.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId)
@@ -585,6 +673,40 @@ func (is *instructorService) UpdateLesson(instructorId, courseId, lessonId uint,
 		updates["is_published"] = *req.IsPublished
 	}
 
+	if req.UnlockAfterDays != nil {
+		if *req.UnlockAfterDays == 0 {
+			updates["unlock_after_days"] = nil
+		} else {
+			updates["unlock_after_days"] = *req.UnlockAfterDays
+		}
+	}
+
+	if req.ClearUnlockAt {
+		updates["unlock_at"] = nil
+	} else if req.UnlockAt != nil {
+		updates["unlock_at"] = *req.UnlockAt
+	}
+
+	if req.RequirePreviousCompletion != nil {
+		updates["require_previous_completion"] = *req.RequirePreviousCompletion
+	}
+
+	if req.PrerequisiteQuizId != nil {
+		if *req.PrerequisiteQuizId == 0 {
+			updates["prerequisite_quiz_id"] = nil
+		} else {
+			// Quiz bắt buộc phải thuộc course và không gắn với chính lesson này
+			quiz, err := is.instructorRepo.FindQuizByIdAndCourse(*req.PrerequisiteQuizId, courseId)
+			if err != nil {
+				return nil, utils.NewError("Prerequisite quiz not found in this course", utils.ErrCodeBadRequest)
+			}
+			if quiz.LessonId == lessonId {
+				return nil, utils.NewError("A lesson cannot require its own quiz", utils.ErrCodeBadRequest)
+			}
+			updates["prerequisite_quiz_id"] = *req.PrerequisiteQuizId
+		}
+	}
+
 	// 4. Nếu không có gì để update
 	if len(updates) == 0 {
 		return nil, utils.NewError("No fields to update", utils.ErrCodeBadRequest)
@@ -615,15 +737,23 @@ func (is *instructorService) UpdateLesson(instructorId, courseId, lessonId uint,
 		IsPreview:     updatedLesson.IsPreview,
 		IsPublished:   updatedLesson.IsPublished,
 		UpdatedAt:     updatedLesson.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+
+		UnlockAfterDays:           updatedLesson.UnlockAfterDays,
+		UnlockAt:                  updatedLesson.UnlockAt,
+		RequirePreviousCompletion: updatedLesson.RequirePreviousCompletion,
+		PrerequisiteQuizId:        updatedLesson.PrerequisiteQuizId,
 	}, nil
 }
 
 func (is *instructorService) DeleteLesson(instructorId, courseId, lessonId uint) (*dto.DeleteLessonResponse, error) {
 	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
-	_, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	course, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
 	if err != nil {
 		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
 	}
+	if course.Status == "published" {
+		return nil, utils.NewError(errEditPublishedCourse, utils.ErrCodeBadRequest)
+	}
 
 	// 2. Kiểm tra lesson có tồn tại và thuộc về course không
 	_, err = is.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId)
@@ -632,7 +762,7 @@ func (is *instructorService) DeleteLesson(instructorId, courseId, lessonId uint)
 	}
 
 	// 3. Delete lesson (soft delete)
-	if err := is.instructorRepo.DeleteCourse(lessonId); err != nil {
+	if err := is.instructorRepo.DeleteLesson(lessonId); err != nil {
 		return nil, utils.WrapError(err, "Failed to delete lesson", utils.ErrCodeInternal)
 	}
 
@@ -674,10 +804,13 @@ func (is *instructorService) ReorderLessons(instructorId, lessonId uint, req *dt
 	// firstLesson = lessons[0]
 
 	// 2. Kiểm tra course có tồn tại và thuộc về instructor không
-	_, err = is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	course, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
 	if err != nil {
 		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
 	}
+	if course.Status == "published" {
+		return nil, utils.NewError(errEditPublishedCourse, utils.ErrCodeBadRequest)
+	}
 
 	// 3. Kiểm tra tất cả lessons có thuộc về cùng một course không
 	lessonCourseMap := make(map[uint]uint) // lessonId -> courseId
@@ -736,3 +869,144 @@ func (is *instructorService) ReorderLessons(instructorId, lessonId uint, req *dt
 	}, nil
 
 }
+
+func (is *instructorService) SetCoursePrerequisites(instructorId, courseId uint, req *dto.SetCoursePrerequisitesRequest) (*dto.SetCoursePrerequisitesResponse, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	if _, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Validate danh sách prerequisites
+	seen := make(map[uint]bool)
+	prerequisiteIds := make([]uint, 0, len(req.CourseIds))
+	items := make([]dto.CoursePrerequisiteItem, 0, len(req.CourseIds))
+	for _, prerequisiteId := range req.CourseIds {
+		if prerequisiteId == courseId {
+			return nil, utils.NewError("A course cannot be a prerequisite of itself", utils.ErrCodeBadRequest)
+		}
+		if seen[prerequisiteId] {
+			continue
+		}
+		seen[prerequisiteId] = true
+
+		prerequisite, err := is.instructorRepo.FindCourseById(prerequisiteId)
+		if err != nil || prerequisite.Status != "published" {
+			return nil, utils.NewError(fmt.Sprintf("Prerequisite course %d not found or not published", prerequisiteId), utils.ErrCodeBadRequest)
+		}
+
+		prerequisiteIds = append(prerequisiteIds, prerequisiteId)
+		items = append(items, dto.CoursePrerequisiteItem{
+			CourseId: prerequisite.Id,
+			Title:    prerequisite.Title,
+			Slug:     prerequisite.Slug,
+		})
+	}
+
+	// 3. Không cho phép vòng lặp prerequisites (A cần B, B lại cần A)
+	for _, prerequisiteId := range prerequisiteIds {
+		cyclic, err := is.dependsOnCourse(prerequisiteId, courseId, make(map[uint]bool))
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to check prerequisites", utils.ErrCodeInternal)
+		}
+		if cyclic {
+			return nil, utils.NewError(fmt.Sprintf("Course %d already requires this course as a prerequisite", prerequisiteId), utils.ErrCodeBadRequest)
+		}
+	}
+
+	// 4. Lưu prerequisites và mode
+	if err := is.instructorRepo.ReplaceCoursePrerequisites(courseId, prerequisiteIds); err != nil {
+		return nil, utils.WrapError(err, "Failed to update prerequisites", utils.ErrCodeInternal)
+	}
+
+	if err := is.instructorRepo.UpdateCourse(courseId, map[string]interface{}{"prerequisite_mode": req.Mode}); err != nil {
+		return nil, utils.WrapError(err, "Failed to update prerequisite mode", utils.ErrCodeInternal)
+	}
+
+	return &dto.SetCoursePrerequisitesResponse{
+		CourseId:         courseId,
+		PrerequisiteMode: req.Mode,
+		Prerequisites:    items,
+	}, nil
+}
+
+// dependsOnCourse kiểm tra course có (trực tiếp hoặc gián tiếp) yêu cầu targetId làm prerequisite không
+func (is *instructorService) dependsOnCourse(courseId, targetId uint, visited map[uint]bool) (bool, error) {
+	if visited[courseId] {
+		return false, nil
+	}
+	visited[courseId] = true
+
+	ids, err := is.instructorRepo.GetPrerequisiteIds(courseId)
+	if err != nil {
+		return false, err
+	}
+
+	for _, id := range ids {
+		if id == targetId {
+			return true, nil
+		}
+		found, err := is.dependsOnCourse(id, targetId, visited)
+		if err != nil {
+			return false, err
+		}
+		if found {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (is *instructorService) UploadCourseThumbnail(instructorId, courseId uint, file *multipart.FileHeader) (*dto.UploadThumbnailResponse, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	course, err := is.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	if err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+	if course.Status == "published" {
+		return nil, utils.NewError(errEditPublishedCourse, utils.ErrCodeBadRequest)
+	}
+
+	// 2. Validate ảnh
+	fileName, contentType, err := utils.ValidateFile(file, utils.ImageFileRule)
+	if err != nil {
+		return nil, utils.WrapError(err, "Invalid thumbnail file", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Lưu vào storage
+	src, err := file.Open()
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to upload thumbnail", utils.ErrCodeBadRequest)
+	}
+	defer src.Close()
+
+	key := storage.OwnerPrefix("thumbnails", instructorId) + fileName
+	if err := is.store.Put(key, src, file.Size, contentType); err != nil {
+		return nil, utils.WrapError(err, "Failed to upload thumbnail", utils.ErrCodeInternal)
+	}
+
+	// 4. Cập nhật course và xóa thumbnail cũ
+	thumbnailURL := is.store.URL(key)
+	if err := is.instructorRepo.UpdateCourse(courseId, map[string]interface{}{"thumbnail_url": thumbnailURL}); err != nil {
+		is.store.Delete(key)
+		return nil, utils.WrapError(err, "Failed to update course thumbnail", utils.ErrCodeInternal)
+	}
+
+	storage.DeleteByURL(is.store, course.ThumbnailURL, "thumbnails/")
+
+	return &dto.UploadThumbnailResponse{
+		Message:      "Thumbnail uploaded successfully",
+		CourseId:     courseId,
+		ThumbnailURL: thumbnailURL,
+	}, nil
+}
+
+// Lỗi trả về khi sửa trực tiếp nội dung của course đã publish
+const errEditPublishedCourse = "course is published. Create a draft revision to edit its content and publish the changes when ready"
+
+// updatesCourseContent kiểm tra request có sửa nội dung được version hóa không (giá, trạng thái, featured vẫn sửa trực tiếp)
+func updatesCourseContent(req *dto.UpdateCourseRequest) bool {
+	return req.Title != "" || req.Description != "" || req.ShortDesc != "" || req.CategoryId != 0 ||
+		req.Level != "" || req.Language != "" || req.Requirements != "" || req.WhatYouLearn != "" ||
+		req.DurationHours > 0 || req.ThumbnailURL != ""
+}
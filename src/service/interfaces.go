@@ -1,6 +1,8 @@
 package service
 
 import (
+	"encoding/json"
+	"io"
 	"lms/src/dto"
 	"lms/src/models"
 	"mime/multipart"
@@ -17,8 +19,9 @@ type AuthService interface {
 
 // Interface cho EmailService
 type EmailService interface {
-	SendPasswordResetEmail(email, resetToken, resetCode string) error
-	SendWelcomeEmail(email, fullName string) error
+	SendPasswordResetEmail(tenantId uint, email, resetToken, resetCode string) error
+	SendWelcomeEmail(tenantId uint, email, fullName string) error
+	SendAnnouncementEmail(tenantId uint, email, fullName, courseTitle, title, htmlBody, unsubscribeURL, openTrackingURL string) error
 }
 
 type UserService interface {
@@ -35,7 +38,7 @@ type AdminService interface {
 	DeleteUser(userId uint) (*dto.DeleteUserResponse, error)
 	ChangeUserStatus(userId uint, req *dto.ChangeUserStatusRequest) (*dto.ChangeUserStatusResponse, error)
 	GetCourses(req *dto.GetAdminCoursesQueryRequest) (*dto.GetAdminCoursesResponse, error)
-	ChangeCourseStatus(courseId uint, req *dto.ChangeCourseStatusRequest) (*dto.ChangeCourseStatusResponse, error)
+	ChangeCourseStatus(adminId, courseId uint, req *dto.ChangeCourseStatusRequest) (*dto.ChangeCourseStatusResponse, error)
 }
 
 type CategoryService interface {
@@ -58,6 +61,14 @@ type ReviewService interface {
 	CreateReview(userId, courseId uint, req *dto.CreateReviewRequest) (*dto.CreateReviewResponse, error)
 	UpdateReview(userId, reviewId uint, req *dto.UpdateReviewRequest) (*dto.UpdateReviewResponse, error)
 	DeleteReview(userId, reviewId uint) (*dto.DeleteReviewResponse, error)
+	ReportReview(userId, reviewId uint, req *dto.ReportReviewRequest) (*dto.ReportReviewResponse, error)
+	UpsertReply(instructorId, reviewId uint, req *dto.ReviewReplyRequest) (*dto.ReviewReplyItem, error)
+	DeleteReply(instructorId, reviewId uint) (*dto.DeleteReviewReplyResponse, error)
+	GetModerationQueue(req *dto.GetReviewModerationQueryRequest) (*dto.GetReviewModerationQueueResponse, error)
+	ModerateReview(adminId, reviewId uint, req *dto.ModerateReviewRequest) (*dto.ModerateReviewResponse, error)
+	GetReviewReports(reviewId uint) (*dto.GetReviewReportsResponse, error)
+	VoteReview(userId, reviewId uint, req *dto.VoteReviewRequest) (*dto.VoteReviewResponse, error)
+	RemoveVote(userId, reviewId uint) (*dto.VoteReviewResponse, error)
 }
 
 type LessonService interface {
@@ -65,10 +76,18 @@ type LessonService interface {
 	GetLessonDetail(userId, courseId uint, slug string) (*dto.LessonDetail, error)
 }
 
+type QuizService interface {
+	CreateQuiz(instructorId, courseId, lessonId uint, req *dto.CreateQuizRequest) (*dto.QuizDetail, error)
+	DeleteQuiz(instructorId, courseId, lessonId uint) (*dto.DeleteQuizResponse, error)
+	GetLessonQuiz(userId, lessonId uint) (*dto.QuizDetail, error)
+	SubmitQuiz(userId, quizId uint, req *dto.SubmitQuizRequest) (*dto.SubmitQuizResponse, error)
+}
+
 type EnrollmentService interface {
 	EnrollCourse(userId, courseId uint, req *dto.EnrollCourseRequest) (*dto.EnrollCourseResponse, error)
 	CheckEnrollment(userId, courseId uint) (*dto.CheckEnrollmentResponse, error)
 	GetMyEnrollments(userId uint, req *dto.GetMyEnrollmentsQueryRequest) (*dto.GetMyEnrollmentsResponse, error)
+	CheckPrerequisites(userId, courseId uint) (*dto.CheckPrerequisitesResponse, error)
 }
 
 type InstructorService interface {
@@ -81,12 +100,105 @@ type InstructorService interface {
 	UpdateLesson(instructorId, courseId, lessonId uint, req *dto.UpdateLessonRequest) (*dto.UpdateLessonResponse, error)
 	DeleteLesson(instructorId, courseId, lessonId uint) (*dto.DeleteLessonResponse, error)
 	ReorderLessons(instructorId, lessonId uint, req *dto.ReorderLessonsRequest) (*dto.ReorderLessonsResponse, error)
+	SetCoursePrerequisites(instructorId, courseId uint, req *dto.SetCoursePrerequisitesRequest) (*dto.SetCoursePrerequisitesResponse, error)
+	UploadCourseThumbnail(instructorId, courseId uint, file *multipart.FileHeader) (*dto.UploadThumbnailResponse, error)
+}
+
+type LearningPathService interface {
+	GetLearningPaths(req *dto.GetLearningPathsQueryRequest) (*dto.GetLearningPathsResponse, error)
+	GetLearningPathBySlug(slug string) (*dto.LearningPathDetail, error)
+	CreateLearningPath(userId uint, role string, req *dto.CreateLearningPathRequest) (*dto.LearningPathDetail, error)
+	UpdateLearningPath(userId uint, role string, pathId uint, req *dto.UpdateLearningPathRequest) (*dto.LearningPathDetail, error)
+	DeleteLearningPath(userId uint, role string, pathId uint) (*dto.DeleteLearningPathResponse, error)
+	SetLearningPathCourses(userId uint, role string, pathId uint, req *dto.SetLearningPathCoursesRequest) (*dto.LearningPathDetail, error)
+	GetLearningPathProgress(userId uint, slug string) (*dto.LearningPathProgressResponse, error)
+	IssueCertificate(userId uint, slug string) (*dto.LearningPathCertificateItem, error)
+}
+
+type VideoService interface {
+	InitUpload(instructorId, courseId, lessonId uint, req *dto.InitVideoUploadRequest) (*dto.VideoUploadResponse, error)
+	UploadChunk(instructorId uint, uploadId string, index int, body io.Reader) (*dto.VideoUploadResponse, error)
+	GetUploadStatus(instructorId uint, uploadId string) (*dto.VideoUploadResponse, error)
+	CompleteUpload(instructorId uint, uploadId string) (*dto.VideoUploadResponse, error)
+	GetPlayback(userId, lessonId uint) (*dto.VideoPlaybackResponse, error)
+	ResolveStream(token, filePath string) (*VideoStream, error)
+}
+
+type DiscussionService interface {
+	GetThreads(userId uint, role string, courseId uint, req *dto.GetThreadsQueryRequest) (*dto.GetThreadsResponse, error)
+	CreateThread(userId uint, role string, courseId uint, req *dto.CreateThreadRequest) (*dto.DiscussionThreadItem, error)
+	GetThreadDetail(userId uint, role string, threadId uint) (*dto.DiscussionThreadDetail, error)
+	DeleteThread(userId uint, role string, threadId uint) (*dto.DeleteDiscussionResponse, error)
+	CreateReply(userId uint, role string, threadId uint, req *dto.CreateReplyRequest) (*dto.DiscussionReplyItem, error)
+	DeleteReply(userId uint, role string, replyId uint) (*dto.DeleteDiscussionResponse, error)
+	UpvoteThread(userId uint, role string, threadId uint) (*dto.UpvoteResponse, error)
+	UpvoteReply(userId uint, role string, replyId uint) (*dto.UpvoteResponse, error)
+	MarkAnswer(userId uint, role string, replyId uint, isAnswer bool) (*dto.DiscussionThreadDetail, error)
+	PinThread(userId uint, role string, threadId uint, req *dto.PinThreadRequest) (*dto.DiscussionThreadItem, error)
+	SetThreadVisibility(userId uint, role string, threadId uint, req *dto.ThreadVisibilityRequest) (*dto.DiscussionThreadItem, error)
+	GetUnansweredThreads(userId uint, req *dto.GetUnansweredQueryRequest) (*dto.GetThreadsResponse, error)
+	GetCourseAssistants(userId uint, role string, courseId uint) (*dto.GetCourseAssistantsResponse, error)
+	AddCourseAssistant(userId uint, role string, courseId uint, req *dto.AddCourseAssistantRequest) (*dto.GetCourseAssistantsResponse, error)
+	RemoveCourseAssistant(userId uint, role string, courseId, assistantId uint) (*dto.GetCourseAssistantsResponse, error)
+}
+
+type NoteService interface {
+	GetLessonNotes(userId, lessonId uint) (*dto.GetLessonNotesResponse, error)
+	CreateNote(userId, lessonId uint, req *dto.CreateNoteRequest) (*dto.LessonNoteItem, error)
+	UpdateNote(userId, noteId uint, req *dto.UpdateNoteRequest) (*dto.LessonNoteItem, error)
+	DeleteNote(userId, noteId uint) (*dto.DeleteNoteResponse, error)
+	SearchNotes(userId uint, req *dto.SearchNotesQueryRequest) (*dto.SearchNotesResponse, error)
+	ExportCourseNotes(userId, courseId uint) (*dto.NotesExport, error)
+}
+
+type AnnouncementService interface {
+	GetCourseAnnouncements(instructorId, courseId uint, req *dto.GetCourseAnnouncementsQueryRequest) (*dto.GetCourseAnnouncementsResponse, error)
+	CreateAnnouncement(instructorId, courseId uint, req *dto.CreateAnnouncementRequest) (*dto.InstructorAnnouncementItem, error)
+	UpdateAnnouncement(instructorId, courseId, announcementId uint, req *dto.UpdateAnnouncementRequest) (*dto.InstructorAnnouncementItem, error)
+	DeleteAnnouncement(instructorId, courseId, announcementId uint) (*dto.DeleteAnnouncementResponse, error)
+	GetFeed(userId uint, req *dto.GetAnnouncementFeedQueryRequest) (*dto.GetAnnouncementFeedResponse, error)
+	GetAnnouncement(userId, announcementId uint) (*dto.AnnouncementFeedItem, error)
+	UpdateSubscription(userId, courseId uint, req *dto.AnnouncementSubscriptionRequest) (*dto.AnnouncementSubscriptionResponse, error)
+	TrackEmailOpen(token string) error
+	UnsubscribeByToken(token string) (*dto.UnsubscribeAnnouncementResponse, error)
+}
+
+// Notifier tạo thông báo in-app cho các service khác (order, enrollment, review, ...)
+type Notifier interface {
+	Notify(userId uint, notificationType, title, message, link string)
+}
+
+type NotificationService interface {
+	GetNotifications(userId uint, req *dto.GetNotificationsQueryRequest) (*dto.GetNotificationsResponse, error)
+	MarkRead(userId uint, req *dto.MarkNotificationsReadRequest) (*dto.MarkNotificationsReadResponse, error)
+	DeleteNotification(userId, notificationId uint) (*dto.DeleteNotificationResponse, error)
+	GetPreferences(userId uint) (*dto.GetNotificationPreferencesResponse, error)
+	UpdatePreferences(userId uint, req *dto.UpdateNotificationPreferencesRequest) (*dto.GetNotificationPreferencesResponse, error)
+	Subscribe(userId uint, lastEventId uint) ([]dto.NotificationItem, <-chan dto.NotificationItem, func(), error)
+}
+
+type AttachmentService interface {
+	UploadAttachment(instructorId, courseId, lessonId uint, title string, file *multipart.FileHeader) (*dto.LessonAttachmentItem, error)
+	DeleteAttachment(instructorId, courseId, lessonId, attachmentId uint) (*dto.DeleteAttachmentResponse, error)
+	DownloadAttachment(userId, lessonId, attachmentId uint) (*AttachmentDownload, error)
+}
+
+type SubtitleService interface {
+	UploadSubtitle(instructorId, courseId, lessonId uint, language, label string, file *multipart.FileHeader) (*dto.LessonSubtitleItem, error)
+	DeleteSubtitle(instructorId, courseId, lessonId, subtitleId uint) (*dto.DeleteSubtitleResponse, error)
+}
+
+type StorageService interface {
+	PresignUpload(userId uint, role string, req *dto.PresignUploadRequest) (*dto.PresignUploadResponse, error)
+	PutLocalObject(key, expires, signature string, body io.Reader, size int64, contentType string) error
+	GetLocalObject(key, expires, signature string) (io.ReadCloser, string, error)
 }
 
 type ProgressService interface {
 	GetCourseProgress(userId, courseId uint) (*dto.GetCourseProgressResponse, error)
 	CompleteLesson(userId, lessonId uint, req *dto.CompleteLessonRequest) (*dto.CompleteLessonResponse, error)
 	UpdateLessonPosition(userId, lessonId uint, req *dto.UpdateLessonPositionRequest) (*dto.UpdateLessonPositionResponse, error)
+	completeLesson(userId uint, lesson *models.Lesson, req *dto.CompleteLessonRequest) (*dto.CompleteLessonResponse, error)
 	updateEnrollmentProgress(userId, courseId uint) error
 }
 
@@ -121,3 +233,196 @@ type AdminAnalyticsService interface {
 	GetAdminUsersAnalytics(req *dto.AdminUsersAnalyticsRequest) (*dto.AdminUsersAnalyticsResponse, error)
 	GetAdminCoursesAnalytics(req *dto.AdminCoursesAnalyticsRequest) (*dto.AdminCoursesAnalyticsResponse, error)
 }
+
+type EventService interface {
+	GetEvents(req *dto.GetOutboxEventsQueryRequest) (*dto.GetOutboxEventsResponse, error)
+	RetryEvent(eventId uint) (*dto.RetryOutboxEventResponse, error)
+}
+
+type WebhookService interface {
+	GetEventTypes() *dto.GetWebhookEventTypesResponse
+	GetWebhooks(req *dto.GetWebhooksQueryRequest) (*dto.GetWebhooksResponse, error)
+	CreateWebhook(adminId uint, req *dto.CreateWebhookRequest) (*dto.WebhookSecretResponse, error)
+	GetWebhook(webhookId uint) (*dto.WebhookItem, error)
+	UpdateWebhook(webhookId uint, req *dto.UpdateWebhookRequest) (*dto.WebhookItem, error)
+	DeleteWebhook(webhookId uint) (*dto.DeleteWebhookResponse, error)
+	RotateSecret(webhookId uint) (*dto.WebhookSecretResponse, error)
+	SendTestEvent(webhookId uint) (*dto.WebhookDeliveryItem, error)
+	GetDeliveries(webhookId uint, req *dto.GetWebhookDeliveriesQueryRequest) (*dto.GetWebhookDeliveriesResponse, error)
+	ReplayDelivery(deliveryId uint) (*dto.WebhookDeliveryItem, error)
+}
+
+type CourseApprovalService interface {
+	GetReviewStatus(instructorId, courseId uint) (*dto.CourseReviewStatusResponse, error)
+	SubmitForReview(instructorId, courseId uint, req *dto.SubmitCourseReviewRequest) (*dto.CourseReviewActionResponse, error)
+	WithdrawSubmission(instructorId, courseId uint) (*dto.CourseReviewActionResponse, error)
+	GetInstructorStatusHistory(instructorId, courseId uint) (*dto.GetCourseStatusHistoryResponse, error)
+	GetReviewQueue(req *dto.GetCourseReviewQueueQueryRequest) (*dto.GetCourseReviewQueueResponse, error)
+	ApproveCourse(adminId, courseId uint, req *dto.ApproveCourseRequest) (*dto.CourseReviewActionResponse, error)
+	RejectCourse(adminId, courseId uint, req *dto.RejectCourseRequest) (*dto.CourseReviewActionResponse, error)
+	GetStatusHistory(courseId uint) (*dto.GetCourseStatusHistoryResponse, error)
+}
+
+type CourseRevisionService interface {
+	// Instructor
+	GetRevisions(instructorId, courseId uint, req *dto.GetCourseRevisionsQueryRequest) (*dto.GetCourseRevisionsResponse, error)
+	CreateDraft(instructorId, courseId uint) (*dto.CourseDraftResponse, error)
+	GetDraft(instructorId, courseId uint) (*dto.CourseDraftResponse, error)
+	UpdateDraft(instructorId, courseId uint, req *dto.UpdateCourseDraftRequest) (*dto.CourseDraftResponse, error)
+	UploadDraftThumbnail(instructorId, courseId uint, file *multipart.FileHeader) (*dto.CourseDraftResponse, error)
+	DiscardDraft(instructorId, courseId uint) (*dto.DiscardCourseDraftResponse, error)
+	PreviewDraft(instructorId, courseId uint) (*dto.CoursePreviewResponse, error)
+	AddDraftLesson(instructorId, courseId uint, req *dto.CreateDraftLessonRequest) (*dto.CourseDraftResponse, error)
+	UpdateDraftLesson(instructorId, courseId uint, key string, req *dto.UpdateDraftLessonRequest) (*dto.CourseDraftResponse, error)
+	RemoveDraftLesson(instructorId, courseId uint, key string) (*dto.CourseDraftResponse, error)
+	ReorderDraftLessons(instructorId, courseId uint, req *dto.ReorderDraftLessonsRequest) (*dto.CourseDraftResponse, error)
+	PublishDraft(instructorId, courseId uint, req *dto.PublishCourseDraftRequest) (*dto.CourseRevisionDetailResponse, error)
+
+	// Admin
+	GetCourseRevisions(courseId uint, req *dto.GetCourseRevisionsQueryRequest) (*dto.GetCourseRevisionsResponse, error)
+	GetRevision(courseId, revisionId uint) (*dto.CourseRevisionDetailResponse, error)
+	DiffRevisions(courseId uint, req *dto.DiffCourseRevisionsQueryRequest) (*dto.CourseRevisionDiff, error)
+	RollbackRevision(adminId, courseId, revisionId uint, req *dto.RollbackCourseRevisionRequest) (*dto.CourseRevisionDetailResponse, error)
+}
+
+type CourseTemplateService interface {
+	DuplicateCourse(instructorId, courseId uint, req *dto.DuplicateCourseRequest) (*dto.DuplicateCourseResponse, error)
+	GetTemplates(req *dto.GetCourseTemplatesQueryRequest) (*dto.GetCourseTemplatesResponse, error)
+	SetTemplate(courseId uint, req *dto.SetCourseTemplateRequest) (*dto.SetCourseTemplateResponse, error)
+}
+
+type CoursePackageService interface {
+	ExportCourse(instructorId, courseId uint) (*dto.CoursePackageExport, error)
+	AdminExportCourse(courseId uint) (*dto.CoursePackageExport, error)
+	ImportCourse(instructorId uint, file *multipart.FileHeader, req *dto.ImportCourseRequest) (*dto.ImportCourseResponse, error)
+}
+
+type ScormService interface {
+	// Instructor
+	UploadPackage(instructorId, courseId, lessonId uint, file *multipart.FileHeader) (*dto.ScormPackageResponse, error)
+	GetPackage(instructorId, courseId, lessonId uint) (*dto.ScormPackageResponse, error)
+	DeletePackage(instructorId, courseId, lessonId uint) (*dto.DeleteScormPackageResponse, error)
+	GetAttempts(instructorId, courseId, lessonId uint, req *dto.GetScormAttemptsQueryRequest) (*dto.GetScormAttemptsResponse, error)
+
+	// Runtime
+	Launch(userId, lessonId uint) (*dto.ScormLaunchResponse, error)
+	Commit(userId, lessonId uint, req *dto.ScormCommitRequest) (*dto.ScormRuntimeResponse, error)
+	ResolveContent(token, filePath string) (*ScormContent, error)
+}
+
+type XapiService interface {
+	About() *dto.XapiAboutResponse
+	PutStatement(client, statementId string, body []byte) error
+	PostStatements(client string, body []byte) ([]string, error)
+	GetStatement(statementId string, voided bool) (json.RawMessage, error)
+	GetStatements(req *dto.GetXapiStatementsQueryRequest) (*dto.XapiStatementResult, error)
+}
+
+type LtiService interface {
+	// Admin
+	CreatePlatform(adminId uint, req *dto.CreateLtiPlatformRequest) (*dto.LtiPlatformItem, error)
+	GetPlatforms(req *dto.GetLtiPlatformsQueryRequest) (*dto.GetLtiPlatformsResponse, error)
+	GetPlatform(platformId uint) (*dto.LtiPlatformItem, error)
+	UpdatePlatform(platformId uint, req *dto.UpdateLtiPlatformRequest) (*dto.LtiPlatformItem, error)
+	DeletePlatform(platformId uint) (*dto.DeleteLtiPlatformResponse, error)
+
+	// Tool
+	GetToolConfig() *dto.LtiToolConfigResponse
+	GetJWKS() (map[string]interface{}, error)
+	Login(req *dto.LtiLoginRequest) (*dto.LtiLaunchResult, error)
+	Launch(req *dto.LtiLaunchRequest) (*dto.LtiLaunchResult, error)
+	GetDeepLinkSession(token string) (*dto.LtiDeepLinkSessionResponse, error)
+	CreateDeepLink(token string, req *dto.LtiDeepLinkRequest) (*dto.LtiDeepLinkResponse, error)
+}
+
+type OrganizationService interface {
+	// Organization
+	CreateOrganization(userId uint, req *dto.CreateOrganizationRequest) (*dto.OrganizationItem, error)
+	GetMyOrganizations(userId uint) (*dto.GetMyOrganizationsResponse, error)
+	GetOrganization(userId, organizationId uint) (*dto.OrganizationItem, error)
+	UpdateOrganization(userId, organizationId uint, req *dto.UpdateOrganizationRequest) (*dto.OrganizationItem, error)
+
+	// Members
+	GetMembers(userId, organizationId uint, req *dto.GetOrganizationMembersQueryRequest) (*dto.GetOrganizationMembersResponse, error)
+	AddMember(userId, organizationId uint, req *dto.AddOrganizationMemberRequest) (*dto.OrganizationMemberItem, error)
+	UpdateMember(userId, organizationId, memberUserId uint, req *dto.UpdateOrganizationMemberRequest) (*dto.OrganizationMemberItem, error)
+	RemoveMember(userId, organizationId, memberUserId uint) (*dto.RemoveOrganizationMemberResponse, error)
+
+	// Licenses & seats
+	GetLicenses(userId, organizationId uint) (*dto.GetOrganizationLicensesResponse, error)
+	PurchaseSeats(userId, organizationId uint, req *dto.PurchaseSeatsRequest) (*dto.PurchaseSeatsResponse, error)
+	CancelLicense(userId, organizationId, licenseId uint) (*dto.CancelLicenseResponse, error)
+	GetSeatAssignments(userId, organizationId, licenseId uint) (*dto.GetSeatAssignmentsResponse, error)
+	AssignSeat(userId, organizationId, licenseId uint, req *dto.AssignSeatRequest) (*dto.SeatAssignmentItem, error)
+	ReclaimSeat(userId, organizationId, licenseId, memberUserId uint) (*dto.ReclaimSeatResponse, error)
+
+	// Invoices
+	GetInvoices(userId, organizationId uint, req *dto.GetOrganizationInvoicesQueryRequest) (*dto.GetOrganizationInvoicesResponse, error)
+	GetInvoice(userId, organizationId, invoiceId uint) (*dto.OrganizationInvoiceItem, error)
+	PayInvoice(userId, organizationId, invoiceId uint, req *dto.PayInvoiceRequest) (*dto.PayInvoiceResponse, error)
+
+	// Team progress
+	GetTeamProgress(userId, organizationId uint, req *dto.GetTeamProgressQueryRequest) (*dto.GetTeamProgressResponse, error)
+
+	// Admin
+	AdminGetOrganizations(req *dto.GetOrganizationsQueryRequest) (*dto.GetOrganizationsResponse, error)
+	AdminUpdateOrganizationStatus(organizationId uint, req *dto.UpdateOrganizationStatusRequest) (*dto.OrganizationItem, error)
+	AdminGetInvoices(organizationId uint, req *dto.GetOrganizationInvoicesQueryRequest) (*dto.GetOrganizationInvoicesResponse, error)
+	AdminMarkInvoicePaid(organizationId, invoiceId uint, req *dto.MarkInvoicePaidRequest) (*dto.PayInvoiceResponse, error)
+}
+
+type TenantService interface {
+	// Branding & settings của tenant hiện tại
+	GetBranding(tenantId uint) (*dto.TenantBrandingResponse, error)
+	GetSettings(tenantId uint) (*dto.TenantSettingsResponse, error)
+	UpdateSettings(tenantId uint, req *dto.UpdateTenantSettingsRequest) (*dto.TenantSettingsResponse, error)
+
+	// Super-admin
+	CreateTenant(req *dto.CreateTenantRequest) (*dto.TenantItem, error)
+	GetTenants(req *dto.GetTenantsQueryRequest) (*dto.GetTenantsResponse, error)
+	GetTenant(tenantId uint) (*dto.TenantItem, error)
+	UpdateTenant(tenantId uint, req *dto.UpdateTenantRequest) (*dto.TenantItem, error)
+	AddDomain(tenantId uint, req *dto.AddTenantDomainRequest) (*dto.TenantDomainItem, error)
+	RemoveDomain(tenantId, domainId uint) error
+	GetCrossTenantAnalytics(req *dto.CrossTenantAnalyticsRequest) (*dto.CrossTenantAnalyticsResponse, error)
+}
+
+type SubscriptionService interface {
+	// Plans
+	GetPlans(req *dto.GetSubscriptionPlansQueryRequest) (*dto.GetSubscriptionPlansResponse, error)
+	GetPlan(planId uint) (*dto.SubscriptionPlanItem, error)
+
+	// Subscription của user
+	GetMySubscription(userId uint) (*dto.GetMySubscriptionResponse, error)
+	Subscribe(userId uint, req *dto.SubscribeRequest) (*dto.SubscriptionResponse, error)
+	CancelSubscription(userId uint) (*dto.SubscriptionResponse, error)
+	ResumeSubscription(userId uint) (*dto.SubscriptionResponse, error)
+	RetryPayment(userId uint, req *dto.RetrySubscriptionPaymentRequest) (*dto.SubscriptionResponse, error)
+	GetMyPayments(userId uint, req *dto.GetSubscriptionPaymentsQueryRequest) (*dto.GetSubscriptionPaymentsResponse, error)
+
+	// Admin
+	AdminGetPlans(req *dto.GetSubscriptionPlansQueryRequest) (*dto.GetSubscriptionPlansResponse, error)
+	CreatePlan(req *dto.CreateSubscriptionPlanRequest) (*dto.SubscriptionPlanItem, error)
+	UpdatePlan(planId uint, req *dto.UpdateSubscriptionPlanRequest) (*dto.SubscriptionPlanItem, error)
+	AdminGetSubscriptions(req *dto.GetSubscriptionsQueryRequest) (*dto.GetSubscriptionsResponse, error)
+	GetCourseTags(courseId uint) (*dto.CourseTagsResponse, error)
+	SetCourseTags(courseId uint, req *dto.SetCourseTagsRequest) (*dto.CourseTagsResponse, error)
+}
+
+type BundleService interface {
+	// Catalog
+	GetBundles(req *dto.GetBundlesQueryRequest) (*dto.GetBundlesResponse, error)
+	GetBundleBySlug(slug string) (*dto.BundleDetail, error)
+
+	// Mua bundle
+	GetQuote(userId uint, slug string) (*dto.BundleQuoteResponse, error)
+	PurchaseBundle(userId uint, slug string, req *dto.PurchaseBundleRequest) (*dto.PurchaseBundleResponse, error)
+	GetMyPurchases(userId uint, req *dto.GetBundlePurchasesQueryRequest) (*dto.GetBundlePurchasesResponse, error)
+
+	// Admin
+	AdminGetBundles(req *dto.AdminGetBundlesQueryRequest) (*dto.GetBundlesResponse, error)
+	CreateBundle(userId uint, req *dto.CreateBundleRequest) (*dto.BundleDetail, error)
+	UpdateBundle(bundleId uint, req *dto.UpdateBundleRequest) (*dto.BundleDetail, error)
+	SetBundleCourses(bundleId uint, req *dto.SetBundleCoursesRequest) (*dto.BundleDetail, error)
+	DeleteBundle(bundleId uint) (*dto.DeleteBundleResponse, error)
+}
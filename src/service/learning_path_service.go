@@ -0,0 +1,472 @@
+package service
+
+import (
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type learningPathService struct {
+	learningPathRepo repository.LearningPathRepository
+	courseRepo       repository.CourseRepository
+	enrollmentRepo   repository.EnrollmentRepository
+	notifier         Notifier
+}
+
+func NewLearningPathService(
+	learningPathRepo repository.LearningPathRepository,
+	courseRepo repository.CourseRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	notifier Notifier,
+) LearningPathService {
+	return &learningPathService{
+		learningPathRepo: learningPathRepo,
+		courseRepo:       courseRepo,
+		enrollmentRepo:   enrollmentRepo,
+		notifier:         notifier,
+	}
+}
+
+func (lps *learningPathService) GetLearningPaths(req *dto.GetLearningPathsQueryRequest) (*dto.GetLearningPathsResponse, error) {
+	// Set default values
+	page := 1
+	limit := 12
+	orderBy := "created_at"
+	sortBy := "desc"
+
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	if req.OrderBy != "" {
+		orderBy = req.OrderBy
+	}
+	if req.SortBy != "" {
+		sortBy = req.SortBy
+	}
+
+	offset := (page - 1) * limit
+
+	// Chỉ hiển thị path đã published
+	filters := map[string]interface{}{
+		"status": "published",
+	}
+	if req.Search != "" {
+		filters["search"] = req.Search
+	}
+
+	paths, total, err := lps.learningPathRepo.GetLearningPaths(offset, limit, filters, orderBy, sortBy)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get learning paths", utils.ErrCodeInternal)
+	}
+
+	pathItems := make([]dto.LearningPathItem, len(paths))
+	for i := range paths {
+		pathItems[i] = toLearningPathItem(&paths[i])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	pagination := dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
+
+	return &dto.GetLearningPathsResponse{
+		LearningPaths: pathItems,
+		Pagination:    pagination,
+	}, nil
+}
+
+func (lps *learningPathService) GetLearningPathBySlug(slug string) (*dto.LearningPathDetail, error) {
+	path, err := lps.learningPathRepo.FindBySlug(slug)
+	if err != nil || path.Status != "published" {
+		return nil, utils.NewError("Learning path not found", utils.ErrCodeNotFound)
+	}
+
+	return toLearningPathDetail(path), nil
+}
+
+func (lps *learningPathService) CreateLearningPath(userId uint, role string, req *dto.CreateLearningPathRequest) (*dto.LearningPathDetail, error) {
+	// 1. Validate danh sách courses
+	courseIds, err := lps.validatePathCourses(userId, role, req.CourseIds)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Generate slug từ title
+	baseSlug := utils.GenerateSlug(req.Title)
+	uniqueSlug := utils.GenerateUniqueSlug(baseSlug, lps.learningPathRepo.ExistsBySlug)
+
+	status := "draft"
+	if req.Status != "" {
+		status = req.Status
+	}
+	if status == "published" && len(courseIds) == 0 {
+		return nil, utils.NewError("Cannot publish a learning path without courses", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Chỉ admin được đánh dấu featured
+	path := &models.LearningPath{
+		Title:        req.Title,
+		Slug:         uniqueSlug,
+		Description:  req.Description,
+		ThumbnailURL: req.ThumbnailURL,
+		CreatedBy:    userId,
+		Status:       status,
+		IsFeatured:   role == "admin" && req.IsFeatured,
+	}
+
+	if err := lps.learningPathRepo.Create(path); err != nil {
+		return nil, utils.WrapError(err, "Failed to create learning path", utils.ErrCodeInternal)
+	}
+
+	// 4. Lưu courses theo thứ tự
+	if len(courseIds) > 0 {
+		if err := lps.learningPathRepo.ReplaceCourses(path.Id, courseIds); err != nil {
+			return nil, utils.WrapError(err, "Failed to save learning path courses", utils.ErrCodeInternal)
+		}
+	}
+
+	return lps.getPathDetail(path.Id)
+}
+
+func (lps *learningPathService) UpdateLearningPath(userId uint, role string, pathId uint, req *dto.UpdateLearningPathRequest) (*dto.LearningPathDetail, error) {
+	// 1. Kiểm tra quyền quản lý path
+	path, err := lps.findManageablePath(userId, role, pathId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Chuẩn bị updates
+	updates := make(map[string]interface{})
+
+	if req.Title != nil && *req.Title != path.Title {
+		updates["title"] = *req.Title
+		baseSlug := utils.GenerateSlug(*req.Title)
+		updates["slug"] = utils.GenerateUniqueSlug(baseSlug, func(slug string) bool {
+			if slug == path.Slug {
+				return false
+			}
+			return lps.learningPathRepo.ExistsBySlug(slug)
+		})
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.ThumbnailURL != nil {
+		updates["thumbnail_url"] = *req.ThumbnailURL
+	}
+	if req.Status != nil {
+		if *req.Status == "published" && len(path.Courses) == 0 {
+			return nil, utils.NewError("Cannot publish a learning path without courses", utils.ErrCodeBadRequest)
+		}
+		updates["status"] = *req.Status
+	}
+	if req.IsFeatured != nil {
+		if role != "admin" {
+			return nil, utils.NewError("Only admins can feature learning paths", utils.ErrCodeForbidden)
+		}
+		updates["is_featured"] = *req.IsFeatured
+	}
+
+	if len(updates) == 0 {
+		return nil, utils.NewError("No fields to update", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Update
+	if err := lps.learningPathRepo.Update(pathId, updates); err != nil {
+		return nil, utils.WrapError(err, "Failed to update learning path", utils.ErrCodeInternal)
+	}
+
+	return lps.getPathDetail(pathId)
+}
+
+func (lps *learningPathService) DeleteLearningPath(userId uint, role string, pathId uint) (*dto.DeleteLearningPathResponse, error) {
+	if _, err := lps.findManageablePath(userId, role, pathId); err != nil {
+		return nil, err
+	}
+
+	if err := lps.learningPathRepo.Delete(pathId); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete learning path", utils.ErrCodeInternal)
+	}
+
+	return &dto.DeleteLearningPathResponse{
+		Message: "Learning path deleted successfully",
+	}, nil
+}
+
+func (lps *learningPathService) SetLearningPathCourses(userId uint, role string, pathId uint, req *dto.SetLearningPathCoursesRequest) (*dto.LearningPathDetail, error) {
+	// 1. Kiểm tra quyền quản lý path
+	if _, err := lps.findManageablePath(userId, role, pathId); err != nil {
+		return nil, err
+	}
+
+	// 2. Validate courses
+	courseIds, err := lps.validatePathCourses(userId, role, req.CourseIds)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Thay thế danh sách courses theo thứ tự mới
+	if err := lps.learningPathRepo.ReplaceCourses(pathId, courseIds); err != nil {
+		return nil, utils.WrapError(err, "Failed to update learning path courses", utils.ErrCodeInternal)
+	}
+
+	return lps.getPathDetail(pathId)
+}
+
+func (lps *learningPathService) GetLearningPathProgress(userId uint, slug string) (*dto.LearningPathProgressResponse, error) {
+	// 1. Lấy path
+	path, err := lps.learningPathRepo.FindBySlug(slug)
+	if err != nil || path.Status != "published" {
+		return nil, utils.NewError("Learning path not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Tính progress dựa trên enrollments
+	progress, err := lps.calculateProgress(userId, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Lấy certificate nếu đã được cấp
+	certificate, err := lps.learningPathRepo.FindCertificate(userId, path.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get certificate", utils.ErrCodeInternal)
+	}
+	if certificate != nil {
+		progress.Certificate = toLearningPathCertificateItem(certificate, path)
+	}
+
+	return progress, nil
+}
+
+func (lps *learningPathService) IssueCertificate(userId uint, slug string) (*dto.LearningPathCertificateItem, error) {
+	// 1. Lấy path
+	path, err := lps.learningPathRepo.FindBySlug(slug)
+	if err != nil || path.Status != "published" {
+		return nil, utils.NewError("Learning path not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Nếu đã có certificate thì trả về luôn
+	existing, err := lps.learningPathRepo.FindCertificate(userId, path.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get certificate", utils.ErrCodeInternal)
+	}
+	if existing != nil {
+		return toLearningPathCertificateItem(existing, path), nil
+	}
+
+	// 3. Kiểm tra đã hoàn thành toàn bộ courses trong path chưa
+	progress, err := lps.calculateProgress(userId, path)
+	if err != nil {
+		return nil, err
+	}
+	if !progress.IsCompleted {
+		return nil, utils.NewError("You must complete all courses in this learning path first", utils.ErrCodeBadRequest)
+	}
+
+	// 4. Tạo certificate
+	certificate := &models.LearningPathCertificate{
+		UserId:          userId,
+		LearningPathId:  path.Id,
+		CertificateCode: fmt.Sprintf("LP-%s", strings.ToUpper(uuid.New().String()[:12])),
+		IssuedAt:        time.Now(),
+	}
+
+	if err := lps.learningPathRepo.CreateCertificate(certificate); err != nil {
+		return nil, utils.WrapError(err, "Failed to issue certificate", utils.ErrCodeInternal)
+	}
+
+	lps.notifier.Notify(userId, notificationCertificateIssued,
+		"Certificate issued",
+		fmt.Sprintf("Congratulations! You earned the certificate for \"%s\" (%s).", path.Title, certificate.CertificateCode),
+		fmt.Sprintf("/learning-paths/%s", path.Slug),
+	)
+
+	return toLearningPathCertificateItem(certificate, path), nil
+}
+
+// calculateProgress tính progress của path = trung bình progress các course (chưa enroll tính 0%)
+func (lps *learningPathService) calculateProgress(userId uint, path *models.LearningPath) (*dto.LearningPathProgressResponse, error) {
+	courseIds := make([]uint, len(path.Courses))
+	for i, item := range path.Courses {
+		courseIds[i] = item.CourseId
+	}
+
+	enrollments, err := lps.enrollmentRepo.GetEnrollmentsByCourses(userId, courseIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get enrollments", utils.ErrCodeInternal)
+	}
+
+	enrollmentMap := make(map[uint]models.Enrollment)
+	for _, enrollment := range enrollments {
+		enrollmentMap[enrollment.CourseId] = enrollment
+	}
+
+	courses := make([]dto.LearningPathCourseProgress, len(path.Courses))
+	completedCourses := 0
+	totalProgress := 0.0
+	for i, item := range path.Courses {
+		courseProgress := dto.LearningPathCourseProgress{
+			CourseId: item.CourseId,
+			Title:    item.Course.Title,
+			Slug:     item.Course.Slug,
+			Position: item.Position,
+		}
+
+		if enrollment, ok := enrollmentMap[item.CourseId]; ok {
+			courseProgress.IsEnrolled = true
+			courseProgress.IsCompleted = enrollment.Status == "completed"
+			courseProgress.ProgressPercentage = enrollment.ProgressPercentage
+			if courseProgress.IsCompleted {
+				courseProgress.ProgressPercentage = 100
+				completedCourses++
+			}
+		}
+
+		totalProgress += courseProgress.ProgressPercentage
+		courses[i] = courseProgress
+	}
+
+	progressPercentage := 0.0
+	if len(courses) > 0 {
+		progressPercentage = math.Round(totalProgress/float64(len(courses))*100) / 100
+	}
+
+	return &dto.LearningPathProgressResponse{
+		LearningPathId:     path.Id,
+		Title:              path.Title,
+		TotalCourses:       len(courses),
+		CompletedCourses:   completedCourses,
+		ProgressPercentage: progressPercentage,
+		IsCompleted:        len(courses) > 0 && completedCourses == len(courses),
+		Courses:            courses,
+	}, nil
+}
+
+// findManageablePath lấy path và kiểm tra user có quyền quản lý (admin hoặc người tạo)
+func (lps *learningPathService) findManageablePath(userId uint, role string, pathId uint) (*models.LearningPath, error) {
+	path, err := lps.learningPathRepo.FindById(pathId)
+	if err != nil {
+		return nil, utils.NewError("Learning path not found", utils.ErrCodeNotFound)
+	}
+
+	if role != "admin" && path.CreatedBy != userId {
+		return nil, utils.NewError("You don't have permission to manage this learning path", utils.ErrCodeForbidden)
+	}
+
+	return path, nil
+}
+
+// validatePathCourses kiểm tra courses đã published, instructor chỉ được dùng course của mình
+func (lps *learningPathService) validatePathCourses(userId uint, role string, courseIds []uint) ([]uint, error) {
+	uniqueIds := make([]uint, 0, len(courseIds))
+	seen := make(map[uint]bool)
+	for _, courseId := range courseIds {
+		if seen[courseId] {
+			return nil, utils.NewError("Duplicate courses in learning path", utils.ErrCodeBadRequest)
+		}
+		seen[courseId] = true
+		uniqueIds = append(uniqueIds, courseId)
+	}
+
+	courses, err := lps.courseRepo.FindByIds(uniqueIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get courses", utils.ErrCodeInternal)
+	}
+
+	courseMap := make(map[uint]models.Course)
+	for _, course := range courses {
+		courseMap[course.Id] = course
+	}
+
+	for _, courseId := range uniqueIds {
+		course, ok := courseMap[courseId]
+		if !ok || course.Status != "published" {
+			return nil, utils.NewError(fmt.Sprintf("Course %d not found or not published", courseId), utils.ErrCodeBadRequest)
+		}
+		if role != "admin" && course.InstructorId != userId {
+			return nil, utils.NewError(fmt.Sprintf("You can only add your own courses (course %d)", courseId), utils.ErrCodeForbidden)
+		}
+	}
+
+	return uniqueIds, nil
+}
+
+func (lps *learningPathService) getPathDetail(pathId uint) (*dto.LearningPathDetail, error) {
+	path, err := lps.learningPathRepo.FindById(pathId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get learning path", utils.ErrCodeInternal)
+	}
+
+	return toLearningPathDetail(path), nil
+}
+
+func toLearningPathItem(path *models.LearningPath) dto.LearningPathItem {
+	return dto.LearningPathItem{
+		Id:           path.Id,
+		Title:        path.Title,
+		Slug:         path.Slug,
+		Description:  path.Description,
+		ThumbnailURL: path.ThumbnailURL,
+		CreatorName:  path.Creator.FullName,
+		IsFeatured:   path.IsFeatured,
+		TotalCourses: len(path.Courses),
+		CreatedAt:    path.CreatedAt,
+	}
+}
+
+func toLearningPathDetail(path *models.LearningPath) *dto.LearningPathDetail {
+	courses := make([]dto.LearningPathCourseItem, len(path.Courses))
+	for i, item := range path.Courses {
+		courses[i] = dto.LearningPathCourseItem{
+			CourseId:      item.CourseId,
+			Position:      item.Position,
+			Title:         item.Course.Title,
+			Slug:          item.Course.Slug,
+			ThumbnailURL:  item.Course.ThumbnailURL,
+			Level:         item.Course.Level,
+			Price:         item.Course.Price,
+			DiscountPrice: item.Course.DiscountPrice,
+			DurationHours: item.Course.DurationHours,
+		}
+	}
+
+	return &dto.LearningPathDetail{
+		Id:           path.Id,
+		Title:        path.Title,
+		Slug:         path.Slug,
+		Description:  path.Description,
+		ThumbnailURL: path.ThumbnailURL,
+		CreatedBy:    path.CreatedBy,
+		CreatorName:  path.Creator.FullName,
+		Status:       path.Status,
+		IsFeatured:   path.IsFeatured,
+		Courses:      courses,
+		CreatedAt:    path.CreatedAt,
+		UpdatedAt:    path.UpdatedAt,
+	}
+}
+
+func toLearningPathCertificateItem(certificate *models.LearningPathCertificate, path *models.LearningPath) *dto.LearningPathCertificateItem {
+	return &dto.LearningPathCertificateItem{
+		Id:               certificate.Id,
+		LearningPathId:   certificate.LearningPathId,
+		LearningPathName: path.Title,
+		CertificateCode:  certificate.CertificateCode,
+		IssuedAt:         certificate.IssuedAt,
+	}
+}
@@ -1,20 +1,52 @@
 package service
 
 import (
+	"fmt"
 	"lms/src/dto"
+	"lms/src/models"
 	"lms/src/repository"
+	"lms/src/storage"
 	"lms/src/utils"
+	"time"
 )
 
+const (
+	lockReasonSchedule       = "schedule"
+	lockReasonPreviousLesson = "previous_lesson"
+	lockReasonQuiz           = "quiz"
+)
+
+// lessonLock mô tả trạng thái khóa của một lesson đối với user
+type lessonLock struct {
+	isLocked bool
+	unlockAt *time.Time
+	reason   string
+}
+
 type lessonService struct {
-	lessonRepo repository.LessonRepository
-	courseRepo repository.CourseRepository
+	lessonRepo     repository.LessonRepository
+	courseRepo     repository.CourseRepository
+	attachmentRepo repository.AttachmentRepository
+	subtitleRepo   repository.SubtitleRepository
+	noteRepo       repository.NoteRepository
+	store          storage.Storage
 }
 
-func NewLessonService(lessonRepo repository.LessonRepository, courseRepo repository.CourseRepository) LessonService {
+func NewLessonService(
+	lessonRepo repository.LessonRepository,
+	courseRepo repository.CourseRepository,
+	attachmentRepo repository.AttachmentRepository,
+	subtitleRepo repository.SubtitleRepository,
+	noteRepo repository.NoteRepository,
+	store storage.Storage,
+) LessonService {
 	return &lessonService{
-		lessonRepo: lessonRepo,
-		courseRepo: courseRepo,
+		lessonRepo:     lessonRepo,
+		courseRepo:     courseRepo,
+		attachmentRepo: attachmentRepo,
+		subtitleRepo:   subtitleRepo,
+		noteRepo:       noteRepo,
+		store:          store,
 	}
 }
 
@@ -53,21 +85,38 @@ func (ls *lessonService) GetCourseLessons(userId, courseId uint) (*dto.GetCourse
 		return nil, utils.WrapError(err, "Failed to get lesson progress", utils.ErrCodeInternal)
 	}
 
-	// 5. Convert sang DTO
+	// 5. Tính trạng thái khóa (drip-feed & prerequisites)
+	locks, err := resolveLessonLocks(ls.lessonRepo, userId, courseId, lessons, progressMap)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to resolve lesson schedule", utils.ErrCodeInternal)
+	}
+
+	// 6. Convert sang DTO - lesson bị khóa vẫn hiển thị nhưng không trả về video
 	lessonItems := make([]dto.LessonItem, len(lessons))
 	for i, lesson := range lessons {
+		lock := locks[lesson.Id]
+
+		videoURL := lesson.VideoURL
+		if lock.isLocked {
+			videoURL = ""
+		}
+
 		lessonItems[i] = dto.LessonItem{
 			Id:            lesson.Id,
 			CourseId:      lesson.CourseId,
 			Title:         lesson.Title,
 			Slug:          lesson.Slug,
 			Description:   lesson.Description,
-			VideoURL:      lesson.VideoURL,
+			VideoURL:      videoURL,
 			VideoDuration: lesson.VideoDuration,
 			LessonOrder:   lesson.LessonOrder,
 			IsPreview:     lesson.IsPreview,
+			LessonType:    lesson.LessonType,
 			IsCompleted:   progressMap[lesson.Id],
 			CreatedAt:     lesson.CreatedAt,
+			IsLocked:      lock.isLocked,
+			UnlockAt:      lock.unlockAt,
+			LockReason:    lock.reason,
 		}
 	}
 
@@ -102,13 +151,23 @@ func (ls *lessonService) GetLessonDetail(userId, courseId uint, slug string) (*d
 		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
 	}
 
-	// 4. Lấy progress detail của lesson
+	// 4. Kiểm tra lesson đã được mở khóa chưa
+	lock, err := getLessonLock(ls.lessonRepo, userId, courseId, lesson.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to resolve lesson schedule", utils.ErrCodeInternal)
+	}
+
+	if lock.isLocked {
+		return nil, utils.NewError(lockMessage(lock), utils.ErrCodeForbidden)
+	}
+
+	// 5. Lấy progress detail của lesson
 	progress, err := ls.lessonRepo.GetLessonProgressDetail(userId, lesson.Id)
 	if err != nil {
 		return nil, utils.WrapError(err, "Failed to get lesson progress", utils.ErrCodeInternal)
 	}
 
-	// 5. Lấy previous và next lesson
+	// 6. Lấy previous và next lesson
 	var previousLesson *dto.LessonNavigation
 	var nextLesson *dto.LessonNavigation
 
@@ -124,13 +183,47 @@ func (ls *lessonService) GetLessonDetail(userId, courseId uint, slug string) (*d
 	nxtLesson, err := ls.lessonRepo.GetNextLesson(courseId, lesson.LessonOrder)
 	if err == nil && nxtLesson != nil {
 		nextLesson = &dto.LessonNavigation{
-			Id:    nextLesson.Id,
-			Title: nextLesson.Title,
-			Slug:  nextLesson.Slug,
+			Id:    nxtLesson.Id,
+			Title: nxtLesson.Title,
+			Slug:  nxtLesson.Slug,
 		}
 	}
 
-	// 6. Convert sang DTO
+	// 7. Video đã transcode HLS thì trả về signed playback URL
+	videoURL := lesson.VideoURL
+	if lesson.VideoStatus == "ready" && lesson.HLSPath != "" {
+		videoURL = signedPlaybackURL(lesson, time.Now().Add(playbackURLTTL))
+	}
+
+	// 8. Lấy tài liệu đính kèm
+	attachments, err := ls.attachmentRepo.FindByLesson(lesson.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get lesson attachments", utils.ErrCodeInternal)
+	}
+
+	attachmentItems := make([]dto.LessonAttachmentItem, len(attachments))
+	for i := range attachments {
+		attachmentItems[i] = toLessonAttachmentItem(&attachments[i])
+	}
+
+	// 9. Lấy danh sách phụ đề
+	subtitles, err := ls.subtitleRepo.FindByLesson(lesson.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get lesson subtitles", utils.ErrCodeInternal)
+	}
+
+	subtitleItems := make([]dto.LessonSubtitleItem, len(subtitles))
+	for i := range subtitles {
+		subtitleItems[i] = toLessonSubtitleItem(ls.store, &subtitles[i])
+	}
+
+	// 10. Đếm ghi chú của học viên trên lesson
+	noteCount, err := ls.noteRepo.CountByUserAndLesson(userId, lesson.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count lesson notes", utils.ErrCodeInternal)
+	}
+
+	// 11. Convert sang DTO
 	return &dto.LessonDetail{
 		Id:             lesson.Id,
 		CourseId:       lesson.CourseId,
@@ -139,10 +232,11 @@ func (ls *lessonService) GetLessonDetail(userId, courseId uint, slug string) (*d
 		Slug:           lesson.Slug,
 		Description:    lesson.Description,
 		Content:        lesson.Content,
-		VideoURL:       lesson.VideoURL,
+		VideoURL:       videoURL,
 		VideoDuration:  lesson.VideoDuration,
 		LessonOrder:    lesson.LessonOrder,
 		IsPreview:      lesson.IsPreview,
+		LessonType:     lesson.LessonType,
 		IsCompleted:    progress.IsCompleted,
 		LastPosition:   progress.LastPosition,
 		WatchDuration:  progress.WatchDuration,
@@ -150,5 +244,125 @@ func (ls *lessonService) GetLessonDetail(userId, courseId uint, slug string) (*d
 		UpdatedAt:      lesson.UpdatedAt,
 		PreviousLesson: previousLesson,
 		NextLesson:     nextLesson,
+
+		RequirePreviousCompletion: lesson.RequirePreviousCompletion,
+		PrerequisiteQuizId:        lesson.PrerequisiteQuizId,
+
+		VideoStatus: lesson.VideoStatus,
+		PosterURL:   lesson.PosterURL,
+
+		Attachments: attachmentItems,
+		Subtitles:   subtitleItems,
+
+		NoteCount: noteCount,
 	}, nil
 }
+
+// getLessonLock tính trạng thái khóa của một lesson trong course
+func getLessonLock(lessonRepo repository.LessonRepository, userId, courseId, lessonId uint) (lessonLock, error) {
+	lessons, err := lessonRepo.GetCourseLessons(courseId)
+	if err != nil {
+		return lessonLock{}, err
+	}
+
+	lessonIds := make([]uint, len(lessons))
+	for i, lesson := range lessons {
+		lessonIds[i] = lesson.Id
+	}
+
+	progressMap, err := lessonRepo.GetLessonProgress(userId, lessonIds)
+	if err != nil {
+		return lessonLock{}, err
+	}
+
+	locks, err := resolveLessonLocks(lessonRepo, userId, courseId, lessons, progressMap)
+	if err != nil {
+		return lessonLock{}, err
+	}
+
+	return locks[lessonId], nil
+}
+
+// resolveLessonLocks tính trạng thái khóa cho danh sách lessons (đã sắp xếp theo lesson_order).
+// Lesson preview không bao giờ bị khóa.
+func resolveLessonLocks(lessonRepo repository.LessonRepository, userId, courseId uint, lessons []models.Lesson, progressMap map[uint]bool) (map[uint]lessonLock, error) {
+	enrollment, err := lessonRepo.GetUserEnrollment(userId, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lấy các quiz bắt buộc mà user đã pass
+	quizIds := make([]uint, 0)
+	for _, lesson := range lessons {
+		if lesson.PrerequisiteQuizId != nil {
+			quizIds = append(quizIds, *lesson.PrerequisiteQuizId)
+		}
+	}
+
+	passedQuizzes, err := lessonRepo.GetPassedQuizzes(userId, quizIds)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	locks := make(map[uint]lessonLock, len(lessons))
+
+	for i, lesson := range lessons {
+		if lesson.IsPreview {
+			locks[lesson.Id] = lessonLock{}
+			continue
+		}
+
+		// 1. Lịch drip-feed
+		if unlockAt := lessonUnlockTime(lesson, enrollment.EnrolledAt); unlockAt != nil && now.Before(*unlockAt) {
+			locks[lesson.Id] = lessonLock{isLocked: true, unlockAt: unlockAt, reason: lockReasonSchedule}
+			continue
+		}
+
+		// 2. Yêu cầu hoàn thành lesson trước đó
+		if lesson.RequirePreviousCompletion && i > 0 && !progressMap[lessons[i-1].Id] {
+			locks[lesson.Id] = lessonLock{isLocked: true, reason: lockReasonPreviousLesson}
+			continue
+		}
+
+		// 3. Yêu cầu pass quiz
+		if lesson.PrerequisiteQuizId != nil && !passedQuizzes[*lesson.PrerequisiteQuizId] {
+			locks[lesson.Id] = lessonLock{isLocked: true, reason: lockReasonQuiz}
+			continue
+		}
+
+		locks[lesson.Id] = lessonLock{}
+	}
+
+	return locks, nil
+}
+
+// lessonUnlockTime trả về thời điểm mở khóa của lesson (lấy mốc muộn nhất nếu có cả hai)
+func lessonUnlockTime(lesson models.Lesson, enrolledAt time.Time) *time.Time {
+	var unlockAt *time.Time
+
+	if lesson.UnlockAfterDays != nil && *lesson.UnlockAfterDays > 0 {
+		t := enrolledAt.AddDate(0, 0, *lesson.UnlockAfterDays)
+		unlockAt = &t
+	}
+
+	if lesson.UnlockAt != nil && (unlockAt == nil || lesson.UnlockAt.After(*unlockAt)) {
+		t := *lesson.UnlockAt
+		unlockAt = &t
+	}
+
+	return unlockAt
+}
+
+func lockMessage(lock lessonLock) string {
+	switch lock.reason {
+	case lockReasonSchedule:
+		return fmt.Sprintf("This lesson will be unlocked at %s", lock.unlockAt.Format(time.RFC3339))
+	case lockReasonPreviousLesson:
+		return "You must complete the previous lesson to access this lesson"
+	case lockReasonQuiz:
+		return "You must pass the required quiz to access this lesson"
+	default:
+		return "This lesson is locked"
+	}
+}
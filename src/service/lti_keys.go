@@ -0,0 +1,320 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// Key set của platform được cache trong khoảng này; kid lạ sẽ làm tải lại sớm hơn
+	ltiKeySetTTL = time.Hour
+	// Không tải lại key set quá thường xuyên khi gặp kid không tồn tại
+	ltiKeySetMinRefresh = 30 * time.Second
+	// Thời hạn của JWT do tool ký (client assertion, deep linking response)
+	ltiToolJWTLifetime = 5 * time.Minute
+)
+
+type ltiKeySet struct {
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+type ltiAccessToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// ltiKeys quản lý key của tool (ký JWT, công bố JWKS), key set của các platform và access token AGS
+type ltiKeys struct {
+	ltiRepo repository.LtiRepository
+	client  *http.Client
+
+	mu           sync.Mutex
+	toolKey      *rsa.PrivateKey
+	toolKid      string
+	platformKeys map[string]*ltiKeySet
+	tokens       map[string]ltiAccessToken
+}
+
+func newLtiKeys(ltiRepo repository.LtiRepository) *ltiKeys {
+	return &ltiKeys{
+		ltiRepo:      ltiRepo,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		platformKeys: make(map[string]*ltiKeySet),
+		tokens:       make(map[string]ltiAccessToken),
+	}
+}
+
+// signingKey trả về private key của tool: từ LTI_PRIVATE_KEY_FILE nếu có,
+// ngược lại dùng key lưu trong DB (tự sinh ở lần đầu, các instance dùng chung)
+func (lk *ltiKeys) signingKey() (*rsa.PrivateKey, string, error) {
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+
+	if lk.toolKey != nil {
+		return lk.toolKey, lk.toolKid, nil
+	}
+
+	if path := utils.GetEnv("LTI_PRIVATE_KEY_FILE", ""); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("read LTI private key: %w", err)
+		}
+		key, err := parseLtiPrivateKey(string(data))
+		if err != nil {
+			return nil, "", err
+		}
+		lk.toolKey, lk.toolKid = key, ltiKeyThumbprint(&key.PublicKey)
+		return lk.toolKey, lk.toolKid, nil
+	}
+
+	stored, err := lk.ltiRepo.GetToolKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if stored == nil {
+		generated, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, "", err
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(generated)
+		if err != nil {
+			return nil, "", err
+		}
+
+		// Instance khác có thể tạo key cùng lúc: đọc lại để mọi instance dùng key đầu tiên
+		_ = lk.ltiRepo.CreateToolKey(&models.LtiToolKey{
+			Kid:        ltiKeyThumbprint(&generated.PublicKey),
+			PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		})
+		if stored, err = lk.ltiRepo.GetToolKey(); err != nil || stored == nil {
+			return nil, "", fmt.Errorf("load LTI tool key: %v", err)
+		}
+	}
+
+	key, err := parseLtiPrivateKey(stored.PrivateKey)
+	if err != nil {
+		return nil, "", err
+	}
+	lk.toolKey, lk.toolKid = key, stored.Kid
+	return lk.toolKey, lk.toolKid, nil
+}
+
+func parseLtiPrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("LTI private key must be PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse LTI private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("LTI private key must be an RSA key")
+	}
+	return key, nil
+}
+
+func ltiKeyThumbprint(key *rsa.PublicKey) string {
+	sum := sha256.Sum256(x509.MarshalPKCS1PublicKey(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// jwks trả về public key của tool theo định dạng JSON Web Key Set
+func (lk *ltiKeys) jwks() (map[string]interface{}, error) {
+	key, kid, err := lk.signingKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": kid,
+			"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+		}},
+	}, nil
+}
+
+// sign ký JWT bằng key của tool (RS256, header kid để platform chọn đúng key)
+func (lk *ltiKeys) sign(claims jwt.Claims) (string, error) {
+	key, kid, err := lk.signingKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	return token.SignedString(key)
+}
+
+// platformKey tìm public key của platform theo kid, tải lại key set khi hết hạn hoặc gặp kid mới
+func (lk *ltiKeys) platformKey(jwksURL, kid string) (*rsa.PublicKey, error) {
+	lk.mu.Lock()
+	cached := lk.platformKeys[jwksURL]
+	lk.mu.Unlock()
+
+	if cached != nil && time.Since(cached.fetchedAt) < ltiKeySetTTL {
+		if key, ok := cached.keys[kid]; ok {
+			return key, nil
+		}
+		if time.Since(cached.fetchedAt) < ltiKeySetMinRefresh {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+	}
+
+	keys, err := lk.fetchKeySet(jwksURL)
+	if err != nil {
+		return nil, err
+	}
+
+	lk.mu.Lock()
+	lk.platformKeys[jwksURL] = &ltiKeySet{keys: keys, fetchedAt: time.Now()}
+	lk.mu.Unlock()
+
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	// Platform chỉ có một key và không dùng kid
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+func (lk *ltiKeys) fetchKeySet(jwksURL string) (map[string]*rsa.PublicKey, error) {
+	resp, err := lk.client.Get(jwksURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetch platform key set: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch platform key set: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("decode platform key set: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, item := range set.Keys {
+		if item.Kty != "RSA" || (item.Use != "" && item.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(strings.TrimRight(item.N, "="))
+		e, errE := base64.RawURLEncoding.DecodeString(strings.TrimRight(item.E, "="))
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		keys[item.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
+
+// accessToken lấy OAuth2 token của platform bằng client_credentials + client assertion (JWT ký bởi tool)
+func (lk *ltiKeys) accessToken(platform *models.LtiPlatform, scopes []string) (string, error) {
+	if platform.AuthTokenURL == "" {
+		return "", errors.New("platform has no token URL")
+	}
+
+	cacheKey := fmt.Sprintf("%d|%s", platform.Id, strings.Join(scopes, " "))
+	lk.mu.Lock()
+	cached, ok := lk.tokens[cacheKey]
+	lk.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.token, nil
+	}
+
+	jti, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	assertion, err := lk.sign(jwt.RegisteredClaims{
+		Issuer:    platform.ClientId,
+		Subject:   platform.ClientId,
+		Audience:  jwt.ClaimStrings{platform.AuthTokenURL},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ltiToolJWTLifetime)),
+		ID:        jti,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {assertion},
+		"scope":                 {strings.Join(scopes, " ")},
+	}
+	resp, err := lk.client.PostForm(platform.AuthTokenURL, form)
+	if err != nil {
+		return "", fmt.Errorf("request access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("request access token: status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil || result.AccessToken == "" {
+		return "", errors.New("platform returned an invalid access token response")
+	}
+
+	// Làm mới trước khi hết hạn 30 giây
+	lifetime := time.Duration(result.ExpiresIn)*time.Second - 30*time.Second
+	if lifetime > 0 {
+		lk.mu.Lock()
+		lk.tokens[cacheKey] = ltiAccessToken{token: result.AccessToken, expiresAt: time.Now().Add(lifetime)}
+		lk.mu.Unlock()
+	}
+	return result.AccessToken, nil
+}
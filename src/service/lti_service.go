@@ -0,0 +1,904 @@
+package service
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	ltiVersion = "1.3.0"
+
+	ltiMessageResourceLink = "LtiResourceLinkRequest"
+	ltiMessageDeepLinking  = "LtiDeepLinkingRequest"
+	ltiMessageDeepLinkResp = "LtiDeepLinkingResponse"
+	ltiContentResourceLink = "ltiResourceLink"
+
+	ltiAgsScopeLineItem = "https://purl.imsglobal.org/spec/lti-ags/scope/lineitem"
+	ltiAgsScopeScore    = "https://purl.imsglobal.org/spec/lti-ags/scope/score"
+
+	// State/nonce của OIDC login chỉ dùng được trong khoảng này
+	ltiStateTTL = 10 * time.Minute
+	// Thời gian instructor chọn course/lesson khi deep linking
+	ltiDeepLinkTTL = 30 * time.Minute
+	// Cho phép lệch đồng hồ giữa platform và tool
+	ltiClockLeeway = time.Minute
+)
+
+// Role (LIS vocabulary) được phép dùng deep linking để gắn nội dung vào course của LMS
+var ltiContentRoles = []string{"#Instructor", "#Administrator", "#ContentDeveloper"}
+
+// ltiLaunchClaims là các claim của id_token trong LTI 1.3 launch
+type ltiLaunchClaims struct {
+	jwt.RegisteredClaims
+	Nonce      string `json:"nonce"`
+	Azp        string `json:"azp"`
+	Name       string `json:"name"`
+	GivenName  string `json:"given_name"`
+	FamilyName string `json:"family_name"`
+	Email      string `json:"email"`
+
+	MessageType   string                 `json:"https://purl.imsglobal.org/spec/lti/claim/message_type"`
+	Version       string                 `json:"https://purl.imsglobal.org/spec/lti/claim/version"`
+	DeploymentId  string                 `json:"https://purl.imsglobal.org/spec/lti/claim/deployment_id"`
+	TargetLinkURI string                 `json:"https://purl.imsglobal.org/spec/lti/claim/target_link_uri"`
+	Roles         []string               `json:"https://purl.imsglobal.org/spec/lti/claim/roles"`
+	ResourceLink  *ltiIdTitleClaim       `json:"https://purl.imsglobal.org/spec/lti/claim/resource_link"`
+	Context       *ltiIdTitleClaim       `json:"https://purl.imsglobal.org/spec/lti/claim/context"`
+	Custom        map[string]interface{} `json:"https://purl.imsglobal.org/spec/lti/claim/custom"`
+
+	Ags *struct {
+		Scope     []string `json:"scope"`
+		LineItems string   `json:"lineitems"`
+		LineItem  string   `json:"lineitem"`
+	} `json:"https://purl.imsglobal.org/spec/lti-ags/claim/endpoint"`
+
+	DeepLinking *struct {
+		ReturnURL   string   `json:"deep_link_return_url"`
+		AcceptTypes []string `json:"accept_types"`
+		Data        string   `json:"data"`
+	} `json:"https://purl.imsglobal.org/spec/lti-dl/claim/deep_linking_settings"`
+}
+
+type ltiIdTitleClaim struct {
+	Id    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type ltiService struct {
+	ltiRepo        repository.LtiRepository
+	userRepo       repository.UserRepository
+	courseRepo     repository.CourseRepository
+	lessonRepo     repository.LessonRepository
+	enrollmentRepo repository.EnrollmentRepository
+	transactor     repository.Transactor
+	keys           *ltiKeys
+	baseURL        string
+	frontendURL    string
+}
+
+func NewLtiService(
+	ltiRepo repository.LtiRepository,
+	userRepo repository.UserRepository,
+	courseRepo repository.CourseRepository,
+	lessonRepo repository.LessonRepository,
+	enrollmentRepo repository.EnrollmentRepository,
+	transactor repository.Transactor,
+	baseURL string,
+	frontendURL string,
+) LtiService {
+	return &ltiService{
+		ltiRepo:        ltiRepo,
+		userRepo:       userRepo,
+		courseRepo:     courseRepo,
+		lessonRepo:     lessonRepo,
+		enrollmentRepo: enrollmentRepo,
+		transactor:     transactor,
+		keys:           newLtiKeys(ltiRepo),
+		baseURL:        baseURL,
+		frontendURL:    frontendURL,
+	}
+}
+
+// ---------------- Admin: quản lý platform ----------------
+func (ls *ltiService) CreatePlatform(adminId uint, req *dto.CreateLtiPlatformRequest) (*dto.LtiPlatformItem, error) {
+	// 1. Mỗi cặp issuer + client_id chỉ đăng ký một lần
+	issuer := strings.TrimSpace(req.Issuer) // Phải khớp chính xác claim iss
+	clientId := strings.TrimSpace(req.ClientId)
+
+	existing, err := ls.ltiRepo.FindPlatform(issuer, clientId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check platform", utils.ErrCodeInternal)
+	}
+	if existing != nil {
+		return nil, utils.NewError("A platform with this issuer and client_id is already registered", utils.ErrCodeConflict)
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	// 2. Lưu platform
+	platform := &models.LtiPlatform{
+		Name:          strings.TrimSpace(req.Name),
+		Issuer:        issuer,
+		ClientId:      clientId,
+		DeploymentIds: strings.Join(normalizeLtiDeploymentIds(req.DeploymentIds), ","),
+		AuthLoginURL:  strings.TrimSpace(req.AuthLoginURL),
+		AuthTokenURL:  strings.TrimSpace(req.AuthTokenURL),
+		JwksURL:       strings.TrimSpace(req.JwksURL),
+		IsActive:      isActive,
+		CreatedBy:     adminId,
+	}
+	if err := ls.ltiRepo.CreatePlatform(platform); err != nil {
+		return nil, utils.WrapError(err, "Failed to create platform", utils.ErrCodeInternal)
+	}
+
+	item := toLtiPlatformItem(platform)
+	return &item, nil
+}
+
+func (ls *ltiService) GetPlatforms(req *dto.GetLtiPlatformsQueryRequest) (*dto.GetLtiPlatformsResponse, error) {
+	// 1. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	// 2. Prepare filters
+	filters := make(map[string]interface{})
+	if req.IsActive != nil {
+		filters["is_active"] = *req.IsActive
+	}
+
+	// 3. Lấy danh sách platform
+	platforms, total, err := ls.ltiRepo.GetPlatforms(offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get platforms", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.LtiPlatformItem, len(platforms))
+	for i := range platforms {
+		items[i] = toLtiPlatformItem(&platforms[i])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetLtiPlatformsResponse{
+		Platforms: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (ls *ltiService) GetPlatform(platformId uint) (*dto.LtiPlatformItem, error) {
+	platform, err := ls.ltiRepo.FindPlatformById(platformId)
+	if err != nil {
+		return nil, utils.NewError("Platform not found", utils.ErrCodeNotFound)
+	}
+
+	item := toLtiPlatformItem(platform)
+	return &item, nil
+}
+
+func (ls *ltiService) UpdatePlatform(platformId uint, req *dto.UpdateLtiPlatformRequest) (*dto.LtiPlatformItem, error) {
+	// 1. Kiểm tra platform tồn tại
+	if _, err := ls.ltiRepo.FindPlatformById(platformId); err != nil {
+		return nil, utils.NewError("Platform not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Chuẩn bị updates (issuer và client_id không đổi được - đăng ký platform mới thay thế)
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		updates["name"] = strings.TrimSpace(*req.Name)
+	}
+	if req.DeploymentIds != nil {
+		updates["deployment_ids"] = strings.Join(normalizeLtiDeploymentIds(req.DeploymentIds), ",")
+	}
+	if req.AuthLoginURL != nil {
+		updates["auth_login_url"] = strings.TrimSpace(*req.AuthLoginURL)
+	}
+	if req.AuthTokenURL != nil {
+		// Chuỗi rỗng = tắt grade passback
+		tokenURL := strings.TrimSpace(*req.AuthTokenURL)
+		if tokenURL != "" && !utils.IsWebhookURL(tokenURL) {
+			return nil, utils.NewError("auth_token_url must be an absolute http(s) URL", utils.ErrCodeBadRequest)
+		}
+		updates["auth_token_url"] = tokenURL
+	}
+	if req.JwksURL != nil {
+		updates["jwks_url"] = strings.TrimSpace(*req.JwksURL)
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+
+	if len(updates) == 0 {
+		return nil, utils.NewError("No fields to update", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Cập nhật
+	if err := ls.ltiRepo.UpdatePlatform(platformId, updates); err != nil {
+		return nil, utils.WrapError(err, "Failed to update platform", utils.ErrCodeInternal)
+	}
+
+	return ls.GetPlatform(platformId)
+}
+
+// DeletePlatform xóa platform cùng các liên kết resource link/user; tài khoản và enrollment được giữ lại
+func (ls *ltiService) DeletePlatform(platformId uint) (*dto.DeleteLtiPlatformResponse, error) {
+	if _, err := ls.ltiRepo.FindPlatformById(platformId); err != nil {
+		return nil, utils.NewError("Platform not found", utils.ErrCodeNotFound)
+	}
+
+	if err := ls.ltiRepo.DeletePlatform(platformId); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete platform", utils.ErrCodeInternal)
+	}
+
+	return &dto.DeleteLtiPlatformResponse{
+		Message: "Platform deleted successfully",
+		Id:      platformId,
+	}, nil
+}
+
+// ---------------- Tool ----------------
+func (ls *ltiService) launchURL() string {
+	return ls.baseURL + "/api/v1/lti/launch"
+}
+
+// GetToolConfig trả về các URL admin của LMS cần khai báo khi đăng ký tool
+func (ls *ltiService) GetToolConfig() *dto.LtiToolConfigResponse {
+	return &dto.LtiToolConfigResponse{
+		LoginURL:       ls.baseURL + "/api/v1/lti/login",
+		RedirectURIs:   []string{ls.launchURL()},
+		LaunchURL:      ls.launchURL(),
+		DeepLinkingURL: ls.launchURL(),
+		JwksURL:        ls.baseURL + "/api/v1/lti/jwks",
+		Scopes:         []string{ltiAgsScopeLineItem, ltiAgsScopeScore},
+	}
+}
+
+func (ls *ltiService) GetJWKS() (map[string]interface{}, error) {
+	jwks, err := ls.keys.jwks()
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to load tool key", utils.ErrCodeInternal)
+	}
+	return jwks, nil
+}
+
+// Login xử lý OIDC login initiation: lưu state + nonce rồi chuyển người dùng về authorization endpoint của platform
+func (ls *ltiService) Login(req *dto.LtiLoginRequest) (*dto.LtiLaunchResult, error) {
+	// 1. Xác định platform theo issuer (và client_id nếu platform gửi kèm)
+	platforms, err := ls.ltiRepo.FindActivePlatforms(req.Issuer, req.ClientId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to find platform", utils.ErrCodeInternal)
+	}
+	if len(platforms) == 0 {
+		return nil, utils.NewError("Unknown LTI platform", utils.ErrCodeNotFound)
+	}
+	if len(platforms) > 1 {
+		return nil, utils.NewError("client_id is required for this issuer", utils.ErrCodeBadRequest)
+	}
+	platform := &platforms[0]
+
+	// 2. Kiểm tra deployment và target_link_uri thuộc tool này
+	if req.LtiDeploymentId != "" && !ltiDeploymentAllowed(platform, req.LtiDeploymentId) {
+		return nil, utils.NewError("Deployment is not registered for this platform", utils.ErrCodeForbidden)
+	}
+	if !strings.HasPrefix(req.TargetLinkURI, ls.baseURL+"/") {
+		return nil, utils.NewError("target_link_uri does not belong to this tool", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Lưu state + nonce để đối chiếu khi nhận id_token
+	state, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to generate state", utils.ErrCodeInternal)
+	}
+	nonce, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to generate nonce", utils.ErrCodeInternal)
+	}
+
+	expiresAt := time.Now().Add(ltiStateTTL)
+	if err := ls.ltiRepo.CreateLaunchState(&models.LtiLaunchState{
+		State:      state,
+		Nonce:      nonce,
+		PlatformId: platform.Id,
+		ExpiresAt:  expiresAt,
+	}); err != nil {
+		return nil, utils.WrapError(err, "Failed to save launch state", utils.ErrCodeInternal)
+	}
+
+	// 4. Authentication request gửi tới platform
+	query := url.Values{
+		"scope":         {"openid"},
+		"response_type": {"id_token"},
+		"response_mode": {"form_post"},
+		"prompt":        {"none"},
+		"client_id":     {platform.ClientId},
+		"redirect_uri":  {ls.launchURL()},
+		"login_hint":    {req.LoginHint},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+	if req.LtiMessageHint != "" {
+		query.Set("lti_message_hint", req.LtiMessageHint)
+	}
+
+	// State cũng được lưu vào cookie của trình duyệt này (handler) để launch chỉ nhận id_token từ đúng trình duyệt
+	return &dto.LtiLaunchResult{
+		RedirectURL:    ltiAppendQuery(platform.AuthLoginURL, query),
+		State:          state,
+		StateExpiresAt: expiresAt,
+	}, nil
+}
+
+// Launch kiểm tra id_token do platform gửi về và xử lý theo loại message
+func (ls *ltiService) Launch(req *dto.LtiLaunchRequest) (*dto.LtiLaunchResult, error) {
+	// 1. State phải khớp cookie của trình duyệt đã bắt đầu login (chống login CSRF) và chỉ dùng được một lần
+	if req.BrowserState == "" || subtle.ConstantTimeCompare([]byte(req.BrowserState), []byte(req.State)) != 1 {
+		return nil, utils.NewError("Launch was not started from this browser", utils.ErrCodeBadRequest)
+	}
+	state, err := ls.ltiRepo.ConsumeLaunchState(req.State)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check launch state", utils.ErrCodeInternal)
+	}
+	if state == nil || time.Now().After(state.ExpiresAt) {
+		return nil, utils.NewError("Invalid or expired launch state", utils.ErrCodeBadRequest)
+	}
+
+	platform, err := ls.ltiRepo.FindPlatformById(state.PlatformId)
+	if err != nil || !platform.IsActive {
+		return nil, utils.NewError("Unknown LTI platform", utils.ErrCodeNotFound)
+	}
+
+	// 2. Xác thực chữ ký bằng key set của platform và các claim chuẩn
+	claims := &ltiLaunchClaims{}
+	_, err = jwt.ParseWithClaims(req.IdToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return ls.keys.platformKey(platform.JwksURL, kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(platform.Issuer),
+		jwt.WithAudience(platform.ClientId),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(ltiClockLeeway),
+	)
+	if err != nil {
+		return nil, utils.NewError("Invalid id_token: "+err.Error(), utils.ErrCodeUnauthorized)
+	}
+
+	// 3. Claim riêng của OIDC và LTI
+	if claims.Nonce != state.Nonce {
+		return nil, utils.NewError("Invalid id_token: nonce mismatch", utils.ErrCodeUnauthorized)
+	}
+	if (len(claims.Audience) > 1 || claims.Azp != "") && claims.Azp != platform.ClientId {
+		return nil, utils.NewError("Invalid id_token: azp must be the tool client_id", utils.ErrCodeUnauthorized)
+	}
+	if claims.Version != ltiVersion {
+		return nil, utils.NewError("Unsupported LTI version", utils.ErrCodeBadRequest)
+	}
+	if claims.DeploymentId == "" || !ltiDeploymentAllowed(platform, claims.DeploymentId) {
+		return nil, utils.NewError("Deployment is not registered for this platform", utils.ErrCodeForbidden)
+	}
+	if claims.Subject == "" {
+		return nil, utils.NewError("Anonymous launches are not supported", utils.ErrCodeBadRequest)
+	}
+
+	switch claims.MessageType {
+	case ltiMessageResourceLink:
+		return ls.launchResourceLink(platform, claims)
+	case ltiMessageDeepLinking:
+		return ls.launchDeepLinking(platform, claims)
+	default:
+		return nil, utils.NewError("Unsupported LTI message type", utils.ErrCodeBadRequest)
+	}
+}
+
+// launchResourceLink đăng nhập (tự tạo tài khoản nếu cần), enroll rồi đưa người dùng tới course/lesson
+func (ls *ltiService) launchResourceLink(platform *models.LtiPlatform, claims *ltiLaunchClaims) (*dto.LtiLaunchResult, error) {
+	if claims.ResourceLink == nil || claims.ResourceLink.Id == "" {
+		return nil, utils.NewError("Missing resource_link claim", utils.ErrCodeBadRequest)
+	}
+
+	// 1. Xác định course/lesson: custom parameter > resource link đã lưu > query của target_link_uri
+	link, err := ls.ltiRepo.FindResourceLink(platform.Id, claims.ResourceLink.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to find resource link", utils.ErrCodeInternal)
+	}
+	if link == nil {
+		link = &models.LtiResourceLink{PlatformId: platform.Id, ResourceLinkId: claims.ResourceLink.Id}
+	}
+
+	courseId, lessonId, found := ltiTarget(func(key string) string {
+		if value, ok := claims.Custom[key]; ok {
+			return fmt.Sprint(value)
+		}
+		return ""
+	})
+	if !found && link.Id != 0 {
+		courseId, lessonId, found = link.CourseId, link.LessonId, true
+	}
+	if !found {
+		if target, err := url.Parse(claims.TargetLinkURI); err == nil {
+			courseId, lessonId, found = ltiTarget(target.Query().Get)
+		}
+	}
+	if !found {
+		return nil, utils.NewError("Launch does not identify a course; add it with deep linking or a course_id custom parameter", utils.ErrCodeBadRequest)
+	}
+
+	course, err := ls.validateTarget(courseId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Tài khoản của người dùng trên platform
+	user, err := ls.provisionUser(platform, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Enroll - quyền truy cập do LMS bên ngoài quản lý nên không qua order
+	if _, enrolled := ls.enrollmentRepo.CheckEnrollment(user.Id, course.Id); !enrolled {
+		enrollment := &models.Enrollment{
+			UserId:     user.Id,
+			CourseId:   course.Id,
+			EnrolledAt: time.Now(),
+			Status:     "active",
+		}
+		err := ls.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+			if err := repos.Enrollments.Create(enrollment); err != nil {
+				return err
+			}
+			return repos.Outbox.Append(newEnrollmentCreatedEvent(enrollment, 0))
+		})
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to enroll user", utils.ErrCodeInternal)
+		}
+	}
+
+	// 4. Lưu resource link (kèm cột điểm AGS) và ghi nhận người dùng để gửi điểm về sau này
+	link.DeploymentId = claims.DeploymentId
+	link.Title = claims.ResourceLink.Title
+	link.CourseId = course.Id
+	link.LessonId = lessonId
+	if claims.Context != nil {
+		link.ContextId = claims.Context.Id
+		link.ContextTitle = claims.Context.Title
+	}
+	if claims.Ags != nil {
+		link.LineItemURL = claims.Ags.LineItem
+		link.AgsScopes = strings.Join(claims.Ags.Scope, " ")
+	}
+
+	if err := ls.saveResourceLink(link); err != nil {
+		return nil, utils.WrapError(err, "Failed to save resource link", utils.ErrCodeInternal)
+	}
+	if err := ls.ltiRepo.AddResourceLinkUser(&models.LtiResourceLinkUser{
+		ResourceLinkId: link.Id,
+		UserId:         user.Id,
+		Subject:        claims.Subject,
+	}); err != nil {
+		return nil, utils.WrapError(err, "Failed to save resource link user", utils.ErrCodeInternal)
+	}
+
+	// 5. Đăng nhập: token nằm trong fragment để không bị gửi lên server hay ghi vào log
+	accessToken, refreshToken, err := utils.GenerateTokens(user.Id, user.Email, user.Role, user.TenantId, user.IsSuperAdmin)
+	if err != nil {
+		return nil, utils.NewError("failed to create tokens", utils.ErrCodeInternal)
+	}
+
+	fragment := url.Values{
+		"access_token":  {accessToken},
+		"refresh_token": {refreshToken},
+		"course_id":     {strconv.FormatUint(uint64(course.Id), 10)},
+		"course_slug":   {course.Slug},
+	}
+	if lessonId != nil {
+		fragment.Set("lesson_id", strconv.FormatUint(uint64(*lessonId), 10))
+	}
+
+	return &dto.LtiLaunchResult{RedirectURL: ls.frontendURL + "/lti/launch#" + fragment.Encode()}, nil
+}
+
+// saveResourceLink lưu resource link; hai launch đầu tiên chạy song song sẽ cùng cập nhật một bản ghi
+func (ls *ltiService) saveResourceLink(link *models.LtiResourceLink) error {
+	err := ls.ltiRepo.SaveResourceLink(link)
+	if err == nil || link.Id != 0 {
+		return err
+	}
+
+	existing, findErr := ls.ltiRepo.FindResourceLink(link.PlatformId, link.ResourceLinkId)
+	if findErr != nil || existing == nil {
+		return err
+	}
+	link.Id, link.CreatedAt = existing.Id, existing.CreatedAt
+	return ls.ltiRepo.SaveResourceLink(link)
+}
+
+// provisionUser tìm tài khoản đã liên kết với (platform, sub) hoặc tạo tài khoản học viên mới.
+// Không tự gộp vào tài khoản có sẵn theo email: platform bên ngoài không được phép đăng nhập thay người dùng.
+func (ls *ltiService) provisionUser(platform *models.LtiPlatform, claims *ltiLaunchClaims) (*models.User, error) {
+	// 1. Tài khoản đã liên kết
+	link, err := ls.ltiRepo.FindUserLink(platform.Id, claims.Subject)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to find user link", utils.ErrCodeInternal)
+	}
+	if link != nil {
+		return ls.linkedUser(link)
+	}
+
+	// 2. Tạo tài khoản mới: username suy ra từ sub, email thật nếu chưa ai dùng
+	sum := sha256.Sum256([]byte(claims.Subject))
+	username := fmt.Sprintf("lti%d_%s", platform.Id, hex.EncodeToString(sum[:])[:12])
+
+	email := utils.NormalizeString(claims.Email)
+	hasEmail := email != "" && len(email) <= 100
+	if hasEmail {
+		if _, exists := ls.userRepo.FindByEmail(email); exists {
+			hasEmail = false
+		}
+	}
+	if !hasEmail {
+		email = username + "@lti.invalid"
+	}
+
+	fullName := strings.TrimSpace(claims.Name)
+	if fullName == "" {
+		fullName = strings.TrimSpace(claims.GivenName + " " + claims.FamilyName)
+	}
+	if fullName == "" {
+		fullName = username
+	}
+	if runes := []rune(fullName); len(runes) > 100 {
+		fullName = string(runes[:100])
+	}
+
+	// Người dùng chỉ đăng nhập qua LTI (hoặc đặt lại mật khẩu bằng email thật)
+	password, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to generate password", utils.ErrCodeInternal)
+	}
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to hash password", utils.ErrCodeInternal)
+	}
+
+	user := &models.User{
+		Username: username,
+		Email:    email,
+		Password: hashedPassword,
+		FullName: fullName,
+		Role:     "student",
+		Status:   "active",
+	}
+
+	// 3. Lưu user + liên kết trong cùng transaction
+	err = ls.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		if err := repos.Users.Create(user); err != nil {
+			return err
+		}
+		if err := repos.Lti.CreateUserLink(&models.LtiUserLink{
+			PlatformId: platform.Id,
+			Subject:    claims.Subject,
+			UserId:     user.Id,
+		}); err != nil {
+			return err
+		}
+		if !hasEmail {
+			return nil
+		}
+		return repos.Outbox.Append(dto.UserRegisteredEvent{
+			TenantId: user.TenantId,
+			UserId:   user.Id,
+			Username: user.Username,
+			Email:    user.Email,
+			FullName: user.FullName,
+		})
+	})
+	if err != nil {
+		// Launch song song của cùng người dùng đã tạo tài khoản trước
+		if link, findErr := ls.ltiRepo.FindUserLink(platform.Id, claims.Subject); findErr == nil && link != nil {
+			return ls.linkedUser(link)
+		}
+		return nil, utils.WrapError(err, "failed to create user", utils.ErrCodeInternal)
+	}
+
+	return user, nil
+}
+
+func (ls *ltiService) linkedUser(link *models.LtiUserLink) (*models.User, error) {
+	user, err := ls.userRepo.FindById(link.UserId)
+	if err != nil {
+		return nil, utils.NewError("Linked account no longer exists", utils.ErrCodeForbidden)
+	}
+	if user.Status != "active" {
+		return nil, utils.NewError("account is inactive", utils.ErrCodeForbidden)
+	}
+	return user, nil
+}
+
+// validateTarget kiểm tra course đã publish và lesson (nếu có) thuộc course
+func (ls *ltiService) validateTarget(courseId uint, lessonId *uint) (*models.Course, error) {
+	course, err := ls.courseRepo.FindById(courseId)
+	if err != nil || course.Status != "published" {
+		return nil, utils.NewError("Course not found", utils.ErrCodeNotFound)
+	}
+
+	if lessonId != nil {
+		lessons, err := ls.lessonRepo.FindLessonByIds([]uint{*lessonId})
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to get lesson", utils.ErrCodeInternal)
+		}
+		if len(lessons) == 0 || lessons[0].CourseId != course.Id || !lessons[0].IsPublished {
+			return nil, utils.NewError("Lesson not found in this course", utils.ErrCodeNotFound)
+		}
+	}
+
+	return course, nil
+}
+
+// ---------------- Deep linking ----------------
+// launchDeepLinking mở phiên để instructor chọn course/lesson gắn vào LMS
+func (ls *ltiService) launchDeepLinking(platform *models.LtiPlatform, claims *ltiLaunchClaims) (*dto.LtiLaunchResult, error) {
+	// 1. Chỉ instructor/admin của LMS được thêm nội dung
+	if !ltiHasRole(claims.Roles, ltiContentRoles) {
+		return nil, utils.NewError("Only instructors can add content", utils.ErrCodeForbidden)
+	}
+
+	settings := claims.DeepLinking
+	if settings == nil || settings.ReturnURL == "" {
+		return nil, utils.NewError("Missing deep_linking_settings claim", utils.ErrCodeBadRequest)
+	}
+	if !slices.Contains(settings.AcceptTypes, ltiContentResourceLink) {
+		return nil, utils.NewError("Platform does not accept LTI resource links", utils.ErrCodeBadRequest)
+	}
+
+	// 2. Lưu phiên, frontend dùng token để hiển thị trang chọn nội dung
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to generate session token", utils.ErrCodeInternal)
+	}
+
+	session := &models.LtiDeepLinkSession{
+		TokenHash:      utils.HashToken(token),
+		PlatformId:     platform.Id,
+		DeploymentId:   claims.DeploymentId,
+		ReturnURL:      settings.ReturnURL,
+		Data:           settings.Data,
+		AcceptLineItem: claims.Ags != nil,
+		ExpiresAt:      time.Now().Add(ltiDeepLinkTTL),
+	}
+	if claims.Context != nil {
+		session.ContextTitle = claims.Context.Title
+	}
+
+	if err := ls.ltiRepo.CreateDeepLinkSession(session); err != nil {
+		return nil, utils.WrapError(err, "Failed to create deep linking session", utils.ErrCodeInternal)
+	}
+
+	return &dto.LtiLaunchResult{RedirectURL: ls.frontendURL + "/lti/deep-link#session=" + url.QueryEscape(token)}, nil
+}
+
+func (ls *ltiService) findDeepLinkSession(token string) (*models.LtiDeepLinkSession, *models.LtiPlatform, error) {
+	session, err := ls.ltiRepo.FindDeepLinkSession(utils.HashToken(token))
+	if err != nil {
+		return nil, nil, utils.WrapError(err, "Failed to get deep linking session", utils.ErrCodeInternal)
+	}
+	if session == nil || session.UsedAt != nil || time.Now().After(session.ExpiresAt) {
+		return nil, nil, utils.NewError("Deep linking session not found or expired", utils.ErrCodeNotFound)
+	}
+
+	platform, err := ls.ltiRepo.FindPlatformById(session.PlatformId)
+	if err != nil || !platform.IsActive {
+		return nil, nil, utils.NewError("Unknown LTI platform", utils.ErrCodeNotFound)
+	}
+
+	return session, platform, nil
+}
+
+func (ls *ltiService) GetDeepLinkSession(token string) (*dto.LtiDeepLinkSessionResponse, error) {
+	session, platform, err := ls.findDeepLinkSession(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LtiDeepLinkSessionResponse{
+		PlatformName:   platform.Name,
+		ContextTitle:   session.ContextTitle,
+		AcceptLineItem: session.AcceptLineItem,
+		ExpiresAt:      session.ExpiresAt,
+	}, nil
+}
+
+// CreateDeepLink ký LtiDeepLinkingResponse chứa resource link tới course/lesson đã chọn.
+// Frontend POST JWT (field "JWT") tới return_url của platform.
+func (ls *ltiService) CreateDeepLink(token string, req *dto.LtiDeepLinkRequest) (*dto.LtiDeepLinkResponse, error) {
+	// 1. Kiểm tra phiên và nội dung được chọn
+	session, platform, err := ls.findDeepLinkSession(token)
+	if err != nil {
+		return nil, err
+	}
+
+	course, err := ls.validateTarget(req.CourseId, req.LessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Mỗi phiên chỉ trả về platform một lần
+	used, err := ls.ltiRepo.MarkDeepLinkSessionUsed(session.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to update deep linking session", utils.ErrCodeInternal)
+	}
+	if !used {
+		return nil, utils.NewError("Deep linking session has already been used", utils.ErrCodeConflict)
+	}
+
+	// 3. Content item: launch URL + custom parameter để launch sau này biết course/lesson
+	title := course.Title
+	target := url.Values{"course_id": {strconv.FormatUint(uint64(course.Id), 10)}}
+	resourceId := fmt.Sprintf("course-%d", course.Id)
+	if req.LessonId != nil {
+		lessons, _ := ls.lessonRepo.FindLessonByIds([]uint{*req.LessonId})
+		if len(lessons) > 0 {
+			title = lessons[0].Title
+		}
+		target.Set("lesson_id", strconv.FormatUint(uint64(*req.LessonId), 10))
+		resourceId = fmt.Sprintf("lesson-%d", *req.LessonId)
+	}
+
+	custom := make(map[string]string, len(target))
+	for key := range target {
+		custom[key] = target.Get(key)
+	}
+
+	item := map[string]interface{}{
+		"type":   ltiContentResourceLink,
+		"title":  title,
+		"url":    ltiAppendQuery(ls.launchURL(), target),
+		"custom": custom,
+	}
+	if session.AcceptLineItem {
+		item["lineItem"] = map[string]interface{}{
+			"scoreMaximum": 100,
+			"label":        title,
+			"resourceId":   resourceId,
+		}
+	}
+
+	// 4. Ký response bằng key của tool
+	nonce, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to generate nonce", utils.ErrCodeInternal)
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   platform.ClientId,
+		"aud":   platform.Issuer,
+		"iat":   now.Unix(),
+		"exp":   now.Add(ltiToolJWTLifetime).Unix(),
+		"nonce": nonce,
+		"https://purl.imsglobal.org/spec/lti/claim/message_type":     ltiMessageDeepLinkResp,
+		"https://purl.imsglobal.org/spec/lti/claim/version":          ltiVersion,
+		"https://purl.imsglobal.org/spec/lti/claim/deployment_id":    session.DeploymentId,
+		"https://purl.imsglobal.org/spec/lti-dl/claim/content_items": []interface{}{item},
+	}
+	if session.Data != "" {
+		claims["https://purl.imsglobal.org/spec/lti-dl/claim/data"] = session.Data
+	}
+
+	signed, err := ls.keys.sign(claims)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to sign deep linking response", utils.ErrCodeInternal)
+	}
+
+	return &dto.LtiDeepLinkResponse{ReturnURL: session.ReturnURL, JWT: signed}, nil
+}
+
+// ---------------- Helpers ----------------
+func toLtiPlatformItem(platform *models.LtiPlatform) dto.LtiPlatformItem {
+	deploymentIds := []string{}
+	if platform.DeploymentIds != "" {
+		deploymentIds = strings.Split(platform.DeploymentIds, ",")
+	}
+
+	return dto.LtiPlatformItem{
+		Id:            platform.Id,
+		Name:          platform.Name,
+		Issuer:        platform.Issuer,
+		ClientId:      platform.ClientId,
+		DeploymentIds: deploymentIds,
+		AuthLoginURL:  platform.AuthLoginURL,
+		AuthTokenURL:  platform.AuthTokenURL,
+		JwksURL:       platform.JwksURL,
+		IsActive:      platform.IsActive,
+		CreatedBy:     platform.CreatedBy,
+		CreatedAt:     platform.CreatedAt,
+		UpdatedAt:     platform.UpdatedAt,
+	}
+}
+
+func normalizeLtiDeploymentIds(ids []string) []string {
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id != "" && !strings.Contains(id, ",") && !slices.Contains(result, id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// ltiDeploymentAllowed: platform không khai báo deployment nào thì chấp nhận mọi deployment
+func ltiDeploymentAllowed(platform *models.LtiPlatform, deploymentId string) bool {
+	if platform.DeploymentIds == "" {
+		return true
+	}
+	return slices.Contains(strings.Split(platform.DeploymentIds, ","), deploymentId)
+}
+
+func ltiHasRole(roles []string, allowed []string) bool {
+	for _, role := range roles {
+		for _, suffix := range allowed {
+			if strings.HasSuffix(role, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ltiTarget đọc course_id/lesson_id từ custom parameter hoặc query string
+func ltiTarget(get func(key string) string) (uint, *uint, bool) {
+	courseId, err := strconv.ParseUint(get("course_id"), 10, 32)
+	if err != nil || courseId == 0 {
+		return 0, nil, false
+	}
+
+	var lessonId *uint
+	if value := get("lesson_id"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || parsed == 0 {
+			return 0, nil, false
+		}
+		id := uint(parsed)
+		lessonId = &id
+	}
+
+	return uint(courseId), lessonId, true
+}
+
+func ltiAppendQuery(rawURL string, query url.Values) string {
+	separator := "?"
+	if strings.Contains(rawURL, "?") {
+		separator = "&"
+	}
+	return rawURL + separator + query.Encode()
+}
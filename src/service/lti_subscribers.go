@@ -0,0 +1,207 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Điểm gửi về gradebook luôn theo thang 0-100 (khớp scoreMaximum khi tạo line item bằng deep linking)
+const ltiScoreMaximum = 100.0
+
+// RegisterLtiSubscribers đăng ký grade passback (Assignment and Grade Services) cho học viên launch từ LMS bên ngoài.
+// Resource link trỏ tới course nhận phần trăm tiến độ, resource link trỏ tới lesson nhận điểm quiz tốt nhất
+// (hoặc 100 khi hoàn thành lesson không có quiz).
+func RegisterLtiSubscribers(
+	dispatcher *EventDispatcher,
+	ltiRepo repository.LtiRepository,
+	progressRepo repository.ProgressRepository,
+	lessonRepo repository.LessonRepository,
+	quizRepo repository.QuizRepository,
+) {
+	passback := &ltiGradePassback{
+		ltiRepo:      ltiRepo,
+		progressRepo: progressRepo,
+		lessonRepo:   lessonRepo,
+		quizRepo:     quizRepo,
+		keys:         newLtiKeys(ltiRepo),
+	}
+
+	dispatcher.Subscribe(dto.EventLessonCompleted, "lti_grade_passback", HandleEvent(func(event dto.LessonCompletedEvent) error {
+		return passback.sync(event.UserId, event.CourseId, event.LessonId, true)
+	}))
+
+	dispatcher.Subscribe(dto.EventQuizSubmitted, "lti_grade_passback", HandleEvent(func(event dto.QuizSubmittedEvent) error {
+		return passback.sync(event.UserId, event.CourseId, event.LessonId, false)
+	}))
+}
+
+type ltiGradePassback struct {
+	ltiRepo      repository.LtiRepository
+	progressRepo repository.ProgressRepository
+	lessonRepo   repository.LessonRepository
+	quizRepo     repository.QuizRepository
+	keys         *ltiKeys
+}
+
+// ltiScore là payload của AGS Score service
+type ltiScore struct {
+	UserId           string  `json:"userId"`
+	ScoreGiven       float64 `json:"scoreGiven"`
+	ScoreMaximum     float64 `json:"scoreMaximum"`
+	ActivityProgress string  `json:"activityProgress"`
+	GradingProgress  string  `json:"gradingProgress"`
+	Timestamp        string  `json:"timestamp"`
+}
+
+// sync gửi điểm tới mọi cột điểm liên quan mà user đã launch; lỗi được trả về để dispatcher retry
+func (gp *ltiGradePassback) sync(userId, courseId, lessonId uint, lessonCompleted bool) error {
+	targets, err := gp.ltiRepo.GetGradeTargets(userId, courseId)
+	if err != nil || len(targets) == 0 {
+		return err
+	}
+
+	var errs []error
+	for i := range targets {
+		target := &targets[i]
+		link := &target.ResourceLink
+		if !strings.Contains(" "+link.AgsScopes+" ", " "+ltiAgsScopeScore+" ") {
+			continue
+		}
+
+		// 1. Tính điểm theo loại resource link
+		var score *ltiScore
+		switch {
+		case link.LessonId == nil && lessonCompleted:
+			score, err = gp.courseScore(userId, courseId)
+		case link.LessonId != nil && *link.LessonId == lessonId:
+			score, err = gp.lessonScore(userId, lessonId)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if score == nil {
+			continue
+		}
+		score.UserId = target.Subject
+
+		// 2. Gửi điểm và ghi nhận lần đồng bộ
+		if err := gp.postScore(link, score); err != nil {
+			errs = append(errs, fmt.Errorf("resource link %d: %w", link.Id, err))
+			continue
+		}
+		if err := gp.ltiRepo.UpdateGradeSync(target.Id, score.ScoreGiven, time.Now()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// courseScore: phần trăm lesson đã hoàn thành của course
+func (gp *ltiGradePassback) courseScore(userId, courseId uint) (*ltiScore, error) {
+	lessons, err := gp.lessonRepo.GetCourseLessons(courseId)
+	if err != nil || len(lessons) == 0 {
+		return nil, err
+	}
+
+	completed, err := gp.progressRepo.CountCompletedLessons(userId, courseId)
+	if err != nil {
+		return nil, err
+	}
+	if completed > len(lessons) {
+		completed = len(lessons)
+	}
+
+	activityProgress := "InProgress"
+	if completed == len(lessons) {
+		activityProgress = "Completed"
+	}
+
+	return newLtiScore(float64(completed)*ltiScoreMaximum/float64(len(lessons)), activityProgress), nil
+}
+
+// lessonScore: điểm quiz tốt nhất của lesson, hoặc điểm tối đa nếu lesson không có quiz
+func (gp *ltiGradePassback) lessonScore(userId, lessonId uint) (*ltiScore, error) {
+	quiz, err := gp.quizRepo.FindByLesson(lessonId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return newLtiScore(ltiScoreMaximum, "Completed"), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attempt, err := gp.quizRepo.GetBestAttempt(userId, quiz.Id)
+	if err != nil || attempt == nil {
+		return nil, err
+	}
+	return newLtiScore(attempt.Score, "Completed"), nil
+}
+
+func newLtiScore(given float64, activityProgress string) *ltiScore {
+	return &ltiScore{
+		ScoreGiven:       given,
+		ScoreMaximum:     ltiScoreMaximum,
+		ActivityProgress: activityProgress,
+		GradingProgress:  "FullyGraded",
+		Timestamp:        time.Now().UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// postScore POST tới {lineitem}/scores (giữ nguyên query string của line item URL)
+func (gp *ltiGradePassback) postScore(link *models.LtiResourceLink, score *ltiScore) error {
+	platform, err := gp.ltiRepo.FindPlatformById(link.PlatformId)
+	if err != nil {
+		return err
+	}
+	if !platform.IsActive {
+		return nil
+	}
+
+	scoresURL, err := url.Parse(link.LineItemURL)
+	if err != nil {
+		return fmt.Errorf("invalid line item URL: %w", err)
+	}
+	scoresURL.Path = strings.TrimRight(scoresURL.Path, "/") + "/scores"
+
+	token, err := gp.keys.accessToken(platform, []string{ltiAgsScopeScore})
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(score)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, scoresURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.ims.lis.v1.score+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := gp.keys.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("platform responded %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"math"
+	"strings"
+	"time"
+)
+
+type noteService struct {
+	noteRepo   repository.NoteRepository
+	lessonRepo repository.LessonRepository
+	courseRepo repository.CourseRepository
+}
+
+func NewNoteService(
+	noteRepo repository.NoteRepository,
+	lessonRepo repository.LessonRepository,
+	courseRepo repository.CourseRepository,
+) NoteService {
+	return &noteService{
+		noteRepo:   noteRepo,
+		lessonRepo: lessonRepo,
+		courseRepo: courseRepo,
+	}
+}
+
+func (ns *noteService) GetLessonNotes(userId, lessonId uint) (*dto.GetLessonNotesResponse, error) {
+	// 1. Kiểm tra lesson có tồn tại không
+	if _, err := ns.findLesson(lessonId); err != nil {
+		return nil, err
+	}
+
+	// 2. Lấy ghi chú của user trên lesson
+	notes, err := ns.noteRepo.FindByUserAndLesson(userId, lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get lesson notes", utils.ErrCodeInternal)
+	}
+
+	noteItems := make([]dto.LessonNoteItem, len(notes))
+	for i := range notes {
+		noteItems[i] = toLessonNoteItem(&notes[i])
+	}
+
+	return &dto.GetLessonNotesResponse{
+		LessonId: lessonId,
+		Notes:    noteItems,
+	}, nil
+}
+
+func (ns *noteService) CreateNote(userId, lessonId uint, req *dto.CreateNoteRequest) (*dto.LessonNoteItem, error) {
+	// 1. Kiểm tra lesson có tồn tại không
+	lesson, err := ns.findLesson(lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Kiểm tra user đã enroll course chưa
+	isEnrolled, err := ns.lessonRepo.CheckUserEnrollment(userId, lesson.CourseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check enrollment", utils.ErrCodeInternal)
+	}
+
+	if !isEnrolled {
+		return nil, utils.NewError("You must enroll in this course to take notes", utils.ErrCodeForbidden)
+	}
+
+	// 3. Kiểm tra lesson đã được mở khóa chưa
+	lock, err := getLessonLock(ns.lessonRepo, userId, lesson.CourseId, lesson.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to resolve lesson schedule", utils.ErrCodeInternal)
+	}
+
+	if lock.isLocked {
+		return nil, utils.NewError(lockMessage(lock), utils.ErrCodeForbidden)
+	}
+
+	// 4. Validate timestamp
+	if err := validateNotePosition(lesson, req.Position); err != nil {
+		return nil, err
+	}
+
+	// 5. Tạo ghi chú
+	note := &models.LessonNote{
+		UserId:   userId,
+		CourseId: lesson.CourseId,
+		LessonId: lesson.Id,
+		Content:  strings.TrimSpace(req.Content),
+		Position: req.Position,
+	}
+
+	if note.Content == "" {
+		return nil, utils.NewError("Note content is required", utils.ErrCodeBadRequest)
+	}
+
+	if err := ns.noteRepo.Create(note); err != nil {
+		return nil, utils.WrapError(err, "Failed to create note", utils.ErrCodeInternal)
+	}
+
+	note.Lesson = *lesson
+
+	item := toLessonNoteItem(note)
+	return &item, nil
+}
+
+func (ns *noteService) UpdateNote(userId, noteId uint, req *dto.UpdateNoteRequest) (*dto.LessonNoteItem, error) {
+	// 1. Ghi chú phải thuộc về user
+	note, err := ns.findOwnNote(userId, noteId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Validate timestamp
+	if err := validateNotePosition(&note.Lesson, req.Position); err != nil {
+		return nil, err
+	}
+
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, utils.NewError("Note content is required", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Cập nhật nội dung và timestamp
+	updates := map[string]interface{}{
+		"content":    content,
+		"position":   req.Position,
+		"updated_at": time.Now(),
+	}
+
+	if err := ns.noteRepo.Update(note.Id, updates); err != nil {
+		return nil, utils.WrapError(err, "Failed to update note", utils.ErrCodeInternal)
+	}
+
+	note, err = ns.noteRepo.FindById(note.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get updated note", utils.ErrCodeInternal)
+	}
+
+	item := toLessonNoteItem(note)
+	return &item, nil
+}
+
+func (ns *noteService) DeleteNote(userId, noteId uint) (*dto.DeleteNoteResponse, error) {
+	// 1. Ghi chú phải thuộc về user
+	note, err := ns.findOwnNote(userId, noteId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Xóa ghi chú
+	if err := ns.noteRepo.Delete(note.Id); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete note", utils.ErrCodeInternal)
+	}
+
+	return &dto.DeleteNoteResponse{
+		Message: "Note deleted successfully",
+		Id:      note.Id,
+	}, nil
+}
+
+func (ns *noteService) SearchNotes(userId uint, req *dto.SearchNotesQueryRequest) (*dto.SearchNotesResponse, error) {
+	// 1. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	// 2. Prepare filters
+	filters := make(map[string]interface{})
+	if req.CourseId != nil {
+		filters["course_id"] = *req.CourseId
+	}
+	if req.BookmarksOnly {
+		filters["bookmarks_only"] = true
+	}
+
+	// 3. Full-text search trên ghi chú của user ở tất cả course
+	notes, total, err := ns.noteRepo.Search(userId, strings.TrimSpace(req.Q), offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to search notes", utils.ErrCodeInternal)
+	}
+
+	noteItems := make([]dto.LessonNoteItem, len(notes))
+	for i := range notes {
+		noteItems[i] = toLessonNoteItem(&notes[i])
+		noteItems[i].CourseTitle = notes[i].Course.Title
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.SearchNotesResponse{
+		Notes: noteItems,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (ns *noteService) ExportCourseNotes(userId, courseId uint) (*dto.NotesExport, error) {
+	// 1. Kiểm tra course có tồn tại không
+	course, err := ns.courseRepo.FindById(courseId)
+	if err != nil {
+		return nil, utils.NewError("Course not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Lấy ghi chú theo thứ tự lesson và timestamp
+	notes, err := ns.noteRepo.FindByUserAndCourse(userId, courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get course notes", utils.ErrCodeInternal)
+	}
+
+	// 3. Render Markdown - mỗi lesson là một section
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s - Notes\n\n", course.Title)
+	fmt.Fprintf(&b, "_Exported on %s_\n", time.Now().Format("2006-01-02 15:04"))
+
+	if len(notes) == 0 {
+		b.WriteString("\nNo notes yet.\n")
+	}
+
+	var currentLessonId uint
+	for _, note := range notes {
+		if note.LessonId != currentLessonId {
+			currentLessonId = note.LessonId
+			fmt.Fprintf(&b, "\n## %d. %s\n\n", note.Lesson.LessonOrder, note.Lesson.Title)
+		}
+
+		b.WriteString("- ")
+		if note.Position != nil {
+			fmt.Fprintf(&b, "**[%s]** ", formatNotePosition(*note.Position))
+		}
+		// Các dòng tiếp theo thụt lề để nằm trong cùng list item
+		b.WriteString(strings.ReplaceAll(note.Content, "\n", "\n  "))
+		b.WriteString("\n")
+	}
+
+	return &dto.NotesExport{
+		FileName: fmt.Sprintf("%s-notes.md", course.Slug),
+		Content:  []byte(b.String()),
+	}, nil
+}
+
+func (ns *noteService) findLesson(lessonId uint) (*models.Lesson, error) {
+	lessons, err := ns.lessonRepo.FindLessonByIds([]uint{lessonId})
+	if err != nil || len(lessons) == 0 {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+	return &lessons[0], nil
+}
+
+// findOwnNote chỉ trả về ghi chú của chính user (ghi chú là riêng tư)
+func (ns *noteService) findOwnNote(userId, noteId uint) (*models.LessonNote, error) {
+	note, err := ns.noteRepo.FindById(noteId)
+	if err != nil || note.UserId != userId {
+		return nil, utils.NewError("Note not found", utils.ErrCodeNotFound)
+	}
+	return note, nil
+}
+
+// validateNotePosition kiểm tra timestamp nằm trong độ dài video của lesson
+func validateNotePosition(lesson *models.Lesson, position *int) error {
+	if position != nil && lesson.VideoDuration > 0 && *position > lesson.VideoDuration {
+		return utils.NewError("Note position exceeds the lesson video duration", utils.ErrCodeBadRequest)
+	}
+	return nil
+}
+
+// formatNotePosition hiển thị timestamp dạng mm:ss hoặc h:mm:ss
+func formatNotePosition(seconds int) string {
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
+
+func toLessonNoteItem(note *models.LessonNote) dto.LessonNoteItem {
+	positionLabel := ""
+	if note.Position != nil {
+		positionLabel = formatNotePosition(*note.Position)
+	}
+
+	return dto.LessonNoteItem{
+		Id:            note.Id,
+		CourseId:      note.CourseId,
+		LessonId:      note.LessonId,
+		LessonTitle:   note.Lesson.Title,
+		LessonSlug:    note.Lesson.Slug,
+		Content:       note.Content,
+		Position:      note.Position,
+		PositionLabel: positionLabel,
+		CreatedAt:     note.CreatedAt,
+		UpdatedAt:     note.UpdatedAt,
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"encoding/json"
+	"lms/src/dto"
+	"lms/src/repository"
+	"log"
+	"sync"
+)
+
+// Số thông báo tối đa chờ gửi cho mỗi kết nối stream (kết nối chậm sẽ bị bỏ bớt)
+const notificationStreamBuffer = 16
+
+// NotificationHub đẩy thông báo mới tới các client đang kết nối stream.
+// Mỗi API instance LISTEN trên Postgres nên thông báo tạo ở instance nào cũng tới được mọi client.
+type NotificationHub struct {
+	notificationRepo repository.NotificationRepository
+	dsn              string
+
+	mu          sync.RWMutex
+	subscribers map[uint]map[chan dto.NotificationItem]struct{}
+}
+
+func NewNotificationHub(notificationRepo repository.NotificationRepository, dsn string) *NotificationHub {
+	return &NotificationHub{
+		notificationRepo: notificationRepo,
+		dsn:              dsn,
+		subscribers:      make(map[uint]map[chan dto.NotificationItem]struct{}),
+	}
+}
+
+// Subscribe đăng ký nhận thông báo của user; gọi hàm trả về để hủy đăng ký
+func (nh *NotificationHub) Subscribe(userId uint) (<-chan dto.NotificationItem, func()) {
+	ch := make(chan dto.NotificationItem, notificationStreamBuffer)
+
+	nh.mu.Lock()
+	if nh.subscribers[userId] == nil {
+		nh.subscribers[userId] = make(map[chan dto.NotificationItem]struct{})
+	}
+	nh.subscribers[userId][ch] = struct{}{}
+	nh.mu.Unlock()
+
+	return ch, func() {
+		nh.mu.Lock()
+		delete(nh.subscribers[userId], ch)
+		if len(nh.subscribers[userId]) == 0 {
+			delete(nh.subscribers, userId)
+		}
+		nh.mu.Unlock()
+	}
+}
+
+// StartWorkers chạy listener Postgres nhận thông báo mới từ mọi API instance
+func (nh *NotificationHub) StartWorkers() {
+	startPostgresListener(nh.dsn, repository.NotificationChannel, func(payload string) {
+		var signal repository.NotificationSignal
+		if err := json.Unmarshal([]byte(payload), &signal); err != nil {
+			log.Printf("Invalid notification payload: %v", err)
+			return
+		}
+
+		nh.dispatch(signal)
+	})
+}
+
+// dispatch gửi thông báo tới các kết nối của user trên instance này
+func (nh *NotificationHub) dispatch(signal repository.NotificationSignal) {
+	nh.mu.RLock()
+	hasSubscribers := len(nh.subscribers[signal.UserId]) > 0
+	nh.mu.RUnlock()
+
+	if !hasSubscribers {
+		return
+	}
+
+	notification, err := nh.notificationRepo.FindById(signal.Id)
+	if err != nil {
+		log.Printf("Failed to load notification %d: %v", signal.Id, err)
+		return
+	}
+	item := toNotificationItem(notification)
+
+	nh.mu.RLock()
+	defer nh.mu.RUnlock()
+
+	for ch := range nh.subscribers[signal.UserId] {
+		select {
+		case ch <- item:
+		default:
+			// Client không đọc kịp - bỏ qua, client sẽ lấy lại qua GET /notifications
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// Các loại thông báo in-app
+const (
+	notificationOrderPaid         = "order_paid"
+	notificationEnrollmentCreated = "enrollment_created"
+	notificationReviewCreated     = "review_created"
+	notificationCertificateIssued = "certificate_issued"
+	notificationDiscussionReply   = "discussion_reply"
+	notificationDiscussionAnswer  = "discussion_answer"
+	notificationCourseReviewed    = "course_reviewed"
+	notificationCourseRolledBack  = "course_rolled_back"
+
+	notificationSubscriptionBilling = "subscription_billing"
+)
+
+// notificationTypes liệt kê loại thông báo user có thể bật/tắt
+var notificationTypes = []struct {
+	name        string
+	description string
+}{
+	{notificationOrderPaid, "Payment confirmations for your orders"},
+	{notificationEnrollmentCreated, "New students enrolling in your courses"},
+	{notificationReviewCreated, "New reviews on your courses"},
+	{notificationCertificateIssued, "Certificates issued to you"},
+	{notificationDiscussionReply, "Replies to your Q&A questions"},
+	{notificationDiscussionAnswer, "Your Q&A replies marked as the answer"},
+	{notificationCourseReviewed, "Approval decisions on courses you submitted for review"},
+	{notificationCourseRolledBack, "Admins rolling back the content of your courses"},
+	{notificationSubscriptionBilling, "Failed renewals and expiry of your subscription"},
+}
+
+// Số thông báo tối đa gửi bù khi client stream kết nối lại
+const notificationCatchUpLimit = 50
+
+type notifier struct {
+	notificationRepo repository.NotificationRepository
+}
+
+func NewNotifier(notificationRepo repository.NotificationRepository) Notifier {
+	return &notifier{
+		notificationRepo: notificationRepo,
+	}
+}
+
+// Notify tạo thông báo in-app. Lỗi chỉ được log để không làm hỏng nghiệp vụ chính.
+func (n *notifier) Notify(userId uint, notificationType, title, message, link string) {
+	enabled, err := n.notificationRepo.IsEnabled(userId, notificationType)
+	if err != nil {
+		log.Printf("Failed to check notification preference (user %d, type %s): %v", userId, notificationType, err)
+		return
+	}
+	if !enabled {
+		return
+	}
+
+	notification := &models.Notification{
+		UserId:  userId,
+		Type:    notificationType,
+		Title:   title,
+		Message: message,
+		Link:    link,
+	}
+
+	if err := n.notificationRepo.Create(notification); err != nil {
+		log.Printf("Failed to create notification (user %d, type %s): %v", userId, notificationType, err)
+	}
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	hub              *NotificationHub
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository, hub *NotificationHub) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		hub:              hub,
+	}
+}
+
+func (ns *notificationService) GetNotifications(userId uint, req *dto.GetNotificationsQueryRequest) (*dto.GetNotificationsResponse, error) {
+	// 1. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	// 2. Prepare filters
+	filters := make(map[string]interface{})
+	if req.UnreadOnly {
+		filters["unread_only"] = true
+	}
+	if req.Type != "" {
+		filters["type"] = req.Type
+	}
+
+	// 3. Lấy thông báo và số chưa đọc
+	notifications, total, err := ns.notificationRepo.GetUserNotifications(userId, offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get notifications", utils.ErrCodeInternal)
+	}
+
+	unreadCount, err := ns.notificationRepo.CountUnread(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count unread notifications", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.NotificationItem, len(notifications))
+	for i := range notifications {
+		items[i] = toNotificationItem(&notifications[i])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetNotificationsResponse{
+		Notifications: items,
+		UnreadCount:   unreadCount,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (ns *notificationService) MarkRead(userId uint, req *dto.MarkNotificationsReadRequest) (*dto.MarkNotificationsReadResponse, error) {
+	updated, err := ns.notificationRepo.MarkRead(userId, req.Ids)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to mark notifications as read", utils.ErrCodeInternal)
+	}
+
+	unreadCount, err := ns.notificationRepo.CountUnread(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count unread notifications", utils.ErrCodeInternal)
+	}
+
+	return &dto.MarkNotificationsReadResponse{
+		Updated:     updated,
+		UnreadCount: unreadCount,
+	}, nil
+}
+
+func (ns *notificationService) DeleteNotification(userId, notificationId uint) (*dto.DeleteNotificationResponse, error) {
+	if err := ns.notificationRepo.Delete(userId, notificationId); err != nil {
+		return nil, utils.NewError("Notification not found", utils.ErrCodeNotFound)
+	}
+
+	return &dto.DeleteNotificationResponse{
+		Message: "Notification deleted successfully",
+		Id:      notificationId,
+	}, nil
+}
+
+func (ns *notificationService) GetPreferences(userId uint) (*dto.GetNotificationPreferencesResponse, error) {
+	preferences, err := ns.notificationRepo.GetPreferences(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get notification preferences", utils.ErrCodeInternal)
+	}
+
+	enabled := make(map[string]bool)
+	for _, preference := range preferences {
+		enabled[preference.Type] = preference.Enabled
+	}
+
+	// Loại chưa có lựa chọn thì mặc định bật
+	items := make([]dto.NotificationPreferenceItem, len(notificationTypes))
+	for i, notificationType := range notificationTypes {
+		value, ok := enabled[notificationType.name]
+		items[i] = dto.NotificationPreferenceItem{
+			Type:        notificationType.name,
+			Description: notificationType.description,
+			Enabled:     !ok || value,
+		}
+	}
+
+	return &dto.GetNotificationPreferencesResponse{Preferences: items}, nil
+}
+
+func (ns *notificationService) UpdatePreferences(userId uint, req *dto.UpdateNotificationPreferencesRequest) (*dto.GetNotificationPreferencesResponse, error) {
+	// 1. Validate loại thông báo
+	validTypes := make(map[string]bool)
+	names := make([]string, len(notificationTypes))
+	for i, notificationType := range notificationTypes {
+		validTypes[notificationType.name] = true
+		names[i] = notificationType.name
+	}
+
+	now := time.Now()
+	preferences := make([]models.NotificationPreference, 0, len(req.Preferences))
+	seen := make(map[string]bool)
+	for _, update := range req.Preferences {
+		if !validTypes[update.Type] {
+			return nil, utils.NewError(
+				fmt.Sprintf("Invalid notification type '%s', must be one of: %s", update.Type, strings.Join(names, ", ")),
+				utils.ErrCodeBadRequest,
+			)
+		}
+		if seen[update.Type] {
+			continue
+		}
+		seen[update.Type] = true
+
+		preferences = append(preferences, models.NotificationPreference{
+			UserId:    userId,
+			Type:      update.Type,
+			Enabled:   *update.Enabled,
+			UpdatedAt: now,
+		})
+	}
+
+	// 2. Lưu lựa chọn
+	if err := ns.notificationRepo.UpsertPreferences(preferences); err != nil {
+		return nil, utils.WrapError(err, "Failed to update notification preferences", utils.ErrCodeInternal)
+	}
+
+	return ns.GetPreferences(userId)
+}
+
+func (ns *notificationService) Subscribe(userId uint, lastEventId uint) ([]dto.NotificationItem, <-chan dto.NotificationItem, func(), error) {
+	// 1. Đăng ký trước khi lấy thông báo bù để không bỏ sót thông báo ở giữa
+	stream, unsubscribe := ns.hub.Subscribe(userId)
+
+	// 2. Client kết nối lại thì gửi bù các thông báo đã lỡ
+	var missed []dto.NotificationItem
+	if lastEventId > 0 {
+		notifications, err := ns.notificationRepo.GetNotificationsAfter(userId, lastEventId, notificationCatchUpLimit)
+		if err != nil {
+			unsubscribe()
+			return nil, nil, nil, utils.WrapError(err, "Failed to get missed notifications", utils.ErrCodeInternal)
+		}
+
+		missed = make([]dto.NotificationItem, len(notifications))
+		for i := range notifications {
+			missed[i] = toNotificationItem(&notifications[i])
+		}
+	}
+
+	return missed, stream, unsubscribe, nil
+}
+
+func toNotificationItem(notification *models.Notification) dto.NotificationItem {
+	return dto.NotificationItem{
+		Id:        notification.Id,
+		Type:      notification.Type,
+		Title:     notification.Title,
+		Message:   notification.Message,
+		Link:      notification.Link,
+		IsRead:    notification.ReadAt != nil,
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+}
@@ -17,6 +17,7 @@ type orderService struct {
 	courseRepo     repository.CourseRepository
 	couponRepo     repository.CouponRepository
 	enrollmentRepo repository.EnrollmentRepository
+	transactor     repository.Transactor
 }
 
 func NewOrderService(
@@ -24,13 +25,14 @@ func NewOrderService(
 	courseRepo repository.CourseRepository,
 	couponRepo repository.CouponRepository,
 	enrollmentRepo repository.EnrollmentRepository,
-
+	transactor repository.Transactor,
 ) OrderService {
 	return &orderService{
 		orderRepo:      orderRepo,
 		courseRepo:     courseRepo,
 		enrollmentRepo: enrollmentRepo,
 		couponRepo:     couponRepo,
+		transactor:     transactor,
 	}
 }
 
@@ -46,13 +48,22 @@ func (os *orderService) CreateOrder(userId uint, req *dto.CreateOrderRequest) (*
 		return nil, utils.NewError("Course is not available for purchase", utils.ErrCodeBadRequest)
 	}
 
-	// 3. Kiểm tra user đã mua course chưa
+	// 3. Kiểm tra user đã mua course chưa (enrollment qua subscription vẫn được mua để sở hữu vĩnh viễn)
 	if existingEnrollment, exists := os.enrollmentRepo.CheckEnrollment(userId, req.CourseId); exists {
-		if existingEnrollment.Status == "active" {
+		if existingEnrollment.Status == "active" && existingEnrollment.SubscriptionId == nil {
 			return nil, utils.NewError("You already own this course", utils.ErrCodeConflict)
 		}
 	}
 
+	// 3.1 Kiểm tra prerequisites (block => chặn, warn => chỉ cảnh báo)
+	_, missingPrerequisites, err := checkCoursePrerequisites(os.courseRepo, os.enrollmentRepo, userId, course)
+	if err != nil {
+		return nil, err
+	}
+	if err := enforcePrerequisites(course, missingPrerequisites); err != nil {
+		return nil, err
+	}
+
 	// 4. Kiểm tra đã có order pending chưa
 	existingOrder, err := os.orderRepo.FindPendingOrderByUserAndCourse(userId, req.CourseId)
 	if err == nil && existingOrder != nil {
@@ -150,6 +161,8 @@ func (os *orderService) CreateOrder(userId uint, req *dto.CreateOrderRequest) (*
 		PaymentStatus:  order.PaymentStatus,
 		CreatedAt:      order.CreatedAt,
 		Message:        message,
+
+		MissingPrerequisites: missingPrerequisites,
 	}, nil
 }
 
@@ -201,6 +214,8 @@ func (os *orderService) GetOrderHistory(userId uint, req *dto.GetOrderHistoryQue
 				PaymentStatus:   order.PaymentStatus,
 				PaidAt:          order.PaidAt,
 				CreatedAt:       order.CreatedAt,
+
+				BundlePurchaseId: order.BundlePurchaseId,
 			}
 			continue
 		}
@@ -217,6 +232,8 @@ func (os *orderService) GetOrderHistory(userId uint, req *dto.GetOrderHistoryQue
 			PaymentStatus:   order.PaymentStatus,
 			PaidAt:          order.PaidAt,
 			CreatedAt:       order.CreatedAt,
+
+			BundlePurchaseId: order.BundlePurchaseId,
 		}
 	}
 
@@ -244,30 +261,50 @@ func (os *orderService) completeOrder(order *models.Order, paymentMethod string)
 	order.PaymentMethod = paymentMethod
 	order.PaidAt = &now
 
-	// Update order
-	if err := os.orderRepo.Update(order); err != nil {
-		return utils.WrapError(err, "Failed to update order", utils.ErrCodeInternal)
-	}
+	// Update order, create enrollment và ghi event trong cùng transaction
+	// (coupon used count, thông báo... do event subscriber xử lý)
+	return os.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		if err := repos.Orders.Update(order); err != nil {
+			return utils.WrapError(err, "Failed to update order", utils.ErrCodeInternal)
+		}
 
-	// Create enrollment
-	enrollment := &models.Enrollment{
-		UserId:             order.UserId,
-		CourseId:           order.CourseId,
-		EnrolledAt:         now,
-		ProgressPercentage: 0,
-		Status:             "active",
-	}
+		enrollment := &models.Enrollment{
+			UserId:             order.UserId,
+			CourseId:           order.CourseId,
+			EnrolledAt:         now,
+			ProgressPercentage: 0,
+			Status:             "active",
+		}
 
-	if err := os.enrollmentRepo.Create(enrollment); err != nil {
-		return utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
-	}
+		if err := repos.Enrollments.Create(enrollment); err != nil {
+			return utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
+		}
 
-	// Update coupon used count nếu có
-	if order.CouponId != nil {
-		os.couponRepo.IncrementUsedCount(*order.CouponId)
+		if err := repos.Outbox.Append(newOrderPaidEvent(order), newEnrollmentCreatedEvent(enrollment, order.Id)); err != nil {
+			return utils.WrapError(err, "Failed to record order events", utils.ErrCodeInternal)
+		}
+		return nil
+	})
+}
+
+func newOrderPaidEvent(order *models.Order) dto.OrderPaidEvent {
+	paidAt := time.Now()
+	if order.PaidAt != nil {
+		paidAt = *order.PaidAt
 	}
 
-	return nil
+	return dto.OrderPaidEvent{
+		OrderId:       order.Id,
+		OrderCode:     order.OrderCode,
+		UserId:        order.UserId,
+		CourseId:      order.CourseId,
+		CouponId:      order.CouponId,
+		FinalPrice:    order.FinalPrice,
+		PaymentMethod: order.PaymentMethod,
+		PaidAt:        paidAt,
+
+		BundlePurchaseId: order.BundlePurchaseId,
+	}
 }
 
 func (os *orderService) GetOrderDetail(userId uint, orderId uint) (*dto.OrderDetailResponse, error) {
@@ -312,6 +349,8 @@ func (os *orderService) GetOrderDetail(userId uint, orderId uint) (*dto.OrderDet
 		PaidAt:          order.PaidAt,
 		CreatedAt:       order.CreatedAt,
 		UpdatedAt:       order.UpdatedAt,
+
+		BundlePurchaseId: order.BundlePurchaseId,
 	}, nil
 }
 
@@ -472,6 +511,8 @@ func (os *orderService) GetAllOrders(req *dto.GetAdminOrdersQueryRequest) (*dto.
 			PaidAt:          order.PaidAt,
 			CreatedAt:       order.CreatedAt,
 			UpdatedAt:       order.UpdatedAt,
+
+			BundlePurchaseId: order.BundlePurchaseId,
 		}
 	}
 
@@ -522,32 +563,63 @@ func (os *orderService) UpdateOrderStatus(orderId uint, req *dto.UpdateOrderStat
 		)
 	}
 
-	// 4. Handle status change to 'paid'
-	if req.Status == "paid" {
-		// Create enrollment if not exists
-		if _, exists := os.enrollmentRepo.CheckEnrollment(order.UserId, order.CourseId); !exists {
-			enrollment := &models.Enrollment{
-				UserId:             order.UserId,
-				CourseId:           order.CourseId,
-				EnrolledAt:         time.Now(),
-				ProgressPercentage: 0,
-				Status:             "active",
-			}
+	// 4. Update status; nếu chuyển sang 'paid' thì tạo enrollment (nếu chưa có) và ghi event trong cùng transaction
+	err = os.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		var events []dto.DomainEvent
 
-			if err := os.enrollmentRepo.Create(enrollment); err != nil {
-				return nil, utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
+		if req.Status == "paid" {
+			// Create enrollment if not exists; enrollment qua subscription trở thành enrollment đã mua
+			existing, exists := repos.Enrollments.CheckEnrollment(order.UserId, order.CourseId)
+			if exists && existing.SubscriptionId != nil {
+				if err := repos.Enrollments.UpdateEnrollmentProgress(existing.Id, map[string]interface{}{
+					"subscription_id": nil,
+				}); err != nil {
+					return utils.WrapError(err, "Failed to update enrollment", utils.ErrCodeInternal)
+				}
 			}
+			if !exists {
+				enrollment := &models.Enrollment{
+					UserId:             order.UserId,
+					CourseId:           order.CourseId,
+					EnrolledAt:         time.Now(),
+					ProgressPercentage: 0,
+					Status:             "active",
+				}
+
+				if err := repos.Enrollments.Create(enrollment); err != nil {
+					return utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
+				}
+				events = append(events, newEnrollmentCreatedEvent(enrollment, order.Id))
+			}
+
+			// Coupon used count được cập nhật bởi subscriber của OrderPaid
+			events = append(events, newOrderPaidEvent(order))
 		}
 
-		// Increment coupon used count
-		if order.CouponId != nil {
-			os.couponRepo.IncrementUsedCount(*order.CouponId)
+		if req.Status == "refunded" && order.PaymentStatus == "paid" {
+			events = append(events, dto.OrderRefundedEvent{
+				OrderId:    order.Id,
+				OrderCode:  order.OrderCode,
+				UserId:     order.UserId,
+				CourseId:   order.CourseId,
+				FinalPrice: order.FinalPrice,
+				Reason:     req.Reason,
+				RefundedAt: time.Now(),
+			})
 		}
-	}
 
-	// 5. Update order status
-	if err := os.orderRepo.UpdateOrderStatus(orderId, req.Status); err != nil {
-		return nil, utils.WrapError(err, "Failed to update order status", utils.ErrCodeInternal)
+		// 5. Update order status
+		if err := repos.Orders.UpdateOrderStatus(orderId, req.Status); err != nil {
+			return utils.WrapError(err, "Failed to update order status", utils.ErrCodeInternal)
+		}
+
+		if err := repos.Outbox.Append(events...); err != nil {
+			return utils.WrapError(err, "Failed to record order events", utils.ErrCodeInternal)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// 6. Get updated order
@@ -0,0 +1,1313 @@
+package service
+
+import (
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Hạn thanh toán của hóa đơn mua seat
+const organizationInvoiceDueDays = 30
+
+type organizationService struct {
+	organizationRepo repository.OrganizationRepository
+	userRepo         repository.UserRepository
+	courseRepo       repository.CourseRepository
+	lessonRepo       repository.LessonRepository
+	progressRepo     repository.ProgressRepository
+	transactor       repository.Transactor
+	catalogSeatPrice float64
+}
+
+func NewOrganizationService(
+	organizationRepo repository.OrganizationRepository,
+	userRepo repository.UserRepository,
+	courseRepo repository.CourseRepository,
+	lessonRepo repository.LessonRepository,
+	progressRepo repository.ProgressRepository,
+	transactor repository.Transactor,
+) OrganizationService {
+	// Giá mỗi seat catalog (truy cập mọi course đã publish)
+	catalogSeatPrice, err := strconv.ParseFloat(utils.GetEnv("ORG_CATALOG_SEAT_PRICE", "199"), 64)
+	if err != nil || catalogSeatPrice < 0 {
+		catalogSeatPrice = 199
+	}
+
+	return &organizationService{
+		organizationRepo: organizationRepo,
+		userRepo:         userRepo,
+		courseRepo:       courseRepo,
+		lessonRepo:       lessonRepo,
+		progressRepo:     progressRepo,
+		transactor:       transactor,
+		catalogSeatPrice: catalogSeatPrice,
+	}
+}
+
+// ---------------- Organizations ----------------
+func (os *organizationService) CreateOrganization(userId uint, req *dto.CreateOrganizationRequest) (*dto.OrganizationItem, error) {
+	// 1. Tạo slug duy nhất từ tên
+	name := strings.TrimSpace(req.Name)
+	organization := &models.Organization{
+		Name:           name,
+		Slug:           utils.GenerateUniqueSlug(utils.GenerateSlug(name), os.organizationRepo.SlugExists),
+		BillingName:    strings.TrimSpace(req.BillingName),
+		BillingEmail:   strings.TrimSpace(req.BillingEmail),
+		BillingAddress: strings.TrimSpace(req.BillingAddress),
+		TaxId:          strings.TrimSpace(req.TaxId),
+		Status:         "active",
+		CreatedBy:      userId,
+	}
+
+	// 2. Tạo organization, người tạo trở thành admin
+	if err := os.organizationRepo.CreateOrganization(organization, userId); err != nil {
+		return nil, utils.WrapError(err, "Failed to create organization", utils.ErrCodeInternal)
+	}
+
+	item := toOrganizationItem(organization, 1)
+	item.MyRole = "admin"
+	return &item, nil
+}
+
+func (os *organizationService) GetMyOrganizations(userId uint) (*dto.GetMyOrganizationsResponse, error) {
+	memberships, err := os.organizationRepo.GetUserMemberships(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get organizations", utils.ErrCodeInternal)
+	}
+
+	organizationIds := make([]uint, len(memberships))
+	for i := range memberships {
+		organizationIds[i] = memberships[i].OrganizationId
+	}
+	memberCounts, err := os.organizationRepo.CountMembers(organizationIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count members", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.OrganizationItem, len(memberships))
+	for i := range memberships {
+		items[i] = toOrganizationItem(&memberships[i].Organization, memberCounts[memberships[i].OrganizationId])
+		items[i].MyRole = memberships[i].Role
+	}
+
+	return &dto.GetMyOrganizationsResponse{
+		Organizations: items,
+	}, nil
+}
+
+func (os *organizationService) GetOrganization(userId, organizationId uint) (*dto.OrganizationItem, error) {
+	organization, member, err := os.requireMember(userId, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return os.buildOrganizationItem(organization, member.Role)
+}
+
+func (os *organizationService) UpdateOrganization(userId, organizationId uint, req *dto.UpdateOrganizationRequest) (*dto.OrganizationItem, error) {
+	// 1. Chỉ admin của organization được sửa thông tin
+	organization, _, err := os.requireAdmin(userId, organizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Chuẩn bị dữ liệu cập nhật (slug giữ nguyên để không làm hỏng link đã chia sẻ)
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		organization.Name = strings.TrimSpace(*req.Name)
+		updates["name"] = organization.Name
+	}
+	if req.BillingName != nil {
+		organization.BillingName = strings.TrimSpace(*req.BillingName)
+		updates["billing_name"] = organization.BillingName
+	}
+	if req.BillingEmail != nil {
+		organization.BillingEmail = strings.TrimSpace(*req.BillingEmail)
+		updates["billing_email"] = organization.BillingEmail
+	}
+	if req.BillingAddress != nil {
+		organization.BillingAddress = strings.TrimSpace(*req.BillingAddress)
+		updates["billing_address"] = organization.BillingAddress
+	}
+	if req.TaxId != nil {
+		organization.TaxId = strings.TrimSpace(*req.TaxId)
+		updates["tax_id"] = organization.TaxId
+	}
+
+	// 3. Lưu
+	if len(updates) > 0 {
+		if err := os.organizationRepo.UpdateOrganization(organizationId, updates); err != nil {
+			return nil, utils.WrapError(err, "Failed to update organization", utils.ErrCodeInternal)
+		}
+	}
+
+	return os.buildOrganizationItem(organization, "admin")
+}
+
+// ---------------- Members ----------------
+func (os *organizationService) GetMembers(userId, organizationId uint, req *dto.GetOrganizationMembersQueryRequest) (*dto.GetOrganizationMembersResponse, error) {
+	// 1. Kiểm tra quyền
+	if _, _, err := os.requireAdmin(userId, organizationId); err != nil {
+		return nil, err
+	}
+
+	// 2. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	// 3. Prepare filters
+	filters := make(map[string]interface{})
+	if req.Role != "" {
+		filters["role"] = req.Role
+	}
+	if req.Search != "" {
+		filters["search"] = utils.NormalizeString(req.Search)
+	}
+
+	// 4. Lấy danh sách thành viên
+	members, total, err := os.organizationRepo.GetMembers(organizationId, offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get members", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.OrganizationMemberItem, len(members))
+	for i := range members {
+		items[i] = toOrganizationMemberItem(&members[i])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetOrganizationMembersResponse{
+		Members: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (os *organizationService) AddMember(userId, organizationId uint, req *dto.AddOrganizationMemberRequest) (*dto.OrganizationMemberItem, error) {
+	// 1. Kiểm tra quyền
+	if _, _, err := os.requireAdmin(userId, organizationId); err != nil {
+		return nil, err
+	}
+
+	// 2. Thành viên phải là tài khoản đã đăng ký
+	user, exists := os.userRepo.FindByEmail(strings.TrimSpace(req.Email))
+	if !exists {
+		return nil, utils.NewError("No user registered with this email", utils.ErrCodeNotFound)
+	}
+
+	existing, err := os.organizationRepo.FindMember(organizationId, user.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check membership", utils.ErrCodeInternal)
+	}
+	if existing != nil {
+		return nil, utils.NewError("User is already a member of this organization", utils.ErrCodeConflict)
+	}
+
+	// 3. Thêm thành viên
+	role := req.Role
+	if role == "" {
+		role = "member"
+	}
+	member := &models.OrganizationMember{
+		OrganizationId: organizationId,
+		UserId:         user.Id,
+		Role:           role,
+	}
+	if err := os.organizationRepo.AddMember(member); err != nil {
+		return nil, utils.WrapError(err, "Failed to add member", utils.ErrCodeInternal)
+	}
+
+	member.User = *user
+	item := toOrganizationMemberItem(member)
+	return &item, nil
+}
+
+func (os *organizationService) UpdateMember(userId, organizationId, memberUserId uint, req *dto.UpdateOrganizationMemberRequest) (*dto.OrganizationMemberItem, error) {
+	// 1. Kiểm tra quyền
+	if _, _, err := os.requireAdmin(userId, organizationId); err != nil {
+		return nil, err
+	}
+
+	member, err := os.findMember(organizationId, memberUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Không được hạ quyền admin cuối cùng
+	if member.Role == "admin" && req.Role != "admin" {
+		if err := os.ensureAnotherAdmin(organizationId); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Cập nhật role
+	if err := os.organizationRepo.UpdateMemberRole(organizationId, memberUserId, req.Role); err != nil {
+		return nil, utils.WrapError(err, "Failed to update member", utils.ErrCodeInternal)
+	}
+
+	member.Role = req.Role
+	user, err := os.userRepo.FindById(memberUserId)
+	if err == nil {
+		member.User = *user
+	}
+	item := toOrganizationMemberItem(member)
+	return &item, nil
+}
+
+func (os *organizationService) RemoveMember(userId, organizationId, memberUserId uint) (*dto.RemoveOrganizationMemberResponse, error) {
+	// 1. Kiểm tra quyền
+	if _, _, err := os.requireAdmin(userId, organizationId); err != nil {
+		return nil, err
+	}
+
+	member, err := os.findMember(organizationId, memberUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Không được xóa admin cuối cùng
+	if member.Role == "admin" {
+		if err := os.ensureAnotherAdmin(organizationId); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. Thu hồi mọi seat của thành viên rồi xóa khỏi organization
+	reclaimed := 0
+	err = os.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		assignments, err := repos.Organizations.GetActiveAssignments(map[string]interface{}{
+			"organization_id": organizationId,
+			"user_id":         memberUserId,
+		})
+		if err != nil {
+			return utils.WrapError(err, "Failed to get seat assignments", utils.ErrCodeInternal)
+		}
+		if err := reclaimSeatAssignments(repos, assignments); err != nil {
+			return err
+		}
+		reclaimed = len(assignments)
+
+		if err := repos.Organizations.RemoveMember(organizationId, memberUserId); err != nil {
+			return utils.WrapError(err, "Failed to remove member", utils.ErrCodeInternal)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RemoveOrganizationMemberResponse{
+		Message:        "Member removed from organization",
+		UserId:         memberUserId,
+		ReclaimedSeats: reclaimed,
+	}, nil
+}
+
+// ---------------- Seat licenses ----------------
+func (os *organizationService) GetLicenses(userId, organizationId uint) (*dto.GetOrganizationLicensesResponse, error) {
+	if _, _, err := os.requireAdmin(userId, organizationId); err != nil {
+		return nil, err
+	}
+
+	licenses, err := os.organizationRepo.GetLicenses(organizationId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get licenses", utils.ErrCodeInternal)
+	}
+
+	licenseIds := make([]uint, len(licenses))
+	for i := range licenses {
+		licenseIds[i] = licenses[i].Id
+	}
+	seatsUsed, err := os.organizationRepo.CountSeatsUsed(licenseIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count seats", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.OrganizationLicenseItem, len(licenses))
+	for i := range licenses {
+		items[i] = toOrganizationLicenseItem(&licenses[i], seatsUsed[licenses[i].Id])
+	}
+
+	return &dto.GetOrganizationLicensesResponse{
+		Licenses: items,
+	}, nil
+}
+
+func (os *organizationService) PurchaseSeats(userId, organizationId uint, req *dto.PurchaseSeatsRequest) (*dto.PurchaseSeatsResponse, error) {
+	// 1. Kiểm tra quyền và trạng thái organization
+	organization, _, err := os.requireAdmin(userId, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	if organization.Status != "active" {
+		return nil, utils.NewError("Organization is suspended", utils.ErrCodeForbidden)
+	}
+
+	// 2. Xác định đơn giá theo phạm vi license
+	license := &models.OrganizationLicense{
+		OrganizationId: organizationId,
+		Scope:          req.Scope,
+		Seats:          req.Seats,
+		Status:         "pending",
+		PurchasedBy:    userId,
+	}
+	var description string
+	switch req.Scope {
+	case "course":
+		if req.CourseId == nil {
+			return nil, utils.NewError("course_id is required for a course license", utils.ErrCodeBadRequest)
+		}
+		course, err := os.courseRepo.FindById(*req.CourseId)
+		if err != nil {
+			return nil, utils.NewError("Course not found", utils.ErrCodeNotFound)
+		}
+		if course.Status != "published" {
+			return nil, utils.NewError("Course is not available for purchase", utils.ErrCodeBadRequest)
+		}
+
+		license.UnitPrice = course.Price
+		if course.DiscountPrice != nil && *course.DiscountPrice < license.UnitPrice {
+			license.UnitPrice = *course.DiscountPrice
+		}
+		license.CourseId = &course.Id
+		license.Course = course
+		description = fmt.Sprintf("%d seat(s) for course \"%s\"", req.Seats, course.Title)
+	default:
+		license.UnitPrice = os.catalogSeatPrice
+		description = fmt.Sprintf("%d catalog seat(s) for all published courses", req.Seats)
+	}
+	license.TotalPrice = math.Round(license.UnitPrice*float64(req.Seats)*100) / 100
+
+	// 3. Xuất hóa đơn, chụp lại thông tin thanh toán tại thời điểm mua
+	now := time.Now()
+	billingName := organization.BillingName
+	if billingName == "" {
+		billingName = organization.Name
+	}
+	invoice := &models.OrganizationInvoice{
+		OrganizationId: organizationId,
+		InvoiceNumber:  fmt.Sprintf("INV-%s-%s", now.Format("200601"), strings.ToUpper(uuid.New().String()[:8])),
+		Description:    description,
+		Quantity:       req.Seats,
+		UnitPrice:      license.UnitPrice,
+		Total:          license.TotalPrice,
+		Status:         "open",
+		BillingName:    billingName,
+		BillingEmail:   organization.BillingEmail,
+		BillingAddress: organization.BillingAddress,
+		TaxId:          organization.TaxId,
+		IssuedAt:       now,
+		DueAt:          now.AddDate(0, 0, organizationInvoiceDueDays),
+	}
+
+	// 4. License miễn phí được kích hoạt ngay, không cần thanh toán
+	message := "Seats reserved. Pay the invoice to activate the license"
+	err = os.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		if license.TotalPrice == 0 {
+			license.Status = "active"
+			license.ActivatedAt = &now
+			invoice.Status = "paid"
+			invoice.PaidAt = &now
+		}
+
+		if err := repos.Organizations.CreateLicense(license, invoice); err != nil {
+			return utils.WrapError(err, "Failed to create license", utils.ErrCodeInternal)
+		}
+
+		if invoice.Status == "paid" {
+			message = "License activated"
+			if err := repos.Outbox.Append(newOrganizationInvoicePaidEvent(invoice, license)); err != nil {
+				return utils.WrapError(err, "Failed to record invoice event", utils.ErrCodeInternal)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PurchaseSeatsResponse{
+		License: toOrganizationLicenseItem(license, 0),
+		Invoice: toOrganizationInvoiceItem(invoice, ""),
+		Message: message,
+	}, nil
+}
+
+// CancelLicense hủy license chưa thanh toán và void hóa đơn của nó
+func (os *organizationService) CancelLicense(userId, organizationId, licenseId uint) (*dto.CancelLicenseResponse, error) {
+	// 1. Kiểm tra quyền
+	if _, _, err := os.requireAdmin(userId, organizationId); err != nil {
+		return nil, err
+	}
+
+	license, err := os.findLicense(organizationId, licenseId)
+	if err != nil {
+		return nil, err
+	}
+	if license.Status != "pending" {
+		return nil, utils.NewError("Only unpaid licenses can be cancelled", utils.ErrCodeBadRequest)
+	}
+
+	invoice, err := os.organizationRepo.FindInvoiceByLicense(licenseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get invoice", utils.ErrCodeInternal)
+	}
+
+	// 2. Void hóa đơn và hủy license cùng lúc (hóa đơn vừa được thanh toán thì dừng lại)
+	err = os.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		if invoice != nil {
+			voided, err := repos.Organizations.UpdateInvoiceStatus(invoice.Id, "open", map[string]interface{}{
+				"status": "void",
+			})
+			if err != nil {
+				return utils.WrapError(err, "Failed to void invoice", utils.ErrCodeInternal)
+			}
+			if !voided {
+				return utils.NewError("Invoice has already been processed", utils.ErrCodeConflict)
+			}
+		}
+
+		if err := repos.Organizations.UpdateLicense(licenseId, map[string]interface{}{
+			"status": "cancelled",
+		}); err != nil {
+			return utils.WrapError(err, "Failed to cancel license", utils.ErrCodeInternal)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.CancelLicenseResponse{
+		Message:   "License cancelled",
+		LicenseId: licenseId,
+	}, nil
+}
+
+// ---------------- Seat assignments ----------------
+func (os *organizationService) GetSeatAssignments(userId, organizationId, licenseId uint) (*dto.GetSeatAssignmentsResponse, error) {
+	if _, _, err := os.requireAdmin(userId, organizationId); err != nil {
+		return nil, err
+	}
+	if _, err := os.findLicense(organizationId, licenseId); err != nil {
+		return nil, err
+	}
+
+	assignments, err := os.organizationRepo.GetActiveAssignments(map[string]interface{}{
+		"license_id": licenseId,
+	})
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get seat assignments", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.SeatAssignmentItem, len(assignments))
+	for i := range assignments {
+		items[i] = toSeatAssignmentItem(&assignments[i])
+	}
+
+	return &dto.GetSeatAssignmentsResponse{
+		Assignments: items,
+	}, nil
+}
+
+// AssignSeat gán seat cho thành viên và ghi danh họ vào course (không tạo Order, org đã trả tiền qua hóa đơn)
+func (os *organizationService) AssignSeat(userId, organizationId, licenseId uint, req *dto.AssignSeatRequest) (*dto.SeatAssignmentItem, error) {
+	// 1. Kiểm tra quyền và trạng thái organization
+	organization, _, err := os.requireAdmin(userId, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	if organization.Status != "active" {
+		return nil, utils.NewError("Organization is suspended", utils.ErrCodeForbidden)
+	}
+
+	license, err := os.findLicense(organizationId, licenseId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Người nhận seat phải là thành viên
+	member, err := os.findMember(organizationId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Xác định course: license course dùng course của license, license catalog cần course_id
+	courseId := req.CourseId
+	if license.Scope == "course" {
+		if courseId != nil && *courseId != *license.CourseId {
+			return nil, utils.NewError("This license only covers its own course", utils.ErrCodeBadRequest)
+		}
+		courseId = license.CourseId
+	} else if courseId == nil {
+		return nil, utils.NewError("course_id is required for a catalog license", utils.ErrCodeBadRequest)
+	}
+	course, err := os.courseRepo.FindById(*courseId)
+	if err != nil {
+		return nil, utils.NewError("Course not found", utils.ErrCodeNotFound)
+	}
+	if course.Status != "published" {
+		return nil, utils.NewError("Course is not available for enrollment", utils.ErrCodeBadRequest)
+	}
+
+	// 4. Khóa license, kiểm tra seat còn trống rồi ghi danh trong cùng transaction
+	now := time.Now()
+	assignment := &models.OrganizationSeatAssignment{
+		OrganizationId: organizationId,
+		LicenseId:      licenseId,
+		UserId:         req.UserId,
+		CourseId:       course.Id,
+		AssignedBy:     userId,
+		AssignedAt:     now,
+	}
+	err = os.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		locked, err := repos.Organizations.LockLicense(licenseId)
+		if err != nil {
+			return utils.WrapError(err, "Failed to lock license", utils.ErrCodeInternal)
+		}
+		if locked.Status != "active" {
+			return utils.NewError("License is not active. Pay its invoice first", utils.ErrCodeBadRequest)
+		}
+
+		// 4.1 Người đã giữ seat của license (catalog) được gán thêm course mà không tốn seat mới
+		holder, err := repos.Organizations.IsSeatHolder(licenseId, req.UserId)
+		if err != nil {
+			return utils.WrapError(err, "Failed to check seat", utils.ErrCodeInternal)
+		}
+		if !holder {
+			seatsUsed, err := repos.Organizations.CountSeatsUsed([]uint{licenseId})
+			if err != nil {
+				return utils.WrapError(err, "Failed to count seats", utils.ErrCodeInternal)
+			}
+			if seatsUsed[licenseId] >= locked.Seats {
+				return utils.NewError("No seats available on this license", utils.ErrCodeConflict)
+			}
+		}
+
+		// 4.2 Ghi danh: kích hoạt lại enrollment đã bị thu hồi, nhận lại enrollment qua subscription
+		// (seat thay subscription nên enrollment không mất khi subscription hết hạn) hoặc tạo enrollment mới
+		existing, exists := repos.Enrollments.CheckEnrollment(req.UserId, course.Id)
+		switch {
+		case exists && existing.Status != "dropped" && existing.SubscriptionId == nil:
+			return utils.NewError("User is already enrolled in this course", utils.ErrCodeConflict)
+		case exists:
+			updates := map[string]interface{}{
+				"subscription_id": nil,
+			}
+			if existing.Status == "dropped" {
+				updates["status"] = "active"
+			}
+			if err := repos.Enrollments.UpdateEnrollmentProgress(existing.Id, updates); err != nil {
+				return utils.WrapError(err, "Failed to reactivate enrollment", utils.ErrCodeInternal)
+			}
+			assignment.EnrollmentId = existing.Id
+		default:
+			enrollment := &models.Enrollment{
+				UserId:     req.UserId,
+				CourseId:   course.Id,
+				EnrolledAt: now,
+				Status:     "active",
+			}
+			if err := repos.Enrollments.Create(enrollment); err != nil {
+				return utils.WrapError(err, "Failed to create enrollment", utils.ErrCodeInternal)
+			}
+			if err := repos.Outbox.Append(newEnrollmentCreatedEvent(enrollment, 0)); err != nil {
+				return utils.WrapError(err, "Failed to record enrollment event", utils.ErrCodeInternal)
+			}
+			assignment.EnrollmentId = enrollment.Id
+		}
+
+		// 4.3 Lưu seat assignment
+		if err := repos.Organizations.CreateAssignment(assignment); err != nil {
+			return utils.WrapError(err, "Failed to assign seat", utils.ErrCodeInternal)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if user, err := os.userRepo.FindById(member.UserId); err == nil {
+		assignment.User = *user
+	}
+	assignment.Course = *course
+	item := toSeatAssignmentItem(assignment)
+	return &item, nil
+}
+
+// ReclaimSeat thu hồi seat của một thành viên trên license, các enrollment tương ứng chuyển sang dropped
+func (os *organizationService) ReclaimSeat(userId, organizationId, licenseId, memberUserId uint) (*dto.ReclaimSeatResponse, error) {
+	// 1. Kiểm tra quyền
+	if _, _, err := os.requireAdmin(userId, organizationId); err != nil {
+		return nil, err
+	}
+	if _, err := os.findLicense(organizationId, licenseId); err != nil {
+		return nil, err
+	}
+
+	// 2. Thu hồi trong transaction
+	reclaimed := 0
+	err := os.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		assignments, err := repos.Organizations.GetActiveAssignments(map[string]interface{}{
+			"license_id": licenseId,
+			"user_id":    memberUserId,
+		})
+		if err != nil {
+			return utils.WrapError(err, "Failed to get seat assignments", utils.ErrCodeInternal)
+		}
+		if len(assignments) == 0 {
+			return utils.NewError("User does not hold a seat on this license", utils.ErrCodeNotFound)
+		}
+
+		reclaimed = len(assignments)
+		return reclaimSeatAssignments(repos, assignments)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ReclaimSeatResponse{
+		Message:          "Seat reclaimed",
+		LicenseId:        licenseId,
+		UserId:           memberUserId,
+		ReclaimedCourses: reclaimed,
+	}, nil
+}
+
+// reclaimSeatAssignments đóng các seat assignment và drop enrollment do seat tạo ra.
+// Enrollment đã completed được giữ nguyên để học viên không mất kết quả đã đạt.
+func reclaimSeatAssignments(repos *repository.TxRepositories, assignments []models.OrganizationSeatAssignment) error {
+	assignmentIds := make([]uint, len(assignments))
+	for i := range assignments {
+		assignmentIds[i] = assignments[i].Id
+
+		enrollment, exists := repos.Enrollments.CheckEnrollment(assignments[i].UserId, assignments[i].CourseId)
+		if !exists || enrollment.Id != assignments[i].EnrollmentId || enrollment.Status != "active" {
+			continue
+		}
+		if err := repos.Enrollments.UpdateEnrollmentProgress(enrollment.Id, map[string]interface{}{
+			"status": "dropped",
+		}); err != nil {
+			return utils.WrapError(err, "Failed to drop enrollment", utils.ErrCodeInternal)
+		}
+	}
+
+	if err := repos.Organizations.ReclaimAssignments(assignmentIds, time.Now()); err != nil {
+		return utils.WrapError(err, "Failed to reclaim seats", utils.ErrCodeInternal)
+	}
+	return nil
+}
+
+// ---------------- Invoices ----------------
+func (os *organizationService) GetInvoices(userId, organizationId uint, req *dto.GetOrganizationInvoicesQueryRequest) (*dto.GetOrganizationInvoicesResponse, error) {
+	organization, _, err := os.requireAdmin(userId, organizationId)
+	if err != nil {
+		return nil, err
+	}
+	return os.listInvoices(organization, req)
+}
+
+func (os *organizationService) GetInvoice(userId, organizationId, invoiceId uint) (*dto.OrganizationInvoiceItem, error) {
+	organization, _, err := os.requireAdmin(userId, organizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := os.findInvoice(organizationId, invoiceId)
+	if err != nil {
+		return nil, err
+	}
+
+	item := toOrganizationInvoiceItem(invoice, organization.Name)
+	return &item, nil
+}
+
+func (os *organizationService) PayInvoice(userId, organizationId, invoiceId uint, req *dto.PayInvoiceRequest) (*dto.PayInvoiceResponse, error) {
+	// 1. Kiểm tra quyền
+	organization, _, err := os.requireAdmin(userId, organizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Tìm hóa đơn
+	invoice, err := os.findInvoice(organizationId, invoiceId)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status != "open" {
+		return nil, utils.NewError("Invoice has already been processed", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Simulate payment processing
+	// TODO: Integrate with real payment gateway
+	time.Sleep(1 * time.Second)
+
+	// 4. Ghi nhận thanh toán và kích hoạt license
+	if err := os.settleInvoice(invoice, req.PaymentMethod); err != nil {
+		return nil, err
+	}
+
+	return &dto.PayInvoiceResponse{
+		Invoice: toOrganizationInvoiceItem(invoice, organization.Name),
+		Message: "Payment successful! The license is now active",
+	}, nil
+}
+
+// settleInvoice đánh dấu hóa đơn đã thanh toán, kích hoạt license và ghi event trong cùng transaction
+func (os *organizationService) settleInvoice(invoice *models.OrganizationInvoice, paymentMethod string) error {
+	license, err := os.findLicense(invoice.OrganizationId, invoice.LicenseId)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = os.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		// 1. Chỉ hóa đơn còn open mới được thanh toán (chặn thanh toán hai lần)
+		paid, err := repos.Organizations.UpdateInvoiceStatus(invoice.Id, "open", map[string]interface{}{
+			"status":         "paid",
+			"payment_method": paymentMethod,
+			"paid_at":        now,
+		})
+		if err != nil {
+			return utils.WrapError(err, "Failed to update invoice", utils.ErrCodeInternal)
+		}
+		if !paid {
+			return utils.NewError("Invoice has already been processed", utils.ErrCodeConflict)
+		}
+
+		// 2. Kích hoạt license
+		if err := repos.Organizations.UpdateLicense(license.Id, map[string]interface{}{
+			"status":       "active",
+			"activated_at": now,
+		}); err != nil {
+			return utils.WrapError(err, "Failed to activate license", utils.ErrCodeInternal)
+		}
+
+		invoice.Status = "paid"
+		invoice.PaymentMethod = paymentMethod
+		invoice.PaidAt = &now
+		if err := repos.Outbox.Append(newOrganizationInvoicePaidEvent(invoice, license)); err != nil {
+			return utils.WrapError(err, "Failed to record invoice event", utils.ErrCodeInternal)
+		}
+		return nil
+	})
+	return err
+}
+
+// ---------------- Team progress ----------------
+// GetTeamProgress tổng hợp tiến độ của từng seat dựa trên progress của lesson đã publish
+func (os *organizationService) GetTeamProgress(userId, organizationId uint, req *dto.GetTeamProgressQueryRequest) (*dto.GetTeamProgressResponse, error) {
+	// 1. Kiểm tra quyền
+	if _, _, err := os.requireAdmin(userId, organizationId); err != nil {
+		return nil, err
+	}
+
+	// 2. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+
+	// 3. Lấy các seat đang được dùng
+	filters := map[string]interface{}{
+		"organization_id": organizationId,
+	}
+	if req.CourseId > 0 {
+		filters["course_id"] = req.CourseId
+	}
+	if req.UserId > 0 {
+		filters["user_id"] = req.UserId
+	}
+	assignments, err := os.organizationRepo.GetActiveAssignments(filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get seat assignments", utils.ErrCodeInternal)
+	}
+
+	// 4. Tính tiến độ từng seat (danh sách lesson được cache theo course)
+	courseLessons := make(map[uint]map[uint]bool)
+	items := make([]dto.TeamProgressItem, len(assignments))
+	summary := dto.TeamProgressSummary{
+		Assignments: len(assignments),
+	}
+	totalPercentage := 0.0
+	for i := range assignments {
+		assignment := &assignments[i]
+
+		lessonIds, ok := courseLessons[assignment.CourseId]
+		if !ok {
+			lessons, err := os.lessonRepo.GetCourseLessons(assignment.CourseId)
+			if err != nil {
+				return nil, utils.WrapError(err, "Failed to get course lessons", utils.ErrCodeInternal)
+			}
+			lessonIds = make(map[uint]bool, len(lessons))
+			for _, lesson := range lessons {
+				lessonIds[lesson.Id] = true
+			}
+			courseLessons[assignment.CourseId] = lessonIds
+		}
+
+		progresses, err := os.progressRepo.GetCourseProgress(assignment.UserId, assignment.CourseId)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to get course progress", utils.ErrCodeInternal)
+		}
+
+		items[i] = toTeamProgressItem(assignment, lessonIds, progresses)
+		switch items[i].Status {
+		case "completed":
+			summary.Completed++
+		case "in_progress":
+			summary.InProgress++
+		default:
+			summary.NotStarted++
+		}
+		totalPercentage += items[i].ProgressPercentage
+	}
+	if len(items) > 0 {
+		summary.AverageProgress = math.Round(totalPercentage/float64(len(items))*100) / 100
+	}
+
+	// 5. Thống kê thành viên và seat
+	memberCounts, err := os.organizationRepo.CountMembers([]uint{organizationId})
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count members", utils.ErrCodeInternal)
+	}
+	summary.Members = memberCounts[organizationId]
+
+	licenses, err := os.organizationRepo.GetLicenses(organizationId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get licenses", utils.ErrCodeInternal)
+	}
+	var activeLicenseIds []uint
+	for i := range licenses {
+		if licenses[i].Status == "active" {
+			summary.SeatsPurchased += licenses[i].Seats
+			activeLicenseIds = append(activeLicenseIds, licenses[i].Id)
+		}
+	}
+	seatsUsed, err := os.organizationRepo.CountSeatsUsed(activeLicenseIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count seats", utils.ErrCodeInternal)
+	}
+	for _, used := range seatsUsed {
+		summary.SeatsUsed += used
+	}
+
+	// 6. Phân trang
+	total := len(items)
+	start := min((page-1)*limit, total)
+	end := min(start+limit, total)
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetTeamProgressResponse{
+		Summary:  summary,
+		Progress: items[start:end],
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+// ---------------- Admin ----------------
+func (os *organizationService) AdminGetOrganizations(req *dto.GetOrganizationsQueryRequest) (*dto.GetOrganizationsResponse, error) {
+	// 1. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	// 2. Prepare filters
+	filters := make(map[string]interface{})
+	if req.Status != "" {
+		filters["status"] = req.Status
+	}
+	if req.Search != "" {
+		filters["search"] = utils.NormalizeString(req.Search)
+	}
+
+	// 3. Lấy danh sách organization
+	organizations, total, err := os.organizationRepo.GetOrganizations(offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get organizations", utils.ErrCodeInternal)
+	}
+
+	organizationIds := make([]uint, len(organizations))
+	for i := range organizations {
+		organizationIds[i] = organizations[i].Id
+	}
+	memberCounts, err := os.organizationRepo.CountMembers(organizationIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count members", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.OrganizationItem, len(organizations))
+	for i := range organizations {
+		items[i] = toOrganizationItem(&organizations[i], memberCounts[organizations[i].Id])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetOrganizationsResponse{
+		Organizations: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+// AdminUpdateOrganizationStatus: organization bị suspended không mua hay gán seat được, seat đã gán vẫn giữ nguyên
+func (os *organizationService) AdminUpdateOrganizationStatus(organizationId uint, req *dto.UpdateOrganizationStatusRequest) (*dto.OrganizationItem, error) {
+	organization, err := os.organizationRepo.FindById(organizationId)
+	if err != nil {
+		return nil, utils.NewError("Organization not found", utils.ErrCodeNotFound)
+	}
+
+	if err := os.organizationRepo.UpdateOrganization(organizationId, map[string]interface{}{
+		"status": req.Status,
+	}); err != nil {
+		return nil, utils.WrapError(err, "Failed to update organization status", utils.ErrCodeInternal)
+	}
+
+	organization.Status = req.Status
+	return os.buildOrganizationItem(organization, "")
+}
+
+func (os *organizationService) AdminGetInvoices(organizationId uint, req *dto.GetOrganizationInvoicesQueryRequest) (*dto.GetOrganizationInvoicesResponse, error) {
+	organization, err := os.organizationRepo.FindById(organizationId)
+	if err != nil {
+		return nil, utils.NewError("Organization not found", utils.ErrCodeNotFound)
+	}
+	return os.listInvoices(organization, req)
+}
+
+// AdminMarkInvoicePaid ghi nhận thanh toán ngoài hệ thống (ví dụ chuyển khoản theo số hóa đơn)
+func (os *organizationService) AdminMarkInvoicePaid(organizationId, invoiceId uint, req *dto.MarkInvoicePaidRequest) (*dto.PayInvoiceResponse, error) {
+	organization, err := os.organizationRepo.FindById(organizationId)
+	if err != nil {
+		return nil, utils.NewError("Organization not found", utils.ErrCodeNotFound)
+	}
+
+	invoice, err := os.findInvoice(organizationId, invoiceId)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status != "open" {
+		return nil, utils.NewError("Invoice has already been processed", utils.ErrCodeBadRequest)
+	}
+
+	if err := os.settleInvoice(invoice, req.PaymentMethod); err != nil {
+		return nil, err
+	}
+
+	return &dto.PayInvoiceResponse{
+		Invoice: toOrganizationInvoiceItem(invoice, organization.Name),
+		Message: "Invoice marked as paid. The license is now active",
+	}, nil
+}
+
+// ---------------- Helpers ----------------
+// requireMember trả về organization và membership của user, lỗi nếu user không thuộc organization
+func (os *organizationService) requireMember(userId, organizationId uint) (*models.Organization, *models.OrganizationMember, error) {
+	organization, err := os.organizationRepo.FindById(organizationId)
+	if err != nil {
+		return nil, nil, utils.NewError("Organization not found", utils.ErrCodeNotFound)
+	}
+
+	member, err := os.organizationRepo.FindMember(organizationId, userId)
+	if err != nil {
+		return nil, nil, utils.WrapError(err, "Failed to check membership", utils.ErrCodeInternal)
+	}
+	if member == nil {
+		return nil, nil, utils.NewError("You are not a member of this organization", utils.ErrCodeForbidden)
+	}
+	return organization, member, nil
+}
+
+func (os *organizationService) requireAdmin(userId, organizationId uint) (*models.Organization, *models.OrganizationMember, error) {
+	organization, member, err := os.requireMember(userId, organizationId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if member.Role != "admin" {
+		return nil, nil, utils.NewError("Only organization admins can perform this action", utils.ErrCodeForbidden)
+	}
+	return organization, member, nil
+}
+
+func (os *organizationService) findMember(organizationId, userId uint) (*models.OrganizationMember, error) {
+	member, err := os.organizationRepo.FindMember(organizationId, userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get member", utils.ErrCodeInternal)
+	}
+	if member == nil {
+		return nil, utils.NewError("User is not a member of this organization", utils.ErrCodeNotFound)
+	}
+	return member, nil
+}
+
+func (os *organizationService) ensureAnotherAdmin(organizationId uint) error {
+	admins, err := os.organizationRepo.CountAdmins(organizationId)
+	if err != nil {
+		return utils.WrapError(err, "Failed to count admins", utils.ErrCodeInternal)
+	}
+	if admins <= 1 {
+		return utils.NewError("An organization must keep at least one admin", utils.ErrCodeBadRequest)
+	}
+	return nil
+}
+
+func (os *organizationService) findLicense(organizationId, licenseId uint) (*models.OrganizationLicense, error) {
+	license, err := os.organizationRepo.FindLicense(organizationId, licenseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get license", utils.ErrCodeInternal)
+	}
+	if license == nil {
+		return nil, utils.NewError("License not found", utils.ErrCodeNotFound)
+	}
+	return license, nil
+}
+
+func (os *organizationService) findInvoice(organizationId, invoiceId uint) (*models.OrganizationInvoice, error) {
+	invoice, err := os.organizationRepo.FindInvoice(invoiceId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get invoice", utils.ErrCodeInternal)
+	}
+	if invoice == nil || invoice.OrganizationId != organizationId {
+		return nil, utils.NewError("Invoice not found", utils.ErrCodeNotFound)
+	}
+	return invoice, nil
+}
+
+func (os *organizationService) listInvoices(organization *models.Organization, req *dto.GetOrganizationInvoicesQueryRequest) (*dto.GetOrganizationInvoicesResponse, error) {
+	// 1. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	// 2. Prepare filters
+	filters := make(map[string]interface{})
+	if req.Status != "" {
+		filters["status"] = req.Status
+	}
+
+	// 3. Lấy danh sách hóa đơn
+	invoices, total, err := os.organizationRepo.GetInvoices(organization.Id, offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get invoices", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.OrganizationInvoiceItem, len(invoices))
+	for i := range invoices {
+		items[i] = toOrganizationInvoiceItem(&invoices[i], organization.Name)
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetOrganizationInvoicesResponse{
+		Invoices: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (os *organizationService) buildOrganizationItem(organization *models.Organization, role string) (*dto.OrganizationItem, error) {
+	memberCounts, err := os.organizationRepo.CountMembers([]uint{organization.Id})
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count members", utils.ErrCodeInternal)
+	}
+
+	item := toOrganizationItem(organization, memberCounts[organization.Id])
+	item.MyRole = role
+	return &item, nil
+}
+
+func toOrganizationItem(organization *models.Organization, memberCount int) dto.OrganizationItem {
+	return dto.OrganizationItem{
+		Id:             organization.Id,
+		Name:           organization.Name,
+		Slug:           organization.Slug,
+		BillingName:    organization.BillingName,
+		BillingEmail:   organization.BillingEmail,
+		BillingAddress: organization.BillingAddress,
+		TaxId:          organization.TaxId,
+		Status:         organization.Status,
+		MemberCount:    memberCount,
+		CreatedBy:      organization.CreatedBy,
+		CreatedAt:      organization.CreatedAt,
+	}
+}
+
+func toOrganizationMemberItem(member *models.OrganizationMember) dto.OrganizationMemberItem {
+	return dto.OrganizationMemberItem{
+		UserId:   member.UserId,
+		Username: member.User.Username,
+		FullName: member.User.FullName,
+		Email:    member.User.Email,
+		Role:     member.Role,
+		JoinedAt: member.CreatedAt,
+	}
+}
+
+func toOrganizationLicenseItem(license *models.OrganizationLicense, seatsUsed int) dto.OrganizationLicenseItem {
+	item := dto.OrganizationLicenseItem{
+		Id:             license.Id,
+		Scope:          license.Scope,
+		CourseId:       license.CourseId,
+		Seats:          license.Seats,
+		SeatsUsed:      seatsUsed,
+		SeatsAvailable: max(license.Seats-seatsUsed, 0),
+		UnitPrice:      license.UnitPrice,
+		TotalPrice:     license.TotalPrice,
+		Status:         license.Status,
+		ActivatedAt:    license.ActivatedAt,
+		CreatedAt:      license.CreatedAt,
+	}
+	if license.Course != nil {
+		item.CourseTitle = license.Course.Title
+	}
+	return item
+}
+
+func toOrganizationInvoiceItem(invoice *models.OrganizationInvoice, organizationName string) dto.OrganizationInvoiceItem {
+	return dto.OrganizationInvoiceItem{
+		Id:               invoice.Id,
+		OrganizationId:   invoice.OrganizationId,
+		OrganizationName: organizationName,
+		LicenseId:        invoice.LicenseId,
+		InvoiceNumber:    invoice.InvoiceNumber,
+		Description:      invoice.Description,
+		Quantity:         invoice.Quantity,
+		UnitPrice:        invoice.UnitPrice,
+		Total:            invoice.Total,
+		Status:           invoice.Status,
+		BillingName:      invoice.BillingName,
+		BillingEmail:     invoice.BillingEmail,
+		BillingAddress:   invoice.BillingAddress,
+		TaxId:            invoice.TaxId,
+		PaymentMethod:    invoice.PaymentMethod,
+		IssuedAt:         invoice.IssuedAt,
+		DueAt:            invoice.DueAt,
+		PaidAt:           invoice.PaidAt,
+	}
+}
+
+func toSeatAssignmentItem(assignment *models.OrganizationSeatAssignment) dto.SeatAssignmentItem {
+	return dto.SeatAssignmentItem{
+		Id:           assignment.Id,
+		LicenseId:    assignment.LicenseId,
+		UserId:       assignment.UserId,
+		FullName:     assignment.User.FullName,
+		CourseId:     assignment.CourseId,
+		CourseTitle:  assignment.Course.Title,
+		EnrollmentId: assignment.EnrollmentId,
+		AssignedAt:   assignment.AssignedAt,
+	}
+}
+
+// toTeamProgressItem chỉ tính progress của lesson còn publish, giống cách tính tiến độ course
+func toTeamProgressItem(assignment *models.OrganizationSeatAssignment, lessonIds map[uint]bool, progresses []models.Progress) dto.TeamProgressItem {
+	item := dto.TeamProgressItem{
+		UserId:       assignment.UserId,
+		FullName:     assignment.User.FullName,
+		Email:        assignment.User.Email,
+		CourseId:     assignment.CourseId,
+		CourseTitle:  assignment.Course.Title,
+		TotalLessons: len(lessonIds),
+		AssignedAt:   assignment.AssignedAt,
+	}
+
+	started := false
+	for i := range progresses {
+		if !lessonIds[progresses[i].LessonId] {
+			continue
+		}
+		started = true
+		if progresses[i].IsCompleted {
+			item.CompletedLessons++
+		}
+		if item.LastActivityAt == nil || progresses[i].UpdatedAt.After(*item.LastActivityAt) {
+			updatedAt := progresses[i].UpdatedAt
+			item.LastActivityAt = &updatedAt
+		}
+	}
+
+	if item.TotalLessons > 0 {
+		item.ProgressPercentage = math.Round(float64(item.CompletedLessons)/float64(item.TotalLessons)*10000) / 100
+	}
+	switch {
+	case item.TotalLessons > 0 && item.CompletedLessons == item.TotalLessons:
+		item.Status = "completed"
+	case started:
+		item.Status = "in_progress"
+	default:
+		item.Status = "not_started"
+	}
+	return item
+}
+
+func newOrganizationInvoicePaidEvent(invoice *models.OrganizationInvoice, license *models.OrganizationLicense) dto.OrganizationInvoicePaidEvent {
+	paidAt := time.Now()
+	if invoice.PaidAt != nil {
+		paidAt = *invoice.PaidAt
+	}
+	return dto.OrganizationInvoicePaidEvent{
+		InvoiceId:      invoice.Id,
+		InvoiceNumber:  invoice.InvoiceNumber,
+		OrganizationId: invoice.OrganizationId,
+		LicenseId:      license.Id,
+		Scope:          license.Scope,
+		CourseId:       license.CourseId,
+		Seats:          license.Seats,
+		Total:          invoice.Total,
+		PaymentMethod:  invoice.PaymentMethod,
+		PaidAt:         paidAt,
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentGateway thu tiền bằng phương thức thanh toán đã lưu của user, không cần user thao tác.
+// Dùng cho các khoản thu định kỳ (gia hạn subscription) và mua bundle.
+type PaymentGateway interface {
+	// Charge trả về mã giao dịch, hoặc lỗi khi giao dịch bị từ chối
+	Charge(paymentMethod string, amount float64, reference string) (string, error)
+	// Refund hoàn lại toàn bộ giao dịch đã thu (dùng khi thu tiền xong nhưng không ghi nhận được đơn hàng)
+	Refund(transactionId string, amount float64) error
+}
+
+type simulatedPaymentGateway struct{}
+
+func NewSimulatedPaymentGateway() PaymentGateway {
+	return &simulatedPaymentGateway{}
+}
+
+func (g *simulatedPaymentGateway) Charge(paymentMethod string, amount float64, reference string) (string, error) {
+	if paymentMethod == "" {
+		return "", fmt.Errorf("no payment method on file")
+	}
+
+	// Simulate payment processing
+	// TODO: Integrate with real payment gateway
+	time.Sleep(1 * time.Second)
+
+	return fmt.Sprintf("TXN-%s", strings.ToUpper(uuid.New().String()[:12])), nil
+}
+
+func (g *simulatedPaymentGateway) Refund(transactionId string, amount float64) error {
+	if transactionId == "" {
+		return fmt.Errorf("no transaction to refund")
+	}
+
+	// Simulate refund processing
+	// TODO: Integrate with real payment gateway
+	time.Sleep(1 * time.Second)
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// startPostgresListener LISTEN trên channel và gọi onNotify với payload của mỗi NOTIFY.
+// Chạy trong goroutine riêng, tự kết nối lại (backoff tối đa 30s) khi mất kết nối.
+func startPostgresListener(dsn, channel string, onNotify func(payload string)) {
+	go func() {
+		backoff := time.Second
+		for {
+			started := time.Now()
+			if err := listenPostgres(dsn, channel, onNotify); err != nil {
+				log.Printf("Postgres listener %s stopped: %v", channel, err)
+			}
+
+			// Kết nối ổn định một thời gian thì reset backoff
+			if time.Since(started) > time.Minute {
+				backoff = time.Second
+			}
+			time.Sleep(backoff)
+			if backoff < 30*time.Second {
+				backoff *= 2
+			}
+		}
+	}()
+}
+
+func listenPostgres(dsn, channel string, onNotify func(payload string)) error {
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		onNotify(notification.Payload)
+	}
+}
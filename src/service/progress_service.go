@@ -14,6 +14,7 @@ type progressService struct {
 	enrollmentRepo repository.EnrollmentRepository
 	courseRepo     repository.CourseRepository
 	lessonRepo     repository.LessonRepository
+	transactor     repository.Transactor
 }
 
 func NewProgressService(
@@ -21,12 +22,14 @@ func NewProgressService(
 	enrollmentRepo repository.EnrollmentRepository,
 	courseRepo repository.CourseRepository,
 	lessonRepo repository.LessonRepository,
+	transactor repository.Transactor,
 ) ProgressService {
 	return &progressService{
 		progressRepo:   progressRepo,
 		enrollmentRepo: enrollmentRepo,
 		courseRepo:     courseRepo,
 		lessonRepo:     lessonRepo,
+		transactor:     transactor,
 	}
 }
 
@@ -160,18 +163,42 @@ func (ps *progressService) CompleteLesson(userId, lessonId uint, req *dto.Comple
 	}
 	lesson := lessons[0]
 
+	// Lesson SCORM chỉ hoàn thành khi package báo completed/passed qua runtime (xem scormService.Commit)
+	if lesson.LessonType == "scorm" {
+		return nil, utils.NewError("SCORM lessons are completed by the SCORM package", utils.ErrCodeBadRequest)
+	}
+
+	return ps.completeLesson(userId, &lesson, req)
+}
+
+// completeLesson đánh dấu lesson hoàn thành, dùng chung cho API và SCORM runtime
+func (ps *progressService) completeLesson(userId uint, lesson *models.Lesson, req *dto.CompleteLessonRequest) (*dto.CompleteLessonResponse, error) {
+	lessonId := lesson.Id
+
 	// 2. Kiểm tra user đã enroll course chưa
 	_, isEnrolled := ps.enrollmentRepo.CheckEnrollment(userId, lesson.CourseId)
 	if !isEnrolled {
 		return nil, utils.NewError("You are not enrolled in this course", utils.ErrCodeForbidden)
 	}
 
+	// Không cho phép hoàn thành lesson đang bị khóa
+	lock, err := getLessonLock(ps.lessonRepo, userId, lesson.CourseId, lessonId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to resolve lesson schedule", utils.ErrCodeInternal)
+	}
+	if lock.isLocked {
+		return nil, utils.NewError(lockMessage(lock), utils.ErrCodeForbidden)
+	}
+
 	// 3. Lấy hoặc tạo progress record
 	progress, err := ps.progressRepo.GetLessonProgress(userId, lessonId)
 	if err != nil {
 		return nil, utils.WrapError(err, "Failed to get lesson progress", utils.ErrCodeInternal)
 	}
 
+	// Chỉ phát event LessonCompleted ở lần hoàn thành đầu tiên
+	firstCompletion := progress == nil || !progress.IsCompleted
+
 	// Nếu chưa có progress, tạo mới
 	if progress == nil {
 		now := time.Now()
@@ -193,8 +220,22 @@ func (ps *progressService) CompleteLesson(userId, lessonId uint, req *dto.Comple
 		progress.LastPosition = lesson.VideoDuration
 	}
 
-	// 4. Lưu progress
-	if err := ps.progressRepo.UpdateProgress(progress); err != nil {
+	// 4. Lưu progress cùng event LessonCompleted
+	err = ps.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		if err := repos.Progress.UpdateProgress(progress); err != nil {
+			return err
+		}
+		if !firstCompletion {
+			return nil
+		}
+		return repos.Outbox.Append(dto.LessonCompletedEvent{
+			UserId:      userId,
+			CourseId:    lesson.CourseId,
+			LessonId:    lessonId,
+			CompletedAt: *progress.CompletedAt,
+		})
+	})
+	if err != nil {
 		return nil, utils.WrapError(err, "Failed to update progress", utils.ErrCodeInternal)
 	}
 
@@ -239,6 +280,35 @@ func (ps *progressService) UpdateLessonPosition(userId, lessonId uint, req *dto.
 		return nil, utils.WrapError(err, "Failed to get lesson progress", utils.ErrCodeInternal)
 	}
 
+	// Lần cập nhật đầu tiên là lúc học viên bắt đầu lesson (event LessonLaunched);
+	// sau đó chỉ phát LessonProgressed khi vượt qua một mốc để không tạo event cho mỗi lần lưu vị trí
+	now := time.Now()
+	var events []dto.DomainEvent
+	previousMilestone := 0
+	if progress == nil {
+		events = append(events, dto.LessonLaunchedEvent{
+			UserId:     userId,
+			CourseId:   lesson.CourseId,
+			LessonId:   lessonId,
+			LaunchedAt: now,
+		})
+	} else if !progress.IsCompleted {
+		previousMilestone = progressMilestone(progress.WatchDuration, lesson.VideoDuration)
+	}
+
+	if progress == nil || !progress.IsCompleted {
+		if milestone := progressMilestone(req.WatchDuration, lesson.VideoDuration); milestone > previousMilestone {
+			events = append(events, dto.LessonProgressedEvent{
+				UserId:        userId,
+				CourseId:      lesson.CourseId,
+				LessonId:      lessonId,
+				Progress:      milestone,
+				WatchDuration: req.WatchDuration,
+				ProgressedAt:  now,
+			})
+		}
+	}
+
 	if progress == nil {
 		// Tạo mới progress
 		progress = &models.Progress{
@@ -255,8 +325,14 @@ func (ps *progressService) UpdateLessonPosition(userId, lessonId uint, req *dto.
 		progress.LastPosition = req.LastPosition
 	}
 
-	// 5. Lưu progress
-	if err := ps.progressRepo.UpdateProgress(progress); err != nil {
+	// 5. Lưu progress cùng các event
+	err = ps.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		if err := repos.Progress.UpdateProgress(progress); err != nil {
+			return err
+		}
+		return repos.Outbox.Append(events...)
+	})
+	if err != nil {
 		return nil, utils.WrapError(err, "Failed to update progress", utils.ErrCodeInternal)
 	}
 
@@ -268,6 +344,20 @@ func (ps *progressService) UpdateLessonPosition(userId, lessonId uint, req *dto.
 	}, nil
 }
 
+// progressMilestone trả về mốc 25/50/75 (%) cao nhất mà thời lượng đã xem đạt được.
+// Mốc 100% được thể hiện bằng event LessonCompleted nên không tính ở đây.
+func progressMilestone(watchDuration, videoDuration int) int {
+	if videoDuration <= 0 || watchDuration <= 0 {
+		return 0
+	}
+
+	milestone := watchDuration * 100 / videoDuration / 25 * 25
+	if milestone > 75 {
+		milestone = 75
+	}
+	return milestone
+}
+
 // Tiếp theo hàm updateEnrollmentProgress
 func (ps *progressService) updateEnrollmentProgress(userId, courseId uint) error {
 	// Đếm số lessons đã hoàn thành
@@ -303,11 +393,27 @@ func (ps *progressService) updateEnrollmentProgress(userId, courseId uint) error
 	}
 
 	// Nếu hoàn thành 100%, cập nhật status
+	var events []dto.DomainEvent
 	if progressPercentage >= 100 {
 		updates["status"] = "completed"
 		now := time.Now()
 		updates["completed_at"] = now
+
+		// Chỉ phát event EnrollmentCompleted ở lần hoàn thành đầu tiên
+		if enrollment.Status != "completed" {
+			events = append(events, dto.EnrollmentCompletedEvent{
+				EnrollmentId: enrollment.Id,
+				UserId:       userId,
+				CourseId:     courseId,
+				CompletedAt:  now,
+			})
+		}
 	}
 
-	return ps.enrollmentRepo.UpdateEnrollmentProgress(enrollment.Id, updates)
+	return ps.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		if err := repos.Enrollments.UpdateEnrollmentProgress(enrollment.Id, updates); err != nil {
+			return err
+		}
+		return repos.Outbox.Append(events...)
+	})
 }
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"time"
+)
+
+type quizService struct {
+	quizRepo       repository.QuizRepository
+	instructorRepo repository.InstructorRepository
+	lessonRepo     repository.LessonRepository
+	transactor     repository.Transactor
+}
+
+func NewQuizService(
+	quizRepo repository.QuizRepository,
+	instructorRepo repository.InstructorRepository,
+	lessonRepo repository.LessonRepository,
+	transactor repository.Transactor,
+) QuizService {
+	return &quizService{
+		quizRepo:       quizRepo,
+		instructorRepo: instructorRepo,
+		lessonRepo:     lessonRepo,
+		transactor:     transactor,
+	}
+}
+
+func (qs *quizService) CreateQuiz(instructorId, courseId, lessonId uint, req *dto.CreateQuizRequest) (*dto.QuizDetail, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	_, err := qs.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	if err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Kiểm tra lesson có thuộc course không
+	_, err = qs.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId)
+	if err != nil {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	// 3. Mỗi lesson chỉ có một quiz
+	if existing, err := qs.quizRepo.FindByLesson(lessonId); err == nil && existing != nil {
+		return nil, utils.NewError("This lesson already has a quiz", utils.ErrCodeConflict)
+	}
+
+	// 4. Validate và build câu hỏi
+	questions := make([]models.QuizQuestion, len(req.Questions))
+	for i, q := range req.Questions {
+		if q.CorrectOption >= len(q.Options) {
+			return nil, utils.NewError(
+				fmt.Sprintf("Question %d: correct_option is out of range", i+1),
+				utils.ErrCodeBadRequest,
+			)
+		}
+
+		options, err := json.Marshal(q.Options)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to encode quiz options", utils.ErrCodeInternal)
+		}
+
+		questions[i] = models.QuizQuestion{
+			Question:      q.Question,
+			Options:       string(options),
+			CorrectOption: q.CorrectOption,
+			QuestionOrder: i + 1,
+		}
+	}
+
+	// 5. Lưu quiz
+	quiz := &models.Quiz{
+		CourseId:     courseId,
+		LessonId:     lessonId,
+		Title:        req.Title,
+		Description:  req.Description,
+		PassingScore: req.PassingScore,
+		Questions:    questions,
+	}
+
+	if err := qs.quizRepo.Create(quiz); err != nil {
+		return nil, utils.WrapError(err, "Failed to create quiz", utils.ErrCodeInternal)
+	}
+
+	return toQuizDetail(quiz), nil
+}
+
+func (qs *quizService) DeleteQuiz(instructorId, courseId, lessonId uint) (*dto.DeleteQuizResponse, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	_, err := qs.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId)
+	if err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Tìm quiz của lesson
+	quiz, err := qs.quizRepo.FindByLesson(lessonId)
+	if err != nil || quiz.CourseId != courseId {
+		return nil, utils.NewError("Quiz not found", utils.ErrCodeNotFound)
+	}
+
+	// 3. Xóa quiz (soft delete)
+	if err := qs.quizRepo.Delete(quiz.Id); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete quiz", utils.ErrCodeInternal)
+	}
+
+	return &dto.DeleteQuizResponse{
+		Message: "Quiz deleted successfully",
+		Id:      quiz.Id,
+	}, nil
+}
+
+func (qs *quizService) GetLessonQuiz(userId, lessonId uint) (*dto.QuizDetail, error) {
+	// 1. Tìm quiz của lesson
+	quiz, err := qs.quizRepo.FindByLesson(lessonId)
+	if err != nil {
+		return nil, utils.NewError("Quiz not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Kiểm tra quyền truy cập
+	if err := qs.checkQuizAccess(userId, quiz); err != nil {
+		return nil, err
+	}
+
+	// 3. Lấy kết quả làm bài của user
+	detail := toQuizDetail(quiz)
+
+	attemptCount, err := qs.quizRepo.CountAttempts(userId, quiz.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get quiz attempts", utils.ErrCodeInternal)
+	}
+	detail.AttemptCount = attemptCount
+
+	bestAttempt, err := qs.quizRepo.GetBestAttempt(userId, quiz.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get quiz attempts", utils.ErrCodeInternal)
+	}
+	if bestAttempt != nil {
+		detail.BestScore = &bestAttempt.Score
+		detail.IsPassed = bestAttempt.IsPassed
+	}
+
+	return detail, nil
+}
+
+func (qs *quizService) SubmitQuiz(userId, quizId uint, req *dto.SubmitQuizRequest) (*dto.SubmitQuizResponse, error) {
+	// 1. Tìm quiz
+	quiz, err := qs.quizRepo.FindById(quizId)
+	if err != nil {
+		return nil, utils.NewError("Quiz not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Kiểm tra quyền truy cập
+	if err := qs.checkQuizAccess(userId, quiz); err != nil {
+		return nil, err
+	}
+
+	// 3. Chấm điểm
+	answerMap := make(map[uint]int, len(req.Answers))
+	for _, answer := range req.Answers {
+		answerMap[answer.QuestionId] = answer.SelectedOption
+	}
+
+	correctCount := 0
+	for _, question := range quiz.Questions {
+		if selected, ok := answerMap[question.Id]; ok && selected == question.CorrectOption {
+			correctCount++
+		}
+	}
+
+	score := 0.0
+	if len(quiz.Questions) > 0 {
+		score = float64(correctCount) / float64(len(quiz.Questions)) * 100
+	}
+	isPassed := score >= float64(quiz.PassingScore)
+
+	// 4. Lưu attempt cùng event QuizSubmitted
+	attempt := &models.QuizAttempt{
+		UserId:      userId,
+		QuizId:      quiz.Id,
+		Score:       score,
+		IsPassed:    isPassed,
+		SubmittedAt: time.Now(),
+	}
+
+	err = qs.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		if err := repos.Quizzes.CreateAttempt(attempt); err != nil {
+			return err
+		}
+		return repos.Outbox.Append(dto.QuizSubmittedEvent{
+			AttemptId:    attempt.Id,
+			UserId:       userId,
+			QuizId:       quiz.Id,
+			LessonId:     quiz.LessonId,
+			CourseId:     quiz.CourseId,
+			Score:        score,
+			PassingScore: quiz.PassingScore,
+			IsPassed:     isPassed,
+			SubmittedAt:  attempt.SubmittedAt,
+		})
+	})
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to save quiz attempt", utils.ErrCodeInternal)
+	}
+
+	message := "Quiz passed! Lessons requiring this quiz are now unlocked"
+	if !isPassed {
+		message = fmt.Sprintf("You need at least %d%% to pass this quiz. Please try again", quiz.PassingScore)
+	}
+
+	return &dto.SubmitQuizResponse{
+		AttemptId:      attempt.Id,
+		QuizId:         quiz.Id,
+		Score:          score,
+		CorrectCount:   correctCount,
+		TotalQuestions: len(quiz.Questions),
+		PassingScore:   quiz.PassingScore,
+		IsPassed:       isPassed,
+		SubmittedAt:    attempt.SubmittedAt,
+		Message:        message,
+	}, nil
+}
+
+// checkQuizAccess kiểm tra user đã enroll và lesson chứa quiz đã được mở khóa
+func (qs *quizService) checkQuizAccess(userId uint, quiz *models.Quiz) error {
+	isEnrolled, err := qs.lessonRepo.CheckUserEnrollment(userId, quiz.CourseId)
+	if err != nil {
+		return utils.WrapError(err, "Failed to check enrollment", utils.ErrCodeInternal)
+	}
+
+	if !isEnrolled {
+		return utils.NewError("You must enroll in this course to take this quiz", utils.ErrCodeForbidden)
+	}
+
+	lock, err := getLessonLock(qs.lessonRepo, userId, quiz.CourseId, quiz.LessonId)
+	if err != nil {
+		return utils.WrapError(err, "Failed to resolve lesson schedule", utils.ErrCodeInternal)
+	}
+
+	if lock.isLocked {
+		return utils.NewError(lockMessage(lock), utils.ErrCodeForbidden)
+	}
+
+	return nil
+}
+
+func toQuizDetail(quiz *models.Quiz) *dto.QuizDetail {
+	questions := make([]dto.QuizQuestionItem, len(quiz.Questions))
+	for i, question := range quiz.Questions {
+		var options []string
+		_ = json.Unmarshal([]byte(question.Options), &options)
+
+		questions[i] = dto.QuizQuestionItem{
+			Id:            question.Id,
+			Question:      question.Question,
+			Options:       options,
+			QuestionOrder: question.QuestionOrder,
+		}
+	}
+
+	return &dto.QuizDetail{
+		Id:             quiz.Id,
+		CourseId:       quiz.CourseId,
+		LessonId:       quiz.LessonId,
+		Title:          quiz.Title,
+		Description:    quiz.Description,
+		PassingScore:   quiz.PassingScore,
+		TotalQuestions: len(questions),
+		Questions:      questions,
+		CreatedAt:      quiz.CreatedAt,
+	}
+}
@@ -6,19 +6,40 @@ import (
 	"lms/src/repository"
 	"lms/src/utils"
 	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	reviewStatusApproved = "approved"
+	reviewStatusPending  = "pending"
+	reviewStatusHidden   = "hidden"
 )
 
 type reviewService struct {
-	reviewRepo     repository.ReviewRepository
-	courseRepo     repository.CourseRepository
-	enrollmentRepo repository.EnrollmentRepository
+	reviewRepo      repository.ReviewRepository
+	courseRepo      repository.CourseRepository
+	enrollmentRepo  repository.EnrollmentRepository
+	transactor      repository.Transactor
+	contentFilter   *utils.ContentFilter
+	reportThreshold int
 }
 
-func NewReviewService(reviewRepo repository.ReviewRepository, courseRepo repository.CourseRepository, enrollmentRepo repository.EnrollmentRepository) ReviewService {
+func NewReviewService(reviewRepo repository.ReviewRepository, courseRepo repository.CourseRepository, enrollmentRepo repository.EnrollmentRepository, transactor repository.Transactor, contentFilter *utils.ContentFilter) ReviewService {
+	// Số report đang mở để tự động giữ review lại chờ duyệt
+	reportThreshold, err := strconv.Atoi(utils.GetEnv("REVIEW_REPORT_THRESHOLD", "3"))
+	if err != nil || reportThreshold < 1 {
+		reportThreshold = 3
+	}
+
 	return &reviewService{
-		reviewRepo:     reviewRepo,
-		courseRepo:     courseRepo,
-		enrollmentRepo: enrollmentRepo,
+		reviewRepo:      reviewRepo,
+		courseRepo:      courseRepo,
+		enrollmentRepo:  enrollmentRepo,
+		transactor:      transactor,
+		contentFilter:   contentFilter,
+		reportThreshold: reportThreshold,
 	}
 }
 
@@ -57,12 +78,14 @@ func (rs *reviewService) GetCourseReviews(courseId uint, req *dto.GetCourseRevie
 	if req.Rating != nil {
 		filters["rating"] = *req.Rating
 	}
-	if req.Published != nil {
-		filters["is_published"] = *req.Published
-	} else {
-		// Default: only show published reviews
-		filters["is_published"] = true
+	if req.VerifiedPurchase != nil {
+		filters["verified_purchase"] = *req.VerifiedPurchase
+	}
+	if req.CompletedCourse != nil {
+		filters["completed_course"] = *req.CompletedCourse
 	}
+	// Public listing: only show published reviews
+	filters["is_published"] = true
 
 	// Get reviews with pagination
 	reviews, total, err := rs.reviewRepo.GetCourseReviews(courseId, offset, limit, filters, orderBy, sortBy)
@@ -90,6 +113,13 @@ func (rs *reviewService) GetCourseReviews(courseId uint, req *dto.GetCourseRevie
 			Comment:     review.Comment,
 			IsPublished: review.IsPublished,
 			CreatedAt:   review.CreatedAt,
+
+			HelpfulCount:       review.HelpfulCount,
+			NotHelpfulCount:    review.NotHelpfulCount,
+			IsVerifiedPurchase: review.IsVerifiedPurchase,
+			HasCompletedCourse: review.HasCompletedCourse,
+
+			Reply: toReviewReplyItem(review.Reply),
 		}
 	}
 
@@ -99,6 +129,8 @@ func (rs *reviewService) GetCourseReviews(courseId uint, req *dto.GetCourseRevie
 		// Log error but don't fail the request
 		stats = &dto.ReviewStats{
 			RatingDistribution: make(map[int]int),
+			VerifiedPurchase:   dto.ReviewFlagStats{RatingDistribution: make(map[int]int)},
+			CompletedCourse:    dto.ReviewFlagStats{RatingDistribution: make(map[int]int)},
 		}
 	}
 
@@ -142,16 +174,36 @@ func (rs *reviewService) CreateReview(userId, courseId uint, req *dto.CreateRevi
 		return nil, utils.NewError("You have already reviewed this course", utils.ErrCodeConflict)
 	}
 
-	// Create review
+	// Create review - nội dung bị filter đánh dấu được giữ lại chờ kiểm duyệt
 	review := &models.Review{
-		UserId:      userId,
-		CourseId:    courseId,
-		Rating:      req.Rating,
-		Comment:     req.Comment,
-		IsPublished: true,
+		UserId:           userId,
+		CourseId:         courseId,
+		Rating:           req.Rating,
+		Comment:          req.Comment,
+		IsPublished:      true,
+		ModerationStatus: reviewStatusApproved,
+	}
+	if flagged, reason := rs.contentFilter.Check(req.Comment); flagged {
+		review.IsPublished = false
+		review.ModerationStatus = reviewStatusPending
+		review.ModerationReason = "Auto-flagged: " + reason
 	}
 
-	if err := rs.reviewRepo.Create(review); err != nil {
+	// Lưu review cùng event ReviewCreated (thông báo cho instructor do event subscriber xử lý)
+	err = rs.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		if err := repos.Reviews.Create(review); err != nil {
+			return err
+		}
+		return repos.Outbox.Append(dto.ReviewCreatedEvent{
+			ReviewId:    review.Id,
+			UserId:      userId,
+			CourseId:    courseId,
+			Rating:      review.Rating,
+			Comment:     review.Comment,
+			IsPublished: review.IsPublished,
+		})
+	})
+	if err != nil {
 		return nil, utils.WrapError(err, "Failed to create review", utils.ErrCodeInternal)
 	}
 
@@ -160,12 +212,19 @@ func (rs *reviewService) CreateReview(userId, courseId uint, req *dto.CreateRevi
 		// Log error but don't fail the request
 	}
 
+	message := "Review created successfully"
+	if !review.IsPublished {
+		message = "Review submitted and is awaiting moderation"
+	}
+
 	return &dto.CreateReviewResponse{
-		Id:       review.Id,
-		CourseId: courseId,
-		Rating:   review.Rating,
-		Comment:  review.Comment,
-		Message:  "Review created successfully",
+		Id:               review.Id,
+		CourseId:         courseId,
+		Rating:           review.Rating,
+		Comment:          review.Comment,
+		IsPublished:      review.IsPublished,
+		ModerationStatus: review.ModerationStatus,
+		Message:          message,
 	}, nil
 }
 
@@ -187,9 +246,20 @@ func (rs *reviewService) UpdateReview(userId, reviewId uint, req *dto.UpdateRevi
 		updates["rating"] = *req.Rating
 		review.Rating = *req.Rating
 	}
+	visibilityChanged := false
 	if req.Comment != nil {
 		updates["comment"] = *req.Comment
 		review.Comment = *req.Comment
+
+		// Nội dung mới bị filter đánh dấu: giữ lại chờ duyệt (review đã bị admin ẩn thì giữ nguyên)
+		if flagged, reason := rs.contentFilter.Check(*req.Comment); flagged && review.ModerationStatus == reviewStatusApproved {
+			updates["moderation_status"] = reviewStatusPending
+			updates["moderation_reason"] = "Auto-flagged: " + reason
+			updates["is_published"] = false
+			review.ModerationStatus = reviewStatusPending
+			review.IsPublished = false
+			visibilityChanged = true
+		}
 	}
 
 	// Update review
@@ -198,17 +268,19 @@ func (rs *reviewService) UpdateReview(userId, reviewId uint, req *dto.UpdateRevi
 	}
 
 	// Update course rating stats
-	if req.Rating != nil {
+	if req.Rating != nil || visibilityChanged {
 		if err := rs.reviewRepo.UpdateCourseRatingStats(review.CourseId); err != nil {
 			// Log error but don't fail the request
 		}
 	}
 
 	return &dto.UpdateReviewResponse{
-		Id:      review.Id,
-		Rating:  review.Rating,
-		Comment: review.Comment,
-		Message: "Review updated successfully",
+		Id:               review.Id,
+		Rating:           review.Rating,
+		Comment:          review.Comment,
+		IsPublished:      review.IsPublished,
+		ModerationStatus: review.ModerationStatus,
+		Message:          "Review updated successfully",
 	}, nil
 }
 
@@ -240,3 +312,366 @@ func (rs *reviewService) DeleteReview(userId, reviewId uint) (*dto.DeleteReviewR
 		Message: "Review deleted successfully",
 	}, nil
 }
+
+func (rs *reviewService) ReportReview(userId, reviewId uint, req *dto.ReportReviewRequest) (*dto.ReportReviewResponse, error) {
+	// 1. Review phải tồn tại và đang hiển thị
+	review, err := rs.reviewRepo.FindById(reviewId)
+	if err != nil || !review.IsPublished {
+		return nil, utils.NewError("Review not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Không tự báo cáo review của mình, mỗi user báo cáo một lần
+	if review.UserId == userId {
+		return nil, utils.NewError("You cannot report your own review", utils.ErrCodeForbidden)
+	}
+
+	reported, err := rs.reviewRepo.HasReported(userId, reviewId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check report", utils.ErrCodeInternal)
+	}
+	if reported {
+		return nil, utils.NewError("You have already reported this review", utils.ErrCodeConflict)
+	}
+
+	// 3. Lưu report
+	report := &models.ReviewReport{
+		ReviewId: reviewId,
+		UserId:   userId,
+		Reason:   req.Reason,
+		Details:  strings.TrimSpace(req.Details),
+		Status:   "open",
+	}
+	if err := rs.reviewRepo.CreateReport(report); err != nil {
+		return nil, utils.WrapError(err, "Failed to report review", utils.ErrCodeInternal)
+	}
+
+	// 4. Đủ số report thì ẩn tạm review chờ admin xử lý
+	openReports, err := rs.reviewRepo.CountOpenReports(reviewId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count reports", utils.ErrCodeInternal)
+	}
+	if openReports >= rs.reportThreshold && review.ModerationStatus == reviewStatusApproved {
+		err := rs.reviewRepo.Update(reviewId, map[string]interface{}{
+			"moderation_status": reviewStatusPending,
+			"moderation_reason": "Auto-held: reported by multiple users",
+			"is_published":      false,
+		})
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to hold review", utils.ErrCodeInternal)
+		}
+
+		if err := rs.reviewRepo.UpdateCourseRatingStats(review.CourseId); err != nil {
+			// Log error but don't fail the request
+		}
+	}
+
+	return &dto.ReportReviewResponse{
+		ReviewId: reviewId,
+		Message:  "Thank you, the review has been reported to our moderators",
+	}, nil
+}
+
+func (rs *reviewService) UpsertReply(instructorId, reviewId uint, req *dto.ReviewReplyRequest) (*dto.ReviewReplyItem, error) {
+	// 1. Chỉ instructor của course được phản hồi review đang hiển thị
+	review, err := rs.findInstructorReview(instructorId, reviewId)
+	if err != nil {
+		return nil, err
+	}
+	if !review.IsPublished {
+		return nil, utils.NewError("You can only reply to published reviews", utils.ErrCodeBadRequest)
+	}
+
+	// 2. Tạo mới hoặc cập nhật phản hồi
+	reply := &models.ReviewReply{
+		ReviewId:     reviewId,
+		InstructorId: instructorId,
+		Content:      strings.TrimSpace(req.Content),
+	}
+	if err := rs.reviewRepo.SaveReply(reply); err != nil {
+		return nil, utils.WrapError(err, "Failed to save reply", utils.ErrCodeInternal)
+	}
+
+	saved, err := rs.reviewRepo.FindReply(reviewId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get reply", utils.ErrCodeInternal)
+	}
+
+	return toReviewReplyItem(saved), nil
+}
+
+func (rs *reviewService) DeleteReply(instructorId, reviewId uint) (*dto.DeleteReviewReplyResponse, error) {
+	review, err := rs.findInstructorReview(instructorId, reviewId)
+	if err != nil {
+		return nil, err
+	}
+
+	if review.Reply == nil {
+		return nil, utils.NewError("Reply not found", utils.ErrCodeNotFound)
+	}
+
+	if err := rs.reviewRepo.DeleteReply(reviewId); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete reply", utils.ErrCodeInternal)
+	}
+
+	return &dto.DeleteReviewReplyResponse{
+		Message: "Reply deleted successfully",
+	}, nil
+}
+
+func (rs *reviewService) GetModerationQueue(req *dto.GetReviewModerationQueryRequest) (*dto.GetReviewModerationQueueResponse, error) {
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	filters := make(map[string]interface{})
+	if req.Status != "" {
+		filters["status"] = req.Status
+	}
+	if req.CourseId != 0 {
+		filters["course_id"] = req.CourseId
+	}
+
+	reviews, total, err := rs.reviewRepo.GetModerationQueue(offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get moderation queue", utils.ErrCodeInternal)
+	}
+
+	reviewIds := make([]uint, len(reviews))
+	for i := range reviews {
+		reviewIds[i] = reviews[i].Id
+	}
+	reportCounts, err := rs.reviewRepo.GetOpenReportCounts(reviewIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count reports", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.ModerationReviewItem, len(reviews))
+	for i := range reviews {
+		items[i] = toModerationReviewItem(&reviews[i], reportCounts[reviews[i].Id])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetReviewModerationQueueResponse{
+		Reviews: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (rs *reviewService) ModerateReview(adminId, reviewId uint, req *dto.ModerateReviewRequest) (*dto.ModerateReviewResponse, error) {
+	// 1. Kiểm tra review
+	review, err := rs.reviewRepo.FindById(reviewId)
+	if err != nil {
+		return nil, utils.NewError("Review not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Xác định trạng thái mới: hide đóng các report (resolved), restore bỏ qua chúng (dismissed)
+	reason := strings.TrimSpace(req.Reason)
+	updates := map[string]interface{}{
+		"moderated_by": adminId,
+		"moderated_at": time.Now(),
+	}
+
+	var reportStatus, message string
+	switch req.Action {
+	case "hide":
+		if reason == "" {
+			return nil, utils.NewError("Reason is required when hiding a review", utils.ErrCodeBadRequest)
+		}
+		updates["moderation_status"] = reviewStatusHidden
+		updates["is_published"] = false
+		reportStatus = "resolved"
+		message = "Review hidden successfully"
+	case "restore":
+		updates["moderation_status"] = reviewStatusApproved
+		updates["is_published"] = true
+		reportStatus = "dismissed"
+		message = "Review restored successfully"
+	}
+	updates["moderation_reason"] = reason
+
+	// 3. Cập nhật review và report
+	if err := rs.reviewRepo.Moderate(reviewId, updates, reportStatus, adminId); err != nil {
+		return nil, utils.WrapError(err, "Failed to moderate review", utils.ErrCodeInternal)
+	}
+
+	// 4. Tính lại rating của course theo review đang hiển thị
+	if err := rs.reviewRepo.UpdateCourseRatingStats(review.CourseId); err != nil {
+		return nil, utils.WrapError(err, "Failed to update course rating", utils.ErrCodeInternal)
+	}
+
+	updated, err := rs.reviewRepo.FindById(reviewId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get review", utils.ErrCodeInternal)
+	}
+
+	return &dto.ModerateReviewResponse{
+		Review:  toModerationReviewItem(updated, 0),
+		Message: message,
+	}, nil
+}
+
+func (rs *reviewService) GetReviewReports(reviewId uint) (*dto.GetReviewReportsResponse, error) {
+	if _, err := rs.reviewRepo.FindById(reviewId); err != nil {
+		return nil, utils.NewError("Review not found", utils.ErrCodeNotFound)
+	}
+
+	reports, err := rs.reviewRepo.GetReviewReports(reviewId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get review reports", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.ReviewReportItem, len(reports))
+	for i, report := range reports {
+		items[i] = dto.ReviewReportItem{
+			Id:         report.Id,
+			UserId:     report.UserId,
+			UserName:   report.User.FullName,
+			Reason:     report.Reason,
+			Details:    report.Details,
+			Status:     report.Status,
+			ResolvedBy: report.ResolvedBy,
+			ResolvedAt: report.ResolvedAt,
+			CreatedAt:  report.CreatedAt,
+		}
+	}
+
+	return &dto.GetReviewReportsResponse{
+		ReviewId: reviewId,
+		Reports:  items,
+	}, nil
+}
+
+func (rs *reviewService) VoteReview(userId, reviewId uint, req *dto.VoteReviewRequest) (*dto.VoteReviewResponse, error) {
+	// 1. Chỉ vote review đang hiển thị và không phải của mình
+	review, err := rs.reviewRepo.FindById(reviewId)
+	if err != nil || !review.IsPublished {
+		return nil, utils.NewError("Review not found", utils.ErrCodeNotFound)
+	}
+	if review.UserId == userId {
+		return nil, utils.NewError("You cannot vote on your own review", utils.ErrCodeForbidden)
+	}
+
+	// 2. Lưu vote (đổi ý thì ghi đè vote cũ)
+	vote := &models.ReviewVote{
+		ReviewId:  reviewId,
+		UserId:    userId,
+		IsHelpful: *req.Helpful,
+	}
+	if err := rs.reviewRepo.SaveVote(vote); err != nil {
+		return nil, utils.WrapError(err, "Failed to save vote", utils.ErrCodeInternal)
+	}
+
+	// 3. Cập nhật số vote và Wilson score
+	response, err := rs.refreshHelpfulStats(reviewId)
+	if err != nil {
+		return nil, err
+	}
+	response.MyVote = req.Helpful
+	response.Message = "Thank you for your feedback"
+
+	return response, nil
+}
+
+func (rs *reviewService) RemoveVote(userId, reviewId uint) (*dto.VoteReviewResponse, error) {
+	if _, err := rs.reviewRepo.FindById(reviewId); err != nil {
+		return nil, utils.NewError("Review not found", utils.ErrCodeNotFound)
+	}
+
+	deleted, err := rs.reviewRepo.DeleteVote(userId, reviewId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to remove vote", utils.ErrCodeInternal)
+	}
+	if !deleted {
+		return nil, utils.NewError("Vote not found", utils.ErrCodeNotFound)
+	}
+
+	response, err := rs.refreshHelpfulStats(reviewId)
+	if err != nil {
+		return nil, err
+	}
+	response.Message = "Vote removed successfully"
+
+	return response, nil
+}
+
+// refreshHelpfulStats đếm lại vote từ bảng review_votes (không cộng dồn nên không lệch khi vote đồng thời)
+func (rs *reviewService) refreshHelpfulStats(reviewId uint) (*dto.VoteReviewResponse, error) {
+	helpful, notHelpful, err := rs.reviewRepo.CountVotes(reviewId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count votes", utils.ErrCodeInternal)
+	}
+
+	score := utils.WilsonLowerBound(helpful, helpful+notHelpful)
+	if err := rs.reviewRepo.UpdateHelpfulStats(reviewId, helpful, notHelpful, score); err != nil {
+		return nil, utils.WrapError(err, "Failed to update review", utils.ErrCodeInternal)
+	}
+
+	return &dto.VoteReviewResponse{
+		ReviewId:        reviewId,
+		HelpfulCount:    helpful,
+		NotHelpfulCount: notHelpful,
+	}, nil
+}
+
+// findInstructorReview lấy review và kiểm tra user là instructor của course
+func (rs *reviewService) findInstructorReview(instructorId, reviewId uint) (*models.Review, error) {
+	review, err := rs.reviewRepo.FindById(reviewId)
+	if err != nil {
+		return nil, utils.NewError("Review not found", utils.ErrCodeNotFound)
+	}
+
+	if review.Course.InstructorId != instructorId {
+		return nil, utils.NewError("You can only reply to reviews of your own courses", utils.ErrCodeForbidden)
+	}
+
+	return review, nil
+}
+
+func toReviewReplyItem(reply *models.ReviewReply) *dto.ReviewReplyItem {
+	if reply == nil {
+		return nil
+	}
+
+	return &dto.ReviewReplyItem{
+		Id:               reply.Id,
+		ReviewId:         reply.ReviewId,
+		InstructorId:     reply.InstructorId,
+		InstructorName:   reply.Instructor.FullName,
+		InstructorAvatar: reply.Instructor.AvatarURL,
+		Content:          reply.Content,
+		CreatedAt:        reply.CreatedAt,
+		UpdatedAt:        reply.UpdatedAt,
+	}
+}
+
+func toModerationReviewItem(review *models.Review, openReports int) dto.ModerationReviewItem {
+	return dto.ModerationReviewItem{
+		Id:               review.Id,
+		CourseId:         review.CourseId,
+		CourseTitle:      review.Course.Title,
+		UserId:           review.UserId,
+		UserName:         review.User.FullName,
+		Rating:           review.Rating,
+		Comment:          review.Comment,
+		IsPublished:      review.IsPublished,
+		ModerationStatus: review.ModerationStatus,
+		ModerationReason: review.ModerationReason,
+		ModeratedBy:      review.ModeratedBy,
+		ModeratedAt:      review.ModeratedAt,
+		OpenReports:      openReports,
+		CreatedAt:        review.CreatedAt,
+	}
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"lms/src/models"
+	"lms/src/utils"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+)
+
+// cmiElement mô tả một phần tử của data model cmi mà SCO được phép ghi
+type cmiElement struct {
+	validate func(value string) error
+	// transient: không lưu vào cmi data (session_time, exit), chỉ dùng để cập nhật attempt
+	transient bool
+}
+
+func cmiVocabulary(values ...string) func(string) error {
+	allowed := make(map[string]bool, len(values))
+	for _, value := range values {
+		allowed[value] = true
+	}
+	return func(value string) error {
+		if !allowed[value] {
+			return fmt.Errorf("must be one of %q", values)
+		}
+		return nil
+	}
+}
+
+func cmiMaxLength(max int) func(string) error {
+	return func(value string) error {
+		if utf8.RuneCountInString(value) > max {
+			return fmt.Errorf("must be at most %d characters", max)
+		}
+		return nil
+	}
+}
+
+// cmiDecimal kiểm tra số thực trong khoảng [min, max]; allowEmpty cho phép xóa giá trị (score của SCORM 1.2)
+func cmiDecimal(min, max float64, allowEmpty bool) func(string) error {
+	return func(value string) error {
+		if value == "" && allowEmpty {
+			return nil
+		}
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		if number < min || number > max {
+			return fmt.Errorf("must be between %g and %g", min, max)
+		}
+		return nil
+	}
+}
+
+func cmiReal(value string) error {
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return errors.New("must be a number")
+	}
+	return nil
+}
+
+func cmiTimespan(version string) func(string) error {
+	return func(value string) error {
+		_, err := utils.ParseScormDuration(version, value)
+		return err
+	}
+}
+
+var scorm12Elements = map[string]cmiElement{
+	"cmi.core.lesson_status":   {validate: cmiVocabulary("passed", "completed", "failed", "incomplete", "browsed")},
+	"cmi.core.lesson_location": {validate: cmiMaxLength(255)},
+	"cmi.core.score.raw":       {validate: cmiDecimal(0, 100, true)},
+	"cmi.core.score.min":       {validate: cmiDecimal(0, 100, true)},
+	"cmi.core.score.max":       {validate: cmiDecimal(0, 100, true)},
+	"cmi.core.exit":            {validate: cmiVocabulary("time-out", "suspend", "logout", ""), transient: true},
+	"cmi.core.session_time":    {validate: cmiTimespan(utils.ScormVersion12), transient: true},
+	"cmi.suspend_data":         {validate: cmiMaxLength(4096)},
+	"cmi.comments":             {validate: cmiMaxLength(4096)},
+}
+
+var scorm2004Elements = map[string]cmiElement{
+	"cmi.completion_status": {validate: cmiVocabulary("completed", "incomplete", "not attempted", "unknown")},
+	"cmi.success_status":    {validate: cmiVocabulary("passed", "failed", "unknown")},
+	"cmi.location":          {validate: cmiMaxLength(1000)},
+	"cmi.score.scaled":      {validate: cmiDecimal(-1, 1, false)},
+	"cmi.score.raw":         {validate: cmiReal},
+	"cmi.score.min":         {validate: cmiReal},
+	"cmi.score.max":         {validate: cmiReal},
+	"cmi.progress_measure":  {validate: cmiDecimal(0, 1, false)},
+	"cmi.exit":              {validate: cmiVocabulary("time-out", "suspend", "logout", "normal", ""), transient: true},
+	"cmi.session_time":      {validate: cmiTimespan(utils.ScormVersion2004), transient: true},
+	"cmi.suspend_data":      {validate: cmiMaxLength(64000)},
+	"adl.nav.request":       {validate: cmiMaxLength(1000)},
+}
+
+// Phần tử dạng collection (objectives, interactions, preferences, comments) chỉ được kiểm tra cấu trúc và độ dài
+var (
+	scorm12Collections   = regexp.MustCompile(`^cmi\.((objectives|interactions)\.\d+\.[a-z_]+(\.\d+(\.[a-z_]+)?|\.[a-z_]+)?|student_preference\.(audio|language|speed|text))$`)
+	scorm2004Collections = regexp.MustCompile(`^cmi\.((objectives|interactions)\.\d+\.[a-z_]+(\.\d+(\.[a-z_]+)?|\.[a-z_]+)?|comments_from_learner\.\d+\.(comment|location|timestamp)|learner_preference\.(audio_level|language|delivery_speed|audio_captioning))$`)
+)
+
+const cmiCollectionMaxLength = 4000
+
+// Phần tử chỉ đọc do LMS cung cấp khi launch
+var (
+	scorm12ReadOnly = map[string]bool{
+		"cmi.core.student_id": true, "cmi.core.student_name": true, "cmi.core.credit": true, "cmi.core.entry": true,
+		"cmi.core.total_time": true, "cmi.core.lesson_mode": true, "cmi.launch_data": true,
+		"cmi.student_data.mastery_score": true, "cmi.comments_from_lms": true,
+	}
+	scorm2004ReadOnly = map[string]bool{
+		"cmi.learner_id": true, "cmi.learner_name": true, "cmi.credit": true, "cmi.entry": true, "cmi.mode": true,
+		"cmi.total_time": true, "cmi.launch_data": true, "cmi.scaled_passing_score": true,
+		"cmi.completion_threshold": true, "cmi.max_time_allowed": true, "cmi.time_limit_action": true,
+	}
+)
+
+// validateCmiValue kiểm tra một phần tử SCO gửi lên theo data model của version
+func validateCmiValue(version, element, value string) (cmiElement, error) {
+	elements, collections, readOnly := scorm12Elements, scorm12Collections, scorm12ReadOnly
+	if version == utils.ScormVersion2004 {
+		elements, collections, readOnly = scorm2004Elements, scorm2004Collections, scorm2004ReadOnly
+	}
+
+	if readOnly[element] {
+		return cmiElement{}, errors.New("element is read only")
+	}
+
+	if rule, ok := elements[element]; ok {
+		return rule, rule.validate(value)
+	}
+
+	if collections.MatchString(element) {
+		return cmiElement{}, cmiMaxLength(cmiCollectionMaxLength)(value)
+	}
+
+	return cmiElement{}, errors.New("element is not implemented")
+}
+
+// scormStatus suy ra completion/success/score của attempt từ cmi data
+type scormStatus struct {
+	completion  string
+	success     string
+	scoreRaw    *float64
+	scoreScaled *float64
+}
+
+// completed là điều kiện hoàn thành lesson: SCO báo hoàn thành mà không trượt, hoặc báo đã pass
+func (ss scormStatus) completed() bool {
+	return (ss.completion == "completed" && ss.success != "failed") || ss.success == "passed"
+}
+
+func parseCmiNumber(data map[string]string, element string) *float64 {
+	value, ok := data[element]
+	if !ok || value == "" {
+		return nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	return &number
+}
+
+func resolveScormStatus(pkg *models.ScormPackage, data map[string]string) scormStatus {
+	if pkg.Version == utils.ScormVersion2004 {
+		status := scormStatus{
+			completion:  data["cmi.completion_status"],
+			success:     data["cmi.success_status"],
+			scoreRaw:    parseCmiNumber(data, "cmi.score.raw"),
+			scoreScaled: parseCmiNumber(data, "cmi.score.scaled"),
+		}
+		if status.completion == "" {
+			status.completion = "unknown"
+		}
+		if status.success == "" {
+			status.success = "unknown"
+		}
+		return status
+	}
+
+	// SCORM 1.2 gộp completion và success trong lesson_status
+	status := scormStatus{completion: "not attempted", success: "unknown", scoreRaw: parseCmiNumber(data, "cmi.core.score.raw")}
+	switch data["cmi.core.lesson_status"] {
+	case "passed":
+		status.completion, status.success = "completed", "passed"
+	case "failed":
+		status.completion, status.success = "completed", "failed"
+	case "completed":
+		status.completion = "completed"
+	case "incomplete", "browsed":
+		status.completion = "incomplete"
+	}
+
+	if status.scoreRaw != nil {
+		min := parseCmiNumber(data, "cmi.core.score.min")
+		max := parseCmiNumber(data, "cmi.core.score.max")
+		low, high := 0.0, 100.0
+		if min != nil {
+			low = *min
+		}
+		if max != nil {
+			high = *max
+		}
+		if high > low {
+			scaled := (*status.scoreRaw - low) / (high - low)
+			status.scoreScaled = &scaled
+		}
+	}
+	return status
+}
+
+// finishScorm12 áp dụng quy tắc của LMS khi SCO 1.2 gọi LMSFinish:
+// chưa báo trạng thái thì coi là completed; có mastery score và điểm thì LMS quyết định passed/failed.
+func finishScorm12(pkg *models.ScormPackage, data map[string]string) {
+	status := data["cmi.core.lesson_status"]
+	if status == "" || status == "not attempted" {
+		status = "completed"
+	}
+
+	if pkg.MasteryScore != nil && status != "browsed" {
+		if raw := parseCmiNumber(data, "cmi.core.score.raw"); raw != nil {
+			status = "failed"
+			if *raw >= *pkg.MasteryScore {
+				status = "passed"
+			}
+		}
+	}
+
+	data["cmi.core.lesson_status"] = status
+}
+
+// scormRuntimeValues là dữ liệu cmi trả về cho API adapter khi Initialize
+func scormRuntimeValues(pkg *models.ScormPackage, attempt *models.ScormAttempt, data map[string]string, learner *models.User, credit bool) map[string]string {
+	values := make(map[string]string, len(data)+10)
+	for element, value := range data {
+		values[element] = value
+	}
+
+	entry := "ab-initio"
+	totalTime := 0.0
+	if attempt != nil {
+		entry = ""
+		if attempt.Suspended {
+			entry = "resume"
+		}
+		totalTime = attempt.TotalTime
+	}
+
+	creditValue, mode := "credit", "normal"
+	if !credit {
+		creditValue, mode = "no-credit", "browse"
+	}
+
+	learnerId, learnerName := "", ""
+	if learner != nil {
+		learnerId, learnerName = strconv.FormatUint(uint64(learner.Id), 10), learner.FullName
+	}
+
+	if pkg.Version == utils.ScormVersion2004 {
+		values["cmi.learner_id"] = learnerId
+		values["cmi.learner_name"] = learnerName
+		values["cmi.credit"] = creditValue
+		values["cmi.mode"] = mode
+		values["cmi.entry"] = entry
+		values["cmi.total_time"] = utils.FormatScormDuration(pkg.Version, totalTime)
+		values["cmi.launch_data"] = pkg.LaunchData
+		if _, ok := values["cmi.completion_status"]; !ok {
+			values["cmi.completion_status"] = "unknown"
+		}
+		if _, ok := values["cmi.success_status"]; !ok {
+			values["cmi.success_status"] = "unknown"
+		}
+		return values
+	}
+
+	values["cmi.core.student_id"] = learnerId
+	values["cmi.core.student_name"] = learnerName
+	values["cmi.core.credit"] = creditValue
+	values["cmi.core.lesson_mode"] = mode
+	values["cmi.core.entry"] = entry
+	values["cmi.core.total_time"] = utils.FormatScormDuration(pkg.Version, totalTime)
+	values["cmi.launch_data"] = pkg.LaunchData
+	if pkg.MasteryScore != nil {
+		values["cmi.student_data.mastery_score"] = strconv.FormatFloat(*pkg.MasteryScore, 'f', -1, 64)
+	}
+	if _, ok := values["cmi.core.lesson_status"]; !ok {
+		values["cmi.core.lesson_status"] = "not attempted"
+	}
+	return values
+}
@@ -0,0 +1,578 @@
+package service
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+	"math"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Thời hạn của URL launch; nội dung tải sau khi hết hạn cần launch lại
+	scormLaunchURLTTL = 4 * time.Hour
+	scormMaxFileCount = 10000
+)
+
+// File được trả trực tiếp qua API để URL tương đối bên trong vẫn đi qua token;
+// file còn lại (ảnh, media) được redirect tới presigned URL của storage
+var scormDirectExts = map[string]bool{
+	".html": true, ".htm": true, ".js": true, ".css": true, ".xml": true, ".json": true, ".svg": true, ".txt": true,
+}
+
+type scormService struct {
+	scormRepo       repository.ScormRepository
+	instructorRepo  repository.InstructorRepository
+	lessonRepo      repository.LessonRepository
+	userRepo        repository.UserRepository
+	progressService ProgressService
+	store           storage.Storage
+}
+
+// ScormContent là kết quả resolve một file trong package
+type ScormContent struct {
+	Body        io.ReadCloser
+	ContentType string
+	RedirectURL string
+}
+
+func NewScormService(
+	scormRepo repository.ScormRepository,
+	instructorRepo repository.InstructorRepository,
+	lessonRepo repository.LessonRepository,
+	userRepo repository.UserRepository,
+	progressService ProgressService,
+	store storage.Storage,
+) ScormService {
+	return &scormService{
+		scormRepo:       scormRepo,
+		instructorRepo:  instructorRepo,
+		lessonRepo:      lessonRepo,
+		userRepo:        userRepo,
+		progressService: progressService,
+		store:           store,
+	}
+}
+
+// scormMaxSize giới hạn dung lượng sau giải nén của package (SCORM_MAX_SIZE_MB, mặc định 500MB)
+func scormMaxSize() int64 {
+	maxSizeMB, err := strconv.Atoi(utils.GetEnv("SCORM_MAX_SIZE_MB", "500"))
+	if err != nil || maxSizeMB < 1 {
+		maxSizeMB = 500
+	}
+	return int64(maxSizeMB) << 20
+}
+
+// lessonScormPrefix prefix (private) chứa các package SCORM của một lesson trên storage
+func lessonScormPrefix(lessonId uint) string {
+	return fmt.Sprintf("%sscorm/%d/", storage.PrivatePrefix, lessonId)
+}
+
+// ---------------- Instructor ----------------
+
+func (ss *scormService) UploadPackage(instructorId, courseId, lessonId uint, file *multipart.FileHeader) (*dto.ScormPackageResponse, error) {
+	// 1. Kiểm tra course và lesson thuộc về instructor
+	if _, err := ss.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+	if _, err := ss.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId); err != nil {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Kiểm tra file zip
+	maxSize := scormMaxSize()
+	if strings.ToLower(path.Ext(file.Filename)) != ".zip" {
+		return nil, utils.NewError("SCORM package must be a .zip file", utils.ErrCodeBadRequest)
+	}
+	if file.Size > maxSize {
+		return nil, utils.NewError(fmt.Sprintf("SCORM package too large (max %dMB)", maxSize>>20), utils.ErrCodeBadRequest)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to read SCORM package", utils.ErrCodeBadRequest)
+	}
+	defer src.Close()
+
+	zr, err := zip.NewReader(src, file.Size)
+	if err != nil {
+		return nil, utils.NewError("SCORM package is not a valid zip archive", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Liệt kê file, chặn đường dẫn ra ngoài package và zip bomb
+	files := make(map[string]*zip.File, len(zr.File))
+	var totalSize int64
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := path.Clean(strings.ReplaceAll(f.Name, "\\", "/"))
+		if strings.HasPrefix(name, "/") || strings.HasPrefix(name, "../") || name == ".." {
+			return nil, utils.NewError(fmt.Sprintf("SCORM package contains an invalid path: %s", f.Name), utils.ErrCodeBadRequest)
+		}
+		files[name] = f
+		totalSize += int64(f.UncompressedSize64)
+	}
+	if len(files) > scormMaxFileCount {
+		return nil, utils.NewError(fmt.Sprintf("SCORM package has too many files (max %d)", scormMaxFileCount), utils.ErrCodeBadRequest)
+	}
+	if totalSize > maxSize {
+		return nil, utils.NewError(fmt.Sprintf("SCORM package too large when extracted (max %dMB)", maxSize>>20), utils.ErrCodeBadRequest)
+	}
+
+	// 4. Đọc imsmanifest.xml ở gốc package
+	manifestFile, ok := files[utils.ScormManifestName]
+	if !ok {
+		return nil, utils.NewError("imsmanifest.xml not found at the root of the SCORM package", utils.ErrCodeBadRequest)
+	}
+	data, err := readZipEntry(manifestFile, 5<<20)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to read imsmanifest.xml", utils.ErrCodeBadRequest)
+	}
+
+	manifest, err := utils.ParseScormManifest(data)
+	if err != nil {
+		return nil, utils.NewError(err.Error(), utils.ErrCodeBadRequest)
+	}
+	if _, ok := files[manifest.LaunchPath]; !ok {
+		return nil, utils.NewError(fmt.Sprintf("Launch file %s declared in imsmanifest.xml not found in package", manifest.LaunchPath), utils.ErrCodeBadRequest)
+	}
+
+	// 5. Giải nén lên storage dưới prefix mới, package cũ chỉ bị xóa khi package mới đã lưu xong
+	prefix := lessonScormPrefix(lessonId) + uuid.New().String() + "/"
+	for name, f := range files {
+		if err := ss.extractFile(f, prefix+name); err != nil {
+			storage.DeletePrefixQuietly(ss.store, prefix)
+			return nil, utils.WrapError(err, fmt.Sprintf("Failed to store %s", name), utils.ErrCodeInternal)
+		}
+	}
+
+	pkg := &models.ScormPackage{
+		LessonId:      lessonId,
+		CourseId:      courseId,
+		Version:       manifest.Version,
+		Identifier:    manifest.Identifier,
+		Title:         manifest.Title,
+		LaunchPath:    manifest.LaunchPath,
+		LaunchQuery:   manifest.LaunchQuery,
+		LaunchData:    manifest.LaunchData,
+		MasteryScore:  manifest.MasteryScore,
+		StoragePrefix: prefix,
+		FileName:      path.Base(file.Filename),
+		FileCount:     len(files),
+		TotalSize:     totalSize,
+		UploadedBy:    instructorId,
+	}
+
+	previous, err := ss.scormRepo.ReplacePackage(pkg)
+	if err != nil {
+		storage.DeletePrefixQuietly(ss.store, prefix)
+		return nil, utils.WrapError(err, "Failed to save SCORM package", utils.ErrCodeInternal)
+	}
+	if previous != nil {
+		storage.DeletePrefixQuietly(ss.store, previous.StoragePrefix)
+	}
+
+	response := toScormPackageResponse(pkg)
+	response.ScoCount = manifest.ScoCount
+	return response, nil
+}
+
+func (ss *scormService) extractFile(f *zip.File, key string) error {
+	body, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return ss.store.Put(key, body, int64(f.UncompressedSize64), scormContentType(key))
+}
+
+func (ss *scormService) GetPackage(instructorId, courseId, lessonId uint) (*dto.ScormPackageResponse, error) {
+	pkg, err := ss.findInstructorPackage(instructorId, courseId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+	return toScormPackageResponse(pkg), nil
+}
+
+func (ss *scormService) DeletePackage(instructorId, courseId, lessonId uint) (*dto.DeleteScormPackageResponse, error) {
+	pkg, err := ss.findInstructorPackage(instructorId, courseId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ss.scormRepo.DeletePackage(pkg); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete SCORM package", utils.ErrCodeInternal)
+	}
+	storage.DeletePrefixQuietly(ss.store, pkg.StoragePrefix)
+
+	return &dto.DeleteScormPackageResponse{
+		Message:  "SCORM package deleted successfully",
+		LessonId: lessonId,
+	}, nil
+}
+
+func (ss *scormService) GetAttempts(instructorId, courseId, lessonId uint, req *dto.GetScormAttemptsQueryRequest) (*dto.GetScormAttemptsResponse, error) {
+	pkg, err := ss.findInstructorPackage(instructorId, courseId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	filters := make(map[string]interface{})
+	if req.CompletionStatus != "" {
+		filters["completion_status"] = req.CompletionStatus
+	}
+
+	attempts, total, err := ss.scormRepo.GetAttempts(pkg.Id, offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get SCORM attempts", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.ScormAttemptItem, len(attempts))
+	for i := range attempts {
+		attempt := &attempts[i]
+		items[i] = dto.ScormAttemptItem{
+			UserId:           attempt.UserId,
+			FullName:         attempt.User.FullName,
+			Email:            attempt.User.Email,
+			CompletionStatus: attempt.CompletionStatus,
+			SuccessStatus:    attempt.SuccessStatus,
+			ScoreRaw:         attempt.ScoreRaw,
+			ScoreScaled:      attempt.ScoreScaled,
+			TotalTime:        attempt.TotalTime,
+			CommitCount:      attempt.CommitCount,
+			LastCommittedAt:  attempt.LastCommittedAt,
+			CompletedAt:      attempt.CompletedAt,
+		}
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetScormAttemptsResponse{
+		Package:  *toScormPackageResponse(pkg),
+		Attempts: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (ss *scormService) findInstructorPackage(instructorId, courseId, lessonId uint) (*models.ScormPackage, error) {
+	if _, err := ss.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+	if _, err := ss.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId); err != nil {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	pkg, err := ss.scormRepo.FindPackageByLesson(lessonId)
+	if err != nil {
+		return nil, utils.NewError("Lesson has no SCORM package", utils.ErrCodeNotFound)
+	}
+	return pkg, nil
+}
+
+// ---------------- Runtime ----------------
+
+func (ss *scormService) Launch(userId, lessonId uint) (*dto.ScormLaunchResponse, error) {
+	// 1. Lesson, package và quyền truy cập (lesson preview ai cũng chạy được nhưng không được lưu kết quả)
+	lesson, pkg, enrolled, err := ss.resolveRuntimeAccess(userId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Dữ liệu cmi đã lưu của học viên
+	var attempt *models.ScormAttempt
+	data := make(map[string]string)
+	if enrolled {
+		attempt, err = ss.scormRepo.FindAttempt(userId, pkg.Id)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to get SCORM attempt", utils.ErrCodeInternal)
+		}
+		if attempt != nil {
+			data = decodeCmiData(attempt.CmiData)
+		}
+	}
+
+	learner, err := ss.userRepo.FindById(userId)
+	if err != nil {
+		return nil, utils.NewError("User not found", utils.ErrCodeNotFound)
+	}
+
+	// 3. URL launch có token, URL tương đối trong nội dung vẫn đi qua token.
+	// SCORM_CONTENT_BASE_URL cho phép phục vụ nội dung từ một origin riêng không có cookie.
+	expiresAt := time.Now().Add(scormLaunchURLTTL)
+	baseURL := strings.TrimRight(utils.GetEnv("SCORM_CONTENT_BASE_URL", utils.GetEnv("BASE_URL", "http://localhost:8080")), "/")
+	launchPath := (&url.URL{Path: pkg.LaunchPath}).EscapedPath()
+	launchURL := fmt.Sprintf("%s/api/v1/scorm/content/%s/%s", baseURL, utils.GenerateScormContentToken(pkg.Id, expiresAt), launchPath)
+	if pkg.LaunchQuery != "" {
+		launchURL += "?" + pkg.LaunchQuery
+	}
+
+	apiName := "API"
+	if pkg.Version == utils.ScormVersion2004 {
+		apiName = "API_1484_11"
+	}
+
+	return &dto.ScormLaunchResponse{
+		LessonId:  lesson.Id,
+		PackageId: pkg.Id,
+		Version:   pkg.Version,
+		APIName:   apiName,
+		LaunchURL: launchURL,
+		ExpiresAt: expiresAt,
+		CanCommit: enrolled,
+		Values:    scormRuntimeValues(pkg, attempt, data, learner, enrolled),
+	}, nil
+}
+
+func (ss *scormService) Commit(userId, lessonId uint, req *dto.ScormCommitRequest) (*dto.ScormRuntimeResponse, error) {
+	// 1. Chỉ học viên đã enroll mới được lưu dữ liệu cmi
+	lesson, pkg, enrolled, err := ss.resolveRuntimeAccess(userId, lessonId)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, utils.NewError("You must enroll in this course to save SCORM progress", utils.ErrCodeForbidden)
+	}
+
+	// 2. Validate toàn bộ giá trị trước khi ghi (theo thứ tự element để lỗi trả về ổn định)
+	elements := make([]string, 0, len(req.Values))
+	for element := range req.Values {
+		elements = append(elements, element)
+	}
+	sort.Strings(elements)
+
+	rules := make(map[string]cmiElement, len(elements))
+	for _, element := range elements {
+		rule, err := validateCmiValue(pkg.Version, element, req.Values[element])
+		if err != nil {
+			return nil, utils.NewError(fmt.Sprintf("%s: %s", element, err.Error()), utils.ErrCodeValidation)
+		}
+		rules[element] = rule
+	}
+
+	// 3. Lấy hoặc tạo attempt
+	attempt, err := ss.scormRepo.FindAttempt(userId, pkg.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get SCORM attempt", utils.ErrCodeInternal)
+	}
+	if attempt == nil {
+		attempt = &models.ScormAttempt{
+			UserId:    userId,
+			PackageId: pkg.Id,
+			LessonId:  lesson.Id,
+			CourseId:  lesson.CourseId,
+		}
+	}
+	data := decodeCmiData(attempt.CmiData)
+
+	// 4. Ghi giá trị: session_time cộng vào total_time, exit quyết định entry của lần launch sau
+	for _, element := range elements {
+		value := req.Values[element]
+		if !rules[element].transient {
+			data[element] = value
+			continue
+		}
+
+		switch element {
+		case "cmi.core.session_time", "cmi.session_time":
+			seconds, _ := utils.ParseScormDuration(pkg.Version, value)
+			attempt.TotalTime += seconds
+		case "cmi.core.exit", "cmi.exit":
+			attempt.Suspended = value == "suspend"
+		}
+	}
+
+	if req.Finish && pkg.Version == utils.ScormVersion12 {
+		finishScorm12(pkg, data)
+	}
+
+	status := resolveScormStatus(pkg, data)
+	now := time.Now()
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to encode SCORM data", utils.ErrCodeInternal)
+	}
+	attempt.CmiData = string(encoded)
+	attempt.CompletionStatus = status.completion
+	attempt.SuccessStatus = status.success
+	attempt.ScoreRaw = status.scoreRaw
+	attempt.ScoreScaled = status.scoreScaled
+	attempt.CommitCount++
+	attempt.LastCommittedAt = &now
+
+	if err := ss.scormRepo.SaveAttempt(attempt); err != nil {
+		return nil, utils.WrapError(err, "Failed to save SCORM data", utils.ErrCodeInternal)
+	}
+
+	// 5. Hoàn thành lần đầu: đi qua ProgressService để cập nhật progress, enrollment và phát event.
+	// Lỗi ở bước này không làm mất dữ liệu cmi đã lưu, lần commit sau sẽ thử lại.
+	if status.completed() && attempt.CompletedAt == nil {
+		_, err := ss.progressService.completeLesson(userId, lesson, &dto.CompleteLessonRequest{WatchDuration: int(attempt.TotalTime)})
+		if err != nil {
+			log.Printf("Failed to complete SCORM lesson %d for user %d: %v", lesson.Id, userId, err)
+		} else {
+			attempt.CompletedAt = &now
+			if err := ss.scormRepo.SaveAttempt(attempt); err != nil {
+				log.Printf("Failed to mark SCORM attempt %d completed: %v", attempt.Id, err)
+			}
+		}
+	}
+
+	return &dto.ScormRuntimeResponse{
+		LessonId:         lesson.Id,
+		PackageId:        pkg.Id,
+		Version:          pkg.Version,
+		CompletionStatus: attempt.CompletionStatus,
+		SuccessStatus:    attempt.SuccessStatus,
+		ScoreRaw:         attempt.ScoreRaw,
+		ScoreScaled:      attempt.ScoreScaled,
+		TotalTime:        attempt.TotalTime,
+		LessonCompleted:  attempt.CompletedAt != nil,
+		CommittedAt:      now,
+		Values:           data,
+	}, nil
+}
+
+// resolveRuntimeAccess kiểm tra lesson SCORM đã publish, học viên đã enroll (hoặc lesson preview) và lesson đã mở khóa
+func (ss *scormService) resolveRuntimeAccess(userId, lessonId uint) (*models.Lesson, *models.ScormPackage, bool, error) {
+	lesson, err := ss.scormRepo.FindLessonById(lessonId)
+	if err != nil || !lesson.IsPublished || lesson.LessonType != "scorm" {
+		return nil, nil, false, utils.NewError("SCORM lesson not found", utils.ErrCodeNotFound)
+	}
+
+	pkg, err := ss.scormRepo.FindPackageByLesson(lessonId)
+	if err != nil {
+		return nil, nil, false, utils.NewError("SCORM lesson not found", utils.ErrCodeNotFound)
+	}
+
+	enrolled, err := ss.lessonRepo.CheckUserEnrollment(userId, lesson.CourseId)
+	if err != nil {
+		return nil, nil, false, utils.WrapError(err, "Failed to check enrollment", utils.ErrCodeInternal)
+	}
+	if !enrolled {
+		if !lesson.IsPreview {
+			return nil, nil, false, utils.NewError("You must enroll in this course to open this lesson", utils.ErrCodeForbidden)
+		}
+		return lesson, pkg, false, nil
+	}
+
+	lock, err := getLessonLock(ss.lessonRepo, userId, lesson.CourseId, lesson.Id)
+	if err != nil {
+		return nil, nil, false, utils.WrapError(err, "Failed to resolve lesson schedule", utils.ErrCodeInternal)
+	}
+	if lock.isLocked {
+		return nil, nil, false, utils.NewError(lockMessage(lock), utils.ErrCodeForbidden)
+	}
+
+	return lesson, pkg, true, nil
+}
+
+// ResolveContent trả về một file trong package theo token của URL launch
+func (ss *scormService) ResolveContent(token, filePath string) (*ScormContent, error) {
+	// 1. Kiểm tra token
+	packageId, err := utils.VerifyScormContentToken(token)
+	if err != nil {
+		return nil, utils.NewError(err.Error(), utils.ErrCodeForbidden)
+	}
+
+	pkg, err := ss.scormRepo.FindPackageById(packageId)
+	if err != nil {
+		return nil, utils.NewError("Content not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Chỉ cho phép đọc file trong thư mục của package
+	key := path.Join(pkg.StoragePrefix, path.Clean("/"+filePath))
+	if !strings.HasPrefix(key, pkg.StoragePrefix) {
+		return nil, utils.NewError("Content not found", utils.ErrCodeNotFound)
+	}
+
+	// 3. Media: redirect tới presigned URL để không tải qua API server
+	if !scormDirectExts[strings.ToLower(path.Ext(key))] {
+		signedURL, err := ss.store.PresignGet(key, scormLaunchURLTTL)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to sign content URL", utils.ErrCodeInternal)
+		}
+		return &ScormContent{RedirectURL: signedURL}, nil
+	}
+
+	// 4. HTML/JS/CSS: đọc từ storage
+	body, err := ss.store.Get(key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, utils.NewError("Content not found", utils.ErrCodeNotFound)
+		}
+		return nil, utils.WrapError(err, "Failed to read content", utils.ErrCodeInternal)
+	}
+
+	return &ScormContent{Body: body, ContentType: scormContentType(key)}, nil
+}
+
+func scormContentType(key string) string {
+	if contentType := mime.TypeByExtension(strings.ToLower(path.Ext(key))); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+func decodeCmiData(raw string) map[string]string {
+	data := make(map[string]string)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			log.Printf("Failed to decode SCORM data: %v", err)
+		}
+	}
+	return data
+}
+
+func toScormPackageResponse(pkg *models.ScormPackage) *dto.ScormPackageResponse {
+	return &dto.ScormPackageResponse{
+		Id:           pkg.Id,
+		LessonId:     pkg.LessonId,
+		CourseId:     pkg.CourseId,
+		Version:      pkg.Version,
+		Identifier:   pkg.Identifier,
+		Title:        pkg.Title,
+		LaunchPath:   pkg.LaunchPath,
+		MasteryScore: pkg.MasteryScore,
+		FileName:     pkg.FileName,
+		FileCount:    pkg.FileCount,
+		TotalSize:    pkg.TotalSize,
+		CreatedAt:    pkg.CreatedAt,
+		UpdatedAt:    pkg.UpdatedAt,
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"lms/src/dto"
+	"lms/src/storage"
+	"lms/src/utils"
+	"mime"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Thời hạn của presigned upload URL
+const presignUploadTTL = 15 * time.Minute
+
+type storageService struct {
+	store storage.Storage
+}
+
+func NewStorageService(store storage.Storage) StorageService {
+	return &storageService{
+		store: store,
+	}
+}
+
+func (ss *storageService) PresignUpload(userId uint, role string, req *dto.PresignUploadRequest) (*dto.PresignUploadResponse, error) {
+	// 1. Kiểm tra quyền theo mục đích upload
+	var folder string
+	switch req.Purpose {
+	case "avatar":
+		folder = "avatars"
+	case "thumbnail":
+		if role != "instructor" && role != "admin" {
+			return nil, utils.NewError("Only instructors can upload course thumbnails", utils.ErrCodeForbidden)
+		}
+		folder = "thumbnails"
+	}
+
+	// 2. Validate tên file và dung lượng
+	ext, err := utils.ValidateFileName(req.FileName, req.FileSize, utils.ImageFileRule)
+	if err != nil {
+		return nil, utils.WrapError(err, "Invalid image file", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Tạo key mới trong thư mục riêng của user (avatar_url/thumbnail_url chỉ nhận key trong thư mục này)
+	key := fmt.Sprintf("%s%s%s", storage.OwnerPrefix(folder, userId), uuid.New().String(), ext)
+	uploadURL, err := ss.store.PresignPut(key, req.ContentType, presignUploadTTL)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to create upload URL", utils.ErrCodeInternal)
+	}
+
+	return &dto.PresignUploadResponse{
+		Key:       key,
+		Method:    "PUT",
+		UploadURL: uploadURL,
+		Headers:   map[string]string{"Content-Type": req.ContentType},
+		PublicURL: ss.store.URL(key),
+		ExpiresAt: time.Now().Add(presignUploadTTL),
+	}, nil
+}
+
+func (ss *storageService) PutLocalObject(key, expires, signature string, body io.Reader, size int64, contentType string) error {
+	// 1. Chỉ hỗ trợ khi dùng local driver
+	local, err := ss.localStorage()
+	if err != nil {
+		return err
+	}
+
+	// 2. Kiểm tra chữ ký presigned URL
+	if err := local.VerifyPresigned("PUT", key, expires, signature); err != nil {
+		return utils.NewError(err.Error(), utils.ErrCodeForbidden)
+	}
+
+	// 3. Kiểm tra dung lượng
+	if size <= 0 {
+		return utils.NewError("Content-Length is required", utils.ErrCodeBadRequest)
+	}
+	if size > utils.ImageFileRule.MaxSize {
+		return utils.NewError("File too large (max 5MB)", utils.ErrCodeBadRequest)
+	}
+
+	if err := local.Put(key, io.LimitReader(body, size), size, contentType); err != nil {
+		return utils.WrapError(err, "Failed to store file", utils.ErrCodeInternal)
+	}
+
+	return nil
+}
+
+func (ss *storageService) GetLocalObject(key, expires, signature string) (io.ReadCloser, string, error) {
+	// 1. Chỉ hỗ trợ khi dùng local driver
+	local, err := ss.localStorage()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// 2. Kiểm tra chữ ký presigned URL
+	if err := local.VerifyPresigned("GET", key, expires, signature); err != nil {
+		return nil, "", utils.NewError(err.Error(), utils.ErrCodeForbidden)
+	}
+
+	body, err := local.Get(key)
+	if err != nil {
+		return nil, "", utils.NewError("File not found", utils.ErrCodeNotFound)
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = hlsContentType(key)
+	}
+
+	return body, contentType, nil
+}
+
+func (ss *storageService) localStorage() (*storage.LocalStorage, error) {
+	local, ok := ss.store.(*storage.LocalStorage)
+	if !ok {
+		return nil, utils.NewError("Local storage is not enabled", utils.ErrCodeNotFound)
+	}
+	return local, nil
+}
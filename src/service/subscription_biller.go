@@ -0,0 +1,183 @@
+package service
+
+import (
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"log"
+	"strconv"
+	"time"
+)
+
+const (
+	// Số subscription tối đa xử lý trong một lượt
+	subscriptionBillingBatchSize = 100
+	// Khoảng cách giữa các lần thu lại khi past_due
+	subscriptionRetryInterval = 24 * time.Hour
+)
+
+// SubscriptionBiller chạy background job gia hạn subscription: hết trial/hết kỳ thì thu tiền kỳ mới,
+// thu thất bại thì chuyển past_due và thu lại mỗi ngày đến hết thời gian gia hạn,
+// subscription đã hủy hoặc hết gia hạn thì kết thúc.
+type SubscriptionBiller struct {
+	subscriptionRepo repository.SubscriptionRepository
+	transactor       repository.Transactor
+	paymentGateway   PaymentGateway
+	interval         time.Duration
+}
+
+func NewSubscriptionBiller(subscriptionRepo repository.SubscriptionRepository, transactor repository.Transactor, paymentGateway PaymentGateway) *SubscriptionBiller {
+	seconds, err := strconv.Atoi(utils.GetEnv("SUBSCRIPTION_BILLING_INTERVAL_SECONDS", "300"))
+	if err != nil || seconds < 1 {
+		seconds = 300
+	}
+
+	return &SubscriptionBiller{
+		subscriptionRepo: subscriptionRepo,
+		transactor:       transactor,
+		paymentGateway:   paymentGateway,
+		interval:         time.Duration(seconds) * time.Second,
+	}
+}
+
+func (sb *SubscriptionBiller) StartWorkers() {
+	go func() {
+		ticker := time.NewTicker(sb.interval)
+		defer ticker.Stop()
+
+		for {
+			sb.billDue()
+			<-ticker.C
+		}
+	}()
+}
+
+func (sb *SubscriptionBiller) billDue() {
+	subscriptions, err := sb.subscriptionRepo.GetDueSubscriptions(time.Now(), subscriptionBillingBatchSize)
+	if err != nil {
+		log.Printf("Failed to load due subscriptions: %v", err)
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		if err := sb.process(subscription.TenantId, subscription.Id); err != nil {
+			log.Printf("Failed to bill subscription %d: %v", subscription.Id, err)
+		}
+	}
+}
+
+// process khóa subscription rồi kiểm tra lại trạng thái (instance khác có thể đã xử lý) trước khi thu tiền.
+// Chạy trong tenant của subscription để payment và domain event được gán đúng tenant.
+func (sb *SubscriptionBiller) process(tenantId, subscriptionId uint) error {
+	now := time.Now()
+	return sb.transactor.ForTenant(tenantId).WithinTransaction(func(repos *repository.TxRepositories) error {
+		subscription, err := repos.Subscriptions.LockSubscription(subscriptionId)
+		if err != nil {
+			return err
+		}
+
+		switch subscription.Status {
+		case "trialing", "active":
+			if subscription.CurrentPeriodEnd.After(now) {
+				return nil
+			}
+			// 1. Đã hủy: kết thúc khi hết kỳ, không thu tiền
+			if subscription.CancelAtPeriodEnd {
+				return endSubscription(repos, subscription, "cancelled", now)
+			}
+
+			// 2. Gia hạn: kỳ mới nối tiếp kỳ cũ, nếu job chạy trễ quá một kỳ thì bắt đầu từ bây giờ
+			periodStart := subscription.CurrentPeriodEnd
+			if !nextPeriodEnd(periodStart, subscription.Interval).After(now) {
+				periodStart = now
+			}
+			payment, err := chargeSubscription(repos, sb.paymentGateway, subscription, periodStart, now)
+			if err != nil || payment.Status == "paid" {
+				return err
+			}
+
+			// 3. Thu thất bại: giữ quyền truy cập trong thời gian gia hạn của plan
+			return sb.startGracePeriod(repos, subscription, payment, now)
+
+		case "past_due":
+			// 4. Hết thời gian gia hạn mà vẫn chưa thu được tiền
+			if subscription.GraceEndsAt == nil || !subscription.GraceEndsAt.After(now) {
+				return endSubscription(repos, subscription, "expired", now)
+			}
+			if subscription.NextRetryAt != nil && subscription.NextRetryAt.After(now) {
+				return nil
+			}
+
+			// 5. Thu lại, thành công thì kỳ mới bắt đầu từ bây giờ
+			payment, err := chargeSubscription(repos, sb.paymentGateway, subscription, now, now)
+			if err != nil || payment.Status == "paid" {
+				return err
+			}
+			return repos.Subscriptions.UpdateSubscription(subscription.Id, map[string]interface{}{
+				"next_retry_at": now.Add(subscriptionRetryInterval),
+			})
+		}
+		return nil
+	})
+}
+
+// startGracePeriod chuyển subscription sang past_due, hết gia hạn sau GracePeriodDays của plan (tính từ cuối kỳ)
+func (sb *SubscriptionBiller) startGracePeriod(repos *repository.TxRepositories, subscription *models.Subscription, payment *models.SubscriptionPayment, now time.Time) error {
+	graceDays := 0
+	plan, err := repos.Subscriptions.FindPlanById(subscription.PlanId)
+	if err != nil {
+		return err
+	}
+	if plan != nil {
+		graceDays = plan.GracePeriodDays
+	}
+
+	graceEndsAt := subscription.CurrentPeriodEnd.AddDate(0, 0, graceDays)
+	if !graceEndsAt.After(now) {
+		return endSubscription(repos, subscription, "expired", now)
+	}
+
+	if err := repos.Subscriptions.UpdateSubscription(subscription.Id, map[string]interface{}{
+		"status":        "past_due",
+		"grace_ends_at": graceEndsAt,
+		"next_retry_at": now.Add(subscriptionRetryInterval),
+	}); err != nil {
+		return err
+	}
+
+	return repos.Outbox.Append(dto.SubscriptionPaymentFailedEvent{
+		SubscriptionId: subscription.Id,
+		PaymentId:      payment.Id,
+		UserId:         subscription.UserId,
+		PlanId:         subscription.PlanId,
+		Amount:         payment.Amount,
+		Reason:         payment.FailureReason,
+		GraceEndsAt:    graceEndsAt,
+	})
+}
+
+// endSubscription kết thúc subscription (cancelled: user đã hủy, expired: không thu được tiền).
+// Enrollment ghi danh qua subscription tự mất quyền học (xem repository.entitledSubscriptions).
+func endSubscription(repos *repository.TxRepositories, subscription *models.Subscription, status string, now time.Time) error {
+	if err := repos.Subscriptions.UpdateSubscription(subscription.Id, map[string]interface{}{
+		"status":        status,
+		"ended_at":      now,
+		"grace_ends_at": nil,
+		"next_retry_at": nil,
+	}); err != nil {
+		return err
+	}
+
+	reason := "cancelled"
+	if status == "expired" {
+		reason = "payment_failed"
+	}
+	return repos.Outbox.Append(dto.SubscriptionExpiredEvent{
+		SubscriptionId: subscription.Id,
+		UserId:         subscription.UserId,
+		PlanId:         subscription.PlanId,
+		Reason:         reason,
+		EndedAt:        now,
+	})
+}
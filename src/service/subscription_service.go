@@ -0,0 +1,724 @@
+package service
+
+import (
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"math"
+	"strings"
+	"time"
+)
+
+type subscriptionService struct {
+	subscriptionRepo repository.SubscriptionRepository
+	courseRepo       repository.CourseRepository
+	transactor       repository.Transactor
+	paymentGateway   PaymentGateway
+}
+
+func NewSubscriptionService(
+	subscriptionRepo repository.SubscriptionRepository,
+	courseRepo repository.CourseRepository,
+	transactor repository.Transactor,
+	paymentGateway PaymentGateway,
+) SubscriptionService {
+	return &subscriptionService{
+		subscriptionRepo: subscriptionRepo,
+		courseRepo:       courseRepo,
+		transactor:       transactor,
+		paymentGateway:   paymentGateway,
+	}
+}
+
+// ---------------- Plans ----------------
+// GetPlans trả về các plan đang mở bán
+func (ss *subscriptionService) GetPlans(req *dto.GetSubscriptionPlansQueryRequest) (*dto.GetSubscriptionPlansResponse, error) {
+	filters := map[string]interface{}{"status": "active"}
+	if req.Interval != "" {
+		filters["interval"] = req.Interval
+	}
+
+	plans, err := ss.subscriptionRepo.GetPlans(filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get subscription plans", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.SubscriptionPlanItem, len(plans))
+	for i := range plans {
+		items[i] = toSubscriptionPlanItem(&plans[i])
+	}
+	return &dto.GetSubscriptionPlansResponse{Plans: items}, nil
+}
+
+func (ss *subscriptionService) GetPlan(planId uint) (*dto.SubscriptionPlanItem, error) {
+	plan, err := ss.subscriptionRepo.FindPlanById(planId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get subscription plan", utils.ErrCodeInternal)
+	}
+	if plan == nil || plan.Status != "active" {
+		return nil, utils.NewError("Subscription plan not found", utils.ErrCodeNotFound)
+	}
+
+	item := toSubscriptionPlanItem(plan)
+	return &item, nil
+}
+
+// ---------------- Subscriptions ----------------
+func (ss *subscriptionService) GetMySubscription(userId uint) (*dto.GetMySubscriptionResponse, error) {
+	subscription, err := ss.subscriptionRepo.FindCurrentByUser(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get subscription", utils.ErrCodeInternal)
+	}
+	if subscription == nil {
+		return &dto.GetMySubscriptionResponse{}, nil
+	}
+
+	item := toSubscriptionItem(subscription)
+	return &dto.GetMySubscriptionResponse{Subscription: &item}, nil
+}
+
+// Subscribe đăng ký plan: dùng thử nếu plan có trial và user chưa từng đăng ký, ngược lại thu tiền kỳ đầu ngay
+func (ss *subscriptionService) Subscribe(userId uint, req *dto.SubscribeRequest) (*dto.SubscriptionResponse, error) {
+	// 1. Kiểm tra plan
+	plan, err := ss.subscriptionRepo.FindPlanById(req.PlanId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get subscription plan", utils.ErrCodeInternal)
+	}
+	if plan == nil || plan.Status != "active" {
+		return nil, utils.NewError("Subscription plan not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Mỗi user chỉ có một subscription hiệu lực
+	current, err := ss.subscriptionRepo.FindCurrentByUser(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check subscription", utils.ErrCodeInternal)
+	}
+	if current != nil {
+		return nil, utils.NewError("You already have an active subscription", utils.ErrCodeConflict)
+	}
+
+	// 3. Trial chỉ dành cho user chưa từng đăng ký
+	subscribed, err := ss.subscriptionRepo.HasSubscribed(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check subscription", utils.ErrCodeInternal)
+	}
+
+	now := time.Now()
+	subscription := &models.Subscription{
+		UserId:             userId,
+		PlanId:             plan.Id,
+		Price:              plan.Price,
+		Interval:           plan.Interval,
+		PaymentMethod:      req.PaymentMethod,
+		CurrentPeriodStart: now,
+	}
+
+	// 4. Tạo subscription và thu tiền kỳ đầu trong cùng transaction (thanh toán bị từ chối thì không tạo gì)
+	message := "Subscription started. You can now enroll in every course included in your plan"
+	err = ss.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		if plan.TrialDays > 0 && !subscribed {
+			trialEndsAt := now.AddDate(0, 0, plan.TrialDays)
+			subscription.Status = "trialing"
+			subscription.CurrentPeriodEnd = trialEndsAt
+			subscription.TrialEndsAt = &trialEndsAt
+			message = fmt.Sprintf("Your %d-day free trial has started. You will be charged when it ends", plan.TrialDays)
+			if err := repos.Subscriptions.Create(subscription); err != nil {
+				return utils.WrapError(err, "Failed to create subscription", utils.ErrCodeInternal)
+			}
+			return nil
+		}
+
+		subscription.Status = "active"
+		subscription.CurrentPeriodEnd = nextPeriodEnd(now, plan.Interval)
+		if err := repos.Subscriptions.Create(subscription); err != nil {
+			return utils.WrapError(err, "Failed to create subscription", utils.ErrCodeInternal)
+		}
+
+		payment, err := chargeSubscription(repos, ss.paymentGateway, subscription, now, now)
+		if err != nil {
+			return err
+		}
+		if payment.Status != "paid" {
+			return utils.NewError(fmt.Sprintf("Payment was declined: %s", payment.FailureReason), utils.ErrCodeBadRequest)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.Plan = *plan
+	return &dto.SubscriptionResponse{
+		Subscription: toSubscriptionItem(subscription),
+		Message:      message,
+	}, nil
+}
+
+// CancelSubscription hủy subscription: vẫn truy cập được đến hết kỳ (hoặc hết trial) và không bị thu tiền nữa.
+// Subscription đang past_due thì kết thúc ngay.
+func (ss *subscriptionService) CancelSubscription(userId uint) (*dto.SubscriptionResponse, error) {
+	subscription, err := ss.findCurrent(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	message := fmt.Sprintf("Subscription cancelled. You keep access until %s", subscription.CurrentPeriodEnd.Format("2006-01-02"))
+	err = ss.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		locked, err := repos.Subscriptions.LockSubscription(subscription.Id)
+		if err != nil {
+			return utils.WrapError(err, "Failed to lock subscription", utils.ErrCodeInternal)
+		}
+		if locked.CancelAtPeriodEnd {
+			return utils.NewError("Subscription is already cancelled", utils.ErrCodeBadRequest)
+		}
+
+		updates := map[string]interface{}{"cancelled_at": now}
+		accessEndsAt := locked.CurrentPeriodEnd
+		switch locked.Status {
+		case "trialing", "active":
+			updates["cancel_at_period_end"] = true
+			subscription.CancelAtPeriodEnd = true
+		case "past_due":
+			updates["status"] = "cancelled"
+			updates["ended_at"] = now
+			updates["grace_ends_at"] = nil
+			updates["next_retry_at"] = nil
+			subscription.Status = "cancelled"
+			subscription.EndedAt = &now
+			subscription.GraceEndsAt = nil
+			accessEndsAt = now
+			message = "Subscription cancelled. Your access has ended"
+		default:
+			return utils.NewError("Subscription has already ended", utils.ErrCodeBadRequest)
+		}
+
+		if err := repos.Subscriptions.UpdateSubscription(subscription.Id, updates); err != nil {
+			return utils.WrapError(err, "Failed to cancel subscription", utils.ErrCodeInternal)
+		}
+		subscription.CancelledAt = &now
+
+		if err := repos.Outbox.Append(dto.SubscriptionCancelledEvent{
+			SubscriptionId: subscription.Id,
+			UserId:         subscription.UserId,
+			PlanId:         subscription.PlanId,
+			CancelledAt:    now,
+			AccessEndsAt:   accessEndsAt,
+		}); err != nil {
+			return utils.WrapError(err, "Failed to record subscription event", utils.ErrCodeInternal)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.SubscriptionResponse{
+		Subscription: toSubscriptionItem(subscription),
+		Message:      message,
+	}, nil
+}
+
+// ResumeSubscription bỏ hủy khi subscription chưa hết kỳ, kỳ sau được gia hạn như bình thường
+func (ss *subscriptionService) ResumeSubscription(userId uint) (*dto.SubscriptionResponse, error) {
+	subscription, err := ss.findCurrent(userId)
+	if err != nil {
+		return nil, err
+	}
+	if !subscription.CancelAtPeriodEnd {
+		return nil, utils.NewError("Subscription is not scheduled to cancel", utils.ErrCodeBadRequest)
+	}
+
+	if err := ss.subscriptionRepo.UpdateSubscription(subscription.Id, map[string]interface{}{
+		"cancel_at_period_end": false,
+		"cancelled_at":         nil,
+	}); err != nil {
+		return nil, utils.WrapError(err, "Failed to resume subscription", utils.ErrCodeInternal)
+	}
+	subscription.CancelAtPeriodEnd = false
+	subscription.CancelledAt = nil
+
+	return &dto.SubscriptionResponse{
+		Subscription: toSubscriptionItem(subscription),
+		Message:      "Subscription resumed. It will renew automatically",
+	}, nil
+}
+
+// RetryPayment thu lại tiền cho subscription past_due (có thể đổi phương thức thanh toán), thành công thì bắt đầu kỳ mới từ bây giờ
+func (ss *subscriptionService) RetryPayment(userId uint, req *dto.RetrySubscriptionPaymentRequest) (*dto.SubscriptionResponse, error) {
+	subscription, err := ss.findCurrent(userId)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.Status != "past_due" {
+		return nil, utils.NewError("Subscription has no outstanding payment", utils.ErrCodeBadRequest)
+	}
+
+	now := time.Now()
+	var payment *models.SubscriptionPayment
+	err = ss.transactor.WithinTransaction(func(repos *repository.TxRepositories) error {
+		locked, err := repos.Subscriptions.LockSubscription(subscription.Id)
+		if err != nil {
+			return utils.WrapError(err, "Failed to lock subscription", utils.ErrCodeInternal)
+		}
+		if locked.Status != "past_due" {
+			return utils.NewError("Subscription has no outstanding payment", utils.ErrCodeConflict)
+		}
+
+		if req.PaymentMethod != "" && req.PaymentMethod != locked.PaymentMethod {
+			if err := repos.Subscriptions.UpdateSubscription(locked.Id, map[string]interface{}{
+				"payment_method": req.PaymentMethod,
+			}); err != nil {
+				return utils.WrapError(err, "Failed to update payment method", utils.ErrCodeInternal)
+			}
+			locked.PaymentMethod = req.PaymentMethod
+		}
+
+		// Lần thu thất bại vẫn được ghi lại, subscription giữ past_due đến hết thời gian gia hạn
+		payment, err = chargeSubscription(repos, ss.paymentGateway, locked, now, now)
+		subscription = locked
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != "paid" {
+		return nil, utils.NewError(fmt.Sprintf("Payment was declined: %s", payment.FailureReason), utils.ErrCodeBadRequest)
+	}
+
+	if plan, err := ss.subscriptionRepo.FindPlanById(subscription.PlanId); err == nil && plan != nil {
+		subscription.Plan = *plan
+	}
+	return &dto.SubscriptionResponse{
+		Subscription: toSubscriptionItem(subscription),
+		Message:      "Payment successful! Your subscription is active again",
+	}, nil
+}
+
+func (ss *subscriptionService) GetMyPayments(userId uint, req *dto.GetSubscriptionPaymentsQueryRequest) (*dto.GetSubscriptionPaymentsResponse, error) {
+	// 1. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	// 2. Lấy lịch sử thanh toán
+	payments, total, err := ss.subscriptionRepo.GetPayments(userId, offset, limit)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get subscription payments", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.SubscriptionPaymentItem, len(payments))
+	for i := range payments {
+		items[i] = toSubscriptionPaymentItem(&payments[i])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetSubscriptionPaymentsResponse{
+		Payments: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+// ---------------- Admin ----------------
+func (ss *subscriptionService) AdminGetPlans(req *dto.GetSubscriptionPlansQueryRequest) (*dto.GetSubscriptionPlansResponse, error) {
+	filters := make(map[string]interface{})
+	if req.Status != "" {
+		filters["status"] = req.Status
+	}
+	if req.Interval != "" {
+		filters["interval"] = req.Interval
+	}
+
+	plans, err := ss.subscriptionRepo.GetPlans(filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get subscription plans", utils.ErrCodeInternal)
+	}
+
+	planIds := make([]uint, len(plans))
+	for i := range plans {
+		planIds[i] = plans[i].Id
+	}
+	subscribers, err := ss.subscriptionRepo.CountSubscribers(planIds)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to count subscribers", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.SubscriptionPlanItem, len(plans))
+	for i := range plans {
+		items[i] = toSubscriptionPlanItem(&plans[i])
+		count := subscribers[plans[i].Id]
+		items[i].Subscribers = &count
+	}
+	return &dto.GetSubscriptionPlansResponse{Plans: items}, nil
+}
+
+func (ss *subscriptionService) CreatePlan(req *dto.CreateSubscriptionPlanRequest) (*dto.SubscriptionPlanItem, error) {
+	// 1. Plan tagged phải có ít nhất một tag, plan all không dùng tag
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if req.Scope == "tagged" && len(tags) == 0 {
+		return nil, utils.NewError("A tagged plan needs at least one tag", utils.ErrCodeBadRequest)
+	}
+	if req.Scope == "all" {
+		tags = nil
+	}
+
+	// 2. Tạo plan cùng tag
+	plan := &models.SubscriptionPlan{
+		Name:            strings.TrimSpace(req.Name),
+		Description:     req.Description,
+		Interval:        req.Interval,
+		Price:           math.Round(req.Price*100) / 100,
+		TrialDays:       req.TrialDays,
+		GracePeriodDays: req.GracePeriodDays,
+		Scope:           req.Scope,
+		Status:          "active",
+	}
+	for _, tag := range tags {
+		plan.Tags = append(plan.Tags, models.SubscriptionPlanTag{Tag: tag})
+	}
+
+	if err := ss.subscriptionRepo.CreatePlan(plan); err != nil {
+		return nil, utils.WrapError(err, "Failed to create subscription plan", utils.ErrCodeInternal)
+	}
+
+	item := toSubscriptionPlanItem(plan)
+	return &item, nil
+}
+
+func (ss *subscriptionService) UpdatePlan(planId uint, req *dto.UpdateSubscriptionPlanRequest) (*dto.SubscriptionPlanItem, error) {
+	// 1. Tìm plan
+	plan, err := ss.subscriptionRepo.FindPlanById(planId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get subscription plan", utils.ErrCodeInternal)
+	}
+	if plan == nil {
+		return nil, utils.NewError("Subscription plan not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Chuẩn bị thay đổi
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		updates["name"] = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.Price != nil {
+		updates["price"] = math.Round(*req.Price*100) / 100
+	}
+	if req.TrialDays != nil {
+		updates["trial_days"] = *req.TrialDays
+	}
+	if req.GracePeriodDays != nil {
+		updates["grace_period_days"] = *req.GracePeriodDays
+	}
+	if req.Status != nil {
+		updates["status"] = *req.Status
+	}
+
+	scope := plan.Scope
+	if req.Scope != nil {
+		scope = *req.Scope
+		updates["scope"] = scope
+	}
+
+	// 3. Tag: giữ tag cũ nếu không gửi; plan tagged phải còn ít nhất một tag
+	replaceTags := req.Tags != nil || scope == "all"
+	tags := make([]string, len(plan.Tags))
+	for i, tag := range plan.Tags {
+		tags[i] = tag.Tag
+	}
+	if req.Tags != nil {
+		if tags, err = normalizeTags(req.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if scope == "all" {
+		tags = nil
+	} else if len(tags) == 0 {
+		return nil, utils.NewError("A tagged plan needs at least one tag", utils.ErrCodeBadRequest)
+	}
+
+	// 4. Lưu thay đổi
+	if len(updates) > 0 {
+		if err := ss.subscriptionRepo.UpdatePlan(planId, updates); err != nil {
+			return nil, utils.WrapError(err, "Failed to update subscription plan", utils.ErrCodeInternal)
+		}
+	}
+	if replaceTags {
+		if err := ss.subscriptionRepo.ReplacePlanTags(planId, tags); err != nil {
+			return nil, utils.WrapError(err, "Failed to update plan tags", utils.ErrCodeInternal)
+		}
+	}
+
+	updated, err := ss.subscriptionRepo.FindPlanById(planId)
+	if err != nil || updated == nil {
+		return nil, utils.WrapError(err, "Failed to get subscription plan", utils.ErrCodeInternal)
+	}
+	item := toSubscriptionPlanItem(updated)
+	return &item, nil
+}
+
+func (ss *subscriptionService) AdminGetSubscriptions(req *dto.GetSubscriptionsQueryRequest) (*dto.GetSubscriptionsResponse, error) {
+	// 1. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	// 2. Prepare filters
+	filters := make(map[string]interface{})
+	if req.Status != "" {
+		filters["status"] = req.Status
+	}
+	if req.PlanId > 0 {
+		filters["plan_id"] = req.PlanId
+	}
+	if req.UserId > 0 {
+		filters["user_id"] = req.UserId
+	}
+
+	// 3. Lấy danh sách subscription
+	subscriptions, total, err := ss.subscriptionRepo.GetSubscriptions(offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get subscriptions", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.SubscriptionItem, len(subscriptions))
+	for i := range subscriptions {
+		items[i] = toSubscriptionItem(&subscriptions[i])
+		items[i].Username = subscriptions[i].User.Username
+		items[i].Email = subscriptions[i].User.Email
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetSubscriptionsResponse{
+		Subscriptions: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (ss *subscriptionService) GetCourseTags(courseId uint) (*dto.CourseTagsResponse, error) {
+	if _, err := ss.courseRepo.FindById(courseId); err != nil {
+		return nil, utils.NewError("Course not found", utils.ErrCodeNotFound)
+	}
+
+	tags, err := ss.subscriptionRepo.GetCourseTags(courseId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get course tags", utils.ErrCodeInternal)
+	}
+	return &dto.CourseTagsResponse{CourseId: courseId, Tags: tags}, nil
+}
+
+// SetCourseTags thay toàn bộ tag của course, quyết định course thuộc các plan tagged nào
+func (ss *subscriptionService) SetCourseTags(courseId uint, req *dto.SetCourseTagsRequest) (*dto.CourseTagsResponse, error) {
+	if _, err := ss.courseRepo.FindById(courseId); err != nil {
+		return nil, utils.NewError("Course not found", utils.ErrCodeNotFound)
+	}
+
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := ss.subscriptionRepo.ReplaceCourseTags(courseId, tags); err != nil {
+		return nil, utils.WrapError(err, "Failed to update course tags", utils.ErrCodeInternal)
+	}
+	return &dto.CourseTagsResponse{CourseId: courseId, Tags: tags}, nil
+}
+
+// ---------------- Helpers ----------------
+func (ss *subscriptionService) findCurrent(userId uint) (*models.Subscription, error) {
+	subscription, err := ss.subscriptionRepo.FindCurrentByUser(userId)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get subscription", utils.ErrCodeInternal)
+	}
+	if subscription == nil {
+		return nil, utils.NewError("You don't have an active subscription", utils.ErrCodeNotFound)
+	}
+	return subscription, nil
+}
+
+// nextPeriodEnd tính thời điểm kết thúc kỳ bắt đầu từ start theo chu kỳ của plan
+func nextPeriodEnd(start time.Time, interval string) time.Time {
+	if interval == "year" {
+		return start.AddDate(1, 0, 0)
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+// chargeSubscription thu tiền một kỳ bắt đầu từ periodStart qua payment gateway và ghi lại payment.
+// Thành công: subscription chuyển active, sang kỳ mới, ghi SubscriptionPaidEvent.
+// Bị từ chối: chỉ ghi payment failed, người gọi quyết định trạng thái subscription.
+// Phải gọi trong transaction đang khóa subscription để không thu tiền hai lần.
+func chargeSubscription(repos *repository.TxRepositories, gateway PaymentGateway, subscription *models.Subscription, periodStart, now time.Time) (*models.SubscriptionPayment, error) {
+	periodEnd := nextPeriodEnd(periodStart, subscription.Interval)
+	payment := &models.SubscriptionPayment{
+		TenantId:       subscription.TenantId,
+		SubscriptionId: subscription.Id,
+		UserId:         subscription.UserId,
+		PlanId:         subscription.PlanId,
+		Amount:         subscription.Price,
+		PeriodStart:    periodStart,
+		PeriodEnd:      periodEnd,
+		Status:         "paid",
+		PaymentMethod:  subscription.PaymentMethod,
+	}
+
+	// 1. Plan miễn phí không cần gọi gateway
+	if subscription.Price > 0 {
+		transactionId, err := gateway.Charge(subscription.PaymentMethod, subscription.Price, fmt.Sprintf("subscription-%d", subscription.Id))
+		if err != nil {
+			payment.Status = "failed"
+			payment.FailureReason = err.Error()
+		}
+		payment.TransactionId = transactionId
+	}
+	if payment.Status == "paid" {
+		payment.PaidAt = &now
+	}
+
+	// 2. Ghi payment (kể cả thất bại)
+	if err := repos.Subscriptions.CreatePayment(payment); err != nil {
+		return nil, utils.WrapError(err, "Failed to record subscription payment", utils.ErrCodeInternal)
+	}
+	if payment.Status != "paid" {
+		return payment, nil
+	}
+
+	// 3. Sang kỳ mới
+	if err := repos.Subscriptions.UpdateSubscription(subscription.Id, map[string]interface{}{
+		"status":               "active",
+		"current_period_start": periodStart,
+		"current_period_end":   periodEnd,
+		"grace_ends_at":        nil,
+		"next_retry_at":        nil,
+	}); err != nil {
+		return nil, utils.WrapError(err, "Failed to renew subscription", utils.ErrCodeInternal)
+	}
+	subscription.Status = "active"
+	subscription.CurrentPeriodStart = periodStart
+	subscription.CurrentPeriodEnd = periodEnd
+	subscription.GraceEndsAt = nil
+	subscription.NextRetryAt = nil
+
+	if err := repos.Outbox.Append(dto.SubscriptionPaidEvent{
+		SubscriptionId: subscription.Id,
+		PaymentId:      payment.Id,
+		UserId:         subscription.UserId,
+		PlanId:         subscription.PlanId,
+		Amount:         payment.Amount,
+		PaymentMethod:  payment.PaymentMethod,
+		PeriodStart:    periodStart,
+		PeriodEnd:      periodEnd,
+		PaidAt:         now,
+	}); err != nil {
+		return nil, utils.WrapError(err, "Failed to record subscription event", utils.ErrCodeInternal)
+	}
+	return payment, nil
+}
+
+// normalizeTags chuẩn hóa tag thành dạng slug (không dấu, chữ thường) và bỏ trùng
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		slug := utils.GenerateSlug(tag)
+		if slug == "" {
+			return nil, utils.NewError(fmt.Sprintf("Invalid tag \"%s\"", tag), utils.ErrCodeBadRequest)
+		}
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		normalized = append(normalized, slug)
+	}
+	return normalized, nil
+}
+
+func toSubscriptionPlanItem(plan *models.SubscriptionPlan) dto.SubscriptionPlanItem {
+	tags := make([]string, len(plan.Tags))
+	for i, tag := range plan.Tags {
+		tags[i] = tag.Tag
+	}
+
+	return dto.SubscriptionPlanItem{
+		Id:              plan.Id,
+		Name:            plan.Name,
+		Description:     plan.Description,
+		Interval:        plan.Interval,
+		Price:           plan.Price,
+		TrialDays:       plan.TrialDays,
+		GracePeriodDays: plan.GracePeriodDays,
+		Scope:           plan.Scope,
+		Tags:            tags,
+		Status:          plan.Status,
+		CreatedAt:       plan.CreatedAt,
+	}
+}
+
+func toSubscriptionItem(subscription *models.Subscription) dto.SubscriptionItem {
+	return dto.SubscriptionItem{
+		Id:                 subscription.Id,
+		UserId:             subscription.UserId,
+		Plan:               toSubscriptionPlanItem(&subscription.Plan),
+		Status:             subscription.Status,
+		Price:              subscription.Price,
+		Interval:           subscription.Interval,
+		PaymentMethod:      subscription.PaymentMethod,
+		CurrentPeriodStart: subscription.CurrentPeriodStart,
+		CurrentPeriodEnd:   subscription.CurrentPeriodEnd,
+		TrialEndsAt:        subscription.TrialEndsAt,
+		GraceEndsAt:        subscription.GraceEndsAt,
+		CancelAtPeriodEnd:  subscription.CancelAtPeriodEnd,
+		CancelledAt:        subscription.CancelledAt,
+		EndedAt:            subscription.EndedAt,
+		CreatedAt:          subscription.CreatedAt,
+	}
+}
+
+func toSubscriptionPaymentItem(payment *models.SubscriptionPayment) dto.SubscriptionPaymentItem {
+	return dto.SubscriptionPaymentItem{
+		Id:             payment.Id,
+		SubscriptionId: payment.SubscriptionId,
+		PlanId:         payment.PlanId,
+		Amount:         payment.Amount,
+		PeriodStart:    payment.PeriodStart,
+		PeriodEnd:      payment.PeriodEnd,
+		Status:         payment.Status,
+		PaymentMethod:  payment.PaymentMethod,
+		TransactionId:  payment.TransactionId,
+		FailureReason:  payment.FailureReason,
+		PaidAt:         payment.PaidAt,
+		CreatedAt:      payment.CreatedAt,
+	}
+}
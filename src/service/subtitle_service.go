@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Ngôn ngữ phụ đề được hỗ trợ và label mặc định
+var subtitleLanguages = map[string]string{
+	"vi": "Tiếng Việt",
+	"en": "English",
+}
+
+type subtitleService struct {
+	subtitleRepo   repository.SubtitleRepository
+	instructorRepo repository.InstructorRepository
+	store          storage.Storage
+}
+
+func NewSubtitleService(subtitleRepo repository.SubtitleRepository, instructorRepo repository.InstructorRepository, store storage.Storage) SubtitleService {
+	return &subtitleService{
+		subtitleRepo:   subtitleRepo,
+		instructorRepo: instructorRepo,
+		store:          store,
+	}
+}
+
+func (ss *subtitleService) UploadSubtitle(instructorId, courseId, lessonId uint, language, label string, file *multipart.FileHeader) (*dto.LessonSubtitleItem, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	if _, err := ss.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Kiểm tra lesson có thuộc về course không
+	if _, err := ss.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId); err != nil {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	// 3. Validate ngôn ngữ và file
+	defaultLabel, ok := subtitleLanguages[language]
+	if !ok {
+		return nil, utils.NewError("Language must be one of: vi, en", utils.ErrCodeBadRequest)
+	}
+
+	label = strings.TrimSpace(label)
+	if label == "" {
+		label = defaultLabel
+	}
+	if len(label) > 50 {
+		return nil, utils.NewError("Label must be at most 50 characters", utils.ErrCodeBadRequest)
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(file.Filename)), ".")
+	if format != "vtt" && format != "srt" {
+		return nil, utils.NewError("Subtitle must be a WebVTT (.vtt) or SRT (.srt) file", utils.ErrCodeBadRequest)
+	}
+	if file.Size > utils.MaxSubtitleSize {
+		return nil, utils.NewError("Subtitle file too large (max 2MB)", utils.ErrCodeBadRequest)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to read subtitle file", utils.ErrCodeBadRequest)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to read subtitle file", utils.ErrCodeBadRequest)
+	}
+	if !utf8.Valid(data) {
+		return nil, utils.NewError("Subtitle file must be UTF-8 encoded", utils.ErrCodeBadRequest)
+	}
+
+	// 4. Parse cues, SRT được chuyển sang WebVTT
+	var cues []utils.SubtitleCue
+	vtt := data
+	if format == "srt" {
+		vtt, cues, err = utils.ConvertSRTToVTT(data)
+	} else {
+		cues, err = utils.ParseVTT(data)
+	}
+	if err != nil {
+		return nil, utils.WrapError(err, "Invalid subtitle file", utils.ErrCodeBadRequest)
+	}
+
+	// 5. Lưu file WebVTT (private) vào storage
+	existing, _ := ss.subtitleRepo.FindByLessonAndLanguage(lessonId, language)
+
+	key := fmt.Sprintf("%s%s-%s.vtt", lessonSubtitlePrefix(lessonId), language, uuid.New().String())
+	if err := ss.store.Put(key, bytes.NewReader(vtt), int64(len(vtt)), "text/vtt; charset=utf-8"); err != nil {
+		return nil, utils.WrapError(err, "Failed to upload subtitle", utils.ErrCodeInternal)
+	}
+
+	// 6. Thay subtitle cũ cùng ngôn ngữ và index transcript
+	subtitle := &models.LessonSubtitle{
+		LessonId:     lessonId,
+		Language:     language,
+		Label:        label,
+		SourceFormat: format,
+		StorageKey:   key,
+		CueCount:     len(cues),
+	}
+
+	transcript := make([]models.TranscriptCue, 0, len(cues))
+	for _, cue := range cues {
+		text := utils.CueText(cue.Text)
+		if text == "" {
+			continue
+		}
+		transcript = append(transcript, models.TranscriptCue{
+			LessonId: lessonId,
+			Language: language,
+			StartMs:  cue.StartMs,
+			EndMs:    cue.EndMs,
+			Text:     text,
+		})
+	}
+
+	if err := ss.subtitleRepo.ReplaceSubtitle(subtitle, transcript); err != nil {
+		ss.store.Delete(key)
+		return nil, utils.WrapError(err, "Failed to save subtitle", utils.ErrCodeInternal)
+	}
+
+	if existing != nil {
+		if err := ss.store.Delete(existing.StorageKey); err != nil {
+			log.Printf("Failed to delete subtitle file %s: %v", existing.StorageKey, err)
+		}
+	}
+
+	item := toLessonSubtitleItem(ss.store, subtitle)
+	return &item, nil
+}
+
+func (ss *subtitleService) DeleteSubtitle(instructorId, courseId, lessonId, subtitleId uint) (*dto.DeleteSubtitleResponse, error) {
+	// 1. Kiểm tra course có tồn tại và thuộc về instructor không
+	if _, err := ss.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Kiểm tra subtitle có thuộc về lesson của course không
+	if _, err := ss.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId); err != nil {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	subtitle, err := ss.subtitleRepo.FindById(subtitleId)
+	if err != nil || subtitle.LessonId != lessonId {
+		return nil, utils.NewError("Subtitle not found", utils.ErrCodeNotFound)
+	}
+
+	// 3. Xóa subtitle, transcript và file trên storage
+	if err := ss.subtitleRepo.Delete(subtitleId); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete subtitle", utils.ErrCodeInternal)
+	}
+
+	if err := ss.store.Delete(subtitle.StorageKey); err != nil {
+		log.Printf("Failed to delete subtitle file %s: %v", subtitle.StorageKey, err)
+	}
+
+	return &dto.DeleteSubtitleResponse{
+		Message: "Subtitle deleted successfully",
+		Id:      subtitleId,
+	}, nil
+}
+
+// lessonSubtitlePrefix prefix (private) chứa file phụ đề của một lesson trên storage
+func lessonSubtitlePrefix(lessonId uint) string {
+	return fmt.Sprintf("%ssubtitles/%d/", storage.PrivatePrefix, lessonId)
+}
+
+// toLessonSubtitleItem kèm presigned URL của file WebVTT cho video player
+func toLessonSubtitleItem(store storage.Storage, subtitle *models.LessonSubtitle) dto.LessonSubtitleItem {
+	url, err := store.PresignGet(subtitle.StorageKey, playbackURLTTL)
+	if err != nil {
+		log.Printf("Failed to sign subtitle %d: %v", subtitle.Id, err)
+	}
+
+	return dto.LessonSubtitleItem{
+		Id:           subtitle.Id,
+		Language:     subtitle.Language,
+		Label:        subtitle.Label,
+		SourceFormat: subtitle.SourceFormat,
+		CueCount:     subtitle.CueCount,
+		URL:          url,
+		CreatedAt:    subtitle.CreatedAt,
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type cachedTenant struct {
+	tenant    *models.Tenant
+	expiresAt time.Time
+}
+
+// TenantResolver xác định tenant của request (header X-Tenant hoặc hostname) và cache kết quả
+// để không phải query DB ở mỗi request. Thay đổi tenant/domain phải gọi Invalidate.
+type TenantResolver struct {
+	tenantRepo repository.TenantRepository
+	ttl        time.Duration
+	mu         sync.RWMutex
+	cache      map[string]cachedTenant
+}
+
+func NewTenantResolver(tenantRepo repository.TenantRepository) *TenantResolver {
+	ttlSeconds, err := strconv.Atoi(utils.GetEnv("TENANT_CACHE_TTL_SECONDS", "60"))
+	if err != nil || ttlSeconds < 0 {
+		ttlSeconds = 60
+	}
+
+	return &TenantResolver{
+		tenantRepo: tenantRepo,
+		ttl:        time.Duration(ttlSeconds) * time.Second,
+		cache:      make(map[string]cachedTenant),
+	}
+}
+
+// Resolve ưu tiên slug trong header, sau đó tới hostname; hostname chưa đăng ký thuộc tenant mặc định
+func (tr *TenantResolver) Resolve(slug, host string) (*models.Tenant, error) {
+	// 1. Header X-Tenant chỉ rõ tenant nên slug sai là lỗi
+	if slug = strings.ToLower(strings.TrimSpace(slug)); slug != "" {
+		tenant, err := tr.load("slug:"+slug, func() (*models.Tenant, error) {
+			return tr.tenantRepo.FindBySlug(slug)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if tenant == nil {
+			return nil, utils.NewError("Tenant not found", utils.ErrCodeNotFound)
+		}
+		return tr.requireActive(tenant)
+	}
+
+	// 2. Theo hostname (bỏ port)
+	hostname := strings.ToLower(host)
+	if i := strings.LastIndex(hostname, ":"); i != -1 && !strings.HasSuffix(hostname, "]") {
+		hostname = hostname[:i]
+	}
+	tenant, err := tr.load("host:"+hostname, func() (*models.Tenant, error) {
+		tenant, err := tr.tenantRepo.FindByHostname(hostname)
+		if err != nil || tenant != nil {
+			return tenant, err
+		}
+		return tr.tenantRepo.FindById(models.DefaultTenantId)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tr.requireActive(tenant)
+}
+
+// Get trả về tenant theo id (dùng khi gửi email, dựng link frontend...)
+func (tr *TenantResolver) Get(tenantId uint) (*models.Tenant, error) {
+	return tr.load("id:"+strconv.FormatUint(uint64(tenantId), 10), func() (*models.Tenant, error) {
+		return tr.tenantRepo.FindById(tenantId)
+	})
+}
+
+// Invalidate xóa cache sau khi tenant hoặc domain thay đổi
+func (tr *TenantResolver) Invalidate() {
+	tr.mu.Lock()
+	tr.cache = make(map[string]cachedTenant)
+	tr.mu.Unlock()
+}
+
+func (tr *TenantResolver) load(key string, find func() (*models.Tenant, error)) (*models.Tenant, error) {
+	tr.mu.RLock()
+	entry, ok := tr.cache[key]
+	tr.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.tenant, nil
+	}
+
+	tenant, err := find()
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to resolve tenant", utils.ErrCodeInternal)
+	}
+	if tenant != nil {
+		tr.mu.Lock()
+		tr.cache[key] = cachedTenant{tenant: tenant, expiresAt: time.Now().Add(tr.ttl)}
+		tr.mu.Unlock()
+	}
+	return tenant, nil
+}
+
+func (tr *TenantResolver) requireActive(tenant *models.Tenant) (*models.Tenant, error) {
+	if tenant.Status != "active" {
+		return nil, utils.NewError("Tenant is suspended", utils.ErrCodeForbidden)
+	}
+	return tenant, nil
+}
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"encoding/json"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"math"
+	"strings"
+	"time"
+)
+
+type tenantService struct {
+	tenantRepo     repository.TenantRepository
+	tenantResolver *TenantResolver
+}
+
+func NewTenantService(tenantRepo repository.TenantRepository, tenantResolver *TenantResolver) TenantService {
+	return &tenantService{
+		tenantRepo:     tenantRepo,
+		tenantResolver: tenantResolver,
+	}
+}
+
+// ---------------- Branding & settings (tenant hiện tại) ----------------
+func (ts *tenantService) GetBranding(tenantId uint) (*dto.TenantBrandingResponse, error) {
+	tenant, err := ts.findTenant(tenantId)
+	if err != nil {
+		return nil, err
+	}
+	branding := toTenantBranding(tenant)
+	return &branding, nil
+}
+
+func (ts *tenantService) GetSettings(tenantId uint) (*dto.TenantSettingsResponse, error) {
+	tenant, err := ts.findTenant(tenantId)
+	if err != nil {
+		return nil, err
+	}
+	return toTenantSettings(tenant), nil
+}
+
+func (ts *tenantService) UpdateSettings(tenantId uint, req *dto.UpdateTenantSettingsRequest) (*dto.TenantSettingsResponse, error) {
+	// 1. Kiểm tra tenant
+	tenant, err := ts.findTenant(tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Chuẩn bị dữ liệu cập nhật
+	updates := make(map[string]interface{})
+	stringFields := []struct {
+		column string
+		value  *string
+		target *string
+	}{
+		{"name", req.Name, &tenant.Name},
+		{"frontend_url", req.FrontendURL, &tenant.FrontendURL},
+		{"email_from_name", req.EmailFromName, &tenant.EmailFromName},
+		{"email_from_address", req.EmailFromAddress, &tenant.EmailFromAddress},
+		{"support_email", req.SupportEmail, &tenant.SupportEmail},
+		{"logo_url", req.LogoURL, &tenant.LogoURL},
+		{"favicon_url", req.FaviconURL, &tenant.FaviconURL},
+		{"primary_color", req.PrimaryColor, &tenant.PrimaryColor},
+		{"secondary_color", req.SecondaryColor, &tenant.SecondaryColor},
+		{"xapi_lrs_key", req.XapiLrsKey, &tenant.XapiLrsKey},
+	}
+	for _, field := range stringFields {
+		if field.value != nil {
+			*field.target = strings.TrimSpace(*field.value)
+			updates[field.column] = *field.target
+		}
+	}
+	if req.Name != nil && tenant.Name == "" {
+		return nil, utils.NewError("Tenant name cannot be empty", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Settings phải là JSON object
+	if req.Settings != nil {
+		var settings map[string]interface{}
+		if err := json.Unmarshal(*req.Settings, &settings); err != nil || settings == nil {
+			return nil, utils.NewError("Settings must be a JSON object", utils.ErrCodeBadRequest)
+		}
+		tenant.Settings = string(*req.Settings)
+		updates["settings"] = tenant.Settings
+	}
+
+	// 4. Secret của LRS tích hợp chỉ lưu hash
+	if req.XapiLrsSecret != nil {
+		tenant.XapiLrsSecretHash = ""
+		if *req.XapiLrsSecret != "" {
+			tenant.XapiLrsSecretHash = utils.HashToken(*req.XapiLrsSecret)
+		}
+		updates["xapi_lrs_secret_hash"] = tenant.XapiLrsSecretHash
+	}
+
+	// 5. Lưu và làm mới cache (email, branding, LRS credentials đọc từ cache)
+	if len(updates) > 0 {
+		if err := ts.tenantRepo.UpdateTenant(tenantId, updates); err != nil {
+			return nil, utils.WrapError(err, "Failed to update tenant settings", utils.ErrCodeInternal)
+		}
+		ts.tenantResolver.Invalidate()
+	}
+
+	return toTenantSettings(tenant), nil
+}
+
+// ---------------- Super-admin: tenants ----------------
+func (ts *tenantService) CreateTenant(req *dto.CreateTenantRequest) (*dto.TenantItem, error) {
+	// 1. Slug dùng trong header X-Tenant nên phải duy nhất
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if ts.tenantRepo.SlugExists(slug) {
+		return nil, utils.NewError("Tenant slug already exists", utils.ErrCodeConflict)
+	}
+
+	// 2. Mỗi hostname chỉ trỏ về một tenant
+	domains := make([]models.TenantDomain, 0, len(req.Domains))
+	seen := make(map[string]bool)
+	for _, hostname := range req.Domains {
+		hostname = strings.ToLower(strings.TrimSpace(hostname))
+		if seen[hostname] {
+			continue
+		}
+		if ts.tenantRepo.HostnameExists(hostname) {
+			return nil, utils.NewError("Domain "+hostname+" is already in use", utils.ErrCodeConflict)
+		}
+		seen[hostname] = true
+		domains = append(domains, models.TenantDomain{Hostname: hostname})
+	}
+
+	// 3. Tài khoản admin đầu tiên của tenant
+	hashedPassword, err := utils.HashPassword(req.AdminPassword)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to hash password", utils.ErrCodeInternal)
+	}
+	admin := &models.User{
+		Username:      utils.NormalizeString(req.AdminUsername),
+		Email:         utils.NormalizeString(req.AdminEmail),
+		Password:      hashedPassword,
+		FullName:      strings.TrimSpace(req.AdminFullName),
+		Role:          "admin",
+		Status:        "active",
+		EmailVerified: true,
+	}
+
+	// 4. Lưu tenant, domain và admin trong một transaction
+	tenant := &models.Tenant{
+		Slug:        slug,
+		Name:        strings.TrimSpace(req.Name),
+		Status:      "active",
+		FrontendURL: strings.TrimSpace(req.FrontendURL),
+		Settings:    "{}",
+		Domains:     domains,
+	}
+	if err := ts.tenantRepo.CreateTenant(tenant, admin); err != nil {
+		return nil, utils.WrapError(err, "Failed to create tenant", utils.ErrCodeInternal)
+	}
+	ts.tenantResolver.Invalidate()
+
+	item := toTenantItem(tenant)
+	return &item, nil
+}
+
+func (ts *tenantService) GetTenants(req *dto.GetTenantsQueryRequest) (*dto.GetTenantsResponse, error) {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	offset := (req.Page - 1) * req.Limit
+
+	filters := make(map[string]interface{})
+	if req.Status != "" {
+		filters["status"] = req.Status
+	}
+	if req.Search != "" {
+		filters["search"] = req.Search
+	}
+
+	tenants, total, err := ts.tenantRepo.GetTenants(offset, req.Limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get tenants", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.TenantItem, len(tenants))
+	for i := range tenants {
+		items[i] = toTenantItem(&tenants[i])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(req.Limit)))
+	return &dto.GetTenantsResponse{
+		Tenants: items,
+		Pagination: dto.PaginationInfo{
+			Page:       req.Page,
+			Limit:      req.Limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    req.Page < totalPages,
+			HasPrev:    req.Page > 1,
+		},
+	}, nil
+}
+
+func (ts *tenantService) GetTenant(tenantId uint) (*dto.TenantItem, error) {
+	tenant, err := ts.findTenant(tenantId)
+	if err != nil {
+		return nil, err
+	}
+	item := toTenantItem(tenant)
+	return &item, nil
+}
+
+func (ts *tenantService) UpdateTenant(tenantId uint, req *dto.UpdateTenantRequest) (*dto.TenantItem, error) {
+	// 1. Kiểm tra tenant
+	tenant, err := ts.findTenant(tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Tenant mặc định chứa tài khoản super-admin nên không được tạm ngưng
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		tenant.Name = strings.TrimSpace(*req.Name)
+		updates["name"] = tenant.Name
+	}
+	if req.Status != nil {
+		if tenant.Id == models.DefaultTenantId && *req.Status != "active" {
+			return nil, utils.NewError("The default tenant cannot be suspended", utils.ErrCodeBadRequest)
+		}
+		tenant.Status = *req.Status
+		updates["status"] = tenant.Status
+	}
+
+	// 3. Lưu và làm mới cache để request tiếp theo thấy trạng thái mới
+	if len(updates) > 0 {
+		if err := ts.tenantRepo.UpdateTenant(tenantId, updates); err != nil {
+			return nil, utils.WrapError(err, "Failed to update tenant", utils.ErrCodeInternal)
+		}
+		ts.tenantResolver.Invalidate()
+	}
+
+	item := toTenantItem(tenant)
+	return &item, nil
+}
+
+func (ts *tenantService) AddDomain(tenantId uint, req *dto.AddTenantDomainRequest) (*dto.TenantDomainItem, error) {
+	// 1. Kiểm tra tenant
+	if _, err := ts.findTenant(tenantId); err != nil {
+		return nil, err
+	}
+
+	// 2. Hostname chưa thuộc tenant nào
+	hostname := strings.ToLower(strings.TrimSpace(req.Hostname))
+	if ts.tenantRepo.HostnameExists(hostname) {
+		return nil, utils.NewError("Domain is already in use", utils.ErrCodeConflict)
+	}
+
+	// 3. Lưu và làm mới cache (hostname này trước đó có thể đã được cache là tenant mặc định)
+	domain := &models.TenantDomain{TenantId: tenantId, Hostname: hostname}
+	if err := ts.tenantRepo.AddDomain(domain); err != nil {
+		return nil, utils.WrapError(err, "Failed to add domain", utils.ErrCodeInternal)
+	}
+	ts.tenantResolver.Invalidate()
+
+	item := toTenantDomainItem(domain)
+	return &item, nil
+}
+
+func (ts *tenantService) RemoveDomain(tenantId, domainId uint) error {
+	domain, err := ts.tenantRepo.FindDomain(tenantId, domainId)
+	if err != nil {
+		return utils.WrapError(err, "Failed to find domain", utils.ErrCodeInternal)
+	}
+	if domain == nil {
+		return utils.NewError("Domain not found", utils.ErrCodeNotFound)
+	}
+
+	if err := ts.tenantRepo.RemoveDomain(domain.Id); err != nil {
+		return utils.WrapError(err, "Failed to remove domain", utils.ErrCodeInternal)
+	}
+	ts.tenantResolver.Invalidate()
+	return nil
+}
+
+// ---------------- Super-admin: cross-tenant analytics ----------------
+func (ts *tenantService) GetCrossTenantAnalytics(req *dto.CrossTenantAnalyticsRequest) (*dto.CrossTenantAnalyticsResponse, error) {
+	// 1. Mặc định 30 ngày gần nhất (end_date tính trọn ngày)
+	endDate := time.Now()
+	if req.EndDate != "" {
+		if parsed, err := time.Parse("2006-01-02", req.EndDate); err == nil {
+			endDate = parsed.Add(24*time.Hour - time.Nanosecond)
+		}
+	}
+	startDate := endDate.AddDate(0, 0, -30)
+	if req.StartDate != "" {
+		if parsed, err := time.Parse("2006-01-02", req.StartDate); err == nil {
+			startDate = parsed
+		}
+	}
+	if startDate.After(endDate) {
+		return nil, utils.NewError("start_date must be before end_date", utils.ErrCodeBadRequest)
+	}
+
+	// 2. Số liệu từng tenant
+	items, err := ts.tenantRepo.GetTenantAnalytics(startDate, endDate)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get tenant analytics", utils.ErrCodeInternal)
+	}
+
+	// 3. Tổng toàn platform
+	response := &dto.CrossTenantAnalyticsResponse{
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.Format("2006-01-02"),
+		Tenants:   items,
+	}
+	for _, item := range items {
+		response.TotalUsers += item.TotalUsers
+		response.TotalCourses += item.Courses
+		response.TotalOrders += item.Orders
+		response.TotalRevenue += item.Revenue
+	}
+	if response.Tenants == nil {
+		response.Tenants = []dto.TenantAnalyticsItem{}
+	}
+
+	return response, nil
+}
+
+// ---------------- Helpers ----------------
+func (ts *tenantService) findTenant(tenantId uint) (*models.Tenant, error) {
+	tenant, err := ts.tenantRepo.FindById(tenantId)
+	if err != nil {
+		return nil, utils.NewError("Tenant not found", utils.ErrCodeNotFound)
+	}
+	return tenant, nil
+}
+
+func toTenantBranding(tenant *models.Tenant) dto.TenantBrandingResponse {
+	settings := json.RawMessage(tenant.Settings)
+	if !json.Valid(settings) {
+		settings = json.RawMessage("{}")
+	}
+
+	frontendURL := tenant.FrontendURL
+	if frontendURL == "" {
+		frontendURL = utils.GetEnv("FRONTEND_URL", "http://localhost:3000")
+	}
+
+	return dto.TenantBrandingResponse{
+		Slug:           tenant.Slug,
+		Name:           tenant.Name,
+		FrontendURL:    frontendURL,
+		SupportEmail:   tenant.SupportEmail,
+		LogoURL:        tenant.LogoURL,
+		FaviconURL:     tenant.FaviconURL,
+		PrimaryColor:   tenant.PrimaryColor,
+		SecondaryColor: tenant.SecondaryColor,
+		Settings:       settings,
+	}
+}
+
+func toTenantSettings(tenant *models.Tenant) *dto.TenantSettingsResponse {
+	return &dto.TenantSettingsResponse{
+		TenantBrandingResponse: toTenantBranding(tenant),
+		EmailFromName:          tenant.EmailFromName,
+		EmailFromAddress:       tenant.EmailFromAddress,
+		XapiLrsKey:             tenant.XapiLrsKey,
+		XapiLrsEnabled:         tenant.XapiLrsKey != "" && tenant.XapiLrsSecretHash != "",
+	}
+}
+
+func toTenantItem(tenant *models.Tenant) dto.TenantItem {
+	domains := make([]dto.TenantDomainItem, len(tenant.Domains))
+	for i := range tenant.Domains {
+		domains[i] = toTenantDomainItem(&tenant.Domains[i])
+	}
+
+	return dto.TenantItem{
+		Id:          tenant.Id,
+		Slug:        tenant.Slug,
+		Name:        tenant.Name,
+		Status:      tenant.Status,
+		FrontendURL: tenant.FrontendURL,
+		Domains:     domains,
+		CreatedAt:   tenant.CreatedAt,
+	}
+}
+
+func toTenantDomainItem(domain *models.TenantDomain) dto.TenantDomainItem {
+	return dto.TenantDomainItem{
+		Id:        domain.Id,
+		Hostname:  domain.Hostname,
+		CreatedAt: domain.CreatedAt,
+	}
+}
@@ -1,9 +1,9 @@
 package service
 
 import (
-	"fmt"
 	"lms/src/dto"
 	"lms/src/repository"
+	"lms/src/storage"
 	"lms/src/utils"
 	"mime/multipart"
 	"strings"
@@ -12,11 +12,13 @@ import (
 
 type userService struct {
 	userRepo repository.UserRepository
+	store    storage.Storage
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
+func NewUserService(userRepo repository.UserRepository, store storage.Storage) UserService {
 	return &userService{
 		userRepo: userRepo,
+		store:    store,
 	}
 }
 
@@ -65,7 +67,11 @@ func (us *userService) UpdateProfile(userId uint, req *dto.UpdateProfileRequest)
 	}
 
 	if req.AvatarURL != "" {
-		updates["avatar_url"] = strings.TrimSpace(req.AvatarURL)
+		avatarURL := strings.TrimSpace(req.AvatarURL)
+		if err := storage.ValidateClientURL(us.store, avatarURL, storage.OwnerPrefix("avatars", userId)); err != nil {
+			return nil, utils.NewError(err.Error(), utils.ErrCodeBadRequest)
+		}
+		updates["avatar_url"] = avatarURL
 	}
 
 	// Luôn cập nhật updated_at
@@ -76,6 +82,11 @@ func (us *userService) UpdateProfile(userId uint, req *dto.UpdateProfileRequest)
 		if err := us.userRepo.UpdateProfile(userId, updates); err != nil {
 			return nil, utils.WrapError(err, "Failed to update profile", utils.ErrCodeInternal)
 		}
+
+		// Avatar mới (vd. upload qua presigned URL) thay thế avatar cũ trên storage
+		if newAvatar, ok := updates["avatar_url"]; ok && newAvatar != existingUser.AvatarURL {
+			storage.DeleteByURL(us.store, existingUser.AvatarURL, "avatars/")
+		}
 	}
 
 	// 4. Lấy thông tin user đã cập nhật
@@ -161,23 +172,37 @@ func (us *userService) UploadAvatar(userId uint, file *multipart.FileHeader) (*d
 		return nil, utils.WrapError(err, "Invalid avatar file", utils.ErrCodeBadRequest)
 	}
 
-	// 4. Validate và lưu file
-	uploadDir := "../../src/uploads/avatars"
+	// 4. Validate và lưu file vào storage
+	fileName, contentType, err := utils.ValidateFile(file, utils.ImageFileRule)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to upload avatar", utils.ErrCodeBadRequest)
+	}
 
-	fileName, err := utils.ValidateAndSaveFile(file, uploadDir)
+	src, err := file.Open()
 	if err != nil {
 		return nil, utils.WrapError(err, "Failed to upload avatar", utils.ErrCodeBadRequest)
 	}
+	defer src.Close()
+
+	key := storage.OwnerPrefix("avatars", userId) + fileName
+	if err := us.store.Put(key, src, file.Size, contentType); err != nil {
+		return nil, utils.WrapError(err, "Failed to upload avatar", utils.ErrCodeInternal)
+	}
 
 	// 5. Tạo URL cho avatar
-	baseURL := utils.GetEnv("BASE_URL", "http://localhost:8080")
-	avatarURL := fmt.Sprintf("%s/uploads/avatars/%s", baseURL, fileName)
+	avatarURL := us.store.URL(key)
 
 	// 6. Cập nhật avatar URL trong database
 	if err := us.userRepo.UpdateAvatar(userId, avatarURL); err != nil {
+		us.store.Delete(key)
 		return nil, utils.WrapError(err, "Failed to update avatar URL", utils.ErrCodeInternal)
 	}
 
+	// 7. Xóa avatar cũ để tránh file mồ côi
+	if existingUser.AvatarURL != "" && existingUser.AvatarURL != avatarURL {
+		storage.DeleteByURL(us.store, existingUser.AvatarURL, "avatars/")
+	}
+
 	return &dto.UploadAvatarResponse{
 		Message:   "Avatar uploaded successfully",
 		AvatarURL: avatarURL,
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/storage"
+	"lms/src/utils"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Thời hạn của signed playback URL
+const playbackURLTTL = 2 * time.Hour
+
+type videoService struct {
+	videoRepo      repository.VideoRepository
+	instructorRepo repository.InstructorRepository
+	lessonRepo     repository.LessonRepository
+	transcoder     *VideoTranscoder
+	store          storage.Storage
+}
+
+// VideoStream là kết quả resolve một file HLS: playlist được đọc qua API,
+// segment được redirect tới presigned URL của storage
+type VideoStream struct {
+	Body        io.ReadCloser
+	ContentType string
+	RedirectURL string
+}
+
+func NewVideoService(
+	videoRepo repository.VideoRepository,
+	instructorRepo repository.InstructorRepository,
+	lessonRepo repository.LessonRepository,
+	transcoder *VideoTranscoder,
+	store storage.Storage,
+) VideoService {
+	return &videoService{
+		videoRepo:      videoRepo,
+		instructorRepo: instructorRepo,
+		lessonRepo:     lessonRepo,
+		transcoder:     transcoder,
+		store:          store,
+	}
+}
+
+func (vs *videoService) InitUpload(instructorId, courseId, lessonId uint, req *dto.InitVideoUploadRequest) (*dto.VideoUploadResponse, error) {
+	// 1. Kiểm tra course thuộc về instructor
+	if _, err := vs.instructorRepo.FindCourseByIdAndInstructor(courseId, instructorId); err != nil {
+		return nil, utils.NewError("Course not found or you don't have permission", utils.ErrCodeNotFound)
+	}
+
+	// 2. Kiểm tra lesson thuộc course
+	if _, err := vs.instructorRepo.FindLessonByIdAndCourse(lessonId, courseId); err != nil {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	// 3. Validate file
+	chunkSize := req.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = utils.DefaultChunkSize
+	}
+	if err := utils.ValidateVideoUpload(req.FileName, req.FileSize, chunkSize); err != nil {
+		return nil, utils.NewError(err.Error(), utils.ErrCodeBadRequest)
+	}
+
+	// 4. Tạo upload session
+	upload := &models.VideoUpload{
+		UploadId:     uuid.New().String(),
+		LessonId:     lessonId,
+		CourseId:     courseId,
+		InstructorId: instructorId,
+		FileName:     filepath.Base(req.FileName),
+		FileSize:     req.FileSize,
+		ChunkSize:    chunkSize,
+		TotalChunks:  int((req.FileSize + chunkSize - 1) / chunkSize),
+		Status:       "uploading",
+	}
+
+	if err := vs.videoRepo.CreateUpload(upload); err != nil {
+		return nil, utils.WrapError(err, "Failed to create upload session", utils.ErrCodeInternal)
+	}
+
+	return toVideoUploadResponse(upload, []int{}), nil
+}
+
+func (vs *videoService) UploadChunk(instructorId uint, uploadId string, index int, body io.Reader) (*dto.VideoUploadResponse, error) {
+	// 1. Lấy upload session
+	upload, err := vs.findInstructorUpload(instructorId, uploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	if upload.Status != "uploading" {
+		return nil, utils.NewError("Upload is already completed", utils.ErrCodeConflict)
+	}
+
+	if index < 0 || index >= upload.TotalChunks {
+		return nil, utils.NewError("Invalid chunk index", utils.ErrCodeBadRequest)
+	}
+
+	// 2. Kích thước mong đợi của chunk (chunk cuối có thể nhỏ hơn)
+	expectedSize := upload.ChunkSize
+	if index == upload.TotalChunks-1 {
+		expectedSize = upload.FileSize - upload.ChunkSize*int64(upload.TotalChunks-1)
+	}
+
+	// 3. Ghi chunk ra file tạm để kiểm tra kích thước (tránh chunk dở dang khi mất kết nối)
+	tmp, err := os.CreateTemp("", "video-chunk-*")
+	if err != nil {
+		return nil, utils.WrapError(err, "Cannot save chunk", utils.ErrCodeInternal)
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	written, err := io.Copy(tmp, io.LimitReader(body, expectedSize+1))
+	if err != nil {
+		return nil, utils.WrapError(err, "Cannot save chunk", utils.ErrCodeInternal)
+	}
+
+	if written != expectedSize {
+		return nil, utils.NewError(
+			fmt.Sprintf("Invalid chunk size: expected %d bytes, got %d", expectedSize, written),
+			utils.ErrCodeBadRequest,
+		)
+	}
+
+	// 4. Đưa chunk lên storage dùng chung (worker transcode có thể chạy ở instance khác) rồi đánh dấu đã nhận
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, utils.WrapError(err, "Cannot save chunk", utils.ErrCodeInternal)
+	}
+	if err := vs.store.Put(videoChunkKey(uploadId, index), tmp, written, "application/octet-stream"); err != nil {
+		return nil, utils.WrapError(err, "Cannot save chunk", utils.ErrCodeInternal)
+	}
+	if err := vs.videoRepo.SaveChunk(upload.Id, index); err != nil {
+		return nil, utils.WrapError(err, "Cannot save chunk", utils.ErrCodeInternal)
+	}
+
+	return vs.uploadResponse(upload)
+}
+
+func (vs *videoService) GetUploadStatus(instructorId uint, uploadId string) (*dto.VideoUploadResponse, error) {
+	upload, err := vs.findInstructorUpload(instructorId, uploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	return vs.uploadResponse(upload)
+}
+
+func (vs *videoService) CompleteUpload(instructorId uint, uploadId string) (*dto.VideoUploadResponse, error) {
+	// 1. Lấy upload session
+	upload, err := vs.findInstructorUpload(instructorId, uploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	if upload.Status != "uploading" {
+		return nil, utils.NewError("Upload is already completed", utils.ErrCodeConflict)
+	}
+
+	// 2. Kiểm tra đã nhận đủ chunks
+	received, err := vs.videoRepo.GetReceivedChunks(upload.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get upload chunks", utils.ErrCodeInternal)
+	}
+	if len(received) != upload.TotalChunks {
+		return nil, utils.NewError(
+			fmt.Sprintf("Missing chunks: received %d of %d", len(received), upload.TotalChunks),
+			utils.ErrCodeBadRequest,
+		)
+	}
+
+	// 3. Đưa vào hàng đợi transcode (worker ghép các chunk từ storage khi nhận job)
+	if err := vs.videoRepo.UpdateUpload(upload.Id, map[string]interface{}{"status": "queued"}); err != nil {
+		return nil, utils.WrapError(err, "Failed to update upload", utils.ErrCodeInternal)
+	}
+	if err := vs.videoRepo.UpdateLessonVideo(upload.LessonId, map[string]interface{}{"video_status": "processing"}); err != nil {
+		return nil, utils.WrapError(err, "Failed to update lesson", utils.ErrCodeInternal)
+	}
+
+	vs.transcoder.Trigger()
+
+	upload.Status = "queued"
+	return toVideoUploadResponse(upload, received), nil
+}
+
+func (vs *videoService) GetPlayback(userId, lessonId uint) (*dto.VideoPlaybackResponse, error) {
+	// 1. Lấy lesson
+	lesson, err := vs.videoRepo.FindLessonById(lessonId)
+	if err != nil || !lesson.IsPublished {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	// Course chưa publish (draft, chờ duyệt, bị gỡ) thì không phát video, kể cả lesson preview
+	course, err := vs.instructorRepo.FindCourseById(lesson.CourseId)
+	if err != nil || course.Status != "published" {
+		return nil, utils.NewError("Lesson not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Lesson preview ai cũng xem được, còn lại phải enroll và lesson đã mở khóa
+	if !lesson.IsPreview {
+		isEnrolled, err := vs.lessonRepo.CheckUserEnrollment(userId, lesson.CourseId)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to check enrollment", utils.ErrCodeInternal)
+		}
+		if !isEnrolled {
+			return nil, utils.NewError("You must enroll in this course to watch this lesson", utils.ErrCodeForbidden)
+		}
+
+		lock, err := getLessonLock(vs.lessonRepo, userId, lesson.CourseId, lesson.Id)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to resolve lesson schedule", utils.ErrCodeInternal)
+		}
+		if lock.isLocked {
+			return nil, utils.NewError(lockMessage(lock), utils.ErrCodeForbidden)
+		}
+	}
+
+	// 3. Video phải transcode xong
+	if lesson.VideoStatus != "ready" || lesson.HLSPath == "" {
+		return nil, utils.NewError("Video is not ready for streaming", utils.ErrCodeNotFound)
+	}
+
+	// 4. Tạo signed URL
+	expiresAt := time.Now().Add(playbackURLTTL)
+
+	return &dto.VideoPlaybackResponse{
+		LessonId:      lesson.Id,
+		Format:        "hls",
+		PlaybackURL:   signedPlaybackURL(lesson, expiresAt),
+		PosterURL:     lesson.PosterURL,
+		VideoDuration: lesson.VideoDuration,
+		ExpiresAt:     expiresAt,
+	}, nil
+}
+
+func (vs *videoService) ResolveStream(token, filePath string) (*VideoStream, error) {
+	// 1. Kiểm tra token
+	lessonId, err := utils.VerifyPlaybackToken(token)
+	if err != nil {
+		return nil, utils.NewError(err.Error(), utils.ErrCodeForbidden)
+	}
+
+	lesson, err := vs.videoRepo.FindLessonById(lessonId)
+	if err != nil || lesson.HLSPath == "" {
+		return nil, utils.NewError("Video not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Chỉ cho phép đọc file HLS trong thư mục output của lesson
+	ext := strings.ToLower(path.Ext(filePath))
+	if ext != ".m3u8" && ext != ".ts" {
+		return nil, utils.NewError("Video not found", utils.ErrCodeNotFound)
+	}
+
+	baseKey := path.Dir(lesson.HLSPath)
+	key := path.Join(baseKey, path.Clean("/"+filePath))
+	if !strings.HasPrefix(key, baseKey+"/") {
+		return nil, utils.NewError("Video not found", utils.ErrCodeNotFound)
+	}
+
+	// 3. Segment: redirect tới presigned URL để không tải video qua API server
+	if ext == ".ts" {
+		url, err := vs.store.PresignGet(key, playbackURLTTL)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to sign video segment", utils.ErrCodeInternal)
+		}
+		return &VideoStream{RedirectURL: url}, nil
+	}
+
+	// 4. Playlist: đọc từ storage, các URI tương đối trong playlist vẫn đi qua token
+	body, err := vs.store.Get(key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, utils.NewError("Video not found", utils.ErrCodeNotFound)
+		}
+		return nil, utils.WrapError(err, "Failed to read video playlist", utils.ErrCodeInternal)
+	}
+
+	return &VideoStream{Body: body, ContentType: hlsContentType(key)}, nil
+}
+
+func (vs *videoService) findInstructorUpload(instructorId uint, uploadId string) (*models.VideoUpload, error) {
+	upload, err := vs.videoRepo.FindUploadByUploadId(uploadId)
+	if err != nil || upload.InstructorId != instructorId {
+		return nil, utils.NewError("Upload not found", utils.ErrCodeNotFound)
+	}
+	return upload, nil
+}
+
+// signedPlaybackURL tạo URL master playlist có token hết hạn sau expiresAt
+func signedPlaybackURL(lesson *models.Lesson, expiresAt time.Time) string {
+	baseURL := utils.GetEnv("BASE_URL", "http://localhost:8080")
+	token := utils.GeneratePlaybackToken(lesson.Id, expiresAt)
+	return fmt.Sprintf("%s/api/v1/videos/stream/%s/%s", baseURL, token, path.Base(lesson.HLSPath))
+}
+
+// uploadResponse trả về trạng thái upload kèm các chunk đã nhận, dùng để client resume upload
+func (vs *videoService) uploadResponse(upload *models.VideoUpload) (*dto.VideoUploadResponse, error) {
+	received, err := vs.videoRepo.GetReceivedChunks(upload.Id)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get upload chunks", utils.ErrCodeInternal)
+	}
+	return toVideoUploadResponse(upload, received), nil
+}
+
+func toVideoUploadResponse(upload *models.VideoUpload, received []int) *dto.VideoUploadResponse {
+	return &dto.VideoUploadResponse{
+		UploadId:       upload.UploadId,
+		LessonId:       upload.LessonId,
+		FileName:       upload.FileName,
+		FileSize:       upload.FileSize,
+		ChunkSize:      upload.ChunkSize,
+		TotalChunks:    upload.TotalChunks,
+		ReceivedChunks: received,
+		Status:         upload.Status,
+		ErrorMessage:   upload.ErrorMessage,
+		CreatedAt:      upload.CreatedAt,
+	}
+}
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/storage"
+	"lms/src/utils"
+	"log"
+	"math"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Job "processing" giữ lâu hơn thời gian này xem như worker đã chết, worker khác nhận lại
+const videoTranscodeLockTimeout = 2 * time.Hour
+
+// hlsRendition cấu hình một mức bitrate của HLS output
+type hlsRendition struct {
+	name         string
+	height       int
+	videoBitrate string
+	audioBitrate string
+}
+
+var hlsRenditions = []hlsRendition{
+	{name: "360p", height: 360, videoBitrate: "800k", audioBitrate: "96k"},
+	{name: "720p", height: 720, videoBitrate: "2800k", audioBitrate: "128k"},
+	{name: "1080p", height: 1080, videoBitrate: "5000k", audioBitrate: "192k"},
+}
+
+// VideoTranscoder chạy background job chuyển video gốc sang HLS nhiều bitrate bằng ffmpeg
+type VideoTranscoder struct {
+	videoRepo repository.VideoRepository
+	store     storage.Storage
+	workers   int
+	interval  time.Duration
+	wake      chan struct{}
+}
+
+func NewVideoTranscoder(videoRepo repository.VideoRepository, store storage.Storage) *VideoTranscoder {
+	workers, err := strconv.Atoi(utils.GetEnv("VIDEO_TRANSCODE_WORKERS", "1"))
+	if err != nil || workers < 1 {
+		workers = 1
+	}
+
+	seconds, err := strconv.Atoi(utils.GetEnv("VIDEO_TRANSCODE_POLL_INTERVAL_SECONDS", "30"))
+	if err != nil || seconds < 1 {
+		seconds = 30
+	}
+
+	return &VideoTranscoder{
+		videoRepo: videoRepo,
+		store:     store,
+		workers:   workers,
+		interval:  time.Duration(seconds) * time.Second,
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Trigger đánh thức worker khi có upload mới vào hàng đợi (instance khác nhận job ở lượt poll kế tiếp)
+func (vt *VideoTranscoder) Trigger() {
+	select {
+	case vt.wake <- struct{}{}:
+	default:
+	}
+}
+
+// StartWorkers khởi động workers. Job nằm trong DB nên job dở dang (do server restart)
+// được nhận lại qua ClaimQueuedUpload, không cần đưa lại vào hàng đợi lúc khởi động.
+func (vt *VideoTranscoder) StartWorkers() {
+	for i := 0; i < vt.workers; i++ {
+		go vt.work()
+	}
+}
+
+func (vt *VideoTranscoder) work() {
+	ticker := time.NewTicker(vt.interval)
+	defer ticker.Stop()
+
+	for {
+		vt.processQueued()
+
+		select {
+		case <-ticker.C:
+		case <-vt.wake:
+		}
+	}
+}
+
+// processQueued nhận và transcode từng job cho tới khi hàng đợi trống
+func (vt *VideoTranscoder) processQueued() {
+	for {
+		now := time.Now()
+		upload, err := vt.videoRepo.ClaimQueuedUpload(now, now.Add(-videoTranscodeLockTimeout))
+		if err != nil {
+			log.Printf("Failed to claim video job: %v", err)
+			return
+		}
+		if upload == nil {
+			return
+		}
+
+		if err := vt.process(upload); err != nil {
+			log.Printf("Video transcode failed (upload %d): %v", upload.Id, err)
+		}
+	}
+}
+
+func (vt *VideoTranscoder) process(upload *models.VideoUpload) error {
+	// 1. Job đã được nhận (status "processing"), cập nhật trạng thái lesson
+	if err := vt.videoRepo.UpdateLessonVideo(upload.LessonId, map[string]interface{}{"video_status": "processing"}); err != nil {
+		return err
+	}
+
+	fail := func(err error) error {
+		vt.videoRepo.UpdateUpload(upload.Id, map[string]interface{}{
+			"status":        "failed",
+			"error_message": err.Error(),
+			"locked_at":     nil,
+		})
+		vt.videoRepo.UpdateLessonVideo(upload.LessonId, map[string]interface{}{"video_status": "failed"})
+		return err
+	}
+
+	// Thư mục làm việc cục bộ của worker, xóa khi xong job
+	workDir := videoWorkDir(upload.UploadId)
+	defer os.RemoveAll(workDir)
+	sourcePath := filepath.Join(workDir, "source"+strings.ToLower(filepath.Ext(upload.FileName)))
+	outputDir := filepath.Join(workDir, "hls")
+
+	// 2. Ghép các chunk từ storage dùng chung thành file gốc
+	if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
+		return fail(err)
+	}
+	if err := downloadChunks(vt.store, upload, sourcePath); err != nil {
+		return fail(fmt.Errorf("assemble chunks failed: %w", err))
+	}
+
+	// 3. Lấy thời lượng và kiểm tra audio bằng ffprobe
+	duration, err := probeDuration(sourcePath)
+	if err != nil {
+		return fail(fmt.Errorf("ffprobe failed: %w", err))
+	}
+	hasAudio := probeHasAudio(sourcePath)
+
+	// 4. Transcode sang HLS nhiều bitrate
+	if err := runFFmpeg(buildHLSArgs(sourcePath, outputDir, hasAudio)); err != nil {
+		return fail(fmt.Errorf("ffmpeg transcode failed: %w", err))
+	}
+
+	// 5. Tạo poster (ảnh đại diện) tại giây thứ 1 hoặc giữa video nếu video ngắn
+	posterPath := filepath.Join(workDir, "poster.jpg")
+	posterAt := math.Min(1, duration/2)
+	if err := runFFmpeg([]string{
+		"-y", "-ss", strconv.FormatFloat(posterAt, 'f', 2, 64), "-i", sourcePath,
+		"-frames:v", "1", "-vf", "scale=1280:-2", posterPath,
+	}); err != nil {
+		return fail(fmt.Errorf("poster generation failed: %w", err))
+	}
+
+	// 6. Đưa HLS output (private) và poster (public) lên storage
+	hlsPrefix := fmt.Sprintf("%s%s/", lessonVideoPrefix(upload.LessonId), upload.UploadId)
+	if err := uploadDir(vt.store, outputDir, hlsPrefix); err != nil {
+		storage.DeletePrefixQuietly(vt.store, hlsPrefix)
+		return fail(fmt.Errorf("upload HLS output failed: %w", err))
+	}
+
+	posterKey := fmt.Sprintf("posters/%s.jpg", upload.UploadId)
+	if err := uploadFile(vt.store, posterPath, posterKey); err != nil {
+		storage.DeletePrefixQuietly(vt.store, hlsPrefix)
+		return fail(fmt.Errorf("upload poster failed: %w", err))
+	}
+
+	// 7. Cập nhật lesson
+	previous, err := vt.videoRepo.FindLessonById(upload.LessonId)
+	if err != nil {
+		return fail(err)
+	}
+
+	if err := vt.videoRepo.UpdateLessonVideo(upload.LessonId, map[string]interface{}{
+		"video_status":   "ready",
+		"hls_path":       hlsPrefix + "master.m3u8",
+		"poster_url":     vt.store.URL(posterKey),
+		"video_duration": int(math.Round(duration)),
+	}); err != nil {
+		return fail(err)
+	}
+
+	if err := vt.videoRepo.UpdateUpload(upload.Id, map[string]interface{}{"status": "ready", "error_message": "", "locked_at": nil}); err != nil {
+		return err
+	}
+
+	// 8. Xóa video cũ của lesson và các chunk trên storage sau khi transcode thành công
+	if previous.HLSPath != "" && !strings.HasPrefix(previous.HLSPath, hlsPrefix) {
+		storage.DeletePrefixQuietly(vt.store, path.Dir(previous.HLSPath)+"/")
+	}
+	if previous.PosterURL != vt.store.URL(posterKey) {
+		storage.DeleteByURL(vt.store, previous.PosterURL, "posters/")
+	}
+	storage.DeletePrefixQuietly(vt.store, videoChunkPrefix(upload.UploadId))
+
+	return nil
+}
+
+// downloadChunks ghép các chunk (theo thứ tự) từ storage vào file gốc trên máy của worker
+func downloadChunks(store storage.Storage, upload *models.VideoUpload, sourcePath string) error {
+	out, err := os.Create(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	for i := 0; i < upload.TotalChunks; i++ {
+		chunk, err := store.Get(videoChunkKey(upload.UploadId, i))
+		if err != nil {
+			return fmt.Errorf("chunk %d: %w", i, err)
+		}
+		_, err = io.Copy(out, chunk)
+		chunk.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildHLSArgs tạo tham số ffmpeg: scale mỗi rendition, master playlist + playlist riêng cho từng mức
+func buildHLSArgs(sourcePath, outputDir string, hasAudio bool) []string {
+	splits := make([]string, len(hlsRenditions))
+	scales := make([]string, len(hlsRenditions))
+	for i, rendition := range hlsRenditions {
+		splits[i] = fmt.Sprintf("[v%d]", i)
+		scales[i] = fmt.Sprintf("[v%d]scale=w=-2:h=%d[v%dout]", i, rendition.height, i)
+	}
+	filter := fmt.Sprintf("[0:v]split=%d%s;%s", len(hlsRenditions), strings.Join(splits, ""), strings.Join(scales, ";"))
+
+	args := []string{"-y", "-i", sourcePath, "-filter_complex", filter}
+	streamMap := make([]string, len(hlsRenditions))
+	for i, rendition := range hlsRenditions {
+		args = append(args,
+			"-map", fmt.Sprintf("[v%dout]", i),
+			fmt.Sprintf("-c:v:%d", i), "libx264",
+			fmt.Sprintf("-b:v:%d", i), rendition.videoBitrate,
+		)
+		if hasAudio {
+			args = append(args,
+				"-map", "a:0",
+				fmt.Sprintf("-c:a:%d", i), "aac",
+				fmt.Sprintf("-b:a:%d", i), rendition.audioBitrate,
+			)
+			streamMap[i] = fmt.Sprintf("v:%d,a:%d,name:%s", i, i, rendition.name)
+		} else {
+			streamMap[i] = fmt.Sprintf("v:%d,name:%s", i, rendition.name)
+		}
+	}
+
+	return append(args,
+		"-preset", "veryfast",
+		"-f", "hls",
+		"-hls_time", "6",
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", filepath.Join(outputDir, "%v", "segment_%03d.ts"),
+		"-master_pl_name", "master.m3u8",
+		"-var_stream_map", strings.Join(streamMap, " "),
+		filepath.Join(outputDir, "%v", "index.m3u8"),
+	)
+}
+
+func probeDuration(sourcePath string) (float64, error) {
+	out, err := exec.Command(
+		"ffprobe", "-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		sourcePath,
+	).Output()
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(strings.TrimSpace(string(out)), 64)
+}
+
+func probeHasAudio(sourcePath string) bool {
+	out, err := exec.Command(
+		"ffprobe", "-v", "error",
+		"-select_streams", "a",
+		"-show_entries", "stream=index",
+		"-of", "csv=p=0",
+		sourcePath,
+	).Output()
+	return err == nil && strings.TrimSpace(string(out)) != ""
+}
+
+func runFFmpeg(args []string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("ffmpeg", args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%v: %s", err, lastLines(stderr.String(), 5))
+	}
+	return nil
+}
+
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lessonVideoPrefix prefix (private) chứa HLS output của một lesson trên storage
+func lessonVideoPrefix(lessonId uint) string {
+	return fmt.Sprintf("%svideos/hls/%d/", storage.PrivatePrefix, lessonId)
+}
+
+// hlsContentType trả về content type của playlist/segment HLS
+func hlsContentType(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".m3u8":
+		return "application/vnd.apple.mpegurl"
+	case ".ts":
+		return "video/mp2t"
+	case ".jpg":
+		return "image/jpeg"
+	}
+	return "application/octet-stream"
+}
+
+// uploadDir đưa toàn bộ file trong dir lên storage, giữ nguyên cấu trúc thư mục dưới prefix
+func uploadDir(store storage.Storage, dir, prefix string) error {
+	return filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		return uploadFile(store, filePath, prefix+filepath.ToSlash(rel))
+	})
+}
+
+func uploadFile(store storage.Storage, filePath, key string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	return store.Put(key, file, info.Size(), hlsContentType(filePath))
+}
+
+// videoChunkPrefix prefix (private) chứa các chunk của một upload trên storage dùng chung,
+// instance nhận chunk và worker transcode có thể là hai máy khác nhau
+func videoChunkPrefix(uploadId string) string {
+	return fmt.Sprintf("%svideos/uploads/%s/", storage.PrivatePrefix, uploadId)
+}
+
+func videoChunkKey(uploadId string, index int) string {
+	return fmt.Sprintf("%s%d.part", videoChunkPrefix(uploadId), index)
+}
+
+// videoWorkDir thư mục làm việc cục bộ của worker khi transcode một upload
+func videoWorkDir(uploadId string) string {
+	return filepath.Join(utils.VideoStorageDir(), "tmp", uploadId)
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// Số delivery nhận mỗi lần claim (gửi song song)
+	webhookBatchSize = 20
+	// Delivery "processing" quá thời gian này được coi là bị treo và được nhận lại
+	webhookLockTimeout = 5 * time.Minute
+	// Thời gian chờ retry: 30s, 1m, 2m, ... tối đa 6 giờ
+	webhookRetryBaseDelay = 30 * time.Second
+	webhookRetryMaxDelay  = 6 * time.Hour
+	// Chỉ lưu phần đầu response body vào log
+	webhookMaxResponseBody = 2048
+)
+
+// RegisterWebhookSubscribers đăng ký subscriber tạo webhook delivery cho mọi endpoint của tenant phát sinh event
+// đang nhận loại event đó
+func RegisterWebhookSubscribers(dispatcher *EventDispatcher, webhookRepo repository.WebhookRepository) {
+	for _, item := range webhookEventTypes {
+		dispatcher.Subscribe(item.Type, "webhooks", func(event *models.OutboxEvent) error {
+			endpoints, err := webhookRepo.GetActiveEndpoints(event.TenantId)
+			if err != nil {
+				return err
+			}
+
+			payload, err := json.Marshal(dto.WebhookPayload{
+				Id:        fmt.Sprintf("evt_%d", event.Id),
+				Type:      event.EventType,
+				CreatedAt: event.CreatedAt,
+				Data:      json.RawMessage(event.Payload),
+			})
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			var deliveries []models.WebhookDelivery
+			for i := range endpoints {
+				if !webhookSubscribes(&endpoints[i], event.EventType) {
+					continue
+				}
+				deliveries = append(deliveries, models.WebhookDelivery{
+					EndpointId:    endpoints[i].Id,
+					EventId:       &event.Id,
+					EventType:     event.EventType,
+					Payload:       string(payload),
+					Status:        "pending",
+					NextAttemptAt: now,
+				})
+			}
+
+			return webhookRepo.CreateDeliveries(deliveries)
+		})
+	}
+}
+
+// WebhookDispatcher gửi webhook delivery tới endpoint: ký HMAC, retry với exponential backoff
+type WebhookDispatcher struct {
+	webhookRepo repository.WebhookRepository
+	dsn         string
+	client      *http.Client
+	interval    time.Duration
+	maxAttempts int
+	wake        chan struct{}
+}
+
+func NewWebhookDispatcher(webhookRepo repository.WebhookRepository, dsn string) *WebhookDispatcher {
+	seconds, err := strconv.Atoi(utils.GetEnv("WEBHOOK_POLL_INTERVAL_SECONDS", "5"))
+	if err != nil || seconds < 1 {
+		seconds = 5
+	}
+
+	timeout, err := strconv.Atoi(utils.GetEnv("WEBHOOK_TIMEOUT_SECONDS", "10"))
+	if err != nil || timeout < 1 {
+		timeout = 10
+	}
+
+	maxAttempts, err := strconv.Atoi(utils.GetEnv("WEBHOOK_MAX_ATTEMPTS", "10"))
+	if err != nil || maxAttempts < 1 {
+		maxAttempts = 10
+	}
+
+	return &WebhookDispatcher{
+		webhookRepo: webhookRepo,
+		dsn:         dsn,
+		client: &http.Client{
+			Timeout: time.Duration(timeout) * time.Second,
+			// Không follow redirect - endpoint phải trả 2xx trực tiếp
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		interval:    time.Duration(seconds) * time.Second,
+		maxAttempts: maxAttempts,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Trigger đánh thức dispatcher ngay
+func (wd *WebhookDispatcher) Trigger() {
+	select {
+	case wd.wake <- struct{}{}:
+	default:
+	}
+}
+
+// StartWorkers LISTEN delivery mới và poll định kỳ để gửi retry
+func (wd *WebhookDispatcher) StartWorkers() {
+	startPostgresListener(wd.dsn, repository.WebhookChannel, func(string) {
+		wd.Trigger()
+	})
+
+	go func() {
+		ticker := time.NewTicker(wd.interval)
+		defer ticker.Stop()
+
+		for {
+			wd.dispatchDue()
+
+			select {
+			case <-ticker.C:
+			case <-wd.wake:
+			}
+		}
+	}()
+}
+
+func (wd *WebhookDispatcher) dispatchDue() {
+	for {
+		now := time.Now()
+		deliveries, err := wd.webhookRepo.ClaimDueDeliveries(now, now.Add(-webhookLockTimeout), webhookBatchSize)
+		if err != nil {
+			log.Printf("Failed to claim webhook deliveries: %v", err)
+			return
+		}
+
+		var wg sync.WaitGroup
+		for i := range deliveries {
+			wg.Add(1)
+			go func(delivery *models.WebhookDelivery) {
+				defer wg.Done()
+				wd.deliver(delivery)
+			}(&deliveries[i])
+		}
+		wg.Wait()
+
+		if len(deliveries) < webhookBatchSize {
+			return
+		}
+	}
+}
+
+func (wd *WebhookDispatcher) deliver(delivery *models.WebhookDelivery) {
+	// 1. Endpoint phải còn tồn tại và đang bật
+	endpoint, err := wd.webhookRepo.FindEndpointById(delivery.EndpointId)
+	if err != nil {
+		wd.finish(delivery.Id, map[string]interface{}{"status": "failed", "last_error": "webhook not found"})
+		return
+	}
+	if !endpoint.IsActive {
+		wd.finish(delivery.Id, map[string]interface{}{"status": "failed", "last_error": "webhook is disabled"})
+		return
+	}
+
+	// 2. Gửi request đã ký
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, endpoint.Url, bytes.NewReader(body))
+	if err != nil {
+		wd.finish(delivery.Id, map[string]interface{}{"status": "failed", "last_error": err.Error()})
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "LMS-Webhooks/1.0")
+	req.Header.Set("X-LMS-Event", delivery.EventType)
+	req.Header.Set("X-LMS-Delivery", strconv.FormatUint(uint64(delivery.Id), 10))
+	req.Header.Set("X-LMS-Signature", utils.SignWebhookPayload(endpoint.Secret, time.Now().Unix(), body))
+
+	started := time.Now()
+	resp, err := wd.client.Do(req)
+	updates := map[string]interface{}{
+		"duration_ms": int(time.Since(started).Milliseconds()),
+	}
+
+	if err != nil {
+		updates["response_status"] = 0
+		updates["response_body"] = ""
+		wd.retry(delivery, updates, err.Error())
+		return
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseBody))
+	updates["response_status"] = resp.StatusCode
+	updates["response_body"] = string(responseBody)
+
+	// 3. 2xx = thành công, còn lại retry
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		wd.retry(delivery, updates, fmt.Sprintf("endpoint responded with status %d", resp.StatusCode))
+		return
+	}
+
+	updates["status"] = "succeeded"
+	updates["delivered_at"] = time.Now()
+	updates["last_error"] = ""
+	wd.finish(delivery.Id, updates)
+}
+
+// retry hẹn lần gửi tiếp theo hoặc đánh dấu failed khi hết số lần thử (admin có thể replay)
+func (wd *WebhookDispatcher) retry(delivery *models.WebhookDelivery, updates map[string]interface{}, lastError string) {
+	updates["last_error"] = lastError
+	if delivery.Attempts >= wd.maxAttempts {
+		updates["status"] = "failed"
+	} else {
+		updates["status"] = "pending"
+		updates["next_attempt_at"] = time.Now().Add(webhookRetryDelay(delivery.Attempts))
+	}
+	wd.finish(delivery.Id, updates)
+}
+
+func (wd *WebhookDispatcher) finish(deliveryId uint, updates map[string]interface{}) {
+	updates["locked_at"] = nil
+	if err := wd.webhookRepo.UpdateDelivery(deliveryId, updates); err != nil {
+		log.Printf("Failed to update webhook delivery %d: %v", deliveryId, err)
+	}
+}
+
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempts && delay < webhookRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > webhookRetryMaxDelay {
+		delay = webhookRetryMaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,438 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event gửi khi admin bấm "test" endpoint - không subscribe được
+const webhookTestEvent = "webhook.test"
+
+// Các domain event có thể gửi qua webhook
+var webhookEventTypes = []dto.WebhookEventTypeItem{
+	{Type: dto.EventUserRegistered, Description: "A new user account was registered"},
+	{Type: dto.EventOrderPaid, Description: "An order was paid"},
+	{Type: dto.EventOrderRefunded, Description: "A paid order was refunded"},
+	{Type: dto.EventEnrollmentCreated, Description: "A student enrolled in a course"},
+	{Type: dto.EventEnrollmentCompleted, Description: "A student completed every lesson of a course"},
+	{Type: dto.EventLessonCompleted, Description: "A student completed a lesson"},
+	{Type: dto.EventReviewCreated, Description: "A student reviewed a course"},
+	{Type: dto.EventOrganizationInvoicePaid, Description: "An organization paid a seat license invoice"},
+	{Type: dto.EventSubscriptionPaid, Description: "A subscription period was paid (new subscription or renewal)"},
+	{Type: dto.EventSubscriptionPaymentFailed, Description: "A subscription renewal payment failed and its grace period started"},
+	{Type: dto.EventSubscriptionCancelled, Description: "A student cancelled their subscription"},
+	{Type: dto.EventSubscriptionExpired, Description: "A subscription ended and its catalog access was revoked"},
+	{Type: dto.EventBundlePurchased, Description: "A student purchased a course bundle"},
+}
+
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository) WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+	}
+}
+
+func (ws *webhookService) GetEventTypes() *dto.GetWebhookEventTypesResponse {
+	return &dto.GetWebhookEventTypesResponse{EventTypes: webhookEventTypes}
+}
+
+func (ws *webhookService) GetWebhooks(req *dto.GetWebhooksQueryRequest) (*dto.GetWebhooksResponse, error) {
+	// 1. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	// 2. Prepare filters
+	filters := make(map[string]interface{})
+	if req.IsActive != nil {
+		filters["is_active"] = *req.IsActive
+	}
+
+	// 3. Lấy danh sách endpoint
+	endpoints, total, err := ws.webhookRepo.GetEndpoints(offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get webhooks", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.WebhookItem, len(endpoints))
+	for i := range endpoints {
+		items[i] = toWebhookItem(&endpoints[i])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetWebhooksResponse{
+		Webhooks: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+func (ws *webhookService) CreateWebhook(adminId uint, req *dto.CreateWebhookRequest) (*dto.WebhookSecretResponse, error) {
+	// 1. Validate URL và event types
+	if !utils.IsWebhookURL(req.Url) {
+		return nil, utils.NewError("Webhook URL must be an absolute http(s) URL", utils.ErrCodeBadRequest)
+	}
+
+	eventTypes, err := normalizeWebhookEventTypes(req.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Sinh secret để ký payload
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to generate webhook secret", utils.ErrCodeInternal)
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	// 3. Lưu endpoint
+	endpoint := &models.WebhookEndpoint{
+		Url:         strings.TrimSpace(req.Url),
+		Description: strings.TrimSpace(req.Description),
+		EventTypes:  strings.Join(eventTypes, ","),
+		Secret:      secret,
+		IsActive:    isActive,
+		CreatedBy:   adminId,
+	}
+
+	if err := ws.webhookRepo.CreateEndpoint(endpoint); err != nil {
+		return nil, utils.WrapError(err, "Failed to create webhook", utils.ErrCodeInternal)
+	}
+
+	return &dto.WebhookSecretResponse{
+		WebhookItem: toWebhookItem(endpoint),
+		Secret:      secret,
+	}, nil
+}
+
+func (ws *webhookService) GetWebhook(webhookId uint) (*dto.WebhookItem, error) {
+	endpoint, err := ws.webhookRepo.FindEndpointById(webhookId)
+	if err != nil {
+		return nil, utils.NewError("Webhook not found", utils.ErrCodeNotFound)
+	}
+
+	item := toWebhookItem(endpoint)
+	return &item, nil
+}
+
+func (ws *webhookService) UpdateWebhook(webhookId uint, req *dto.UpdateWebhookRequest) (*dto.WebhookItem, error) {
+	// 1. Kiểm tra endpoint tồn tại
+	if _, err := ws.webhookRepo.FindEndpointById(webhookId); err != nil {
+		return nil, utils.NewError("Webhook not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Chuẩn bị updates
+	updates := make(map[string]interface{})
+	if req.Url != nil {
+		if !utils.IsWebhookURL(*req.Url) {
+			return nil, utils.NewError("Webhook URL must be an absolute http(s) URL", utils.ErrCodeBadRequest)
+		}
+		updates["url"] = strings.TrimSpace(*req.Url)
+	}
+	if req.Description != nil {
+		updates["description"] = strings.TrimSpace(*req.Description)
+	}
+	if req.EventTypes != nil {
+		eventTypes, err := normalizeWebhookEventTypes(req.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+		updates["event_types"] = strings.Join(eventTypes, ",")
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+
+	if len(updates) == 0 {
+		return nil, utils.NewError("No fields to update", utils.ErrCodeBadRequest)
+	}
+
+	// 3. Cập nhật
+	if err := ws.webhookRepo.UpdateEndpoint(webhookId, updates); err != nil {
+		return nil, utils.WrapError(err, "Failed to update webhook", utils.ErrCodeInternal)
+	}
+
+	return ws.GetWebhook(webhookId)
+}
+
+func (ws *webhookService) DeleteWebhook(webhookId uint) (*dto.DeleteWebhookResponse, error) {
+	if _, err := ws.webhookRepo.FindEndpointById(webhookId); err != nil {
+		return nil, utils.NewError("Webhook not found", utils.ErrCodeNotFound)
+	}
+
+	if err := ws.webhookRepo.DeleteEndpoint(webhookId); err != nil {
+		return nil, utils.WrapError(err, "Failed to delete webhook", utils.ErrCodeInternal)
+	}
+
+	return &dto.DeleteWebhookResponse{
+		Message: "Webhook deleted successfully",
+		Id:      webhookId,
+	}, nil
+}
+
+func (ws *webhookService) RotateSecret(webhookId uint) (*dto.WebhookSecretResponse, error) {
+	// 1. Kiểm tra endpoint tồn tại
+	endpoint, err := ws.webhookRepo.FindEndpointById(webhookId)
+	if err != nil {
+		return nil, utils.NewError("Webhook not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Sinh secret mới - các lần gửi tiếp theo (kể cả retry) dùng secret mới
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to generate webhook secret", utils.ErrCodeInternal)
+	}
+
+	if err := ws.webhookRepo.UpdateEndpoint(webhookId, map[string]interface{}{"secret": secret}); err != nil {
+		return nil, utils.WrapError(err, "Failed to rotate webhook secret", utils.ErrCodeInternal)
+	}
+
+	endpoint.Secret = secret
+	return &dto.WebhookSecretResponse{
+		WebhookItem: toWebhookItem(endpoint),
+		Secret:      secret,
+	}, nil
+}
+
+func (ws *webhookService) SendTestEvent(webhookId uint) (*dto.WebhookDeliveryItem, error) {
+	// 1. Kiểm tra endpoint tồn tại
+	endpoint, err := ws.webhookRepo.FindEndpointById(webhookId)
+	if err != nil {
+		return nil, utils.NewError("Webhook not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Tạo delivery với event test
+	data, _ := json.Marshal(map[string]interface{}{
+		"webhook_id": endpoint.Id,
+		"message":    "This is a test event",
+	})
+	payload, err := json.Marshal(dto.WebhookPayload{
+		Id:        "test_" + uuid.New().String(),
+		Type:      webhookTestEvent,
+		CreatedAt: time.Now(),
+		Data:      data,
+	})
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to build test event", utils.ErrCodeInternal)
+	}
+
+	delivery := models.WebhookDelivery{
+		EndpointId:    endpoint.Id,
+		EventType:     webhookTestEvent,
+		Payload:       string(payload),
+		Status:        "pending",
+		NextAttemptAt: time.Now(),
+	}
+
+	return ws.createDelivery(delivery)
+}
+
+func (ws *webhookService) GetDeliveries(webhookId uint, req *dto.GetWebhookDeliveriesQueryRequest) (*dto.GetWebhookDeliveriesResponse, error) {
+	// 1. Kiểm tra endpoint tồn tại
+	if _, err := ws.webhookRepo.FindEndpointById(webhookId); err != nil {
+		return nil, utils.NewError("Webhook not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Set default values
+	page := 1
+	limit := 20
+	if req.Page > 0 {
+		page = req.Page
+	}
+	if req.Limit > 0 {
+		limit = req.Limit
+	}
+	offset := (page - 1) * limit
+
+	// 3. Prepare filters
+	filters := make(map[string]interface{})
+	if req.Status != "" {
+		filters["status"] = req.Status
+	}
+	if req.EventType != "" {
+		filters["event_type"] = req.EventType
+	}
+
+	// 4. Lấy log gửi
+	deliveries, total, err := ws.webhookRepo.GetEndpointDeliveries(webhookId, offset, limit, filters)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get webhook deliveries", utils.ErrCodeInternal)
+	}
+
+	items := make([]dto.WebhookDeliveryItem, len(deliveries))
+	for i := range deliveries {
+		items[i] = toWebhookDeliveryItem(&deliveries[i])
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	return &dto.GetWebhookDeliveriesResponse{
+		Deliveries: items,
+		Pagination: dto.PaginationInfo{
+			Page:       page,
+			Limit:      limit,
+			Total:      total,
+			TotalPages: totalPages,
+			HasNext:    page < totalPages,
+			HasPrev:    page > 1,
+		},
+	}, nil
+}
+
+// ReplayDelivery gửi lại đúng payload của một delivery cũ dưới dạng delivery mới (giữ nguyên log cũ)
+func (ws *webhookService) ReplayDelivery(deliveryId uint) (*dto.WebhookDeliveryItem, error) {
+	// 1. Kiểm tra delivery tồn tại
+	original, err := ws.webhookRepo.FindDeliveryById(deliveryId)
+	if err != nil {
+		return nil, utils.NewError("Webhook delivery not found", utils.ErrCodeNotFound)
+	}
+
+	// 2. Endpoint phải còn tồn tại (delivery của endpoint đã xóa không preload được)
+	if original.Endpoint.Id == 0 {
+		return nil, utils.NewError("Webhook has been deleted", utils.ErrCodeConflict)
+	}
+
+	// 3. Không replay delivery đang chờ gửi
+	if original.Status == "pending" || original.Status == "processing" {
+		return nil, utils.NewError("Delivery is still in progress", utils.ErrCodeConflict)
+	}
+
+	delivery := models.WebhookDelivery{
+		EndpointId:    original.EndpointId,
+		EventId:       original.EventId,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        "pending",
+		NextAttemptAt: time.Now(),
+		ReplayOfId:    &original.Id,
+	}
+
+	return ws.createDelivery(delivery)
+}
+
+func (ws *webhookService) createDelivery(delivery models.WebhookDelivery) (*dto.WebhookDeliveryItem, error) {
+	deliveries := []models.WebhookDelivery{delivery}
+	if err := ws.webhookRepo.CreateDeliveries(deliveries); err != nil {
+		return nil, utils.WrapError(err, "Failed to queue webhook delivery", utils.ErrCodeInternal)
+	}
+
+	item := toWebhookDeliveryItem(&deliveries[0])
+	return &item, nil
+}
+
+// normalizeWebhookEventTypes kiểm tra và loại trùng event type (danh sách rỗng = tất cả event)
+func normalizeWebhookEventTypes(eventTypes []string) ([]string, error) {
+	supported := make(map[string]bool, len(webhookEventTypes))
+	for _, item := range webhookEventTypes {
+		supported[item.Type] = true
+	}
+
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if !supported[eventType] {
+			return nil, utils.NewError(fmt.Sprintf("Unsupported webhook event type: %s", eventType), utils.ErrCodeBadRequest)
+		}
+		if seen[eventType] {
+			continue
+		}
+		seen[eventType] = true
+		result = append(result, eventType)
+	}
+	return result, nil
+}
+
+func splitWebhookEventTypes(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+// webhookSubscribes kiểm tra endpoint có nhận loại event này không
+func webhookSubscribes(endpoint *models.WebhookEndpoint, eventType string) bool {
+	if endpoint.EventTypes == "" {
+		return true
+	}
+	for _, subscribed := range splitWebhookEventTypes(endpoint.EventTypes) {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func generateWebhookSecret() (string, error) {
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", err
+	}
+	return "whsec_" + token, nil
+}
+
+func toWebhookItem(endpoint *models.WebhookEndpoint) dto.WebhookItem {
+	hint := endpoint.Secret
+	if len(hint) > 4 {
+		hint = hint[len(hint)-4:]
+	}
+
+	return dto.WebhookItem{
+		Id:          endpoint.Id,
+		Url:         endpoint.Url,
+		Description: endpoint.Description,
+		EventTypes:  splitWebhookEventTypes(endpoint.EventTypes),
+		IsActive:    endpoint.IsActive,
+		SecretHint:  hint,
+		CreatedBy:   endpoint.CreatedBy,
+		CreatedAt:   endpoint.CreatedAt,
+		UpdatedAt:   endpoint.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryItem(delivery *models.WebhookDelivery) dto.WebhookDeliveryItem {
+	return dto.WebhookDeliveryItem{
+		Id:             delivery.Id,
+		EndpointId:     delivery.EndpointId,
+		EventId:        delivery.EventId,
+		EventType:      delivery.EventType,
+		Payload:        json.RawMessage(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		ResponseBody:   delivery.ResponseBody,
+		LastError:      delivery.LastError,
+		DurationMs:     delivery.DurationMs,
+		DeliveredAt:    delivery.DeliveredAt,
+		ReplayOfId:     delivery.ReplayOfId,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
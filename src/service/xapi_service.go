@@ -0,0 +1,294 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Số statement tối đa mỗi trang khi query
+	xapiMaxLimit = 100
+	// Client dùng khi platform tự ghi statement vào LRS tích hợp
+	xapiPlatformClient = "lms"
+)
+
+type xapiService struct {
+	xapiRepo repository.XapiRepository
+}
+
+func NewXapiService(xapiRepo repository.XapiRepository) XapiService {
+	return &xapiService{
+		xapiRepo: xapiRepo,
+	}
+}
+
+func (xs *xapiService) About() *dto.XapiAboutResponse {
+	return &dto.XapiAboutResponse{Version: []string{dto.XapiVersion}}
+}
+
+// PutStatement lưu statement với id do client chọn (PUT /xapi/statements?statementId=...)
+func (xs *xapiService) PutStatement(client, statementId string, body []byte) error {
+	statements, err := parseXapiStatements(body)
+	if err != nil {
+		return err
+	}
+	if len(statements) != 1 {
+		return utils.NewError("PUT accepts a single statement", utils.ErrCodeBadRequest)
+	}
+	statementId = strings.ToLower(statementId)
+
+	// id trong body (nếu có) phải khớp tham số statementId
+	if raw, ok := statements[0]["id"]; ok {
+		id, err := xapiUUID(raw, "statement.id")
+		if err != nil {
+			return utils.NewError(err.Error(), utils.ErrCodeValidation)
+		}
+		if id != statementId {
+			return utils.NewError("statement.id: does not match the statementId parameter", utils.ErrCodeBadRequest)
+		}
+	}
+	statements[0]["id"] = statementId
+
+	_, err = xs.storeStatements(client, statements)
+	return err
+}
+
+// PostStatements lưu một statement hoặc một batch, trả về id theo thứ tự gửi lên
+func (xs *xapiService) PostStatements(client string, body []byte) ([]string, error) {
+	statements, err := parseXapiStatements(body)
+	if err != nil {
+		return nil, err
+	}
+	return xs.storeStatements(client, statements)
+}
+
+// storeStatements kiểm tra và lưu cả batch (all-or-nothing).
+// Statement trùng id với nội dung giống hệt được bỏ qua; khác nội dung thì trả về conflict.
+func (xs *xapiService) storeStatements(client string, items []map[string]interface{}) ([]string, error) {
+	// 1. Kiểm tra từng statement
+	statements := make([]*xapiStatement, 0, len(items))
+	for i, item := range items {
+		path := "statement"
+		if len(items) > 1 {
+			path = fmt.Sprintf("statements[%d]", i)
+		}
+		statement, err := validateXapiStatement(item, path)
+		if err != nil {
+			return nil, utils.NewError(err.Error(), utils.ErrCodeValidation)
+		}
+		statements = append(statements, statement)
+	}
+
+	// 2. Gán id còn thiếu, không cho phép trùng id trong cùng batch
+	seen := make(map[string]bool, len(statements))
+	ids := make([]string, 0, len(statements))
+	for _, statement := range statements {
+		if statement.statementId == "" {
+			statement.statementId = uuid.New().String()
+		}
+		if seen[statement.statementId] {
+			return nil, utils.NewError(fmt.Sprintf("Statement id %s appears more than once in the batch", statement.statementId), utils.ErrCodeBadRequest)
+		}
+		seen[statement.statementId] = true
+		ids = append(ids, statement.statementId)
+	}
+
+	// 3. So sánh với statement đã lưu
+	existing, err := xs.xapiRepo.FindByStatementIds(ids)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to check existing statements", utils.ErrCodeInternal)
+	}
+	stored := make(map[string]string, len(existing))
+	for _, item := range existing {
+		stored[item.StatementId] = item.ContentHash
+	}
+
+	// 4. Gán các thuộc tính do LRS quản lý
+	now := time.Now().UTC()
+	authority := map[string]interface{}{
+		"objectType": "Agent",
+		"account": map[string]interface{}{
+			"homePage": utils.GetEnv("BASE_URL", "http://localhost:8080"),
+			"name":     client,
+		},
+	}
+
+	records := make([]models.XapiStatement, 0, len(statements))
+	for _, statement := range statements {
+		if hash, exists := stored[statement.statementId]; exists {
+			if hash != statement.contentHash {
+				return nil, utils.NewError(fmt.Sprintf("Statement %s already exists with different content", statement.statementId), utils.ErrCodeConflict)
+			}
+			continue
+		}
+
+		timestamp := now
+		if value, ok := statement.data["timestamp"].(string); ok {
+			timestamp, _ = time.Parse(time.RFC3339Nano, value)
+		} else {
+			statement.data["timestamp"] = now.Format(time.RFC3339Nano)
+		}
+
+		statement.data["id"] = statement.statementId
+		statement.data["stored"] = now.Format(time.RFC3339Nano)
+		statement.data["authority"] = authority
+		if _, ok := statement.data["version"]; !ok {
+			statement.data["version"] = "1.0.0"
+		}
+
+		encoded, err := json.Marshal(statement.data)
+		if err != nil {
+			return nil, utils.WrapError(err, "Failed to encode statement", utils.ErrCodeInternal)
+		}
+
+		records = append(records, models.XapiStatement{
+			StatementId:     statement.statementId,
+			ActorKey:        statement.actorKey,
+			ObjectAgentKey:  statement.objectAgentKey,
+			VerbId:          statement.verbId,
+			ActivityId:      statement.activityId,
+			Registration:    statement.registration,
+			VoidedStatement: statement.voidedStatement,
+			ContentHash:     statement.contentHash,
+			Statement:       string(encoded),
+			Timestamp:       timestamp,
+			Stored:          now,
+		})
+	}
+
+	// 5. Lưu và cập nhật trạng thái voided
+	if err := xs.xapiRepo.CreateStatements(records); err != nil {
+		return nil, utils.WrapError(err, "Failed to store statements", utils.ErrCodeInternal)
+	}
+
+	return ids, nil
+}
+
+// GetStatement trả về một statement theo statementId (chưa bị void) hoặc voidedStatementId (đã bị void)
+func (xs *xapiService) GetStatement(statementId string, voided bool) (json.RawMessage, error) {
+	statement, err := xs.xapiRepo.FindStatement(strings.ToLower(statementId), voided)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to get statement", utils.ErrCodeInternal)
+	}
+	if statement == nil {
+		return nil, utils.NewError("Statement not found", utils.ErrCodeNotFound)
+	}
+	return json.RawMessage(statement.Statement), nil
+}
+
+func (xs *xapiService) GetStatements(req *dto.GetXapiStatementsQueryRequest) (*dto.XapiStatementResult, error) {
+	// 1. Chuyển tham số query thành filter
+	filters := make(map[string]interface{})
+
+	if req.Agent != "" {
+		decoder := json.NewDecoder(strings.NewReader(req.Agent))
+		decoder.UseNumber()
+		var agent interface{}
+		if err := decoder.Decode(&agent); err != nil {
+			return nil, utils.NewError("agent: must be a JSON Agent or Group", utils.ErrCodeBadRequest)
+		}
+		key, err := validateXapiAgent(agent, "agent", true)
+		if err != nil {
+			return nil, utils.NewError(err.Error(), utils.ErrCodeValidation)
+		}
+		if key == "" {
+			return nil, utils.NewError("agent: must have an inverse functional identifier", utils.ErrCodeBadRequest)
+		}
+		filters["agent"] = key
+	}
+
+	if req.Verb != "" {
+		if _, err := xapiIRI(req.Verb, "verb"); err != nil {
+			return nil, utils.NewError(err.Error(), utils.ErrCodeBadRequest)
+		}
+		filters["verb_id"] = req.Verb
+	}
+
+	if req.Activity != "" {
+		if _, err := xapiIRI(req.Activity, "activity"); err != nil {
+			return nil, utils.NewError(err.Error(), utils.ErrCodeBadRequest)
+		}
+		filters["activity_id"] = req.Activity
+	}
+
+	if req.Registration != "" {
+		filters["registration"] = strings.ToLower(req.Registration)
+	}
+
+	for field, value := range map[string]string{"since": req.Since, "until": req.Until} {
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, utils.NewError(field+": must be an ISO 8601 timestamp with time zone", utils.ErrCodeBadRequest)
+		}
+		filters[field] = parsed
+	}
+
+	// limit = 0 nghĩa là "tối đa server cho phép"
+	limit := req.Limit
+	if limit <= 0 || limit > xapiMaxLimit {
+		limit = xapiMaxLimit
+	}
+
+	// 2. Lấy thêm một bản ghi để biết còn trang sau không
+	statements, err := xs.xapiRepo.QueryStatements(filters, req.Cursor, req.Ascending, limit+1)
+	if err != nil {
+		return nil, utils.WrapError(err, "Failed to query statements", utils.ErrCodeInternal)
+	}
+
+	more := ""
+	if len(statements) > limit {
+		statements = statements[:limit]
+		more = xapiMoreURL(req, statements[len(statements)-1].Id)
+	}
+
+	// 3. Trả về statement gốc đã lưu
+	result := &dto.XapiStatementResult{
+		Statements: make([]json.RawMessage, 0, len(statements)),
+		More:       more,
+	}
+	for _, statement := range statements {
+		result.Statements = append(result.Statements, json.RawMessage(statement.Statement))
+	}
+
+	return result, nil
+}
+
+// xapiMoreURL tạo URL trang tiếp theo với cùng filter và cursor là bản ghi cuối của trang hiện tại
+func xapiMoreURL(req *dto.GetXapiStatementsQueryRequest, cursor uint) string {
+	query := url.Values{}
+	params := map[string]string{
+		"agent":        req.Agent,
+		"verb":         req.Verb,
+		"activity":     req.Activity,
+		"registration": req.Registration,
+		"since":        req.Since,
+		"until":        req.Until,
+	}
+	for key, value := range params {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	if req.Limit > 0 {
+		query.Set("limit", strconv.Itoa(req.Limit))
+	}
+	if req.Ascending {
+		query.Set("ascending", "true")
+	}
+	query.Set("cursor", strconv.FormatUint(uint64(cursor), 10))
+
+	return "/api/v1/xapi/statements?" + query.Encode()
+}
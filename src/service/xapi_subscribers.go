@@ -0,0 +1,277 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"lms/src/dto"
+	"lms/src/models"
+	"lms/src/repository"
+	"lms/src/utils"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Verb và activity type của ADL registry
+const (
+	xapiVerbLaunched   = "http://adlnet.gov/expapi/verbs/launched"
+	xapiVerbProgressed = "http://adlnet.gov/expapi/verbs/progressed"
+	xapiVerbCompleted  = "http://adlnet.gov/expapi/verbs/completed"
+	xapiVerbPassed     = "http://adlnet.gov/expapi/verbs/passed"
+	xapiVerbFailed     = "http://adlnet.gov/expapi/verbs/failed"
+
+	xapiActivityCourse     = "http://adlnet.gov/expapi/activities/course"
+	xapiActivityLesson     = "http://adlnet.gov/expapi/activities/lesson"
+	xapiActivityAssessment = "http://adlnet.gov/expapi/activities/assessment"
+
+	// Extension progress (0-100) của cmi5, được các LRS phổ biến hiểu
+	xapiProgressExtension = "https://w3id.org/xapi/cmi5/result/extensions/progress"
+	xapiPlatform          = "LMS"
+	xapiLanguage          = "en-US"
+)
+
+var xapiVerbDisplay = map[string]string{
+	xapiVerbLaunched:   "launched",
+	xapiVerbProgressed: "progressed",
+	xapiVerbCompleted:  "completed",
+	xapiVerbPassed:     "passed",
+	xapiVerbFailed:     "failed",
+}
+
+// xapiDeliver gửi statement của một tenant tới LRS; trả về lỗi để dispatcher retry
+type xapiDeliver func(tenantId uint, statement *dto.XapiStatement) error
+
+// RegisterXapiSubscribers đăng ký subscriber chuyển domain event thành xAPI statement.
+// Statement được gửi tới LRS ngoài (XAPI_LRS_ENDPOINT) hoặc lưu vào LRS tích hợp của tenant phát sinh event khi không cấu hình.
+func RegisterXapiSubscribers(
+	dispatcher *EventDispatcher,
+	xapiServiceFor func(tenantId uint) XapiService,
+	userRepo repository.UserRepository,
+	courseRepo repository.CourseRepository,
+	lessonRepo repository.LessonRepository,
+	quizRepo repository.QuizRepository,
+) {
+	if utils.GetEnv("XAPI_ENABLED", "true") != "true" {
+		return
+	}
+
+	deliver := newXapiDelivery(xapiServiceFor)
+	builder := &xapiStatementBuilder{
+		baseURL:    strings.TrimRight(utils.GetEnv("BASE_URL", "http://localhost:8080"), "/"),
+		userRepo:   userRepo,
+		courseRepo: courseRepo,
+		lessonRepo: lessonRepo,
+		quizRepo:   quizRepo,
+	}
+
+	dispatcher.Subscribe(dto.EventLessonLaunched, "xapi", handleXapiEvent(deliver, func(event dto.LessonLaunchedEvent) *dto.XapiStatement {
+		return builder.lessonStatement(xapiVerbLaunched, event.UserId, event.CourseId, event.LessonId, nil, event.LaunchedAt)
+	}))
+
+	dispatcher.Subscribe(dto.EventLessonProgressed, "xapi", handleXapiEvent(deliver, func(event dto.LessonProgressedEvent) *dto.XapiStatement {
+		result := &dto.XapiResult{Extensions: map[string]interface{}{xapiProgressExtension: event.Progress}}
+		return builder.lessonStatement(xapiVerbProgressed, event.UserId, event.CourseId, event.LessonId, result, event.ProgressedAt)
+	}))
+
+	dispatcher.Subscribe(dto.EventLessonCompleted, "xapi", handleXapiEvent(deliver, func(event dto.LessonCompletedEvent) *dto.XapiStatement {
+		completion := true
+		result := &dto.XapiResult{Completion: &completion}
+		return builder.lessonStatement(xapiVerbCompleted, event.UserId, event.CourseId, event.LessonId, result, event.CompletedAt)
+	}))
+
+	dispatcher.Subscribe(dto.EventEnrollmentCompleted, "xapi", handleXapiEvent(deliver, func(event dto.EnrollmentCompletedEvent) *dto.XapiStatement {
+		completion := true
+		return &dto.XapiStatement{
+			Actor:     builder.actor(event.UserId),
+			Verb:      xapiVerb(xapiVerbCompleted),
+			Object:    builder.course(event.CourseId),
+			Result:    &dto.XapiResult{Completion: &completion},
+			Context:   &dto.XapiContext{Platform: xapiPlatform},
+			Timestamp: event.CompletedAt,
+		}
+	}))
+
+	dispatcher.Subscribe(dto.EventQuizSubmitted, "xapi", handleXapiEvent(deliver, func(event dto.QuizSubmittedEvent) *dto.XapiStatement {
+		verb := xapiVerbFailed
+		if event.IsPassed {
+			verb = xapiVerbPassed
+		}
+
+		scaled, raw, min, max := event.Score/100, event.Score, 0.0, 100.0
+		success, completion := event.IsPassed, true
+		return &dto.XapiStatement{
+			Actor:  builder.actor(event.UserId),
+			Verb:   xapiVerb(verb),
+			Object: builder.quiz(event.QuizId),
+			Result: &dto.XapiResult{
+				Score:      &dto.XapiScore{Scaled: &scaled, Raw: &raw, Min: &min, Max: &max},
+				Success:    &success,
+				Completion: &completion,
+			},
+			Context: &dto.XapiContext{
+				Platform: xapiPlatform,
+				ContextActivities: &dto.XapiContextActivities{
+					Parent:   []dto.XapiActivity{builder.lesson(event.LessonId)},
+					Grouping: []dto.XapiActivity{builder.course(event.CourseId)},
+				},
+			},
+			Timestamp: event.SubmittedAt,
+		}
+	}))
+}
+
+// handleXapiEvent dựng statement từ event và gửi đi. Id của statement suy ra từ id của outbox event
+// nên khi dispatcher retry, LRS nhận lại cùng statement thay vì tạo bản trùng.
+func handleXapiEvent[T dto.DomainEvent](deliver xapiDeliver, build func(event T) *dto.XapiStatement) EventHandler {
+	return func(event *models.OutboxEvent) error {
+		return HandleEvent(func(payload T) error {
+			statement := build(payload)
+			statement.Id = uuid.NewSHA1(uuid.NameSpaceURL, []byte(fmt.Sprintf("lms:outbox-event:%d", event.Id))).String()
+			statement.Timestamp = statement.Timestamp.UTC()
+			return deliver(event.TenantId, statement)
+		})(event)
+	}
+}
+
+// newXapiDelivery chọn nơi nhận statement: LRS ngoài nếu có XAPI_LRS_ENDPOINT, ngược lại LRS tích hợp của tenant
+func newXapiDelivery(xapiServiceFor func(tenantId uint) XapiService) xapiDeliver {
+	endpoint := strings.TrimRight(utils.GetEnv("XAPI_LRS_ENDPOINT", ""), "/")
+	if endpoint == "" {
+		return func(tenantId uint, statement *dto.XapiStatement) error {
+			body, err := json.Marshal(statement)
+			if err != nil {
+				return err
+			}
+
+			_, err = xapiServiceFor(tenantId).PostStatements(xapiPlatformClient, body)
+			var appErr *utils.AppError
+			if errors.As(err, &appErr) && appErr.Code == utils.ErrCodeConflict {
+				// Statement đã được lưu ở lần gửi trước (tên course/lesson có thể đã đổi)
+				log.Printf("xAPI statement %s already stored: %v", statement.Id, err)
+				return nil
+			}
+			return err
+		}
+	}
+
+	timeout, err := strconv.Atoi(utils.GetEnv("XAPI_LRS_TIMEOUT_SECONDS", "10"))
+	if err != nil || timeout < 1 {
+		timeout = 10
+	}
+	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
+	username := utils.GetEnv("XAPI_LRS_USERNAME", "")
+	password := utils.GetEnv("XAPI_LRS_PASSWORD", "")
+
+	return func(_ uint, statement *dto.XapiStatement) error {
+		body, err := json.Marshal(statement)
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequest(http.MethodPost, endpoint+"/statements", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Experience-API-Version", dto.XapiVersion)
+		if username != "" {
+			req.SetBasicAuth(username, password)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		// 409: LRS đã có statement với id này (lần gửi trước thành công nhưng không nhận được response)
+		if resp.StatusCode == http.StatusConflict {
+			log.Printf("xAPI statement %s already stored by the LRS", statement.Id)
+			return nil
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			return fmt.Errorf("LRS responded %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+		}
+		return nil
+	}
+}
+
+// xapiStatementBuilder dựng actor và activity từ dữ liệu của platform.
+// Dữ liệu không còn tồn tại (vd. lesson đã xóa) chỉ làm thiếu tên hiển thị, statement vẫn được gửi.
+type xapiStatementBuilder struct {
+	baseURL    string
+	userRepo   repository.UserRepository
+	courseRepo repository.CourseRepository
+	lessonRepo repository.LessonRepository
+	quizRepo   repository.QuizRepository
+}
+
+func xapiVerb(id string) dto.XapiVerb {
+	return dto.XapiVerb{Id: id, Display: map[string]string{xapiLanguage: xapiVerbDisplay[id]}}
+}
+
+// actor định danh học viên bằng account trên platform (không gửi email ra LRS)
+func (xb *xapiStatementBuilder) actor(userId uint) dto.XapiAgent {
+	agent := dto.XapiAgent{
+		ObjectType: "Agent",
+		Account:    &dto.XapiAccount{HomePage: xb.baseURL, Name: strconv.FormatUint(uint64(userId), 10)},
+	}
+	if user, err := xb.userRepo.FindById(userId); err == nil {
+		agent.Name = user.FullName
+	}
+	return agent
+}
+
+func (xb *xapiStatementBuilder) activity(path, activityType, name string) dto.XapiActivity {
+	definition := &dto.XapiActivityDefinition{Type: activityType}
+	if name != "" {
+		definition.Name = map[string]string{xapiLanguage: name}
+	}
+	return dto.XapiActivity{ObjectType: "Activity", Id: xb.baseURL + "/xapi/activities/" + path, Definition: definition}
+}
+
+func (xb *xapiStatementBuilder) course(courseId uint) dto.XapiActivity {
+	name := ""
+	if course, err := xb.courseRepo.FindById(courseId); err == nil {
+		name = course.Title
+	}
+	return xb.activity(fmt.Sprintf("courses/%d", courseId), xapiActivityCourse, name)
+}
+
+func (xb *xapiStatementBuilder) lesson(lessonId uint) dto.XapiActivity {
+	name := ""
+	if lessons, err := xb.lessonRepo.FindLessonByIds([]uint{lessonId}); err == nil && len(lessons) > 0 {
+		name = lessons[0].Title
+	}
+	return xb.activity(fmt.Sprintf("lessons/%d", lessonId), xapiActivityLesson, name)
+}
+
+func (xb *xapiStatementBuilder) quiz(quizId uint) dto.XapiActivity {
+	name := ""
+	if quiz, err := xb.quizRepo.FindById(quizId); err == nil {
+		name = quiz.Title
+	}
+	return xb.activity(fmt.Sprintf("quizzes/%d", quizId), xapiActivityAssessment, name)
+}
+
+func (xb *xapiStatementBuilder) lessonStatement(verb string, userId, courseId, lessonId uint, result *dto.XapiResult, timestamp time.Time) *dto.XapiStatement {
+	return &dto.XapiStatement{
+		Actor:  xb.actor(userId),
+		Verb:   xapiVerb(verb),
+		Object: xb.lesson(lessonId),
+		Result: result,
+		Context: &dto.XapiContext{
+			Platform:          xapiPlatform,
+			ContextActivities: &dto.XapiContextActivities{Parent: []dto.XapiActivity{xb.course(courseId)}},
+		},
+		Timestamp: timestamp,
+	}
+}
@@ -0,0 +1,698 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"lms/src/repository"
+	"lms/src/utils"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Các thuộc tính spec xAPI cho phép ở mỗi cấp; LRS phải từ chối thuộc tính lạ
+var (
+	xapiStatementKeys    = []string{"id", "actor", "verb", "object", "result", "context", "timestamp", "stored", "authority", "version", "attachments"}
+	xapiSubStatementKeys = []string{"objectType", "actor", "verb", "object", "result", "context", "timestamp", "attachments"}
+	xapiAgentKeys        = []string{"objectType", "name", "mbox", "mbox_sha1sum", "openid", "account", "member"}
+	xapiActivityKeys     = []string{"objectType", "id", "definition"}
+	xapiResultKeys       = []string{"score", "success", "completion", "response", "duration", "extensions"}
+	xapiScoreKeys        = []string{"scaled", "raw", "min", "max"}
+	xapiContextKeys      = []string{"registration", "instructor", "team", "contextActivities", "revision", "platform", "language", "statement", "extensions"}
+	xapiContextActKeys   = []string{"parent", "grouping", "category", "other"}
+)
+
+var xapiSha1Pattern = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+
+// xapiStatement là statement đã kiểm tra cùng các giá trị dùng để index
+type xapiStatement struct {
+	data            map[string]interface{}
+	statementId     string
+	actorKey        string
+	objectAgentKey  string
+	verbId          string
+	activityId      string
+	registration    string
+	voidedStatement string
+	contentHash     string
+}
+
+// parseXapiStatements đọc một statement hoặc mảng statement. Số được giữ nguyên dạng gốc (UseNumber).
+func parseXapiStatements(body []byte) ([]map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var payload interface{}
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, utils.NewError("Request body must be a JSON statement or an array of statements", utils.ErrCodeBadRequest)
+	}
+
+	switch value := payload.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{value}, nil
+	case []interface{}:
+		if len(value) == 0 {
+			return nil, utils.NewError("Statement batch is empty", utils.ErrCodeBadRequest)
+		}
+		statements := make([]map[string]interface{}, 0, len(value))
+		for i, item := range value {
+			statement, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, utils.NewError(fmt.Sprintf("statements[%d]: must be an object", i), utils.ErrCodeValidation)
+			}
+			statements = append(statements, statement)
+		}
+		return statements, nil
+	}
+
+	return nil, utils.NewError("Request body must be a JSON statement or an array of statements", utils.ErrCodeBadRequest)
+}
+
+// validateXapiStatement kiểm tra statement theo spec xAPI 1.0 và trích các giá trị để index.
+// Lỗi trả về có dạng "<path>: <message>".
+func validateXapiStatement(data map[string]interface{}, path string) (*xapiStatement, error) {
+	if err := validateXapiKeys(data, xapiStatementKeys, path); err != nil {
+		return nil, err
+	}
+
+	statement := &xapiStatement{data: data}
+
+	// 1. id (tùy chọn) phải là UUID
+	if raw, ok := data["id"]; ok {
+		id, err := xapiUUID(raw, path+".id")
+		if err != nil {
+			return nil, err
+		}
+		statement.statementId = id
+		data["id"] = id
+	}
+
+	// 2. actor, verb, object
+	actorKey, err := validateXapiAgent(data["actor"], path+".actor", true)
+	if err != nil {
+		return nil, err
+	}
+	statement.actorKey = actorKey
+
+	verbId, err := validateXapiVerb(data["verb"], path+".verb")
+	if err != nil {
+		return nil, err
+	}
+	statement.verbId = verbId
+
+	object, err := validateXapiObject(data["object"], path+".object", false)
+	if err != nil {
+		return nil, err
+	}
+	statement.activityId, statement.objectAgentKey = object.activityId, object.agentKey
+
+	// Voiding statement phải trỏ tới statement khác bằng StatementRef
+	if verbId == repository.XapiVoidedVerb {
+		if object.objectType != "StatementRef" {
+			return nil, fmt.Errorf("%s.object: a voiding statement must reference a StatementRef", path)
+		}
+		statement.voidedStatement = object.statementRef
+	}
+
+	// 3. result, context, timestamp, version, attachments
+	if err := validateXapiResult(data["result"], path+".result"); err != nil {
+		return nil, err
+	}
+
+	registration, err := validateXapiContext(data["context"], path+".context")
+	if err != nil {
+		return nil, err
+	}
+	statement.registration = registration
+
+	if err := validateXapiTimestamp(data["timestamp"], path+".timestamp"); err != nil {
+		return nil, err
+	}
+
+	if raw, ok := data["version"]; ok {
+		version, isString := raw.(string)
+		if !isString || !strings.HasPrefix(version, "1.0") {
+			return nil, fmt.Errorf("%s.version: only xAPI 1.0.x is supported", path)
+		}
+	}
+
+	if err := validateXapiAttachments(data["attachments"], path+".attachments"); err != nil {
+		return nil, err
+	}
+
+	// 4. Hash nội dung do client gửi (không tính các thuộc tính LRS gán) để so sánh khi trùng id.
+	// json.Marshal sắp xếp key của map nên kết quả ổn định.
+	content := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		switch key {
+		case "id", "stored", "authority", "version":
+			continue
+		}
+		content[key] = value
+	}
+	encoded, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	sum := sha256.Sum256(encoded)
+	statement.contentHash = hex.EncodeToString(sum[:])
+
+	return statement, nil
+}
+
+func validateXapiKeys(data map[string]interface{}, allowed []string, path string) error {
+	for key := range data {
+		found := false
+		for _, name := range allowed {
+			if key == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s.%s: property is not allowed", path, key)
+		}
+	}
+	return nil
+}
+
+func xapiObject(raw interface{}, path string) (map[string]interface{}, error) {
+	if raw == nil {
+		return nil, fmt.Errorf("%s: is required", path)
+	}
+	data, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: must be an object", path)
+	}
+	return data, nil
+}
+
+func xapiString(raw interface{}, path string) (string, error) {
+	value, ok := raw.(string)
+	if !ok {
+		return "", fmt.Errorf("%s: must be a string", path)
+	}
+	return value, nil
+}
+
+func xapiIRI(raw interface{}, path string) (string, error) {
+	value, err := xapiString(raw, path)
+	if err != nil {
+		return "", err
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Scheme == "" {
+		return "", fmt.Errorf("%s: must be an absolute IRI", path)
+	}
+	return value, nil
+}
+
+func xapiUUID(raw interface{}, path string) (string, error) {
+	value, err := xapiString(raw, path)
+	if err != nil {
+		return "", err
+	}
+	id, err := uuid.Parse(value)
+	if err != nil || len(value) != 36 {
+		return "", fmt.Errorf("%s: must be a UUID", path)
+	}
+	return id.String(), nil
+}
+
+func xapiNumber(raw interface{}, path string) (float64, error) {
+	number, ok := raw.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("%s: must be a number", path)
+	}
+	value, err := number.Float64()
+	if err != nil {
+		return 0, fmt.Errorf("%s: must be a number", path)
+	}
+	return value, nil
+}
+
+func xapiLanguageMap(raw interface{}, path string) error {
+	data, err := xapiObject(raw, path)
+	if err != nil {
+		return err
+	}
+	for key, value := range data {
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s.%s: must be a string", path, key)
+		}
+	}
+	return nil
+}
+
+// validateXapiAgent kiểm tra Agent/Group và trả về inverse functional identifier dạng chuẩn để query.
+// Agent phải có đúng một IFI; Group có thể ẩn danh (không IFI) nhưng khi đó phải có member.
+func validateXapiAgent(raw interface{}, path string, allowGroup bool) (string, error) {
+	data, err := xapiObject(raw, path)
+	if err != nil {
+		return "", err
+	}
+	if err := validateXapiKeys(data, xapiAgentKeys, path); err != nil {
+		return "", err
+	}
+
+	objectType := "Agent"
+	if value, ok := data["objectType"]; ok {
+		if objectType, err = xapiString(value, path+".objectType"); err != nil {
+			return "", err
+		}
+	}
+	if objectType != "Agent" && (objectType != "Group" || !allowGroup) {
+		return "", fmt.Errorf("%s.objectType: must be Agent or Group", path)
+	}
+
+	if value, ok := data["name"]; ok {
+		if _, err := xapiString(value, path+".name"); err != nil {
+			return "", err
+		}
+	}
+
+	key, err := xapiAgentKey(data, path)
+	if err != nil {
+		return "", err
+	}
+
+	if objectType == "Agent" {
+		if key == "" {
+			return "", fmt.Errorf("%s: an Agent must have exactly one of mbox, mbox_sha1sum, openid or account", path)
+		}
+		if _, ok := data["member"]; ok {
+			return "", fmt.Errorf("%s.member: property is only allowed for a Group", path)
+		}
+		return key, nil
+	}
+
+	members, hasMembers := data["member"]
+	if key == "" && !hasMembers {
+		return "", fmt.Errorf("%s: an anonymous Group must list its members", path)
+	}
+	if hasMembers {
+		list, ok := members.([]interface{})
+		if !ok || (key == "" && len(list) == 0) {
+			return "", fmt.Errorf("%s.member: must be an array of Agents", path)
+		}
+		for i, member := range list {
+			if _, err := validateXapiAgent(member, fmt.Sprintf("%s.member[%d]", path, i), false); err != nil {
+				return "", err
+			}
+		}
+	}
+	return key, nil
+}
+
+// xapiAgentKey trả về IFI của agent ("" nếu không có), lỗi nếu có nhiều hơn một IFI
+func xapiAgentKey(data map[string]interface{}, path string) (string, error) {
+	var keys []string
+
+	if value, ok := data["mbox"]; ok {
+		mbox, err := xapiString(value, path+".mbox")
+		if err != nil {
+			return "", err
+		}
+		if !strings.HasPrefix(mbox, "mailto:") || len(mbox) <= len("mailto:") {
+			return "", fmt.Errorf("%s.mbox: must be a mailto IRI", path)
+		}
+		keys = append(keys, "mbox:"+strings.ToLower(mbox))
+	}
+
+	if value, ok := data["mbox_sha1sum"]; ok {
+		sum, err := xapiString(value, path+".mbox_sha1sum")
+		if err != nil {
+			return "", err
+		}
+		if !xapiSha1Pattern.MatchString(sum) {
+			return "", fmt.Errorf("%s.mbox_sha1sum: must be a SHA1 hex digest", path)
+		}
+		keys = append(keys, "mbox_sha1sum:"+strings.ToLower(sum))
+	}
+
+	if value, ok := data["openid"]; ok {
+		openid, err := xapiIRI(value, path+".openid")
+		if err != nil {
+			return "", err
+		}
+		keys = append(keys, "openid:"+openid)
+	}
+
+	if value, ok := data["account"]; ok {
+		account, err := xapiObject(value, path+".account")
+		if err != nil {
+			return "", err
+		}
+		if err := validateXapiKeys(account, []string{"homePage", "name"}, path+".account"); err != nil {
+			return "", err
+		}
+		homePage, err := xapiIRI(account["homePage"], path+".account.homePage")
+		if err != nil {
+			return "", err
+		}
+		name, err := xapiString(account["name"], path+".account.name")
+		if err != nil {
+			return "", err
+		}
+		if name == "" {
+			return "", fmt.Errorf("%s.account.name: must not be empty", path)
+		}
+		keys = append(keys, "account:"+homePage+"|"+name)
+	}
+
+	if len(keys) > 1 {
+		return "", fmt.Errorf("%s: must not have more than one of mbox, mbox_sha1sum, openid or account", path)
+	}
+	if len(keys) == 0 {
+		return "", nil
+	}
+	return keys[0], nil
+}
+
+func validateXapiVerb(raw interface{}, path string) (string, error) {
+	data, err := xapiObject(raw, path)
+	if err != nil {
+		return "", err
+	}
+	if err := validateXapiKeys(data, []string{"id", "display"}, path); err != nil {
+		return "", err
+	}
+
+	id, err := xapiIRI(data["id"], path+".id")
+	if err != nil {
+		return "", err
+	}
+	if display, ok := data["display"]; ok {
+		if err := xapiLanguageMap(display, path+".display"); err != nil {
+			return "", err
+		}
+	}
+	return id, nil
+}
+
+type xapiObjectInfo struct {
+	objectType   string
+	activityId   string
+	agentKey     string
+	statementRef string
+}
+
+func validateXapiObject(raw interface{}, path string, inSubStatement bool) (*xapiObjectInfo, error) {
+	data, err := xapiObject(raw, path)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &xapiObjectInfo{objectType: "Activity"}
+	if value, ok := data["objectType"]; ok {
+		if info.objectType, err = xapiString(value, path+".objectType"); err != nil {
+			return nil, err
+		}
+	}
+
+	switch info.objectType {
+	case "Activity":
+		if info.activityId, err = validateXapiActivity(data, path); err != nil {
+			return nil, err
+		}
+	case "Agent", "Group":
+		if info.agentKey, err = validateXapiAgent(data, path, true); err != nil {
+			return nil, err
+		}
+	case "StatementRef":
+		if err := validateXapiKeys(data, []string{"objectType", "id"}, path); err != nil {
+			return nil, err
+		}
+		if info.statementRef, err = xapiUUID(data["id"], path+".id"); err != nil {
+			return nil, err
+		}
+		data["id"] = info.statementRef
+	case "SubStatement":
+		if inSubStatement {
+			return nil, fmt.Errorf("%s: a SubStatement must not contain another SubStatement", path)
+		}
+		if err := validateXapiSubStatement(data, path); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%s.objectType: must be Activity, Agent, Group, StatementRef or SubStatement", path)
+	}
+	return info, nil
+}
+
+func validateXapiActivity(data map[string]interface{}, path string) (string, error) {
+	if err := validateXapiKeys(data, xapiActivityKeys, path); err != nil {
+		return "", err
+	}
+	id, err := xapiIRI(data["id"], path+".id")
+	if err != nil {
+		return "", err
+	}
+
+	if value, ok := data["definition"]; ok {
+		definition, err := xapiObject(value, path+".definition")
+		if err != nil {
+			return "", err
+		}
+		for _, field := range []string{"name", "description"} {
+			if value, ok := definition[field]; ok {
+				if err := xapiLanguageMap(value, path+".definition."+field); err != nil {
+					return "", err
+				}
+			}
+		}
+		if value, ok := definition["type"]; ok {
+			if _, err := xapiIRI(value, path+".definition.type"); err != nil {
+				return "", err
+			}
+		}
+	}
+	return id, nil
+}
+
+func validateXapiSubStatement(data map[string]interface{}, path string) error {
+	if err := validateXapiKeys(data, xapiSubStatementKeys, path); err != nil {
+		return err
+	}
+	if _, err := validateXapiAgent(data["actor"], path+".actor", true); err != nil {
+		return err
+	}
+	if _, err := validateXapiVerb(data["verb"], path+".verb"); err != nil {
+		return err
+	}
+	if _, err := validateXapiObject(data["object"], path+".object", true); err != nil {
+		return err
+	}
+	if err := validateXapiResult(data["result"], path+".result"); err != nil {
+		return err
+	}
+	if _, err := validateXapiContext(data["context"], path+".context"); err != nil {
+		return err
+	}
+	return validateXapiTimestamp(data["timestamp"], path+".timestamp")
+}
+
+func validateXapiResult(raw interface{}, path string) error {
+	if raw == nil {
+		return nil
+	}
+	data, err := xapiObject(raw, path)
+	if err != nil {
+		return err
+	}
+	if err := validateXapiKeys(data, xapiResultKeys, path); err != nil {
+		return err
+	}
+
+	for _, field := range []string{"success", "completion"} {
+		if value, ok := data[field]; ok {
+			if _, isBool := value.(bool); !isBool {
+				return fmt.Errorf("%s.%s: must be a boolean", path, field)
+			}
+		}
+	}
+	if value, ok := data["response"]; ok {
+		if _, err := xapiString(value, path+".response"); err != nil {
+			return err
+		}
+	}
+	if value, ok := data["duration"]; ok {
+		duration, err := xapiString(value, path+".duration")
+		if err != nil {
+			return err
+		}
+		if _, err := utils.ParseScormDuration(utils.ScormVersion2004, duration); err != nil {
+			return fmt.Errorf("%s.duration: %v", path, err)
+		}
+	}
+	if value, ok := data["extensions"]; ok {
+		if _, err := xapiObject(value, path+".extensions"); err != nil {
+			return err
+		}
+	}
+
+	if value, ok := data["score"]; ok {
+		return validateXapiScore(value, path+".score")
+	}
+	return nil
+}
+
+func validateXapiScore(raw interface{}, path string) error {
+	data, err := xapiObject(raw, path)
+	if err != nil {
+		return err
+	}
+	if err := validateXapiKeys(data, xapiScoreKeys, path); err != nil {
+		return err
+	}
+
+	values := make(map[string]float64, len(data))
+	for key, value := range data {
+		number, err := xapiNumber(value, path+"."+key)
+		if err != nil {
+			return err
+		}
+		values[key] = number
+	}
+
+	if scaled, ok := values["scaled"]; ok && (scaled < -1 || scaled > 1) {
+		return fmt.Errorf("%s.scaled: must be between -1 and 1", path)
+	}
+	min, hasMin := values["min"]
+	max, hasMax := values["max"]
+	if hasMin && hasMax && min >= max {
+		return fmt.Errorf("%s.max: must be greater than min", path)
+	}
+	if raw, ok := values["raw"]; ok {
+		if (hasMin && raw < min) || (hasMax && raw > max) {
+			return fmt.Errorf("%s.raw: must be between min and max", path)
+		}
+	}
+	return nil
+}
+
+// validateXapiContext kiểm tra context và trả về registration (nếu có)
+func validateXapiContext(raw interface{}, path string) (string, error) {
+	if raw == nil {
+		return "", nil
+	}
+	data, err := xapiObject(raw, path)
+	if err != nil {
+		return "", err
+	}
+	if err := validateXapiKeys(data, xapiContextKeys, path); err != nil {
+		return "", err
+	}
+
+	registration := ""
+	if value, ok := data["registration"]; ok {
+		if registration, err = xapiUUID(value, path+".registration"); err != nil {
+			return "", err
+		}
+		data["registration"] = registration
+	}
+
+	if value, ok := data["instructor"]; ok {
+		if _, err := validateXapiAgent(value, path+".instructor", true); err != nil {
+			return "", err
+		}
+	}
+	if value, ok := data["team"]; ok {
+		if _, err := validateXapiAgent(value, path+".team", true); err != nil {
+			return "", err
+		}
+	}
+	for _, field := range []string{"revision", "platform", "language"} {
+		if value, ok := data[field]; ok {
+			if _, err := xapiString(value, path+"."+field); err != nil {
+				return "", err
+			}
+		}
+	}
+	if value, ok := data["statement"]; ok {
+		if _, err := validateXapiObject(value, path+".statement", true); err != nil {
+			return "", err
+		}
+	}
+	if value, ok := data["extensions"]; ok {
+		if _, err := xapiObject(value, path+".extensions"); err != nil {
+			return "", err
+		}
+	}
+
+	if value, ok := data["contextActivities"]; ok {
+		activities, err := xapiObject(value, path+".contextActivities")
+		if err != nil {
+			return "", err
+		}
+		if err := validateXapiKeys(activities, xapiContextActKeys, path+".contextActivities"); err != nil {
+			return "", err
+		}
+		for key, value := range activities {
+			itemPath := path + ".contextActivities." + key
+			// Spec cho phép một activity đơn lẻ, LRS chuẩn hóa thành mảng
+			list, isList := value.([]interface{})
+			if !isList {
+				list = []interface{}{value}
+				activities[key] = list
+			}
+			for i, item := range list {
+				activity, err := xapiObject(item, fmt.Sprintf("%s[%d]", itemPath, i))
+				if err != nil {
+					return "", err
+				}
+				if objectType, ok := activity["objectType"]; ok && objectType != "Activity" {
+					return "", fmt.Errorf("%s[%d].objectType: must be Activity", itemPath, i)
+				}
+				if _, err := validateXapiActivity(activity, fmt.Sprintf("%s[%d]", itemPath, i)); err != nil {
+					return "", err
+				}
+			}
+		}
+	}
+	return registration, nil
+}
+
+func validateXapiTimestamp(raw interface{}, path string) error {
+	if raw == nil {
+		return nil
+	}
+	value, err := xapiString(raw, path)
+	if err != nil {
+		return err
+	}
+	if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+		return fmt.Errorf("%s: must be an ISO 8601 timestamp with time zone", path)
+	}
+	return nil
+}
+
+// Chỉ hỗ trợ attachment tham chiếu bằng fileUrl (không nhận multipart/mixed)
+func validateXapiAttachments(raw interface{}, path string) error {
+	if raw == nil {
+		return nil
+	}
+	list, ok := raw.([]interface{})
+	if !ok {
+		return fmt.Errorf("%s: must be an array", path)
+	}
+	for i, item := range list {
+		attachment, err := xapiObject(item, fmt.Sprintf("%s[%d]", path, i))
+		if err != nil {
+			return err
+		}
+		if _, ok := attachment["fileUrl"]; !ok {
+			return fmt.Errorf("%s[%d].fileUrl: attachment content must be referenced by fileUrl", path, i)
+		}
+		if _, err := xapiIRI(attachment["fileUrl"], fmt.Sprintf("%s[%d].fileUrl", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"lms/src/config"
+	"lms/src/utils"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStorage lưu file trên ổ đĩa. Object public nằm trong PublicDir (được serve tại /uploads),
+// object private nằm trong PrivateDir và chỉ đọc/ghi được qua presigned URL.
+type LocalStorage struct {
+	publicDir  string
+	privateDir string
+	baseURL    string
+}
+
+func NewLocalStorage(cfg config.LocalStorageConfig) *LocalStorage {
+	return &LocalStorage{
+		publicDir:  cfg.PublicDir,
+		privateDir: cfg.PrivateDir,
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+	}
+}
+
+// Path trả về đường dẫn file trên ổ đĩa của key
+func (ls *LocalStorage) Path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+
+	if strings.HasPrefix(key, PrivatePrefix) {
+		return filepath.Join(ls.privateDir, filepath.FromSlash(strings.TrimPrefix(key, PrivatePrefix))), nil
+	}
+
+	return filepath.Join(ls.publicDir, filepath.FromSlash(key)), nil
+}
+
+func (ls *LocalStorage) Put(key string, body io.Reader, size int64, contentType string) error {
+	path, err := ls.Path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	// Ghi ra file tạm rồi rename để tránh file dở dang
+	tmpPath := path + ".tmp"
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, body); err != nil {
+		out.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+func (ls *LocalStorage) Get(key string) (io.ReadCloser, error) {
+	path, err := ls.Path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	return file, err
+}
+
+func (ls *LocalStorage) Exists(key string) (bool, error) {
+	path, err := ls.Path(key)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (ls *LocalStorage) Delete(key string) error {
+	path, err := ls.Path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (ls *LocalStorage) DeletePrefix(prefix string) error {
+	path, err := ls.Path(strings.TrimSuffix(prefix, "/"))
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(path)
+}
+
+func (ls *LocalStorage) URL(key string) string {
+	return fmt.Sprintf("%s/uploads/%s", ls.baseURL, key)
+}
+
+func (ls *LocalStorage) PresignGet(key string, expires time.Duration) (string, error) {
+	return ls.presign("GET", key, expires)
+}
+
+func (ls *LocalStorage) PresignPut(key, contentType string, expires time.Duration) (string, error) {
+	return ls.presign("PUT", key, expires)
+}
+
+func (ls *LocalStorage) presign(method, key string, expires time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+
+	expiresAt := strconv.FormatInt(time.Now().Add(expires).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expiresAt)
+	query.Set("signature", signLocal(method, key, expiresAt))
+
+	return fmt.Sprintf("%s/api/v1/storage/local/%s?%s", ls.baseURL, key, query.Encode()), nil
+}
+
+// VerifyPresigned kiểm tra chữ ký của presigned URL local
+func (ls *LocalStorage) VerifyPresigned(method, key, expires, signature string) error {
+	if !hmac.Equal([]byte(signLocal(method, key, expires)), []byte(signature)) {
+		return errors.New("invalid signature")
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return errors.New("presigned URL expired")
+	}
+
+	return nil
+}
+
+func signLocal(method, key, expires string) string {
+	secret := utils.GetEnv("STORAGE_SIGNING_SECRET", string(utils.JWTSecret))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(method + "\n" + key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,389 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"lms/src/config"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// S3Storage lưu file trên object storage tương thích S3 (AWS S3, MinIO, ...).
+// Request được ký bằng AWS Signature Version 4.
+type S3Storage struct {
+	endpoint     *url.URL
+	region       string
+	bucket       string
+	accessKey    string
+	secretKey    string
+	publicURL    string
+	usePathStyle bool
+	client       *http.Client
+}
+
+func NewS3Storage(cfg config.S3Config) (*S3Storage, error) {
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint: %s", cfg.Endpoint)
+	}
+
+	if cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required")
+	}
+
+	s := &S3Storage{
+		endpoint:     endpoint,
+		region:       cfg.Region,
+		bucket:       cfg.Bucket,
+		accessKey:    cfg.AccessKey,
+		secretKey:    cfg.SecretKey,
+		usePathStyle: cfg.UsePathStyle,
+		client:       &http.Client{Timeout: 5 * time.Minute},
+	}
+
+	s.publicURL = strings.TrimRight(cfg.PublicURL, "/")
+	if s.publicURL == "" {
+		s.publicURL = strings.TrimRight(s.objectURL("").String(), "/")
+	}
+
+	return s, nil
+}
+
+func (s *S3Storage) Put(key string, body io.Reader, size int64, contentType string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(key).String(), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+func (s *S3Storage) Get(key string) (io.ReadCloser, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, s.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+func (s *S3Storage) Exists(key string) (bool, error) {
+	if err := validateKey(key); err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequest(http.MethodHead, s.objectURL(key).String(), nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := s.do(req)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	return true, nil
+}
+
+func (s *S3Storage) Delete(key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, s.objectURL(key).String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// s3ListResult là phần cần dùng của response ListObjectsV2
+type s3ListResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *S3Storage) DeletePrefix(prefix string) error {
+	if err := validateKey(prefix); err != nil {
+		return err
+	}
+
+	continuationToken := ""
+	for {
+		// 1. Liệt kê object theo prefix (tối đa 1000 mỗi trang)
+		listURL := s.objectURL("")
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+		listURL.RawQuery = query.Encode()
+
+		req, err := http.NewRequest(http.MethodGet, listURL.String(), nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.do(req)
+		if err != nil {
+			return err
+		}
+
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		// 2. Xóa từng object
+		for _, object := range result.Contents {
+			if err := s.Delete(object.Key); err != nil {
+				return err
+			}
+		}
+
+		if !result.IsTruncated {
+			return nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+func (s *S3Storage) URL(key string) string {
+	return fmt.Sprintf("%s/%s", s.publicURL, key)
+}
+
+func (s *S3Storage) PresignGet(key string, expires time.Duration) (string, error) {
+	return s.presign(http.MethodGet, key, expires)
+}
+
+func (s *S3Storage) PresignPut(key, contentType string, expires time.Duration) (string, error) {
+	return s.presign(http.MethodPut, key, expires)
+}
+
+// objectURL tạo URL của object theo kiểu path-style (MinIO) hoặc virtual-hosted (AWS)
+func (s *S3Storage) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	if s.usePathStyle {
+		u.Path = "/" + s.bucket + "/" + key
+	} else {
+		u.Host = s.bucket + "." + s.endpoint.Host
+		u.Path = "/" + key
+	}
+	u.RawPath = s3EncodePath(u.Path)
+	return &u
+}
+
+func (s *S3Storage) do(req *http.Request) (*http.Response, error) {
+	s.sign(req, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3 %s %s failed: %s %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return resp, nil
+}
+
+// sign ký request bằng header Authorization (SigV4, payload không ký)
+func (s *S3Storage) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+
+	headerNames := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if req.Header.Get("Content-Type") != "" {
+		headerNames = append(headerNames, "content-type")
+	}
+	sort.Strings(headerNames)
+
+	canonicalHeaders := ""
+	for _, name := range headerNames {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders += name + ":" + strings.TrimSpace(value) + "\n"
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3EncodePath(req.URL.Path),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n")
+
+	scope := s.scope(now)
+	signature := s.signature(now, amzDate, scope, canonicalRequest)
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.accessKey, scope, signedHeaders, signature,
+	))
+}
+
+// presign tạo presigned URL (SigV4 query string)
+func (s *S3Storage) presign(method, key string, expires time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.scope(now)
+
+	u := s.objectURL(key)
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.accessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		s3EncodePath(u.Path),
+		s3CanonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+
+	query.Set("X-Amz-Signature", s.signature(now, amzDate, scope, canonicalRequest))
+	u.RawQuery = s3CanonicalQuery(query)
+
+	return u.String(), nil
+}
+
+func (s *S3Storage) scope(now time.Time) string {
+	return fmt.Sprintf("%s/%s/s3/aws4_request", now.Format("20060102"), s.region)
+}
+
+func (s *S3Storage) signature(now time.Time, amzDate, scope, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{s3Algorithm, amzDate, scope, hex.EncodeToString(hash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3Encode URI-encode theo quy tắc của SigV4 (chỉ giữ nguyên ký tự unreserved)
+func s3Encode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func s3EncodePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = s3Encode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, s3Encode(key)+"="+s3Encode(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
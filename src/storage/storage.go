@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"lms/src/config"
+	"log"
+	"strings"
+	"time"
+)
+
+// Key bắt đầu bằng PrivatePrefix không bao giờ được serve public, chỉ truy cập qua presigned URL
+const PrivatePrefix = "private/"
+
+var ErrNotFound = errors.New("object not found")
+
+// Storage là abstraction cho nơi lưu file (local filesystem hoặc S3-compatible như AWS S3/MinIO)
+type Storage interface {
+	Put(key string, body io.Reader, size int64, contentType string) error
+	Get(key string) (io.ReadCloser, error)
+	Exists(key string) (bool, error)
+	Delete(key string) error
+	DeletePrefix(prefix string) error
+	// URL trả về URL public của object (không dùng cho key private)
+	URL(key string) string
+	PresignGet(key string, expires time.Duration) (string, error)
+	PresignPut(key, contentType string, expires time.Duration) (string, error)
+}
+
+var Store Storage
+
+func InitStorage() error {
+	cfg := config.NewStorageConfig()
+
+	switch cfg.Driver {
+	case "local":
+		Store = NewLocalStorage(cfg.Local)
+	case "s3":
+		s3Storage, err := NewS3Storage(cfg.S3)
+		if err != nil {
+			return err
+		}
+		Store = s3Storage
+	default:
+		return fmt.Errorf("unsupported storage driver: %s", cfg.Driver)
+	}
+
+	log.Printf("Storage initialized with %s driver", cfg.Driver)
+
+	return nil
+}
+
+// KeyFromURL lấy lại key từ URL public do store tạo ra (false nếu URL không thuộc storage)
+func KeyFromURL(store Storage, url string) (string, bool) {
+	if store == nil || url == "" {
+		return "", false
+	}
+
+	base := store.URL("")
+	if !strings.HasPrefix(url, base) {
+		return "", false
+	}
+
+	key := strings.TrimPrefix(url, base)
+	return key, key != ""
+}
+
+// OwnerPrefix là thư mục riêng của user trong folder (vd. avatars/12/), dùng cho key cấp qua presigned upload
+func OwnerPrefix(folder string, ownerId uint) string {
+	return fmt.Sprintf("%s/%d/", folder, ownerId)
+}
+
+// ValidateClientURL kiểm tra URL do client gửi lên (avatar_url, thumbnail_url...):
+// URL bên ngoài được chấp nhận, URL thuộc storage phải nằm trong prefix được cấp cho user.
+// Tránh việc trỏ vào object của người khác rồi để server xóa hoặc copy nó.
+func ValidateClientURL(store Storage, url, prefix string) error {
+	key, ok := KeyFromURL(store, url)
+	if !ok {
+		return nil
+	}
+	if strings.HasPrefix(key, PrivatePrefix) || !strings.HasPrefix(key, prefix) || validateKey(key) != nil {
+		return fmt.Errorf("file URL must be an external URL or a file uploaded under %s", prefix)
+	}
+	return nil
+}
+
+// DeleteByURL xóa object theo URL public, bỏ qua URL bên ngoài. Dùng khi dọn file mồ côi.
+// Chỉ xóa key nằm trong prefix mong đợi (vd. "avatars/") và không bao giờ xóa key private.
+func DeleteByURL(store Storage, url, prefix string) {
+	key, ok := KeyFromURL(store, url)
+	if !ok {
+		return
+	}
+	if prefix == "" || strings.HasPrefix(key, PrivatePrefix) || !strings.HasPrefix(key, prefix) || validateKey(key) != nil {
+		log.Printf("Refused to delete object %s outside %q", key, prefix)
+		return
+	}
+
+	if err := store.Delete(key); err != nil {
+		log.Printf("Failed to delete object %s: %v", key, err)
+	}
+}
+
+// DeletePrefixQuietly xóa toàn bộ object theo prefix và chỉ log lỗi
+func DeletePrefixQuietly(store Storage, prefix string) {
+	if store == nil || prefix == "" {
+		return
+	}
+
+	if err := store.DeletePrefix(prefix); err != nil {
+		log.Printf("Failed to delete objects with prefix %s: %v", prefix, err)
+	}
+}
+
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "..") {
+		return fmt.Errorf("invalid object key: %q", key)
+	}
+	return nil
+}
@@ -3,91 +3,212 @@ package utils
 import (
 	"errors"
 	"fmt"
-	"io"
 	"mime/multipart"
 	"net/http"
-	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
 )
 
-var allowExts = map[string]bool{
-	".jpg":  true,
-	".jpeg": true,
-	".png":  true,
+// FileRule là allow-list cho một loại file upload
+type FileRule struct {
+	// Extension được phép -> content type lưu kèm file
+	Extensions map[string]string
+	// Extension -> MIME type nội dung file phải có khi khác content type (vd. .docx là file zip)
+	SniffedTypes map[string]string
+	// MIME type phát hiện từ nội dung file được chấp nhận
+	MimeTypes map[string]bool
+	MaxSize   int64
 }
-var allowMimeTypes = map[string]bool{
-	"image/jpeg": true,
-	"image/png":  true,
+
+// expectedMimeType trả về MIME type mà nội dung file có extension ext phải khớp
+func (rule FileRule) expectedMimeType(ext string) string {
+	if mimeType, ok := rule.SniffedTypes[ext]; ok {
+		return mimeType
+	}
+
+	// http.DetectContentType nhận mọi file văn bản là text/plain
+	mimeType := baseMimeType(rule.Extensions[ext])
+	if strings.HasPrefix(mimeType, "text/") || mimeType == "application/json" {
+		return "text/plain"
+	}
+	return mimeType
 }
 
-const maxSize = 5 << 20
+// ImageFileRule dùng cho avatar, thumbnail
+var ImageFileRule = FileRule{
+	Extensions: map[string]string{
+		".jpg":  "image/jpeg",
+		".jpeg": "image/jpeg",
+		".png":  "image/png",
+	},
+	MimeTypes: map[string]bool{
+		"image/jpeg": true,
+		"image/png":  true,
+	},
+	MaxSize: 5 << 20,
+}
 
-func ValidateAndSaveFile(fileHeader *multipart.FileHeader, uploadDir string) (string, error) {
-	// Check extension in filename
-	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
-	if !allowExts[ext] {
-		return "", errors.New("unsupported file extension")
+// Các loại file đính kèm lesson: slides, tài liệu, source code, worksheet
+var attachmentExtensions = map[string]string{
+	".pdf":  "application/pdf",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".zip":  "application/zip",
+	".txt":  "text/plain; charset=utf-8",
+	".md":   "text/markdown; charset=utf-8",
+	".csv":  "text/csv; charset=utf-8",
+	".json": "application/json",
+	".go":   "text/plain; charset=utf-8",
+	".py":   "text/plain; charset=utf-8",
+	".js":   "text/plain; charset=utf-8",
+	".ts":   "text/plain; charset=utf-8",
+	".java": "text/plain; charset=utf-8",
+	".c":    "text/plain; charset=utf-8",
+	".cpp":  "text/plain; charset=utf-8",
+	".sql":  "text/plain; charset=utf-8",
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+}
+
+// File Office Open XML là file zip
+var attachmentSniffedTypes = map[string]string{
+	".pptx": "application/zip",
+	".docx": "application/zip",
+	".xlsx": "application/zip",
+}
+
+const defaultAttachmentMimeTypes = "application/pdf,application/zip,text/plain,image/jpeg,image/png"
+
+// AttachmentFileRule trả về allow-list cho file đính kèm lesson, cấu hình qua env:
+//   - ATTACHMENT_ALLOWED_EXTS: danh sách extension thay cho mặc định (vd. ".pdf,.zip"). Extension ngoài danh sách
+//     mặc định khai báo kèm content type, và MIME type nội dung nếu khác (vd. ".odt=application/vnd.oasis.opendocument.text|application/zip")
+//   - ATTACHMENT_ALLOWED_MIME_TYPES: MIME type phát hiện từ nội dung được chấp nhận (vd. "application/pdf,text/plain")
+//   - ATTACHMENT_MAX_SIZE_MB: giới hạn dung lượng
+func AttachmentFileRule() FileRule {
+	extensions := attachmentExtensions
+	sniffedTypes := attachmentSniffedTypes
+	if allowed := GetEnv("ATTACHMENT_ALLOWED_EXTS", ""); allowed != "" {
+		extensions = make(map[string]string)
+		sniffedTypes = make(map[string]string)
+		for _, item := range strings.Split(allowed, ",") {
+			ext, contentType, declared := strings.Cut(strings.TrimSpace(item), "=")
+			ext = strings.ToLower(strings.TrimSpace(ext))
+			if !strings.HasPrefix(ext, ".") {
+				continue
+			}
+
+			// 1. Extension mặc định dùng content type có sẵn
+			if !declared {
+				if contentType, ok := attachmentExtensions[ext]; ok {
+					extensions[ext] = contentType
+					if sniffed, ok := attachmentSniffedTypes[ext]; ok {
+						sniffedTypes[ext] = sniffed
+					}
+				}
+				continue
+			}
+
+			// 2. Extension khai báo thêm: "content-type" hoặc "content-type|mime-type-nội-dung"
+			contentType, sniffed, _ := strings.Cut(contentType, "|")
+			contentType = strings.TrimSpace(contentType)
+			if contentType == "" {
+				continue
+			}
+			extensions[ext] = contentType
+			if sniffed = strings.TrimSpace(sniffed); sniffed != "" {
+				sniffedTypes[ext] = sniffed
+			}
+		}
 	}
 
-	// Check size
-	if fileHeader.Size > maxSize {
-		return "", errors.New("file too large (max 5MB)")
+	mimeTypes := make(map[string]bool)
+	for _, mimeType := range strings.Split(GetEnv("ATTACHMENT_ALLOWED_MIME_TYPES", defaultAttachmentMimeTypes), ",") {
+		if mimeType = baseMimeType(mimeType); mimeType != "" {
+			mimeTypes[mimeType] = true
+		}
+	}
+
+	maxSizeMB, err := strconv.Atoi(GetEnv("ATTACHMENT_MAX_SIZE_MB", "50"))
+	if err != nil || maxSizeMB < 1 {
+		maxSizeMB = 50
+	}
+
+	return FileRule{
+		Extensions:   extensions,
+		SniffedTypes: sniffedTypes,
+		MimeTypes:    mimeTypes,
+		MaxSize:      int64(maxSizeMB) << 20,
+	}
+}
+
+// ValidateFile kiểm tra file upload theo rule, trả về tên file mới (uuid) và content type để lưu vào storage
+func ValidateFile(fileHeader *multipart.FileHeader, rule FileRule) (string, string, error) {
+	// Check extension và size
+	ext, err := ValidateFileName(fileHeader.Filename, fileHeader.Size, rule)
+	if err != nil {
+		return "", "", err
 	}
 
 	// Check file type
 	file, err := fileHeader.Open()
 	if err != nil {
-		return "", errors.New("cannot open file")
+		return "", "", errors.New("cannot open file")
 	}
 	defer file.Close()
 
 	buffer := make([]byte, 512)
-	_, err = file.Read(buffer)
+	n, err := file.Read(buffer)
 	if err != nil {
-		return "", errors.New("cannot read file")
+		return "", "", errors.New("cannot read file")
 	}
-
-	mimeType := http.DetectContentType(buffer)
-	if !allowMimeTypes[mimeType] {
-		return "", fmt.Errorf("invalid MIME type: %s", mimeType)
+	if err := ValidateFileContent(ext, buffer[:n], rule); err != nil {
+		return "", "", err
 	}
 
 	// Change file name
 	fileName := fmt.Sprintf("%s%s", uuid.New().String(), ext)
 
-	// Create folder if not exist
-	if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
-		return "", errors.New("cannot create upload folder")
-	}
+	return fileName, rule.Extensions[ext], nil
+}
 
-	// uploadDir "./upload" + filename "abc.jpg"
-	savePath := filepath.Join(uploadDir, fileName)
-	if err := saveFile(fileHeader, savePath); err != nil {
-		return "", err
+// ValidateFileContent kiểm tra MIME type phát hiện từ phần đầu nội dung file (tối đa 512 byte):
+// phải nằm trong allow-list của rule và khớp với extension (file .pdf chứa ảnh PNG bị từ chối)
+func ValidateFileContent(ext string, head []byte, rule FileRule) error {
+	mimeType := baseMimeType(http.DetectContentType(head))
+	if !rule.MimeTypes[mimeType] {
+		return fmt.Errorf("invalid MIME type: %s", mimeType)
 	}
-
-	return fileName, nil
+	if expected := rule.expectedMimeType(ext); mimeType != expected {
+		return fmt.Errorf("file content (%s) does not match extension %s", mimeType, ext)
+	}
+	return nil
 }
 
-func saveFile(fileHeader *multipart.FileHeader, destination string) error {
-	src, err := fileHeader.Open()
-	if err != nil {
-		return err
+// baseMimeType bỏ phần tham số (vd. "; charset=utf-8") của MIME type
+func baseMimeType(mimeType string) string {
+	if i := strings.Index(mimeType, ";"); i >= 0 {
+		mimeType = mimeType[:i]
 	}
-	defer src.Close()
+	return strings.ToLower(strings.TrimSpace(mimeType))
+}
 
-	out, err := os.Create(destination)
-	if err != nil {
-		return err
+// ValidateFileName kiểm tra extension và dung lượng theo rule (dùng cho presigned upload, file không đi qua server)
+func ValidateFileName(fileName string, fileSize int64, rule FileRule) (string, error) {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if _, ok := rule.Extensions[ext]; !ok {
+		return "", errors.New("unsupported file extension")
 	}
-	defer out.Close()
 
-	_, err = io.Copy(out, src)
+	if fileSize > rule.MaxSize {
+		return "", fmt.Errorf("file too large (max %dMB)", rule.MaxSize>>20)
+	}
 
-	return err
+	return ext, nil
 }
 
 func ValidateAvatarFile(fileHeader *multipart.FileHeader) error {
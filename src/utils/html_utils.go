@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Các tag được phép trong nội dung rich-text (announcement, ...) và attribute tương ứng
+var richTextTags = map[string][]string{
+	"p": nil, "br": nil, "hr": nil, "div": nil, "span": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil,
+	"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil,
+	"ul": nil, "ol": nil, "li": nil,
+	"blockquote": nil, "code": nil, "pre": nil,
+	"a":   {"href", "title"},
+	"img": {"src", "alt", "title"},
+}
+
+// Nội dung của các tag này bị bỏ hoàn toàn
+var droppedContentTags = map[string]bool{"script": true, "style": true, "iframe": true, "object": true, "embed": true, "noscript": true, "template": true}
+
+// SanitizeHTML giữ lại các tag rich-text an toàn (allow-list), bỏ script, event handler và URL javascript:
+func SanitizeHTML(input string) string {
+	var b strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	skipDepth := 0
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			// io.EOF hoặc HTML lỗi: trả về phần đã xử lý
+			return strings.TrimSpace(b.String())
+		}
+
+		token := tokenizer.Token()
+		switch tokenType {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if droppedContentTags[token.Data] {
+				if tokenType == html.StartTagToken {
+					skipDepth++
+				}
+				continue
+			}
+			allowedAttrs, ok := richTextTags[token.Data]
+			if !ok || skipDepth > 0 {
+				continue
+			}
+
+			b.WriteString("<" + token.Data)
+			for _, attr := range token.Attr {
+				if !containsString(allowedAttrs, attr.Key) {
+					continue
+				}
+				if (attr.Key == "href" || attr.Key == "src") && !isSafeURL(attr.Val) {
+					continue
+				}
+				b.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+			}
+			if token.Data == "a" {
+				b.WriteString(` rel="noopener noreferrer nofollow"`)
+			}
+			if tokenType == html.SelfClosingTagToken {
+				b.WriteString(" /")
+			}
+			b.WriteString(">")
+		case html.EndTagToken:
+			if droppedContentTags[token.Data] {
+				if skipDepth > 0 {
+					skipDepth--
+				}
+				continue
+			}
+			if _, ok := richTextTags[token.Data]; ok && skipDepth == 0 {
+				b.WriteString("</" + token.Data + ">")
+			}
+		case html.TextToken:
+			if skipDepth == 0 {
+				b.WriteString(html.EscapeString(token.Data))
+			}
+		}
+	}
+}
+
+// HTMLToText lấy phần text của HTML (dùng cho email dạng text, preview)
+func HTMLToText(input string) string {
+	var b strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	skipDepth := 0
+
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			return strings.TrimSpace(b.String())
+		}
+
+		token := tokenizer.Token()
+		switch tokenType {
+		case html.StartTagToken:
+			if droppedContentTags[token.Data] {
+				skipDepth++
+			}
+			if token.Data == "br" || token.Data == "p" || token.Data == "li" || token.Data == "div" {
+				b.WriteString("\n")
+			}
+		case html.SelfClosingTagToken:
+			if token.Data == "br" {
+				b.WriteString("\n")
+			}
+		case html.EndTagToken:
+			if droppedContentTags[token.Data] && skipDepth > 0 {
+				skipDepth--
+			}
+		case html.TextToken:
+			if skipDepth == 0 {
+				b.WriteString(token.Data)
+			}
+		}
+	}
+}
+
+// isSafeURL chỉ cho phép link http(s), mailto hoặc đường dẫn tương đối
+func isSafeURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -10,19 +10,23 @@ import (
 var JWTSecret = []byte(GetEnv("JWT_SECRET", "fallback-secret-key-please-change-in-production"))
 
 type JWTClaims struct {
-	UserId   uint   `json:"user_id"`
-	Email    string `json:"email"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	UserId     uint   `json:"user_id"`
+	Email      string `json:"email"`
+	Username   string `json:"username"`
+	Role       string `json:"role"`
+	TenantId   uint   `json:"tenant_id"`             // Token chỉ dùng được trên tenant đã cấp nó
+	SuperAdmin bool   `json:"super_admin,omitempty"` // Quản trị toàn bộ deployment
 	jwt.RegisteredClaims
 }
 
-func GenerateTokens(userId uint, username, role string) (string, string, error) {
+func GenerateTokens(userId uint, username, role string, tenantId uint, superAdmin bool) (string, string, error) {
 	// Access Token (24h)
 	accessClaims := &JWTClaims{
-		UserId:   userId,
-		Username: username,
-		Role:     role,
+		UserId:     userId,
+		Username:   username,
+		Role:       role,
+		TenantId:   tenantId,
+		SuperAdmin: superAdmin,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -39,9 +43,11 @@ func GenerateTokens(userId uint, username, role string) (string, string, error)
 
 	// Refresh Token (30 ngay)
 	refreshClaims := &JWTClaims{
-		UserId:   userId,
-		Username: username,
-		Role:     role,
+		UserId:     userId,
+		Username:   username,
+		Role:       role,
+		TenantId:   tenantId,
+		SuperAdmin: superAdmin,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(30 * 24 * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
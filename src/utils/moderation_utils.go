@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Từ khóa spam/tục tĩu mặc định, ghi đè bằng REVIEW_BLOCKED_WORDS (phân cách bởi dấu phẩy)
+const defaultBlockedWords = "fuck,shit,bitch,asshole,viagra,casino,porn,crypto giveaway,buy followers,click here,work from home"
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+`)
+
+// ContentFilter đánh dấu nội dung đáng ngờ (tục tĩu, spam) để giữ lại chờ kiểm duyệt
+type ContentFilter struct {
+	blockedWords  []string
+	maxLinks      int
+	maxRepeatRune int
+}
+
+// NewContentFilter đọc cấu hình từ biến môi trường:
+// REVIEW_BLOCKED_WORDS, REVIEW_MAX_LINKS (mặc định 0), REVIEW_MAX_REPEATED_CHARS (mặc định 8)
+func NewContentFilter() *ContentFilter {
+	var blockedWords []string
+	for _, word := range strings.Split(GetEnv("REVIEW_BLOCKED_WORDS", defaultBlockedWords), ",") {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			blockedWords = append(blockedWords, normalizeFilterText(word))
+		}
+	}
+
+	maxLinks, err := strconv.Atoi(GetEnv("REVIEW_MAX_LINKS", "0"))
+	if err != nil || maxLinks < 0 {
+		maxLinks = 0
+	}
+
+	maxRepeatRune, err := strconv.Atoi(GetEnv("REVIEW_MAX_REPEATED_CHARS", "8"))
+	if err != nil || maxRepeatRune < 2 {
+		maxRepeatRune = 8
+	}
+
+	return &ContentFilter{
+		blockedWords:  blockedWords,
+		maxLinks:      maxLinks,
+		maxRepeatRune: maxRepeatRune,
+	}
+}
+
+// Check trả về true kèm lý do nếu nội dung cần được kiểm duyệt trước khi hiển thị
+func (cf *ContentFilter) Check(text string) (bool, string) {
+	// 1. Từ khóa bị chặn (so khớp theo cả từ)
+	normalized := " " + normalizeFilterText(text) + " "
+	for _, word := range cf.blockedWords {
+		if strings.Contains(normalized, " "+word+" ") {
+			return true, fmt.Sprintf("contains blocked word %q", word)
+		}
+	}
+
+	// 2. Quá nhiều link
+	if links := len(linkPattern.FindAllString(text, -1)); links > cf.maxLinks {
+		return true, fmt.Sprintf("contains %d links", links)
+	}
+
+	// 3. Ký tự lặp lại liên tục (vd. "!!!!!!!!!!", "goooooooood")
+	var last rune
+	run := 0
+	for _, r := range text {
+		if r == last && !unicode.IsSpace(r) {
+			run++
+			if run >= cf.maxRepeatRune {
+				return true, "contains repeated characters"
+			}
+			continue
+		}
+		last = r
+		run = 1
+	}
+
+	// 4. Viết hoa gần như toàn bộ
+	letters, upper := 0, 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	if letters >= 20 && float64(upper)/float64(letters) > 0.7 {
+		return true, "mostly uppercase"
+	}
+
+	return false, ""
+}
+
+// normalizeFilterText chuyển về chữ thường và thay mọi ký tự không phải chữ/số bằng khoảng trắng
+func normalizeFilterText(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,20 @@
+package utils
+
+import "math"
+
+// Hệ số z cho khoảng tin cậy 95%
+const wilsonZ = 1.96
+
+// WilsonLowerBound trả về cận dưới khoảng tin cậy Wilson của tỉ lệ positive/total.
+// Review ít vote nhưng 100% hữu ích không vượt qua review nhiều vote với tỉ lệ cao.
+func WilsonLowerBound(positive, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+
+	n := float64(total)
+	p := float64(positive) / n
+	z2 := wilsonZ * wilsonZ
+
+	return (p + z2/(2*n) - wilsonZ*math.Sqrt((p*(1-p)+z2/(4*n))/n)) / (1 + z2/n)
+}
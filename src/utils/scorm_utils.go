@@ -0,0 +1,288 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ScormVersion12   = "1.2"
+	ScormVersion2004 = "2004"
+
+	// ScormManifestName là file manifest bắt buộc nằm ở gốc package
+	ScormManifestName = "imsmanifest.xml"
+)
+
+var scormSigningSecret = []byte(GetEnv("SCORM_SIGNING_SECRET", string(JWTSecret)))
+
+// ScormManifest là thông tin cần để chạy package, lấy từ imsmanifest.xml
+type ScormManifest struct {
+	Version      string
+	Identifier   string
+	Title        string
+	LaunchPath   string   // đường dẫn file launch trong package (không gồm query)
+	LaunchQuery  string   // parameters của item, nối vào URL launch
+	LaunchData   string   // adlcp:datafromlms / adlcp:dataFromLMS
+	MasteryScore *float64 // adlcp:masteryscore (SCORM 1.2)
+	ScoCount     int
+}
+
+type imsManifest struct {
+	XMLName       xml.Name         `xml:"manifest"`
+	Identifier    string           `xml:"identifier,attr"`
+	SchemaVersion string           `xml:"metadata>schemaversion"`
+	Organizations imsOrganizations `xml:"organizations"`
+	Resources     imsResources     `xml:"resources"`
+}
+
+type imsOrganizations struct {
+	Default       string            `xml:"default,attr"`
+	Organizations []imsOrganization `xml:"organization"`
+}
+
+type imsOrganization struct {
+	Identifier string    `xml:"identifier,attr"`
+	Title      string    `xml:"title"`
+	Items      []imsItem `xml:"item"`
+}
+
+type imsItem struct {
+	Identifier    string    `xml:"identifier,attr"`
+	IdentifierRef string    `xml:"identifierref,attr"`
+	Parameters    string    `xml:"parameters,attr"`
+	Title         string    `xml:"title"`
+	MasteryScore  string    `xml:"masteryscore"`
+	DataFromLMS12 string    `xml:"datafromlms"`
+	DataFromLMS   string    `xml:"dataFromLMS"`
+	Items         []imsItem `xml:"item"`
+}
+
+type imsResources struct {
+	Base      string        `xml:"base,attr"`
+	Resources []imsResource `xml:"resource"`
+}
+
+type imsResource struct {
+	Identifier  string `xml:"identifier,attr"`
+	Href        string `xml:"href,attr"`
+	Base        string `xml:"base,attr"`
+	ScormType12 string `xml:"scormtype,attr"`
+	ScormType   string `xml:"scormType,attr"`
+}
+
+func (r *imsResource) scormType() string {
+	if r.ScormType != "" {
+		return strings.ToLower(r.ScormType)
+	}
+	return strings.ToLower(r.ScormType12)
+}
+
+// ParseScormManifest đọc imsmanifest.xml, xác định version SCORM và SCO đầu tiên của organization mặc định.
+// Package nhiều SCO chỉ chạy SCO đầu tiên (một lesson = một SCO).
+func ParseScormManifest(data []byte) (*ScormManifest, error) {
+	var manifest imsManifest
+	if err := xml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid imsmanifest.xml: %v", err)
+	}
+
+	// 1. Version: theo schemaversion, fallback theo namespace của manifest
+	version := ScormVersion12
+	schemaVersion := strings.ToLower(strings.TrimSpace(manifest.SchemaVersion))
+	switch {
+	case schemaVersion == "1.2":
+	case strings.Contains(schemaVersion, "2004") || strings.Contains(schemaVersion, "1.3"):
+		version = ScormVersion2004
+	case schemaVersion == "" && strings.Contains(manifest.XMLName.Space, "imscp_v1p1"):
+		version = ScormVersion2004
+	case schemaVersion != "":
+		return nil, fmt.Errorf("unsupported SCORM schemaversion %q", manifest.SchemaVersion)
+	}
+
+	// 2. Organization mặc định (hoặc organization đầu tiên)
+	if len(manifest.Organizations.Organizations) == 0 {
+		return nil, errors.New("imsmanifest.xml has no organization")
+	}
+	organization := &manifest.Organizations.Organizations[0]
+	for i := range manifest.Organizations.Organizations {
+		if manifest.Organizations.Organizations[i].Identifier == manifest.Organizations.Default {
+			organization = &manifest.Organizations.Organizations[i]
+			break
+		}
+	}
+
+	resources := make(map[string]*imsResource, len(manifest.Resources.Resources))
+	for i := range manifest.Resources.Resources {
+		resources[manifest.Resources.Resources[i].Identifier] = &manifest.Resources.Resources[i]
+	}
+
+	// 3. Item đầu tiên (duyệt theo thứ tự cây) trỏ tới resource có href, ưu tiên SCO
+	var launchItem *imsItem
+	var launchResource *imsResource
+	scoCount := 0
+	var walk func(items []imsItem)
+	walk = func(items []imsItem) {
+		for i := range items {
+			item := &items[i]
+			if resource, ok := resources[item.IdentifierRef]; ok && resource.Href != "" {
+				isSco := resource.scormType() == "sco"
+				if isSco {
+					scoCount++
+				}
+				if launchItem == nil || (isSco && launchResource.scormType() != "sco") {
+					launchItem, launchResource = item, resource
+				}
+			}
+			walk(item.Items)
+		}
+	}
+	walk(organization.Items)
+
+	if launchItem == nil {
+		return nil, errors.New("imsmanifest.xml has no launchable item")
+	}
+
+	// 4. Đường dẫn launch = xml:base của resources + xml:base của resource + href
+	href, query, _ := strings.Cut(launchResource.Href, "?")
+	launchPath := path.Clean("/" + manifest.Resources.Base + "/" + launchResource.Base + "/" + href)
+	if strings.Contains(href, "://") {
+		return nil, errors.New("launch resource must be a file inside the package")
+	}
+
+	parameters := strings.TrimPrefix(strings.TrimSpace(launchItem.Parameters), "?")
+	if query != "" && parameters != "" {
+		query += "&" + parameters
+	} else if parameters != "" {
+		query = parameters
+	}
+
+	result := &ScormManifest{
+		Version:     version,
+		Identifier:  manifest.Identifier,
+		Title:       strings.TrimSpace(organization.Title),
+		LaunchPath:  strings.TrimPrefix(launchPath, "/"),
+		LaunchQuery: query,
+		LaunchData:  strings.TrimSpace(launchItem.DataFromLMS12 + launchItem.DataFromLMS),
+		ScoCount:    scoCount,
+	}
+	if result.Title == "" {
+		result.Title = strings.TrimSpace(launchItem.Title)
+	}
+
+	if masteryScore := strings.TrimSpace(launchItem.MasteryScore); masteryScore != "" {
+		score, err := strconv.ParseFloat(masteryScore, 64)
+		if err != nil || score < 0 || score > 100 {
+			return nil, fmt.Errorf("invalid masteryscore %q", launchItem.MasteryScore)
+		}
+		result.MasteryScore = &score
+	}
+
+	return result, nil
+}
+
+// GenerateScormContentToken tạo token có hạn để tải nội dung package.
+// Token nằm trong path để các URL tương đối trong nội dung SCORM vẫn mang token.
+func GenerateScormContentToken(packageId uint, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%d.%d", packageId, expiresAt.Unix())
+	return fmt.Sprintf("%s.%s", payload, signScorm(payload))
+}
+
+// VerifyScormContentToken kiểm tra chữ ký, thời hạn và trả về package ID
+func VerifyScormContentToken(token string) (uint, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, errors.New("invalid content token")
+	}
+
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(signScorm(payload)), []byte(parts[2])) {
+		return 0, errors.New("invalid content signature")
+	}
+
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid content token")
+	}
+
+	if time.Now().Unix() > expires {
+		return 0, errors.New("content token expired")
+	}
+
+	packageId, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, errors.New("invalid content token")
+	}
+
+	return uint(packageId), nil
+}
+
+func signScorm(payload string) string {
+	mac := hmac.New(sha256.New, scormSigningSecret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+var (
+	// CMITimespan của SCORM 1.2: HHHH:MM:SS.SS
+	scorm12TimeRegex = regexp.MustCompile(`^(\d{2,4}):([0-5]\d):([0-5]\d)(\.\d{1,2})?$`)
+	// timeinterval của SCORM 2004 (ISO 8601 duration): P[yY][mM][dD][T[hH][mM][s[.s]S]]
+	scorm2004TimeRegex = regexp.MustCompile(`^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d{1,2})?)S)?)?$`)
+)
+
+// ParseScormDuration đổi session_time của SCO sang số giây
+func ParseScormDuration(version, value string) (float64, error) {
+	if version == ScormVersion12 {
+		match := scorm12TimeRegex.FindStringSubmatch(value)
+		if match == nil {
+			return 0, errors.New("must be a CMITimespan (HHHH:MM:SS.SS)")
+		}
+		hours, _ := strconv.Atoi(match[1])
+		minutes, _ := strconv.Atoi(match[2])
+		seconds, _ := strconv.ParseFloat(match[3]+match[4], 64)
+		return float64(hours*3600+minutes*60) + seconds, nil
+	}
+
+	match := scorm2004TimeRegex.FindStringSubmatch(value)
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, errors.New("must be an ISO 8601 duration (e.g. PT1H30M5S)")
+	}
+	// Năm/tháng được quy đổi gần đúng như khuyến nghị của SCORM 2004
+	units := []float64{365 * 86400, 30 * 86400, 86400, 3600, 60}
+	total := 0.0
+	for i, unit := range units {
+		if match[i+1] != "" {
+			n, _ := strconv.Atoi(match[i+1])
+			total += float64(n) * unit
+		}
+	}
+	if match[6] != "" {
+		seconds, _ := strconv.ParseFloat(match[6], 64)
+		total += seconds
+	}
+	return total, nil
+}
+
+// FormatScormDuration đổi số giây sang định dạng total_time của từng version
+func FormatScormDuration(version string, seconds float64) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	centis := int64(math.Round(seconds * 100))
+	hours := centis / 360000
+	minutes := centis / 6000 % 60
+	secs := float64(centis%6000) / 100
+
+	if version == ScormVersion12 {
+		return fmt.Sprintf("%04d:%02d:%05.2f", hours, minutes, secs)
+	}
+	return fmt.Sprintf("PT%dH%dM%gS", hours, minutes, secs)
+}
@@ -0,0 +1,200 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Dung lượng tối đa của một file subtitle
+const MaxSubtitleSize = 2 << 20
+
+// SubtitleCue là một đoạn phụ đề với thời gian tính bằng mili giây
+type SubtitleCue struct {
+	StartMs int
+	EndMs   int
+	Text    string
+}
+
+var (
+	vttTimingRegex = regexp.MustCompile(`^((?:\d{2,}:)?\d{2}:\d{2}\.\d{3})\s+-->\s+((?:\d{2,}:)?\d{2}:\d{2}\.\d{3})(\s.*)?$`)
+	srtTimingRegex = regexp.MustCompile(`^(\d{1,2}:\d{2}:\d{2}[,.]\d{3})\s+-->\s+(\d{1,2}:\d{2}:\d{2}[,.]\d{3})(\s.*)?$`)
+	cueTagRegex    = regexp.MustCompile(`<[^>]*>`)
+)
+
+// ParseVTT đọc và validate file WebVTT
+func ParseVTT(data []byte) ([]SubtitleCue, error) {
+	blocks := subtitleBlocks(data)
+	if len(blocks) == 0 || !strings.HasPrefix(blocks[0][0], "WEBVTT") {
+		return nil, errors.New("missing WEBVTT header")
+	}
+
+	var cues []SubtitleCue
+	for _, lines := range blocks[1:] {
+		// Bỏ qua các block không phải cue
+		if strings.HasPrefix(lines[0], "NOTE") || lines[0] == "STYLE" || lines[0] == "REGION" {
+			continue
+		}
+
+		// Cue identifier (không bắt buộc)
+		if !strings.Contains(lines[0], "-->") {
+			lines = lines[1:]
+		}
+		if len(lines) == 0 {
+			return nil, errors.New("cue without timing")
+		}
+
+		match := vttTimingRegex.FindStringSubmatch(lines[0])
+		if match == nil {
+			return nil, fmt.Errorf("invalid cue timing: %q", lines[0])
+		}
+
+		cue, err := newSubtitleCue(match[1], match[2], lines[1:])
+		if err != nil {
+			return nil, err
+		}
+		cues = append(cues, cue)
+	}
+
+	if len(cues) == 0 {
+		return nil, errors.New("subtitle has no cues")
+	}
+
+	return cues, nil
+}
+
+// ParseSRT đọc và validate file SubRip
+func ParseSRT(data []byte) ([]SubtitleCue, error) {
+	var cues []SubtitleCue
+	for _, lines := range subtitleBlocks(data) {
+		// Dòng đầu là số thứ tự của cue
+		if _, err := strconv.Atoi(lines[0]); err == nil {
+			lines = lines[1:]
+		}
+		if len(lines) == 0 {
+			return nil, errors.New("cue without timing")
+		}
+
+		match := srtTimingRegex.FindStringSubmatch(lines[0])
+		if match == nil {
+			return nil, fmt.Errorf("invalid cue timing: %q", lines[0])
+		}
+
+		cue, err := newSubtitleCue(match[1], match[2], lines[1:])
+		if err != nil {
+			return nil, err
+		}
+		cues = append(cues, cue)
+	}
+
+	if len(cues) == 0 {
+		return nil, errors.New("subtitle has no cues")
+	}
+
+	return cues, nil
+}
+
+// ConvertSRTToVTT chuyển file SRT sang WebVTT
+func ConvertSRTToVTT(data []byte) ([]byte, []SubtitleCue, error) {
+	cues, err := ParseSRT(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+	for i, cue := range cues {
+		fmt.Fprintf(&b, "\n%d\n%s --> %s\n%s\n", i+1, formatVTTTime(cue.StartMs), formatVTTTime(cue.EndMs), cue.Text)
+	}
+
+	return []byte(b.String()), cues, nil
+}
+
+// CueText bỏ tag định dạng (<i>, <v Speaker>, ...) để index transcript
+func CueText(text string) string {
+	text = cueTagRegex.ReplaceAllString(text, "")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// subtitleBlocks tách file thành các block (phân cách bằng dòng trống)
+func subtitleBlocks(data []byte) [][]string {
+	content := strings.TrimPrefix(string(data), "\uFEFF")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+
+	var blocks [][]string
+	var current []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if line == "" {
+			if len(current) > 0 {
+				blocks = append(blocks, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, current)
+	}
+
+	return blocks
+}
+
+func newSubtitleCue(start, end string, textLines []string) (SubtitleCue, error) {
+	startMs, err := parseSubtitleTime(start)
+	if err != nil {
+		return SubtitleCue{}, err
+	}
+	endMs, err := parseSubtitleTime(end)
+	if err != nil {
+		return SubtitleCue{}, err
+	}
+	if endMs <= startMs {
+		return SubtitleCue{}, fmt.Errorf("cue end time must be after start time: %s --> %s", start, end)
+	}
+
+	return SubtitleCue{
+		StartMs: startMs,
+		EndMs:   endMs,
+		Text:    strings.Join(textLines, "\n"),
+	}, nil
+}
+
+// parseSubtitleTime đọc thời gian dạng [hh:]mm:ss.ttt hoặc hh:mm:ss,ttt
+func parseSubtitleTime(value string) (int, error) {
+	value = strings.Replace(value, ",", ".", 1)
+	dot := strings.LastIndex(value, ".")
+	parts := strings.Split(value[:dot], ":")
+
+	millis, err := strconv.Atoi(value[dot+1:])
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp: %s", value)
+	}
+
+	total := 0
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, fmt.Errorf("invalid timestamp: %s", value)
+		}
+		total = total*60 + n
+	}
+
+	minutes, seconds := parts[len(parts)-2], parts[len(parts)-1]
+	if m, _ := strconv.Atoi(minutes); len(parts) > 2 && m > 59 {
+		return 0, fmt.Errorf("invalid timestamp: %s", value)
+	}
+	if s, _ := strconv.Atoi(seconds); s > 59 {
+		return 0, fmt.Errorf("invalid timestamp: %s", value)
+	}
+
+	return total*1000 + millis, nil
+}
+
+func formatVTTTime(ms int) string {
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
@@ -0,0 +1,19 @@
+package utils
+
+import "context"
+
+type tenantContextKey struct{}
+
+// WithTenant gắn tenant vào context; mọi query gorm chạy với context này bị giới hạn trong tenant đó
+func WithTenant(ctx context.Context, tenantId uint) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantId)
+}
+
+// TenantFromContext trả về tenant đã gắn vào context (nếu có)
+func TenantFromContext(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	tenantId, ok := ctx.Value(tenantContextKey{}).(uint)
+	return tenantId, ok && tenantId != 0
+}
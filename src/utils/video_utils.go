@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var allowVideoExts = map[string]bool{
+	".mp4":  true,
+	".mov":  true,
+	".mkv":  true,
+	".webm": true,
+	".avi":  true,
+}
+
+const (
+	maxVideoSize     = 4 << 30 // 4GB
+	minVideoChunk    = 1 << 20 // 1MB
+	maxVideoChunk    = 50 << 20
+	DefaultChunkSize = 10 << 20
+)
+
+var videoSigningSecret = []byte(GetEnv("VIDEO_SIGNING_SECRET", string(JWTSecret)))
+
+// VideoStorageDir thư mục làm việc cục bộ của worker khi transcode (chunk upload nằm trên storage dùng chung)
+func VideoStorageDir() string {
+	return GetEnv("VIDEO_STORAGE_DIR", "./storage/videos")
+}
+
+// ValidateVideoUpload kiểm tra extension, dung lượng và chunk size trước khi bắt đầu upload
+func ValidateVideoUpload(fileName string, fileSize, chunkSize int64) error {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if !allowVideoExts[ext] {
+		return errors.New("unsupported video extension")
+	}
+
+	if fileSize <= 0 {
+		return errors.New("invalid video size")
+	}
+
+	if fileSize > maxVideoSize {
+		return errors.New("video too large (max 4GB)")
+	}
+
+	if chunkSize < minVideoChunk || chunkSize > maxVideoChunk {
+		return errors.New("chunk size must be between 1MB and 50MB")
+	}
+
+	return nil
+}
+
+// GeneratePlaybackToken tạo token có hạn dùng để stream HLS của lesson.
+// Token nằm trong path (không phải query) để các segment tương đối trong playlist vẫn mang token.
+func GeneratePlaybackToken(lessonId uint, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%d.%d", lessonId, expiresAt.Unix())
+	return fmt.Sprintf("%s.%s", payload, signPlayback(payload))
+}
+
+// VerifyPlaybackToken kiểm tra chữ ký, thời hạn và trả về lesson ID
+func VerifyPlaybackToken(token string) (uint, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, errors.New("invalid playback token")
+	}
+
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(signPlayback(payload)), []byte(parts[2])) {
+		return 0, errors.New("invalid playback signature")
+	}
+
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid playback token")
+	}
+
+	if time.Now().Unix() > expires {
+		return 0, errors.New("playback token expired")
+	}
+
+	lessonId, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, errors.New("invalid playback token")
+	}
+
+	return uint(lessonId), nil
+}
+
+func signPlayback(payload string) string {
+	mac := hmac.New(sha256.New, videoSigningSecret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SignWebhookPayload tạo header chữ ký dạng "t=<timestamp>,v1=<hex>".
+// Chữ ký là HMAC-SHA256 của "<timestamp>.<body>" với secret của endpoint;
+// bên nhận so sánh chữ ký và từ chối timestamp quá cũ để chống replay.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%d.", timestamp)))
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// IsWebhookURL chỉ chấp nhận URL tuyệt đối http(s) có host
+func IsWebhookURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Scheme == "http" || u.Scheme == "https"
+}